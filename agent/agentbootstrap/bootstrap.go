@@ -26,6 +26,7 @@ import (
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/model"
 	corenetwork "github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/secrets"
 	"github.com/juju/juju/environs"
 	environscloudspec "github.com/juju/juju/environs/cloudspec"
 	"github.com/juju/juju/environs/config"
@@ -121,6 +122,12 @@ func InitializeState(
 		return nil, errors.Trace(err)
 	}
 
+	// The secrets keys are generated here, on the bootstrap machine,
+	// and sent to other controller machines as they're added.
+	if err := secrets.EnsureKeys(secrets.KeysDir(c.DataDir())); err != nil {
+		return nil, errors.Annotate(err, "creating secrets keys")
+	}
+
 	logger.Debugf("initializing address %v", info.Addrs)
 
 	isCAAS := cloud.CloudIsCAAS(args.ControllerCloud)
@@ -150,6 +157,7 @@ func InitializeState(
 		MongoSession:              session,
 		AdminPassword:             info.Password,
 		NewPolicy:                 newPolicy,
+		DataDir:                   c.DataDir(),
 	})
 	if err != nil {
 		return nil, errors.Errorf("failed to initialize state: %v", err)
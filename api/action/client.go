@@ -48,6 +48,17 @@ func (c *Client) ListOperations(arg params.OperationQueryArgs) (params.Operation
 	return results, err
 }
 
+// ExportOperations fetches the operations which completed in a time
+// window, with all of the details of their tasks.
+func (c *Client) ExportOperations(arg params.OperationExportArgs) (params.OperationResults, error) {
+	results := params.OperationResults{}
+	if v := c.BestAPIVersion(); v < 10 {
+		return results, errors.Errorf("ExportOperations not supported by this version (%d) of Juju", v)
+	}
+	err := c.facade.FacadeCall("ExportOperations", arg, &results)
+	return results, err
+}
+
 // Operation fetches the operation with the specified id.
 func (c *Client) Operation(id string) (params.OperationResult, error) {
 	if v := c.BestAPIVersion(); v < 6 {
@@ -88,10 +99,51 @@ func (c *Client) EnqueueOperation(arg params.Actions) (params.EnqueuedActions, e
 	if v := c.BestAPIVersion(); v < 6 {
 		return results, errors.Errorf("EnqueueOperation not supported by this version (%d) of Juju", v)
 	}
+	if err := c.checkRollout(arg.Rollout); err != nil {
+		return results, errors.Trace(err)
+	}
+	if err := c.checkSchedule(arg.Schedule); err != nil {
+		return results, errors.Trace(err)
+	}
 	err := c.facade.FacadeCall("EnqueueOperation", arg, &results)
 	return results, err
 }
 
+// checkRollout returns an error if a rollout is requested
+// but the controller cannot run rolling operations.
+func (c *Client) checkRollout(rollout *params.RolloutParams) error {
+	if v := c.BestAPIVersion(); rollout != nil && v < 8 {
+		return errors.Errorf("rolling operations not supported by this version (%d) of Juju", v)
+	}
+	return nil
+}
+
+// checkSchedule returns an error if a schedule is requested
+// but the controller cannot schedule actions.
+func (c *Client) checkSchedule(schedule *params.ScheduleParams) error {
+	if v := c.BestAPIVersion(); schedule != nil && v < 9 {
+		return errors.Errorf("scheduled operations not supported by this version (%d) of Juju", v)
+	}
+	return nil
+}
+
+// ResumeOperation restarts a rolling operation which was halted
+// after too many of its tasks failed.
+func (c *Client) ResumeOperation(id string) error {
+	if v := c.BestAPIVersion(); v < 8 {
+		return errors.Errorf("ResumeOperation not supported by this version (%d) of Juju", v)
+	}
+	arg := params.Entities{
+		Entities: []params.Entity{{names.NewOperationTag(id).String()}},
+	}
+	var results params.ErrorResults
+	err := c.facade.FacadeCall("ResumeOperations", arg, &results)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
 // Cancel attempts to cancel a queued up Action from running.
 func (c *Client) Cancel(arg params.Entities) (params.ActionResults, error) {
 	results := params.ActionResults{}
@@ -5,6 +5,7 @@ package action_test
 
 import (
 	"errors"
+	"time"
 
 	"github.com/juju/names/v4"
 	jc "github.com/juju/testing/checkers"
@@ -263,6 +264,57 @@ func (s *actionSuite) TestListOperationsNotSupported(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "ListOperations not supported by this version \\(4\\) of Juju")
 }
 
+func (s *actionSuite) TestExportOperations(c *gc.C) {
+	from := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	args := params.OperationExportArgs{From: &from, Archived: true}
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				c.Assert(request, gc.Equals, "ExportOperations")
+				c.Assert(a, jc.DeepEquals, args)
+				c.Assert(result, gc.FitsTypeOf, &params.OperationResults{})
+				*(result.(*params.OperationResults)) = params.OperationResults{
+					Results: []params.OperationResult{{
+						Summary: "hello",
+					}},
+				}
+				return nil
+			},
+		),
+		BestVersion: 10,
+	}
+	client := action.NewClient(apiCaller)
+	result, err := client.ExportOperations(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.OperationResults{
+		Results: []params.OperationResult{{
+			Summary: "hello",
+		}},
+	})
+}
+
+func (s *actionSuite) TestExportOperationsNotSupported(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				return nil
+			},
+		),
+		BestVersion: 9,
+	}
+	client := action.NewClient(apiCaller)
+	_, err := client.ExportOperations(params.OperationExportArgs{})
+	c.Assert(err, gc.ErrorMatches, "ExportOperations not supported by this version \\(9\\) of Juju")
+}
+
 func (s *actionSuite) TestOperation(c *gc.C) {
 	apiCaller := basetesting.BestVersionCaller{
 		APICallerFunc: basetesting.APICallerFunc(
@@ -369,3 +421,94 @@ func (s *actionSuite) TestEnqueueOperationNotSupported(c *gc.C) {
 	_, err := client.EnqueueOperation(params.Actions{})
 	c.Assert(err, gc.ErrorMatches, "EnqueueOperation not supported by this version \\(5\\) of Juju")
 }
+
+func (s *actionSuite) TestEnqueueOperationRolloutNotSupported(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				return nil
+			},
+		),
+		BestVersion: 7,
+	}
+	client := action.NewClient(apiCaller)
+	_, err := client.EnqueueOperation(params.Actions{Rollout: &params.RolloutParams{BatchSize: 1}})
+	c.Assert(err, gc.ErrorMatches, "rolling operations not supported by this version \\(7\\) of Juju")
+	_, err = client.RunOnAllMachinesWithParams(params.RunParams{
+		Commands: "hostname",
+		Timeout:  time.Minute,
+		Rollout:  &params.RolloutParams{BatchSize: 1},
+	})
+	c.Assert(err, gc.ErrorMatches, "rolling operations not supported by this version \\(7\\) of Juju")
+}
+
+func (s *actionSuite) TestScheduleNotSupported(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				return nil
+			},
+		),
+		BestVersion: 8,
+	}
+	client := action.NewClient(apiCaller)
+	schedule := &params.ScheduleParams{Recurrence: "@daily"}
+	_, err := client.EnqueueOperation(params.Actions{Schedule: schedule})
+	c.Assert(err, gc.ErrorMatches, "scheduled operations not supported by this version \\(8\\) of Juju")
+	_, err = client.Run(params.RunParams{Commands: "hostname", Schedule: schedule})
+	c.Assert(err, gc.ErrorMatches, "scheduled operations not supported by this version \\(8\\) of Juju")
+	_, err = client.RunOnAllMachinesWithParams(params.RunParams{Commands: "hostname", Schedule: schedule})
+	c.Assert(err, gc.ErrorMatches, "scheduled operations not supported by this version \\(8\\) of Juju")
+}
+
+func (s *actionSuite) TestResumeOperation(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				c.Assert(request, gc.Equals, "ResumeOperations")
+				c.Assert(a, jc.DeepEquals, params.Entities{Entities: []params.Entity{{Tag: "operation-666"}}})
+				c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+				*(result.(*params.ErrorResults)) = params.ErrorResults{
+					Results: []params.ErrorResult{{
+						Error: &params.Error{Message: "FAIL"},
+					}},
+				}
+				return nil
+			},
+		),
+		BestVersion: 8,
+	}
+	client := action.NewClient(apiCaller)
+	err := client.ResumeOperation("666")
+	c.Assert(err, gc.ErrorMatches, "FAIL")
+}
+
+func (s *actionSuite) TestResumeOperationNotSupported(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				return nil
+			},
+		),
+		BestVersion: 7,
+	}
+	client := action.NewClient(apiCaller)
+	err := client.ResumeOperation("666")
+	c.Assert(err, gc.ErrorMatches, "ResumeOperation not supported by this version \\(7\\) of Juju")
+}
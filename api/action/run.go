@@ -6,14 +6,34 @@ package action
 import (
 	"time"
 
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/apiserver/params"
 )
 
 // RunOnAllMachines runs the command on all the machines with the specified
 // timeout.
 func (c *Client) RunOnAllMachines(commands string, timeout time.Duration) (params.EnqueuedActions, error) {
+	return c.RunOnAllMachinesWithParams(params.RunParams{Commands: commands, Timeout: timeout})
+}
+
+// RunOnAllMachinesWithParams runs the commands on all the machines. Any
+// targets in the params are ignored; the timeout, rollout and schedule
+// are honoured.
+func (c *Client) RunOnAllMachinesWithParams(run params.RunParams) (params.EnqueuedActions, error) {
 	var results params.EnqueuedActions
-	args := params.RunParams{Commands: commands, Timeout: timeout}
+	if err := c.checkRollout(run.Rollout); err != nil {
+		return results, errors.Trace(err)
+	}
+	if err := c.checkSchedule(run.Schedule); err != nil {
+		return results, errors.Trace(err)
+	}
+	args := params.RunParams{
+		Commands: run.Commands,
+		Timeout:  run.Timeout,
+		Rollout:  run.Rollout,
+		Schedule: run.Schedule,
+	}
 	err := c.facade.FacadeCall("RunOnAllMachines", args, &results)
 	return results, err
 }
@@ -22,6 +42,12 @@ func (c *Client) RunOnAllMachines(commands string, timeout time.Duration) (param
 // provided in the machines, applications and units slices.
 func (c *Client) Run(run params.RunParams) (params.EnqueuedActions, error) {
 	var results params.EnqueuedActions
+	if err := c.checkRollout(run.Rollout); err != nil {
+		return results, errors.Trace(err)
+	}
+	if err := c.checkSchedule(run.Schedule); err != nil {
+		return results, errors.Trace(err)
+	}
 	err := c.facade.FacadeCall("Run", run, &results)
 	return results, err
 }
@@ -0,0 +1,24 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionschedules
+
+import (
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/common"
+)
+
+const actionSchedulesFacade = "ActionSchedules"
+
+// API provides access to the ActionSchedules API facade.
+type API struct {
+	*common.DueWorkRunner
+}
+
+// NewAPI creates a new client-side ActionSchedules facade.
+func NewAPI(caller base.APICaller) *API {
+	facadeCaller := base.NewFacadeCaller(caller, actionSchedulesFacade)
+	return &API{
+		DueWorkRunner: common.NewDueWorkRunner(facadeCaller),
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionschedules_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/actionschedules"
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+	coretesting "github.com/juju/juju/testing"
+)
+
+// ActionSchedulesSuite tests that the client calls the ActionSchedules facade. Its
+// methods are those of common.DueWorkRunner, which are tested there.
+type ActionSchedulesSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&ActionSchedulesSuite{})
+
+func (s *ActionSchedulesSuite) TestRunDueWork(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "ActionSchedules")
+		c.Check(request, gc.Equals, "RunDueWork")
+		c.Assert(result, gc.FitsTypeOf, &params.DueWorkResult{})
+		return nil
+	})
+	api := actionschedules.NewAPI(apiCaller)
+	result, err := api.RunDueWork()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.IsZero(), jc.IsTrue)
+}
@@ -79,6 +79,21 @@ func (st *State) StateServingInfo() (controller.StateServingInfo, error) {
 	}, nil
 }
 
+// ControllerSecretKeys returns the keys, by name, that the controller's
+// secret values are encrypted with. It returns an error satisfying
+// errors.IsNotSupported if the controller is too old to send them.
+func (st *State) ControllerSecretKeys() (map[string][]byte, error) {
+	if st.facade.BestAPIVersion() < 3 {
+		return nil, errors.NotSupportedf("controller secret keys")
+	}
+	var result params.SecretKeysResult
+	err := st.facade.FacadeCall("ControllerSecretKeys", nil, &result)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Keys, nil
+}
+
 // IsMaster reports whether the connected machine
 // agent lives at the same network address as the primary
 // mongo server for the replica set.
@@ -23,6 +23,7 @@ import (
 	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/core/devices"
+	"github.com/juju/juju/core/hooktrace"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/network/firewall"
 	"github.com/juju/juju/storage"
@@ -441,6 +442,42 @@ func (c *Client) SetCharm(branchName string, cfg SetCharmConfig) error {
 	return c.facade.FacadeCall("SetCharm", args, nil)
 }
 
+// CharmRefreshReportConfig describes the charm refresh to report on.
+type CharmRefreshReportConfig struct {
+	// ApplicationName is the name of the application to report on.
+	ApplicationName string
+
+	// CharmURL is the url of the charm the application would be
+	// refreshed to. It must already be added to the model.
+	CharmURL *charm.URL
+
+	// ForceSeries reports whether the refresh would be forced onto
+	// a series not supported by the new charm.
+	ForceSeries bool
+
+	// ResourceUploads holds the names of the resources that would be
+	// uploaded during the refresh.
+	ResourceUploads []string
+}
+
+// CharmRefreshReport reports what refreshing an application to a new
+// charm would change, without changing anything.
+func (c *Client) CharmRefreshReport(branchName string, cfg CharmRefreshReportConfig) (params.CharmRefreshReport, error) {
+	if apiVersion := c.BestAPIVersion(); apiVersion < 14 {
+		return params.CharmRefreshReport{}, errors.NotSupportedf("CharmRefreshReport for Application facade v%v", apiVersion)
+	}
+	args := params.CharmRefreshReportArgs{
+		ApplicationName: cfg.ApplicationName,
+		Generation:      branchName,
+		CharmURL:        cfg.CharmURL.String(),
+		ForceSeries:     cfg.ForceSeries,
+		ResourceUploads: cfg.ResourceUploads,
+	}
+	var result params.CharmRefreshReport
+	err := c.facade.FacadeCall("CharmRefreshReport", args, &result)
+	return result, errors.Trace(err)
+}
+
 // Update updates the application attributes, including charm URL,
 // minimum number of units, settings and constraints.
 func (c *Client) Update(args params.ApplicationUpdate) error {
@@ -1182,6 +1219,7 @@ type UnitInfo struct {
 	Charm           string
 	Leader          bool
 	RelationData    []EndpointRelationData
+	HookTraces      []hooktrace.Trace
 
 	// The following are for CAAS models.
 	ProviderId string
@@ -1274,5 +1312,26 @@ func unitInfoFromParams(in params.UnitInfoResult) UnitInfo {
 		}
 		info.RelationData = append(info.RelationData, erd)
 	}
+	for _, inTrace := range in.Result.HookTraces {
+		trace := hooktrace.Trace{
+			Operation:        inTrace.Operation,
+			Started:          inTrace.Started,
+			LockWait:         inTrace.LockWait,
+			Duration:         inTrace.Duration,
+			Flush:            inTrace.Flush,
+			DroppedToolCalls: inTrace.DroppedToolCalls,
+			Error:            inTrace.Error,
+		}
+		for _, inCall := range inTrace.ToolCalls {
+			trace.ToolCalls = append(trace.ToolCalls, hooktrace.ToolCall{
+				Name:     inCall.Name,
+				Args:     inCall.Args,
+				Started:  inCall.Started,
+				Duration: inCall.Duration,
+				Failed:   inCall.Failed,
+			})
+		}
+		info.HookTraces = append(info.HookTraces, trace)
+	}
 	return info
 }
@@ -37,7 +37,7 @@ type applicationSuite struct {
 var _ = gc.Suite(&applicationSuite{})
 
 func newClient(f basetesting.APICallerFunc) *application.Client {
-	return newClientWithVersion(f, 13)
+	return newClientWithVersion(f, 14)
 }
 
 func newClientWithVersion(f basetesting.APICallerFunc, version int) *application.Client {
@@ -1947,3 +1947,53 @@ func (s *applicationSuite) TestUnexposeVersionChecks(c *gc.C) {
 		}
 	}
 }
+
+func (s *applicationSuite) TestCharmRefreshReport(c *gc.C) {
+	called := false
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		called = true
+		c.Assert(request, gc.Equals, "CharmRefreshReport")
+		c.Assert(a, jc.DeepEquals, params.CharmRefreshReportArgs{
+			ApplicationName: "foo",
+			Generation:      "new-branch",
+			CharmURL:        "cs:foo-2",
+			ForceSeries:     true,
+			ResourceUploads: []string{"image"},
+		})
+		result, ok := response.(*params.CharmRefreshReport)
+		c.Assert(ok, jc.IsTrue)
+		*result = params.CharmRefreshReport{
+			ApplicationName: "foo",
+			CharmURL:        "cs:foo-1",
+			NewCharmURL:     "cs:foo-2",
+			Blockers:        []string{`would break relation "foo:db bar:db"`},
+		}
+		return nil
+	})
+	report, err := client.CharmRefreshReport("new-branch", application.CharmRefreshReportConfig{
+		ApplicationName: "foo",
+		CharmURL:        charm.MustParseURL("cs:foo-2"),
+		ForceSeries:     true,
+		ResourceUploads: []string{"image"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(report, jc.DeepEquals, params.CharmRefreshReport{
+		ApplicationName: "foo",
+		CharmURL:        "cs:foo-1",
+		NewCharmURL:     "cs:foo-2",
+		Blockers:        []string{`would break relation "foo:db bar:db"`},
+	})
+}
+
+func (s *applicationSuite) TestCharmRefreshReportNotSupported(c *gc.C) {
+	client := newClientWithVersion(func(objType string, version int, id, request string, a, response interface{}) error {
+		c.Fail()
+		return nil
+	}, 13)
+	_, err := client.CharmRefreshReport("", application.CharmRefreshReportConfig{
+		ApplicationName: "foo",
+		CharmURL:        charm.MustParseURL("cs:foo-2"),
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -0,0 +1,24 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Contents describes what the identified backup archive holds,
+// without restoring it.
+func (c *Client) Contents(id string) (*params.BackupsContentsResult, error) {
+	if c.BestAPIVersion() < 5 {
+		return nil, errors.NotSupportedf("showing backup contents with this version of the controller")
+	}
+	var result params.BackupsContentsResult
+	args := params.BackupsContentsArgs{ID: id}
+	if err := c.facade.FacadeCall("Contents", args, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/backups"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type contentsSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&contentsSuite{})
+
+func (s *contentsSuite) TestContents(c *gc.C) {
+	cleanup := backups.PatchClientFacadeCall(s.client,
+		func(req string, paramsIn interface{}, resp interface{}) error {
+			c.Check(req, gc.Equals, "Contents")
+			c.Assert(paramsIn, gc.FitsTypeOf, params.BackupsContentsArgs{})
+			c.Check(paramsIn.(params.BackupsContentsArgs).ID, gc.Equals, "spam")
+			result := resp.(*params.BackupsContentsResult)
+			result.ControllerUUID = "controller-uuid"
+			return nil
+		},
+	)
+	defer cleanup()
+
+	result, err := s.client.Contents("spam")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.ControllerUUID, gc.Equals, "controller-uuid")
+}
+
+func (s *contentsSuite) TestContentsError(c *gc.C) {
+	cleanup := backups.PatchClientFacadeCall(s.client,
+		func(req string, paramsIn interface{}, resp interface{}) error {
+			return errors.New("failed!")
+		},
+	)
+	defer cleanup()
+
+	_, err := s.client.Contents("spam")
+	c.Assert(err, gc.ErrorMatches, "failed!")
+}
@@ -0,0 +1,22 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Restore requests that the controller restore the identified backup
+// over its current state. The controller agent restarts shortly after
+// the restore completes, dropping this connection.
+func (c *Client) Restore(id string) error {
+	if c.BestAPIVersion() < 4 {
+		return errors.NotSupportedf("restoring backups with this version of the controller")
+	}
+	args := params.BackupsRestoreArgs{ID: id}
+	err := c.facade.FacadeCall("Restore", args, nil)
+	return errors.Trace(err)
+}
@@ -0,0 +1,46 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/backups"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type restoreSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&restoreSuite{})
+
+func (s *restoreSuite) TestRestore(c *gc.C) {
+	cleanup := backups.PatchClientFacadeCall(s.client,
+		func(req string, paramsIn interface{}, resp interface{}) error {
+			c.Check(req, gc.Equals, "Restore")
+			c.Assert(paramsIn, gc.FitsTypeOf, params.BackupsRestoreArgs{})
+			c.Check(paramsIn.(params.BackupsRestoreArgs).ID, gc.Equals, "spam")
+			return nil
+		},
+	)
+	defer cleanup()
+
+	err := s.client.Restore("spam")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *restoreSuite) TestRestoreError(c *gc.C) {
+	cleanup := backups.PatchClientFacadeCall(s.client,
+		func(req string, paramsIn interface{}, resp interface{}) error {
+			return errors.New("failed!")
+		},
+	)
+	defer cleanup()
+
+	err := s.client.Restore("spam")
+	c.Assert(err, gc.ErrorMatches, "failed!")
+}
@@ -0,0 +1,52 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package bundledrift provides access to the BundleDrift API facade,
+// which sets the bundle a model is expected to match, and reports how
+// the model has drifted from it.
+package bundledrift
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides methods for accessing the desired bundle of a model.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new `Client` based on an existing authenticated API
+// connection.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "BundleDrift")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// SetDesiredBundle sets the bundle, with overlays applied in order, that
+// the model is expected to match.
+func (c *Client) SetDesiredBundle(bundle string, overlays []string) error {
+	args := params.SetDesiredBundleArgs{
+		Bundle:   bundle,
+		Overlays: overlays,
+	}
+	return errors.Trace(c.facade.FacadeCall("SetDesiredBundle", args, nil))
+}
+
+// RemoveDesiredBundle removes the bundle the model is expected to match.
+func (c *Client) RemoveDesiredBundle() error {
+	return errors.Trace(c.facade.FacadeCall("RemoveDesiredBundle", nil, nil))
+}
+
+// BundleDrift returns the bundle the model is expected to match, and how
+// the model had drifted from it when it was last compared.
+func (c *Client) BundleDrift() (params.BundleDriftResult, error) {
+	var result params.BundleDriftResult
+	if err := c.facade.FacadeCall("BundleDrift", nil, &result); err != nil {
+		return params.BundleDriftResult{}, errors.Trace(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundledrift_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/bundledrift"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type bundleDriftSuite struct {
+	gitjujutesting.IsolationSuite
+}
+
+var _ = gc.Suite(&bundleDriftSuite{})
+
+func (s *bundleDriftSuite) TestSetDesiredBundle(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "BundleDrift")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "SetDesiredBundle")
+			c.Check(a, jc.DeepEquals, params.SetDesiredBundleArgs{
+				Bundle:   "bundle",
+				Overlays: []string{"overlay"},
+			})
+			c.Check(result, gc.IsNil)
+			return nil
+		},
+	)
+	client := bundledrift.NewClient(apiCaller)
+	err := client.SetDesiredBundle("bundle", []string{"overlay"})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *bundleDriftSuite) TestRemoveDesiredBundle(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "BundleDrift")
+			c.Check(request, gc.Equals, "RemoveDesiredBundle")
+			c.Check(a, gc.IsNil)
+			return errors.New("boom")
+		},
+	)
+	client := bundledrift.NewClient(apiCaller)
+	err := client.RemoveDesiredBundle()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *bundleDriftSuite) TestBundleDrift(c *gc.C) {
+	checked := time.Date(2021, 5, 6, 7, 5, 0, 0, time.UTC)
+	expected := params.BundleDriftResult{
+		Bundle:  "bundle",
+		SetBy:   "bob",
+		Set:     time.Date(2021, 5, 6, 7, 0, 0, 0, time.UTC),
+		Checked: &checked,
+		Diff:    "applications: {}\n",
+	}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "BundleDrift")
+			c.Check(request, gc.Equals, "BundleDrift")
+			c.Check(a, gc.IsNil)
+			c.Assert(result, gc.FitsTypeOf, &params.BundleDriftResult{})
+			*(result.(*params.BundleDriftResult)) = expected
+			return nil
+		},
+	)
+	client := bundledrift.NewClient(apiCaller)
+	result, err := client.BundleDrift()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, expected)
+}
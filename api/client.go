@@ -113,6 +113,17 @@ func (c *Client) StatusHistory(kind status.HistoryKind, tag names.Tag, filter st
 	return history, nil
 }
 
+// ModelStatusHistory returns the changes in status of all of the units,
+// machines and applications in the model in a time window, oldest first.
+func (c *Client) ModelStatusHistory(args params.ModelStatusHistoryArgs) (params.ModelStatusHistoryResult, error) {
+	var result params.ModelStatusHistoryResult
+	if v := c.facade.BestAPIVersion(); v < 3 {
+		return result, errors.Errorf("ModelStatusHistory not supported by this version (%d) of Juju", v)
+	}
+	err := c.facade.FacadeCall("ModelStatusHistory", args, &result)
+	return result, errors.Trace(err)
+}
+
 // Resolved clears errors on a unit.
 func (c *Client) Resolved(unit string, retry bool) error {
 	p := params.Resolved{
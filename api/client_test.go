@@ -629,16 +629,20 @@ func (s *clientSuite) TestWatchDebugLogParamsEncoded(c *gc.C) {
 	s.PatchValue(api.WebsocketDial, catcher.recordLocation)
 
 	params := common.DebugLogParams{
-		IncludeEntity: []string{"a", "b"},
-		IncludeModule: []string{"c", "d"},
-		ExcludeEntity: []string{"e", "f"},
-		ExcludeModule: []string{"g", "h"},
-		Limit:         100,
-		Backlog:       200,
-		Level:         loggo.ERROR,
-		Replay:        true,
-		NoTail:        true,
-		StartTime:     time.Date(2016, 11, 30, 11, 48, 0, 100, time.UTC),
+		IncludeEntity:   []string{"a", "b"},
+		IncludeModule:   []string{"c", "d"},
+		ExcludeEntity:   []string{"e", "f"},
+		ExcludeModule:   []string{"g", "h"},
+		Limit:           100,
+		Backlog:         200,
+		Level:           loggo.ERROR,
+		Replay:          true,
+		NoTail:          true,
+		StartTime:       time.Date(2016, 11, 30, 11, 48, 0, 100, time.UTC),
+		EndTime:         time.Date(2016, 11, 30, 12, 48, 0, 0, time.UTC),
+		IncludeLocation: []string{"i", "j"},
+		ExcludeLocation: []string{"k"},
+		IncludeMessage:  "^l.*m$",
 	}
 
 	client := s.APIState.Client()
@@ -650,16 +654,20 @@ func (s *clientSuite) TestWatchDebugLogParamsEncoded(c *gc.C) {
 
 	values := connectURL.Query()
 	c.Assert(values, jc.DeepEquals, url.Values{
-		"includeEntity": params.IncludeEntity,
-		"includeModule": params.IncludeModule,
-		"excludeEntity": params.ExcludeEntity,
-		"excludeModule": params.ExcludeModule,
-		"maxLines":      {"100"},
-		"backlog":       {"200"},
-		"level":         {"ERROR"},
-		"replay":        {"true"},
-		"noTail":        {"true"},
-		"startTime":     {"2016-11-30T11:48:00.0000001Z"},
+		"includeEntity":   params.IncludeEntity,
+		"includeModule":   params.IncludeModule,
+		"excludeEntity":   params.ExcludeEntity,
+		"excludeModule":   params.ExcludeModule,
+		"maxLines":        {"100"},
+		"backlog":         {"200"},
+		"level":           {"ERROR"},
+		"replay":          {"true"},
+		"noTail":          {"true"},
+		"startTime":       {"2016-11-30T11:48:00.0000001Z"},
+		"endTime":         {"2016-11-30T12:48:00Z"},
+		"includeLocation": params.IncludeLocation,
+		"excludeLocation": params.ExcludeLocation,
+		"includeMessage":  {"^l.*m$"},
 	})
 }
 
@@ -862,3 +870,33 @@ func (s *IsolatedClientSuite) TestFindAllErrorsOnOlderController(c *gc.C) {
 	_, err := client.FindTools(0, 0, "", "", "proposed")
 	c.Assert(err, gc.ErrorMatches, "passing agent-stream not supported by the controller")
 }
+
+func (s *IsolatedClientSuite) TestModelStatusHistory(c *gc.C) {
+	from := time.Date(2021, 6, 1, 2, 0, 0, 0, time.UTC)
+	args := params.ModelStatusHistoryArgs{From: &from, Statuses: []string{"error"}}
+	apiCaller := apitesting.BestVersionCaller{
+		APICallerFunc: apitesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, result interface{}) error {
+				c.Check(objType, gc.Equals, "Client")
+				c.Check(request, gc.Equals, "ModelStatusHistory")
+				c.Check(a, jc.DeepEquals, args)
+				*(result.(*params.ModelStatusHistoryResult)) = params.ModelStatusHistoryResult{
+					Transitions: []params.StatusTransition{{Tag: "unit-mysql-0", Status: "error"}},
+				}
+				return nil
+			},
+		),
+		BestVersion: 3,
+	}
+	client := api.APIClient(apiCaller)
+	result, err := client.ModelStatusHistory(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Transitions, jc.DeepEquals, []params.StatusTransition{{Tag: "unit-mysql-0", Status: "error"}})
+}
+
+func (s *IsolatedClientSuite) TestModelStatusHistoryNotSupported(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{BestVersion: 2}
+	client := api.APIClient(apiCaller)
+	_, err := client.ModelStatusHistory(params.ModelStatusHistoryArgs{})
+	c.Assert(err, gc.ErrorMatches, `ModelStatusHistory not supported by this version \(2\) of Juju`)
+}
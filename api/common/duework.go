@@ -0,0 +1,54 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"time"
+
+	"github.com/juju/juju/api/base"
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/watcher"
+)
+
+// DueWorkRunner provides common client-side API functions to call
+// into apiserver.common.DueWorkRunner.
+type DueWorkRunner struct {
+	facade base.FacadeCaller
+}
+
+// NewDueWorkRunner returns a new DueWorkRunner that makes API calls
+// using the given facade caller.
+func NewDueWorkRunner(facade base.FacadeCaller) *DueWorkRunner {
+	return &DueWorkRunner{
+		facade: facade,
+	}
+}
+
+// RunDueWork calls the server-side RunDueWork method. It returns when
+// more work will be due, or the zero time if none is waiting.
+func (r *DueWorkRunner) RunDueWork() (time.Time, error) {
+	var result params.DueWorkResult
+	if err := r.facade.FacadeCall("RunDueWork", nil, &result); err != nil {
+		return time.Time{}, err
+	}
+	if result.Next == nil {
+		return time.Time{}, nil
+	}
+	return *result.Next, nil
+}
+
+// WatchDueWork calls the server-side WatchDueWork method.
+func (r *DueWorkRunner) WatchDueWork() (watcher.NotifyWatcher, error) {
+	var result params.NotifyWatchResult
+	err := r.facade.FacadeCall("WatchDueWork", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.Error; err != nil {
+		return nil, result.Error
+	}
+	w := apiwatcher.NewNotifyWatcher(r.facade.RawAPICaller(), result)
+	return w, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base"
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type dueWorkRunnerSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&dueWorkRunnerSuite{})
+
+func newDueWorkRunner(apiCaller base.APICaller) *common.DueWorkRunner {
+	return common.NewDueWorkRunner(base.NewFacadeCaller(apiCaller, "Work"))
+}
+
+func (s *dueWorkRunnerSuite) TestRunDueWork(c *gc.C) {
+	next := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Work")
+		c.Check(request, gc.Equals, "RunDueWork")
+		c.Check(arg, gc.IsNil)
+		c.Assert(result, gc.FitsTypeOf, &params.DueWorkResult{})
+		*(result.(*params.DueWorkResult)) = params.DueWorkResult{Next: &next}
+		return nil
+	})
+	result, err := newDueWorkRunner(apiCaller).RunDueWork()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.Equals, next)
+}
+
+func (s *dueWorkRunnerSuite) TestRunDueWorkNoneWaiting(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return nil
+	})
+	result, err := newDueWorkRunner(apiCaller).RunDueWork()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.IsZero(), jc.IsTrue)
+}
+
+func (s *dueWorkRunnerSuite) TestRunDueWorkError(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("boom")
+	})
+	_, err := newDueWorkRunner(apiCaller).RunDueWork()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *dueWorkRunnerSuite) TestWatchDueWorkError(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "Work")
+		c.Check(request, gc.Equals, "WatchDueWork")
+		c.Assert(result, gc.FitsTypeOf, &params.NotifyWatchResult{})
+		*(result.(*params.NotifyWatchResult)) = params.NotifyWatchResult{
+			Error: &params.Error{Message: "FAIL"},
+		}
+		return nil
+	})
+	w, err := newDueWorkRunner(apiCaller).WatchDueWork()
+	c.Assert(err, gc.ErrorMatches, "FAIL")
+	c.Assert(w, gc.IsNil)
+}
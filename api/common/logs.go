@@ -53,14 +53,30 @@ type DebugLogParams struct {
 	// StartTime should be a time in the past - only records with a
 	// log time on or after StartTime will be returned.
 	StartTime time.Time
+	// EndTime, if set, means only records with a log time before EndTime
+	// will be returned. Once EndTime has passed the server stops sending
+	// new records, even if NoTail is false.
+	EndTime time.Time
+	// IncludeLocation lists source locations to include in the response.
+	// Locations are of the form "file.go:123"; if the line number is
+	// omitted, all lines in the file match.
+	IncludeLocation []string
+	// ExcludeLocation lists source locations to exclude from the
+	// response, in the same form as IncludeLocation.
+	ExcludeLocation []string
+	// IncludeMessage is a regular expression; if set, only records with
+	// a message matching it will be returned.
+	IncludeMessage string
 }
 
 func (args DebugLogParams) URLQuery() url.Values {
 	attrs := url.Values{
-		"includeEntity": args.IncludeEntity,
-		"includeModule": args.IncludeModule,
-		"excludeEntity": args.ExcludeEntity,
-		"excludeModule": args.ExcludeModule,
+		"includeEntity":   args.IncludeEntity,
+		"includeModule":   args.IncludeModule,
+		"excludeEntity":   args.ExcludeEntity,
+		"excludeModule":   args.ExcludeModule,
+		"includeLocation": args.IncludeLocation,
+		"excludeLocation": args.ExcludeLocation,
 	}
 	if args.Replay {
 		attrs.Set("replay", fmt.Sprint(args.Replay))
@@ -80,6 +96,12 @@ func (args DebugLogParams) URLQuery() url.Values {
 	if !args.StartTime.IsZero() {
 		attrs.Set("startTime", args.StartTime.Format(time.RFC3339Nano))
 	}
+	if !args.EndTime.IsZero() {
+		attrs.Set("endTime", args.EndTime.Format(time.RFC3339Nano))
+	}
+	if args.IncludeMessage != "" {
+		attrs.Set("includeMessage", args.IncludeMessage)
+	}
 	return attrs
 }
 
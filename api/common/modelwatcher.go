@@ -13,7 +13,6 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/watcher"
 	"github.com/juju/juju/environs/config"
-	"github.com/juju/juju/logfwd/syslog"
 )
 
 // ModelWatcher provides common client-side API functions
@@ -54,22 +53,22 @@ func (e *ModelWatcher) ModelConfig() (*config.Config, error) {
 }
 
 // WatchForLogForwardConfigChanges return a NotifyWatcher waiting for the
-// log forward syslog configuration to change.
+// log forward configuration to change.
 func (e *ModelWatcher) WatchForLogForwardConfigChanges() (watcher.NotifyWatcher, error) {
 	// TODO(wallyworld) - lp:1602237 - this needs to have it's own backend implementation.
 	// For now, we'll piggyback off the ModelConfig API.
 	return e.WatchForModelConfigChanges()
 }
 
-// LogForwardConfig returns the current log forward syslog configuration.
-func (e *ModelWatcher) LogForwardConfig() (*syslog.RawConfig, bool, error) {
+// LogForwardConfig returns the current log forward configuration.
+func (e *ModelWatcher) LogForwardConfig() (*config.LogFwdConfig, bool, error) {
 	// TODO(wallyworld) - lp:1602237 - this needs to have it's own backend implementation.
 	// For now, we'll piggyback off the ModelConfig API.
 	modelConfig, err := e.ModelConfig()
 	if err != nil {
 		return nil, false, err
 	}
-	cfg, ok := modelConfig.LogFwdSyslog()
+	cfg, ok := modelConfig.LogFwd()
 	return cfg, ok, nil
 }
 
@@ -0,0 +1,59 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package confighistory provides access to the ConfigHistory API facade,
+// which lists the recorded revisions of application and model config,
+// and rolls config back to an earlier revision.
+package confighistory
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides methods for accessing config history.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new `Client` based on an existing authenticated API
+// connection.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "ConfigHistory")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// ConfigRevisions returns the recorded config revisions of the
+// application, or model, with the given tag, oldest first.
+func (c *Client) ConfigRevisions(tag names.Tag) ([]params.ConfigRevision, error) {
+	var results params.ConfigRevisionsResults
+	args := params.Entities{Entities: []params.Entity{{Tag: tag.String()}}}
+	if err := c.facade.FacadeCall("ConfigRevisions", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if n := len(results.Results); n != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", n)
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results[0].Revisions, nil
+}
+
+// RollbackConfig sets the config of the application, or model, with
+// the given tag to that of the given revision.
+func (c *Client) RollbackConfig(tag names.Tag, revision int) error {
+	var results params.ErrorResults
+	args := params.RollbackConfigArgs{Args: []params.RollbackConfigArg{{
+		Tag:      tag.String(),
+		Revision: revision,
+	}}}
+	if err := c.facade.FacadeCall("RollbackConfig", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
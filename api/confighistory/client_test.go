@@ -0,0 +1,102 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package confighistory_test
+
+import (
+	"time"
+
+	"github.com/juju/names/v4"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/confighistory"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type configHistorySuite struct {
+	gitjujutesting.IsolationSuite
+}
+
+var _ = gc.Suite(&configHistorySuite{})
+
+func (s *configHistorySuite) TestConfigRevisions(c *gc.C) {
+	revisions := []params.ConfigRevision{{
+		Revision: 1,
+		Config:   map[string]interface{}{"blog-title": "first"},
+		Author:   "bob",
+		Created:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "ConfigHistory")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ConfigRevisions")
+			c.Check(a, jc.DeepEquals, params.Entities{Entities: []params.Entity{
+				{Tag: "application-wordpress"},
+			}})
+			c.Assert(result, gc.FitsTypeOf, &params.ConfigRevisionsResults{})
+			*(result.(*params.ConfigRevisionsResults)) = params.ConfigRevisionsResults{
+				Results: []params.ConfigRevisionsResult{{Revisions: revisions}},
+			}
+			return nil
+		},
+	)
+	client := confighistory.NewClient(apiCaller)
+	result, err := client.ConfigRevisions(names.NewApplicationTag("wordpress"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, revisions)
+}
+
+func (s *configHistorySuite) TestConfigRevisionsError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			*(result.(*params.ConfigRevisionsResults)) = params.ConfigRevisionsResults{
+				Results: []params.ConfigRevisionsResult{{
+					Error: &params.Error{Message: `application "mysql" not found`, Code: params.CodeNotFound},
+				}},
+			}
+			return nil
+		},
+	)
+	client := confighistory.NewClient(apiCaller)
+	_, err := client.ConfigRevisions(names.NewApplicationTag("mysql"))
+	c.Assert(err, gc.ErrorMatches, `application "mysql" not found`)
+}
+
+func (s *configHistorySuite) TestRollbackConfig(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "ConfigHistory")
+			c.Check(request, gc.Equals, "RollbackConfig")
+			c.Check(a, jc.DeepEquals, params.RollbackConfigArgs{Args: []params.RollbackConfigArg{{
+				Tag:      "application-wordpress",
+				Revision: 3,
+			}}})
+			c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{
+					Error: &params.Error{Message: "boom"},
+				}},
+			}
+			return nil
+		},
+	)
+	client := confighistory.NewClient(apiCaller)
+	err := client.RollbackConfig(names.NewApplicationTag("wordpress"), 3)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
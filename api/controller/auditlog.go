@@ -0,0 +1,24 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// AuditLog returns the audit log entries matching the query from all
+// the controller nodes, along with the IDs of any nodes whose entries
+// couldn't be retrieved.
+func (c *Client) AuditLog(args params.AuditLogQueryArgs) (params.AuditLogResult, error) {
+	var result params.AuditLogResult
+	if c.BestAPIVersion() < 10 {
+		return result, errors.NotSupportedf("AuditLog not supported by this version of Juju")
+	}
+	if err := c.facade.FacadeCall("AuditLog", args, &result); err != nil {
+		return result, errors.Trace(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/controller"
+	"github.com/juju/juju/apiserver/params"
+)
+
+func (s *Suite) TestAuditLogPriorV10(c *gc.C) {
+	called := false
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 9,
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			called = true
+			return nil
+		},
+	}
+
+	client := controller.NewClient(apiCaller)
+	_, err := client.AuditLog(params.AuditLogQueryArgs{})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(called, jc.IsFalse)
+}
+
+func (s *Suite) TestAuditLog(c *gc.C) {
+	from := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	args := params.AuditLogQueryArgs{
+		Users:   []string{"bob"},
+		Models:  []string{"prod"},
+		Methods: []string{"Application.DestroyApplication"},
+		From:    &from,
+		Limit:   10,
+	}
+	expected := params.AuditLogResult{
+		Entries: []params.AuditLogEntry{{
+			ControllerID: "0",
+			Who:          "bob",
+			ModelName:    "prod",
+			When:         from.Add(time.Hour),
+			Facade:       "Application",
+			Method:       "DestroyApplication",
+		}},
+		Missing: map[string]string{"1": "timed out waiting for response"},
+	}
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 10,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "Controller")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "AuditLog")
+			c.Check(arg, jc.DeepEquals, args)
+			c.Assert(result, gc.FitsTypeOf, &params.AuditLogResult{})
+			*(result.(*params.AuditLogResult)) = expected
+			return nil
+		},
+	}
+
+	client := controller.NewClient(apiCaller)
+	result, err := client.AuditLog(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, expected)
+}
+
+func (s *Suite) TestAuditLogCallError(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 10,
+		APICallerFunc: func(string, int, string, string, interface{}, interface{}) error {
+			return errors.New("boom")
+		},
+	}
+	client := controller.NewClient(apiCaller)
+	_, err := client.AuditLog(params.AuditLogQueryArgs{})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,23 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// ScheduledBackupStatus returns the controller's backup schedule and the
+// outcome of the most recent scheduled backups.
+func (c *Client) ScheduledBackupStatus() (params.ScheduledBackupStatus, error) {
+	var result params.ScheduledBackupStatus
+	if c.BestAPIVersion() < 11 {
+		return result, errors.NotSupportedf("ScheduledBackupStatus not supported by this version of Juju")
+	}
+	if err := c.facade.FacadeCall("ScheduledBackupStatus", nil, &result); err != nil {
+		return result, errors.Trace(err)
+	}
+	return result, nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/controller"
+	"github.com/juju/juju/apiserver/params"
+)
+
+func (s *Suite) TestScheduledBackupStatusPriorV11(c *gc.C) {
+	called := false
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 10,
+		APICallerFunc: func(objType string, version int, id, request string, a, response interface{}) error {
+			called = true
+			return nil
+		},
+	}
+
+	client := controller.NewClient(apiCaller)
+	_, err := client.ScheduledBackupStatus()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(called, jc.IsFalse)
+}
+
+func (s *Suite) TestScheduledBackupStatus(c *gc.C) {
+	lastSuccess := time.Date(2021, 3, 1, 2, 0, 0, 0, time.UTC)
+	nextRun := lastSuccess.Add(24 * time.Hour)
+	expected := params.ScheduledBackupStatus{
+		Schedule:     "0 2 * * *",
+		NextRun:      &nextRun,
+		LastAttempt:  &lastSuccess,
+		LastSuccess:  &lastSuccess,
+		LastBackupID: "20210301-020000.deadbeef",
+	}
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 11,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "Controller")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "ScheduledBackupStatus")
+			c.Check(arg, gc.IsNil)
+			c.Assert(result, gc.FitsTypeOf, &params.ScheduledBackupStatus{})
+			*(result.(*params.ScheduledBackupStatus)) = expected
+			return nil
+		},
+	}
+
+	client := controller.NewClient(apiCaller)
+	result, err := client.ScheduledBackupStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, expected)
+}
+
+func (s *Suite) TestScheduledBackupStatusCallError(c *gc.C) {
+	apiCaller := apitesting.BestVersionCaller{
+		BestVersion: 11,
+		APICallerFunc: func(objType string, version int, id, request string, arg, result interface{}) error {
+			return errors.New("boom")
+		},
+	}
+
+	client := controller.NewClient(apiCaller)
+	_, err := client.ScheduledBackupStatus()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -11,19 +11,21 @@ package api
 // New facades should start at 1.
 // Facades that existed before versioning start at 0.
 var facadeVersions = map[string]int{
-	"Action":                       7,
+	"Action":                       10,
 	"ActionPruner":                 1,
-	"Agent":                        2,
+	"ActionSchedules":              1,
+	"Agent":                        3,
 	"AgentTools":                   1,
 	"AllModelWatcher":              2,
 	"AllWatcher":                   1,
 	"Annotations":                  2,
-	"Application":                  13,
+	"Application":                  14,
 	"ApplicationOffers":            3,
 	"ApplicationScaler":            1,
-	"Backups":                      3,
+	"Backups":                      5,
 	"Block":                        2,
 	"Bundle":                       4,
+	"BundleDrift":                  1,
 	"CAASAgent":                    1,
 	"CAASAdmission":                1,
 	"CAASApplication":              1,
@@ -39,9 +41,10 @@ var facadeVersions = map[string]int{
 	"CharmRevisionUpdater":         2,
 	"Charms":                       4,
 	"Cleaner":                      2,
-	"Client":                       2,
+	"Client":                       3,
 	"Cloud":                        7,
-	"Controller":                   9,
+	"ConfigHistory":                1,
+	"Controller":                   11,
 	"CredentialManager":            1,
 	"CredentialValidator":          2,
 	"CrossController":              1,
@@ -83,6 +86,7 @@ var facadeVersions = map[string]int{
 	"ModelConfig":                  2,
 	"ModelGeneration":              4,
 	"ModelManager":                 9,
+	"ModelQuotas":                  1,
 	"ModelSummaryWatcher":          1,
 	"ModelUpgrader":                1,
 	"NotifyWatcher":                1,
@@ -101,6 +105,8 @@ var facadeVersions = map[string]int{
 	"ResourcesHookContext":         1,
 	"Resumer":                      2,
 	"RetryStrategy":                1,
+	"Rollouts":                     1,
+	"SecretsManager":               1,
 	"Singular":                     2,
 	"Spaces":                       6,
 	"SSHClient":                    2,
@@ -111,13 +117,14 @@ var facadeVersions = map[string]int{
 	"Subnets":                      4,
 	"Undertaker":                   1,
 	"UnitAssigner":                 1,
-	"Uniter":                       17,
+	"Uniter":                       18,
 	"Upgrader":                     1,
 	"UpgradeSeries":                3,
 	"UpgradeSteps":                 2,
 	"UserManager":                  2,
 	"VolumeAttachmentsWatcher":     2,
 	"VolumeAttachmentPlansWatcher": 1,
+	"Webhooks":                     1,
 }
 
 // bestVersion tries to find the newest version in the version list that we can
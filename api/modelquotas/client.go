@@ -0,0 +1,76 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package modelquotas provides access to the ModelQuotas API facade,
+// which reads and sets the quotas that limit the resources used by a
+// model, or by all the models owned by a user.
+package modelquotas
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/quota"
+)
+
+// Client provides methods for reading and setting quotas.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new `Client` based on an existing authenticated API
+// connection.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "ModelQuotas")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Quotas returns the quota limits set for the model or user with the
+// given tag, and the amount of each resource used.
+func (c *Client) Quotas(tag names.Tag) (quota.Limits, quota.Usage, error) {
+	var results params.QuotasResults
+	args := params.Entities{Entities: []params.Entity{{Tag: tag.String()}}}
+	if err := c.facade.FacadeCall("Quotas", args, &results); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if n := len(results.Results); n != 1 {
+		return nil, nil, errors.Errorf("expected 1 result, got %d", n)
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, nil, errors.Trace(result.Error)
+	}
+	limits := make(quota.Limits, len(result.Result.Limits))
+	for r, limit := range result.Result.Limits {
+		limits[quota.Resource(r)] = limit
+	}
+	usage := make(quota.Usage, len(result.Result.Usage))
+	for r, amount := range result.Result.Usage {
+		usage[quota.Resource(r)] = amount
+	}
+	return limits, usage, nil
+}
+
+// SetQuotas sets the given quota limits for the model or user with the
+// given tag, and removes the limits on the resources in reset.
+func (c *Client) SetQuotas(tag names.Tag, limits quota.Limits, reset []quota.Resource) error {
+	arg := params.SetQuotasArg{Tag: tag.String()}
+	if len(limits) > 0 {
+		arg.Limits = make(map[string]uint64, len(limits))
+		for r, limit := range limits {
+			arg.Limits[string(r)] = limit
+		}
+	}
+	for _, r := range reset {
+		arg.Reset = append(arg.Reset, string(r))
+	}
+	var results params.ErrorResults
+	args := params.SetQuotasArgs{Args: []params.SetQuotasArg{arg}}
+	if err := c.facade.FacadeCall("SetQuotas", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
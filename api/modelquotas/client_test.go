@@ -0,0 +1,102 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelquotas_test
+
+import (
+	"github.com/juju/names/v4"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/modelquotas"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/quota"
+)
+
+type modelQuotasSuite struct {
+	gitjujutesting.IsolationSuite
+}
+
+var _ = gc.Suite(&modelQuotasSuite{})
+
+func (s *modelQuotasSuite) TestQuotas(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "ModelQuotas")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "Quotas")
+			c.Check(a, jc.DeepEquals, params.Entities{Entities: []params.Entity{{Tag: "user-bob"}}})
+			c.Assert(result, gc.FitsTypeOf, &params.QuotasResults{})
+			*(result.(*params.QuotasResults)) = params.QuotasResults{
+				Results: []params.QuotasResult{{
+					Result: &params.Quotas{
+						Limits: map[string]uint64{"units": 10},
+						Usage:  map[string]uint64{"units": 4, "machines": 2},
+					},
+				}},
+			}
+			return nil
+		},
+	)
+	client := modelquotas.NewClient(apiCaller)
+	limits, usage, err := client.Quotas(names.NewUserTag("bob"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(limits, jc.DeepEquals, quota.Limits{quota.Units: 10})
+	c.Assert(usage, jc.DeepEquals, quota.Usage{quota.Units: 4, quota.Machines: 2})
+}
+
+func (s *modelQuotasSuite) TestQuotasError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			*(result.(*params.QuotasResults)) = params.QuotasResults{
+				Results: []params.QuotasResult{{
+					Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized},
+				}},
+			}
+			return nil
+		},
+	)
+	client := modelquotas.NewClient(apiCaller)
+	_, _, err := client.Quotas(names.NewUserTag("bob"))
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *modelQuotasSuite) TestSetQuotas(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "ModelQuotas")
+			c.Check(request, gc.Equals, "SetQuotas")
+			c.Check(a, jc.DeepEquals, params.SetQuotasArgs{Args: []params.SetQuotasArg{{
+				Tag:    "model-deadbeef-0bad-400d-8000-4b1d0d06f00d",
+				Limits: map[string]uint64{"machines": 5},
+				Reset:  []string{"storage"},
+			}}})
+			c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{}},
+			}
+			return nil
+		},
+	)
+	client := modelquotas.NewClient(apiCaller)
+	err := client.SetQuotas(
+		names.NewModelTag("deadbeef-0bad-400d-8000-4b1d0d06f00d"),
+		quota.Limits{quota.Machines: 5},
+		[]quota.Resource{quota.Storage},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -0,0 +1,24 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rollouts
+
+import (
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/api/common"
+)
+
+const rolloutsFacade = "Rollouts"
+
+// API provides access to the Rollouts API facade.
+type API struct {
+	*common.DueWorkRunner
+}
+
+// NewAPI creates a new client-side Rollouts facade.
+func NewAPI(caller base.APICaller) *API {
+	facadeCaller := base.NewFacadeCaller(caller, rolloutsFacade)
+	return &API{
+		DueWorkRunner: common.NewDueWorkRunner(facadeCaller),
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package secretsmanager provides access to the SecretsManager API
+// facade, used by unit agents to create and read secrets.
+package secretsmanager
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/secrets"
+)
+
+// Client provides access to the secrets manager api.
+type Client struct {
+	facade base.FacadeCaller
+}
+
+// NewClient creates a client for accessing the secrets manager api.
+func NewClient(apiCaller base.APICaller) *Client {
+	return &Client{base.NewFacadeCaller(apiCaller, "SecretsManager")}
+}
+
+// CreateSecret creates a secret owned by the given application or unit,
+// returning its URI.
+func (c *Client) CreateSecret(owner names.Tag, arg params.CreateSecretArg) (string, error) {
+	arg.OwnerTag = owner.String()
+	var results params.StringResults
+	args := params.CreateSecretArgs{Args: []params.CreateSecretArg{arg}}
+	if err := c.facade.FacadeCall("CreateSecrets", args, &results); err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return "", fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", errors.Trace(result.Error)
+	}
+	return result.Result, nil
+}
+
+// UpdateSecret updates the attributes of a secret, adding a new revision
+// if its value is set.
+func (c *Client) UpdateSecret(arg params.UpdateSecretArg) error {
+	var results params.ErrorResults
+	args := params.UpdateSecretArgs{Args: []params.UpdateSecretArg{arg}}
+	if err := c.facade.FacadeCall("UpdateSecrets", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// GetSecretValue returns the value of the secret with the given URI.
+// With peek, the latest revision is returned without the unit then
+// using it; with refresh, the unit uses the latest revision from then on.
+func (c *Client) GetSecretValue(uri string, peek, refresh bool) (secrets.SecretData, error) {
+	var results params.SecretValueResults
+	args := params.GetSecretValueArgs{Args: []params.GetSecretValueArg{{
+		URI:     uri,
+		Peek:    peek,
+		Refresh: refresh,
+	}}}
+	if err := c.facade.FacadeCall("GetSecretValues", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, errors.Trace(result.Error)
+	}
+	return secrets.SecretData(result.Data), nil
+}
+
+// GrantSecret grants the given applications and units access to the
+// secret with the given URI.
+func (c *Client) GrantSecret(uri string, subjects []names.Tag) error {
+	return c.grantOrRevoke("GrantSecrets", uri, subjects)
+}
+
+// RevokeSecret revokes the access of the given applications and units
+// to the secret with the given URI.
+func (c *Client) RevokeSecret(uri string, subjects []names.Tag) error {
+	return c.grantOrRevoke("RevokeSecrets", uri, subjects)
+}
+
+func (c *Client) grantOrRevoke(method, uri string, subjects []names.Tag) error {
+	arg := params.GrantRevokeSecretArg{URI: uri}
+	for _, subject := range subjects {
+		arg.SubjectTags = append(arg.SubjectTags, subject.String())
+	}
+	var results params.ErrorResults
+	args := params.GrantRevokeSecretArgs{Args: []params.GrantRevokeSecretArg{arg}}
+	if err := c.facade.FacadeCall(method, args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
@@ -0,0 +1,143 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secretsmanager_test
+
+import (
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/secretsmanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/secrets"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type secretsManagerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&secretsManagerSuite{})
+
+const uri = "secret:deadbeef-0bad-400d-8000-4b1d0d06f00d"
+
+func (s *secretsManagerSuite) TestCreateSecret(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "SecretsManager")
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "CreateSecrets")
+		c.Check(arg, jc.DeepEquals, params.CreateSecretArgs{Args: []params.CreateSecretArg{{
+			OwnerTag:     "application-mysql",
+			Description:  "db password",
+			RotatePolicy: "daily",
+			Data:         map[string]string{"password": "s3cret"},
+		}}})
+		*(result.(*params.StringResults)) = params.StringResults{
+			Results: []params.StringResult{{Result: uri}},
+		}
+		return nil
+	})
+	client := secretsmanager.NewClient(apiCaller)
+	result, err := client.CreateSecret(names.NewApplicationTag("mysql"), params.CreateSecretArg{
+		Description:  "db password",
+		RotatePolicy: "daily",
+		Data:         map[string]string{"password": "s3cret"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.Equals, uri)
+}
+
+func (s *secretsManagerSuite) TestCreateSecretError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.StringResults)) = params.StringResults{
+			Results: []params.StringResult{{Error: &params.Error{Message: "boom"}}},
+		}
+		return nil
+	})
+	client := secretsmanager.NewClient(apiCaller)
+	_, err := client.CreateSecret(names.NewUnitTag("mysql/0"), params.CreateSecretArg{})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *secretsManagerSuite) TestUpdateSecret(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "SecretsManager")
+		c.Check(request, gc.Equals, "UpdateSecrets")
+		c.Check(arg, jc.DeepEquals, params.UpdateSecretArgs{Args: []params.UpdateSecretArg{{
+			URI:  uri,
+			Data: map[string]string{"password": "n3w"},
+		}}})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{Error: &params.Error{Message: "boom"}}},
+		}
+		return nil
+	})
+	client := secretsmanager.NewClient(apiCaller)
+	err := client.UpdateSecret(params.UpdateSecretArg{
+		URI:  uri,
+		Data: map[string]string{"password": "n3w"},
+	})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *secretsManagerSuite) TestGetSecretValue(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "SecretsManager")
+		c.Check(request, gc.Equals, "GetSecretValues")
+		c.Check(arg, jc.DeepEquals, params.GetSecretValueArgs{Args: []params.GetSecretValueArg{{
+			URI:     uri,
+			Refresh: true,
+		}}})
+		*(result.(*params.SecretValueResults)) = params.SecretValueResults{
+			Results: []params.SecretValueResult{{
+				Revision: 2,
+				Data:     map[string]string{"password": "s3cret"},
+			}},
+		}
+		return nil
+	})
+	client := secretsmanager.NewClient(apiCaller)
+	data, err := client.GetSecretValue(uri, false, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, secrets.SecretData{"password": "s3cret"})
+}
+
+func (s *secretsManagerSuite) TestGetSecretValueError(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.SecretValueResults)) = params.SecretValueResults{
+			Results: []params.SecretValueResult{{
+				Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized},
+			}},
+		}
+		return nil
+	})
+	client := secretsmanager.NewClient(apiCaller)
+	_, err := client.GetSecretValue(uri, true, false)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(err, jc.Satisfies, params.IsCodeUnauthorized)
+}
+
+func (s *secretsManagerSuite) TestGrantRevokeSecret(c *gc.C) {
+	var calls []string
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		calls = append(calls, request)
+		c.Check(objType, gc.Equals, "SecretsManager")
+		c.Check(arg, jc.DeepEquals, params.GrantRevokeSecretArgs{Args: []params.GrantRevokeSecretArg{{
+			URI:         uri,
+			SubjectTags: []string{"application-wordpress", "unit-wordpress-0"},
+		}}})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}},
+		}
+		return nil
+	})
+	client := secretsmanager.NewClient(apiCaller)
+	subjects := []names.Tag{names.NewApplicationTag("wordpress"), names.NewUnitTag("wordpress/0")}
+	err := client.GrantSecret(uri, subjects)
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.RevokeSecret(uri, subjects)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(calls, jc.DeepEquals, []string{"GrantSecrets", "RevokeSecrets"})
+}
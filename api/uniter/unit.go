@@ -11,6 +11,7 @@ import (
 	"github.com/juju/juju/api/common"
 	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/core/life"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/network"
@@ -655,6 +656,36 @@ func (u *Unit) LogActionMessage(tag names.ActionTag, message string) error {
 	return result.OneError()
 }
 
+// HookLimits returns the limits placed on the hooks and actions run by
+// the unit. Controllers that predate hook limits impose none.
+func (u *Unit) HookLimits() (application.HookLimits, error) {
+	if u.st.facade.BestAPIVersion() < 18 {
+		return application.HookLimits{}, nil
+	}
+
+	var results params.HookLimitsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("HookLimits", args, &results)
+	if err != nil {
+		return application.HookLimits{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return application.HookLimits{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return application.HookLimits{}, errors.Trace(result.Error)
+	}
+	return application.HookLimits{
+		HookTimeout:   result.Result.HookTimeout,
+		ActionTimeout: result.Result.ActionTimeout,
+		CPUQuota:      result.Result.CPUQuota,
+		MemoryLimit:   result.Result.MemoryLimit,
+	}, nil
+}
+
 // UpgradeSeriesStatus returns the upgrade series status of a unit from remote state
 func (u *Unit) UpgradeSeriesStatus() (model.UpgradeSeriesStatus, error) {
 	res, err := u.st.UpgradeSeriesUnitStatus()
@@ -17,6 +17,7 @@ import (
 	basetesting "github.com/juju/juju/api/base/testing"
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/core/life"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/status"
@@ -1042,3 +1043,47 @@ func (s *unitSuite) TestCanApplyLXDProfile(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(canApply, jc.IsTrue)
 }
+
+func (s *unitSuite) TestHookLimits(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Assert(objType, gc.Equals, "Uniter")
+		c.Assert(request, gc.Equals, "HookLimits")
+		c.Assert(arg, gc.DeepEquals, params.Entities{Entities: []params.Entity{{Tag: "unit-mysql-0"}}})
+		c.Assert(result, gc.FitsTypeOf, &params.HookLimitsResults{})
+		*(result.(*params.HookLimitsResults)) = params.HookLimitsResults{
+			Results: []params.HookLimitsResult{{
+				Result: params.HookLimits{
+					HookTimeout:   30 * time.Minute,
+					ActionTimeout: time.Hour,
+					CPUQuota:      50,
+					MemoryLimit:   512,
+				},
+			}},
+		}
+		return nil
+	})
+	caller := basetesting.BestVersionCaller{apiCaller, 18}
+	client := uniter.NewState(caller, names.NewUnitTag("mysql/0"))
+	unit := uniter.CreateUnit(client, names.NewUnitTag("mysql/0"))
+	limits, err := unit.HookLimits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(limits, jc.DeepEquals, application.HookLimits{
+		HookTimeout:   30 * time.Minute,
+		ActionTimeout: time.Hour,
+		CPUQuota:      50,
+		MemoryLimit:   512,
+	})
+}
+
+func (s *unitSuite) TestHookLimitsOldController(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected api call %q", request)
+		return nil
+	})
+	caller := basetesting.BestVersionCaller{apiCaller, 17}
+	client := uniter.NewState(caller, names.NewUnitTag("mysql/0"))
+	unit := uniter.CreateUnit(client, names.NewUnitTag("mysql/0"))
+	limits, err := unit.HookLimits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(limits, jc.DeepEquals, application.HookLimits{})
+}
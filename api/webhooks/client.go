@@ -0,0 +1,71 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package webhooks provides access to the Webhooks API facade, which
+// manages the webhooks notified of events in a model.
+package webhooks
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/webhook"
+)
+
+// Client provides methods for managing webhooks.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new `Client` based on an existing authenticated API
+// connection.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Webhooks")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// AddWebhook registers a webhook to be notified of events in the model,
+// returning its id.
+func (c *Client) AddWebhook(hook webhook.Webhook) (string, error) {
+	arg := params.AddWebhookArg{
+		URL:      hook.URL,
+		Secret:   hook.Secret,
+		Template: hook.Template,
+	}
+	for _, event := range hook.Events {
+		arg.Events = append(arg.Events, string(event))
+	}
+	var results params.AddWebhookResults
+	args := params.AddWebhooksArgs{Webhooks: []params.AddWebhookArg{arg}}
+	if err := c.facade.FacadeCall("AddWebhooks", args, &results); err != nil {
+		return "", errors.Trace(err)
+	}
+	if n := len(results.Results); n != 1 {
+		return "", errors.Errorf("expected 1 result, got %d", n)
+	}
+	if err := results.Results[0].Error; err != nil {
+		return "", errors.Trace(err)
+	}
+	return results.Results[0].Id, nil
+}
+
+// Webhooks returns the webhooks registered in the model.
+func (c *Client) Webhooks() ([]params.Webhook, error) {
+	var result params.WebhooksResult
+	if err := c.facade.FacadeCall("ListWebhooks", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result.Webhooks, nil
+}
+
+// RemoveWebhook removes the webhook with the given id from the model.
+func (c *Client) RemoveWebhook(id string) error {
+	var results params.ErrorResults
+	args := params.RemoveWebhooksArgs{Ids: []string{id}}
+	if err := c.facade.FacadeCall("RemoveWebhooks", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
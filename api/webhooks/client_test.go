@@ -0,0 +1,130 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhooks_test
+
+import (
+	"time"
+
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/webhooks"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/webhook"
+)
+
+type webhooksSuite struct {
+	gitjujutesting.IsolationSuite
+}
+
+var _ = gc.Suite(&webhooksSuite{})
+
+func (s *webhooksSuite) TestAddWebhook(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Webhooks")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "AddWebhooks")
+			c.Check(a, jc.DeepEquals, params.AddWebhooksArgs{Webhooks: []params.AddWebhookArg{{
+				URL:    "https://example.com/hook",
+				Events: []string{"unit-error", "machine-down"},
+				Secret: "s3cret",
+			}}})
+			c.Assert(result, gc.FitsTypeOf, &params.AddWebhookResults{})
+			*(result.(*params.AddWebhookResults)) = params.AddWebhookResults{
+				Results: []params.AddWebhookResult{{Id: "1"}},
+			}
+			return nil
+		},
+	)
+	client := webhooks.NewClient(apiCaller)
+	id, err := client.AddWebhook(webhook.Webhook{
+		URL:    "https://example.com/hook",
+		Events: []webhook.EventKind{webhook.UnitError, webhook.MachineDown},
+		Secret: "s3cret",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, "1")
+}
+
+func (s *webhooksSuite) TestAddWebhookError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			*(result.(*params.AddWebhookResults)) = params.AddWebhookResults{
+				Results: []params.AddWebhookResult{{
+					Error: &params.Error{Message: `webhook event "bogus" not valid`},
+				}},
+			}
+			return nil
+		},
+	)
+	client := webhooks.NewClient(apiCaller)
+	_, err := client.AddWebhook(webhook.Webhook{
+		URL:    "https://example.com/hook",
+		Events: []webhook.EventKind{"bogus"},
+	})
+	c.Assert(err, gc.ErrorMatches, `webhook event "bogus" not valid`)
+}
+
+func (s *webhooksSuite) TestWebhooks(c *gc.C) {
+	hooks := []params.Webhook{{
+		Id:        "1",
+		URL:       "https://example.com/hook",
+		Signed:    true,
+		CreatedBy: "admin",
+		Created:   time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	}}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Webhooks")
+			c.Check(request, gc.Equals, "ListWebhooks")
+			c.Check(a, gc.IsNil)
+			c.Assert(result, gc.FitsTypeOf, &params.WebhooksResult{})
+			*(result.(*params.WebhooksResult)) = params.WebhooksResult{Webhooks: hooks}
+			return nil
+		},
+	)
+	client := webhooks.NewClient(apiCaller)
+	result, err := client.Webhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, hooks)
+}
+
+func (s *webhooksSuite) TestRemoveWebhook(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Webhooks")
+			c.Check(request, gc.Equals, "RemoveWebhooks")
+			c.Check(a, jc.DeepEquals, params.RemoveWebhooksArgs{Ids: []string{"42"}})
+			c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+			*(result.(*params.ErrorResults)) = params.ErrorResults{
+				Results: []params.ErrorResult{{
+					Error: &params.Error{Message: `webhook "42" not found`, Code: params.CodeNotFound},
+				}},
+			}
+			return nil
+		},
+	)
+	client := webhooks.NewClient(apiCaller)
+	err := client.RemoveWebhook("42")
+	c.Assert(err, gc.ErrorMatches, `webhook "42" not found`)
+}
@@ -37,6 +37,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/agent/reboot"
 	"github.com/juju/juju/apiserver/facades/agent/resourceshookcontext"
 	"github.com/juju/juju/apiserver/facades/agent/retrystrategy"
+	"github.com/juju/juju/apiserver/facades/agent/secretsmanager"
 	"github.com/juju/juju/apiserver/facades/agent/storageprovisioner"
 	"github.com/juju/juju/apiserver/facades/agent/unitassigner"
 	"github.com/juju/juju/apiserver/facades/agent/uniter"
@@ -50,10 +51,12 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/backups" // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/block"   // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/bundle"
+	"github.com/juju/juju/apiserver/facades/client/bundledrift"
 	"github.com/juju/juju/apiserver/facades/client/charmhub"
-	"github.com/juju/juju/apiserver/facades/client/charms"     // ModelUser Write
-	"github.com/juju/juju/apiserver/facades/client/client"     // ModelUser Write
-	"github.com/juju/juju/apiserver/facades/client/cloud"      // ModelUser Read
+	"github.com/juju/juju/apiserver/facades/client/charms" // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/client" // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/cloud"  // ModelUser Read
+	"github.com/juju/juju/apiserver/facades/client/confighistory"
 	"github.com/juju/juju/apiserver/facades/client/controller" // ModelUser Admin (although some methods check for read only)
 	"github.com/juju/juju/apiserver/facades/client/credentialmanager"
 	"github.com/juju/juju/apiserver/facades/client/firewallrules"
@@ -66,6 +69,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/modelconfig"    // ModelUser Write
 	"github.com/juju/juju/apiserver/facades/client/modelgeneration"
 	"github.com/juju/juju/apiserver/facades/client/modelmanager" // ModelUser Write
+	"github.com/juju/juju/apiserver/facades/client/modelquotas"
 	"github.com/juju/juju/apiserver/facades/client/payloads"
 	"github.com/juju/juju/apiserver/facades/client/resources"
 	"github.com/juju/juju/apiserver/facades/client/spaces"    // ModelUser Write
@@ -73,7 +77,9 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/storage"
 	"github.com/juju/juju/apiserver/facades/client/subnets"
 	"github.com/juju/juju/apiserver/facades/client/usermanager"
+	"github.com/juju/juju/apiserver/facades/client/webhooks"
 	"github.com/juju/juju/apiserver/facades/controller/actionpruner"
+	"github.com/juju/juju/apiserver/facades/controller/actionschedules"
 	"github.com/juju/juju/apiserver/facades/controller/agenttools"
 	"github.com/juju/juju/apiserver/facades/controller/applicationscaler"
 	"github.com/juju/juju/apiserver/facades/controller/caasapplicationprovisioner"
@@ -99,6 +105,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/controller/modelupgrader"
 	"github.com/juju/juju/apiserver/facades/controller/remoterelations"
 	"github.com/juju/juju/apiserver/facades/controller/resumer"
+	"github.com/juju/juju/apiserver/facades/controller/rollouts"
 	"github.com/juju/juju/apiserver/facades/controller/singular"
 	"github.com/juju/juju/apiserver/facades/controller/statushistory"
 	"github.com/juju/juju/apiserver/facades/controller/undertaker"
@@ -134,8 +141,13 @@ func AllFacades() *facade.Registry {
 	}
 
 	reg("Action", 7, action.NewActionAPIV7)
+	reg("Action", 8, action.NewActionAPIV8)
+	reg("Action", 9, action.NewActionAPIV9)
+	reg("Action", 10, action.NewActionAPIV10)
 	reg("ActionPruner", 1, actionpruner.NewAPI)
+	reg("ActionSchedules", 1, actionschedules.NewActionSchedulesAPI)
 	reg("Agent", 2, agent.NewAgentAPIV2)
+	reg("Agent", 3, agent.NewAgentAPIV3)
 	reg("AgentTools", 1, agenttools.NewFacade)
 	reg("Annotations", 2, annotations.NewAPI)
 
@@ -155,17 +167,21 @@ func AllFacades() *facade.Registry {
 	reg("Application", 11, application.NewFacadeV11) // Get call returns the endpoint bindings
 	reg("Application", 12, application.NewFacadeV12) // Adds UnitsInfo()
 	reg("Application", 13, application.NewFacadeV13) // Adds CharmOrigin to Deploy
+	reg("Application", 14, application.NewFacadeV14) // Adds CharmRefreshReport()
 
 	reg("ApplicationOffers", 1, applicationoffers.NewOffersAPI)
 	reg("ApplicationOffers", 2, applicationoffers.NewOffersAPIV2)
 	reg("ApplicationOffers", 3, applicationoffers.NewOffersAPIV3) // Add user to consume offers details  args.
 	reg("ApplicationScaler", 1, applicationscaler.NewAPI)
 	reg("Backups", 3, backups.NewFacadeV3)
+	reg("Backups", 4, backups.NewFacadeV4)
+	reg("Backups", 5, backups.NewFacadeV5)
 	reg("Block", 2, block.NewAPI)
 	reg("Bundle", 1, bundle.NewFacadeV1)
 	reg("Bundle", 2, bundle.NewFacadeV2)
 	reg("Bundle", 3, bundle.NewFacadeV3)
 	reg("Bundle", 4, bundle.NewFacadeV4)
+	reg("BundleDrift", 1, bundledrift.NewFacade)
 	reg("CharmHub", 1, charmhub.NewFacade)
 	reg("CharmRevisionUpdater", 2, charmrevisionupdater.NewCharmRevisionUpdaterAPI)
 	reg("Charms", 2, charms.NewFacadeV2)
@@ -173,7 +189,8 @@ func AllFacades() *facade.Registry {
 	reg("Charms", 4, charms.NewFacadeV4)
 	reg("Cleaner", 2, cleaner.NewCleanerAPI)
 	reg("Client", 1, client.NewFacadeV1)
-	reg("Client", 2, client.NewFacade)
+	reg("Client", 2, client.NewFacadeV2)
+	reg("Client", 3, client.NewFacade)
 	reg("Cloud", 1, cloud.NewFacadeV1)
 	reg("Cloud", 2, cloud.NewFacadeV2) // adds AddCloud, AddCredentials, CredentialContents, RemoveClouds
 	reg("Cloud", 3, cloud.NewFacadeV3) // changes signature of UpdateCredentials, adds ModifyCloudAccess
@@ -196,6 +213,8 @@ func AllFacades() *facade.Registry {
 	reg("CAASApplication", 1, caasapplication.NewStateFacade)
 	reg("CAASApplicationProvisioner", 1, caasapplicationprovisioner.NewStateCAASApplicationProvisionerAPI)
 
+	reg("ConfigHistory", 1, confighistory.NewFacade)
+
 	reg("Controller", 3, controller.NewControllerAPIv3)
 	reg("Controller", 4, controller.NewControllerAPIv4)
 	reg("Controller", 5, controller.NewControllerAPIv5)
@@ -203,6 +222,8 @@ func AllFacades() *facade.Registry {
 	reg("Controller", 7, controller.NewControllerAPIv7)
 	reg("Controller", 8, controller.NewControllerAPIv8)
 	reg("Controller", 9, controller.NewControllerAPIv9)
+	reg("Controller", 10, controller.NewControllerAPIv10)
+	reg("Controller", 11, controller.NewControllerAPIv11)
 	reg("CrossModelRelations", 1, crossmodelrelations.NewStateCrossModelRelationsAPIV1)
 	reg("CrossModelRelations", 2, crossmodelrelations.NewStateCrossModelRelationsAPI) // Adds WatchRelationChanges, removes WatchRelationUnits
 	reg("CrossController", 1, crosscontroller.NewStateCrossControllerAPI)
@@ -279,6 +300,7 @@ func AllFacades() *facade.Registry {
 	reg("ModelManager", 7, modelmanager.NewFacadeV7) // DestroyModels gains 'force' and max-wait' parameters.
 	reg("ModelManager", 8, modelmanager.NewFacadeV8) // ModelInfo gains credential validity in return.
 	reg("ModelManager", 9, modelmanager.NewFacadeV9) // Adds ValidateModelUpgrade
+	reg("ModelQuotas", 1, modelquotas.NewFacade)
 	reg("ModelUpgrader", 1, modelupgrader.NewStateFacade)
 
 	reg("Payloads", 1, payloads.NewFacade)
@@ -311,6 +333,8 @@ func AllFacades() *facade.Registry {
 
 	reg("Resumer", 2, resumer.NewResumerAPI)
 	reg("RetryStrategy", 1, retrystrategy.NewRetryStrategyAPI)
+	reg("Rollouts", 1, rollouts.NewRolloutsAPI)
+	reg("SecretsManager", 1, secretsmanager.NewFacade)
 	reg("Singular", 2, singular.NewExternalFacade)
 
 	reg("SSHClient", 1, sshclient.NewFacade)
@@ -350,7 +374,8 @@ func AllFacades() *facade.Registry {
 	reg("Uniter", 14, uniter.NewUniterAPIV14)
 	reg("Uniter", 15, uniter.NewUniterAPIV15)
 	reg("Uniter", 16, uniter.NewUniterAPIV16)
-	reg("Uniter", 17, uniter.NewUniterAPI)
+	reg("Uniter", 17, uniter.NewUniterAPIV17)
+	reg("Uniter", 18, uniter.NewUniterAPI)
 
 	reg("Upgrader", 1, upgrader.NewUpgraderFacade)
 
@@ -362,6 +387,7 @@ func AllFacades() *facade.Registry {
 	reg("UpgradeSteps", 2, upgradesteps.NewFacadeV2)
 	reg("UserManager", 1, usermanager.NewUserManagerAPI)
 	reg("UserManager", 2, usermanager.NewUserManagerAPI) // Adds ResetPassword
+	reg("Webhooks", 1, webhooks.NewFacade)
 
 	regRaw("AllWatcher", 1, NewAllWatcher, reflect.TypeOf((*SrvAllWatcher)(nil)))
 	// Note: AllModelWatcher uses the same infrastructure as AllWatcher
@@ -687,6 +687,10 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		stateAuthFunc: httpCtxt.stateForMigrationImporting,
 	}
 	backupHandler := &backupHandler{ctxt: httpCtxt}
+	modelMetricsHandler := &modelMetricsHandler{
+		ctxt:       httpCtxt,
+		controller: srv.shared.controller,
+	}
 	registerHandler := &registerUserHandler{ctxt: httpCtxt}
 	dashboardArchiveHandler := &dashboardArchiveHandler{ctxt: httpCtxt}
 	dashboardVersionHandler := &dashboardVersionHandler{ctxt: httpCtxt}
@@ -760,6 +764,9 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 	}, {
 		pattern: modelRoutePrefix + "/backups",
 		handler: backupHandler,
+	}, {
+		pattern: modelRoutePrefix + "/metrics",
+		handler: modelMetricsHandler,
 	}, {
 		pattern:    "/migrate/charms",
 		handler:    migrateCharmsHTTPHandler,
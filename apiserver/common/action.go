@@ -286,3 +286,29 @@ func MakeActionResult(actionReceiverTag names.Tag, action state.Action) params.A
 
 	return result
 }
+
+// MakeOperationResult does the type conversion from state.OperationInfo
+// to params.OperationResult, including the results of all of the
+// operation's tasks.
+func MakeOperationResult(info state.OperationInfo) params.OperationResult {
+	result := params.OperationResult{
+		OperationTag: info.Operation.Tag().String(),
+		Summary:      info.Operation.Summary(),
+		Enqueued:     info.Operation.Enqueued(),
+		Started:      info.Operation.Started(),
+		Completed:    info.Operation.Completed(),
+		Status:       string(info.Operation.Status()),
+		Actions:      make([]params.ActionResult, len(info.Actions)),
+	}
+	for i, a := range info.Actions {
+		receiver, err := names.ActionReceiverTag(a.Receiver())
+		if err == nil {
+			result.Actions[i] = MakeActionResult(receiver, a)
+			continue
+		}
+		result.Actions[i] = params.ActionResult{
+			Error: apiservererrors.ServerError(errors.Errorf("unknown action receiver %q", a.Receiver())),
+		}
+	}
+	return result
+}
@@ -8,7 +8,9 @@ import (
 	"github.com/juju/names/v4"
 
 	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/state"
 )
@@ -16,29 +18,38 @@ import (
 // ControllerConfigAPI implements two common methods for use by various
 // facades - eg Provisioner and ControllerConfig.
 type ControllerConfigAPI struct {
-	st state.ControllerAccessor
+	st         state.ControllerAccessor
+	authorizer facade.Authorizer
 }
 
 // NewStateControllerConfig returns a new NewControllerConfigAPI.
-func NewStateControllerConfig(st *state.State) *ControllerConfigAPI {
-	return NewControllerConfig(&controllerStateShim{st})
+func NewStateControllerConfig(st *state.State, authorizer facade.Authorizer) *ControllerConfigAPI {
+	return NewControllerConfig(&controllerStateShim{st}, authorizer)
 }
 
 // NewControllerConfig returns a new NewControllerConfigAPI.
-func NewControllerConfig(st state.ControllerAccessor) *ControllerConfigAPI {
+func NewControllerConfig(st state.ControllerAccessor, authorizer facade.Authorizer) *ControllerConfigAPI {
 	return &ControllerConfigAPI{
-		st: st,
+		st:         st,
+		authorizer: authorizer,
 	}
 }
 
-// ControllerConfig returns the controller's configuration.
+// ControllerConfig returns the controller's configuration. The secret
+// attributes are only returned to controller machine agents.
 func (s *ControllerConfigAPI) ControllerConfig() (params.ControllerConfigResult, error) {
 	result := params.ControllerConfigResult{}
 	config, err := s.st.ControllerConfig()
 	if err != nil {
 		return result, err
 	}
-	result.Config = params.ControllerConfig(config)
+	result.Config = make(params.ControllerConfig)
+	for name, value := range config {
+		if controller.SecretAttribute(name) && !s.authorizer.AuthController() {
+			continue
+		}
+		result.Config[name] = value
+	}
 	return result, nil
 }
 
@@ -14,6 +14,7 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/crossmodel"
 	"github.com/juju/juju/core/migration"
@@ -33,6 +34,13 @@ type controllerConfigSuite struct {
 
 var _ = gc.Suite(&controllerConfigSuite{})
 
+// controllerAuthorizer authorizes a controller machine agent, which is
+// given the secret controller config attributes.
+var controllerAuthorizer = apiservertesting.FakeAuthorizer{
+	Tag:        names.NewMachineTag("0"),
+	Controller: true,
+}
+
 type fakeControllerAccessor struct {
 	controllerConfigError error
 }
@@ -42,10 +50,11 @@ func (f *fakeControllerAccessor) ControllerConfig() (controller.Config, error) {
 		return nil, f.controllerConfigError
 	}
 	return map[string]interface{}{
-		controller.ControllerUUIDKey: testing.ControllerTag.Id(),
-		controller.CACertKey:         testing.CACert,
-		controller.APIPort:           4321,
-		controller.StatePort:         1234,
+		controller.ControllerUUIDKey:       testing.ControllerTag.Id(),
+		controller.CACertKey:               testing.CACert,
+		controller.APIPort:                 4321,
+		controller.StatePort:               1234,
+		controller.SecretBackendVaultToken: "s3cret",
 	}, nil
 }
 
@@ -64,9 +73,11 @@ func (s *controllerConfigSuite) TearDownTest(c *gc.C) {
 func (*controllerConfigSuite) TestControllerConfigSuccess(c *gc.C) {
 	cc := common.NewControllerConfig(
 		&fakeControllerAccessor{},
+		apiservertesting.FakeAuthorizer{Tag: names.NewMachineTag("1")},
 	)
 	result, err := cc.ControllerConfig()
 	c.Assert(err, jc.ErrorIsNil)
+	// The secret attributes are left out.
 	c.Assert(map[string]interface{}(result.Config), jc.DeepEquals, map[string]interface{}{
 		"ca-cert":         testing.CACert,
 		"controller-uuid": "deadbeef-1bad-500d-9000-4b1d0d06f00d",
@@ -75,11 +86,28 @@ func (*controllerConfigSuite) TestControllerConfigSuccess(c *gc.C) {
 	})
 }
 
+func (*controllerConfigSuite) TestControllerConfigForController(c *gc.C) {
+	cc := common.NewControllerConfig(
+		&fakeControllerAccessor{},
+		controllerAuthorizer,
+	)
+	result, err := cc.ControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(map[string]interface{}(result.Config), jc.DeepEquals, map[string]interface{}{
+		"ca-cert":                    testing.CACert,
+		"controller-uuid":            "deadbeef-1bad-500d-9000-4b1d0d06f00d",
+		"state-port":                 1234,
+		"api-port":                   4321,
+		"secret-backend-vault-token": "s3cret",
+	})
+}
+
 func (*controllerConfigSuite) TestControllerConfigFetchError(c *gc.C) {
 	cc := common.NewControllerConfig(
 		&fakeControllerAccessor{
 			controllerConfigError: fmt.Errorf("pow"),
 		},
+		controllerAuthorizer,
 	)
 	_, err := cc.ControllerConfig()
 	c.Assert(err, gc.ErrorMatches, "pow")
@@ -88,6 +116,7 @@ func (*controllerConfigSuite) TestControllerConfigFetchError(c *gc.C) {
 func (*controllerConfigSuite) TestControllerInfo(c *gc.C) {
 	cc := common.NewControllerConfig(
 		&fakeControllerAccessor{},
+		controllerAuthorizer,
 	)
 	results, err := cc.ControllerAPIInfoForModels(params.Entities{
 		Entities: []params.Entity{{Tag: testing.ModelTag.String()}}})
@@ -118,7 +147,7 @@ func (s *controllerInfoSuite) SetUpTest(c *gc.C) {
 }
 
 func (s *controllerInfoSuite) TestControllerInfoLocalModel(c *gc.C) {
-	cc := common.NewStateControllerConfig(s.State)
+	cc := common.NewStateControllerConfig(s.State, controllerAuthorizer)
 	results, err := cc.ControllerAPIInfoForModels(params.Entities{
 		Entities: []params.Entity{{Tag: s.localModel.ModelTag().String()}}})
 	c.Assert(err, jc.ErrorIsNil)
@@ -140,7 +169,7 @@ func (s *controllerInfoSuite) TestControllerInfoExternalModel(c *gc.C) {
 	}
 	_, err := ec.Save(info, modelUUID)
 	c.Assert(err, jc.ErrorIsNil)
-	cc := common.NewStateControllerConfig(s.State)
+	cc := common.NewStateControllerConfig(s.State, controllerAuthorizer)
 	results, err := cc.ControllerAPIInfoForModels(params.Entities{
 		Entities: []params.Entity{{Tag: names.NewModelTag(modelUUID).String()}}})
 	c.Assert(err, jc.ErrorIsNil)
@@ -150,7 +179,7 @@ func (s *controllerInfoSuite) TestControllerInfoExternalModel(c *gc.C) {
 }
 
 func (s *controllerInfoSuite) TestControllerInfoMigratedController(c *gc.C) {
-	cc := common.NewStateControllerConfig(s.State)
+	cc := common.NewStateControllerConfig(s.State, controllerAuthorizer)
 	modelState := s.Factory.MakeModel(c, &factory.ModelParams{})
 	model, err := modelState.Model()
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,71 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+)
+
+// DueWorkBackend does work that becomes due over time, such as
+// releasing the next batch of a rolling operation.
+type DueWorkBackend interface {
+	// RunDueWork does the work that is due, and returns when more
+	// work will be due, or the zero time if none is waiting.
+	RunDueWork() (time.Time, error)
+
+	// WatchDueWork returns a watcher that notifies of changes which
+	// may make work due.
+	WatchDueWork() state.NotifyWatcher
+}
+
+// DueWorkRunner implements the methods of the facades used by
+// worker/duework workers.
+type DueWorkRunner struct {
+	backend   DueWorkBackend
+	resources facade.Resources
+}
+
+// NewDueWorkRunner returns a new DueWorkRunner doing the work of the
+// given backend.
+func NewDueWorkRunner(backend DueWorkBackend, resources facade.Resources) *DueWorkRunner {
+	return &DueWorkRunner{
+		backend:   backend,
+		resources: resources,
+	}
+}
+
+// RunDueWork does the work that is due, and returns when more work
+// will be due.
+func (r *DueWorkRunner) RunDueWork() (params.DueWorkResult, error) {
+	next, err := r.backend.RunDueWork()
+	if err != nil {
+		return params.DueWorkResult{}, errors.Trace(err)
+	}
+	var result params.DueWorkResult
+	if !next.IsZero() {
+		result.Next = &next
+	}
+	return result, nil
+}
+
+// WatchDueWork watches for changes which may make work due.
+func (r *DueWorkRunner) WatchDueWork() (params.NotifyWatchResult, error) {
+	watch := r.backend.WatchDueWork()
+	if _, ok := <-watch.Changes(); ok {
+		return params.NotifyWatchResult{
+			NotifyWatcherId: r.resources.Register(watch),
+		}, nil
+	}
+	return params.NotifyWatchResult{
+		Error: apiservererrors.ServerError(watcher.EnsureErr(watch)),
+	}, nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type dueWorkRunnerSuite struct {
+	coretesting.BaseSuite
+
+	backend *mockDueWorkBackend
+	runner  *common.DueWorkRunner
+}
+
+var _ = gc.Suite(&dueWorkRunnerSuite{})
+
+func (s *dueWorkRunnerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.backend = &mockDueWorkBackend{Stub: &testing.Stub{}}
+	resources := common.NewResources()
+	s.AddCleanup(func(*gc.C) { resources.StopAll() })
+	s.runner = common.NewDueWorkRunner(s.backend, resources)
+}
+
+func (s *dueWorkRunnerSuite) TestWatchDueWorkSuccess(c *gc.C) {
+	result, err := s.runner.WatchDueWork()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.NotifyWatcherId, gc.Not(gc.Equals), "")
+	s.backend.CheckCallNames(c, "WatchDueWork")
+}
+
+func (s *dueWorkRunnerSuite) TestWatchDueWorkFailure(c *gc.C) {
+	s.backend.SetErrors(errors.New("boom!"))
+	s.backend.watchFails = true
+
+	result, err := s.runner.WatchDueWork()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error.Error(), gc.Equals, "boom!")
+	s.backend.CheckCallNames(c, "WatchDueWork")
+}
+
+func (s *dueWorkRunnerSuite) TestRunDueWork(c *gc.C) {
+	s.backend.next = time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	result, err := s.runner.RunDueWork()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Next, gc.NotNil)
+	c.Assert(*result.Next, gc.Equals, s.backend.next)
+	s.backend.CheckCallNames(c, "RunDueWork")
+}
+
+func (s *dueWorkRunnerSuite) TestRunDueWorkNoneWaiting(c *gc.C) {
+	result, err := s.runner.RunDueWork()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Next, gc.IsNil)
+}
+
+func (s *dueWorkRunnerSuite) TestRunDueWorkFailure(c *gc.C) {
+	s.backend.SetErrors(errors.New("Boom!"))
+	_, err := s.runner.RunDueWork()
+	c.Assert(err, gc.ErrorMatches, "Boom!")
+}
+
+type mockDueWorkBackend struct {
+	*testing.Stub
+	watchFails bool
+	next       time.Time
+}
+
+type dueWorkWatcher struct {
+	out     chan struct{}
+	backend *mockDueWorkBackend
+}
+
+func (w *dueWorkWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+func (w *dueWorkWatcher) Stop() error {
+	return nil
+}
+
+func (w *dueWorkWatcher) Kill() {
+}
+
+func (w *dueWorkWatcher) Wait() error {
+	return nil
+}
+
+func (w *dueWorkWatcher) Err() error {
+	return w.backend.NextErr()
+}
+
+func (b *mockDueWorkBackend) WatchDueWork() state.NotifyWatcher {
+	w := &dueWorkWatcher{
+		out:     make(chan struct{}, 1),
+		backend: b,
+	}
+	if b.watchFails {
+		close(w.out)
+	} else {
+		w.out <- struct{}{}
+	}
+	b.MethodCall(b, "WatchDueWork")
+	return w
+}
+
+func (b *mockDueWorkBackend) RunDueWork() (time.Time, error) {
+	b.MethodCall(b, "RunDueWork")
+	return b.next, b.NextErr()
+}
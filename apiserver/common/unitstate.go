@@ -126,6 +126,7 @@ func (u *UnitStateAPI) State(args params.Entities) (params.UnitStateResults, err
 		res[i].RelationState, _ = unitState.RelationState()
 		res[i].StorageState, _ = unitState.StorageState()
 		res[i].MeterStatusState, _ = unitState.MeterStatusState()
+		res[i].HookTraces, _ = unitState.HookTraces()
 	}
 
 	return params.UnitStateResults{Results: res}, nil
@@ -179,6 +180,9 @@ func (u *UnitStateAPI) SetState(args params.SetUnitStateArgs) (params.ErrorResul
 		if arg.MeterStatusState != nil {
 			unitState.SetMeterStatusState(*arg.MeterStatusState)
 		}
+		if arg.HookTraces != nil {
+			unitState.SetHookTraces(*arg.HookTraces)
+		}
 
 		ops := unit.SetStateOperation(
 			unitState,
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"regexp"
 	"strconv"
 	"syscall"
 	"time"
@@ -77,6 +78,13 @@ func newDebugLogHandler(
 //   excludeEntity -> []string - lists entity tags to exclude from the response
 //      - as with include, it may finish with a '*'
 //   excludeModule -> []string - lists logging modules to exclude from the response
+//   includeLocation -> []string - lists source locations to include in the response
+//      - locations are of the form file.go:123, or just file.go to match any line
+//   excludeLocation -> []string - lists source locations to exclude from the response
+//   includeMessage -> string - regular expression that messages must match
+//   startTime -> string - RFC3339 time, only send records logged at or after it
+//   endTime -> string - RFC3339 time, only send records logged before it
+//      - once the end time has passed, new logs are no longer waited for
 //   limit -> uint - show *at most* this many lines
 //   backlog -> uint
 //      - go back this many lines from the end before starting to filter
@@ -180,16 +188,20 @@ func (s *debugLogSocketImpl) sendLogRecord(record *params.LogMessage) error {
 
 // debugLogParams contains the parsed debuglog API request parameters.
 type debugLogParams struct {
-	startTime     time.Time
-	maxLines      uint
-	fromTheStart  bool
-	noTail        bool
-	backlog       uint
-	filterLevel   loggo.Level
-	includeEntity []string
-	excludeEntity []string
-	includeModule []string
-	excludeModule []string
+	startTime       time.Time
+	endTime         time.Time
+	maxLines        uint
+	fromTheStart    bool
+	noTail          bool
+	backlog         uint
+	filterLevel     loggo.Level
+	includeEntity   []string
+	excludeEntity   []string
+	includeModule   []string
+	excludeModule   []string
+	includeLocation []string
+	excludeLocation []string
+	includeMessage  string
 }
 
 func readDebugLogParams(queryMap url.Values) (debugLogParams, error) {
@@ -245,10 +257,30 @@ func readDebugLogParams(queryMap url.Values) (debugLogParams, error) {
 		params.startTime = startTime
 	}
 
+	if value := queryMap.Get("endTime"); value != "" {
+		endTime, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return params, errors.Errorf("end time %q is not a valid time in RFC3339 format", value)
+		}
+		if !params.startTime.IsZero() && !endTime.After(params.startTime) {
+			return params, errors.Errorf("end time %q is not after start time", value)
+		}
+		params.endTime = endTime
+	}
+
+	if value := queryMap.Get("includeMessage"); value != "" {
+		if _, err := regexp.Compile(value); err != nil {
+			return params, errors.Errorf("includeMessage value %q is not a valid regular expression", value)
+		}
+		params.includeMessage = value
+	}
+
 	params.includeEntity = queryMap["includeEntity"]
 	params.excludeEntity = queryMap["excludeEntity"]
 	params.includeModule = queryMap["includeModule"]
 	params.excludeModule = queryMap["excludeModule"]
+	params.includeLocation = queryMap["includeLocation"]
+	params.excludeLocation = queryMap["excludeLocation"]
 
 	return params, nil
 }
@@ -32,6 +32,18 @@ func handleDebugLogDBRequest(
 	stop <-chan struct{},
 ) error {
 	params := makeLogTailerParams(reqParams)
+
+	// There's nothing to wait for once the end of the requested time
+	// range has passed, and no point waiting beyond it otherwise.
+	if !reqParams.endTime.IsZero() {
+		remaining := reqParams.endTime.Sub(clock.Now())
+		if remaining <= 0 {
+			params.NoTail = true
+		} else if remaining < maxDuration {
+			maxDuration = remaining
+		}
+	}
+
 	tailer, err := newLogTailer(st, params)
 	if err != nil {
 		return errors.Trace(err)
@@ -69,14 +81,18 @@ func handleDebugLogDBRequest(
 
 func makeLogTailerParams(reqParams debugLogParams) state.LogTailerParams {
 	params := state.LogTailerParams{
-		MinLevel:      reqParams.filterLevel,
-		NoTail:        reqParams.noTail,
-		StartTime:     reqParams.startTime,
-		InitialLines:  int(reqParams.backlog),
-		IncludeEntity: reqParams.includeEntity,
-		ExcludeEntity: reqParams.excludeEntity,
-		IncludeModule: reqParams.includeModule,
-		ExcludeModule: reqParams.excludeModule,
+		MinLevel:        reqParams.filterLevel,
+		NoTail:          reqParams.noTail,
+		StartTime:       reqParams.startTime,
+		EndTime:         reqParams.endTime,
+		InitialLines:    int(reqParams.backlog),
+		IncludeEntity:   reqParams.includeEntity,
+		ExcludeEntity:   reqParams.excludeEntity,
+		IncludeModule:   reqParams.includeModule,
+		ExcludeModule:   reqParams.excludeModule,
+		IncludeLocation: reqParams.includeLocation,
+		ExcludeLocation: reqParams.excludeLocation,
+		IncludeMessage:  reqParams.includeMessage,
 	}
 	if reqParams.fromTheStart {
 		params.InitialLines = 0
@@ -5,6 +5,7 @@ package apiserver
 
 import (
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/juju/clock/testclock"
@@ -37,15 +38,18 @@ func (s *debugLogDBIntSuite) SetUpTest(c *gc.C) {
 func (s *debugLogDBIntSuite) TestParamConversion(c *gc.C) {
 	t1 := time.Date(2016, 11, 30, 10, 51, 0, 0, time.UTC)
 	reqParams := debugLogParams{
-		fromTheStart:  false,
-		noTail:        true,
-		backlog:       11,
-		startTime:     t1,
-		filterLevel:   loggo.INFO,
-		includeEntity: []string{"foo"},
-		includeModule: []string{"bar"},
-		excludeEntity: []string{"baz"},
-		excludeModule: []string{"qux"},
+		fromTheStart:    false,
+		noTail:          true,
+		backlog:         11,
+		startTime:       t1,
+		filterLevel:     loggo.INFO,
+		includeEntity:   []string{"foo"},
+		includeModule:   []string{"bar"},
+		excludeEntity:   []string{"baz"},
+		excludeModule:   []string{"qux"},
+		includeLocation: []string{"uniter.go"},
+		excludeLocation: []string{"runner.go:12"},
+		includeMessage:  "hook failed",
 	}
 
 	called := false
@@ -62,6 +66,10 @@ func (s *debugLogDBIntSuite) TestParamConversion(c *gc.C) {
 		c.Assert(params.IncludeModule, jc.DeepEquals, []string{"bar"})
 		c.Assert(params.ExcludeEntity, jc.DeepEquals, []string{"baz"})
 		c.Assert(params.ExcludeModule, jc.DeepEquals, []string{"qux"})
+		c.Assert(params.IncludeLocation, jc.DeepEquals, []string{"uniter.go"})
+		c.Assert(params.ExcludeLocation, jc.DeepEquals, []string{"runner.go:12"})
+		c.Assert(params.IncludeMessage, gc.Equals, "hook failed")
+		c.Assert(params.EndTime.IsZero(), jc.IsTrue)
 
 		return newFakeLogTailer(), nil
 	})
@@ -96,6 +104,51 @@ func (s *debugLogDBIntSuite) TestParamConversionReplay(c *gc.C) {
 	c.Assert(called, jc.IsTrue)
 }
 
+func (s *debugLogDBIntSuite) TestParamConversionPastEndTime(c *gc.C) {
+	reqParams := debugLogParams{
+		endTime: s.clock.Now().Add(-time.Second),
+	}
+
+	called := false
+	s.PatchValue(&newLogTailer, func(_ state.LogTailerState, params state.LogTailerParams) (state.LogTailer, error) {
+		called = true
+
+		c.Assert(params.EndTime, gc.Equals, reqParams.endTime)
+		// The end of the range has passed, so there's nothing to tail.
+		c.Assert(params.NoTail, jc.IsTrue)
+
+		return newFakeLogTailer(), nil
+	})
+
+	stop := make(chan struct{})
+	close(stop) // Stop the request immediately.
+	err := handleDebugLogDBRequest(s.clock, s.timeout, nil, reqParams, s.sock, stop)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *debugLogDBIntSuite) TestEndTimeLimitsDuration(c *gc.C) {
+	tailer := newFakeLogTailer()
+	s.PatchValue(&newLogTailer, func(_ state.LogTailerState, params state.LogTailerParams) (state.LogTailer, error) {
+		c.Assert(params.NoTail, jc.IsFalse)
+		return tailer, nil
+	})
+
+	reqParams := debugLogParams{
+		endTime: s.clock.Now().Add(s.timeout / 2),
+	}
+	done := s.runRequest(reqParams, nil)
+	s.assertOutput(c, []string{"ok"})
+
+	s.assertRunning(c, done, tailer)
+	err := s.clock.WaitAdvance(s.timeout/2, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The request finishes once the end time is reached, before the
+	// maximum duration.
+	s.assertStops(c, done, tailer)
+}
+
 func (s *debugLogDBIntSuite) TestFullRequest(c *gc.C) {
 	// Set up a fake log tailer with a 2 log records ready to send.
 	tailer := newFakeLogTailer()
@@ -214,6 +267,45 @@ func (s *debugLogDBIntSuite) TestMaxLines(c *gc.C) {
 	s.assertStops(c, done, tailer)
 }
 
+func (s *debugLogDBIntSuite) TestReadDebugLogParams(c *gc.C) {
+	params, err := readDebugLogParams(url.Values{
+		"startTime":       {"2016-11-30T10:51:00Z"},
+		"endTime":         {"2016-11-30T11:51:00.5Z"},
+		"includeLocation": {"uniter.go", "runner.go:12"},
+		"excludeLocation": {"agent.go"},
+		"includeMessage":  {"^hook .* failed$"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(params.startTime, gc.Equals, time.Date(2016, 11, 30, 10, 51, 0, 0, time.UTC))
+	c.Check(params.endTime, gc.Equals, time.Date(2016, 11, 30, 11, 51, 0, 500000000, time.UTC))
+	c.Check(params.includeLocation, jc.DeepEquals, []string{"uniter.go", "runner.go:12"})
+	c.Check(params.excludeLocation, jc.DeepEquals, []string{"agent.go"})
+	c.Check(params.includeMessage, gc.Equals, "^hook .* failed$")
+}
+
+func (s *debugLogDBIntSuite) TestReadDebugLogParamsErrors(c *gc.C) {
+	for i, test := range []struct {
+		query url.Values
+		err   string
+	}{{
+		query: url.Values{"endTime": {"yesterday"}},
+		err:   `end time "yesterday" is not a valid time in RFC3339 format`,
+	}, {
+		query: url.Values{
+			"startTime": {"2016-11-30T10:51:00Z"},
+			"endTime":   {"2016-11-30T10:51:00Z"},
+		},
+		err: `end time "2016-11-30T10:51:00Z" is not after start time`,
+	}, {
+		query: url.Values{"includeMessage": {"hook ("}},
+		err:   `includeMessage value "hook \(" is not a valid regular expression`,
+	}} {
+		c.Logf("test %d: %v", i, test.query)
+		_, err := readDebugLogParams(test.query)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
 func (s *debugLogDBIntSuite) runRequest(params debugLogParams, stop chan struct{}) chan error {
 	done := make(chan error)
 	go func() {
@@ -211,4 +211,5 @@ type ModelPresence interface {
 // Hub represents the central hub that the API server has.
 type Hub interface {
 	Publish(topic string, data interface{}) (<-chan struct{}, error)
+	Subscribe(topic string, handler interface{}) (func(), error)
 }
@@ -17,11 +17,18 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/life"
 	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/core/secrets"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/watcher"
 )
 
+// AgentAPIV3 implements the version 3 of the API provided to an agent.
+type AgentAPIV3 struct {
+	*AgentAPIV2
+	dataDir string
+}
+
 // AgentAPIV2 implements the version 2 of the API provided to an agent.
 type AgentAPIV2 struct {
 	*common.PasswordChanger
@@ -35,6 +42,23 @@ type AgentAPIV2 struct {
 	resources facade.Resources
 }
 
+// NewAgentAPIV3 returns an object implementing version 3 of the Agent API
+// with the given authorizer representing the currently logged in client.
+func NewAgentAPIV3(st *state.State, resources facade.Resources, auth facade.Authorizer) (*AgentAPIV3, error) {
+	api, err := NewAgentAPIV2(st, resources, auth)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	dataDir, ok := resources.Get("dataDir").(common.StringResource)
+	if !ok {
+		return nil, errors.New("data directory not available")
+	}
+	return &AgentAPIV3{
+		AgentAPIV2: api,
+		dataDir:    dataDir.String(),
+	}, nil
+}
+
 // NewAgentAPIV2 returns an object implementing version 2 of the Agent API
 // with the given authorizer representing the currently logged in client.
 func NewAgentAPIV2(st *state.State, resources facade.Resources, auth facade.Authorizer) (*AgentAPIV2, error) {
@@ -54,7 +78,7 @@ func NewAgentAPIV2(st *state.State, resources facade.Resources, auth facade.Auth
 		PasswordChanger:     common.NewPasswordChanger(st, getCanChange),
 		RebootFlagClearer:   common.NewRebootFlagClearer(st, getCanChange),
 		ModelWatcher:        common.NewModelWatcher(model, resources, auth),
-		ControllerConfigAPI: common.NewStateControllerConfig(st),
+		ControllerConfigAPI: common.NewStateControllerConfig(st, auth),
 		CloudSpecAPI: cloudspec.NewCloudSpec(
 			resources,
 			cloudspec.MakeCloudSpecGetterForModel(st),
@@ -140,6 +164,23 @@ func (api *AgentAPIV2) StateServingInfo() (result params.StateServingInfo, err e
 	return result, nil
 }
 
+// ControllerSecretKeys returns the keys the controller's secret values
+// are encrypted with, so that a controller machine being added can
+// store them locally. Only controller agents may call it.
+func (api *AgentAPIV3) ControllerSecretKeys() (params.SecretKeysResult, error) {
+	if !api.auth.AuthController() {
+		return params.SecretKeysResult{}, apiservererrors.ErrPerm
+	}
+	keys, err := secrets.ReadKeys(secrets.KeysDir(api.dataDir))
+	if err != nil {
+		return params.SecretKeysResult{}, errors.Trace(err)
+	}
+	return params.SecretKeysResult{Keys: keys}, nil
+}
+
+// ControllerSecretKeys isn't on the v2 API.
+func (*AgentAPIV2) ControllerSecretKeys(_, _ struct{}) {}
+
 // MongoIsMaster is called by the IsMaster API call
 // instead of mongo.IsMaster. It exists so it can
 // be overridden by tests.
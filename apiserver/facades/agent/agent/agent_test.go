@@ -17,6 +17,7 @@ import (
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/core/secrets"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
@@ -276,3 +277,31 @@ func (s *agentSuite) TestWatchAuthError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 	c.Assert(s.resources.Count(), gc.Equals, 0)
 }
+
+func (s *agentSuite) TestControllerSecretKeys(c *gc.C) {
+	dataDir := c.MkDir()
+	keysDir := secrets.KeysDir(dataDir)
+	err := secrets.EnsureKeys(keysDir)
+	c.Assert(err, jc.ErrorIsNil)
+	keys, err := secrets.ReadKeys(keysDir)
+	c.Assert(err, jc.ErrorIsNil)
+	s.resources.RegisterNamed("dataDir", common.StringResource(dataDir))
+
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag:        names.NewMachineTag("0"),
+		Controller: true,
+	}
+	api, err := agent.NewAgentAPIV3(s.State, s.resources, authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.ControllerSecretKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.SecretKeysResult{Keys: keys})
+}
+
+func (s *agentSuite) TestControllerSecretKeysAuthError(c *gc.C) {
+	s.resources.RegisterNamed("dataDir", common.StringResource(c.MkDir()))
+	api, err := agent.NewAgentAPIV3(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.ControllerSecretKeys()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
@@ -0,0 +1,298 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package secretsmanager provides the API for units to create and
+// update secrets, read them, and grant other applications and units
+// access to them.
+package secretsmanager
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/leadership"
+	"github.com/juju/juju/core/secrets"
+	"github.com/juju/juju/state"
+)
+
+// Backend contains the state.State methods used in this package,
+// allowing stubs to be created for testing.
+type Backend interface {
+	CreateSecret(*secrets.URI, state.CreateSecretParams) (*secrets.SecretMetadata, error)
+	UpdateSecret(*secrets.URI, state.UpdateSecretParams) (*secrets.SecretMetadata, error)
+	GetSecret(*secrets.URI) (*secrets.SecretMetadata, error)
+	GetSecretValue(*secrets.URI, int) (secrets.SecretData, error)
+	GrantSecretAccess(*secrets.URI, names.Tag) error
+	RevokeSecretAccess(*secrets.URI, names.Tag) error
+	HasSecretAccess(*secrets.URI, names.Tag) (bool, error)
+	GetSecretConsumer(*secrets.URI, names.Tag) (int, error)
+	SaveSecretConsumer(*secrets.URI, names.Tag, int) error
+}
+
+// API implements the SecretsManager facade.
+type API struct {
+	backend           Backend
+	leadershipChecker leadership.Checker
+	unitTag           names.UnitTag
+	applicationTag    names.ApplicationTag
+}
+
+// NewFacade is used for API registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	leadershipChecker, err := ctx.LeadershipChecker()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewAPI(ctx.State(), leadershipChecker, ctx.Auth())
+}
+
+// NewAPI returns a new SecretsManager API facade.
+func NewAPI(backend Backend, leadershipChecker leadership.Checker, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthUnitAgent() {
+		return nil, apiservererrors.ErrPerm
+	}
+	unitTag, ok := authorizer.GetAuthTag().(names.UnitTag)
+	if !ok {
+		return nil, apiservererrors.ErrPerm
+	}
+	appName, err := names.UnitApplication(unitTag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &API{
+		backend:           backend,
+		leadershipChecker: leadershipChecker,
+		unitTag:           unitTag,
+		applicationTag:    names.NewApplicationTag(appName),
+	}, nil
+}
+
+// checkCanManage returns an error unless the unit may update the secrets
+// owned by the given owner, and grant access to them: the unit must be
+// the owner, or the leader of the application that is.
+func (api *API) checkCanManage(owner names.Tag) error {
+	switch owner {
+	case api.unitTag:
+		return nil
+	case api.applicationTag:
+		token := api.leadershipChecker.LeadershipCheck(api.applicationTag.Id(), api.unitTag.Id())
+		return errors.Trace(token.Check(0, nil))
+	}
+	return apiservererrors.ErrPerm
+}
+
+// isOwner reports whether the secret is owned by the unit, or by its
+// application.
+func (api *API) isOwner(md *secrets.SecretMetadata) bool {
+	return md.OwnerTag == api.unitTag || md.OwnerTag == api.applicationTag
+}
+
+// CreateSecrets creates secrets owned by the calling unit, or its
+// application, and returns their URIs.
+func (api *API) CreateSecrets(args params.CreateSecretArgs) (params.StringResults, error) {
+	result := params.StringResults{
+		Results: make([]params.StringResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		uri, err := api.createSecret(arg)
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = uri.String()
+	}
+	return result, nil
+}
+
+func (api *API) createSecret(arg params.CreateSecretArg) (*secrets.URI, error) {
+	owner, err := names.ParseTag(arg.OwnerTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := api.checkCanManage(owner); err != nil {
+		return nil, errors.Trace(err)
+	}
+	uri := secrets.NewURI()
+	_, err = api.backend.CreateSecret(uri, state.CreateSecretParams{
+		Owner:        owner,
+		Description:  arg.Description,
+		RotatePolicy: secrets.RotatePolicy(arg.RotatePolicy),
+		ExpireTime:   arg.ExpireTime,
+		Data:         arg.Data,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return uri, nil
+}
+
+// UpdateSecrets updates secrets owned by the calling unit, or its
+// application.
+func (api *API) UpdateSecrets(args params.UpdateSecretArgs) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		err := api.updateSecret(arg)
+		result.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *API) updateSecret(arg params.UpdateSecretArg) error {
+	uri, err := api.managedSecret(arg.URI)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	p := state.UpdateSecretParams{
+		Description: arg.Description,
+		ExpireTime:  arg.ExpireTime,
+		Data:        arg.Data,
+	}
+	if arg.RotatePolicy != nil {
+		policy := secrets.RotatePolicy(*arg.RotatePolicy)
+		p.RotatePolicy = &policy
+	}
+	_, err = api.backend.UpdateSecret(uri, p)
+	return errors.Trace(err)
+}
+
+// managedSecret parses the URI of a secret, and returns an error unless
+// the unit may manage the secret.
+func (api *API) managedSecret(uriStr string) (*secrets.URI, error) {
+	uri, err := secrets.ParseURI(uriStr)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	md, err := api.backend.GetSecret(uri)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := api.checkCanManage(md.OwnerTag); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return uri, nil
+}
+
+// GetSecretValues returns the values of secrets. Owners always get the
+// latest revision of a secret. Other units get the revision they got
+// first, until they refresh it.
+func (api *API) GetSecretValues(args params.GetSecretValueArgs) (params.SecretValueResults, error) {
+	result := params.SecretValueResults{
+		Results: make([]params.SecretValueResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		revision, data, err := api.getSecretValue(arg)
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		result.Results[i].Revision = revision
+		result.Results[i].Data = data
+	}
+	return result, nil
+}
+
+func (api *API) getSecretValue(arg params.GetSecretValueArg) (int, secrets.SecretData, error) {
+	uri, err := secrets.ParseURI(arg.URI)
+	if err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	md, err := api.backend.GetSecret(uri)
+	if errors.IsNotFound(err) {
+		// Don't reveal which secrets exist to units without access.
+		return 0, nil, apiservererrors.ErrPerm
+	} else if err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	revision, err := api.consumedRevision(md, arg.Peek, arg.Refresh)
+	if err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	data, err := api.backend.GetSecretValue(uri, revision)
+	if err != nil {
+		return 0, nil, errors.Trace(err)
+	}
+	return revision, data, nil
+}
+
+// consumedRevision returns the revision of the secret the unit should
+// get, recording it as the revision the unit is using if need be.
+func (api *API) consumedRevision(md *secrets.SecretMetadata, peek, refresh bool) (int, error) {
+	if api.isOwner(md) {
+		return md.LatestRevision, nil
+	}
+	var canRead bool
+	for _, subject := range []names.Tag{api.unitTag, api.applicationTag} {
+		granted, err := api.backend.HasSecretAccess(md.URI, subject)
+		if err != nil {
+			return 0, errors.Trace(err)
+		}
+		canRead = canRead || granted
+	}
+	if !canRead {
+		return 0, apiservererrors.ErrPerm
+	}
+	if peek {
+		return md.LatestRevision, nil
+	}
+	current, err := api.backend.GetSecretConsumer(md.URI, api.unitTag)
+	if err != nil && !errors.IsNotFound(err) {
+		return 0, errors.Trace(err)
+	}
+	if err == nil && !refresh {
+		return current, nil
+	}
+	if err := api.backend.SaveSecretConsumer(md.URI, api.unitTag, md.LatestRevision); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return md.LatestRevision, nil
+}
+
+// GrantSecrets grants applications and units access to secrets
+// managed by the calling unit.
+func (api *API) GrantSecrets(args params.GrantRevokeSecretArgs) (params.ErrorResults, error) {
+	return api.grantOrRevoke(args, api.backend.GrantSecretAccess)
+}
+
+// RevokeSecrets revokes the access of applications and units to
+// secrets managed by the calling unit.
+func (api *API) RevokeSecrets(args params.GrantRevokeSecretArgs) (params.ErrorResults, error) {
+	return api.grantOrRevoke(args, api.backend.RevokeSecretAccess)
+}
+
+func (api *API) grantOrRevoke(args params.GrantRevokeSecretArgs, op func(*secrets.URI, names.Tag) error) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	for i, arg := range args.Args {
+		err := api.grantOrRevokeOne(arg, op)
+		result.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *API) grantOrRevokeOne(arg params.GrantRevokeSecretArg, op func(*secrets.URI, names.Tag) error) error {
+	uri, err := api.managedSecret(arg.URI)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, tagStr := range arg.SubjectTags {
+		subject, err := names.ParseTag(tagStr)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		switch subject.Kind() {
+		case names.ApplicationTagKind, names.UnitTagKind:
+		default:
+			return errors.NotValidf("secret subject %q", tagStr)
+		}
+		if err := op(uri, subject); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,337 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secretsmanager_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/agent/secretsmanager"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/leadership"
+	"github.com/juju/juju/core/secrets"
+	"github.com/juju/juju/state"
+)
+
+type secretsManagerSuite struct {
+	testing.IsolationSuite
+	backend  *mockBackend
+	isLeader bool
+
+	uri *secrets.URI
+}
+
+var _ = gc.Suite(&secretsManagerSuite{})
+
+var (
+	unitTag  = names.NewUnitTag("mysql/0")
+	appTag   = names.NewApplicationTag("mysql")
+	otherTag = names.NewUnitTag("wordpress/0")
+)
+
+func (s *secretsManagerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.isLeader = true
+	s.uri = secrets.NewURI()
+	s.backend = &mockBackend{
+		metadata: &secrets.SecretMetadata{
+			URI:            s.uri,
+			OwnerTag:       appTag,
+			LatestRevision: 3,
+		},
+		data: secrets.SecretData{"password": "s3cret"},
+	}
+}
+
+func (s *secretsManagerSuite) newAPI(c *gc.C, tag names.Tag) *secretsmanager.API {
+	api, err := secretsmanager.NewAPI(s.backend, s, apiservertesting.FakeAuthorizer{Tag: tag})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+// LeadershipCheck is part of leadership.Checker.
+func (s *secretsManagerSuite) LeadershipCheck(applicationName, unitName string) leadership.Token {
+	return token{isLeader: s.isLeader, unit: unitName, application: applicationName}
+}
+
+type token struct {
+	isLeader          bool
+	unit, application string
+}
+
+func (t token) Check(int, interface{}) error {
+	if !t.isLeader {
+		return leadership.NewNotLeaderError(t.unit, t.application)
+	}
+	return nil
+}
+
+func (s *secretsManagerSuite) TestNewAPIRequiresUnit(c *gc.C) {
+	_, err := secretsmanager.NewAPI(s.backend, s, apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *secretsManagerSuite) TestCreateSecrets(c *gc.C) {
+	api := s.newAPI(c, unitTag)
+	results, err := api.CreateSecrets(params.CreateSecretArgs{Args: []params.CreateSecretArg{{
+		OwnerTag:     appTag.String(),
+		Description:  "db password",
+		RotatePolicy: "daily",
+		Data:         map[string]string{"password": "s3cret"},
+	}, {
+		OwnerTag: unitTag.String(),
+		Data:     map[string]string{"password": "s3cret"},
+	}, {
+		OwnerTag: otherTag.String(),
+		Data:     map[string]string{"password": "s3cret"},
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	for _, result := range results.Results[:2] {
+		c.Assert(result.Error, gc.IsNil)
+		_, err := secrets.ParseURI(result.Result)
+		c.Check(err, jc.ErrorIsNil)
+	}
+	c.Check(results.Results[2].Error, jc.DeepEquals, &params.Error{
+		Message: "permission denied", Code: params.CodeUnauthorized,
+	})
+
+	s.backend.CheckCallNames(c, "CreateSecret", "CreateSecret")
+	args := s.backend.Calls()[0].Args
+	c.Check(args[1], jc.DeepEquals, state.CreateSecretParams{
+		Owner:        appTag,
+		Description:  "db password",
+		RotatePolicy: secrets.RotateDaily,
+		Data:         secrets.SecretData{"password": "s3cret"},
+	})
+}
+
+func (s *secretsManagerSuite) TestCreateSecretsNotLeader(c *gc.C) {
+	s.isLeader = false
+	api := s.newAPI(c, unitTag)
+	results, err := api.CreateSecrets(params.CreateSecretArgs{Args: []params.CreateSecretArg{{
+		OwnerTag: appTag.String(),
+		Data:     map[string]string{"password": "s3cret"},
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `"mysql/0" is not leader of "mysql"`)
+	s.backend.CheckNoCalls(c)
+}
+
+func (s *secretsManagerSuite) TestUpdateSecrets(c *gc.C) {
+	api := s.newAPI(c, unitTag)
+	description := "new password"
+	policy := "hourly"
+	results, err := api.UpdateSecrets(params.UpdateSecretArgs{Args: []params.UpdateSecretArg{{
+		URI:          s.uri.String(),
+		Description:  &description,
+		RotatePolicy: &policy,
+		Data:         map[string]string{"password": "n3w"},
+	}, {
+		URI: "secret:nope",
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `secret URI "secret:nope" not valid`)
+
+	hourly := secrets.RotateHourly
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"GetSecret", []interface{}{s.uri}},
+		{"UpdateSecret", []interface{}{s.uri, state.UpdateSecretParams{
+			Description:  &description,
+			RotatePolicy: &hourly,
+			Data:         secrets.SecretData{"password": "n3w"},
+		}}},
+	})
+}
+
+func (s *secretsManagerSuite) TestUpdateSecretsNotOwner(c *gc.C) {
+	api := s.newAPI(c, otherTag)
+	results, err := api.UpdateSecrets(params.UpdateSecretArgs{Args: []params.UpdateSecretArg{{
+		URI:  s.uri.String(),
+		Data: map[string]string{"password": "n3w"},
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "GetSecret")
+}
+
+func (s *secretsManagerSuite) getValue(c *gc.C, tag names.Tag, arg params.GetSecretValueArg) params.SecretValueResult {
+	api := s.newAPI(c, tag)
+	arg.URI = s.uri.String()
+	results, err := api.GetSecretValues(params.GetSecretValueArgs{Args: []params.GetSecretValueArg{arg}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	return results.Results[0]
+}
+
+func (s *secretsManagerSuite) TestGetSecretValuesOwner(c *gc.C) {
+	s.isLeader = false
+	result := s.getValue(c, names.NewUnitTag("mysql/1"), params.GetSecretValueArg{})
+	c.Assert(result, jc.DeepEquals, params.SecretValueResult{
+		Revision: 3,
+		Data:     map[string]string{"password": "s3cret"},
+	})
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"GetSecret", []interface{}{s.uri}},
+		{"GetSecretValue", []interface{}{s.uri, 3}},
+	})
+}
+
+func (s *secretsManagerSuite) TestGetSecretValuesNoAccess(c *gc.C) {
+	result := s.getValue(c, otherTag, params.GetSecretValueArg{})
+	c.Assert(result.Error, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "GetSecret", "HasSecretAccess", "HasSecretAccess")
+}
+
+func (s *secretsManagerSuite) TestGetSecretValuesNotFound(c *gc.C) {
+	s.backend.SetErrors(errors.NotFoundf("secret"))
+	result := s.getValue(c, otherTag, params.GetSecretValueArg{})
+	c.Assert(result.Error, gc.ErrorMatches, "permission denied")
+}
+
+func (s *secretsManagerSuite) TestGetSecretValuesFirstRead(c *gc.C) {
+	s.backend.granted = names.NewApplicationTag("wordpress")
+	s.backend.SetErrors(nil, nil, nil, errors.NotFoundf("consumer"))
+	result := s.getValue(c, otherTag, params.GetSecretValueArg{})
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Revision, gc.Equals, 3)
+	s.backend.CheckCallNames(c, "GetSecret", "HasSecretAccess", "HasSecretAccess",
+		"GetSecretConsumer", "SaveSecretConsumer", "GetSecretValue")
+	s.backend.CheckCall(c, 4, "SaveSecretConsumer", s.uri, otherTag, 3)
+}
+
+func (s *secretsManagerSuite) TestGetSecretValuesTracked(c *gc.C) {
+	s.backend.granted = otherTag
+	s.backend.consumed = 2
+	result := s.getValue(c, otherTag, params.GetSecretValueArg{})
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Revision, gc.Equals, 2)
+	s.backend.CheckCallNames(c, "GetSecret", "HasSecretAccess", "HasSecretAccess",
+		"GetSecretConsumer", "GetSecretValue")
+	s.backend.CheckCall(c, 4, "GetSecretValue", s.uri, 2)
+}
+
+func (s *secretsManagerSuite) TestGetSecretValuesRefresh(c *gc.C) {
+	s.backend.granted = otherTag
+	s.backend.consumed = 2
+	result := s.getValue(c, otherTag, params.GetSecretValueArg{Refresh: true})
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Revision, gc.Equals, 3)
+	s.backend.CheckCall(c, 4, "SaveSecretConsumer", s.uri, otherTag, 3)
+}
+
+func (s *secretsManagerSuite) TestGetSecretValuesPeek(c *gc.C) {
+	s.backend.granted = otherTag
+	s.backend.consumed = 2
+	result := s.getValue(c, otherTag, params.GetSecretValueArg{Peek: true})
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Revision, gc.Equals, 3)
+	s.backend.CheckCallNames(c, "GetSecret", "HasSecretAccess", "HasSecretAccess", "GetSecretValue")
+}
+
+func (s *secretsManagerSuite) TestGrantSecrets(c *gc.C) {
+	api := s.newAPI(c, unitTag)
+	results, err := api.GrantSecrets(params.GrantRevokeSecretArgs{Args: []params.GrantRevokeSecretArg{{
+		URI:         s.uri.String(),
+		SubjectTags: []string{"application-wordpress", "unit-wordpress-0"},
+	}, {
+		URI:         s.uri.String(),
+		SubjectTags: []string{"machine-0"},
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `secret subject "machine-0" not valid`)
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"GetSecret", []interface{}{s.uri}},
+		{"GrantSecretAccess", []interface{}{s.uri, names.NewApplicationTag("wordpress")}},
+		{"GrantSecretAccess", []interface{}{s.uri, otherTag}},
+		{"GetSecret", []interface{}{s.uri}},
+	})
+}
+
+func (s *secretsManagerSuite) TestRevokeSecretsNotLeader(c *gc.C) {
+	s.isLeader = false
+	api := s.newAPI(c, unitTag)
+	results, err := api.RevokeSecrets(params.GrantRevokeSecretArgs{Args: []params.GrantRevokeSecretArg{{
+		URI:         s.uri.String(),
+		SubjectTags: []string{"application-wordpress"},
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `"mysql/0" is not leader of "mysql"`)
+	s.backend.CheckCallNames(c, "GetSecret")
+}
+
+func (s *secretsManagerSuite) TestRevokeSecrets(c *gc.C) {
+	api := s.newAPI(c, unitTag)
+	results, err := api.RevokeSecrets(params.GrantRevokeSecretArgs{Args: []params.GrantRevokeSecretArg{{
+		URI:         s.uri.String(),
+		SubjectTags: []string{"application-wordpress"},
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	s.backend.CheckCall(c, 1, "RevokeSecretAccess", s.uri, names.NewApplicationTag("wordpress"))
+}
+
+type mockBackend struct {
+	testing.Stub
+	metadata *secrets.SecretMetadata
+	data     secrets.SecretData
+	granted  names.Tag
+	consumed int
+}
+
+func (b *mockBackend) CreateSecret(uri *secrets.URI, p state.CreateSecretParams) (*secrets.SecretMetadata, error) {
+	b.MethodCall(b, "CreateSecret", uri, p)
+	return b.metadata, b.NextErr()
+}
+
+func (b *mockBackend) UpdateSecret(uri *secrets.URI, p state.UpdateSecretParams) (*secrets.SecretMetadata, error) {
+	b.MethodCall(b, "UpdateSecret", uri, p)
+	return b.metadata, b.NextErr()
+}
+
+func (b *mockBackend) GetSecret(uri *secrets.URI) (*secrets.SecretMetadata, error) {
+	b.MethodCall(b, "GetSecret", uri)
+	if err := b.NextErr(); err != nil {
+		return nil, err
+	}
+	return b.metadata, nil
+}
+
+func (b *mockBackend) GetSecretValue(uri *secrets.URI, revision int) (secrets.SecretData, error) {
+	b.MethodCall(b, "GetSecretValue", uri, revision)
+	return b.data, b.NextErr()
+}
+
+func (b *mockBackend) GrantSecretAccess(uri *secrets.URI, subject names.Tag) error {
+	b.MethodCall(b, "GrantSecretAccess", uri, subject)
+	return b.NextErr()
+}
+
+func (b *mockBackend) RevokeSecretAccess(uri *secrets.URI, subject names.Tag) error {
+	b.MethodCall(b, "RevokeSecretAccess", uri, subject)
+	return b.NextErr()
+}
+
+func (b *mockBackend) HasSecretAccess(uri *secrets.URI, subject names.Tag) (bool, error) {
+	b.MethodCall(b, "HasSecretAccess", uri, subject)
+	return subject == b.granted, b.NextErr()
+}
+
+func (b *mockBackend) GetSecretConsumer(uri *secrets.URI, consumer names.Tag) (int, error) {
+	b.MethodCall(b, "GetSecretConsumer", uri, consumer)
+	return b.consumed, b.NextErr()
+}
+
+func (b *mockBackend) SaveSecretConsumer(uri *secrets.URI, consumer names.Tag, revision int) error {
+	b.MethodCall(b, "SaveSecretConsumer", uri, consumer, revision)
+	return b.NextErr()
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/caas"
 	k8sspecs "github.com/juju/juju/caas/kubernetes/provider/specs"
+	coreapplication "github.com/juju/juju/core/application"
 	"github.com/juju/juju/core/cache"
 	"github.com/juju/juju/core/leadership"
 	"github.com/juju/juju/core/life"
@@ -42,9 +43,8 @@ var logger = loggo.GetLogger("juju.apiserver.uniter")
 // TODO (manadart 2020-10-21): Remove the ModelUUID method
 // from the next version of this facade.
 
-// UniterAPI implements the latest version (v17) of the Uniter API, which
-// augments the payload of the CommitHookChanges API call and introduces
-// the OpenedMachinePortRanges call as a replacement for AllMachinePorts.
+// UniterAPI implements the latest version (v18) of the Uniter API, which
+// adds HookLimits.
 type UniterAPI struct {
 	*common.LifeGetter
 	*StatusAPI
@@ -83,10 +83,17 @@ type UniterAPI struct {
 	cloudSpec       cloudspec.CloudSpecAPI
 }
 
+// UniterAPIV17 implements version (v17) of the Uniter API, which
+// augments the payload of the CommitHookChanges API call and introduces
+// the OpenedMachinePortRanges call as a replacement for AllMachinePorts.
+type UniterAPIV17 struct {
+	UniterAPI
+}
+
 // UniterAPIV16 implements version (v16) of the Uniter API, which adds
 // LXDProfileAPIV2.
 type UniterAPIV16 struct {
-	UniterAPI
+	UniterAPIV17
 }
 
 // UniterAPIV15 implements version (v15) of the Uniter API, which adds
@@ -262,14 +269,25 @@ func NewUniterAPI(context facade.Context) (*UniterAPI, error) {
 	}, nil
 }
 
+// NewUniterAPIV17 creates an instance of the V17 uniter API.
+func NewUniterAPIV17(context facade.Context) (*UniterAPIV17, error) {
+	uniterAPI, err := NewUniterAPI(context)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV17{
+		UniterAPI: *uniterAPI,
+	}, nil
+}
+
 // NewUniterAPIV16 creates an instance of the V16 uniter API.
 func NewUniterAPIV16(context facade.Context) (*UniterAPIV16, error) {
-	uniterAPI, err := NewUniterAPI(context)
+	uniterAPI, err := NewUniterAPIV17(context)
 	if err != nil {
 		return nil, err
 	}
 	return &UniterAPIV16{
-		UniterAPI: *uniterAPI,
+		UniterAPIV17: *uniterAPI,
 	}, nil
 }
 
@@ -711,6 +729,63 @@ func (u *UniterAPI) Resolved(args params.Entities) (params.ResolvedModeResults,
 	return result, nil
 }
 
+// HookLimits isn't on the v17 API.
+func (u *UniterAPIV17) HookLimits(_ struct{}) {}
+
+// HookLimits isn't on the v15 API.
+func (u *UniterAPIV15) HookLimits(_ struct{}) {}
+
+// HookLimits returns the limits placed on the hooks and actions run by
+// each given unit, as set in its application's config.
+func (u *UniterAPI) HookLimits(args params.Entities) (params.HookLimitsResults, error) {
+	result := params.HookLimitsResults{
+		Results: make([]params.HookLimitsResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.HookLimitsResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(apiservererrors.ErrPerm)
+			continue
+		}
+		if !canAccess(tag) {
+			result.Results[i].Error = apiservererrors.ServerError(apiservererrors.ErrPerm)
+			continue
+		}
+		limits, err := u.hookLimits(tag)
+		if err != nil {
+			result.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = params.HookLimits{
+			HookTimeout:   limits.HookTimeout,
+			ActionTimeout: limits.ActionTimeout,
+			CPUQuota:      limits.CPUQuota,
+			MemoryLimit:   limits.MemoryLimit,
+		}
+	}
+	return result, nil
+}
+
+func (u *UniterAPI) hookLimits(tag names.UnitTag) (coreapplication.HookLimits, error) {
+	unit, err := u.getUnit(tag)
+	if err != nil {
+		return coreapplication.HookLimits{}, err
+	}
+	app, err := unit.Application()
+	if err != nil {
+		return coreapplication.HookLimits{}, err
+	}
+	cfg, err := app.ApplicationConfig()
+	if err != nil {
+		return coreapplication.HookLimits{}, err
+	}
+	return coreapplication.ParseHookLimits(cfg)
+}
+
 // ClearResolved removes any resolved setting from each given unit.
 func (u *UniterAPI) ClearResolved(args params.Entities) (params.ErrorResults, error) {
 	result := params.ErrorResults{
@@ -3724,6 +3799,9 @@ func (u *UniterAPI) commitHookChangesForOneUnit(unitTag names.UnitTag, changes p
 		if changes.SetUnitState.MeterStatusState != nil {
 			newUS.SetMeterStatusState(*changes.SetUnitState.MeterStatusState)
 		}
+		if changes.SetUnitState.HookTraces != nil {
+			newUS.SetHookTraces(*changes.SetUnitState.HookTraces)
+		}
 
 		modelOp := unit.SetStateOperation(
 			newUS,
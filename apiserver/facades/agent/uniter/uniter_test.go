@@ -705,6 +705,36 @@ func (s *uniterSuite) TestResolvedAPIV6(c *gc.C) {
 	})
 }
 
+func (s *uniterSuite) TestHookLimits(c *gc.C) {
+	schema := environschema.Fields{
+		"hook-timeout":      environschema.Attr{Type: environschema.Tstring},
+		"hook-memory-limit": environschema.Attr{Type: environschema.Tstring},
+	}
+	err := s.wordpress.UpdateApplicationConfig(coreapplication.ConfigAttributes{
+		"hook-timeout":      "30m",
+		"hook-memory-limit": "1G",
+	}, nil, schema, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "unit-foo-42"},
+	}}
+	result, err := s.uniter.HookLimits(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.HookLimitsResults{
+		Results: []params.HookLimitsResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{Result: params.HookLimits{
+				HookTimeout: 30 * time.Minute,
+				MemoryLimit: 1024,
+			}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
 func (s *uniterSuite) TestClearResolved(c *gc.C) {
 	err := s.wordpressUnit.SetResolved(state.ResolvedRetryHooks)
 	c.Assert(err, jc.ErrorIsNil)
@@ -27,18 +27,60 @@ type ActionAPI struct {
 
 // APIv7 provides the Action API facade for version 7.
 type APIv7 struct {
+	*APIv8
+}
+
+// APIv8 provides the Action API facade for version 8.
+type APIv8 struct {
+	*APIv9
+}
+
+// APIv9 provides the Action API facade for version 9.
+type APIv9 struct {
+	*APIv10
+}
+
+// APIv10 provides the Action API facade for version 10.
+type APIv10 struct {
 	*ActionAPI
 }
 
 // NewActionAPIV7 returns an initialized ActionAPI for version 7.
 func NewActionAPIV7(ctx facade.Context) (*APIv7, error) {
-	api, err := newActionAPI(ctx.State(), ctx.Resources(), ctx.Auth())
+	api, err := NewActionAPIV8(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return &APIv7{api}, nil
 }
 
+// NewActionAPIV8 returns an initialized ActionAPI for version 8.
+func NewActionAPIV8(ctx facade.Context) (*APIv8, error) {
+	api, err := NewActionAPIV9(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv8{api}, nil
+}
+
+// NewActionAPIV9 returns an initialized ActionAPI for version 9.
+func NewActionAPIV9(ctx facade.Context) (*APIv9, error) {
+	api, err := NewActionAPIV10(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv9{api}, nil
+}
+
+// NewActionAPIV10 returns an initialized ActionAPI for version 10.
+func NewActionAPIV10(ctx facade.Context) (*APIv10, error) {
+	api, err := newActionAPI(ctx.State(), ctx.Resources(), ctx.Auth())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv10{api}, nil
+}
+
 func newActionAPI(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*ActionAPI, error) {
 	if !authorizer.AuthClient() {
 		return nil, apiservererrors.ErrPerm
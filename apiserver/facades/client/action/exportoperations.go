@@ -0,0 +1,117 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// defaultExportLimit is the number of operations exported in each
+// batch of archived operations if the client doesn't ask for a limit.
+const defaultExportLimit = 50
+
+// ExportOperations isn't on the v9 API.
+func (a *APIv9) ExportOperations(_, _ struct{}) {}
+
+// ExportOperations returns the operations which completed in the given
+// time window, oldest first, with all of the details of their tasks.
+// If archived operations are requested, the operations pruned from the
+// model and archived in the controller's blob storage are returned
+// instead.
+func (a *ActionAPI) ExportOperations(arg params.OperationExportArgs) (params.OperationResults, error) {
+	if err := a.checkCanRead(); err != nil {
+		return params.OperationResults{}, errors.Trace(err)
+	}
+
+	var from, to time.Time
+	if arg.From != nil {
+		from = *arg.From
+	}
+	if arg.To != nil {
+		to = *arg.To
+	}
+	offset := 0
+	if arg.Offset != nil {
+		offset = *arg.Offset
+	}
+	limit := 0
+	if arg.Limit != nil {
+		limit = *arg.Limit
+	}
+	if arg.Archived {
+		return a.exportArchivedOperations(from, to, offset, limit)
+	}
+
+	operations, truncated, err := a.model.ExportOperations(from, to, offset, limit)
+	if err != nil {
+		return params.OperationResults{}, errors.Trace(err)
+	}
+	result := params.OperationResults{
+		Truncated: truncated,
+		Results:   make([]params.OperationResult, len(operations)),
+	}
+	for i, op := range operations {
+		result.Results[i] = common.MakeOperationResult(op)
+	}
+	return result, nil
+}
+
+func (a *ActionAPI) exportArchivedOperations(from, to time.Time, offset, limit int) (params.OperationResults, error) {
+	archives, err := a.model.OperationArchives(from, to)
+	if err != nil {
+		return params.OperationResults{}, errors.Trace(err)
+	}
+	if limit <= 0 {
+		limit = defaultExportLimit
+	}
+	var result params.OperationResults
+	for _, archive := range archives {
+		if offset >= archive.Count {
+			// An archive which lies wholly inside the window and
+			// before the offset can be skipped without reading it.
+			if !archive.Earliest.Before(from) && (to.IsZero() || archive.Latest.Before(to)) {
+				offset -= archive.Count
+				continue
+			}
+		}
+		r, err := a.model.OpenOperationArchive(archive.Id)
+		if err != nil {
+			return params.OperationResults{}, errors.Trace(err)
+		}
+		dec := json.NewDecoder(r)
+		for {
+			var op params.OperationResult
+			if err := dec.Decode(&op); err == io.EOF {
+				break
+			} else if err != nil {
+				_ = r.Close()
+				return params.OperationResults{}, errors.Annotatef(err, "reading operation archive %s", archive.Id)
+			}
+			if op.Completed.Before(from) || (!to.IsZero() && !op.Completed.Before(to)) {
+				continue
+			}
+			if offset > 0 {
+				offset--
+				continue
+			}
+			if len(result.Results) == limit {
+				result.Truncated = true
+				break
+			}
+			result.Results = append(result.Results, op)
+		}
+		_ = r.Close()
+		if result.Truncated {
+			break
+		}
+	}
+	return result, nil
+}
@@ -26,8 +26,11 @@ func (a *ActionAPI) EnqueueOperation(arg params.Actions) (params.EnqueuedActions
 		return params.EnqueuedActions{}, err
 	}
 	results := params.EnqueuedActions{
-		OperationTag: names.NewOperationTag(operationId).String(),
-		Actions:      actionResults.Results,
+		Actions: actionResults.Results,
+	}
+	// A rolling operation is not created if none of its tasks can run.
+	if operationId != "" {
+		results.OperationTag = names.NewOperationTag(operationId).String()
 	}
 	return results, nil
 }
@@ -67,26 +70,38 @@ func (a *ActionAPI) enqueue(arg params.Actions) (string, params.ActionResults, e
 		}
 	}
 	summary := fmt.Sprintf("%v run on %v", operationName, strings.Join(receivers, ","))
-	operationID, err := a.model.EnqueueOperation(summary)
-	if err != nil {
-		return "", params.ActionResults{}, errors.Annotate(err, "creating operation for actions")
-	}
 
 	tagToActionReceiver := common.TagToActionReceiverFn(a.state.FindEntity)
-	response := params.ActionResults{Results: make([]params.ActionResult, len(arg.Actions))}
-	for i, action := range arg.Actions {
-		currentResult := &response.Results[i]
-		actionReceiver := action.Receiver
+	findReceiver := func(actionReceiver string) (state.ActionReceiver, error) {
 		if strings.HasSuffix(actionReceiver, "leader") {
 			app := strings.Split(actionReceiver, "/")[0]
 			receiverName, err := getLeader(app)
 			if err != nil {
-				currentResult.Error = apiservererrors.ServerError(err)
-				continue
+				return nil, err
 			}
 			actionReceiver = names.NewUnitTag(receiverName).String()
 		}
-		receiver, err := tagToActionReceiver(actionReceiver)
+		return tagToActionReceiver(actionReceiver)
+	}
+	if arg.Schedule != nil {
+		if arg.Rollout != nil {
+			return "", params.ActionResults{}, errors.NotSupportedf("scheduling a rolling operation")
+		}
+		return a.enqueueSchedule(summary, *arg.Schedule, arg.Actions)
+	}
+	if arg.Rollout != nil {
+		return a.enqueueRollout(summary, *arg.Rollout, arg.Actions, findReceiver)
+	}
+
+	operationID, err := a.model.EnqueueOperation(summary)
+	if err != nil {
+		return "", params.ActionResults{}, errors.Annotate(err, "creating operation for actions")
+	}
+
+	response := params.ActionResults{Results: make([]params.ActionResult, len(arg.Actions))}
+	for i, action := range arg.Actions {
+		currentResult := &response.Results[i]
+		receiver, err := findReceiver(action.Receiver)
 		if err != nil {
 			currentResult.Error = apiservererrors.ServerError(err)
 			continue
@@ -102,6 +117,103 @@ func (a *ActionAPI) enqueue(arg params.Actions) (string, params.ActionResults, e
 	return operationID, response, nil
 }
 
+// enqueueRollout records a rolling operation for the given actions. The
+// actions are released to their receivers later, so the results only
+// report whether each was accepted.
+func (a *ActionAPI) enqueueRollout(
+	summary string, rollout params.RolloutParams, actions []params.Action,
+	findReceiver func(string) (state.ActionReceiver, error),
+) (string, params.ActionResults, error) {
+	response := params.ActionResults{Results: make([]params.ActionResult, len(actions))}
+	var (
+		tasks   []state.RolloutTask
+		indices []int
+	)
+	for i, action := range actions {
+		receiver, err := findReceiver(action.Receiver)
+		if err != nil {
+			response.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		tasks = append(tasks, state.RolloutTask{
+			Receiver:   receiver.Tag(),
+			Name:       action.Name,
+			Parameters: action.Parameters,
+		})
+		indices = append(indices, i)
+	}
+	if len(tasks) == 0 {
+		return "", response, nil
+	}
+
+	operationID, taskErrors, err := a.model.EnqueueRollingOperation(summary, state.OperationRollout{
+		BatchSize:   rollout.BatchSize,
+		MaxParallel: rollout.MaxParallel,
+		BatchWait:   rollout.BatchWait,
+		MaxFailures: rollout.MaxFailures,
+	}, tasks)
+	if err != nil {
+		return "", params.ActionResults{}, errors.Annotate(err, "creating rolling operation for actions")
+	}
+	for j, i := range indices {
+		if taskErrors[j] != nil {
+			response.Results[i].Error = apiservererrors.ServerError(taskErrors[j])
+			continue
+		}
+		response.Results[i] = params.ActionResult{
+			Action: &params.Action{
+				Receiver:   tasks[j].Receiver.String(),
+				Name:       tasks[j].Name,
+				Parameters: tasks[j].Parameters,
+			},
+			Status: params.ActionPending,
+		}
+	}
+	return operationID, response, nil
+}
+
+// enqueueSchedule records a schedule for running the given actions
+// later. Receivers may be given as application tags or with the leader
+// syntax; they are resolved each time a run is scheduled. The results
+// report the tasks of the first run.
+func (a *ActionAPI) enqueueSchedule(
+	summary string, schedule params.ScheduleParams, actions []params.Action,
+) (string, params.ActionResults, error) {
+	args := state.ActionScheduleArgs{
+		Summary:    summary,
+		Recurrence: schedule.Recurrence,
+		Tasks:      make([]state.ScheduledTask, len(actions)),
+	}
+	if schedule.At != nil {
+		args.At = *schedule.At
+	}
+	for i, action := range actions {
+		args.Tasks[i] = state.ScheduledTask{
+			Receiver:   action.Receiver,
+			Name:       action.Name,
+			Parameters: action.Parameters,
+		}
+	}
+	operationID, err := a.model.AddActionSchedule(args)
+	if err != nil {
+		return "", params.ActionResults{}, errors.Annotate(err, "scheduling actions")
+	}
+	operation, err := a.model.OperationWithActions(operationID)
+	if err != nil {
+		return "", params.ActionResults{}, errors.Trace(err)
+	}
+	response := params.ActionResults{Results: make([]params.ActionResult, len(operation.Actions))}
+	for i, action := range operation.Actions {
+		receiver, err := names.ActionReceiverTag(action.Receiver())
+		if err != nil {
+			response.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		response.Results[i] = common.MakeActionResult(receiver, action)
+	}
+	return operationID, response, nil
+}
+
 // ListOperations fetches the called actions for specified apps/units.
 func (a *ActionAPI) ListOperations(arg params.OperationQueryArgs) (params.OperationResults, error) {
 	if err := a.checkCanRead(); err != nil {
@@ -163,25 +275,7 @@ func (a *ActionAPI) ListOperations(arg params.OperationQueryArgs) (params.Operat
 		Results:   make([]params.OperationResult, len(summaryResults)),
 	}
 	for i, r := range summaryResults {
-		result.Results[i] = params.OperationResult{
-			OperationTag: r.Operation.Tag().String(),
-			Summary:      r.Operation.Summary(),
-			Enqueued:     r.Operation.Enqueued(),
-			Started:      r.Operation.Started(),
-			Completed:    r.Operation.Completed(),
-			Status:       string(r.Operation.Status()),
-			Actions:      make([]params.ActionResult, len(r.Actions)),
-		}
-		for j, a := range r.Actions {
-			receiver, err := names.ActionReceiverTag(a.Receiver())
-			if err == nil {
-				result.Results[i].Actions[j] = common.MakeActionResult(receiver, a)
-				continue
-			}
-			result.Results[i].Actions[j] = params.ActionResult{
-				Error: apiservererrors.ServerError(errors.Errorf("unknown action receiver %q", a.Receiver())),
-			}
-		}
+		result.Results[i] = common.MakeOperationResult(r)
 	}
 	return result, nil
 }
@@ -205,25 +299,62 @@ func (a *ActionAPI) Operations(arg params.Entities) (params.OperationResults, er
 			continue
 		}
 
-		results.Results[i] = params.OperationResult{
-			OperationTag: op.Operation.Tag().String(),
-			Summary:      op.Operation.Summary(),
-			Enqueued:     op.Operation.Enqueued(),
-			Started:      op.Operation.Started(),
-			Completed:    op.Operation.Completed(),
-			Status:       string(op.Operation.Status()),
-			Actions:      make([]params.ActionResult, len(op.Actions)),
-		}
-		for j, a := range op.Actions {
-			receiver, err := names.ActionReceiverTag(a.Receiver())
-			if err == nil {
-				results.Results[i].Actions[j] = common.MakeActionResult(receiver, a)
-				continue
-			}
-			results.Results[i].Actions[j] = params.ActionResult{
-				Error: apiservererrors.ServerError(errors.Errorf("unknown action receiver %q", a.Receiver())),
-			}
+		results.Results[i] = common.MakeOperationResult(*op)
+		progress, err := a.model.RolloutProgress(tag.Id())
+		if err != nil {
+			results.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		if progress != nil {
+			results.Results[i].Rollout = rolloutInfo(progress)
+		}
+	}
+	return results, nil
+}
+
+func rolloutInfo(progress *state.RolloutProgress) *params.RolloutInfo {
+	info := &params.RolloutInfo{
+		RolloutParams: params.RolloutParams{
+			BatchSize:   progress.BatchSize,
+			MaxParallel: progress.MaxParallel,
+			BatchWait:   progress.BatchWait,
+			MaxFailures: progress.MaxFailures,
+		},
+		Halted:  progress.Halted,
+		Batches: make([]params.RolloutBatch, len(progress.Batches)),
+	}
+	for i, batch := range progress.Batches {
+		info.Batches[i] = params.RolloutBatch{
+			Pending:   batch.Pending,
+			Running:   batch.Running,
+			Completed: batch.Completed,
+			Failed:    batch.Failed,
+		}
+	}
+	return info
+}
+
+// ResumeOperations isn't on the v7 API.
+func (a *APIv7) ResumeOperations(_, _ struct{}) {}
+
+// ResumeOperations restarts the specified rolling operations which were
+// halted after too many of their tasks failed.
+func (a *ActionAPI) ResumeOperations(arg params.Entities) (params.ErrorResults, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	if err := a.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{Results: make([]params.ErrorResult, len(arg.Entities))}
+	for i, entity := range arg.Entities {
+		tag, err := names.ParseOperationTag(entity.Tag)
+		if err != nil {
+			results.Results[i].Error = apiservererrors.ServerError(err)
+			continue
 		}
+		err = a.model.ResumeOperation(tag.Id())
+		results.Results[i].Error = apiservererrors.ServerError(err)
 	}
 	return results, nil
 }
@@ -4,8 +4,12 @@
 package action_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"strconv"
+	"time"
 
+	"github.com/juju/names/v4"
 	jc "github.com/juju/testing/checkers"
 	"github.com/kr/pretty"
 	gc "gopkg.in/check.v1"
@@ -325,3 +329,163 @@ func (s *operationSuite) TestOperations(c *gc.C) {
 	c.Assert(action.Tag, gc.Equals, "action-5")
 	c.Assert(result.Actions[3].Status, gc.Equals, "pending")
 }
+
+func (s *operationSuite) TestEnqueueRollingOperation(c *gc.C) {
+	arg := params.Actions{
+		Actions: []params.Action{
+			{Receiver: s.wordpressUnit.Tag().String(), Name: "fakeaction", Parameters: map[string]interface{}{}},
+			{Receiver: s.mysqlUnit.Tag().String(), Name: "fakeaction", Parameters: map[string]interface{}{}},
+			{Receiver: "unit-mysql-9", Name: "fakeaction", Parameters: map[string]interface{}{}},
+		},
+		Rollout: &params.RolloutParams{BatchSize: 1, MaxFailures: 1},
+	}
+	r, err := s.action.EnqueueOperation(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.OperationTag, gc.Equals, "operation-1")
+	c.Assert(r.Actions, gc.HasLen, 3)
+	c.Assert(r.Actions[0].Action.Tag, gc.Equals, "")
+	c.Assert(r.Actions[0].Action.Receiver, gc.Equals, "unit-wordpress-0")
+	c.Assert(r.Actions[0].Status, gc.Equals, params.ActionPending)
+	c.Assert(r.Actions[1].Action.Receiver, gc.Equals, "unit-mysql-0")
+	c.Assert(r.Actions[2].Error, gc.ErrorMatches, `unit-mysql-9 not found`)
+
+	// Nothing is enqueued until the rollout is advanced.
+	actions, err := s.Model.AllActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 0)
+
+	operations, err := s.action.Operations(params.Entities{
+		Entities: []params.Entity{{Tag: "operation-1"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(operations.Results, gc.HasLen, 1)
+	c.Assert(operations.Results[0].Rollout, jc.DeepEquals, &params.RolloutInfo{
+		RolloutParams: params.RolloutParams{BatchSize: 1, MaxFailures: 1},
+		Batches:       []params.RolloutBatch{{Pending: 1}, {Pending: 1}},
+	})
+}
+
+func (s *operationSuite) TestOperationsNotRolling(c *gc.C) {
+	s.setupOperations(c)
+	operations, err := s.action.Operations(params.Entities{
+		Entities: []params.Entity{{Tag: "operation-1"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(operations.Results, gc.HasLen, 1)
+	c.Assert(operations.Results[0].Rollout, gc.IsNil)
+}
+
+func (s *operationSuite) TestResumeOperations(c *gc.C) {
+	arg := params.Actions{
+		Actions: []params.Action{
+			{Receiver: s.wordpressUnit.Tag().String(), Name: "fakeaction", Parameters: map[string]interface{}{}},
+			{Receiver: s.mysqlUnit.Tag().String(), Name: "fakeaction", Parameters: map[string]interface{}{}},
+		},
+		Rollout: &params.RolloutParams{BatchSize: 1, MaxFailures: 1},
+	}
+	_, err := s.action.EnqueueOperation(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.Model.AdvanceRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	actions, err := s.Model.AllActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	_, err = actions[0].Finish(state.ActionResults{Status: state.ActionFailed})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.Model.AdvanceRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.action.ResumeOperations(params.Entities{
+		Entities: []params.Entity{{Tag: "operation-1"}, {Tag: "operation-1"}, {Tag: "unit-mysql-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `operation "1" is not halted`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, `"unit-mysql-0" is not a valid operation tag`)
+
+	_, err = s.Model.AdvanceRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	actions, err = s.Model.AllActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 2)
+}
+
+func (s *operationSuite) TestExportOperations(c *gc.C) {
+	s.setupOperations(c)
+	arg := params.Actions{
+		Actions: []params.Action{
+			{Receiver: s.wordpressUnit.Tag().String(), Name: "fakeaction", Parameters: map[string]interface{}{"foo": "bar"}},
+		}}
+	r, err := s.action.EnqueueOperation(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	tag, err := names.ParseActionTag(r.Actions[0].Action.Tag)
+	c.Assert(err, jc.ErrorIsNil)
+	a, err := s.Model.ActionByTag(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = a.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = a.Finish(state.ActionResults{Status: state.ActionCompleted, Results: map[string]interface{}{"out": "put"}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Only completed operations are exported.
+	operations, err := s.action.ExportOperations(params.OperationExportArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(operations.Truncated, jc.IsFalse)
+	c.Assert(operations.Results, gc.HasLen, 1)
+	result := operations.Results[0]
+	c.Assert(result.OperationTag, gc.Equals, r.OperationTag)
+	c.Assert(result.Status, gc.Equals, "completed")
+	c.Assert(result.Actions, gc.HasLen, 1)
+	c.Assert(result.Actions[0].Action.Parameters, jc.DeepEquals, map[string]interface{}{"foo": "bar"})
+	c.Assert(result.Actions[0].Output, jc.DeepEquals, map[string]interface{}{"out": "put"})
+
+	from := result.Completed.Add(time.Second)
+	operations, err = s.action.ExportOperations(params.OperationExportArgs{From: &from})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(operations.Results, gc.HasLen, 0)
+}
+
+func (s *operationSuite) TestExportArchivedOperations(c *gc.C) {
+	start := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for i := 0; i < 3; i++ {
+		err := enc.Encode(params.OperationResult{
+			OperationTag: names.NewOperationTag(strconv.Itoa(i + 1)).String(),
+			Completed:    start.Add(time.Duration(i) * time.Hour),
+			Status:       "completed",
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	_, err := s.Model.AddOperationArchive(state.OperationArchive{
+		Earliest: start,
+		Latest:   start.Add(2 * time.Hour),
+		Count:    3,
+	}, bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	c.Assert(err, jc.ErrorIsNil)
+
+	from := start.Add(time.Hour)
+	limit := 1
+	operations, err := s.action.ExportOperations(params.OperationExportArgs{
+		From:     &from,
+		Archived: true,
+		Limit:    &limit,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(operations.Truncated, jc.IsTrue)
+	c.Assert(operations.Results, gc.HasLen, 1)
+	c.Assert(operations.Results[0].OperationTag, gc.Equals, "operation-2")
+
+	offset := 1
+	operations, err = s.action.ExportOperations(params.OperationExportArgs{
+		From:     &from,
+		Archived: true,
+		Offset:   &offset,
+		Limit:    &limit,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(operations.Truncated, jc.IsFalse)
+	c.Assert(operations.Results, gc.HasLen, 1)
+	c.Assert(operations.Results[0].OperationTag, gc.Equals, "operation-3")
+}
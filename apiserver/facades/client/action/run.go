@@ -87,26 +87,67 @@ func (a *ActionAPI) Run(run params.RunParams) (results params.EnqueuedActions, e
 		return results, errors.Trace(err)
 	}
 
-	units, err := getAllUnitNames(a.state, run.Units, run.Applications)
+	var receivers []string
+	if run.Schedule != nil {
+		// Applications and leaders are resolved for each
+		// scheduled run rather than now.
+		receivers, err = scheduledReceivers(run.Units, run.Applications)
+	} else {
+		var units []names.Tag
+		units, err = getAllUnitNames(a.state, run.Units, run.Applications)
+		for _, unit := range units {
+			receivers = append(receivers, unit.String())
+		}
+	}
 	if err != nil {
 		return results, errors.Trace(err)
 	}
 
-	machines := make([]names.Tag, len(run.Machines))
-	for i, machineId := range run.Machines {
+	for _, machineId := range run.Machines {
 		if !names.IsValidMachine(machineId) {
 			return results, errors.Errorf("invalid machine id %q", machineId)
 		}
-		machines[i] = names.NewMachineTag(machineId)
+		receivers = append(receivers, names.NewMachineTag(machineId).String())
 	}
 
-	actionParams, err := a.createActionsParams(append(units, machines...), run.Commands, run.Timeout, run.WorkloadContext)
+	actionParams, err := a.createActionsParams(receivers, run.Commands, run.Timeout, run.WorkloadContext)
 	if err != nil {
 		return results, errors.Trace(err)
 	}
+	actionParams.Rollout = run.Rollout
+	actionParams.Schedule = run.Schedule
 	return a.EnqueueOperation(actionParams)
 }
 
+// scheduledReceivers returns the receivers of a scheduled run of
+// commands, leaving leaders and applications to be resolved when
+// each run is scheduled.
+func scheduledReceivers(units, applications []string) ([]string, error) {
+	var receivers []string
+	for _, unit := range units {
+		if validLeader(unit) {
+			receivers = append(receivers, unit)
+			continue
+		}
+		if !names.IsValidUnit(unit) {
+			return nil, errors.Errorf("invalid unit name %q", unit)
+		}
+		receivers = append(receivers, names.NewUnitTag(unit).String())
+	}
+	for _, app := range applications {
+		if !names.IsValidApplication(app) {
+			return nil, errors.Errorf("invalid application name %q", app)
+		}
+		receivers = append(receivers, names.NewApplicationTag(app).String())
+	}
+	return receivers, nil
+}
+
+func validLeader(unit string) bool {
+	app := strings.TrimSuffix(unit, "/leader")
+	return app != unit && names.IsValidApplication(app)
+}
+
 // RunOnAllMachines attempts to run the specified command on all the machines.
 func (a *ActionAPI) RunOnAllMachines(run params.RunParams) (results params.EnqueuedActions, err error) {
 	if err := a.checkCanAdmin(); err != nil {
@@ -129,20 +170,22 @@ func (a *ActionAPI) RunOnAllMachines(run params.RunParams) (results params.Enque
 	if err != nil {
 		return results, err
 	}
-	machineTags := make([]names.Tag, len(machines))
+	machineTags := make([]string, len(machines))
 	for i, machine := range machines {
-		machineTags[i] = machine.Tag()
+		machineTags[i] = machine.Tag().String()
 	}
 
 	actionParams, err := a.createActionsParams(machineTags, run.Commands, run.Timeout, false)
 	if err != nil {
 		return results, errors.Trace(err)
 	}
+	actionParams.Rollout = run.Rollout
+	actionParams.Schedule = run.Schedule
 	return a.EnqueueOperation(actionParams)
 }
 
 func (a *ActionAPI) createActionsParams(
-	actionReceiverTags []names.Tag,
+	actionReceivers []string,
 	quotedCommands string,
 	timeout time.Duration,
 	workloadContext bool,
@@ -159,9 +202,9 @@ func (a *ActionAPI) createActionsParams(
 	actionParams["timeout"] = timeout.Nanoseconds()
 	actionParams["workload-context"] = workloadContext
 
-	for _, tag := range actionReceiverTags {
+	for _, receiver := range actionReceivers {
 		apiActionParams.Actions = append(apiActionParams.Actions, params.Action{
-			Receiver:   tag.String(),
+			Receiver:   receiver,
 			Name:       actionRunnerName,
 			Parameters: actionParams,
 		})
@@ -0,0 +1,35 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/environschema.v1"
+
+	coreapplication "github.com/juju/juju/core/application"
+)
+
+var actionRetentionFields = environschema.Fields{
+	coreapplication.ActionResultsMaxAgeOptionName: {
+		Description: "The maximum age for the results of tasks run on this application before they are pruned, overriding the model's max-action-results-age",
+		Type:        environschema.Tstring,
+		Group:       environschema.JujuGroup,
+	},
+}
+
+// AddActionRetentionSchema adds the action retention schema fields to an
+// existing set of schema fields.
+func AddActionRetentionSchema(extra environschema.Fields) (environschema.Fields, error) {
+	fields := make(environschema.Fields)
+	for name, field := range actionRetentionFields {
+		fields[name] = field
+	}
+	for name, field := range extra {
+		if _, ok := actionRetentionFields[name]; ok {
+			return nil, errors.Errorf("config field %q clashes with common config", name)
+		}
+		fields[name] = field
+	}
+	return fields, nil
+}
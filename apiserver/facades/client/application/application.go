@@ -110,6 +110,12 @@ type APIv12 struct {
 // It adds CharmOrigin. The ApplicationsInfo call populates the exposed
 // endpoints field in its response entries.
 type APIv13 struct {
+	*APIv14
+}
+
+// APIv14 provides the Application API facade for version 14.
+// It adds the CharmRefreshReport method.
+type APIv14 struct {
 	*APIBase
 }
 
@@ -225,13 +231,21 @@ func NewFacadeV12(ctx facade.Context) (*APIv12, error) {
 }
 
 func NewFacadeV13(ctx facade.Context) (*APIv13, error) {
-	api, err := newFacadeBase(ctx)
+	api, err := NewFacadeV14(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return &APIv13{api}, nil
 }
 
+func NewFacadeV14(ctx facade.Context) (*APIv14, error) {
+	api, err := newFacadeBase(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv14{api}, nil
+}
+
 type caasBrokerInterface interface {
 	ValidateStorageClass(config map[string]interface{}) error
 	Version() (*version.Number, error)
@@ -496,7 +510,15 @@ func (api *APIBase) Deploy(args params.ApplicationsDeploy) (params.ErrorResults,
 
 func applicationConfigSchema(modelType state.ModelType) (environschema.Fields, schema.Defaults, error) {
 	if modelType != state.ModelTypeCAAS {
-		return trustFields, trustDefaults, nil
+		configSchema, err := AddActionRetentionSchema(trustFields)
+		if err != nil {
+			return nil, nil, err
+		}
+		configSchema, err = AddHookLimitsSchema(configSchema)
+		if err != nil {
+			return nil, nil, err
+		}
+		return configSchema, trustDefaults, nil
 	}
 	// TODO(caas) - get the schema from the provider
 	defaults := caas.ConfigDefaults(k8s.ConfigDefaults())
@@ -504,7 +526,19 @@ func applicationConfigSchema(modelType state.ModelType) (environschema.Fields, s
 	if err != nil {
 		return nil, nil, err
 	}
-	return AddTrustSchemaAndDefaults(configSchema, defaults)
+	configSchema, defaults, err = AddTrustSchemaAndDefaults(configSchema, defaults)
+	if err != nil {
+		return nil, nil, err
+	}
+	configSchema, err = AddActionRetentionSchema(configSchema)
+	if err != nil {
+		return nil, nil, err
+	}
+	configSchema, err = AddHookLimitsSchema(configSchema)
+	if err != nil {
+		return nil, nil, err
+	}
+	return configSchema, defaults, nil
 }
 
 func splitApplicationAndCharmConfig(modelType state.ModelType, inConfig map[string]string) (
@@ -864,6 +898,12 @@ func parseCharmSettings(modelType state.ModelType, ch Charm, appName string, con
 	if err != nil {
 		return nil, nil, nil, errors.Trace(err)
 	}
+	if _, _, err := application.ActionResultsMaxAge(appConfig.Attributes()); err != nil {
+		return nil, nil, nil, errors.Trace(err)
+	}
+	if _, err := application.ParseHookLimits(appConfig.Attributes()); err != nil {
+		return nil, nil, nil, errors.Trace(err)
+	}
 
 	charmSettings := make(charm.Settings)
 	if len(charmYamlConfig) > 0 {
@@ -1044,14 +1084,15 @@ func (api *APIBase) setConfig(app Application, generation, settingsYAML string,
 		return errors.Annotate(err, "obtaining charm for this application")
 	}
 
-	appConfig, appConfigSchema, charmSettings, err := parseCharmSettings(api.modelType, ch, app.Name(), settingsStrings, settingsYAML)
+	appName := app.Name()
+	appConfig, appConfigSchema, charmSettings, err := parseCharmSettings(api.modelType, ch, appName, settingsStrings, settingsYAML)
 	if err != nil {
 		return errors.Annotate(err, "parsing settings for application")
 	}
 
 	var configChanged bool
 	if len(charmSettings) != 0 {
-		if err = app.UpdateCharmConfig(generation, charmSettings); err != nil {
+		if err = api.updateCharmConfig(app, appName, generation, charmSettings); err != nil {
 			return errors.Annotate(err, "updating charm config settings")
 		}
 		configChanged = true
@@ -1453,7 +1494,26 @@ func (api *APIBase) Set(p params.ApplicationSet) error {
 		return err
 	}
 
-	return app.UpdateCharmConfig(model.GenerationMaster, changes)
+	return api.updateCharmConfig(app, p.ApplicationName, model.GenerationMaster, changes)
+}
+
+// updateCharmConfig updates the charm config of the application on the
+// given branch. Changes to the master branch are recorded in the
+// application's config history, along with any changes made since the
+// latest recorded revision.
+func (api *APIBase) updateCharmConfig(app Application, appName, branchName string, settings charm.Settings) error {
+	if branchName != model.GenerationMaster {
+		return app.UpdateCharmConfig(branchName, settings)
+	}
+	tag := names.NewApplicationTag(appName)
+	if _, err := api.backend.RecordConfigRevision(tag, ""); err != nil {
+		return errors.Trace(err)
+	}
+	if err := app.UpdateCharmConfig(branchName, settings); err != nil {
+		return err
+	}
+	_, err := api.backend.RecordConfigRevision(tag, api.authorizer.GetAuthTag().Id())
+	return errors.Trace(err)
 }
 
 // Unset implements the server side of Client.Unset.
@@ -1478,7 +1538,7 @@ func (api *APIBase) Unset(p params.ApplicationUnset) error {
 	if p.BranchName == "" {
 		p.BranchName = model.GenerationMaster
 	}
-	return app.UpdateCharmConfig(p.BranchName, settings)
+	return api.updateCharmConfig(app, p.ApplicationName, p.BranchName, settings)
 }
 
 // CharmRelations implements the server side of Application.CharmRelations.
@@ -2653,7 +2713,7 @@ func (api *APIBase) unsetApplicationConfig(arg params.ApplicationUnset) error {
 		if arg.BranchName == "" {
 			arg.BranchName = model.GenerationMaster
 		}
-		if err := app.UpdateCharmConfig(arg.BranchName, charmSettings); err != nil {
+		if err := api.updateCharmConfig(app, arg.ApplicationName, arg.BranchName, charmSettings); err != nil {
 			return errors.Annotate(err, "updating application charm settings")
 		}
 	}
@@ -3019,6 +3079,12 @@ func (api *APIBase) UnitsInfo(in params.Entities) (params.UnitInfoResults, error
 			out[i].Error = apiservererrors.ServerError(err)
 			continue
 		}
+		// The hook traces are only diagnostic, so failing to read
+		// them shouldn't hide the rest of the unit's details.
+		result.HookTraces, err = unitHookTraces(unit)
+		if err != nil {
+			logger.Warningf("cannot read hook traces for %s: %v", unit.Name(), err)
+		}
 
 		out[i].Result = result
 	}
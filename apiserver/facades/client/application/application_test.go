@@ -50,7 +50,7 @@ type applicationSuite struct {
 	jujutesting.JujuConnSuite
 	commontesting.BlockHelper
 
-	applicationAPI *application.APIv13
+	applicationAPI *application.APIv14
 	application    *state.Application
 	authorizer     *apiservertesting.FakeAuthorizer
 	repo           *mockRepo
@@ -114,7 +114,7 @@ func (s *applicationSuite) UploadCharmMultiSeries(c *gc.C, url, name string) (*c
 	return s.UploadCharm(c, url, name)
 }
 
-func (s *applicationSuite) makeAPI(c *gc.C) *application.APIv13 {
+func (s *applicationSuite) makeAPI(c *gc.C) *application.APIv14 {
 	resources := common.NewResources()
 	c.Assert(resources.RegisterNamed("dataDir", common.StringResource(c.MkDir())), jc.ErrorIsNil)
 	storageAccess, err := application.GetStorageState(s.State)
@@ -139,7 +139,7 @@ func (s *applicationSuite) makeAPI(c *gc.C) *application.APIv13 {
 		nil, // CAAS Broker not used in this suite.
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	return &application.APIv13{api}
+	return &application.APIv14{api}
 }
 
 func (s *applicationSuite) TestCharmConfig(c *gc.C) {
@@ -165,7 +165,9 @@ func (s *applicationSuite) TestCharmConfigV8(c *gc.C) {
 			APIv10: &application.APIv10{
 				APIv11: &application.APIv11{
 					APIv12: &application.APIv12{
-						s.applicationAPI,
+						&application.APIv13{
+							s.applicationAPI,
+						},
 					},
 				},
 			},
@@ -1721,7 +1723,7 @@ func (s *applicationSuite) checkClientApplicationUpdateSetCharm(c *gc.C, forceCh
 		MinUnits:        &minUnits,
 		ForceCharmURL:   forceCharmURL,
 	}
-	api := &application.APIv12{s.applicationAPI}
+	api := &application.APIv12{&application.APIv13{s.applicationAPI}}
 	err = api.Update(args)
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -1764,7 +1766,7 @@ func (s *applicationSuite) TestBlockChangeApplicationUpdate(c *gc.C) {
 		CharmURL:        curl,
 		ForceCharmURL:   false,
 	}
-	api := &application.APIv12{s.applicationAPI}
+	api := &application.APIv12{&application.APIv13{s.applicationAPI}}
 	err := api.Update(args)
 	s.AssertBlocked(c, err, "TestBlockChangeApplicationUpdate")
 }
@@ -1778,7 +1780,7 @@ func (s *applicationSuite) TestApplicationUpdateSetMinUnits(c *gc.C) {
 		ApplicationName: "dummy",
 		MinUnits:        &minUnits,
 	}
-	api := &application.APIv12{s.applicationAPI}
+	api := &application.APIv12{&application.APIv13{s.applicationAPI}}
 	err := api.Update(args)
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -1796,7 +1798,7 @@ func (s *applicationSuite) TestApplicationUpdateSetMinUnitsWithLXDProfile(c *gc.
 		ApplicationName: "lxd-profile",
 		MinUnits:        &minUnits,
 	}
-	api := &application.APIv12{s.applicationAPI}
+	api := &application.APIv12{&application.APIv13{s.applicationAPI}}
 	err := api.Update(args)
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -1824,7 +1826,7 @@ func (s *applicationSuite) TestApplicationUpdateSetMinUnitsError(c *gc.C) {
 		ApplicationName: "dummy",
 		MinUnits:        &minUnits,
 	}
-	api := &application.APIv12{s.applicationAPI}
+	api := &application.APIv12{&application.APIv13{s.applicationAPI}}
 	err := api.Update(args)
 	c.Assert(err, gc.ErrorMatches,
 		`cannot set minimum units for application "dummy": cannot set a negative minimum number of units`)
@@ -1852,7 +1854,7 @@ func (s *applicationSuite) testApplicationUpdateSetSettingsStrings(c *gc.C, bran
 		SettingsStrings: map[string]string{"title": "s-title", "username": "s-user"},
 		Generation:      branchName,
 	}
-	api := &application.APIv12{s.applicationAPI}
+	api := &application.APIv12{&application.APIv13{s.applicationAPI}}
 	err := api.Update(args)
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -1876,7 +1878,7 @@ func (s *applicationSuite) TestApplicationUpdateSetSettingsStringsBranch(c *gc.C
 		SettingsStrings: map[string]string{"title": "s-title", "username": "s-user"},
 		Generation:      newBranch,
 	}
-	api := &application.APIv12{s.applicationAPI}
+	api := &application.APIv12{&application.APIv13{s.applicationAPI}}
 	err := api.Update(args)
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -1910,7 +1912,7 @@ func (s *applicationSuite) testApplicationUpdateSetSettingsYAML(c *gc.C, branchN
 		SettingsYAML:    "dummy:\n  title: y-title\n  username: y-user",
 		Generation:      branchName,
 	}
-	api := &application.APIv12{s.applicationAPI}
+	api := &application.APIv12{&application.APIv13{s.applicationAPI}}
 	err := api.Update(args)
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -1934,7 +1936,7 @@ func (s *applicationSuite) TestApplicationUpdateSetSettingsYAMLBranch(c *gc.C) {
 		SettingsYAML:    "dummy:\n  title: y-title\n  username: y-user",
 		Generation:      newBranch,
 	}
-	api := &application.APIv12{s.applicationAPI}
+	api := &application.APIv12{&application.APIv13{s.applicationAPI}}
 	err := api.Update(args)
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -1960,7 +1962,7 @@ func (s *applicationSuite) TestClientApplicationUpdateSetSettingsGetYAML(c *gc.C
 		SettingsYAML:    "charm: dummy\napplication: dummy\nsettings:\n  title:\n    value: y-title\n    type: string\n  username:\n    value: y-user\n  ignore:\n    blah: true",
 		Generation:      model.GenerationMaster,
 	}
-	api := &application.APIv12{s.applicationAPI}
+	api := &application.APIv12{&application.APIv13{s.applicationAPI}}
 	err := api.Update(args)
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -1987,7 +1989,7 @@ func (s *applicationSuite) TestApplicationUpdateCombinedStringAndYAMLSettings(c
 		SettingsYAML: "dummy:\n  title: s-title",
 		Generation:   newBranch,
 	}
-	api := &application.APIv12{s.applicationAPI}
+	api := &application.APIv12{&application.APIv13{s.applicationAPI}}
 	err := api.Update(args)
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -2013,7 +2015,7 @@ func (s *applicationSuite) TestApplicationUpdateSetConstraints(c *gc.C) {
 		ApplicationName: "dummy",
 		Constraints:     &cons,
 	}
-	api := &application.APIv12{s.applicationAPI}
+	api := &application.APIv12{&application.APIv13{s.applicationAPI}}
 	err = api.Update(args)
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -2051,7 +2053,7 @@ func (s *applicationSuite) TestApplicationUpdateAllParams(c *gc.C) {
 		Constraints:     &cons,
 		Generation:      model.GenerationMaster,
 	}
-	api := &application.APIv12{s.applicationAPI}
+	api := &application.APIv12{&application.APIv13{s.applicationAPI}}
 	err = api.Update(args)
 	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
 
@@ -2074,20 +2076,20 @@ func (s *applicationSuite) TestApplicationUpdateNoParams(c *gc.C) {
 
 	// Calling Update with no parameters set is a no-op.
 	args := params.ApplicationUpdate{ApplicationName: "wordpress"}
-	api := &application.APIv12{s.applicationAPI}
+	api := &application.APIv12{&application.APIv13{s.applicationAPI}}
 	err := api.Update(args)
 	c.Assert(err, jc.ErrorIsNil)
 }
 
 func (s *applicationSuite) TestApplicationUpdateNoApplication(c *gc.C) {
-	api := &application.APIv12{s.applicationAPI}
+	api := &application.APIv12{&application.APIv13{s.applicationAPI}}
 	err := api.Update(params.ApplicationUpdate{})
 	c.Assert(err, gc.ErrorMatches, `"" is not a valid application name`)
 }
 
 func (s *applicationSuite) TestApplicationUpdateInvalidApplication(c *gc.C) {
 	args := params.ApplicationUpdate{ApplicationName: "no-such-application"}
-	api := &application.APIv12{s.applicationAPI}
+	api := &application.APIv12{&application.APIv13{s.applicationAPI}}
 	err := api.Update(args)
 	c.Assert(err, gc.ErrorMatches, `application "no-such-application" not found`)
 }
@@ -62,7 +62,7 @@ type ApplicationSuite struct {
 	env          environs.Environ
 	blockChecker mockBlockChecker
 	authorizer   apiservertesting.FakeAuthorizer
-	api          *application.APIv13
+	api          *application.APIv14
 	deployParams map[string]application.DeployApplicationParams
 }
 
@@ -93,7 +93,7 @@ func (s *ApplicationSuite) setAPIUser(c *gc.C, user names.UserTag) {
 		s.caasBroker,
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	s.api = &application.APIv13{api}
+	s.api = &application.APIv14{api}
 }
 
 func (s *ApplicationSuite) SetUpTest(c *gc.C) {
@@ -153,6 +153,18 @@ func (s *ApplicationSuite) SetUpTest(c *gc.C) {
 						tag:        names.NewUnitTag("postgresql/0"),
 						machineId:  "0",
 						agentTools: agentTools,
+						hookTraces: `
+- operation: run install hook
+  started: 2021-04-01T12:00:00Z
+  lock-wait: 2s
+  duration: 1m0s
+  flush: 500ms
+  tool-calls:
+  - name: status-set
+    args: maintenance installing
+    started: 2021-04-01T12:00:10Z
+    duration: 100ms
+`[1:],
 					},
 					{
 						name:       "postgresql/1",
@@ -385,7 +397,7 @@ func (s *ApplicationSuite) TestUpdateCAASApplicationSettings(c *gc.C) {
 		ApplicationName: "postgresql",
 		SettingsYAML:    "postgresql:\n  stringOption: bar\n  juju-external-hostname: foo",
 	}
-	api := &application.APIv12{s.api}
+	api := &application.APIv12{&application.APIv13{s.api}}
 	err := api.Update(args)
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -399,6 +411,10 @@ func (s *ApplicationSuite) TestUpdateCAASApplicationSettings(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	appCfgSchema, appDefaults, err = application.AddTrustSchemaAndDefaults(appCfgSchema, appDefaults)
 	c.Assert(err, jc.ErrorIsNil)
+	appCfgSchema, err = application.AddActionRetentionSchema(appCfgSchema)
+	c.Assert(err, jc.ErrorIsNil)
+	appCfgSchema, err = application.AddHookLimitsSchema(appCfgSchema)
+	c.Assert(err, jc.ErrorIsNil)
 
 	appCfg, err := coreapplication.NewConfig(map[string]interface{}{
 		"juju-external-hostname": "foo",
@@ -438,6 +454,10 @@ func (s *ApplicationSuite) TestSetCAASConfigSettings(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	appCfgSchema, appDefaults, err = application.AddTrustSchemaAndDefaults(appCfgSchema, appDefaults)
 	c.Assert(err, jc.ErrorIsNil)
+	appCfgSchema, err = application.AddActionRetentionSchema(appCfgSchema)
+	c.Assert(err, jc.ErrorIsNil)
+	appCfgSchema, err = application.AddHookLimitsSchema(appCfgSchema)
+	c.Assert(err, jc.ErrorIsNil)
 
 	appCfg, err := coreapplication.NewConfig(map[string]interface{}{
 		"juju-external-hostname": "foo",
@@ -463,7 +483,7 @@ func (s *ApplicationSuite) TestUpdateCAASApplicationSettingsInIAASModelTriggersE
 		ApplicationName: "postgresql",
 		SettingsYAML:    "postgresql:\n  stringOption: bar\n  juju-external-hostname: foo",
 	}
-	api := &application.APIv12{s.api}
+	api := &application.APIv12{&application.APIv13{s.api}}
 	err := api.Update(args)
 	c.Assert(err, gc.ErrorMatches, `.*unknown option "juju-external-hostname"`, gc.Commentf("expected to get an error when attempting to set CAAS-specific app setting in IAAS model"))
 }
@@ -1850,7 +1870,7 @@ func (s *ApplicationSuite) TestSetApplicationConfigEmptyUsesMaster(c *gc.C) {
 
 func (s *ApplicationSuite) testSetApplicationConfig(c *gc.C, branchName string) {
 	application.SetModelType(s.api, state.ModelTypeCAAS)
-	api := &application.APIv12{s.api}
+	api := &application.APIv12{&application.APIv13{s.api}}
 	result, err := api.SetApplicationsConfig(params.ApplicationConfigSetArgs{
 		Args: []params.ApplicationConfigSet{{
 			ApplicationName: "postgresql",
@@ -1871,6 +1891,10 @@ func (s *ApplicationSuite) testSetApplicationConfig(c *gc.C, branchName string)
 	defaults := caas.ConfigDefaults(k8s.ConfigDefaults())
 	appCfgSchema, defaults, err = application.AddTrustSchemaAndDefaults(appCfgSchema, defaults)
 	c.Assert(err, jc.ErrorIsNil)
+	appCfgSchema, err = application.AddActionRetentionSchema(appCfgSchema)
+	c.Assert(err, jc.ErrorIsNil)
+	appCfgSchema, err = application.AddHookLimitsSchema(appCfgSchema)
+	c.Assert(err, jc.ErrorIsNil)
 
 	appCfg, err := coreapplication.NewConfig(map[string]interface{}{
 		"juju-external-hostname": "value",
@@ -1880,13 +1904,17 @@ func (s *ApplicationSuite) testSetApplicationConfig(c *gc.C, branchName string)
 	app.CheckCall(c, 3, "UpdateApplicationConfig", appCfg.Attributes(), []string(nil), appCfgSchema, schema.Defaults(nil))
 	app.CheckCall(c, 2, "UpdateCharmConfig", model.GenerationMaster, charm.Settings{"stringOption": "stringVal"})
 
+	// The change is recorded in the config history, after any changes
+	// made since the latest revision.
+	c.Check(s.backend.configRevisionAuthors, jc.DeepEquals, []string{"postgresql:", "postgresql:admin"})
+
 	// We should never have accessed the generation.
 	c.Check(s.backend.generation, gc.IsNil)
 }
 
 func (s *ApplicationSuite) TestSetApplicationConfigBranch(c *gc.C) {
 	application.SetModelType(s.api, state.ModelTypeCAAS)
-	api := &application.APIv12{s.api}
+	api := &application.APIv12{&application.APIv13{s.api}}
 	result, err := api.SetApplicationsConfig(params.ApplicationConfigSetArgs{
 		Args: []params.ApplicationConfigSet{{
 			ApplicationName: "postgresql",
@@ -1907,6 +1935,10 @@ func (s *ApplicationSuite) TestSetApplicationConfigBranch(c *gc.C) {
 	defaults := caas.ConfigDefaults(k8s.ConfigDefaults())
 	appCfgSchema, defaults, err = application.AddTrustSchemaAndDefaults(appCfgSchema, defaults)
 	c.Assert(err, jc.ErrorIsNil)
+	appCfgSchema, err = application.AddActionRetentionSchema(appCfgSchema)
+	c.Assert(err, jc.ErrorIsNil)
+	appCfgSchema, err = application.AddHookLimitsSchema(appCfgSchema)
+	c.Assert(err, jc.ErrorIsNil)
 
 	appCfg, err := coreapplication.NewConfig(map[string]interface{}{
 		"juju-external-hostname": "value",
@@ -1916,6 +1948,9 @@ func (s *ApplicationSuite) TestSetApplicationConfigBranch(c *gc.C) {
 	app.CheckCall(c, 3, "UpdateApplicationConfig", appCfg.Attributes(), []string(nil), appCfgSchema, schema.Defaults(nil))
 	app.CheckCall(c, 2, "UpdateCharmConfig", "new-branch", charm.Settings{"stringOption": "stringVal"})
 
+	// Changes to branches aren't recorded in the config history.
+	c.Check(s.backend.configRevisionAuthors, gc.HasLen, 0)
+
 	s.backend.generation.CheckCall(c, 0, "AssignApplication", "postgresql")
 }
 
@@ -1941,6 +1976,10 @@ func (s *ApplicationSuite) TestSetConfigBranch(c *gc.C) {
 	defaults := caas.ConfigDefaults(k8s.ConfigDefaults())
 	appCfgSchema, defaults, err = application.AddTrustSchemaAndDefaults(appCfgSchema, defaults)
 	c.Assert(err, jc.ErrorIsNil)
+	appCfgSchema, err = application.AddActionRetentionSchema(appCfgSchema)
+	c.Assert(err, jc.ErrorIsNil)
+	appCfgSchema, err = application.AddHookLimitsSchema(appCfgSchema)
+	c.Assert(err, jc.ErrorIsNil)
 
 	appCfg, err := coreapplication.NewConfig(map[string]interface{}{
 		"juju-external-hostname": "value",
@@ -1955,7 +1994,7 @@ func (s *ApplicationSuite) TestSetConfigBranch(c *gc.C) {
 
 func (s *ApplicationSuite) TestBlockSetApplicationConfig(c *gc.C) {
 	s.blockChecker.SetErrors(errors.New("blocked"))
-	api := &application.APIv12{s.api}
+	api := &application.APIv12{&application.APIv13{s.api}}
 	_, err := api.SetApplicationsConfig(params.ApplicationConfigSetArgs{})
 	c.Assert(err, gc.ErrorMatches, "blocked")
 	s.blockChecker.CheckCallNames(c, "ChangeAllowed")
@@ -1964,7 +2003,7 @@ func (s *ApplicationSuite) TestBlockSetApplicationConfig(c *gc.C) {
 
 func (s *ApplicationSuite) TestSetApplicationConfigPermissionDenied(c *gc.C) {
 	s.setAPIUser(c, names.NewUserTag("fred"))
-	api := &application.APIv12{s.api}
+	api := &application.APIv12{&application.APIv13{s.api}}
 	_, err := api.SetApplicationsConfig(params.ApplicationConfigSetArgs{
 		Args: []params.ApplicationConfigSet{{
 			ApplicationName: "postgresql",
@@ -1993,6 +2032,10 @@ func (s *ApplicationSuite) TestUnsetApplicationConfig(c *gc.C) {
 	defaults := caas.ConfigDefaults(k8s.ConfigDefaults())
 	schema, defaults, err = application.AddTrustSchemaAndDefaults(schema, defaults)
 	c.Assert(err, jc.ErrorIsNil)
+	schema, err = application.AddActionRetentionSchema(schema)
+	c.Assert(err, jc.ErrorIsNil)
+	schema, err = application.AddHookLimitsSchema(schema)
+	c.Assert(err, jc.ErrorIsNil)
 
 	app.CheckCall(c, 0, "UpdateApplicationConfig", coreapplication.ConfigAttributes(nil),
 		[]string{"juju-external-hostname"}, schema, defaults)
@@ -2252,6 +2295,19 @@ func (s *ApplicationSuite) TestUnitsInfo(c *gc.C) {
 				},
 			},
 		}},
+		HookTraces: []params.HookTrace{{
+			Operation: "run install hook",
+			Started:   time.Date(2021, 4, 1, 12, 0, 0, 0, time.UTC),
+			LockWait:  2 * time.Second,
+			Duration:  time.Minute,
+			Flush:     500 * time.Millisecond,
+			ToolCalls: []params.HookToolTrace{{
+				Name:     "status-set",
+				Args:     "maintenance installing",
+				Started:  time.Date(2021, 4, 1, 12, 0, 10, 0, time.UTC),
+				Duration: 100 * time.Millisecond,
+			}},
+		}},
 		ProviderId: "provider-id",
 		Address:    "192.168.1.1",
 	})
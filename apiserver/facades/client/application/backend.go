@@ -23,6 +23,7 @@ import (
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/resource"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/tools"
 )
@@ -52,6 +53,7 @@ type Backend interface {
 	OfferConnectionForRelation(string) (OfferConnection, error)
 	SaveEgressNetworks(relationKey string, cidrs []string) (state.RelationNetworks, error)
 	Branch(string) (Generation, error)
+	RecordConfigRevision(names.Tag, string) (state.ConfigRevision, error)
 	state.EndpointBinding
 }
 
@@ -178,6 +180,7 @@ type Unit interface {
 	AssignWithPolicy(state.AssignmentPolicy) error
 	AssignWithPlacement(*instance.Placement) error
 	ContainerInfo() (state.CloudContainer, error)
+	State() (*state.UnitState, error)
 }
 
 // Model defines a subset of the functionality provided by the
@@ -199,6 +202,7 @@ type Model interface {
 // state.Resources type, as required by the application facade. See
 // the state.Resources type for details on the methods.
 type Resources interface {
+	ListResources(string) (resource.ApplicationResources, error)
 	RemovePendingAppResources(string, map[string]string) error
 }
 
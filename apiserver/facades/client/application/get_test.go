@@ -31,7 +31,7 @@ import (
 type getSuite struct {
 	jujutesting.JujuConnSuite
 
-	applicationAPI *application.APIv13
+	applicationAPI *application.APIv14
 	authorizer     apiservertesting.FakeAuthorizer
 }
 
@@ -63,7 +63,7 @@ func (s *getSuite) SetUpTest(c *gc.C) {
 		nil, // CAAS Broker not used in this suite.
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	s.applicationAPI = &application.APIv13{api}
+	s.applicationAPI = &application.APIv14{api}
 }
 
 func (s *getSuite) TestClientApplicationGetSmokeTestV4(c *gc.C) {
@@ -77,7 +77,9 @@ func (s *getSuite) TestClientApplicationGetSmokeTestV4(c *gc.C) {
 							&application.APIv10{
 								&application.APIv11{
 									&application.APIv12{
-										s.applicationAPI,
+										&application.APIv13{
+											s.applicationAPI,
+										},
 									},
 								},
 							},
@@ -115,7 +117,9 @@ func (s *getSuite) TestClientApplicationGetSmokeTestV5(c *gc.C) {
 						&application.APIv10{
 							&application.APIv11{
 								&application.APIv12{
-									s.applicationAPI,
+									&application.APIv13{
+										s.applicationAPI,
+									},
 								},
 							},
 						},
@@ -162,6 +166,31 @@ func (s *getSuite) TestClientApplicationGetIAASModelSmokeTest(c *gc.C) {
 			},
 		},
 		ApplicationConfig: map[string]interface{}{
+			"action-results-max-age": map[string]interface{}{
+				"description": "The maximum age for the results of tasks run on this application before they are pruned, overriding the model's max-action-results-age",
+				"source":      "unset",
+				"type":        environschema.Tstring,
+			},
+			"action-timeout": map[string]interface{}{
+				"description": "The maximum time an action may run on this application's units before it is terminated and fails, eg \"2h\"",
+				"source":      "unset",
+				"type":        environschema.Tstring,
+			},
+			"hook-cpu-quota": map[string]interface{}{
+				"description": "The maximum CPU time of hook and action processes on this application's machine units, as a percentage of one CPU, eg \"50%\"",
+				"source":      "unset",
+				"type":        environschema.Tstring,
+			},
+			"hook-memory-limit": map[string]interface{}{
+				"description": "The maximum memory of hook and action processes on this application's machine units, eg \"512M\"",
+				"source":      "unset",
+				"type":        environschema.Tstring,
+			},
+			"hook-timeout": map[string]interface{}{
+				"description": "The maximum time a hook may run on this application's units before it is terminated and the unit is put into error, eg \"30m\"",
+				"source":      "unset",
+				"type":        environschema.Tstring,
+			},
 			"trust": map[string]interface{}{
 				"default":     false,
 				"description": "Does this application have access to trusted credentials",
@@ -198,6 +227,10 @@ func (s *getSuite) TestClientApplicationGetCAASModelSmokeTest(c *gc.C) {
 
 	schemaFields, defaults, err = application.AddTrustSchemaAndDefaults(schemaFields, defaults)
 	c.Assert(err, jc.ErrorIsNil)
+	schemaFields, err = application.AddActionRetentionSchema(schemaFields)
+	c.Assert(err, jc.ErrorIsNil)
+	schemaFields, err = application.AddHookLimitsSchema(schemaFields)
+	c.Assert(err, jc.ErrorIsNil)
 
 	appConfig, err := coreapplication.NewConfig(map[string]interface{}{"juju-external-hostname": "ext"}, schemaFields, defaults)
 	c.Assert(err, jc.ErrorIsNil)
@@ -261,7 +294,9 @@ func (s *getSuite) TestClientApplicationGetCAASModelSmokeTest(c *gc.C) {
 				&application.APIv11{
 					&application.APIv12{
 						&application.APIv13{
-							api,
+							&application.APIv14{
+								api,
+							},
 						},
 					},
 				},
@@ -344,6 +379,31 @@ var getTests = []struct {
 			},
 		},
 		ApplicationConfig: map[string]interface{}{
+			"action-results-max-age": map[string]interface{}{
+				"description": "The maximum age for the results of tasks run on this application before they are pruned, overriding the model's max-action-results-age",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"action-timeout": map[string]interface{}{
+				"description": "The maximum time an action may run on this application's units before it is terminated and fails, eg \"2h\"",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"hook-cpu-quota": map[string]interface{}{
+				"description": "The maximum CPU time of hook and action processes on this application's machine units, as a percentage of one CPU, eg \"50%\"",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"hook-memory-limit": map[string]interface{}{
+				"description": "The maximum memory of hook and action processes on this application's machine units, eg \"512M\"",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"hook-timeout": map[string]interface{}{
+				"description": "The maximum time a hook may run on this application's units before it is terminated and the unit is put into error, eg \"30m\"",
+				"source":      "unset",
+				"type":        "string",
+			},
 			"trust": map[string]interface{}{
 				"value":       false,
 				"default":     false,
@@ -406,6 +466,31 @@ var getTests = []struct {
 			},
 		},
 		ApplicationConfig: map[string]interface{}{
+			"action-results-max-age": map[string]interface{}{
+				"description": "The maximum age for the results of tasks run on this application before they are pruned, overriding the model's max-action-results-age",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"action-timeout": map[string]interface{}{
+				"description": "The maximum time an action may run on this application's units before it is terminated and fails, eg \"2h\"",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"hook-cpu-quota": map[string]interface{}{
+				"description": "The maximum CPU time of hook and action processes on this application's machine units, as a percentage of one CPU, eg \"50%\"",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"hook-memory-limit": map[string]interface{}{
+				"description": "The maximum memory of hook and action processes on this application's machine units, eg \"512M\"",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"hook-timeout": map[string]interface{}{
+				"description": "The maximum time a hook may run on this application's units before it is terminated and the unit is put into error, eg \"30m\"",
+				"source":      "unset",
+				"type":        "string",
+			},
 			"trust": map[string]interface{}{
 				"value":       false,
 				"default":     false,
@@ -426,6 +511,31 @@ var getTests = []struct {
 		CharmConfig: map[string]interface{}{},
 		Series:      "quantal",
 		ApplicationConfig: map[string]interface{}{
+			"action-results-max-age": map[string]interface{}{
+				"description": "The maximum age for the results of tasks run on this application before they are pruned, overriding the model's max-action-results-age",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"action-timeout": map[string]interface{}{
+				"description": "The maximum time an action may run on this application's units before it is terminated and fails, eg \"2h\"",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"hook-cpu-quota": map[string]interface{}{
+				"description": "The maximum CPU time of hook and action processes on this application's machine units, as a percentage of one CPU, eg \"50%\"",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"hook-memory-limit": map[string]interface{}{
+				"description": "The maximum memory of hook and action processes on this application's machine units, eg \"512M\"",
+				"source":      "unset",
+				"type":        "string",
+			},
+			"hook-timeout": map[string]interface{}{
+				"description": "The maximum time a hook may run on this application's units before it is terminated and the unit is put into error, eg \"30m\"",
+				"source":      "unset",
+				"type":        "string",
+			},
 			"trust": map[string]interface{}{
 				"value":       false,
 				"default":     false,
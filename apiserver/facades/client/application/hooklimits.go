@@ -0,0 +1,50 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/environschema.v1"
+
+	coreapplication "github.com/juju/juju/core/application"
+)
+
+var hookLimitsFields = environschema.Fields{
+	coreapplication.HookTimeoutOptionName: {
+		Description: "The maximum time a hook may run on this application's units before it is terminated and the unit is put into error, eg \"30m\"",
+		Type:        environschema.Tstring,
+		Group:       environschema.JujuGroup,
+	},
+	coreapplication.ActionTimeoutOptionName: {
+		Description: "The maximum time an action may run on this application's units before it is terminated and fails, eg \"2h\"",
+		Type:        environschema.Tstring,
+		Group:       environschema.JujuGroup,
+	},
+	coreapplication.HookCPUQuotaOptionName: {
+		Description: "The maximum CPU time of hook and action processes on this application's machine units, as a percentage of one CPU, eg \"50%\"",
+		Type:        environschema.Tstring,
+		Group:       environschema.JujuGroup,
+	},
+	coreapplication.HookMemoryLimitOptionName: {
+		Description: "The maximum memory of hook and action processes on this application's machine units, eg \"512M\"",
+		Type:        environschema.Tstring,
+		Group:       environschema.JujuGroup,
+	},
+}
+
+// AddHookLimitsSchema adds the hook limits schema fields to an existing
+// set of schema fields.
+func AddHookLimitsSchema(extra environschema.Fields) (environschema.Fields, error) {
+	fields := make(environschema.Fields)
+	for name, field := range hookLimitsFields {
+		fields[name] = field
+	}
+	for name, field := range extra {
+		if _, ok := hookLimitsFields[name]; ok {
+			return nil, errors.Errorf("config field %q clashes with common config", name)
+		}
+		fields[name] = field
+	}
+	return fields, nil
+}
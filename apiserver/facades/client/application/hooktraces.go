@@ -0,0 +1,48 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/hooktrace"
+)
+
+// unitHookTraces returns the recent hook traces saved by the unit's
+// uniter.
+func unitHookTraces(unit Unit) ([]params.HookTrace, error) {
+	unitState, err := unit.State()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	data, _ := unitState.HookTraces()
+	traces, err := hooktrace.Decode(data)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []params.HookTrace
+	for _, trace := range traces {
+		hookTrace := params.HookTrace{
+			Operation:        trace.Operation,
+			Started:          trace.Started,
+			LockWait:         trace.LockWait,
+			Duration:         trace.Duration,
+			Flush:            trace.Flush,
+			DroppedToolCalls: trace.DroppedToolCalls,
+			Error:            trace.Error,
+		}
+		for _, call := range trace.ToolCalls {
+			hookTrace.ToolCalls = append(hookTrace.ToolCalls, params.HookToolTrace{
+				Name:     call.Name,
+				Args:     call.Args,
+				Started:  call.Started,
+				Duration: call.Duration,
+				Failed:   call.Failed,
+			})
+		}
+		result = append(result, hookTrace)
+	}
+	return result, nil
+}
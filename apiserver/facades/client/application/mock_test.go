@@ -33,6 +33,7 @@ import (
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/resource"
 	"github.com/juju/juju/state"
 	statestorage "github.com/juju/juju/state/storage"
 	"github.com/juju/juju/storage"
@@ -104,6 +105,7 @@ type mockApplication struct {
 	exposed          bool
 	remote           bool
 	agentTools       *tools.Tools
+	relations        []application.Relation
 }
 
 func (m *mockApplication) Name() string {
@@ -266,6 +268,9 @@ func (a *mockApplication) AgentTools() (*tools.Tools, error) {
 
 func (a *mockApplication) Relations() ([]application.Relation, error) {
 	a.MethodCall(a, "Relations")
+	if a.relations != nil {
+		return a.relations, a.NextErr()
+	}
 	return []application.Relation{
 		&mockRelation{},
 	}, nil
@@ -372,6 +377,7 @@ type mockBackend struct {
 	allmodels                  []application.Model
 	users                      set.Strings
 	applications               map[string]*mockApplication
+	configRevisionAuthors      []string
 	remoteApplications         map[string]application.RemoteApplication
 	endpoints                  *[]state.Endpoint
 	relations                  map[int]*mockRelation
@@ -383,6 +389,24 @@ type mockBackend struct {
 	machines                   map[string]*mockMachine
 	generation                 *mockGeneration
 	spaceInfos                 network.SpaceInfos
+	resources                  *mockResources
+}
+
+func (m *mockBackend) Resources() (application.Resources, error) {
+	m.MethodCall(m, "Resources")
+	return m.resources, m.NextErr()
+}
+
+type mockResources struct {
+	application.Resources
+	jtesting.Stub
+
+	appResources resource.ApplicationResources
+}
+
+func (m *mockResources) ListResources(appName string) (resource.ApplicationResources, error) {
+	m.MethodCall(m, "ListResources", appName)
+	return m.appResources, m.NextErr()
 }
 
 type mockFilesystemAccess struct {
@@ -794,6 +818,13 @@ func (m *mockBackend) Branch(branchName string) (application.Generation, error)
 	return m.generation, nil
 }
 
+func (m *mockBackend) RecordConfigRevision(tag names.Tag, author string) (state.ConfigRevision, error) {
+	// Config revisions aren't recorded as stub calls, so that they don't
+	// get in the way of the checks of the other calls.
+	m.configRevisionAuthors = append(m.configRevisionAuthors, tag.Id()+":"+author)
+	return state.ConfigRevision{}, nil
+}
+
 type mockExternalController struct {
 	uuid string
 	info crossmodel.ControllerInfo
@@ -826,6 +857,7 @@ type mockRelation struct {
 	jtesting.Stub
 
 	tag             names.Tag
+	endpoint        *state.Endpoint
 	status          status.Status
 	message         string
 	suspended       bool
@@ -875,6 +907,9 @@ func (r *mockRelation) Unit(name string) (application.RelationUnit, error) {
 
 func (r *mockRelation) Endpoint(name string) (state.Endpoint, error) {
 	r.MethodCall(r, "Endpoint")
+	if r.endpoint != nil {
+		return *r.endpoint, nil
+	}
 	if name != "postgresql" {
 		return state.Endpoint{}, errors.NotFoundf("endpoint for %q", name)
 	}
@@ -941,6 +976,7 @@ type mockUnit struct {
 	machineId  string
 	name       string
 	agentTools *tools.Tools
+	hookTraces string
 }
 
 func (u *mockUnit) Tag() names.Tag {
@@ -1002,6 +1038,13 @@ func (u *mockUnit) ContainerInfo() (state.CloudContainer, error) {
 	return mockCloudContainer{}, nil
 }
 
+func (u *mockUnit) State() (*state.UnitState, error) {
+	u.MethodCall(u, "State")
+	unitState := state.NewUnitState()
+	unitState.SetHookTraces(u.hookTraces)
+	return unitState, u.NextErr()
+}
+
 func (u *mockUnit) AgentTools() (*tools.Tools, error) {
 	u.MethodCall(u, "AgentTools")
 	return u.agentTools, u.NextErr()
@@ -0,0 +1,486 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/juju/charm/v8"
+	charmresource "github.com/juju/charm/v8/resource"
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+const (
+	changeAdded   = "added"
+	changeRemoved = "removed"
+	changeChanged = "changed"
+
+	resourceFetch  = "fetch"
+	resourceUpload = "upload"
+	resourceKeep   = "keep"
+	resourceRemove = "remove"
+)
+
+// CharmRefreshReport isn't on the v13 API.
+func (api *APIv13) CharmRefreshReport(_, _ struct{}) {}
+
+// CharmRefreshReport reports what refreshing an application to the
+// given charm would change: config, endpoints, storage, devices, LXD
+// profile and resources, along with any reason the refresh would be
+// refused. Nothing is changed; the new charm must already have been
+// added to the model.
+func (api *APIBase) CharmRefreshReport(args params.CharmRefreshReportArgs) (params.CharmRefreshReport, error) {
+	if err := api.checkCanRead(); err != nil {
+		return params.CharmRefreshReport{}, errors.Trace(err)
+	}
+	app, err := api.backend.Application(args.ApplicationName)
+	if err != nil {
+		return params.CharmRefreshReport{}, errors.Trace(err)
+	}
+	curl, err := charm.ParseURL(args.CharmURL)
+	if err != nil {
+		return params.CharmRefreshReport{}, errors.Trace(err)
+	}
+	newCharm, err := api.backend.Charm(curl)
+	if err != nil {
+		return params.CharmRefreshReport{}, errors.Trace(err)
+	}
+	currentCharm, _, err := app.Charm()
+	if err != nil {
+		return params.CharmRefreshReport{}, errors.Trace(err)
+	}
+	currentURL, _ := app.CharmURL()
+	settings, err := app.CharmConfig(args.Generation)
+	if err != nil {
+		return params.CharmRefreshReport{}, errors.Trace(err)
+	}
+
+	oldMeta, newMeta := currentCharm.Meta(), newCharm.Meta()
+	report := params.CharmRefreshReport{
+		ApplicationName: args.ApplicationName,
+		NewCharmURL:     curl.String(),
+		Config:          configChanges(currentCharm.Config(), newCharm.Config(), settings),
+		Endpoints:       endpointChanges(oldMeta, newMeta),
+		Devices:         deviceChanges(oldMeta, newMeta),
+		LXDProfile:      lxdProfileChanges(charmLXDProfile(currentCharm), charmLXDProfile(newCharm)),
+	}
+	if currentURL != nil {
+		report.CharmURL = currentURL.String()
+	}
+	report.Storage, report.Blockers = storageChanges(oldMeta, newMeta)
+
+	report.BrokenRelations, err = brokenRelations(app, newCharm)
+	if err != nil {
+		return params.CharmRefreshReport{}, errors.Trace(err)
+	}
+	for _, key := range report.BrokenRelations {
+		report.Blockers = append(report.Blockers, fmt.Sprintf("would break relation %q", key))
+	}
+
+	report.Resources, err = api.resourceChanges(args.ApplicationName, curl, newMeta, args.ResourceUploads)
+	if err != nil {
+		return params.CharmRefreshReport{}, errors.Trace(err)
+	}
+
+	if newMeta.Subordinate != oldMeta.Subordinate {
+		report.Blockers = append(report.Blockers, "cannot change an application's subordinacy")
+	}
+	if reason := seriesBlocker(app.Series(), curl, newMeta, args.ForceSeries); reason != "" {
+		report.Blockers = append(report.Blockers, reason)
+	}
+	if api.modelType == state.ModelTypeCAAS {
+		if !reflect.DeepEqual(oldMeta.Deployment, newMeta.Deployment) {
+			report.Blockers = append(report.Blockers, strings.TrimSpace(deploymentInfoUpgradeMessage))
+		}
+		if !reflect.DeepEqual(oldMeta.Storage, newMeta.Storage) {
+			report.Blockers = append(report.Blockers, strings.TrimSpace(storageUpgradeMessage))
+		}
+		if !reflect.DeepEqual(oldMeta.Devices, newMeta.Devices) {
+			report.Blockers = append(report.Blockers, strings.TrimSpace(devicesUpgradeMessage))
+		}
+	}
+	return report, nil
+}
+
+// configChanges compares the config options of two charms. A type
+// change notes whether the application's current value survives it.
+func configChanges(oldConfig, newConfig *charm.Config, settings charm.Settings) []params.CharmRefreshChange {
+	oldOptions, newOptions := configOptions(oldConfig), configOptions(newConfig)
+	var changes []params.CharmRefreshChange
+	for _, name := range unionKeys(oldOptions, newOptions) {
+		oldOption, inOld := oldOptions[name]
+		newOption, inNew := newOptions[name]
+		switch {
+		case !inOld:
+			changes = append(changes, params.CharmRefreshChange{
+				Name: name, Change: changeAdded, New: newOption.Type,
+			})
+		case !inNew:
+			changes = append(changes, params.CharmRefreshChange{
+				Name: name, Change: changeRemoved, Old: oldOption.Type,
+			})
+		case oldOption.Type != newOption.Type:
+			change := params.CharmRefreshChange{
+				Name: name, Change: changeChanged, Old: oldOption.Type, New: newOption.Type,
+			}
+			if value, ok := settings[name]; ok && value != nil {
+				if len(newConfig.FilterSettings(charm.Settings{name: value})) == 0 {
+					change.Detail = "current value will be reset"
+				}
+			}
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+func configOptions(config *charm.Config) map[string]charm.Option {
+	if config == nil {
+		return nil
+	}
+	return config.Options
+}
+
+// endpointChanges compares the relation endpoints of two charms.
+func endpointChanges(oldMeta, newMeta *charm.Meta) []params.CharmRefreshChange {
+	oldRelations, newRelations := oldMeta.CombinedRelations(), newMeta.CombinedRelations()
+	var changes []params.CharmRefreshChange
+	for _, name := range unionKeys(oldRelations, newRelations) {
+		oldRelation, inOld := oldRelations[name]
+		newRelation, inNew := newRelations[name]
+		switch {
+		case !inOld:
+			changes = append(changes, params.CharmRefreshChange{
+				Name: name, Change: changeAdded, New: describeRelation(newRelation),
+			})
+		case !inNew:
+			changes = append(changes, params.CharmRefreshChange{
+				Name: name, Change: changeRemoved, Old: describeRelation(oldRelation),
+			})
+		case describeRelation(oldRelation) != describeRelation(newRelation):
+			changes = append(changes, params.CharmRefreshChange{
+				Name:   name,
+				Change: changeChanged,
+				Old:    describeRelation(oldRelation),
+				New:    describeRelation(newRelation),
+			})
+		}
+	}
+	return changes
+}
+
+func describeRelation(rel charm.Relation) string {
+	desc := fmt.Sprintf("%s %s", rel.Role, rel.Interface)
+	if rel.Scope == charm.ScopeContainer {
+		desc += " (container scope)"
+	}
+	return desc
+}
+
+// storageChanges compares the storage of two charms. It also returns
+// the reasons the storage changes would be refused, mirroring the
+// checks made when the charm is set.
+func storageChanges(oldMeta, newMeta *charm.Meta) ([]params.CharmRefreshChange, []string) {
+	var (
+		changes  []params.CharmRefreshChange
+		blockers []string
+	)
+	for _, name := range unionKeys(oldMeta.Storage, newMeta.Storage) {
+		oldStorage, inOld := oldMeta.Storage[name]
+		newStorage, inNew := newMeta.Storage[name]
+		switch {
+		case !inOld:
+			changes = append(changes, params.CharmRefreshChange{
+				Name: name, Change: changeAdded, New: describeStorage(newStorage),
+			})
+		case !inNew:
+			change := params.CharmRefreshChange{
+				Name: name, Change: changeRemoved, Old: describeStorage(oldStorage),
+			}
+			if oldStorage.CountMin > 0 {
+				blockers = append(blockers, fmt.Sprintf("required storage %q removed", name))
+			} else {
+				change.Detail = "refused if any instances of the storage exist"
+			}
+			changes = append(changes, change)
+		case describeStorage(oldStorage) != describeStorage(newStorage):
+			changes = append(changes, params.CharmRefreshChange{
+				Name:   name,
+				Change: changeChanged,
+				Old:    describeStorage(oldStorage),
+				New:    describeStorage(newStorage),
+			})
+			if reason := storageBlocker(name, oldStorage, newStorage); reason != "" {
+				blockers = append(blockers, reason)
+			}
+		}
+	}
+	return changes, blockers
+}
+
+func storageBlocker(name string, oldStorage, newStorage charm.Storage) string {
+	switch {
+	case newStorage.Type != oldStorage.Type:
+		return fmt.Sprintf("existing storage %q type changed from %q to %q", name, oldStorage.Type, newStorage.Type)
+	case newStorage.Shared != oldStorage.Shared:
+		return fmt.Sprintf("existing storage %q shared changed from %v to %v", name, oldStorage.Shared, newStorage.Shared)
+	case newStorage.ReadOnly != oldStorage.ReadOnly:
+		return fmt.Sprintf("existing storage %q read-only changed from %v to %v", name, oldStorage.ReadOnly, newStorage.ReadOnly)
+	case newStorage.Location != oldStorage.Location:
+		return fmt.Sprintf("existing storage %q location changed from %q to %q", name, oldStorage.Location, newStorage.Location)
+	case newStorage.CountMax != -1 && (oldStorage.CountMax == -1 || newStorage.CountMax < oldStorage.CountMax):
+		return fmt.Sprintf("existing storage %q range contracted: max decreased from %s to %d", name, countMax(oldStorage.CountMax), newStorage.CountMax)
+	case oldStorage.Location != "" && oldStorage.CountMax == 1 && newStorage.CountMax != 1:
+		return fmt.Sprintf("existing storage %q with location changed from single to multiple", name)
+	}
+	return ""
+}
+
+func describeStorage(s charm.Storage) string {
+	parts := []string{string(s.Type), fmt.Sprintf("count %d-%s", s.CountMin, countMax(s.CountMax))}
+	if s.Location != "" {
+		parts = append(parts, "location "+s.Location)
+	}
+	if s.Shared {
+		parts = append(parts, "shared")
+	}
+	if s.ReadOnly {
+		parts = append(parts, "read-only")
+	}
+	if s.MinimumSize > 0 {
+		parts = append(parts, fmt.Sprintf("minimum size %dM", s.MinimumSize))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func countMax(n int) string {
+	if n == -1 {
+		return "<unbounded>"
+	}
+	return fmt.Sprint(n)
+}
+
+// deviceChanges compares the devices of two charms.
+func deviceChanges(oldMeta, newMeta *charm.Meta) []params.CharmRefreshChange {
+	var changes []params.CharmRefreshChange
+	for _, name := range unionKeys(oldMeta.Devices, newMeta.Devices) {
+		oldDevice, inOld := oldMeta.Devices[name]
+		newDevice, inNew := newMeta.Devices[name]
+		switch {
+		case !inOld:
+			changes = append(changes, params.CharmRefreshChange{
+				Name: name, Change: changeAdded, New: describeDevice(newDevice),
+			})
+		case !inNew:
+			changes = append(changes, params.CharmRefreshChange{
+				Name: name, Change: changeRemoved, Old: describeDevice(oldDevice),
+			})
+		case describeDevice(oldDevice) != describeDevice(newDevice):
+			changes = append(changes, params.CharmRefreshChange{
+				Name:   name,
+				Change: changeChanged,
+				Old:    describeDevice(oldDevice),
+				New:    describeDevice(newDevice),
+			})
+		}
+	}
+	return changes
+}
+
+func describeDevice(d charm.Device) string {
+	return fmt.Sprintf("%s, count %d-%d", d.Type, d.CountMin, d.CountMax)
+}
+
+func charmLXDProfile(ch Charm) *charm.LXDProfile {
+	if profiler, ok := ch.(charm.LXDProfiler); ok {
+		return profiler.LXDProfile()
+	}
+	return nil
+}
+
+// lxdProfileChanges compares two LXD profiles. Config keys are
+// reported as "config/<key>" and devices as "devices/<name>".
+func lxdProfileChanges(oldProfile, newProfile *charm.LXDProfile) []params.CharmRefreshChange {
+	if oldProfile == nil {
+		oldProfile = charm.NewLXDProfile()
+	}
+	if newProfile == nil {
+		newProfile = charm.NewLXDProfile()
+	}
+	changes := valueChanges("config/", oldProfile.Config, newProfile.Config)
+	oldDevices, newDevices := make(map[string]string), make(map[string]string)
+	for name, device := range oldProfile.Devices {
+		oldDevices[name] = describeProfileDevice(device)
+	}
+	for name, device := range newProfile.Devices {
+		newDevices[name] = describeProfileDevice(device)
+	}
+	return append(changes, valueChanges("devices/", oldDevices, newDevices)...)
+}
+
+func describeProfileDevice(device map[string]string) string {
+	var parts []string
+	for key, value := range device {
+		parts = append(parts, key+"="+value)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
+
+func valueChanges(prefix string, oldValues, newValues map[string]string) []params.CharmRefreshChange {
+	var changes []params.CharmRefreshChange
+	for _, key := range unionKeys(oldValues, newValues) {
+		oldValue, inOld := oldValues[key]
+		newValue, inNew := newValues[key]
+		switch {
+		case !inOld:
+			changes = append(changes, params.CharmRefreshChange{
+				Name: prefix + key, Change: changeAdded, New: newValue,
+			})
+		case !inNew:
+			changes = append(changes, params.CharmRefreshChange{
+				Name: prefix + key, Change: changeRemoved, Old: oldValue,
+			})
+		case oldValue != newValue:
+			changes = append(changes, params.CharmRefreshChange{
+				Name: prefix + key, Change: changeChanged, Old: oldValue, New: newValue,
+			})
+		}
+	}
+	return changes
+}
+
+// brokenRelations returns the keys of the application's relations
+// whose endpoints the new charm doesn't implement. As when the charm
+// is set, a peer relation of a single unit application doesn't count.
+func brokenRelations(app Application, newCharm Charm) ([]string, error) {
+	relations, err := app.Relations()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var broken []string
+	for _, rel := range relations {
+		ep, err := rel.Endpoint(app.Name())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if ep.ImplementedBy(newCharm) {
+			continue
+		}
+		if ep.Role == charm.RolePeer {
+			units, err := app.AllUnits()
+			if err == nil && len(units) == 1 {
+				continue
+			}
+		}
+		broken = append(broken, rel.Tag().Id())
+	}
+	sort.Strings(broken)
+	return broken, nil
+}
+
+// resourceChanges reports what the refresh would do with each of the
+// new charm's resources and any resources it no longer declares.
+// Store resources are fetched at the latest revision known to the
+// controller; resources the operator uploaded stay pinned unless
+// uploaded again.
+func (api *APIBase) resourceChanges(
+	appName string, curl *charm.URL, newMeta *charm.Meta, uploads []string,
+) ([]params.CharmRefreshResourceChange, error) {
+	resources, err := api.backend.Resources()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	appResources, err := resources.ListResources(appName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	current := make(map[string]charmresource.Resource)
+	for _, res := range appResources.Resources {
+		current[res.Name] = res.Resource
+	}
+	latest := make(map[string]charmresource.Resource)
+	for _, res := range appResources.CharmStoreResources {
+		latest[res.Name] = res
+	}
+	uploadSet := set.NewStrings(uploads...)
+	// Resources aren't refreshed for charmhub charms.
+	refreshStore := !charm.CharmHub.Matches(curl.Schema)
+
+	var changes []params.CharmRefreshResourceChange
+	for _, name := range unionKeys(current, newMeta.Resources) {
+		res, inCurrent := current[name]
+		meta, inNew := newMeta.Resources[name]
+		change := params.CharmRefreshResourceChange{Name: name}
+		if inCurrent {
+			change.Type = res.Type.String()
+			change.Origin = res.Origin.String()
+			change.Revision = intPtr(res.Revision)
+		}
+		switch {
+		case !inNew:
+			change.Action = resourceRemove
+		case uploadSet.Contains(name):
+			change.Type = meta.Type.String()
+			change.Action = resourceUpload
+		case !inCurrent:
+			change.Type = meta.Type.String()
+			change.Action = resourceFetch
+		case res.Origin == charmresource.OriginStore && refreshStore:
+			change.Action = resourceKeep
+			if storeRes, ok := latest[name]; ok && storeRes.Revision != res.Revision {
+				change.Action = resourceFetch
+				change.NewRevision = intPtr(storeRes.Revision)
+			}
+		default:
+			change.Action = resourceKeep
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+// seriesBlocker mirrors the series checks made when the charm is set.
+func seriesBlocker(appSeries string, curl *charm.URL, newMeta *charm.Meta, forceSeries bool) string {
+	if curl.Series != "" {
+		if curl.Series != appSeries {
+			return "cannot change an application's series"
+		}
+		return ""
+	}
+	if forceSeries {
+		return ""
+	}
+	charmSeries := newMeta.ComputedSeries()
+	if set.NewStrings(charmSeries...).Contains(appSeries) {
+		return ""
+	}
+	supported := "no series"
+	if len(charmSeries) > 0 {
+		supported = strings.Join(charmSeries, ", ")
+	}
+	return fmt.Sprintf("series %q not supported by the new charm; only these series are supported: %v", appSeries, supported)
+}
+
+// unionKeys returns the sorted keys of two maps with string keys.
+func unionKeys(a, b interface{}) []string {
+	keys := set.NewStrings()
+	for _, m := range []interface{}{a, b} {
+		for _, key := range reflect.ValueOf(m).MapKeys() {
+			keys.Add(key.String())
+		}
+	}
+	return keys.SortedValues()
+}
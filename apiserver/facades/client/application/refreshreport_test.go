@@ -0,0 +1,220 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"github.com/juju/charm/v8"
+	charmresource "github.com/juju/charm/v8/resource"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/client/application"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/resource"
+	"github.com/juju/juju/state"
+)
+
+func (s *ApplicationSuite) setUpRefreshReport() {
+	app := s.backend.applications["postgresql"]
+	app.charm.meta = &charm.Meta{
+		Name: "postgresql",
+		Provides: map[string]charm.Relation{
+			"db": {Name: "db", Role: charm.RoleProvider, Interface: "pgsql", Scope: charm.ScopeGlobal},
+		},
+		Storage: map[string]charm.Storage{
+			"pgdata": {Name: "pgdata", Type: charm.StorageFilesystem, CountMin: 1, CountMax: 1, Location: "/srv"},
+		},
+	}
+	app.relations = []application.Relation{
+		&mockRelation{
+			tag: names.NewRelationTag("wordpress:db postgresql:db"),
+			endpoint: &state.Endpoint{
+				ApplicationName: "postgresql",
+				Relation:        charm.Relation{Name: "db", Role: charm.RoleProvider, Interface: "pgsql", Scope: charm.ScopeGlobal},
+			},
+		},
+	}
+	s.backend.charm = &mockCharm{
+		meta: &charm.Meta{
+			Name:   "postgresql",
+			Series: []string{"quantal"},
+			Provides: map[string]charm.Relation{
+				"db-admin": {Name: "db-admin", Role: charm.RoleProvider, Interface: "pgsql", Scope: charm.ScopeGlobal},
+			},
+			Storage: map[string]charm.Storage{
+				"pgdata": {Name: "pgdata", Type: charm.StorageFilesystem, CountMin: 1, CountMax: 1, Location: "/srv"},
+				"logs":   {Name: "logs", Type: charm.StorageFilesystem, CountMin: 0, CountMax: -1},
+			},
+			Devices: map[string]charm.Device{
+				"gpu": {Name: "gpu", Type: "nvidia.com/gpu", CountMin: 1, CountMax: 2},
+			},
+			Resources: map[string]charmresource.Meta{
+				"image":  {Name: "image", Type: charmresource.TypeFile, Path: "image.tgz"},
+				"blob":   {Name: "blob", Type: charmresource.TypeFile, Path: "blob.tgz"},
+				"extras": {Name: "extras", Type: charmresource.TypeFile, Path: "extras.tgz"},
+			},
+		},
+		config: &charm.Config{
+			Options: map[string]charm.Option{
+				"stringOption": {Type: "string"},
+				"intOption":    {Type: "string"},
+				"boolOption":   {Type: "boolean"},
+			},
+		},
+		lxdProfile: &charm.LXDProfile{
+			Config: map[string]string{
+				"security.nested": "true",
+			},
+			Devices: map[string]map[string]string{
+				"tun": {"type": "unix-char", "path": "/dev/net/tun"},
+			},
+		},
+	}
+	storeResource := func(name string, revision int) charmresource.Resource {
+		return charmresource.Resource{
+			Meta:     charmresource.Meta{Name: name, Type: charmresource.TypeFile},
+			Origin:   charmresource.OriginStore,
+			Revision: revision,
+		}
+	}
+	s.backend.resources = &mockResources{
+		appResources: resource.ApplicationResources{
+			Resources: []resource.Resource{
+				{Resource: storeResource("image", 2)},
+				{Resource: charmresource.Resource{
+					Meta:     charmresource.Meta{Name: "blob", Type: charmresource.TypeFile},
+					Origin:   charmresource.OriginUpload,
+					Revision: 0,
+				}},
+				{Resource: storeResource("legacy", 1)},
+			},
+			CharmStoreResources: []charmresource.Resource{
+				storeResource("image", 3),
+				storeResource("blob", 1),
+				storeResource("legacy", 1),
+			},
+		},
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func (s *ApplicationSuite) TestCharmRefreshReport(c *gc.C) {
+	s.setUpRefreshReport()
+
+	report, err := s.api.CharmRefreshReport(params.CharmRefreshReportArgs{
+		ApplicationName: "postgresql",
+		CharmURL:        "cs:postgresql-43",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report, jc.DeepEquals, params.CharmRefreshReport{
+		ApplicationName: "postgresql",
+		CharmURL:        "cs:postgresql-42",
+		NewCharmURL:     "cs:postgresql-43",
+		Config: []params.CharmRefreshChange{
+			{Name: "boolOption", Change: "added", New: "boolean"},
+			{Name: "intOption", Change: "changed", Old: "int", New: "string", Detail: "current value will be reset"},
+		},
+		Endpoints: []params.CharmRefreshChange{
+			{Name: "db", Change: "removed", Old: "provider pgsql"},
+			{Name: "db-admin", Change: "added", New: "provider pgsql"},
+		},
+		Storage: []params.CharmRefreshChange{
+			{Name: "logs", Change: "added", New: "filesystem, count 0-<unbounded>"},
+		},
+		Devices: []params.CharmRefreshChange{
+			{Name: "gpu", Change: "added", New: "nvidia.com/gpu, count 1-2"},
+		},
+		LXDProfile: []params.CharmRefreshChange{
+			{Name: "config/security.nested", Change: "changed", Old: "false", New: "true"},
+			{Name: "devices/tun", Change: "added", New: "path=/dev/net/tun type=unix-char"},
+		},
+		Resources: []params.CharmRefreshResourceChange{
+			{Name: "blob", Type: "file", Action: "keep", Origin: "upload", Revision: intPtr(0)},
+			{Name: "extras", Type: "file", Action: "fetch"},
+			{Name: "image", Type: "file", Action: "fetch", Origin: "store", Revision: intPtr(2), NewRevision: intPtr(3)},
+			{Name: "legacy", Type: "file", Action: "remove", Origin: "store", Revision: intPtr(1)},
+		},
+		BrokenRelations: []string{"wordpress:db postgresql:db"},
+		Blockers:        []string{`would break relation "wordpress:db postgresql:db"`},
+	})
+	s.backend.CheckCallNames(c, "Application", "Charm", "Resources")
+	s.backend.resources.CheckCall(c, 0, "ListResources", "postgresql")
+	// Nothing is changed.
+	s.backend.applications["postgresql"].CheckCallNames(c, "Charm", "CharmURL", "CharmConfig", "Relations", "Name", "Series")
+}
+
+func (s *ApplicationSuite) TestCharmRefreshReportResourceUploads(c *gc.C) {
+	s.setUpRefreshReport()
+
+	report, err := s.api.CharmRefreshReport(params.CharmRefreshReportArgs{
+		ApplicationName: "postgresql",
+		CharmURL:        "cs:postgresql-43",
+		ResourceUploads: []string{"image", "blob"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.Resources, jc.DeepEquals, []params.CharmRefreshResourceChange{
+		{Name: "blob", Type: "file", Action: "upload", Origin: "upload", Revision: intPtr(0)},
+		{Name: "extras", Type: "file", Action: "fetch"},
+		{Name: "image", Type: "file", Action: "upload", Origin: "store", Revision: intPtr(2)},
+		{Name: "legacy", Type: "file", Action: "remove", Origin: "store", Revision: intPtr(1)},
+	})
+}
+
+func (s *ApplicationSuite) TestCharmRefreshReportBlockers(c *gc.C) {
+	s.setUpRefreshReport()
+	newMeta := s.backend.charm.meta
+	newMeta.Series = []string{"bionic", "focal"}
+	newMeta.Subordinate = true
+	newMeta.Provides["db"] = charm.Relation{Name: "db", Role: charm.RoleProvider, Interface: "pgsql", Scope: charm.ScopeGlobal}
+	newMeta.Storage = map[string]charm.Storage{
+		"logs": {Name: "logs", Type: charm.StorageBlock, CountMin: 0, CountMax: 1},
+	}
+	s.backend.applications["postgresql"].charm.meta.Storage["logs"] = charm.Storage{
+		Name: "logs", Type: charm.StorageFilesystem, CountMin: 0, CountMax: -1,
+	}
+
+	report, err := s.api.CharmRefreshReport(params.CharmRefreshReportArgs{
+		ApplicationName: "postgresql",
+		CharmURL:        "cs:postgresql-43",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.BrokenRelations, gc.HasLen, 0)
+	c.Assert(report.Storage, jc.DeepEquals, []params.CharmRefreshChange{{
+		Name:   "logs",
+		Change: "changed",
+		Old:    "filesystem, count 0-<unbounded>",
+		New:    "block, count 0-1",
+	}, {
+		Name:   "pgdata",
+		Change: "removed",
+		Old:    "filesystem, count 1-1, location /srv",
+	}})
+	c.Assert(report.Blockers, jc.DeepEquals, []string{
+		`existing storage "logs" type changed from "filesystem" to "block"`,
+		`required storage "pgdata" removed`,
+		"cannot change an application's subordinacy",
+		`series "quantal" not supported by the new charm; only these series are supported: bionic, focal`,
+	})
+
+	report, err = s.api.CharmRefreshReport(params.CharmRefreshReportArgs{
+		ApplicationName: "postgresql",
+		CharmURL:        "cs:postgresql-43",
+		ForceSeries:     true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.Blockers, gc.HasLen, 3)
+}
+
+func (s *ApplicationSuite) TestCharmRefreshReportCharmNotFound(c *gc.C) {
+	s.backend.charm = nil
+	_, err := s.api.CharmRefreshReport(params.CharmRefreshReportArgs{
+		ApplicationName: "postgresql",
+		CharmURL:        "cs:postgresql-43",
+	})
+	c.Assert(err, gc.ErrorMatches, `charm "cs:postgresql-43" not found`)
+}
@@ -36,9 +36,20 @@ type Backend interface {
 	ControllerNodes() ([]state.ControllerNode, error)
 }
 
+// APIv3 provides the Backups API facade for version 3.
+type APIv3 struct {
+	*APIv4
+}
+
+// APIv4 provides the Backups API facade for version 4.
+type APIv4 struct {
+	*API
+}
+
 // API provides backup-specific API methods.
 type API struct {
 	backend Backend
+	hub     facade.Hub
 	paths   *backups.Paths
 
 	// machineID is the ID of the machine where the API server is running.
@@ -46,7 +57,7 @@ type API struct {
 }
 
 // NewAPI creates a new instance of the Backups API facade.
-func NewAPI(backend Backend, resources facade.Resources, authorizer facade.Authorizer) (*API, error) {
+func NewAPI(backend Backend, hub facade.Hub, resources facade.Resources, authorizer facade.Authorizer) (*API, error) {
 	isControllerAdmin, err := authorizer.HasPermission(permission.SuperuserAccess, backend.ControllerTag())
 	if err != nil && !errors.IsNotFound(err) {
 		return nil, errors.Trace(err)
@@ -94,12 +105,37 @@ func NewAPI(backend Backend, resources facade.Resources, authorizer facade.Autho
 	}
 	b := API{
 		backend:   backend,
+		hub:       hub,
 		paths:     &paths,
 		machineID: machineID,
 	}
 	return &b, nil
 }
 
+// NewAPIv3 creates a new instance of the Backups API facade for version 3.
+func NewAPIv3(backend Backend, hub facade.Hub, resources facade.Resources, authorizer facade.Authorizer) (*APIv3, error) {
+	api, err := NewAPIv4(backend, hub, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv3{api}, nil
+}
+
+// NewAPIv4 creates a new instance of the Backups API facade for version 4.
+func NewAPIv4(backend Backend, hub facade.Hub, resources facade.Resources, authorizer facade.Authorizer) (*APIv4, error) {
+	api, err := NewAPI(backend, hub, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv4{api}, nil
+}
+
+// Restore isn't on the v3 API.
+func (*APIv3) Restore(_, _ struct{}) {}
+
+// Contents isn't on the v4 API.
+func (*APIv4) Contents(_, _ struct{}) {}
+
 func extractResourceValue(resources facade.Resources, key string) (string, error) {
 	res := resources.Get(key)
 	strRes, ok := res.(common.StringResource)
@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/names/v4"
+	"github.com/juju/pubsub"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -25,6 +26,7 @@ import (
 
 type backupsSuite struct {
 	testing.JujuConnSuite
+	hub        *pubsub.StructuredHub
 	resources  *common.Resources
 	authorizer *apiservertesting.FakeAuthorizer
 	api        *backupsAPI.API
@@ -38,6 +40,7 @@ func (s *backupsSuite) SetUpTest(c *gc.C) {
 	s.JujuConnSuite.SetUpTest(c)
 
 	s.machineTag = names.NewMachineTag("0")
+	s.hub = pubsub.NewStructuredHub(nil)
 	s.resources = common.NewResources()
 	s.resources.RegisterNamed("dataDir", common.StringResource(s.DataDir()))
 	s.resources.RegisterNamed("machineID", common.StringResource(s.machineTag.Id()))
@@ -64,7 +67,7 @@ func (s *backupsSuite) SetUpTest(c *gc.C) {
 		controllerNodesF: func() ([]state.ControllerNode, error) { return nil, nil },
 		machineF:         func(id string) (backupsAPI.Machine, error) { return &testMachine{}, nil },
 	}
-	s.api, err = backupsAPI.NewAPI(shim, s.resources, s.authorizer)
+	s.api, err = backupsAPI.NewAPI(shim, s.hub, s.resources, s.authorizer)
 	c.Assert(err, jc.ErrorIsNil)
 	s.meta = backupstesting.NewMetadataStarted()
 }
@@ -89,13 +92,13 @@ func (s *backupsSuite) setBackups(c *gc.C, meta *backups.Metadata, err string) *
 }
 
 func (s *backupsSuite) TestNewAPIOkay(c *gc.C) {
-	_, err := backupsAPI.NewAPI(&stateShim{State: s.State, Model: s.Model}, s.resources, s.authorizer)
+	_, err := backupsAPI.NewAPI(&stateShim{State: s.State, Model: s.Model}, s.hub, s.resources, s.authorizer)
 	c.Check(err, jc.ErrorIsNil)
 }
 
 func (s *backupsSuite) TestNewAPINotAuthorized(c *gc.C) {
 	s.authorizer.Tag = names.NewApplicationTag("eggs")
-	_, err := backupsAPI.NewAPI(&stateShim{State: s.State, Model: s.Model}, s.resources, s.authorizer)
+	_, err := backupsAPI.NewAPI(&stateShim{State: s.State, Model: s.Model}, s.hub, s.resources, s.authorizer)
 	c.Check(errors.Cause(err), gc.Equals, apiservererrors.ErrPerm)
 }
 
@@ -104,7 +107,7 @@ func (s *backupsSuite) TestNewAPIHostedEnvironmentFails(c *gc.C) {
 	defer otherState.Close()
 	otherModel, err := otherState.Model()
 	c.Assert(err, jc.ErrorIsNil)
-	_, err = backupsAPI.NewAPI(&stateShim{State: otherState, Model: otherModel}, s.resources, s.authorizer)
+	_, err = backupsAPI.NewAPI(&stateShim{State: otherState, Model: otherModel}, s.hub, s.resources, s.authorizer)
 	c.Check(err, gc.ErrorMatches, "backups are only supported from the controller model\nUse juju switch to select the controller model")
 }
 
@@ -115,6 +118,6 @@ func (s *backupsSuite) TestBackupsCAASFails(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 
 	isController := true
-	_, err = backupsAPI.NewAPI(&stateShim{State: otherState, Model: otherModel, isController: &isController}, s.resources, s.authorizer)
+	_, err = backupsAPI.NewAPI(&stateShim{State: otherState, Model: otherModel, isController: &isController}, s.hub, s.resources, s.authorizer)
 	c.Assert(err, gc.ErrorMatches, "backups on kubernetes controllers not supported")
 }
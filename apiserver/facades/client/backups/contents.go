@@ -0,0 +1,62 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state/backups"
+)
+
+// Contents describes the models, applications and units, and the
+// database collections, held in the identified backup archive, without
+// restoring it.
+func (a *API) Contents(args params.BackupsContentsArgs) (params.BackupsContentsResult, error) {
+	backupsMethods, closer := newBackups(a.backend)
+	defer closer.Close()
+
+	contents, err := backupsMethods.Contents(args.ID)
+	if err != nil {
+		return params.BackupsContentsResult{}, errors.Trace(err)
+	}
+	return ContentsResult(contents), nil
+}
+
+// ContentsResult converts the archive contents into their API form.
+func ContentsResult(contents *backups.ArchiveContents) params.BackupsContentsResult {
+	result := params.BackupsContentsResult{
+		ControllerUUID: contents.ControllerUUID,
+		JujuVersion:    contents.JujuVersion,
+		Models:         make([]params.BackupsModelContents, len(contents.Models)),
+		Collections:    make([]params.BackupsCollectionContents, len(contents.Collections)),
+	}
+	for i, m := range contents.Models {
+		model := params.BackupsModelContents{
+			UUID:         m.UUID,
+			Name:         m.Name,
+			Owner:        m.Owner,
+			Type:         m.Type,
+			Machines:     m.Machines,
+			Applications: make([]params.BackupsApplicationContents, len(m.Applications)),
+		}
+		for j, app := range m.Applications {
+			model.Applications[j] = params.BackupsApplicationContents{
+				Name:     app.Name,
+				CharmURL: app.CharmURL,
+				Units:    app.Units,
+			}
+		}
+		result.Models[i] = model
+	}
+	for i, coll := range contents.Collections {
+		result.Collections[i] = params.BackupsCollectionContents{
+			Database:  coll.Database,
+			Name:      coll.Name,
+			Documents: coll.Documents,
+			Size:      coll.Size,
+		}
+	}
+	return result
+}
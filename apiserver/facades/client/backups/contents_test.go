@@ -0,0 +1,64 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state/backups"
+)
+
+func (s *backupsSuite) TestContentsOkay(c *gc.C) {
+	fake := s.setBackups(c, s.meta, "")
+	fake.ArchiveContents = &backups.ArchiveContents{
+		ControllerUUID: "controller-uuid",
+		JujuVersion:    version.MustParse("2.9.1"),
+		Models: []backups.ModelContents{{
+			UUID:     "model-uuid",
+			Name:     "controller",
+			Owner:    "admin",
+			Type:     "iaas",
+			Machines: 1,
+			Applications: []backups.ApplicationContents{
+				{Name: "mysql", CharmURL: "cs:mysql-1", Units: 2},
+			},
+		}},
+		Collections: []backups.CollectionContents{
+			{Database: "juju", Name: "units", Documents: 2, Size: 512},
+		},
+	}
+
+	result, err := s.api.Contents(params.BackupsContentsArgs{ID: "some-id"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(fake.Calls, jc.DeepEquals, []string{"Contents"})
+	c.Check(fake.IDArg, gc.Equals, "some-id")
+	c.Check(result, jc.DeepEquals, params.BackupsContentsResult{
+		ControllerUUID: "controller-uuid",
+		JujuVersion:    version.MustParse("2.9.1"),
+		Models: []params.BackupsModelContents{{
+			UUID:     "model-uuid",
+			Name:     "controller",
+			Owner:    "admin",
+			Type:     "iaas",
+			Machines: 1,
+			Applications: []params.BackupsApplicationContents{
+				{Name: "mysql", CharmURL: "cs:mysql-1", Units: 2},
+			},
+		}},
+		Collections: []params.BackupsCollectionContents{
+			{Database: "juju", Name: "units", Documents: 2, Size: 512},
+		},
+	})
+}
+
+func (s *backupsSuite) TestContentsError(c *gc.C) {
+	s.setBackups(c, s.meta, "failed!")
+
+	_, err := s.api.Contents(params.BackupsContentsArgs{ID: "some-id"})
+	c.Assert(err, gc.ErrorMatches, "failed!")
+}
@@ -4,6 +4,10 @@
 package backups
 
 var (
-	NewBackups     = &newBackups
-	WaitUntilReady = &waitUntilReady
+	NewBackups       = &newBackups
+	NewAgentServices = &newAgentServices
+	WaitUntilReady   = &waitUntilReady
+
+	RestoreLockdownTimeout = &restoreLockdownTimeout
+	LockdownController     = lockdownController
 )
@@ -0,0 +1,195 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names/v4"
+	"github.com/juju/utils/v2"
+
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	pubsubbackups "github.com/juju/juju/pubsub/backups"
+	"github.com/juju/juju/service"
+	"github.com/juju/juju/service/common"
+	"github.com/juju/juju/state/backups"
+	jujuversion "github.com/juju/juju/version"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.backups")
+
+// agentRestartDelay is how long the controller agent waits after a
+// restore before restarting itself, giving the API call time to
+// return to the client.
+var agentRestartDelay = 5 * time.Second
+
+// restoreLockdownTimeout is how long a restore waits for the controller
+// agent to stop its database workers before giving up.
+var restoreLockdownTimeout = 2 * time.Minute
+
+var newAgentServices = func(hub facade.Hub, dataDir, machineID string) backups.AgentServices {
+	return &agentServices{
+		hub:       hub,
+		dataDir:   dataDir,
+		machineID: machineID,
+	}
+}
+
+// Restore replaces the controller's state with the contents of the
+// identified backup archive. The archive is checked before any agents
+// or workers are stopped. Once the restore has finished, successfully
+// or not, the controller agent is restarted, so clients should expect
+// to lose their connection shortly after this call returns.
+func (a *API) Restore(args params.BackupsRestoreArgs) error {
+	backupsMethods, closer := newBackups(a.backend)
+	defer closer.Close()
+
+	// Restoring replaces the replicaset contents wholesale, which
+	// other controller nodes would immediately contradict.
+	nodes, err := a.backend.ControllerNodes()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(nodes) > 1 {
+		return errors.Errorf(
+			"cannot restore onto a controller with %d nodes in HA; remove the other controller machines first",
+			len(nodes))
+	}
+
+	session := a.backend.MongoSession().Copy()
+	defer session.Close()
+
+	mgoInfo, err := mongoInfo(a.paths.DataDir, a.machineID)
+	if err != nil {
+		return errors.Annotatef(err, "getting mongo info")
+	}
+	dbInfo, err := backups.NewDBInfo(mgoInfo, session)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	logger.Infof("restoring backup %q onto machine %q", args.ID, a.machineID)
+	err = backupsMethods.Restore(args.ID, backups.RestoreArgs{
+		DBInfo:         dbInfo,
+		Session:        session,
+		Paths:          a.paths,
+		ControllerUUID: a.backend.ControllerTag().Id(),
+		MachineID:      a.machineID,
+		Version:        jujuversion.Current,
+		Agents:         newAgentServices(a.hub, a.paths.DataDir, a.machineID),
+	})
+	return errors.Trace(err)
+}
+
+// lockdownController asks the controller agent on the machine to stop
+// the workers that write to the database, and waits until it has.
+func lockdownController(hub facade.Hub, machineID string) error {
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	responseTopic := pubsubbackups.RestoreLockdownTopic + ".response." + uuid.String()
+	responses := make(chan pubsubbackups.RestoreLockdownResponse, 1)
+	unsubscribe, err := hub.Subscribe(responseTopic, func(_ string, resp pubsubbackups.RestoreLockdownResponse, err error) {
+		if err != nil {
+			logger.Errorf("unable to decode restore lockdown response: %v", err)
+			return
+		}
+		select {
+		case responses <- resp:
+		default:
+		}
+	})
+	if err != nil {
+		return errors.Annotate(err, "subscribing to restore lockdown responses")
+	}
+	defer unsubscribe()
+
+	req := pubsubbackups.RestoreLockdownRequest{
+		MachineID:     machineID,
+		ResponseTopic: responseTopic,
+	}
+	if _, err := hub.Publish(pubsubbackups.RestoreLockdownTopic, req); err != nil {
+		return errors.Annotate(err, "publishing restore lockdown request")
+	}
+	select {
+	case resp := <-responses:
+		if resp.Error != "" {
+			return errors.New(resp.Error)
+		}
+		return nil
+	case <-time.After(restoreLockdownTimeout):
+		return errors.Timeoutf("waiting for controller workers to stop")
+	}
+}
+
+// agentServices implements backups.AgentServices for the agents on the
+// controller machine serving the restore request.
+type agentServices struct {
+	hub       facade.Hub
+	dataDir   string
+	machineID string
+}
+
+// Stop stops the controller workers that write to the database, and
+// any unit agents running on the controller machine. The machine agent
+// is left running as it is serving the restore itself.
+func (s *agentServices) Stop() error {
+	// The controller workers writing to the database would race the
+	// restore, so they must be stopped before it replaces the data.
+	if err := lockdownController(s.hub, s.machineID); err != nil {
+		return errors.Annotate(err, "stopping controller workers")
+	}
+	_, unitAgents, _, err := service.FindAgents(s.dataDir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, name := range unitAgents {
+		svc, err := service.DiscoverService(agentServiceName(name), common.Conf{})
+		if err != nil {
+			return errors.Annotatef(err, "failed to find service for %q", name)
+		}
+		if err := svc.Stop(); err != nil {
+			return errors.Annotatef(err, "failed to stop %q", name)
+		}
+	}
+	return nil
+}
+
+// Start schedules a restart of the machine agent, so that the workers
+// stopped by Stop are started again with the restored database and
+// agent config, and starts the unit agents stopped by Stop.
+func (s *agentServices) Start() error {
+	// The machine agent is restarted even if the unit agents can't
+	// be started, as its workers stay stopped until it is.
+	machineService := agentServiceName(names.NewMachineTag(s.machineID).String())
+	time.AfterFunc(agentRestartDelay, func() {
+		logger.Infof("restarting %q after restore", machineService)
+		if err := service.Restart(machineService); err != nil {
+			logger.Errorf("cannot restart controller agent after restore: %v", err)
+		}
+	})
+
+	_, unitAgents, _, err := service.FindAgents(s.dataDir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, name := range unitAgents {
+		svc, err := service.DiscoverService(agentServiceName(name), common.Conf{})
+		if err != nil {
+			return errors.Annotatef(err, "failed to find service for %q", name)
+		}
+		if err := svc.Start(); err != nil {
+			return errors.Annotatef(err, "failed to start %q", name)
+		}
+	}
+	return nil
+}
+
+func agentServiceName(agentName string) string {
+	return "jujud-" + agentName
+}
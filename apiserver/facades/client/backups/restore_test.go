@@ -0,0 +1,127 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facade"
+	backupsAPI "github.com/juju/juju/apiserver/facades/client/backups"
+	"github.com/juju/juju/apiserver/params"
+	pubsubbackups "github.com/juju/juju/pubsub/backups"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	jujuversion "github.com/juju/juju/version"
+)
+
+type fakeAgentServices struct {
+	backups.AgentServices
+	hub       facade.Hub
+	dataDir   string
+	machineID string
+}
+
+func (s *backupsSuite) patchAgentServices() *fakeAgentServices {
+	agents := &fakeAgentServices{}
+	s.PatchValue(backupsAPI.NewAgentServices, func(hub facade.Hub, dataDir, machineID string) backups.AgentServices {
+		agents.hub = hub
+		agents.dataDir = dataDir
+		agents.machineID = machineID
+		return agents
+	})
+	return agents
+}
+
+func (s *backupsSuite) respondToRestoreLockdown(c *gc.C, errMessage string) <-chan pubsubbackups.RestoreLockdownRequest {
+	requests := make(chan pubsubbackups.RestoreLockdownRequest, 1)
+	unsubscribe, err := s.hub.Subscribe(pubsubbackups.RestoreLockdownTopic, func(_ string, req pubsubbackups.RestoreLockdownRequest, err error) {
+		c.Check(err, jc.ErrorIsNil)
+		requests <- req
+		_, err = s.hub.Publish(req.ResponseTopic, pubsubbackups.RestoreLockdownResponse{
+			Error: errMessage,
+		})
+		c.Check(err, jc.ErrorIsNil)
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { unsubscribe() })
+	return requests
+}
+
+func (s *backupsSuite) TestRestoreOkay(c *gc.C) {
+	fake := s.setBackups(c, s.meta, "")
+	agents := s.patchAgentServices()
+
+	err := s.api.Restore(params.BackupsRestoreArgs{ID: "some-id"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(fake.Calls, jc.DeepEquals, []string{"Restore"})
+	c.Check(fake.IDArg, gc.Equals, "some-id")
+	args := fake.RestoreArgs
+	c.Check(args.ControllerUUID, gc.Equals, s.State.ControllerUUID())
+	c.Check(args.MachineID, gc.Equals, "0")
+	c.Check(args.Version, gc.Equals, jujuversion.Current)
+	c.Check(args.Paths.DataDir, gc.Equals, s.DataDir())
+	c.Check(args.DBInfo, gc.NotNil)
+	c.Check(args.Session, gc.NotNil)
+	c.Check(args.Agents, gc.Equals, agents)
+	c.Check(agents.hub, gc.Equals, s.hub)
+	c.Check(agents.dataDir, gc.Equals, s.DataDir())
+	c.Check(agents.machineID, gc.Equals, "0")
+}
+
+func (s *backupsSuite) TestRestoreError(c *gc.C) {
+	s.setBackups(c, s.meta, "failed!")
+	s.patchAgentServices()
+
+	err := s.api.Restore(params.BackupsRestoreArgs{ID: "some-id"})
+	c.Assert(err, gc.ErrorMatches, "failed!")
+}
+
+func (s *backupsSuite) TestLockdownController(c *gc.C) {
+	requests := s.respondToRestoreLockdown(c, "")
+
+	err := backupsAPI.LockdownController(s.hub, "0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	select {
+	case req := <-requests:
+		c.Check(req.MachineID, gc.Equals, "0")
+	default:
+		c.Fatalf("restore lockdown not requested")
+	}
+}
+
+func (s *backupsSuite) TestLockdownControllerError(c *gc.C) {
+	s.respondToRestoreLockdown(c, "fortress abort")
+
+	err := backupsAPI.LockdownController(s.hub, "0")
+	c.Assert(err, gc.ErrorMatches, "fortress abort")
+}
+
+func (s *backupsSuite) TestLockdownControllerTimeout(c *gc.C) {
+	s.PatchValue(backupsAPI.RestoreLockdownTimeout, time.Millisecond)
+
+	err := backupsAPI.LockdownController(s.hub, "0")
+	c.Assert(err, gc.ErrorMatches, "waiting for controller workers to stop timeout")
+}
+
+func (s *backupsSuite) TestRestoreHA(c *gc.C) {
+	fake := s.setBackups(c, s.meta, "")
+	shim := &stateShim{
+		State: s.State,
+		Model: s.Model,
+		controllerNodesF: func() ([]state.ControllerNode, error) {
+			return make([]state.ControllerNode, 3), nil
+		},
+	}
+	api, err := backupsAPI.NewAPI(shim, s.hub, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = api.Restore(params.BackupsRestoreArgs{ID: "some-id"})
+	c.Assert(err, gc.ErrorMatches, "cannot restore onto a controller with 3 nodes in HA; remove the other controller machines first")
+	c.Check(fake.Calls, gc.HasLen, 0)
+}
@@ -35,12 +35,33 @@ func (s *stateShim) MachineSeries(id string) (string, error) {
 }
 
 // NewFacadeV3 provides the required signature for facade registration.
-func NewFacadeV3(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*API, error) {
+func NewFacadeV3(ctx facade.Context) (*APIv3, error) {
+	st := ctx.State()
 	model, err := st.Model()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return NewAPI(&stateShim{st, model}, resources, authorizer)
+	return NewAPIv3(&stateShim{st, model}, ctx.Hub(), ctx.Resources(), ctx.Auth())
+}
+
+// NewFacadeV4 provides the required signature for facade registration.
+func NewFacadeV4(ctx facade.Context) (*APIv4, error) {
+	st := ctx.State()
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewAPIv4(&stateShim{st, model}, ctx.Hub(), ctx.Resources(), ctx.Auth())
+}
+
+// NewFacadeV5 provides the required signature for facade registration.
+func NewFacadeV5(ctx facade.Context) (*API, error) {
+	st := ctx.State()
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewAPI(&stateShim{st, model}, ctx.Hub(), ctx.Resources(), ctx.Auth())
 }
 
 // ControllerTag disambiguates the ControllerTag method pending further
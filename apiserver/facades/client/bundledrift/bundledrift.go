@@ -0,0 +1,150 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package bundledrift provides the API for setting the bundle a model is
+// expected to match, and getting how the model has drifted from it.
+package bundledrift
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/common"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/bundledrift"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+)
+
+// Backend contains the state.State and state.Model methods used in this
+// package, allowing stubs to be created for testing.
+type Backend interface {
+	common.BlockGetter
+	ControllerTag() names.ControllerTag
+	ModelTag() names.ModelTag
+	SetDesiredBundle(bundle string, overlays []string, setBy string) error
+	DesiredBundle() (state.DesiredBundle, error)
+	RemoveDesiredBundle() error
+}
+
+type stateShim struct {
+	*state.State
+	model *state.Model
+}
+
+func (s stateShim) ModelTag() names.ModelTag {
+	return s.model.ModelTag()
+}
+
+func (s stateShim) SetDesiredBundle(bundle string, overlays []string, setBy string) error {
+	return s.model.SetDesiredBundle(bundle, overlays, setBy)
+}
+
+func (s stateShim) DesiredBundle() (state.DesiredBundle, error) {
+	return s.model.DesiredBundle()
+}
+
+func (s stateShim) RemoveDesiredBundle() error {
+	return s.model.RemoveDesiredBundle()
+}
+
+// API implements the BundleDrift facade.
+type API struct {
+	backend Backend
+	auth    facade.Authorizer
+	check   *common.BlockChecker
+}
+
+// NewFacade is used for API registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	model, err := ctx.State().Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewAPI(stateShim{State: ctx.State(), model: model}, ctx.Auth())
+}
+
+// NewAPI returns a new BundleDrift API facade.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, apiservererrors.ErrPerm
+	}
+	return &API{
+		backend: backend,
+		auth:    authorizer,
+		check:   common.NewBlockChecker(backend),
+	}, nil
+}
+
+func (api *API) checkAccess(access permission.Access) error {
+	isAdmin, err := api.auth.HasPermission(permission.SuperuserAccess, api.backend.ControllerTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if isAdmin {
+		return nil
+	}
+	hasAccess, err := api.auth.HasPermission(access, api.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !hasAccess {
+		return apiservererrors.ErrPerm
+	}
+	return nil
+}
+
+// SetDesiredBundle sets the bundle, with overlays, that the model is
+// expected to match. The model is compared with it periodically by the
+// controller.
+func (api *API) SetDesiredBundle(args params.SetDesiredBundleArgs) error {
+	if err := api.checkAccess(permission.WriteAccess); err != nil {
+		return errors.Trace(err)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := bundledrift.ReadBundle(args.Bundle, args.Overlays); err != nil {
+		return errors.Annotate(err, "invalid desired bundle")
+	}
+	return api.backend.SetDesiredBundle(args.Bundle, args.Overlays, api.auth.GetAuthTag().Id())
+}
+
+// RemoveDesiredBundle removes the bundle the model is expected to match,
+// so it's no longer compared with it.
+func (api *API) RemoveDesiredBundle() error {
+	if err := api.checkAccess(permission.WriteAccess); err != nil {
+		return errors.Trace(err)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	return api.backend.RemoveDesiredBundle()
+}
+
+// BundleDrift returns the bundle the model is expected to match, and how
+// the model had drifted from it when it was last compared.
+func (api *API) BundleDrift() (params.BundleDriftResult, error) {
+	if err := api.checkAccess(permission.ReadAccess); err != nil {
+		return params.BundleDriftResult{}, errors.Trace(err)
+	}
+	desired, err := api.backend.DesiredBundle()
+	if err != nil {
+		return params.BundleDriftResult{}, errors.Trace(err)
+	}
+	result := params.BundleDriftResult{
+		Bundle:   desired.Bundle,
+		Overlays: desired.Overlays,
+		SetBy:    desired.SetBy,
+		Set:      desired.Set,
+	}
+	if drift := desired.Drift; drift != nil {
+		checked := drift.Checked
+		result.Checked = &checked
+		result.Diff = drift.Diff
+		result.CheckError = drift.Error
+	}
+	return result, nil
+}
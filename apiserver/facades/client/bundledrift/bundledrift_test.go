@@ -0,0 +1,236 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundledrift_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/client/bundledrift"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type bundleDriftSuite struct {
+	testing.IsolationSuite
+	backend *mockBackend
+
+	// modelWriter is the name of a user with write access to the
+	// model only.
+	modelWriter string
+}
+
+var _ = gc.Suite(&bundleDriftSuite{})
+
+const bundle = `
+applications:
+  mysql:
+    charm: cs:mysql-58
+    num_units: 1
+`
+
+const overlay = `
+applications:
+  mysql:
+    options:
+      max-connections: 500
+`
+
+func (s *bundleDriftSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.modelWriter = "write-" + coretesting.ModelTag.String()
+	s.backend = &mockBackend{}
+}
+
+func (s *bundleDriftSuite) newAPI(c *gc.C, user string) *bundledrift.API {
+	api, err := bundledrift.NewAPI(s.backend, apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag(user),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *bundleDriftSuite) TestNewAPIRequiresClient(c *gc.C) {
+	_, err := bundledrift.NewAPI(s.backend, apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *bundleDriftSuite) TestSetDesiredBundle(c *gc.C) {
+	api := s.newAPI(c, s.modelWriter)
+	err := api.SetDesiredBundle(params.SetDesiredBundleArgs{
+		Bundle:   bundle,
+		Overlays: []string{overlay},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"ControllerTag", nil},
+		{"ModelTag", nil},
+		{"GetBlockForType", []interface{}{state.ChangeBlock}},
+		{"SetDesiredBundle", []interface{}{bundle, []string{overlay}, s.modelWriter}},
+	})
+}
+
+func (s *bundleDriftSuite) TestSetDesiredBundleInvalid(c *gc.C) {
+	api := s.newAPI(c, s.modelWriter)
+	err := api.SetDesiredBundle(params.SetDesiredBundleArgs{
+		Bundle: "applications: [",
+	})
+	c.Assert(err, gc.ErrorMatches, "invalid desired bundle: reading bundle: .*")
+	s.backend.CheckCallNames(c, "ControllerTag", "ModelTag", "GetBlockForType")
+}
+
+func (s *bundleDriftSuite) TestSetDesiredBundleNoIncludes(c *gc.C) {
+	api := s.newAPI(c, s.modelWriter)
+	err := api.SetDesiredBundle(params.SetDesiredBundleArgs{
+		Bundle: `
+applications:
+  mysql:
+    charm: cs:mysql-58
+    options:
+      password: include-file:///etc/passwd
+`,
+	})
+	c.Assert(err, gc.ErrorMatches, `invalid desired bundle: .*include-file "/etc/passwd" in desired bundle not supported`)
+	s.backend.CheckCallNames(c, "ControllerTag", "ModelTag", "GetBlockForType")
+}
+
+func (s *bundleDriftSuite) TestSetDesiredBundleRequiresWrite(c *gc.C) {
+	api := s.newAPI(c, "read-"+coretesting.ModelTag.String())
+	err := api.SetDesiredBundle(params.SetDesiredBundleArgs{Bundle: bundle})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "ControllerTag", "ModelTag")
+}
+
+func (s *bundleDriftSuite) TestSetDesiredBundleBlocked(c *gc.C) {
+	s.backend.block = mockBlock{}
+	api := s.newAPI(c, s.modelWriter)
+	err := api.SetDesiredBundle(params.SetDesiredBundleArgs{Bundle: bundle})
+	c.Assert(params.IsCodeOperationBlocked(err), jc.IsTrue)
+	s.backend.CheckCallNames(c, "ControllerTag", "ModelTag", "GetBlockForType")
+}
+
+func (s *bundleDriftSuite) TestRemoveDesiredBundle(c *gc.C) {
+	api := s.newAPI(c, s.modelWriter)
+	err := api.RemoveDesiredBundle()
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.CheckCallNames(c, "ControllerTag", "ModelTag", "GetBlockForType", "RemoveDesiredBundle")
+}
+
+func (s *bundleDriftSuite) TestRemoveDesiredBundleRequiresWrite(c *gc.C) {
+	api := s.newAPI(c, "read-"+coretesting.ModelTag.String())
+	err := api.RemoveDesiredBundle()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "ControllerTag", "ModelTag")
+}
+
+func (s *bundleDriftSuite) TestBundleDrift(c *gc.C) {
+	set := time.Date(2021, 5, 6, 7, 0, 0, 0, time.UTC)
+	checked := set.Add(5 * time.Minute)
+	s.backend.desired = state.DesiredBundle{
+		Bundle:   bundle,
+		Overlays: []string{overlay},
+		SetBy:    "bob",
+		Set:      set,
+		Drift: &state.BundleDrift{
+			Checked: checked,
+			Diff:    "applications: {}\n",
+		},
+	}
+	api := s.newAPI(c, "read-"+coretesting.ModelTag.String())
+	result, err := api.BundleDrift()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.BundleDriftResult{
+		Bundle:   bundle,
+		Overlays: []string{overlay},
+		SetBy:    "bob",
+		Set:      set,
+		Checked:  &checked,
+		Diff:     "applications: {}\n",
+	})
+	s.backend.CheckCallNames(c, "ControllerTag", "ModelTag", "DesiredBundle")
+}
+
+func (s *bundleDriftSuite) TestBundleDriftNotChecked(c *gc.C) {
+	set := time.Date(2021, 5, 6, 7, 0, 0, 0, time.UTC)
+	s.backend.desired = state.DesiredBundle{
+		Bundle: bundle,
+		SetBy:  "bob",
+		Set:    set,
+	}
+	api := s.newAPI(c, "superuser-x")
+	result, err := api.BundleDrift()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.BundleDriftResult{
+		Bundle: bundle,
+		SetBy:  "bob",
+		Set:    set,
+	})
+}
+
+func (s *bundleDriftSuite) TestBundleDriftNotFound(c *gc.C) {
+	s.backend.SetErrors(errors.NotFoundf("desired bundle"))
+	api := s.newAPI(c, "read-"+coretesting.ModelTag.String())
+	_, err := api.BundleDrift()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *bundleDriftSuite) TestBundleDriftRequiresRead(c *gc.C) {
+	api := s.newAPI(c, "someuser")
+	_, err := api.BundleDrift()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "ControllerTag", "ModelTag")
+}
+
+type mockBackend struct {
+	testing.Stub
+	desired state.DesiredBundle
+	block   state.Block
+}
+
+func (b *mockBackend) GetBlockForType(t state.BlockType) (state.Block, bool, error) {
+	b.MethodCall(b, "GetBlockForType", t)
+	return b.block, b.block != nil, nil
+}
+
+func (b *mockBackend) ControllerTag() names.ControllerTag {
+	b.MethodCall(b, "ControllerTag")
+	return coretesting.ControllerTag
+}
+
+func (b *mockBackend) ModelTag() names.ModelTag {
+	b.MethodCall(b, "ModelTag")
+	return coretesting.ModelTag
+}
+
+func (b *mockBackend) SetDesiredBundle(bundle string, overlays []string, setBy string) error {
+	b.MethodCall(b, "SetDesiredBundle", bundle, overlays, setBy)
+	return b.NextErr()
+}
+
+func (b *mockBackend) DesiredBundle() (state.DesiredBundle, error) {
+	b.MethodCall(b, "DesiredBundle")
+	return b.desired, b.NextErr()
+}
+
+func (b *mockBackend) RemoveDesiredBundle() error {
+	b.MethodCall(b, "RemoveDesiredBundle")
+	return b.NextErr()
+}
+
+type mockBlock struct {
+	state.Block
+}
+
+func (mockBlock) Message() string {
+	return "no changes"
+}
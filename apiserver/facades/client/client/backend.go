@@ -72,6 +72,7 @@ type Backend interface {
 	SetAnnotations(state.GlobalEntity, map[string]string) error
 	SetModelAgentVersion(version.Number, bool) error
 	SetModelConstraints(constraints.Value) error
+	StatusTransitions(state.StatusTransitionFilter) ([]state.StatusTransition, bool, error)
 	Unit(string) (Unit, error)
 	UpdateModelConfig(map[string]interface{}, []string, ...state.ValidateConfigFunc) error
 }
@@ -124,6 +125,10 @@ func (s *stateShim) SetAnnotations(entity state.GlobalEntity, ann map[string]str
 	return s.model.SetAnnotations(entity, ann)
 }
 
+func (s *stateShim) StatusTransitions(filter state.StatusTransitionFilter) ([]state.StatusTransition, bool, error) {
+	return s.model.StatusTransitions(filter)
+}
+
 func (s *stateShim) Unit(name string) (Unit, error) {
 	u, err := s.State.Unit(name)
 	if err != nil {
@@ -83,6 +83,11 @@ type Client struct {
 
 // ClientV1 serves the (v1) client-specific API methods.
 type ClientV1 struct {
+	*ClientV2
+}
+
+// ClientV2 serves the (v2) client-specific API methods.
+type ClientV2 struct {
 	*Client
 }
 
@@ -134,14 +139,23 @@ func (c *Client) checkIsAdmin() error {
 	return nil
 }
 
-// NewFacade creates a version 1 Client facade to handle API requests.
+// NewFacade creates a version 3 Client facade to handle API requests.
 func NewFacade(ctx facade.Context) (*Client, error) {
 	return newFacade(ctx)
 }
 
+// NewFacadeV2 creates a version 2 Client facade to handle API requests.
+func NewFacadeV2(ctx facade.Context) (*ClientV2, error) {
+	client, err := newFacade(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ClientV2{client}, nil
+}
+
 // NewFacadeV1 creates a version 1 Client facade to handle API requests.
 func NewFacadeV1(ctx facade.Context) (*ClientV1, error) {
-	client, err := newFacade(ctx)
+	client, err := NewFacadeV2(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
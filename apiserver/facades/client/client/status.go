@@ -106,6 +106,19 @@ func (c *Client) machineStatusHistory(machineTag names.MachineTag, filter status
 	return agentStatusFromStatusInfo(sInfo, kind), nil
 }
 
+// applicationStatusHistory returns status history for the given application.
+func (c *Client) applicationStatusHistory(appTag names.ApplicationTag, filter status.StatusHistoryFilter) ([]params.DetailedStatus, error) {
+	app, err := c.api.stateAccessor.Application(appTag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sInfo, err := app.StatusHistory(filter)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return agentStatusFromStatusInfo(sInfo, status.KindApplication), nil
+}
+
 // StatusHistory returns a slice of past statuses for several entities.
 func (c *Client) StatusHistory(request params.StatusHistoryRequests) params.StatusHistoryResults {
 	results := params.StatusHistoryResults{}
@@ -146,6 +159,11 @@ func (c *Client) StatusHistory(request params.StatusHistoryRequests) params.Stat
 			if u, err = names.ParseUnitTag(request.Tag); err == nil {
 				hist, err = c.unitStatusHistory(u, filter, kind)
 			}
+		case status.KindApplication:
+			var a names.ApplicationTag
+			if a, err = names.ParseApplicationTag(request.Tag); err == nil {
+				hist, err = c.applicationStatusHistory(a, filter)
+			}
 		default:
 			var m names.MachineTag
 			if m, err = names.ParseMachineTag(request.Tag); err == nil {
@@ -166,6 +184,55 @@ func (c *Client) StatusHistory(request params.StatusHistoryRequests) params.Stat
 	return results
 }
 
+// ModelStatusHistory isn't on the v2 API.
+func (c *ClientV2) ModelStatusHistory(_, _ struct{}) {}
+
+// ModelStatusHistory returns the changes in status of all of the units,
+// machines and applications in the model in a time window, oldest first.
+func (c *Client) ModelStatusHistory(args params.ModelStatusHistoryArgs) (params.ModelStatusHistoryResult, error) {
+	if err := c.checkCanRead(); err != nil {
+		return params.ModelStatusHistoryResult{}, errors.Trace(err)
+	}
+	var filter state.StatusTransitionFilter
+	if args.From != nil {
+		filter.From = *args.From
+	}
+	if args.To != nil {
+		filter.To = *args.To
+	}
+	for _, kind := range args.Kinds {
+		filter.Kinds = append(filter.Kinds, status.HistoryKind(kind))
+	}
+	for _, s := range args.Statuses {
+		filter.Statuses = append(filter.Statuses, status.Status(s))
+	}
+	if args.Offset != nil {
+		filter.Offset = *args.Offset
+	}
+	if args.Limit != nil {
+		filter.Limit = *args.Limit
+	}
+	transitions, truncated, err := c.api.stateAccessor.StatusTransitions(filter)
+	if err != nil {
+		return params.ModelStatusHistoryResult{}, errors.Trace(err)
+	}
+	result := params.ModelStatusHistoryResult{
+		Transitions: make([]params.StatusTransition, len(transitions)),
+		Truncated:   truncated,
+	}
+	for i, t := range transitions {
+		result.Transitions[i] = params.StatusTransition{
+			Tag:    t.Tag.String(),
+			Kind:   string(t.Kind),
+			Status: string(t.Status),
+			Info:   t.Message,
+			Data:   t.Data,
+			Since:  t.Since,
+		}
+	}
+	return result, nil
+}
+
 // FullStatus gives the information needed for juju status over the api
 func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error) {
 	if err := c.checkCanRead(); err != nil {
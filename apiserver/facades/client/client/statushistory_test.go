@@ -16,6 +16,7 @@ import (
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
 )
 
@@ -226,10 +227,68 @@ func (s *statusHistoryTestSuite) TestStatusHistoryCombined(c *gc.C) {
 	checkStatusInfo(c, h.Results[0].History.Statuses, expected)
 }
 
+func (s *statusHistoryTestSuite) TestModelStatusHistory(c *gc.C) {
+	since := time.Date(2021, 6, 1, 2, 5, 0, 0, time.UTC)
+	s.st.transitions = []state.StatusTransition{{
+		StatusInfo: status.StatusInfo{
+			Status:  status.Blocked,
+			Message: "missing relation",
+			Since:   &since,
+		},
+		Tag:  names.NewUnitTag("unit/0"),
+		Kind: status.KindWorkload,
+	}}
+	s.st.truncated = true
+	from := time.Date(2021, 6, 1, 2, 0, 0, 0, time.UTC)
+	to := from.Add(15 * time.Minute)
+	limit := 10
+	result, err := s.api.ModelStatusHistory(params.ModelStatusHistoryArgs{
+		From:     &from,
+		To:       &to,
+		Kinds:    []string{"unit", "juju-machine"},
+		Statuses: []string{"blocked", "error"},
+		Limit:    &limit,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.st.transitionFilter, jc.DeepEquals, state.StatusTransitionFilter{
+		From:     from,
+		To:       to,
+		Kinds:    []status.HistoryKind{status.KindUnit, status.KindMachine},
+		Statuses: []status.Status{status.Blocked, status.Error},
+		Limit:    10,
+	})
+	c.Assert(result, jc.DeepEquals, params.ModelStatusHistoryResult{
+		Transitions: []params.StatusTransition{{
+			Tag:    "unit-unit-0",
+			Kind:   "workload",
+			Status: "blocked",
+			Info:   "missing relation",
+			Since:  &since,
+		}},
+		Truncated: true,
+	})
+}
+
+func (s *statusHistoryTestSuite) TestModelStatusHistoryError(c *gc.C) {
+	s.st.transitionsErr = errors.NotValidf("time window ending before it starts")
+	_, err := s.api.ModelStatusHistory(params.ModelStatusHistoryArgs{})
+	c.Assert(err, gc.ErrorMatches, "time window ending before it starts not valid")
+}
+
 type mockState struct {
 	client.Backend
 	unitHistory  []status.StatusInfo
 	agentHistory []status.StatusInfo
+
+	transitionFilter state.StatusTransitionFilter
+	transitions      []state.StatusTransition
+	truncated        bool
+	transitionsErr   error
+}
+
+func (m *mockState) StatusTransitions(filter state.StatusTransitionFilter) ([]state.StatusTransition, bool, error) {
+	m.transitionFilter = filter
+	return m.transitions, m.truncated, m.transitionsErr
 }
 
 func (m *mockState) ModelUUID() string {
@@ -0,0 +1,162 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package confighistory provides the API for listing the recorded
+// revisions of application and model config, and rolling config back to
+// an earlier revision.
+package confighistory
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/common"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+)
+
+// Backend contains the state.State methods used in this package,
+// allowing stubs to be created for testing.
+type Backend interface {
+	common.BlockGetter
+	ControllerTag() names.ControllerTag
+	ModelTag() names.ModelTag
+	ConfigRevisions(names.Tag) ([]state.ConfigRevision, error)
+	RollbackConfig(names.Tag, int, string) error
+}
+
+type stateShim struct {
+	*state.State
+}
+
+func (s stateShim) ModelTag() names.ModelTag {
+	return names.NewModelTag(s.ModelUUID())
+}
+
+// API implements the ConfigHistory facade.
+type API struct {
+	backend Backend
+	auth    facade.Authorizer
+	check   *common.BlockChecker
+}
+
+// NewFacade is used for API registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(stateShim{ctx.State()}, ctx.Auth())
+}
+
+// NewAPI returns a new ConfigHistory API facade.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, apiservererrors.ErrPerm
+	}
+	return &API{
+		backend: backend,
+		auth:    authorizer,
+		check:   common.NewBlockChecker(backend),
+	}, nil
+}
+
+func (api *API) checkAccess(access permission.Access) error {
+	isAdmin, err := api.auth.HasPermission(permission.SuperuserAccess, api.backend.ControllerTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if isAdmin {
+		return nil
+	}
+	hasAccess, err := api.auth.HasPermission(access, api.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !hasAccess {
+		return apiservererrors.ErrPerm
+	}
+	return nil
+}
+
+// parseTag returns the tag of an application, or of the model, whose
+// config history is requested.
+func (api *API) parseTag(tagString string) (names.Tag, error) {
+	tag, err := names.ParseTag(tagString)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch tag := tag.(type) {
+	case names.ApplicationTag:
+		return tag, nil
+	case names.ModelTag:
+		if tag != api.backend.ModelTag() {
+			return nil, apiservererrors.ErrPerm
+		}
+		return tag, nil
+	}
+	return nil, errors.NotValidf("config history of %q", tagString)
+}
+
+// ConfigRevisions returns the recorded config revisions of each of the
+// given applications or model, oldest first.
+func (api *API) ConfigRevisions(args params.Entities) (params.ConfigRevisionsResults, error) {
+	results := params.ConfigRevisionsResults{
+		Results: make([]params.ConfigRevisionsResult, len(args.Entities)),
+	}
+	if err := api.checkAccess(permission.ReadAccess); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		revisions, err := api.configRevisions(entity.Tag)
+		if err != nil {
+			results.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		results.Results[i].Revisions = revisions
+	}
+	return results, nil
+}
+
+func (api *API) configRevisions(tagString string) ([]params.ConfigRevision, error) {
+	tag, err := api.parseTag(tagString)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	revisions, err := api.backend.ConfigRevisions(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]params.ConfigRevision, len(revisions))
+	for i, revision := range revisions {
+		result[i] = params.ConfigRevision{
+			Revision: revision.Revision,
+			Config:   revision.Config,
+			Author:   revision.Author,
+			Created:  revision.Created,
+		}
+	}
+	return result, nil
+}
+
+// RollbackConfig sets the config of each of the given applications or
+// model to that of the given revision.
+func (api *API) RollbackConfig(args params.RollbackConfigArgs) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	if err := api.checkAccess(permission.WriteAccess); err != nil {
+		return results, errors.Trace(err)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+	author := api.auth.GetAuthTag().Id()
+	for i, arg := range args.Args {
+		tag, err := api.parseTag(arg.Tag)
+		if err == nil {
+			err = api.backend.RollbackConfig(tag, arg.Revision, author)
+		}
+		results.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return results, nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package confighistory_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/client/confighistory"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type configHistorySuite struct {
+	testing.IsolationSuite
+	backend *mockBackend
+
+	// modelWriter is the name of a user with write access to the
+	// model only.
+	modelWriter string
+}
+
+var _ = gc.Suite(&configHistorySuite{})
+
+func (s *configHistorySuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.modelWriter = "write-" + coretesting.ModelTag.String()
+	s.backend = &mockBackend{
+		revisions: []state.ConfigRevision{{
+			Revision: 1,
+			Config:   map[string]interface{}{"blog-title": "first"},
+			Author:   "bob",
+			Created:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		}, {
+			Revision: 2,
+			Config:   map[string]interface{}{"blog-title": "second"},
+			Created:  time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+}
+
+func (s *configHistorySuite) newAPI(c *gc.C, user string) *confighistory.API {
+	api, err := confighistory.NewAPI(s.backend, apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag(user),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *configHistorySuite) TestNewAPIRequiresClient(c *gc.C) {
+	_, err := confighistory.NewAPI(s.backend, apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *configHistorySuite) TestConfigRevisions(c *gc.C) {
+	api := s.newAPI(c, "read-"+coretesting.ModelTag.String())
+	results, err := api.ConfigRevisions(params.Entities{Entities: []params.Entity{
+		{Tag: "application-wordpress"},
+		{Tag: coretesting.ModelTag.String()},
+		{Tag: "machine-0"},
+		{Tag: "model-f47ac10b-58cc-4372-a567-0e02b2c3d479"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	revisions := []params.ConfigRevision{{
+		Revision: 1,
+		Config:   map[string]interface{}{"blog-title": "first"},
+		Author:   "bob",
+		Created:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, {
+		Revision: 2,
+		Config:   map[string]interface{}{"blog-title": "second"},
+		Created:  time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+	}}
+	c.Assert(results, jc.DeepEquals, params.ConfigRevisionsResults{
+		Results: []params.ConfigRevisionsResult{
+			{Revisions: revisions},
+			{Revisions: revisions},
+			{Error: &params.Error{Message: `config history of "machine-0" not valid`}},
+			{Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized}},
+		},
+	})
+	s.backend.CheckCallNames(c, "ControllerTag", "ModelTag", "ConfigRevisions", "ModelTag", "ConfigRevisions", "ModelTag")
+	s.backend.CheckCall(c, 2, "ConfigRevisions", names.NewApplicationTag("wordpress"))
+	s.backend.CheckCall(c, 4, "ConfigRevisions", coretesting.ModelTag)
+}
+
+func (s *configHistorySuite) TestConfigRevisionsRequiresRead(c *gc.C) {
+	api := s.newAPI(c, "someuser")
+	_, err := api.ConfigRevisions(params.Entities{Entities: []params.Entity{
+		{Tag: "application-wordpress"},
+	}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "ControllerTag", "ModelTag")
+}
+
+func (s *configHistorySuite) TestRollbackConfig(c *gc.C) {
+	s.backend.SetErrors(nil, errors.NotFoundf("revision 9 of %q config", "application-wordpress"))
+	api := s.newAPI(c, s.modelWriter)
+	results, err := api.RollbackConfig(params.RollbackConfigArgs{Args: []params.RollbackConfigArg{
+		{Tag: "application-wordpress", Revision: 1},
+		{Tag: "application-wordpress", Revision: 9},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Message: `revision 9 of "application-wordpress" config not found`, Code: params.CodeNotFound}},
+		},
+	})
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"ControllerTag", nil},
+		{"ModelTag", nil},
+		{"GetBlockForType", []interface{}{state.ChangeBlock}},
+		{"RollbackConfig", []interface{}{names.NewApplicationTag("wordpress"), 1, s.modelWriter}},
+		{"RollbackConfig", []interface{}{names.NewApplicationTag("wordpress"), 9, s.modelWriter}},
+	})
+}
+
+func (s *configHistorySuite) TestRollbackConfigRequiresWrite(c *gc.C) {
+	api := s.newAPI(c, "read-"+coretesting.ModelTag.String())
+	_, err := api.RollbackConfig(params.RollbackConfigArgs{Args: []params.RollbackConfigArg{
+		{Tag: "application-wordpress", Revision: 1},
+	}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "ControllerTag", "ModelTag")
+}
+
+type mockBackend struct {
+	testing.Stub
+	revisions []state.ConfigRevision
+	block     state.Block
+}
+
+func (b *mockBackend) GetBlockForType(t state.BlockType) (state.Block, bool, error) {
+	b.MethodCall(b, "GetBlockForType", t)
+	return b.block, b.block != nil, nil
+}
+
+func (b *mockBackend) ControllerTag() names.ControllerTag {
+	b.MethodCall(b, "ControllerTag")
+	return coretesting.ControllerTag
+}
+
+func (b *mockBackend) ModelTag() names.ModelTag {
+	b.MethodCall(b, "ModelTag")
+	return coretesting.ModelTag
+}
+
+func (b *mockBackend) ConfigRevisions(tag names.Tag) ([]state.ConfigRevision, error) {
+	b.MethodCall(b, "ConfigRevisions", tag)
+	return b.revisions, b.NextErr()
+}
+
+func (b *mockBackend) RollbackConfig(tag names.Tag, revision int, author string) error {
+	b.MethodCall(b, "RollbackConfig", tag, revision, author)
+	return b.NextErr()
+}
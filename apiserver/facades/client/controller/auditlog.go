@@ -0,0 +1,169 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/utils/v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/auditlog"
+	pubsubauditlog "github.com/juju/juju/pubsub/auditlog"
+)
+
+// maxAuditLogEntries is the most audit log entries returned by a
+// single AuditLog call.
+const maxAuditLogEntries = 10000
+
+// auditLogQueryTimeout is how long AuditLog waits for every controller
+// to respond before returning the entries it has.
+var auditLogQueryTimeout = 30 * time.Second
+
+// AuditLog isn't on the v9 API.
+func (c *ControllerAPIv9) AuditLog(_, _ struct{}) {}
+
+// AuditLog returns the entries in the audit logs of all the controller
+// nodes matching the query, ordered by the time the requests were made.
+// Controllers that don't respond in time are reported in the result's
+// Missing map rather than failing the whole query.
+func (c *ControllerAPI) AuditLog(args params.AuditLogQueryArgs) (params.AuditLogResult, error) {
+	var result params.AuditLogResult
+	if err := c.checkIsSuperUser(); err != nil {
+		return result, errors.Trace(err)
+	}
+	if args.Limit < 0 {
+		return result, errors.NotValidf("negative limit")
+	}
+	limit := args.Limit
+	if limit == 0 || limit > maxAuditLogEntries {
+		limit = maxAuditLogEntries
+	}
+
+	info, err := c.state.ControllerInfo()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	pending := make(map[string]bool)
+	for _, id := range info.ControllerIds {
+		pending[id] = true
+	}
+
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	responseTopic := pubsubauditlog.QueryTopic + ".response." + uuid.String()
+	responses := make(chan pubsubauditlog.QueryResponse, len(pending))
+	done := make(chan struct{})
+	defer close(done)
+	unsubscribe, err := c.hub.Subscribe(responseTopic, func(_ string, resp pubsubauditlog.QueryResponse, err error) {
+		if err != nil {
+			logger.Errorf("unable to decode audit log query response: %v", err)
+			return
+		}
+		select {
+		case responses <- resp:
+		case <-done:
+		}
+	})
+	if err != nil {
+		return result, errors.Annotate(err, "subscribing to audit log responses")
+	}
+	defer unsubscribe()
+
+	req := pubsubauditlog.QueryRequest{
+		Users:         args.Users,
+		Models:        args.Models,
+		Methods:       args.Methods,
+		Limit:         limit,
+		ResponseTopic: responseTopic,
+	}
+	if args.From != nil {
+		req.From = args.From.UTC().Format(time.RFC3339)
+	}
+	if args.To != nil {
+		req.To = args.To.UTC().Format(time.RFC3339)
+	}
+	if _, err := c.hub.Publish(pubsubauditlog.QueryTopic, req); err != nil {
+		return result, errors.Annotate(err, "publishing audit log query")
+	}
+
+	var entries []params.AuditLogEntry
+	missing := make(map[string]string)
+	timeout := time.After(auditLogQueryTimeout)
+	for len(pending) > 0 {
+		select {
+		case resp := <-responses:
+			id := controllerID(resp.Origin)
+			if !pending[id] {
+				logger.Debugf("ignoring audit log response from %q", resp.Origin)
+				continue
+			}
+			delete(pending, id)
+			if resp.Error != "" {
+				missing[id] = resp.Error
+				continue
+			}
+			for _, entry := range resp.Entries {
+				entries = append(entries, auditLogEntry(id, entry))
+			}
+		case <-timeout:
+			for id := range pending {
+				missing[id] = "timed out waiting for response"
+			}
+			pending = nil
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].When.Before(entries[j].When)
+	})
+	if len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	result.Entries = entries
+	if len(missing) > 0 {
+		result.Missing = missing
+	}
+	return result, nil
+}
+
+// controllerID returns the controller id for the agent tag the central
+// hub records as the origin of a message.
+func controllerID(origin string) string {
+	tag, err := names.ParseTag(origin)
+	if err != nil {
+		return origin
+	}
+	return tag.Id()
+}
+
+func auditLogEntry(controllerID string, entry auditlog.Entry) params.AuditLogEntry {
+	result := params.AuditLogEntry{
+		ControllerID:   controllerID,
+		Who:            entry.Conversation.Who,
+		What:           entry.Conversation.What,
+		ModelName:      entry.Conversation.ModelName,
+		ModelUUID:      entry.Conversation.ModelUUID,
+		ConversationID: entry.Conversation.ConversationID,
+		ConnectionID:   entry.Request.ConnectionID,
+		RequestID:      entry.Request.RequestID,
+		When:           entry.When(),
+		Facade:         entry.Request.Facade,
+		Method:         entry.Request.Method,
+		Version:        entry.Request.Version,
+		Args:           entry.Request.Args,
+	}
+	for _, e := range entry.Errors {
+		result.Errors = append(result.Errors, params.AuditLogError{
+			Message: e.Message,
+			Code:    e.Code,
+		})
+	}
+	return result
+}
@@ -0,0 +1,49 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// ScheduledBackupStatus isn't on the v10 API.
+func (c *ControllerAPIv10) ScheduledBackupStatus(_, _ struct{}) {}
+
+// ScheduledBackupStatus returns the backup schedule and the outcome of
+// the most recent scheduled backups.
+func (c *ControllerAPI) ScheduledBackupStatus() (params.ScheduledBackupStatus, error) {
+	var result params.ScheduledBackupStatus
+	if err := c.checkIsSuperUser(); err != nil {
+		return result, errors.Trace(err)
+	}
+	cfg, err := c.state.ControllerConfig()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	status, err := c.state.ScheduledBackupStatus()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.Schedule = cfg.BackupSchedule()
+	if result.Schedule != "" {
+		result.NextRun = optionalTime(status.NextRun)
+	}
+	result.LastAttempt = optionalTime(status.LastAttempt)
+	result.LastSuccess = optionalTime(status.LastSuccess)
+	result.LastBackupID = status.LastBackupID
+	result.LastFailure = optionalTime(status.LastFailure)
+	result.LastError = status.LastError
+	return result, nil
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
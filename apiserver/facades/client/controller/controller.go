@@ -57,10 +57,23 @@ type ControllerAPI struct {
 	multiwatcherFactory multiwatcher.Factory
 }
 
+// ControllerAPIv10 provides the v10 Controller API. The only difference
+// between this and v11 is that v10 doesn't have the
+// ScheduledBackupStatus method.
+type ControllerAPIv10 struct {
+	*ControllerAPI
+}
+
+// ControllerAPIv9 provides the v9 Controller API. The only difference
+// between this and v10 is that v9 doesn't have the AuditLog method.
+type ControllerAPIv9 struct {
+	*ControllerAPIv10
+}
+
 // ControllerAPIv8 provides the v8 Controller API. The only difference
 // between this and v9 is that v8 doesn't have the model summary watchers.
 type ControllerAPIv8 struct {
-	*ControllerAPI
+	*ControllerAPIv9
 }
 
 // ControllerAPIv7 provides the v7 Controller API. The only difference
@@ -95,10 +108,10 @@ type ControllerAPIv3 struct {
 
 // LatestAPI is used for testing purposes to create the latest
 // controller API.
-var LatestAPI = NewControllerAPIv9
+var LatestAPI = NewControllerAPIv11
 
-// NewControllerAPIv9 creates a new ControllerAPIv9.
-func NewControllerAPIv9(ctx facade.Context) (*ControllerAPI, error) {
+// NewControllerAPIv11 creates a new ControllerAPIv11.
+func NewControllerAPIv11(ctx facade.Context) (*ControllerAPI, error) {
 	st := ctx.State()
 	authorizer := ctx.Auth()
 	pool := ctx.StatePool()
@@ -120,6 +133,24 @@ func NewControllerAPIv9(ctx facade.Context) (*ControllerAPI, error) {
 	)
 }
 
+// NewControllerAPIv10 creates a new ControllerAPIv10.
+func NewControllerAPIv10(ctx facade.Context) (*ControllerAPIv10, error) {
+	v11, err := NewControllerAPIv11(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ControllerAPIv10{v11}, nil
+}
+
+// NewControllerAPIv9 creates a new ControllerAPIv9.
+func NewControllerAPIv9(ctx facade.Context) (*ControllerAPIv9, error) {
+	v10, err := NewControllerAPIv10(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &ControllerAPIv9{v10}, nil
+}
+
 // NewControllerAPIv8 creates a new ControllerAPIv8.
 func NewControllerAPIv8(ctx facade.Context) (*ControllerAPIv8, error) {
 	v9, err := NewControllerAPIv9(ctx)
@@ -199,7 +230,7 @@ func NewControllerAPI(
 		return nil, errors.Trace(err)
 	}
 	return &ControllerAPI{
-		ControllerConfigAPI: common.NewStateControllerConfig(st),
+		ControllerConfigAPI: common.NewStateControllerConfig(st, authorizer),
 		ModelStatusAPI: common.NewModelStatusAPI(
 			common.NewModelManagerBackend(model, pool),
 			authorizer,
@@ -28,11 +28,13 @@ import (
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/cloud"
 	corecontroller "github.com/juju/juju/controller"
+	"github.com/juju/juju/core/auditlog"
 	"github.com/juju/juju/core/cache"
 	"github.com/juju/juju/core/permission"
 	"github.com/juju/juju/environs"
 	environscloudspec "github.com/juju/juju/environs/cloudspec"
 	"github.com/juju/juju/environs/config"
+	pubsubauditlog "github.com/juju/juju/pubsub/auditlog"
 	pscontroller "github.com/juju/juju/pubsub/controller"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
@@ -1183,3 +1185,187 @@ func (noopRegisterer) Register(prometheus.Collector) error {
 func (noopRegisterer) Unregister(prometheus.Collector) bool {
 	return true
 }
+
+func (s *controllerSuite) respondToAuditLogQueries(c *gc.C, origin string, entries ...auditlog.Entry) <-chan pubsubauditlog.QueryRequest {
+	requests := make(chan pubsubauditlog.QueryRequest, 1)
+	unsubscribe, err := s.hub.Subscribe(pubsubauditlog.QueryTopic, func(_ string, req pubsubauditlog.QueryRequest, err error) {
+		c.Check(err, jc.ErrorIsNil)
+		requests <- req
+		_, err = s.hub.Publish(req.ResponseTopic, pubsubauditlog.QueryResponse{
+			Origin:  origin,
+			Entries: entries,
+		})
+		c.Check(err, jc.ErrorIsNil)
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { unsubscribe() })
+	return requests
+}
+
+func auditLogEntry(who, when, method string) auditlog.Entry {
+	return auditlog.Entry{
+		Conversation: auditlog.Conversation{
+			Who:            who,
+			ModelName:      "controller",
+			ConversationID: "0123456789abcdef",
+			ConnectionID:   "A",
+		},
+		Request: auditlog.Request{
+			ConversationID: "0123456789abcdef",
+			ConnectionID:   "A",
+			RequestID:      1,
+			When:           when,
+			Facade:         "Application",
+			Method:         method,
+			Version:        12,
+		},
+	}
+}
+
+func (s *controllerSuite) TestAuditLog(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+	entry := auditLogEntry("bob", "2021-03-01T10:00:00Z", "DestroyApplication")
+	entry.Errors = []*auditlog.Error{{Message: "boom", Code: "not found"}}
+	requests := s.respondToAuditLogQueries(c, m.Tag().String(), entry)
+
+	from := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	result, err := s.controller.AuditLog(params.AuditLogQueryArgs{
+		Users:   []string{"bob"},
+		Methods: []string{"Application.DestroyApplication"},
+		From:    &from,
+		Limit:   10,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.AuditLogResult{
+		Entries: []params.AuditLogEntry{{
+			ControllerID:   m.Id(),
+			Who:            "bob",
+			ModelName:      "controller",
+			ConversationID: "0123456789abcdef",
+			ConnectionID:   "A",
+			RequestID:      1,
+			When:           time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC),
+			Facade:         "Application",
+			Method:         "DestroyApplication",
+			Version:        12,
+			Errors:         []params.AuditLogError{{Message: "boom", Code: "not found"}},
+		}},
+	})
+
+	req := <-requests
+	c.Assert(req.Users, jc.DeepEquals, []string{"bob"})
+	c.Assert(req.Methods, jc.DeepEquals, []string{"Application.DestroyApplication"})
+	c.Assert(req.From, gc.Equals, "2021-03-01T00:00:00Z")
+	c.Assert(req.To, gc.Equals, "")
+	c.Assert(req.Limit, gc.Equals, 10)
+}
+
+func (s *controllerSuite) TestAuditLogLimitKeepsMostRecent(c *gc.C) {
+	m, err := s.State.AddMachine("quantal", state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+	s.respondToAuditLogQueries(c, m.Tag().String(),
+		auditLogEntry("bob", "2021-03-01T10:00:00Z", "Deploy"),
+		auditLogEntry("bob", "2021-03-01T12:00:00Z", "DestroyApplication"),
+		auditLogEntry("bob", "2021-03-01T11:00:00Z", "SetConfigs"),
+	)
+
+	result, err := s.controller.AuditLog(params.AuditLogQueryArgs{Limit: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Entries, gc.HasLen, 2)
+	c.Assert(result.Entries[0].Method, gc.Equals, "SetConfigs")
+	c.Assert(result.Entries[1].Method, gc.Equals, "DestroyApplication")
+}
+
+func (s *controllerSuite) TestAuditLogReportsMissingControllers(c *gc.C) {
+	controller.SetAuditLogQueryTimeout(s, testing.ShortWait)
+	m, err := s.State.AddMachine("quantal", state.JobManageModel)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.controller.AuditLog(params.AuditLogQueryArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Entries, gc.HasLen, 0)
+	c.Assert(result.Missing, jc.DeepEquals, map[string]string{
+		m.Id(): "timed out waiting for response",
+	})
+}
+
+func (s *controllerSuite) TestAuditLogRequiresSuperUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Access: permission.ReadAccess,
+	})
+	anAuthoriser := apiservertesting.FakeAuthorizer{
+		Tag: user.Tag(),
+	}
+	endpoint, err := controller.NewControllerAPIv11(
+		facadetest.Context{
+			State_:     s.State,
+			Resources_: s.resources,
+			Auth_:      anAuthoriser,
+			Hub_:       s.hub,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = endpoint.AuditLog(params.AuditLogQueryArgs{})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *controllerSuite) TestAuditLogNegativeLimit(c *gc.C) {
+	_, err := s.controller.AuditLog(params.AuditLogQueryArgs{Limit: -1})
+	c.Assert(err, gc.ErrorMatches, "negative limit not valid")
+}
+
+func (s *controllerSuite) TestScheduledBackupStatusNoneRun(c *gc.C) {
+	result, err := s.controller.ScheduledBackupStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ScheduledBackupStatus{})
+}
+
+func (s *controllerSuite) TestScheduledBackupStatus(c *gc.C) {
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		"backup-schedule": "0 2 * * *",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	lastSuccess := time.Date(2021, 3, 1, 2, 0, 0, 0, time.UTC)
+	lastFailure := lastSuccess.Add(24 * time.Hour)
+	nextRun := lastFailure.Add(24 * time.Hour)
+	err = s.State.SetScheduledBackupStatus(state.ScheduledBackupStatus{
+		LastAttempt:  lastFailure,
+		LastSuccess:  lastSuccess,
+		LastBackupID: "20210301-020000.deadbeef",
+		LastFailure:  lastFailure,
+		LastError:    "boom",
+		NextRun:      nextRun,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := s.controller.ScheduledBackupStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Schedule, gc.Equals, "0 2 * * *")
+	c.Assert(result.LastAttempt.Equal(lastFailure), jc.IsTrue)
+	c.Assert(result.LastSuccess.Equal(lastSuccess), jc.IsTrue)
+	c.Assert(result.LastBackupID, gc.Equals, "20210301-020000.deadbeef")
+	c.Assert(result.LastFailure.Equal(lastFailure), jc.IsTrue)
+	c.Assert(result.LastError, gc.Equals, "boom")
+	c.Assert(result.NextRun.Equal(nextRun), jc.IsTrue)
+}
+
+func (s *controllerSuite) TestScheduledBackupStatusRequiresSuperUser(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{
+		Access: permission.ReadAccess,
+	})
+	anAuthoriser := apiservertesting.FakeAuthorizer{
+		Tag: user.Tag(),
+	}
+	endpoint, err := controller.NewControllerAPIv11(
+		facadetest.Context{
+			State_:     s.State,
+			Resources_: s.resources,
+			Auth_:      anAuthoriser,
+			Hub_:       s.hub,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = endpoint.ScheduledBackupStatus()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
@@ -4,6 +4,8 @@
 package controller
 
 import (
+	"time"
+
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/core/migration"
 	"github.com/juju/juju/state"
@@ -18,3 +20,7 @@ func SetPrecheckResult(p patcher, err error) {
 		return err
 	})
 }
+
+func SetAuditLogQueryTimeout(p patcher, timeout time.Duration) {
+	p.PatchValue(&auditLogQueryTimeout, timeout)
+}
@@ -23,6 +23,7 @@ type Backend interface {
 	SetSLA(level, owner string, credentials []byte) error
 	SLALevel() (string, error)
 	SpaceByName(string) error
+	RecordConfigRevision(names.Tag, string) (state.ConfigRevision, error)
 }
 
 type stateShim struct {
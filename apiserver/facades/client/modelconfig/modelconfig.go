@@ -158,7 +158,22 @@ func (c *ModelConfigAPI) ModelSet(args params.ModelSet) error {
 
 	// Replace any deprecated attributes with their new values.
 	attrs := config.ProcessDeprecatedAttributes(args.Config)
-	return c.backend.UpdateModelConfig(attrs, nil, checkAgentVersion, checkLogTrace, checkDefaultSpace, checkCharmHubURL)
+	return c.updateModelConfig(attrs, nil, checkAgentVersion, checkLogTrace, checkDefaultSpace, checkCharmHubURL)
+}
+
+// updateModelConfig updates the model config, recording the change in
+// the model's config history, along with any changes made since the
+// latest recorded revision.
+func (c *ModelConfigAPI) updateModelConfig(attrs map[string]interface{}, remove []string, validate ...state.ValidateConfigFunc) error {
+	tag := c.backend.ModelTag()
+	if _, err := c.backend.RecordConfigRevision(tag, ""); err != nil {
+		return errors.Trace(err)
+	}
+	if err := c.backend.UpdateModelConfig(attrs, remove, validate...); err != nil {
+		return err
+	}
+	_, err := c.backend.RecordConfigRevision(tag, c.auth.GetAuthTag().Id())
+	return errors.Trace(err)
 }
 
 func (c *ModelConfigAPI) checkLogTrace() state.ValidateConfigFunc {
@@ -244,7 +259,7 @@ func (c *ModelConfigAPI) ModelUnset(args params.ModelUnset) error {
 	if err := c.check.ChangeAllowed(); err != nil {
 		return errors.Trace(err)
 	}
-	return c.backend.UpdateModelConfig(nil, args.Keys)
+	return c.updateModelConfig(nil, args.Keys)
 }
 
 // SetSLALevel sets the sla level on the model.
@@ -206,6 +206,7 @@ func (s *modelconfigSuite) TestModelUnset(c *gc.C) {
 	err = s.api.ModelUnset(args)
 	c.Assert(err, jc.ErrorIsNil)
 	s.assertConfigValueMissing(c, "abc")
+	c.Assert(s.backend.configRevisionAuthors, jc.DeepEquals, []string{"", "bruce"})
 }
 
 func (s *modelconfigSuite) TestBlockModelUnset(c *gc.C) {
@@ -235,6 +236,13 @@ type mockBackend struct {
 	old *config.Config
 	b   state.BlockType
 	msg string
+
+	configRevisionAuthors []string
+}
+
+func (m *mockBackend) RecordConfigRevision(tag names.Tag, author string) (state.ConfigRevision, error) {
+	m.configRevisionAuthors = append(m.configRevisionAuthors, author)
+	return state.ConfigRevision{}, nil
 }
 
 func (m *mockBackend) ModelConfigValues() (config.ConfigValues, error) {
@@ -0,0 +1,174 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package modelquotas provides the API for reading and setting the
+// quotas that limit the resources used by a model, or by all the models
+// owned by a user.
+package modelquotas
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/core/quota"
+)
+
+// Backend contains the state.State methods used in this package,
+// allowing stubs to be created for testing.
+type Backend interface {
+	ControllerTag() names.ControllerTag
+	QuotaLimits(names.Tag) (quota.Limits, error)
+	SetQuotaLimits(names.Tag, quota.Limits, []quota.Resource) error
+	QuotaUsage(names.Tag) (quota.Usage, error)
+}
+
+// API implements the ModelQuotas facade.
+type API struct {
+	backend Backend
+	auth    facade.Authorizer
+}
+
+// NewFacade is used for API registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	return NewAPI(ctx.State(), ctx.Auth())
+}
+
+// NewAPI returns a new ModelQuotas API facade.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, apiservererrors.ErrPerm
+	}
+	return &API{
+		backend: backend,
+		auth:    authorizer,
+	}, nil
+}
+
+func (api *API) isSuperUser() (bool, error) {
+	return api.auth.HasPermission(permission.SuperuserAccess, api.backend.ControllerTag())
+}
+
+// checkCanRead checks that the authenticated user can see the quotas of
+// the model or user: controller superusers can see all of them, model
+// users can see their model's quotas, and users can see their own.
+func (api *API) checkCanRead(tag names.Tag) error {
+	if isAdmin, err := api.isSuperUser(); err != nil {
+		return errors.Trace(err)
+	} else if isAdmin {
+		return nil
+	}
+	switch tag := tag.(type) {
+	case names.ModelTag:
+		canRead, err := api.auth.HasPermission(permission.ReadAccess, tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if canRead {
+			return nil
+		}
+	case names.UserTag:
+		if authTag, ok := api.auth.GetAuthTag().(names.UserTag); ok && authTag.Id() == tag.Id() {
+			return nil
+		}
+	}
+	return apiservererrors.ErrPerm
+}
+
+// Quotas returns the quota limits set for each of the given models or
+// users, along with the amount of each resource they use.
+func (api *API) Quotas(args params.Entities) (params.QuotasResults, error) {
+	results := params.QuotasResults{
+		Results: make([]params.QuotasResult, len(args.Entities)),
+	}
+	for i, arg := range args.Entities {
+		quotas, err := api.quotas(arg.Tag)
+		if err != nil {
+			results.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = quotas
+	}
+	return results, nil
+}
+
+func (api *API) quotas(tagString string) (*params.Quotas, error) {
+	tag, err := parseQuotaTag(tagString)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := api.checkCanRead(tag); err != nil {
+		return nil, errors.Trace(err)
+	}
+	limits, err := api.backend.QuotaLimits(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	usage, err := api.backend.QuotaUsage(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := &params.Quotas{
+		Usage: make(map[string]uint64, len(usage)),
+	}
+	for r, amount := range usage {
+		result.Usage[string(r)] = amount
+	}
+	if len(limits) > 0 {
+		result.Limits = make(map[string]uint64, len(limits))
+		for r, limit := range limits {
+			result.Limits[string(r)] = limit
+		}
+	}
+	return result, nil
+}
+
+// SetQuotas sets or resets the quota limits of each of the given models
+// or users. Only controller superusers can set quotas.
+func (api *API) SetQuotas(args params.SetQuotasArgs) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Args)),
+	}
+	if isAdmin, err := api.isSuperUser(); err != nil {
+		return results, errors.Trace(err)
+	} else if !isAdmin {
+		return results, apiservererrors.ErrPerm
+	}
+	for i, arg := range args.Args {
+		err := api.setQuotas(arg)
+		results.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return results, nil
+}
+
+func (api *API) setQuotas(arg params.SetQuotasArg) error {
+	tag, err := parseQuotaTag(arg.Tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	limits := make(quota.Limits, len(arg.Limits))
+	for r, limit := range arg.Limits {
+		limits[quota.Resource(r)] = limit
+	}
+	reset := make([]quota.Resource, len(arg.Reset))
+	for i, r := range arg.Reset {
+		reset[i] = quota.Resource(r)
+	}
+	return errors.Trace(api.backend.SetQuotaLimits(tag, limits, reset))
+}
+
+// parseQuotaTag parses the tag of a model or user.
+func parseQuotaTag(tagString string) (names.Tag, error) {
+	tag, err := names.ParseTag(tagString)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	switch tag.(type) {
+	case names.ModelTag, names.UserTag:
+		return tag, nil
+	}
+	return nil, errors.NotValidf("quota entity %q", tagString)
+}
@@ -0,0 +1,167 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelquotas_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/client/modelquotas"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/quota"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type modelQuotasSuite struct {
+	testing.IsolationSuite
+
+	backend  *mockBackend
+	modelTag names.ModelTag
+}
+
+var _ = gc.Suite(&modelQuotasSuite{})
+
+func (s *modelQuotasSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.modelTag = names.NewModelTag(coretesting.ModelTag.Id())
+	s.backend = &mockBackend{
+		limits: map[string]quota.Limits{
+			s.modelTag.String(): {quota.Machines: 10},
+		},
+		usage: quota.Usage{
+			quota.Machines: 3,
+			quota.Units:    5,
+		},
+	}
+}
+
+func (s *modelQuotasSuite) newAPI(c *gc.C, user string) *modelquotas.API {
+	api, err := modelquotas.NewAPI(s.backend, apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag(user),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *modelQuotasSuite) TestNewAPIRequiresClient(c *gc.C) {
+	_, err := modelquotas.NewAPI(s.backend, apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *modelQuotasSuite) TestQuotas(c *gc.C) {
+	api := s.newAPI(c, "read")
+	results, err := api.Quotas(params.Entities{Entities: []params.Entity{
+		{Tag: s.modelTag.String()},
+		{Tag: "user-read"},
+		{Tag: "user-bob"},
+		{Tag: "machine-0"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.QuotasResults{
+		Results: []params.QuotasResult{{
+			Result: &params.Quotas{
+				Limits: map[string]uint64{"machines": 10},
+				Usage:  map[string]uint64{"machines": 3, "units": 5},
+			},
+		}, {
+			Result: &params.Quotas{
+				Usage: map[string]uint64{"machines": 3, "units": 5},
+			},
+		}, {
+			Error: &params.Error{Message: "permission denied", Code: params.CodeUnauthorized},
+		}, {
+			Error: &params.Error{Message: `quota entity "machine-0" not valid`},
+		}},
+	})
+}
+
+func (s *modelQuotasSuite) TestQuotasSuperuser(c *gc.C) {
+	api := s.newAPI(c, "superuser-alice")
+	results, err := api.Quotas(params.Entities{Entities: []params.Entity{
+		{Tag: "user-bob"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+}
+
+func (s *modelQuotasSuite) TestQuotasNoModelAccess(c *gc.C) {
+	api := s.newAPI(c, "bob")
+	results, err := api.Quotas(params.Entities{Entities: []params.Entity{
+		{Tag: s.modelTag.String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, "permission denied")
+}
+
+func (s *modelQuotasSuite) TestSetQuotas(c *gc.C) {
+	api := s.newAPI(c, "superuser-alice")
+	results, err := api.SetQuotas(params.SetQuotasArgs{Args: []params.SetQuotasArg{{
+		Tag:    s.modelTag.String(),
+		Limits: map[string]uint64{"units": 20},
+		Reset:  []string{"machines"},
+	}, {
+		Tag:    "user-bob",
+		Limits: map[string]uint64{"storage": 1024},
+	}, {
+		Tag: "application-mysql",
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{},
+			{Error: &params.Error{Message: `quota entity "application-mysql" not valid`}},
+		},
+	})
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"ControllerTag", nil},
+		{"SetQuotaLimits", []interface{}{s.modelTag, quota.Limits{quota.Units: 20}, []quota.Resource{quota.Machines}}},
+		{"SetQuotaLimits", []interface{}{names.NewUserTag("bob"), quota.Limits{quota.Storage: 1024}, []quota.Resource{}}},
+	})
+}
+
+func (s *modelQuotasSuite) TestSetQuotasRequiresSuperuser(c *gc.C) {
+	api := s.newAPI(c, "adminuser")
+	_, err := api.SetQuotas(params.SetQuotasArgs{Args: []params.SetQuotasArg{{
+		Tag:    s.modelTag.String(),
+		Limits: map[string]uint64{"units": 20},
+	}}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+type mockBackend struct {
+	testing.Stub
+	limits map[string]quota.Limits
+	usage  quota.Usage
+}
+
+func (b *mockBackend) ControllerTag() names.ControllerTag {
+	b.MethodCall(b, "ControllerTag")
+	return coretesting.ControllerTag
+}
+
+func (b *mockBackend) QuotaLimits(tag names.Tag) (quota.Limits, error) {
+	b.MethodCall(b, "QuotaLimits", tag)
+	return b.limits[tag.String()], b.NextErr()
+}
+
+func (b *mockBackend) SetQuotaLimits(tag names.Tag, limits quota.Limits, reset []quota.Resource) error {
+	b.MethodCall(b, "SetQuotaLimits", tag, limits, reset)
+	return b.NextErr()
+}
+
+func (b *mockBackend) QuotaUsage(tag names.Tag) (quota.Usage, error) {
+	b.MethodCall(b, "QuotaUsage", tag)
+	if err := b.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return b.usage, nil
+}
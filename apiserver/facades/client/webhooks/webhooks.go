@@ -0,0 +1,148 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package webhooks provides the API for managing the webhooks notified
+// of events in a model.
+package webhooks
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/core/webhook"
+	"github.com/juju/juju/state"
+)
+
+// Backend contains the state.Model methods used in this package,
+// allowing stubs to be created for testing.
+type Backend interface {
+	ControllerTag() names.ControllerTag
+	ModelTag() names.ModelTag
+	AddWebhook(webhook.Webhook, string) (state.Webhook, error)
+	Webhooks() ([]state.Webhook, error)
+	RemoveWebhook(string) error
+}
+
+// API implements the Webhooks facade.
+type API struct {
+	backend Backend
+	auth    facade.Authorizer
+}
+
+// NewFacade is used for API registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	model, err := ctx.State().Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewAPI(model, ctx.Auth())
+}
+
+// NewAPI returns a new Webhooks API facade.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, apiservererrors.ErrPerm
+	}
+	return &API{
+		backend: backend,
+		auth:    authorizer,
+	}, nil
+}
+
+// checkCanAdmin checks that the authenticated user can manage the
+// model's webhooks: model admins and controller superusers can. As the
+// webhook URLs may hold credentials, only they can list them too.
+func (api *API) checkCanAdmin() error {
+	isAdmin, err := api.auth.HasPermission(permission.SuperuserAccess, api.backend.ControllerTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if isAdmin {
+		return nil
+	}
+	canAdmin, err := api.auth.HasPermission(permission.AdminAccess, api.backend.ModelTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !canAdmin {
+		return apiservererrors.ErrPerm
+	}
+	return nil
+}
+
+// AddWebhooks registers webhooks to be notified of events in the model,
+// returning the id of each added webhook.
+func (api *API) AddWebhooks(args params.AddWebhooksArgs) (params.AddWebhookResults, error) {
+	results := params.AddWebhookResults{
+		Results: make([]params.AddWebhookResult, len(args.Webhooks)),
+	}
+	if err := api.checkCanAdmin(); err != nil {
+		return results, errors.Trace(err)
+	}
+	createdBy := api.auth.GetAuthTag().Id()
+	for i, arg := range args.Webhooks {
+		hook := webhook.Webhook{
+			URL:      arg.URL,
+			Secret:   arg.Secret,
+			Template: arg.Template,
+		}
+		for _, event := range arg.Events {
+			hook.Events = append(hook.Events, webhook.EventKind(event))
+		}
+		added, err := api.backend.AddWebhook(hook, createdBy)
+		if err != nil {
+			results.Results[i].Error = apiservererrors.ServerError(err)
+			continue
+		}
+		results.Results[i].Id = added.Id
+	}
+	return results, nil
+}
+
+// ListWebhooks returns the webhooks registered in the model.
+func (api *API) ListWebhooks() (params.WebhooksResult, error) {
+	if err := api.checkCanAdmin(); err != nil {
+		return params.WebhooksResult{}, errors.Trace(err)
+	}
+	hooks, err := api.backend.Webhooks()
+	if err != nil {
+		return params.WebhooksResult{}, errors.Trace(err)
+	}
+	result := params.WebhooksResult{
+		Webhooks: make([]params.Webhook, len(hooks)),
+	}
+	for i, hook := range hooks {
+		item := params.Webhook{
+			Id:        hook.Id,
+			URL:       hook.URL,
+			Signed:    hook.Secret != "",
+			Template:  hook.Template,
+			CreatedBy: hook.CreatedBy,
+			Created:   hook.Created,
+		}
+		for _, event := range hook.Events {
+			item.Events = append(item.Events, string(event))
+		}
+		result.Webhooks[i] = item
+	}
+	return result, nil
+}
+
+// RemoveWebhooks removes the webhooks with the given ids from the model.
+func (api *API) RemoveWebhooks(args params.RemoveWebhooksArgs) (params.ErrorResults, error) {
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	if err := api.checkCanAdmin(); err != nil {
+		return results, errors.Trace(err)
+	}
+	for i, id := range args.Ids {
+		err := api.backend.RemoveWebhook(id)
+		results.Results[i].Error = apiservererrors.ServerError(err)
+	}
+	return results, nil
+}
@@ -0,0 +1,196 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhooks_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/client/webhooks"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/webhook"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type webhooksSuite struct {
+	testing.IsolationSuite
+
+	backend *mockBackend
+
+	// modelAdmin is the name of a user with admin access to the model
+	// only.
+	modelAdmin string
+}
+
+var _ = gc.Suite(&webhooksSuite{})
+
+func (s *webhooksSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.modelAdmin = "admin-" + coretesting.ModelTag.String()
+	s.backend = &mockBackend{
+		webhooks: []state.Webhook{{
+			Webhook: webhook.Webhook{
+				Id:     "1",
+				URL:    "https://example.com/hook",
+				Events: []webhook.EventKind{webhook.UnitError, webhook.MachineDown},
+				Secret: "s3cret",
+			},
+			CreatedBy: "admin",
+			Created:   time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		}, {
+			Webhook: webhook.Webhook{
+				Id:       "2",
+				URL:      "http://example.com/other",
+				Template: `{"text": {{json .Entity}}}`,
+			},
+			CreatedBy: "bob",
+			Created:   time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+}
+
+func (s *webhooksSuite) newAPI(c *gc.C, user string) *webhooks.API {
+	api, err := webhooks.NewAPI(s.backend, apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag(user),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return api
+}
+
+func (s *webhooksSuite) TestNewAPIRequiresClient(c *gc.C) {
+	_, err := webhooks.NewAPI(s.backend, apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *webhooksSuite) TestAddWebhooks(c *gc.C) {
+	s.backend.SetErrors(nil, errors.NotValidf("webhook event %q", "bogus"))
+	api := s.newAPI(c, s.modelAdmin)
+	results, err := api.AddWebhooks(params.AddWebhooksArgs{Webhooks: []params.AddWebhookArg{{
+		URL:    "https://example.com/hook",
+		Events: []string{"unit-error", "action-failed"},
+		Secret: "s3cret",
+	}, {
+		URL:    "https://example.com/hook",
+		Events: []string{"bogus"},
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.AddWebhookResults{
+		Results: []params.AddWebhookResult{
+			{Id: "3"},
+			{Error: &params.Error{Message: `webhook event "bogus" not valid`}},
+		},
+	})
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"ControllerTag", nil},
+		{"ModelTag", nil},
+		{"AddWebhook", []interface{}{webhook.Webhook{
+			URL:    "https://example.com/hook",
+			Events: []webhook.EventKind{webhook.UnitError, webhook.ActionFailed},
+			Secret: "s3cret",
+		}, s.modelAdmin}},
+		{"AddWebhook", []interface{}{webhook.Webhook{
+			URL:    "https://example.com/hook",
+			Events: []webhook.EventKind{"bogus"},
+		}, s.modelAdmin}},
+	})
+}
+
+func (s *webhooksSuite) TestAddWebhooksRequiresAdmin(c *gc.C) {
+	api := s.newAPI(c, "writeuser")
+	_, err := api.AddWebhooks(params.AddWebhooksArgs{Webhooks: []params.AddWebhookArg{{
+		URL: "https://example.com/hook",
+	}}})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	s.backend.CheckCallNames(c, "ControllerTag", "ModelTag")
+}
+
+func (s *webhooksSuite) TestListWebhooks(c *gc.C) {
+	api := s.newAPI(c, "superuser-alice")
+	result, err := api.ListWebhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.WebhooksResult{
+		Webhooks: []params.Webhook{{
+			Id:        "1",
+			URL:       "https://example.com/hook",
+			Events:    []string{"unit-error", "machine-down"},
+			Signed:    true,
+			CreatedBy: "admin",
+			Created:   time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		}, {
+			Id:        "2",
+			URL:       "http://example.com/other",
+			Template:  `{"text": {{json .Entity}}}`,
+			CreatedBy: "bob",
+			Created:   time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+		}},
+	})
+}
+
+func (s *webhooksSuite) TestListWebhooksRequiresAdmin(c *gc.C) {
+	api := s.newAPI(c, "readuser")
+	_, err := api.ListWebhooks()
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *webhooksSuite) TestRemoveWebhooks(c *gc.C) {
+	s.backend.SetErrors(nil, errors.NotFoundf("webhook %q", "42"))
+	api := s.newAPI(c, s.modelAdmin)
+	results, err := api.RemoveWebhooks(params.RemoveWebhooksArgs{Ids: []string{"1", "42"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Message: `webhook "42" not found`, Code: params.CodeNotFound}},
+		},
+	})
+	s.backend.CheckCalls(c, []testing.StubCall{
+		{"ControllerTag", nil},
+		{"ModelTag", nil},
+		{"RemoveWebhook", []interface{}{"1"}},
+		{"RemoveWebhook", []interface{}{"42"}},
+	})
+}
+
+type mockBackend struct {
+	testing.Stub
+	webhooks []state.Webhook
+}
+
+func (b *mockBackend) ControllerTag() names.ControllerTag {
+	b.MethodCall(b, "ControllerTag")
+	return coretesting.ControllerTag
+}
+
+func (b *mockBackend) ModelTag() names.ModelTag {
+	b.MethodCall(b, "ModelTag")
+	return coretesting.ModelTag
+}
+
+func (b *mockBackend) AddWebhook(hook webhook.Webhook, createdBy string) (state.Webhook, error) {
+	b.MethodCall(b, "AddWebhook", hook, createdBy)
+	if err := b.NextErr(); err != nil {
+		return state.Webhook{}, err
+	}
+	hook.Id = "3"
+	return state.Webhook{Webhook: hook, CreatedBy: createdBy}, nil
+}
+
+func (b *mockBackend) Webhooks() ([]state.Webhook, error) {
+	b.MethodCall(b, "Webhooks")
+	return b.webhooks, b.NextErr()
+}
+
+func (b *mockBackend) RemoveWebhook(id string) error {
+	b.MethodCall(b, "RemoveWebhook", id)
+	return b.NextErr()
+}
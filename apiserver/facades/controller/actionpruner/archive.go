@@ -0,0 +1,109 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionpruner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
+
+// archiver returns a function which archives pruned operations to the
+// given destination, or nil if they are not archived.
+func (api *API) archiver(destination string) (func([]state.OperationInfo) error, error) {
+	switch destination {
+	case "":
+		return nil, nil
+	case config.ActionResultsArchiveStorage:
+		return api.archiveToStorage, nil
+	case config.ActionResultsArchiveDirectory:
+		controllerConfig, err := api.st.ControllerConfig()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		dir := controllerConfig.ActionResultsArchiveDir()
+		if dir == "" {
+			// Don't prune results that can't be archived.
+			return nil, errors.NotValidf("%s %q without controller %s", config.ActionResultsArchive, destination, controller.ActionResultsArchiveDir)
+		}
+		return func(operations []state.OperationInfo) error {
+			return api.archiveToDirectory(dir, operations)
+		}, nil
+	}
+	return nil, errors.NotValidf("%s %q", config.ActionResultsArchive, destination)
+}
+
+// encodeOperations returns the operations encoded as JSON Lines, in the
+// same format as the output of export-operations, together with the
+// completion times of the first and last of them.
+func encodeOperations(operations []state.OperationInfo) ([]byte, time.Time, time.Time, error) {
+	var (
+		buf              bytes.Buffer
+		earliest, latest time.Time
+	)
+	enc := json.NewEncoder(&buf)
+	for _, info := range operations {
+		completed := info.Operation.Completed()
+		if earliest.IsZero() || completed.Before(earliest) {
+			earliest = completed
+		}
+		if completed.After(latest) {
+			latest = completed
+		}
+		if err := enc.Encode(common.MakeOperationResult(info)); err != nil {
+			return nil, time.Time{}, time.Time{}, errors.Trace(err)
+		}
+	}
+	return buf.Bytes(), earliest, latest, nil
+}
+
+func (api *API) archiveToStorage(operations []state.OperationInfo) error {
+	if len(operations) == 0 {
+		return nil
+	}
+	data, earliest, latest, err := encodeOperations(operations)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	archive, err := api.model.AddOperationArchive(state.OperationArchive{
+		Earliest: earliest,
+		Latest:   latest,
+		Count:    len(operations),
+	}, bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	logger.Debugf("archived %d operations in operation archive %s", archive.Count, archive.Id)
+	return nil
+}
+
+func (api *API) archiveToDirectory(dir string, operations []state.OperationInfo) error {
+	if len(operations) == 0 {
+		return nil
+	}
+	data, _, _, err := encodeOperations(operations)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return errors.Annotate(err, "creating operation archive directory")
+	}
+	path := filepath.Join(dir, fmt.Sprintf("operations-%s-%d.jsonl", api.model.UUID(), time.Now().UnixNano()))
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return errors.Annotate(err, "writing operation archive")
+	}
+	logger.Debugf("archived %d operations in %s", len(operations), path)
+	return nil
+}
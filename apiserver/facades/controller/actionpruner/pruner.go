@@ -4,13 +4,21 @@
 package actionpruner
 
 import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
 	"github.com/juju/juju/apiserver/common"
 	apiservererrors "github.com/juju/juju/apiserver/errors"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/application"
 	"github.com/juju/juju/state"
 )
 
+var logger = loggo.GetLogger("juju.apiserver.actionpruner")
+
 type API struct {
 	*common.ModelWatcher
 	st         *state.State
@@ -27,14 +35,62 @@ func NewAPI(st *state.State, r facade.Resources, auth facade.Authorizer) (*API,
 	return &API{
 		ModelWatcher: common.NewModelWatcher(m, r, auth),
 		st:           st,
+		model:        m,
 		authorizer:   auth,
 	}, nil
 }
 
+// Prune removes the results of operations which are older than the
+// maximum age, taking into account the overrides in the applications'
+// config, and then the oldest results until the results are smaller
+// than the maximum size. If the model is configured to archive action
+// results, the pruned operations are archived first.
 func (api *API) Prune(p params.ActionPruneArgs) error {
 	if !api.authorizer.AuthController() {
 		return apiservererrors.ErrPerm
 	}
 
-	return state.PruneOperations(api.st, p.MaxHistoryTime, p.MaxHistoryMB)
+	appMaxAge, err := api.applicationMaxAge()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cfg, err := api.model.ModelConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	archive, err := api.archiver(cfg.ActionResultsArchive())
+	if err != nil {
+		return errors.Annotate(err, "cannot archive action results")
+	}
+	return state.PruneOperationsWithRetention(api.st, state.OperationRetention{
+		MaxAge:            p.MaxHistoryTime,
+		MaxSizeMB:         p.MaxHistoryMB,
+		ApplicationMaxAge: appMaxAge,
+		Archive:           archive,
+	})
+}
+
+// applicationMaxAge returns the maximum age of the results of tasks
+// run on each application which overrides the model's.
+func (api *API) applicationMaxAge() (map[string]time.Duration, error) {
+	apps, err := api.st.AllApplications()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make(map[string]time.Duration)
+	for _, app := range apps {
+		cfg, err := app.ApplicationConfig()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		maxAge, ok, err := application.ActionResultsMaxAge(cfg)
+		if err != nil {
+			logger.Warningf("ignoring action results retention for application %q: %v", app.Name(), err)
+			continue
+		}
+		if ok {
+			result[app.Name()] = maxAge
+		}
+	}
+	return result, nil
 }
@@ -0,0 +1,33 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The actionschedules package implements the API interface
+// used by the action scheduler worker.
+
+package actionschedules
+
+import (
+	"github.com/juju/juju/apiserver/common"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/state"
+)
+
+// ActionSchedulesAPI implements the API used by the action scheduler worker.
+type ActionSchedulesAPI struct {
+	*common.DueWorkRunner
+}
+
+// NewActionSchedulesAPI creates a new instance of the ActionSchedules API.
+func NewActionSchedulesAPI(
+	st *state.State,
+	res facade.Resources,
+	authorizer facade.Authorizer,
+) (*ActionSchedulesAPI, error) {
+	if !authorizer.AuthController() {
+		return nil, apiservererrors.ErrPerm
+	}
+	return &ActionSchedulesAPI{
+		DueWorkRunner: common.NewDueWorkRunner(stateShim{st}, res),
+	}, nil
+}
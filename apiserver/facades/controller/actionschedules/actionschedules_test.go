@@ -0,0 +1,49 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionschedules_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/facades/controller/actionschedules"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+// ActionSchedulesSuite tests the creation of the facade. Its methods are those
+// of common.DueWorkRunner, which are tested there.
+type ActionSchedulesSuite struct {
+	coretesting.BaseSuite
+
+	authoriser apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&ActionSchedulesSuite{})
+
+func (s *ActionSchedulesSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+
+	s.authoriser = apiservertesting.FakeAuthorizer{
+		Controller: true,
+	}
+}
+
+func (s *ActionSchedulesSuite) TestNewActionSchedulesAPI(c *gc.C) {
+	api, err := actionschedules.NewActionSchedulesAPI(nil, common.NewResources(), s.authoriser)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(api, gc.NotNil)
+}
+
+func (s *ActionSchedulesSuite) TestNewActionSchedulesAPIRequiresController(c *gc.C) {
+	anAuthoriser := s.authoriser
+	anAuthoriser.Controller = false
+	api, err := actionschedules.NewActionSchedulesAPI(nil, nil, anAuthoriser)
+	c.Assert(api, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(apiservererrors.ServerError(err), jc.Satisfies, params.IsCodeUnauthorized)
+}
@@ -0,0 +1,33 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionschedules
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state"
+)
+
+// stateShim implements common.DueWorkBackend for the ActionSchedules facade.
+type stateShim struct {
+	*state.State
+}
+
+// RunDueWork is part of common.DueWorkBackend. It releases the tasks
+// of any scheduled runs which are due, and schedules the next run of
+// recurring schedules.
+func (s stateShim) RunDueWork() (time.Time, error) {
+	m, err := s.State.Model()
+	if err != nil {
+		return time.Time{}, errors.Trace(err)
+	}
+	return m.RunActionSchedules()
+}
+
+// WatchDueWork is part of common.DueWorkBackend.
+func (s stateShim) WatchDueWork() state.NotifyWatcher {
+	return s.State.WatchActionSchedules()
+}
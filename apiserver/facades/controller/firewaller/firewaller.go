@@ -92,7 +92,7 @@ func NewStateFirewallerAPIV4(context facade.Context) (*FirewallerAPIV4, error) {
 		return nil, err
 	}
 	return &FirewallerAPIV4{
-		ControllerConfigAPI: common.NewStateControllerConfig(context.State()),
+		ControllerConfigAPI: common.NewStateControllerConfig(context.State(), context.Auth()),
 		FirewallerAPIV3:     facadev3,
 	}, nil
 }
@@ -184,7 +184,7 @@ func (s *firewallerSuite) TestAreManuallyProvisioned(c *gc.C) {
 	apiv5 := &firewaller.FirewallerAPIV5{
 		&firewaller.FirewallerAPIV4{
 			FirewallerAPIV3:     s.firewaller,
-			ControllerConfigAPI: common.NewControllerConfig(newMockState(coretesting.ModelTag.Id())),
+			ControllerConfigAPI: common.NewControllerConfig(newMockState(coretesting.ModelTag.Id()), s.authorizer),
 		}}
 
 	result, err := apiv5.AreManuallyProvisioned(args)
@@ -211,7 +211,7 @@ func (s *firewallerSuite) TestGetExposeInfo(c *gc.C) {
 		&firewaller.FirewallerAPIV5{
 			&firewaller.FirewallerAPIV4{
 				FirewallerAPIV3:     s.firewaller,
-				ControllerConfigAPI: common.NewControllerConfig(newMockState(coretesting.ModelTag.Id())),
+				ControllerConfigAPI: common.NewControllerConfig(newMockState(coretesting.ModelTag.Id()), s.authorizer),
 			},
 		},
 	}
@@ -241,7 +241,7 @@ func (s *firewallerSuite) TestWatchSubnets(c *gc.C) {
 		&firewaller.FirewallerAPIV5{
 			&firewaller.FirewallerAPIV4{
 				FirewallerAPIV3:     s.firewaller,
-				ControllerConfigAPI: common.NewControllerConfig(newMockState(coretesting.ModelTag.Id())),
+				ControllerConfigAPI: common.NewControllerConfig(newMockState(coretesting.ModelTag.Id()), s.authorizer),
 			},
 		},
 	}
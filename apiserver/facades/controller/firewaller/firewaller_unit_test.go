@@ -47,7 +47,7 @@ func (s *RemoteFirewallerSuite) SetUpTest(c *gc.C) {
 	s.st = newMockState(coretesting.ModelTag.Id())
 	api, err := firewaller.NewFirewallerAPI(s.st, s.resources, s.authorizer, &mockCloudSpecAPI{})
 	c.Assert(err, jc.ErrorIsNil)
-	s.api = &firewaller.FirewallerAPIV4{FirewallerAPIV3: api, ControllerConfigAPI: common.NewControllerConfig(s.st)}
+	s.api = &firewaller.FirewallerAPIV4{FirewallerAPIV3: api, ControllerConfigAPI: common.NewControllerConfig(s.st, s.authorizer)}
 }
 
 func (s *RemoteFirewallerSuite) TestWatchIngressAddressesForRelations(c *gc.C) {
@@ -165,7 +165,7 @@ func (s *FirewallerSuite) SetUpTest(c *gc.C) {
 		&firewaller.FirewallerAPIV5{
 			&firewaller.FirewallerAPIV4{
 				FirewallerAPIV3:     api,
-				ControllerConfigAPI: common.NewControllerConfig(newMockState(coretesting.ModelTag.Id())),
+				ControllerConfigAPI: common.NewControllerConfig(newMockState(coretesting.ModelTag.Id()), s.authorizer),
 			},
 		},
 	}
@@ -0,0 +1,33 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The rollouts package implements the API interface
+// used by the rollouts worker.
+
+package rollouts
+
+import (
+	"github.com/juju/juju/apiserver/common"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/state"
+)
+
+// RolloutsAPI implements the API used by the rollouts worker.
+type RolloutsAPI struct {
+	*common.DueWorkRunner
+}
+
+// NewRolloutsAPI creates a new instance of the Rollouts API.
+func NewRolloutsAPI(
+	st *state.State,
+	res facade.Resources,
+	authorizer facade.Authorizer,
+) (*RolloutsAPI, error) {
+	if !authorizer.AuthController() {
+		return nil, apiservererrors.ErrPerm
+	}
+	return &RolloutsAPI{
+		DueWorkRunner: common.NewDueWorkRunner(stateShim{st}, res),
+	}, nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rollouts_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	apiservererrors "github.com/juju/juju/apiserver/errors"
+	"github.com/juju/juju/apiserver/facades/controller/rollouts"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+// RolloutsSuite tests the creation of the facade. Its methods are those
+// of common.DueWorkRunner, which are tested there.
+type RolloutsSuite struct {
+	coretesting.BaseSuite
+
+	authoriser apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&RolloutsSuite{})
+
+func (s *RolloutsSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+
+	s.authoriser = apiservertesting.FakeAuthorizer{
+		Controller: true,
+	}
+}
+
+func (s *RolloutsSuite) TestNewRolloutsAPI(c *gc.C) {
+	api, err := rollouts.NewRolloutsAPI(nil, common.NewResources(), s.authoriser)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(api, gc.NotNil)
+}
+
+func (s *RolloutsSuite) TestNewRolloutsAPIRequiresController(c *gc.C) {
+	anAuthoriser := s.authoriser
+	anAuthoriser.Controller = false
+	api, err := rollouts.NewRolloutsAPI(nil, nil, anAuthoriser)
+	c.Assert(api, gc.IsNil)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	c.Assert(apiservererrors.ServerError(err), jc.Satisfies, params.IsCodeUnauthorized)
+}
@@ -0,0 +1,32 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rollouts
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state"
+)
+
+// stateShim implements common.DueWorkBackend for the Rollouts facade.
+type stateShim struct {
+	*state.State
+}
+
+// RunDueWork is part of common.DueWorkBackend. It releases the next
+// tasks of any rolling operations which are ready for them.
+func (s stateShim) RunDueWork() (time.Time, error) {
+	m, err := s.State.Model()
+	if err != nil {
+		return time.Time{}, errors.Trace(err)
+	}
+	return m.AdvanceRollouts()
+}
+
+// WatchDueWork is part of common.DueWorkBackend.
+func (s stateShim) WatchDueWork() state.NotifyWatcher {
+	return s.State.WatchRollouts()
+}
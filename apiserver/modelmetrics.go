@@ -0,0 +1,98 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/cache"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+)
+
+// modelMetricsHandler is an http.Handler that serves Prometheus metrics
+// for the status of the entities in a model. The metrics are built from
+// the model cache, so scraping them does not query the database.
+type modelMetricsHandler struct {
+	ctxt       httpContext
+	controller *cache.Controller
+}
+
+// ServeHTTP is part of the http.Handler interface.
+func (h *modelMetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h.serveMetrics(w, r); err != nil {
+		if err := sendError(w, err); err != nil {
+			logger.Debugf("%v", err)
+		}
+	}
+}
+
+func (h *modelMetricsHandler) serveMetrics(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != "GET" {
+		return errors.MethodNotAllowedf("unsupported method: %q", r.Method)
+	}
+	st, entity, err := h.ctxt.stateAndEntityForRequestAuthenticatedUser(r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer st.Release()
+
+	if err := checkModelMetricsAccess(st.State, entity.Tag()); err != nil {
+		return errors.Trace(err)
+	}
+
+	model, err := h.controller.Model(st.ModelUUID())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(cache.NewModelMetricsCollector(model)); err != nil {
+		return errors.Trace(err)
+	}
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+	return nil
+}
+
+// checkModelMetricsAccess checks that the user can see the metrics for
+// the model; users with "read" access on the model, or "superuser"
+// access on the controller, can.
+func checkModelMetricsAccess(st *state.State, tag names.Tag) error {
+	ok, err := common.HasPermission(
+		st.UserPermission,
+		tag,
+		permission.ReadAccess,
+		names.NewModelTag(st.ModelUUID()),
+	)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if ok {
+		return nil
+	}
+
+	ok, err = common.HasPermission(
+		st.UserPermission,
+		tag,
+		permission.SuperuserAccess,
+		st.ControllerTag(),
+	)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if ok {
+		return nil
+	}
+
+	return &params.Error{
+		Code:    params.CodeForbidden,
+		Message: "access denied",
+	}
+}
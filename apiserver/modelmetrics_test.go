@@ -0,0 +1,87 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/juju/clock"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	apitesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/state"
+)
+
+type modelMetricsSuite struct {
+	apiserverBaseSuite
+	bob *state.User
+	url string
+}
+
+var _ = gc.Suite(&modelMetricsSuite{})
+
+func (s *modelMetricsSuite) SetUpTest(c *gc.C) {
+	s.apiserverBaseSuite.SetUpTest(c)
+	bob, err := s.State.AddUser("bob", "", "hunter2", "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	s.bob = bob
+	s.url = s.URL("/model/"+s.State.ModelUUID()+"/metrics", nil).String()
+
+	// Ensure the model is in the cache before it is scraped.
+	_, err = s.config.Controller.WaitForModel(s.State.ModelUUID(), clock.WallClock)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *modelMetricsSuite) TestMetrics(c *gc.C) {
+	s.testAccess(c, s.Owner.String(), ownerPassword)
+}
+
+func (s *modelMetricsSuite) TestReadAccess(c *gc.C) {
+	_, err := s.Model.AddUser(
+		state.UserAccessSpec{
+			User:      s.bob.UserTag(),
+			CreatedBy: s.Owner,
+			Access:    permission.ReadAccess,
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	s.testAccess(c, "user-bob", "hunter2")
+}
+
+func (s *modelMetricsSuite) testAccess(c *gc.C, tag, password string) {
+	resp := apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method:   "GET",
+		URL:      s.url,
+		Tag:      tag,
+		Password: password,
+	})
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
+	content, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(content), jc.Contains, `juju_model_migration_mode{mode="none",model="admin/controller",model_uuid="`+s.State.ModelUUID()+`"} 1`)
+}
+
+func (s *modelMetricsSuite) TestAccessDenied(c *gc.C) {
+	resp := apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method:   "GET",
+		URL:      s.url,
+		Tag:      "user-bob",
+		Password: "hunter2",
+	})
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusForbidden)
+}
+
+func (s *modelMetricsSuite) TestMethodNotAllowed(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "POST",
+		URL:    s.url,
+	})
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusMethodNotAllowed)
+}
@@ -38,6 +38,34 @@ const (
 // Actions is a slice of Action for bulk requests.
 type Actions struct {
 	Actions []Action `json:"actions,omitempty"`
+
+	// Rollout, if set, releases the actions to their receivers
+	// in batches rather than all at once.
+	Rollout *RolloutParams `json:"rollout,omitempty"`
+
+	// Schedule, if set, runs the actions at a later time, and
+	// possibly again on a recurring basis, rather than now.
+	Schedule *ScheduleParams `json:"schedule,omitempty"`
+}
+
+// ScheduleParams holds when scheduled actions are run.
+type ScheduleParams struct {
+	// At is the time of the first run. If it is not set, the first
+	// run is the first time matching Recurrence.
+	At *time.Time `json:"at,omitempty"`
+
+	// Recurrence is a cron spec or descriptor, interpreted in UTC,
+	// for when the actions are run again.
+	Recurrence string `json:"recurrence,omitempty"`
+}
+
+// RolloutParams controls how the tasks of a rolling operation are
+// released to their receivers.
+type RolloutParams struct {
+	BatchSize   int           `json:"batch-size,omitempty"`
+	MaxParallel int           `json:"max-parallel,omitempty"`
+	BatchWait   time.Duration `json:"batch-wait,omitempty"`
+	MaxFailures int           `json:"max-failures,omitempty"`
 }
 
 // Action describes an Action that will be or has been queued up.
@@ -118,6 +146,22 @@ type OperationQueryArgs struct {
 	Limit  *int `json:"limit,omitempty"`
 }
 
+// OperationExportArgs holds args for exporting the operations which
+// completed in a time window.
+type OperationExportArgs struct {
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+
+	// Archived is true to export the operations which have been
+	// pruned and archived in the controller's blob storage.
+	Archived bool `json:"archived,omitempty"`
+
+	// These attributes are used to support client side
+	// batching of results.
+	Offset *int `json:"offset,omitempty"`
+	Limit  *int `json:"limit,omitempty"`
+}
+
 // OperationResults is a slice of OperationResult for bulk requests.
 type OperationResults struct {
 	Results   []OperationResult `json:"results,omitempty"`
@@ -133,9 +177,33 @@ type OperationResult struct {
 	Completed    time.Time      `json:"completed,omitempty"`
 	Status       string         `json:"status,omitempty"`
 	Actions      []ActionResult `json:"actions,omitempty"`
+	Rollout      *RolloutInfo   `json:"rollout,omitempty"`
 	Error        *Error         `json:"error,omitempty"`
 }
 
+// RolloutInfo describes the progress of a rolling operation.
+type RolloutInfo struct {
+	RolloutParams `json:",inline"`
+	Halted        string         `json:"halted,omitempty"`
+	Batches       []RolloutBatch `json:"batches"`
+}
+
+// DueWorkResult holds the result of doing the work that is due, such
+// as advancing rolling operations or running action schedules.
+type DueWorkResult struct {
+	// Next is when more work will be due. It is not set if no work
+	// is waiting.
+	Next *time.Time `json:"next,omitempty"`
+}
+
+// RolloutBatch holds the number of tasks of a rollout batch in each state.
+type RolloutBatch struct {
+	Pending   int `json:"pending"`
+	Running   int `json:"running"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
 // ActionExecutionResults holds a slice of ActionExecutionResult for a
 // bulk action API call
 type ActionExecutionResults struct {
@@ -259,6 +259,76 @@ type ApplicationSetCharm struct {
 	EndpointBindings map[string]string `json:"endpoint-bindings,omitempty"`
 }
 
+// CharmRefreshReportArgs holds the parameters for reporting what
+// refreshing an application to a new charm would change.
+type CharmRefreshReportArgs struct {
+	// ApplicationName is the name of the application to report on.
+	ApplicationName string `json:"application"`
+
+	// Generation is the generation whose charm config is compared.
+	Generation string `json:"generation"`
+
+	// CharmURL is the url of the charm the application would be
+	// refreshed to. The charm must already be added to the model.
+	CharmURL string `json:"charm-url"`
+
+	// ForceSeries reports whether the refresh would be forced onto
+	// a series not supported by the new charm.
+	ForceSeries bool `json:"force-series,omitempty"`
+
+	// ResourceUploads holds the names of the resources the operator
+	// would upload during the refresh.
+	ResourceUploads []string `json:"resource-uploads,omitempty"`
+}
+
+// CharmRefreshReport describes what refreshing an application to a
+// new charm would change, without changing anything.
+type CharmRefreshReport struct {
+	ApplicationName string `json:"application"`
+	CharmURL        string `json:"charm-url"`
+	NewCharmURL     string `json:"new-charm-url"`
+
+	Config     []CharmRefreshChange         `json:"config,omitempty"`
+	Endpoints  []CharmRefreshChange         `json:"endpoints,omitempty"`
+	Storage    []CharmRefreshChange         `json:"storage,omitempty"`
+	Devices    []CharmRefreshChange         `json:"devices,omitempty"`
+	LXDProfile []CharmRefreshChange         `json:"lxd-profile,omitempty"`
+	Resources  []CharmRefreshResourceChange `json:"resources,omitempty"`
+
+	// BrokenRelations holds the keys of the existing relations that
+	// the new charm no longer implements.
+	BrokenRelations []string `json:"broken-relations,omitempty"`
+
+	// Blockers holds the reasons the controller would refuse the
+	// refresh.
+	Blockers []string `json:"blockers,omitempty"`
+}
+
+// CharmRefreshChange describes a single difference between an
+// application's current charm and the charm it would be refreshed to.
+type CharmRefreshChange struct {
+	Name string `json:"name"`
+
+	// Change is one of "added", "removed" or "changed".
+	Change string `json:"change"`
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// CharmRefreshResourceChange describes what a refresh would do with
+// one of the new charm's resources.
+type CharmRefreshResourceChange struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+
+	// Action is one of "fetch", "upload", "keep" or "remove".
+	Action      string `json:"action"`
+	Origin      string `json:"origin,omitempty"`
+	Revision    *int   `json:"revision,omitempty"`
+	NewRevision *int   `json:"new-revision,omitempty"`
+}
+
 // ApplicationExpose holds the parameters for making the application Expose call.
 type ApplicationExpose struct {
 	ApplicationName string `json:"application"`
@@ -617,12 +687,35 @@ type UnitResult struct {
 	Charm           string                 `json:"charm"`
 	Leader          bool                   `json:"leader,omitempty"`
 	RelationData    []EndpointRelationData `json:"relation-data,omitempty"`
+	HookTraces      []HookTrace            `json:"hook-traces,omitempty"`
 
 	// The following are for CAAS models.
 	ProviderId string `json:"provider-id,omitempty"`
 	Address    string `json:"address,omitempty"`
 }
 
+// HookTrace holds where the time went when a unit ran a hook, action
+// or command.
+type HookTrace struct {
+	Operation        string          `json:"operation"`
+	Started          time.Time       `json:"started"`
+	LockWait         time.Duration   `json:"lock-wait"`
+	Duration         time.Duration   `json:"duration"`
+	Flush            time.Duration   `json:"flush"`
+	ToolCalls        []HookToolTrace `json:"tool-calls,omitempty"`
+	DroppedToolCalls int             `json:"dropped-tool-calls,omitempty"`
+	Error            string          `json:"error,omitempty"`
+}
+
+// HookToolTrace holds the timing of a hook tool run by a hook.
+type HookToolTrace struct {
+	Name     string        `json:"name"`
+	Args     string        `json:"args,omitempty"`
+	Started  time.Time     `json:"started"`
+	Duration time.Duration `json:"duration"`
+	Failed   bool          `json:"failed,omitempty"`
+}
+
 // UnitInfoResults holds an unit info result or a retrieval error.
 type UnitInfoResult struct {
 	Result *UnitResult `json:"result,omitempty"`
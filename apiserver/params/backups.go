@@ -41,6 +41,16 @@ type BackupsRemoveArgs struct {
 	IDs []string `json:"ids"`
 }
 
+// BackupsRestoreArgs holds the args for the API Restore method.
+type BackupsRestoreArgs struct {
+	ID string `json:"id"`
+}
+
+// BackupsContentsArgs holds the args for the API Contents method.
+type BackupsContentsArgs struct {
+	ID string `json:"id"`
+}
+
 // BackupsListResult holds the list of all stored backups.
 type BackupsListResult struct {
 	List []BackupsMetadataResult `json:"list"`
@@ -89,3 +99,38 @@ type BackupsMetadataResult struct {
 	// HANodes reflects HA configuration: number of controller nodes in HA.
 	HANodes int64 `json:"ha-nodes"`
 }
+
+// BackupsContentsResult describes what a backup archive holds.
+type BackupsContentsResult struct {
+	ControllerUUID string                      `json:"controller-uuid"`
+	JujuVersion    version.Number              `json:"juju-version"`
+	Models         []BackupsModelContents      `json:"models"`
+	Collections    []BackupsCollectionContents `json:"collections"`
+}
+
+// BackupsModelContents describes a model held in a backup archive.
+type BackupsModelContents struct {
+	UUID         string                       `json:"uuid"`
+	Name         string                       `json:"name"`
+	Owner        string                       `json:"owner"`
+	Type         string                       `json:"type"`
+	Machines     int                          `json:"machines"`
+	Applications []BackupsApplicationContents `json:"applications"`
+}
+
+// BackupsApplicationContents describes an application held in a
+// backup archive.
+type BackupsApplicationContents struct {
+	Name     string `json:"name"`
+	CharmURL string `json:"charm-url"`
+	Units    int    `json:"units"`
+}
+
+// BackupsCollectionContents describes a database collection held in a
+// backup archive.
+type BackupsCollectionContents struct {
+	Database  string `json:"database"`
+	Name      string `json:"name"`
+	Documents int    `json:"documents"`
+	Size      int64  `json:"size"`
+}
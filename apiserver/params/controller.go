@@ -3,7 +3,11 @@
 
 package params
 
-import "github.com/juju/juju/core/life"
+import (
+	"time"
+
+	"github.com/juju/juju/core/life"
+)
 
 // DestroyControllerArgs holds the arguments for destroying a controller.
 type DestroyControllerArgs struct {
@@ -115,3 +119,80 @@ type ControllerVersionResults struct {
 	Version   string `json:"version"`
 	GitCommit string `json:"git-commit"`
 }
+
+// AuditLogQueryArgs holds the filters for a Controller.AuditLog call.
+// Only requests matching all the set filters are returned.
+type AuditLogQueryArgs struct {
+	// Users, if set, only matches requests made by these users.
+	Users []string `json:"users,omitempty"`
+
+	// Models, if set, only matches requests made on these models,
+	// given by name or UUID.
+	Models []string `json:"models,omitempty"`
+
+	// Methods, if set, only matches these API calls, given as either
+	// a facade name or Facade.Method.
+	Methods []string `json:"methods,omitempty"`
+
+	// From, if set, only matches requests made at or after this time.
+	From *time.Time `json:"from,omitempty"`
+
+	// To, if set, only matches requests made before this time.
+	To *time.Time `json:"to,omitempty"`
+
+	// Limit is the maximum number of entries to return; the most
+	// recent are returned. If zero, the controller's maximum is used.
+	Limit int `json:"limit,omitempty"`
+}
+
+// AuditLogEntry is a single API request recorded in a controller's
+// audit log.
+type AuditLogEntry struct {
+	ControllerID   string          `json:"controller-id"`
+	Who            string          `json:"who"`
+	What           string          `json:"what"`
+	ModelName      string          `json:"model-name"`
+	ModelUUID      string          `json:"model-uuid"`
+	ConversationID string          `json:"conversation-id"`
+	ConnectionID   string          `json:"connection-id"`
+	RequestID      uint64          `json:"request-id"`
+	When           time.Time       `json:"when"`
+	Facade         string          `json:"facade"`
+	Method         string          `json:"method"`
+	Version        int             `json:"version"`
+	Args           string          `json:"args,omitempty"`
+	Errors         []AuditLogError `json:"errors,omitempty"`
+}
+
+// AuditLogError is an error returned in response to an audited
+// request.
+type AuditLogError struct {
+	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
+}
+
+// AuditLogResult holds the entries returned by Controller.AuditLog,
+// in the order they were recorded.
+type AuditLogResult struct {
+	Entries []AuditLogEntry `json:"entries"`
+
+	// Missing maps the IDs of any controller nodes whose entries
+	// couldn't be retrieved to the reason why.
+	Missing map[string]string `json:"missing,omitempty"`
+}
+
+// ScheduledBackupStatus holds the outcome of the controller's scheduled
+// backups, as returned by Controller.ScheduledBackupStatus. Times are
+// omitted if the event hasn't happened.
+type ScheduledBackupStatus struct {
+	// Schedule is the backup-schedule controller config value; no
+	// backups are scheduled if it is empty.
+	Schedule string `json:"schedule,omitempty"`
+
+	NextRun      *time.Time `json:"next-run,omitempty"`
+	LastAttempt  *time.Time `json:"last-attempt,omitempty"`
+	LastSuccess  *time.Time `json:"last-success,omitempty"`
+	LastBackupID string     `json:"last-backup-id,omitempty"`
+	LastFailure  *time.Time `json:"last-failure,omitempty"`
+	LastError    string     `json:"last-error,omitempty"`
+}
@@ -264,6 +264,8 @@ type UnitStateResult struct {
 	StorageState string `json:"storage-state,omitempty"`
 	// MeterStatusState encodes the meter status state for this unit.
 	MeterStatusState string `json:"meter-status-state,omitempty"`
+	// HookTraces encodes the recent hook traces for this unit.
+	HookTraces string `json:"hook-traces,omitempty"`
 }
 
 // UnitStateResults holds multiple unit state maps or errors.
@@ -291,6 +293,7 @@ type SetUnitStateArg struct {
 	RelationState    *map[int]string    `json:"relation-state,omitempty"`
 	StorageState     *string            `json:"storage-state,omitempty"`
 	MeterStatusState *string            `json:"meter-status-state,omitempty"`
+	HookTraces       *string            `json:"hook-traces,omitempty"`
 }
 
 // CommitHookChangesArgs serves as a container for CommitHookChangesArg objects
@@ -757,6 +760,14 @@ type RunParams struct {
 	// WorkloadContext for CAAS is true when the Commands should be run on
 	// the workload not the operator.
 	WorkloadContext bool `json:"workload-context,omitempty"`
+
+	// Rollout, if set, runs the commands on the targets in
+	// batches rather than all at once.
+	Rollout *RolloutParams `json:"rollout,omitempty"`
+
+	// Schedule, if set, runs the commands at a later time, and
+	// possibly again on a recurring basis, rather than now.
+	Schedule *ScheduleParams `json:"schedule,omitempty"`
 }
 
 // RunResult contains the result from an individual run call on a machine.
@@ -981,6 +992,26 @@ type UnitRefreshResults struct {
 	Results []UnitRefreshResult
 }
 
+// HookLimits holds the limits placed on the hooks and actions run by
+// a unit.
+type HookLimits struct {
+	HookTimeout   time.Duration `json:"hook-timeout,omitempty"`
+	ActionTimeout time.Duration `json:"action-timeout,omitempty"`
+	CPUQuota      int           `json:"cpu-quota,omitempty"`
+	MemoryLimit   uint64        `json:"memory-limit,omitempty"`
+}
+
+// HookLimitsResult holds the hook limits of a unit or an error.
+type HookLimitsResult struct {
+	Result HookLimits `json:"result"`
+	Error  *Error     `json:"error,omitempty"`
+}
+
+// HookLimitsResults holds the results of a HookLimits API call.
+type HookLimitsResults struct {
+	Results []HookLimitsResult `json:"results"`
+}
+
 // EntityString holds an entity tag and a string value.
 type EntityString struct {
 	Tag   string `json:"tag"`
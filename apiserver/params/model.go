@@ -466,3 +466,164 @@ type ValidateModelUpgradeParam struct {
 	// ModelTag is a tag for the model that you want to upgrade.
 	ModelTag string `json:"model-tag"`
 }
+
+// Quotas holds the quota limits set for a model, or for all the models
+// owned by a user, and the amount of each resource used.
+type Quotas struct {
+	// Limits holds the most of each resource allowed. Resources
+	// without a limit are omitted.
+	Limits map[string]uint64 `json:"limits,omitempty"`
+
+	// Usage holds the amount of each resource used.
+	Usage map[string]uint64 `json:"usage"`
+}
+
+// QuotasResult holds the quotas of a model or user, or an error.
+type QuotasResult struct {
+	Result *Quotas `json:"result,omitempty"`
+	Error  *Error  `json:"error,omitempty"`
+}
+
+// QuotasResults holds the results of a ModelQuotas.Quotas call.
+type QuotasResults struct {
+	Results []QuotasResult `json:"results"`
+}
+
+// SetQuotasArg holds the quota limits to set for a model or user.
+type SetQuotasArg struct {
+	// Tag identifies the model or user.
+	Tag string `json:"tag"`
+
+	// Limits holds the limits to set, keyed by resource.
+	Limits map[string]uint64 `json:"limits,omitempty"`
+
+	// Reset holds the resources whose limits are removed.
+	Reset []string `json:"reset,omitempty"`
+}
+
+// SetQuotasArgs holds the arguments for a ModelQuotas.SetQuotas call.
+type SetQuotasArgs struct {
+	Args []SetQuotasArg `json:"args"`
+}
+
+// Webhook describes a webhook registered to be notified of events in a
+// model. The secret used to sign the payloads is never returned.
+type Webhook struct {
+	Id        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events,omitempty"`
+	Signed    bool      `json:"signed"`
+	Template  string    `json:"template,omitempty"`
+	CreatedBy string    `json:"created-by"`
+	Created   time.Time `json:"created"`
+}
+
+// WebhooksResult holds the webhooks registered in a model.
+type WebhooksResult struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+// AddWebhookArg holds a webhook to register in a model.
+type AddWebhookArg struct {
+	URL string `json:"url"`
+
+	// Events holds the events the webhook is notified of; all of them
+	// if empty.
+	Events []string `json:"events,omitempty"`
+
+	// Secret, if set, is used to sign the payloads.
+	Secret string `json:"secret,omitempty"`
+
+	// Template, if set, is used to render the payloads.
+	Template string `json:"template,omitempty"`
+}
+
+// AddWebhooksArgs holds the arguments for a Webhooks.AddWebhooks call.
+type AddWebhooksArgs struct {
+	Webhooks []AddWebhookArg `json:"webhooks"`
+}
+
+// AddWebhookResult holds the id of an added webhook, or an error.
+type AddWebhookResult struct {
+	Id    string `json:"id,omitempty"`
+	Error *Error `json:"error,omitempty"`
+}
+
+// AddWebhookResults holds the results of a Webhooks.AddWebhooks call.
+type AddWebhookResults struct {
+	Results []AddWebhookResult `json:"results"`
+}
+
+// RemoveWebhooksArgs holds the arguments for a Webhooks.RemoveWebhooks
+// call.
+type RemoveWebhooksArgs struct {
+	Ids []string `json:"ids"`
+}
+
+// ConfigRevision holds a recorded revision of the charm config of an
+// application, or of the model config.
+type ConfigRevision struct {
+	Revision int                    `json:"revision"`
+	Config   map[string]interface{} `json:"config"`
+	Author   string                 `json:"author,omitempty"`
+	Created  time.Time              `json:"created"`
+}
+
+// ConfigRevisionsResult holds the config revisions of an entity, oldest
+// first, or an error.
+type ConfigRevisionsResult struct {
+	Revisions []ConfigRevision `json:"revisions,omitempty"`
+	Error     *Error           `json:"error,omitempty"`
+}
+
+// ConfigRevisionsResults holds the results of a
+// ConfigHistory.ConfigRevisions call.
+type ConfigRevisionsResults struct {
+	Results []ConfigRevisionsResult `json:"results"`
+}
+
+// RollbackConfigArg holds the entity whose config is rolled back, and
+// the revision it is rolled back to.
+type RollbackConfigArg struct {
+	Tag      string `json:"tag"`
+	Revision int    `json:"revision"`
+}
+
+// RollbackConfigArgs holds the arguments for a
+// ConfigHistory.RollbackConfig call.
+type RollbackConfigArgs struct {
+	Args []RollbackConfigArg `json:"args"`
+}
+
+// SetDesiredBundleArgs holds the arguments for a
+// BundleDrift.SetDesiredBundle call.
+type SetDesiredBundleArgs struct {
+	// Bundle holds the YAML of the bundle the model is expected to
+	// match.
+	Bundle string `json:"bundle"`
+
+	// Overlays holds the YAML of the overlays applied to the bundle,
+	// in order.
+	Overlays []string `json:"overlays,omitempty"`
+}
+
+// BundleDriftResult holds the desired bundle of a model, and the drift
+// of the model from it when it was last checked.
+type BundleDriftResult struct {
+	Bundle   string    `json:"bundle"`
+	Overlays []string  `json:"overlays,omitempty"`
+	SetBy    string    `json:"set-by"`
+	Set      time.Time `json:"set"`
+
+	// Checked is when the model was last compared with the bundle; it
+	// is nil if it hasn't been since the bundle was set.
+	Checked *time.Time `json:"checked,omitempty"`
+
+	// Diff holds the differences found, in the YAML format of
+	// diff-bundle.
+	Diff string `json:"diff,omitempty"`
+
+	// CheckError holds why the model couldn't be compared with the
+	// bundle, if it couldn't.
+	CheckError string `json:"check-error,omitempty"`
+}
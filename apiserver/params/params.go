@@ -698,6 +698,12 @@ type StateServingInfo struct {
 	SystemIdentity string `json:"system-identity"`
 }
 
+// SecretKeysResult holds the keys, by name, that a controller's secret
+// values are encrypted with.
+type SecretKeysResult struct {
+	Keys map[string][]byte `json:"keys"`
+}
+
 // IsMasterResult holds the result of an IsMaster API call.
 type IsMasterResult struct {
 	// Master reports whether the connected agent
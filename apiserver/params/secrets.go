@@ -0,0 +1,89 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// CreateSecretArgs holds the args for creating secrets.
+type CreateSecretArgs struct {
+	Args []CreateSecretArg `json:"args"`
+}
+
+// CreateSecretArg holds the args for creating a secret.
+type CreateSecretArg struct {
+	// OwnerTag is the application or unit that owns the secret.
+	OwnerTag string `json:"owner-tag"`
+
+	Description  string     `json:"description,omitempty"`
+	RotatePolicy string     `json:"rotate-policy,omitempty"`
+	ExpireTime   *time.Time `json:"expire-time,omitempty"`
+
+	// Data is the value of the secret.
+	Data map[string]string `json:"data"`
+}
+
+// UpdateSecretArgs holds the args for updating secrets.
+type UpdateSecretArgs struct {
+	Args []UpdateSecretArg `json:"args"`
+}
+
+// UpdateSecretArg holds the args for updating a secret; nil
+// attributes are left unchanged.
+type UpdateSecretArg struct {
+	URI string `json:"uri"`
+
+	Description  *string    `json:"description,omitempty"`
+	RotatePolicy *string    `json:"rotate-policy,omitempty"`
+	ExpireTime   *time.Time `json:"expire-time,omitempty"`
+
+	// Data, if set, is the value of a new revision of the secret.
+	Data map[string]string `json:"data,omitempty"`
+}
+
+// GetSecretValueArgs holds the args for getting the values of secrets.
+type GetSecretValueArgs struct {
+	Args []GetSecretValueArg `json:"args"`
+}
+
+// GetSecretValueArg holds the args for getting the value of a secret.
+type GetSecretValueArg struct {
+	URI string `json:"uri"`
+
+	// Peek gets the latest revision of the secret, without the unit
+	// then using it.
+	Peek bool `json:"peek,omitempty"`
+
+	// Refresh gets the latest revision of the secret, which the unit
+	// then uses.
+	Refresh bool `json:"refresh,omitempty"`
+}
+
+// SecretValueResults holds the values of secrets.
+type SecretValueResults struct {
+	Results []SecretValueResult `json:"results"`
+}
+
+// SecretValueResult holds the value of a revision of a secret, or an
+// error.
+type SecretValueResult struct {
+	Revision int               `json:"revision,omitempty"`
+	Data     map[string]string `json:"data,omitempty"`
+	Error    *Error            `json:"error,omitempty"`
+}
+
+// GrantRevokeSecretArgs holds the args for granting or revoking access
+// to secrets.
+type GrantRevokeSecretArgs struct {
+	Args []GrantRevokeSecretArg `json:"args"`
+}
+
+// GrantRevokeSecretArg holds the args for granting or revoking access
+// to a secret.
+type GrantRevokeSecretArg struct {
+	URI string `json:"uri"`
+
+	// SubjectTags are the applications and units to grant or revoke
+	// access to the secret.
+	SubjectTags []string `json:"subject-tags"`
+}
@@ -299,6 +299,44 @@ type StatusHistoryResults struct {
 	Results []StatusHistoryResult `json:"results"`
 }
 
+// ModelStatusHistoryArgs holds the parameters used to query the changes
+// in status of all of the units, machines and applications in a model.
+type ModelStatusHistoryArgs struct {
+	// From and To bound the time window the changes happened in.
+	// From is inclusive and To exclusive.
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+
+	// Kinds restricts the changes to the given kinds of status.
+	Kinds []string `json:"kinds,omitempty"`
+
+	// Statuses restricts the changes to those into the given
+	// status values.
+	Statuses []string `json:"statuses,omitempty"`
+
+	// These attributes are used to support client side
+	// batching of results.
+	Offset *int `json:"offset,omitempty"`
+	Limit  *int `json:"limit,omitempty"`
+}
+
+// StatusTransition describes a change in status of an entity.
+type StatusTransition struct {
+	Tag    string                 `json:"tag"`
+	Kind   string                 `json:"kind"`
+	Status string                 `json:"status"`
+	Info   string                 `json:"info"`
+	Data   map[string]interface{} `json:"data,omitempty"`
+	Since  *time.Time             `json:"since"`
+}
+
+// ModelStatusHistoryResult holds the changes in status of the entities
+// in a model, oldest first.
+type ModelStatusHistoryResult struct {
+	Transitions []StatusTransition `json:"transitions"`
+	Truncated   bool               `json:"truncated,omitempty"`
+}
+
 // StatusHistoryPruneArgs holds arguments for status history
 // prunning process.
 type StatusHistoryPruneArgs struct {
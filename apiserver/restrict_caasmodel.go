@@ -15,6 +15,7 @@ import (
 var commonModelFacadeNames = set.NewStrings(
 	"Action",
 	"ActionPruner",
+	"ActionSchedules",
 	"AllWatcher",
 	"Agent",
 	"Annotations",
@@ -55,6 +56,7 @@ var commonModelFacadeNames = set.NewStrings(
 	"RemoteRelations",
 	"Resumer",
 	"RetryStrategy",
+	"Rollouts",
 	"Singular",
 	"StatusHistory",
 	"Storage",
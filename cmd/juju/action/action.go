@@ -23,6 +23,10 @@ type APIClient interface {
 	// timeout.
 	RunOnAllMachines(commands string, timeout time.Duration) (params.EnqueuedActions, error)
 
+	// RunOnAllMachinesWithParams runs the commands on all the machines,
+	// using the timeout, rollout and schedule in the params.
+	RunOnAllMachinesWithParams(params.RunParams) (params.EnqueuedActions, error)
+
 	// Run the Commands specified on the machines identified through the ids
 	// provided in the machines, applications and units slices.
 	Run(params.RunParams) (params.EnqueuedActions, error)
@@ -49,6 +53,14 @@ type APIClient interface {
 	// Operation fetches the operation with the specified id.
 	Operation(id string) (params.OperationResult, error)
 
+	// ExportOperations fetches the operations which completed in a
+	// time window, with all of the details of their tasks.
+	ExportOperations(params.OperationExportArgs) (params.OperationResults, error)
+
+	// ResumeOperation resumes the halted rolling operation with the
+	// specified id.
+	ResumeOperation(id string) error
+
 	// WatchActionProgress reports on logged action progress messages.
 	WatchActionProgress(actionId string) (watcher.StringsWatcher, error)
 }
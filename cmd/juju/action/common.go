@@ -52,6 +52,8 @@ type runCommandBase struct {
 	clock       clock.Clock
 	wait        time.Duration
 	defaultWait time.Duration
+	rollout     rolloutFlags
+	schedule    scheduleFlags
 
 	logMessageHandler func(*cmd.Context, string)
 }
@@ -68,12 +70,27 @@ func (c *runCommandBase) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.background, "background", false, "Run the task in the background")
 	f.DurationVar(&c.wait, "wait", 0, "Maximum wait time for a task to complete")
 	f.BoolVar(&c.utc, "utc", false, "Show times in UTC")
+	c.rollout.SetFlags(f)
+	c.schedule.SetFlags(f)
 }
 
 func (c *runCommandBase) Init(args []string) error {
 	if c.background && c.wait > 0 {
 		return errors.New("cannot specify both --wait and --background")
 	}
+	if err := c.rollout.validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := c.schedule.validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if c.schedule.params() != nil {
+		if c.rollout.params() != nil {
+			return errors.New("cannot schedule a rolling operation")
+		}
+		// Scheduled operations are never waited for.
+		c.background = true
+	}
 	if !c.background && c.wait == 0 {
 		c.wait = c.defaultWait
 		if c.wait == 0 {
@@ -92,6 +109,12 @@ func (c *runCommandBase) ensureAPI() (err error) {
 }
 
 func (c *runCommandBase) processOperationResults(ctx *cmd.Context, results *params.EnqueuedActions) error {
+	if c.schedule.params() != nil {
+		return c.processScheduledResults(ctx, results)
+	}
+	if c.rollout.params() != nil {
+		return c.processRolloutResults(ctx, results)
+	}
 	tasks := make([]enqueuedAction, len(results.Actions))
 	for i, a := range results.Actions {
 		if a.Error != nil {
@@ -99,11 +99,29 @@ targets.
 Since juju exec creates tasks, you can query for the status of commands
 started with juju run by calling "juju operations --machines <id>,... --actions juju-run".
 
+To run the command on a large number of targets without running it everywhere
+at once, use --batch-size to release the tasks in batches, --max-parallel to
+limit how many tasks run at the same time, and --batch-wait to pause between
+batches. With --max-failures, the operation is halted once that many tasks
+have failed; a halted operation can be continued with "juju resume-operation".
+The progress of each batch is reported by "juju show-operation".
+
+To run the command later, use --at with a time in RFC3339 format. To run it
+on a recurring basis, use --schedule with a cron specification, such as
+"0 2 * * *", or a descriptor, such as @daily; schedules are interpreted in
+UTC. Both may be given to start a recurring schedule at a given time.
+Targets given with --application or the <application>/leader syntax are
+resolved as each run is scheduled. Each run is listed by
+"juju operations" as soon as it is scheduled, and the schedule is cancelled
+by cancelling all the tasks of the next run with "juju cancel-task".
+
 If you need to pass options to the command being run, you must precede the
 command and its arguments with "--", to tell "juju exec" to stop processing
 those arguments. For example:
 
     juju exec --all -- hostname -f
+    juju exec --application mysql --batch-size 2 --max-failures 1 -- sudo apt-get -y upgrade
+    juju exec --application mysql --schedule "0 2 * * *" -- sudo apt-get -y upgrade
 
 `
 
@@ -214,7 +232,17 @@ func (c *execCommand) Run(ctx *cmd.Context) error {
 
 	var runResults params.EnqueuedActions
 	if c.all {
-		runResults, err = c.api.RunOnAllMachines(c.commands, c.wait)
+		rollout, schedule := c.rollout.params(), c.schedule.params()
+		if rollout != nil || schedule != nil {
+			runResults, err = c.api.RunOnAllMachinesWithParams(params.RunParams{
+				Commands: c.commands,
+				Timeout:  c.wait,
+				Rollout:  rollout,
+				Schedule: schedule,
+			})
+		} else {
+			runResults, err = c.api.RunOnAllMachines(c.commands, c.wait)
+		}
 	} else {
 		runParams := params.RunParams{
 			Commands:     c.commands,
@@ -222,6 +250,8 @@ func (c *execCommand) Run(ctx *cmd.Context) error {
 			Machines:     c.machines,
 			Applications: c.applications,
 			Units:        c.units,
+			Rollout:      c.rollout.params(),
+			Schedule:     c.schedule.params(),
 		}
 		if c.operator {
 			if modelType != model.CAAS {
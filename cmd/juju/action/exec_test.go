@@ -654,3 +654,213 @@ Waiting for task 1...
 		c.Check(cmdtesting.Stderr(context), gc.Equals, test.stderr)
 	}
 }
+
+func (*ExecSuite) TestRolloutArgParsing(c *gc.C) {
+	for i, test := range []struct {
+		message  string
+		args     []string
+		errMatch string
+	}{{
+		message:  "negative batch size",
+		args:     []string{"--batch-size=-1", "--all", "sudo reboot"},
+		errMatch: "--batch-size cannot be negative",
+	}, {
+		message:  "negative max parallel",
+		args:     []string{"--max-parallel=-1", "--all", "sudo reboot"},
+		errMatch: "--max-parallel cannot be negative",
+	}, {
+		message:  "negative batch wait",
+		args:     []string{"--batch-wait=-1s", "--all", "sudo reboot"},
+		errMatch: "--batch-wait cannot be negative",
+	}, {
+		message:  "negative max failures",
+		args:     []string{"--max-failures=-1", "--all", "sudo reboot"},
+		errMatch: "--max-failures cannot be negative",
+	}, {
+		message: "all rollout flags",
+		args:    []string{"--batch-size=2", "--max-parallel=1", "--batch-wait=1m", "--max-failures=1", "--all", "sudo reboot"},
+	}} {
+		c.Log(fmt.Sprintf("%v: %s", i, test.message))
+		runCmd, _ := newTestExecCommand(testClock(), model.IAAS)
+		cmdtesting.TestInit(c, runCmd, test.args, test.errMatch)
+	}
+}
+
+func (*ExecSuite) TestScheduleArgParsing(c *gc.C) {
+	for i, test := range []struct {
+		message  string
+		args     []string
+		errMatch string
+	}{{
+		message:  "invalid time",
+		args:     []string{"--at=tomorrow", "--all", "sudo reboot"},
+		errMatch: `invalid --at time "tomorrow": expected RFC3339 format, e.g. 2021-06-01T02:00:00Z`,
+	}, {
+		message:  "invalid schedule",
+		args:     []string{"--schedule=every day", "--all", "sudo reboot"},
+		errMatch: `invalid --schedule "every day": .*`,
+	}, {
+		message:  "rolling schedule",
+		args:     []string{"--schedule=@daily", "--batch-size=1", "--all", "sudo reboot"},
+		errMatch: "cannot schedule a rolling operation",
+	}, {
+		message: "time and schedule",
+		args:    []string{"--at=2021-06-01T02:00:00Z", "--schedule=0 2 * * *", "--all", "sudo reboot"},
+	}} {
+		c.Log(fmt.Sprintf("%v: %s", i, test.message))
+		runCmd, _ := newTestExecCommand(testClock(), model.IAAS)
+		cmdtesting.TestInit(c, runCmd, test.args, test.errMatch)
+	}
+}
+
+func (s *ExecSuite) TestScheduledAllMachines(c *gc.C) {
+	fakeClient := s.rollingClient()
+	fakeClient.actionResults[0].Action.Tag = validActionTagString
+	fakeClient.actionResults[1].Action.Tag = validActionTagString2
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	runCmd, _ := newTestExecCommand(testClock(), model.IAAS)
+	context, err := cmdtesting.RunCommand(c, runCmd,
+		"--all", "--at=2021-06-01T02:00:00Z", "--utc", "hostname")
+	c.Assert(err, jc.ErrorIsNil)
+	at := time.Date(2021, 6, 1, 2, 0, 0, 0, time.UTC)
+	c.Check(fakeClient.scheduleParams, jc.DeepEquals, &params.ScheduleParams{At: &at})
+	c.Check(cmdtesting.Stdout(context), gc.Equals, `
+"0":
+  id: "1"
+"1":
+  id: "2"
+`[1:])
+	c.Check(cmdtesting.Stderr(context), gc.Equals, `
+Scheduled operation 1 with 2 tasks to run at 2021-06-01 02:00:00 +0000 UTC
+Check operation status with 'juju show-operation 1'
+Cancel the schedule by cancelling its tasks with 'juju cancel-task <id> ...'
+`[1:])
+}
+
+func (s *ExecSuite) TestScheduledApplication(c *gc.C) {
+	fakeClient := s.rollingClient()
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	runCmd, _ := newTestExecCommand(testClock(), model.IAAS)
+	context, err := cmdtesting.RunCommand(c, runCmd,
+		"--application=mysql", "--schedule=@daily", "hostname")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fakeClient.execParams.Schedule, jc.DeepEquals, &params.ScheduleParams{Recurrence: "@daily"})
+	c.Check(fakeClient.execParams.Applications, jc.DeepEquals, []string{"mysql"})
+	c.Check(cmdtesting.Stderr(context), gc.Matches, `(?s)Scheduled operation 1 with .* to run at .*
+The tasks will be run again on the schedule "@daily"
+.*`)
+}
+
+func (s *ExecSuite) rollingClient() *fakeAPIClient {
+	fakeClient := &fakeAPIClient{}
+	fakeClient.actionResults = []params.ActionResult{{
+		Action: &params.Action{Receiver: "machine-0", Name: "juju-exec"},
+	}, {
+		Action: &params.Action{Receiver: "machine-1", Name: "juju-exec"},
+	}}
+	fakeClient.machines = set.NewStrings("0", "1")
+	return fakeClient
+}
+
+func (s *ExecSuite) TestAllMachinesRolling(c *gc.C) {
+	fakeClient := s.rollingClient()
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	fakeClient.operationResults = []params.OperationResult{{
+		OperationTag: "operation-1",
+		Status:       "completed",
+		Actions: []params.ActionResult{{
+			Action: &params.Action{
+				Tag:      validActionTagString,
+				Receiver: "machine-0",
+			},
+			Output: map[string]interface{}{
+				"stdout": "megatron",
+			},
+			Status: "completed",
+		}, {
+			Action: &params.Action{
+				Tag:      validActionTagString2,
+				Receiver: "machine-1",
+			},
+			Output: map[string]interface{}{
+				"stdout": "bumblebee",
+			},
+			Status: "completed",
+		}},
+		Rollout: &params.RolloutInfo{
+			RolloutParams: params.RolloutParams{BatchSize: 1},
+			Batches:       []params.RolloutBatch{{Completed: 1}, {Completed: 1}},
+		},
+	}}
+
+	runCmd, _ := newTestExecCommand(testClock(), model.IAAS)
+	context, err := cmdtesting.RunCommand(c, runCmd,
+		"--format=yaml", "--all", "--batch-size=1", "hostname")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fakeClient.rolloutParams, jc.DeepEquals, &params.RolloutParams{BatchSize: 1})
+
+	c.Check(cmdtesting.Stdout(context), gc.Equals, `
+"0":
+  id: "1"
+  machine: "0"
+  results:
+    stdout: megatron
+  status: completed
+"1":
+  id: "2"
+  machine: "1"
+  results:
+    stdout: bumblebee
+  status: completed
+`[1:])
+	c.Check(cmdtesting.Stderr(context), gc.Equals, `
+Running rolling operation 1 with 2 tasks
+  - batch 1/2: 1 completed, 0 failed, 0 running, 0 pending
+  - batch 2/2: 1 completed, 0 failed, 0 running, 0 pending
+
+`[1:])
+}
+
+func (s *ExecSuite) TestRollingBackground(c *gc.C) {
+	fakeClient := s.rollingClient()
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	runCmd, _ := newTestExecCommand(testClock(), model.IAAS)
+	context, err := cmdtesting.RunCommand(c, runCmd,
+		"--machine=0,1", "--max-parallel=1", "--background", "hostname")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fakeClient.execParams.Rollout, jc.DeepEquals, &params.RolloutParams{MaxParallel: 1})
+	c.Check(cmdtesting.Stderr(context), gc.Equals, `
+Scheduled rolling operation 1 with 2 tasks
+Check operation status with 'juju show-operation 1'
+`[1:])
+}
+
+func (s *ExecSuite) TestRollingHalted(c *gc.C) {
+	fakeClient := s.rollingClient()
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	fakeClient.operationResults = []params.OperationResult{{
+		OperationTag: "operation-1",
+		Status:       "running",
+		Rollout: &params.RolloutInfo{
+			RolloutParams: params.RolloutParams{BatchSize: 1, MaxFailures: 1},
+			Halted:        "1 of 2 tasks failed",
+			Batches:       []params.RolloutBatch{{Failed: 1}, {Pending: 1}},
+		},
+	}}
+
+	runCmd, _ := newTestExecCommand(testClock(), model.IAAS)
+	_, err := cmdtesting.RunCommand(c, runCmd,
+		"--all", "--batch-size=1", "--max-failures=1", "hostname")
+	c.Assert(err, gc.ErrorMatches, `operation 1 halted: 1 of 2 tasks failed
+Resume it with 'juju resume-operation 1'`)
+}
@@ -129,6 +129,18 @@ func NewShowOperationCommandForTest(store jujuclient.ClientStore, clock clock.Cl
 	return modelcmd.Wrap(c, modelcmd.WrapSkipDefaultModel), &ShowOperationCommand{c}
 }
 
+func NewResumeOperationCommandForTest(store jujuclient.ClientStore) cmd.Command {
+	c := &resumeOperationCommand{}
+	c.SetClientStore(store)
+	return modelcmd.Wrap(c, modelcmd.WrapSkipDefaultModel)
+}
+
+func NewExportOperationsCommandForTest(store jujuclient.ClientStore) cmd.Command {
+	c := &exportOperationsCommand{}
+	c.SetClientStore(store)
+	return modelcmd.Wrap(c, modelcmd.WrapSkipDefaultModel)
+}
+
 func NewCancelCommandForTest(store jujuclient.ClientStore) (cmd.Command, *CancelCommand) {
 	c := &cancelCommand{}
 	c.SetClientStore(store)
@@ -0,0 +1,179 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+// exportBatchSize is the number of operations fetched from the
+// controller at a time.
+const exportBatchSize = 100
+
+func NewExportOperationsCommand() cmd.Command {
+	return modelcmd.Wrap(&exportOperationsCommand{})
+}
+
+// exportOperationsCommand writes out the operations which completed in
+// a time window, with all of the details of their tasks.
+type exportOperationsCommand struct {
+	ActionCommandBase
+	from            string
+	to              string
+	includeArchived bool
+	filename        string
+
+	fromTime time.Time
+	toTime   time.Time
+}
+
+const exportOperationsDoc = `
+Export the operations which completed in a time window, with their tasks'
+parameters, output and logs.
+
+The operations are written as JSON Lines, one operation per line, oldest
+first. Each line has the same form as the JSON output of
+'juju show-operation', with the full details of every task.
+
+Operations are pruned from the model according to the model's
+max-action-results-age and max-action-results-size settings, which the
+action-results-max-age application setting overrides for tasks run on an
+application's units. If the model's action-results-archive setting is
+"storage", pruned operations are archived in the controller and can be
+exported with --include-archived.
+
+Examples:
+
+    juju export-operations
+    juju export-operations --from 2021-06-01T00:00:00Z --to 2021-07-01T00:00:00Z
+    juju export-operations --include-archived --filename operations.jsonl
+
+See also:
+    operations
+    show-operation
+`
+
+// SetFlags implements Command.
+func (c *exportOperationsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ActionCommandBase.SetFlags(f)
+	f.StringVar(&c.from, "from", "", "Export the operations completed at or after this time (RFC3339)")
+	f.StringVar(&c.to, "to", "", "Export the operations completed before this time (RFC3339)")
+	f.BoolVar(&c.includeArchived, "include-archived", false, "Include the operations archived in the controller")
+	f.StringVar(&c.filename, "filename", "", "Write the operations to this file")
+}
+
+// Info implements Command.
+func (c *exportOperationsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "export-operations",
+		Purpose: "Export completed operations as JSON Lines.",
+		Doc:     exportOperationsDoc,
+	})
+}
+
+// Init implements Command.
+func (c *exportOperationsCommand) Init(args []string) error {
+	var err error
+	if c.from != "" {
+		if c.fromTime, err = time.Parse(time.RFC3339, c.from); err != nil {
+			return errors.Errorf("invalid --from time %q: expected RFC3339 format, e.g. 2021-06-01T02:00:00Z", c.from)
+		}
+	}
+	if c.to != "" {
+		if c.toTime, err = time.Parse(time.RFC3339, c.to); err != nil {
+			return errors.Errorf("invalid --to time %q: expected RFC3339 format, e.g. 2021-06-01T02:00:00Z", c.to)
+		}
+	}
+	if !c.fromTime.IsZero() && !c.toTime.IsZero() && !c.fromTime.Before(c.toTime) {
+		return errors.New("--from time must be before --to time")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.
+func (c *exportOperationsCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	var out io.Writer = ctx.Stdout
+	if c.filename != "" {
+		f, err := os.Create(ctx.AbsPath(c.filename))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer f.Close()
+		out = f
+	}
+	enc := json.NewEncoder(out)
+
+	var count int
+	if c.includeArchived {
+		// Archived operations were pruned, so they are older than
+		// those still in the model.
+		n, err := c.export(api, enc, true)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		count += n
+	}
+	n, err := c.export(api, enc, false)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	count += n
+
+	if c.filename != "" {
+		ctx.Infof("Exported %d operation(s) to %s", count, c.filename)
+	} else if count == 0 {
+		ctx.Infof("no matching operations")
+	}
+	return nil
+}
+
+// export writes out all of the operations in the window, fetching
+// them in batches, and returns how many there were.
+func (c *exportOperationsCommand) export(api APIClient, enc *json.Encoder, archived bool) (int, error) {
+	args := params.OperationExportArgs{Archived: archived}
+	if !c.fromTime.IsZero() {
+		args.From = &c.fromTime
+	}
+	if !c.toTime.IsZero() {
+		args.To = &c.toTime
+	}
+	limit := exportBatchSize
+	args.Limit = &limit
+
+	var count int
+	for {
+		offset := count
+		args.Offset = &offset
+		results, err := api.ExportOperations(args)
+		if err != nil {
+			return count, errors.Trace(err)
+		}
+		for _, result := range results.Results {
+			if err := enc.Encode(result); err != nil {
+				return count, errors.Trace(err)
+			}
+		}
+		count += len(results.Results)
+		if !results.Truncated || len(results.Results) == 0 {
+			return count, nil
+		}
+	}
+}
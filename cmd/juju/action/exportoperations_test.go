@@ -0,0 +1,131 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/action"
+)
+
+type ExportOperationsSuite struct {
+	BaseActionSuite
+}
+
+var _ = gc.Suite(&ExportOperationsSuite{})
+
+func (s *ExportOperationsSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+	}{{
+		args:     []string{"--from", "yesterday"},
+		errMatch: `invalid --from time "yesterday": expected RFC3339 format, e.g. 2021-06-01T02:00:00Z`,
+	}, {
+		args:     []string{"--to", "2021-06-01"},
+		errMatch: `invalid --to time "2021-06-01": expected RFC3339 format, e.g. 2021-06-01T02:00:00Z`,
+	}, {
+		args:     []string{"--from", "2021-06-02T00:00:00Z", "--to", "2021-06-01T00:00:00Z"},
+		errMatch: "--from time must be before --to time",
+	}, {
+		args:     []string{"foo"},
+		errMatch: `unrecognized args: \["foo"\]`,
+	}} {
+		c.Logf("test %d", i)
+		cmd := action.NewExportOperationsCommandForTest(s.store)
+		args := append([]string{s.modelFlags[0], "admin"}, test.args...)
+		err := cmdtesting.InitCommand(cmd, args)
+		c.Check(err, gc.ErrorMatches, test.errMatch)
+	}
+}
+
+func (s *ExportOperationsSuite) operations(ids ...string) []params.OperationResult {
+	var results []params.OperationResult
+	for _, id := range ids {
+		results = append(results, params.OperationResult{
+			OperationTag: "operation-" + id,
+			Status:       "completed",
+		})
+	}
+	return results
+}
+
+func (s *ExportOperationsSuite) TestRun(c *gc.C) {
+	fakeClient := &fakeAPIClient{operationResults: s.operations("1", "2")}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := cmdtesting.RunCommand(c, action.NewExportOperationsCommandForTest(s.store),
+		s.modelFlags[0], "admin", "--from", "2021-06-01T00:00:00Z")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+{"operation":"operation-1","summary":"","enqueued":"0001-01-01T00:00:00Z","started":"0001-01-01T00:00:00Z","completed":"0001-01-01T00:00:00Z","status":"completed"}
+{"operation":"operation-2","summary":"","enqueued":"0001-01-01T00:00:00Z","started":"0001-01-01T00:00:00Z","completed":"0001-01-01T00:00:00Z","status":"completed"}
+`[1:])
+	c.Assert(fakeClient.exportArgs, gc.HasLen, 1)
+	args := fakeClient.exportArgs[0]
+	c.Check(args.Archived, jc.IsFalse)
+	c.Check(*args.From, gc.Equals, time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC))
+	c.Check(args.To, gc.IsNil)
+}
+
+func (s *ExportOperationsSuite) TestRunBatches(c *gc.C) {
+	var ids []string
+	for i := 0; i < 250; i++ {
+		ids = append(ids, "1")
+	}
+	fakeClient := &fakeAPIClient{operationResults: s.operations(ids...)}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	_, err := cmdtesting.RunCommand(c, action.NewExportOperationsCommandForTest(s.store), s.modelFlags[0], "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fakeClient.exportArgs, gc.HasLen, 3)
+	for i, args := range fakeClient.exportArgs {
+		c.Check(*args.Offset, gc.Equals, i*100)
+		c.Check(*args.Limit, gc.Equals, 100)
+	}
+}
+
+func (s *ExportOperationsSuite) TestRunIncludeArchivedToFile(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		archivedResults:  s.operations("1"),
+		operationResults: s.operations("2"),
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	path := filepath.Join(c.MkDir(), "operations.jsonl")
+	ctx, err := cmdtesting.RunCommand(c, action.NewExportOperationsCommandForTest(s.store),
+		s.modelFlags[0], "admin", "--include-archived", "--filename", path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, "Exported 2 operation(s) to "+path+"\n")
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, `
+{"operation":"operation-1","summary":"","enqueued":"0001-01-01T00:00:00Z","started":"0001-01-01T00:00:00Z","completed":"0001-01-01T00:00:00Z","status":"completed"}
+{"operation":"operation-2","summary":"","enqueued":"0001-01-01T00:00:00Z","started":"0001-01-01T00:00:00Z","completed":"0001-01-01T00:00:00Z","status":"completed"}
+`[1:])
+	c.Assert(fakeClient.exportArgs, gc.HasLen, 2)
+	c.Check(fakeClient.exportArgs[0].Archived, jc.IsTrue)
+	c.Check(fakeClient.exportArgs[1].Archived, jc.IsFalse)
+}
+
+func (s *ExportOperationsSuite) TestRunNoOperations(c *gc.C) {
+	fakeClient := &fakeAPIClient{}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := cmdtesting.RunCommand(c, action.NewExportOperationsCommandForTest(s.store), s.modelFlags[0], "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, "no matching operations\n")
+}
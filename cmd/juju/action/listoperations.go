@@ -275,6 +275,23 @@ type operationInfo struct {
 	Action  *actionSummary      `yaml:"action,omitempty" json:"action,omitempty"`
 	Timing  timingInfo          `yaml:"timing,omitempty" json:"timing,omitempty"`
 	Tasks   map[string]taskInfo `yaml:"tasks,omitempty" json:"tasks,omitempty"`
+	Rollout *rolloutInfo        `yaml:"rollout,omitempty" json:"rollout,omitempty"`
+}
+
+type rolloutInfo struct {
+	BatchSize   int         `yaml:"batch-size,omitempty" json:"batch-size,omitempty"`
+	MaxParallel int         `yaml:"max-parallel,omitempty" json:"max-parallel,omitempty"`
+	BatchWait   string      `yaml:"batch-wait,omitempty" json:"batch-wait,omitempty"`
+	MaxFailures int         `yaml:"max-failures,omitempty" json:"max-failures,omitempty"`
+	Halted      string      `yaml:"halted,omitempty" json:"halted,omitempty"`
+	Batches     []batchInfo `yaml:"batches" json:"batches"`
+}
+
+type batchInfo struct {
+	Pending   int `yaml:"pending" json:"pending"`
+	Running   int `yaml:"running" json:"running"`
+	Completed int `yaml:"completed" json:"completed"`
+	Failed    int `yaml:"failed" json:"failed"`
 }
 
 type timingInfo struct {
@@ -314,6 +331,21 @@ func formatOperationResult(operation params.OperationResult, utc bool) operation
 	if err := operation.Error; err != nil {
 		result.Error = err.Error()
 	}
+	if rollout := operation.Rollout; rollout != nil {
+		result.Rollout = &rolloutInfo{
+			BatchSize:   rollout.BatchSize,
+			MaxParallel: rollout.MaxParallel,
+			MaxFailures: rollout.MaxFailures,
+			Halted:      rollout.Halted,
+			Batches:     make([]batchInfo, len(rollout.Batches)),
+		}
+		if rollout.BatchWait > 0 {
+			result.Rollout.BatchWait = rollout.BatchWait.String()
+		}
+		for i, batch := range rollout.Batches {
+			result.Rollout.Batches[i] = batchInfo(batch)
+		}
+	}
 	var singleAction actionSummary
 	haveSingleAction := true
 	for i, task := range operation.Actions {
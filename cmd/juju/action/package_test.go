@@ -142,11 +142,16 @@ type fakeAPIClient struct {
 	actionResults      []params.ActionResult
 	operationResults   []params.OperationResult
 	operationQueryArgs params.OperationQueryArgs
+	archivedResults    []params.OperationResult
+	exportArgs         []params.OperationExportArgs
 	enqueuedActions    params.Actions
 	actionsByReceivers []params.ActionsByReceiver
 	charmActions       map[string]params.ActionSpec
 	machines           set.Strings
 	execParams         *params.RunParams
+	rolloutParams      *params.RolloutParams
+	scheduleParams     *params.ScheduleParams
+	resumedOperation   string
 	apiErr             error
 	logMessageCh       chan []string
 	waitForResults     chan bool
@@ -244,6 +249,27 @@ func (c *fakeAPIClient) ListOperations(args params.OperationQueryArgs) (params.O
 	}, c.apiErr
 }
 
+func (c *fakeAPIClient) ExportOperations(args params.OperationExportArgs) (params.OperationResults, error) {
+	c.exportArgs = append(c.exportArgs, args)
+	if c.apiErr != nil {
+		return params.OperationResults{}, c.apiErr
+	}
+	operations := c.operationResults
+	if args.Archived {
+		operations = c.archivedResults
+	}
+	if args.Offset != nil {
+		operations = operations[*args.Offset:]
+	}
+	var result params.OperationResults
+	if args.Limit != nil && len(operations) > *args.Limit {
+		operations = operations[:*args.Limit]
+		result.Truncated = true
+	}
+	result.Results = operations
+	return result, nil
+}
+
 func (c *fakeAPIClient) Operation(id string) (params.OperationResult, error) {
 	// If the test supplies a delay time too long, we'll return an error
 	// to prevent the test hanging.  If the given wait is up, then return
@@ -336,6 +362,17 @@ func (c *fakeAPIClient) RunOnAllMachines(commands string, wait time.Duration) (p
 	return result, nil
 }
 
+func (c *fakeAPIClient) RunOnAllMachinesWithParams(runParams params.RunParams) (params.EnqueuedActions, error) {
+	c.rolloutParams = runParams.Rollout
+	c.scheduleParams = runParams.Schedule
+	return c.RunOnAllMachines(runParams.Commands, runParams.Timeout)
+}
+
+func (c *fakeAPIClient) ResumeOperation(id string) error {
+	c.resumedOperation = id
+	return c.apiErr
+}
+
 func (c *fakeAPIClient) Run(runParams params.RunParams) (params.EnqueuedActions, error) {
 	var result params.EnqueuedActions
 
@@ -0,0 +1,82 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+func NewResumeOperationCommand() cmd.Command {
+	return modelcmd.Wrap(&resumeOperationCommand{})
+}
+
+// resumeOperationCommand resumes a rolling operation which was halted
+// after too many of its tasks failed.
+type resumeOperationCommand struct {
+	ActionCommandBase
+	requestedID string
+}
+
+const resumeOperationDoc = `
+Resume a rolling operation that was halted because too many of its
+tasks failed.
+
+Rolling operations are started with the --batch-size, --max-parallel,
+--batch-wait or --max-failures options of 'juju exec' and 'juju run'.
+Once resumed, the failures seen so far no longer count towards the
+--max-failures limit and the remaining batches are released as before.
+
+Examples:
+
+    juju resume-operation 1
+
+See also:
+    exec
+    run
+    show-operation
+`
+
+// Info implements Command.
+func (c *resumeOperationCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "resume-operation",
+		Args:    "<operation-id>",
+		Purpose: "Resume a halted rolling operation.",
+		Doc:     resumeOperationDoc,
+	})
+}
+
+// Init implements Command.
+func (c *resumeOperationCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no operation ID specified")
+	case 1:
+		c.requestedID = args[0]
+		return nil
+	default:
+		return cmd.CheckEmpty(args[1:])
+	}
+}
+
+// Run implements Command.
+func (c *resumeOperationCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	if err := api.ResumeOperation(c.requestedID); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Resumed operation %s", c.requestedID)
+	ctx.Infof("Check operation status with 'juju show-operation %s'", c.requestedID)
+	return nil
+}
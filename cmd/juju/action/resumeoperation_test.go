@@ -0,0 +1,53 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"errors"
+
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/action"
+)
+
+type ResumeOperationSuite struct {
+	BaseActionSuite
+}
+
+var _ = gc.Suite(&ResumeOperationSuite{})
+
+func (s *ResumeOperationSuite) TestInit(c *gc.C) {
+	cmd := action.NewResumeOperationCommandForTest(s.store)
+	err := cmdtesting.InitCommand(cmd, []string{s.modelFlags[0], "admin"})
+	c.Check(err, gc.ErrorMatches, "no operation ID specified")
+
+	cmd = action.NewResumeOperationCommandForTest(s.store)
+	err = cmdtesting.InitCommand(cmd, []string{s.modelFlags[0], "admin", "1", "2"})
+	c.Check(err, gc.ErrorMatches, `unrecognized args: \["2"\]`)
+}
+
+func (s *ResumeOperationSuite) TestRun(c *gc.C) {
+	fakeClient := &fakeAPIClient{}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := cmdtesting.RunCommand(c, action.NewResumeOperationCommandForTest(s.store), s.modelFlags[0], "admin", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fakeClient.resumedOperation, gc.Equals, "1")
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, `
+Resumed operation 1
+Check operation status with 'juju show-operation 1'
+`[1:])
+}
+
+func (s *ResumeOperationSuite) TestRunError(c *gc.C) {
+	fakeClient := &fakeAPIClient{apiErr: errors.New(`operation "1" is not halted`)}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	_, err := cmdtesting.RunCommand(c, action.NewResumeOperationCommandForTest(s.store), s.modelFlags[0], "admin", "1")
+	c.Assert(err, gc.ErrorMatches, `operation "1" is not halted`)
+}
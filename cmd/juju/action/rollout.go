@@ -0,0 +1,165 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// rolloutFlags holds the options used to release the tasks of an
+// operation in batches rather than all at once.
+type rolloutFlags struct {
+	batchSize   int
+	maxParallel int
+	batchWait   time.Duration
+	maxFailures int
+}
+
+func (f *rolloutFlags) SetFlags(fs *gnuflag.FlagSet) {
+	fs.IntVar(&f.batchSize, "batch-size", 0, "Run the tasks in batches of this many targets")
+	fs.IntVar(&f.maxParallel, "max-parallel", 0, "Maximum number of tasks to run at the same time")
+	fs.DurationVar(&f.batchWait, "batch-wait", 0, "Time to wait between batches")
+	fs.IntVar(&f.maxFailures, "max-failures", 0, "Halt the rollout once this many tasks have failed")
+}
+
+func (f *rolloutFlags) validate() error {
+	if f.batchSize < 0 {
+		return errors.New("--batch-size cannot be negative")
+	}
+	if f.maxParallel < 0 {
+		return errors.New("--max-parallel cannot be negative")
+	}
+	if f.batchWait < 0 {
+		return errors.New("--batch-wait cannot be negative")
+	}
+	if f.maxFailures < 0 {
+		return errors.New("--max-failures cannot be negative")
+	}
+	return nil
+}
+
+// params returns the rollout parameters for the operation, or nil
+// if none of the rollout flags have been specified.
+func (f *rolloutFlags) params() *params.RolloutParams {
+	if f.batchSize == 0 && f.maxParallel == 0 && f.batchWait == 0 && f.maxFailures == 0 {
+		return nil
+	}
+	return &params.RolloutParams{
+		BatchSize:   f.batchSize,
+		MaxParallel: f.maxParallel,
+		BatchWait:   f.batchWait,
+		MaxFailures: f.maxFailures,
+	}
+}
+
+// processRolloutResults reports on a rolling operation and, unless
+// running in the background, waits for it to finish. The tasks of a
+// rolling operation are created by the controller as each batch is
+// released, so progress is tracked through the operation itself.
+func (c *runCommandBase) processRolloutResults(ctx *cmd.Context, results *params.EnqueuedActions) error {
+	var numTasks int
+	for _, a := range results.Actions {
+		if a.Error == nil {
+			numTasks++
+			continue
+		}
+		if results.OperationTag == "" {
+			return a.Error
+		}
+		ctx.Warningf("%v", a.Error)
+	}
+	operationTag, err := names.ParseOperationTag(results.OperationTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	operationId := operationTag.Id()
+	var plural string
+	if numTasks != 1 {
+		plural = "s"
+	}
+	if c.background {
+		ctx.Infof("Scheduled rolling operation %s with %d task%s", operationId, numTasks, plural)
+		ctx.Infof("Check operation status with 'juju show-operation %s'", operationId)
+		return nil
+	}
+	ctx.Infof("Running rolling operation %s with %d task%s", operationId, numTasks, plural)
+
+	result, err := c.waitForRollout(ctx, operationId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("")
+	info := make(map[string]interface{}, len(result.Actions))
+	for _, a := range result.Actions {
+		if a.Action == nil {
+			continue
+		}
+		actionTag, err := names.ParseActionTag(a.Action.Tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		task := enqueuedAction{receiver: a.Action.Receiver}
+		info[task.receiverId()] = formatActionResult(actionTag.Id(), a, c.utc)
+	}
+	return c.out.Write(ctx, info)
+}
+
+// waitForRollout polls the operation until it is no longer pending or
+// running, reporting the progress of each batch as it changes.
+func (c *runCommandBase) waitForRollout(ctx *cmd.Context, operationId string) (params.OperationResult, error) {
+	var wait <-chan time.Time
+	if c.wait >= 0 {
+		wait = c.clock.After(c.wait)
+	}
+	var reported []string
+	for {
+		result, err := c.api.Operation(operationId)
+		if err != nil {
+			return result, errors.Trace(err)
+		}
+		if rollout := result.Rollout; rollout != nil {
+			for i, batch := range rollout.Batches {
+				progress := formatRolloutBatch(i, len(rollout.Batches), batch)
+				if i < len(reported) {
+					if reported[i] == progress {
+						continue
+					}
+					reported[i] = progress
+				} else {
+					reported = append(reported, progress)
+				}
+				ctx.Infof("  - %s", progress)
+			}
+			if rollout.Halted != "" {
+				return result, errors.Errorf("operation %s halted: %s\n"+
+					"Resume it with 'juju resume-operation %s'", operationId, rollout.Halted, operationId)
+			}
+		}
+		switch result.Status {
+		case params.ActionRunning, params.ActionPending:
+		default:
+			return result, nil
+		}
+
+		select {
+		case <-wait:
+			return result, errors.Errorf("timed out waiting for operation %s to complete\n"+
+				"Check operation status with 'juju show-operation %s'", operationId, operationId)
+		case <-c.clock.After(resultPollTime):
+		}
+	}
+}
+
+func formatRolloutBatch(i, total int, batch params.RolloutBatch) string {
+	return fmt.Sprintf("batch %d/%d: %d completed, %d failed, %d running, %d pending",
+		i+1, total, batch.Completed, batch.Failed, batch.Running, batch.Pending)
+}
@@ -57,6 +57,19 @@ use the --background option.
 
 To set the maximum time to wait for a action to complete, use the --wait option.
 
+To run an action on many units without running it everywhere at once, use
+--batch-size to release the tasks in batches, --max-parallel to limit how
+many tasks run at the same time, and --batch-wait to pause between batches.
+With --max-failures, the operation is halted once that many tasks have failed;
+a halted operation can be continued with 'juju resume-operation <ID>'.
+
+To run an action later, use --at with a time in RFC3339 format. To run it on
+a recurring basis, use --schedule with a cron specification, such as
+"0 2 * * *", or a descriptor, such as @daily; schedules are interpreted in
+UTC. Leaders are resolved as each run is scheduled. Each run is
+listed by 'juju operations' as soon as it is scheduled, and the schedule is
+cancelled by cancelling all the tasks of the next run with 'juju cancel-task'.
+
 By default, the output of a single action will just be that action's stdout.
 For multiple actions, each action stdout is printed with the action id.
 To see more detailed information about run timings etc, use --format yaml.
@@ -88,6 +101,9 @@ Examples:
     juju run mysql/3 backup --utc
     juju run mysql/3 backup
     juju run mysql/leader backup
+    juju run mysql/0 mysql/1 mysql/2 backup --batch-size 1 --batch-wait 5m
+    juju run mysql/leader backup --at 2021-06-01T02:00:00Z
+    juju run mysql/leader backup --schedule @daily
     juju show-operation <ID>
     juju run mysql/3 backup --params parameters.yml
     juju run mysql/3 backup out=out.tar.bz2 file.kind=xz file.quality=high
@@ -96,8 +112,10 @@ Examples:
     juju run sleeper/0 pause --string-args time=1000
 
 See also:
+    cancel-task
     list-operations
     list-tasks
+    resume-operation
     show-operation
     show-task
 `
@@ -233,7 +251,11 @@ func (c *runCommand) enqueueActions(ctx *cmd.Context) (*params.EnqueuedActions,
 		actions[i].Name = c.actionName
 		actions[i].Parameters = actionParams
 	}
-	results, err := c.api.EnqueueOperation(params.Actions{Actions: actions})
+	results, err := c.api.EnqueueOperation(params.Actions{
+		Actions:  actions,
+		Rollout:  c.rollout.params(),
+		Schedule: c.schedule.params(),
+	})
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -0,0 +1,117 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+	"gopkg.in/robfig/cron.v2"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// scheduleFlags holds the options used to run an operation at a later
+// time, and possibly again on a recurring basis.
+type scheduleFlags struct {
+	at         string
+	recurrence string
+
+	atTime time.Time
+}
+
+func (f *scheduleFlags) SetFlags(fs *gnuflag.FlagSet) {
+	fs.StringVar(&f.at, "at", "", "Run the tasks at this time (RFC3339, e.g. 2021-06-01T02:00:00Z)")
+	fs.StringVar(&f.recurrence, "schedule", "", "Run the tasks on this recurring cron schedule, in UTC (e.g. \"0 2 * * *\" or @daily)")
+}
+
+func (f *scheduleFlags) validate() error {
+	if f.at != "" {
+		at, err := time.Parse(time.RFC3339, f.at)
+		if err != nil {
+			return errors.Errorf("invalid --at time %q: expected RFC3339 format, e.g. 2021-06-01T02:00:00Z", f.at)
+		}
+		f.atTime = at
+	}
+	if f.recurrence != "" {
+		if _, err := cron.Parse(f.recurrence); err != nil {
+			return errors.Errorf("invalid --schedule %q: %v", f.recurrence, err)
+		}
+	}
+	return nil
+}
+
+// params returns the schedule parameters for the operation, or nil
+// if neither of the schedule flags have been specified.
+func (f *scheduleFlags) params() *params.ScheduleParams {
+	if f.atTime.IsZero() && f.recurrence == "" {
+		return nil
+	}
+	schedule := &params.ScheduleParams{Recurrence: f.recurrence}
+	if !f.atTime.IsZero() {
+		at := f.atTime
+		schedule.At = &at
+	}
+	return schedule
+}
+
+// firstRun returns the time the first run of the schedule is due.
+func (f *scheduleFlags) firstRun(now time.Time) time.Time {
+	if !f.atTime.IsZero() {
+		return f.atTime
+	}
+	schedule, err := cron.Parse(f.recurrence)
+	if err != nil {
+		return time.Time{}
+	}
+	return schedule.Next(now.UTC())
+}
+
+// processScheduledResults reports on the first run of a scheduled
+// operation. The tasks are held back by the controller until the
+// run is due, so there is nothing to wait for.
+func (c *runCommandBase) processScheduledResults(ctx *cmd.Context, results *params.EnqueuedActions) error {
+	operationTag, err := names.ParseOperationTag(results.OperationTag)
+	if err != nil {
+		for _, a := range results.Actions {
+			if a.Error != nil {
+				return a.Error
+			}
+		}
+		return errors.Trace(err)
+	}
+	operationId := operationTag.Id()
+
+	info := make(map[string]interface{}, len(results.Actions))
+	for _, a := range results.Actions {
+		if a.Error != nil {
+			ctx.Warningf("%v", a.Error)
+			continue
+		}
+		actionTag, err := names.ParseActionTag(a.Action.Tag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		task := enqueuedAction{receiver: a.Action.Receiver}
+		info[task.receiverId()] = map[string]string{
+			"id": actionTag.Id(),
+		}
+	}
+	var plural string
+	if len(info) != 1 {
+		plural = "s"
+	}
+	ctx.Infof("Scheduled operation %s with %d task%s to run at %s",
+		operationId, len(info), plural, formatTimestamp(c.schedule.firstRun(c.clock.Now()), false, c.utc, false))
+	if c.schedule.recurrence != "" {
+		ctx.Infof("The tasks will be run again on the schedule %q", c.schedule.recurrence)
+	}
+	_ = cmd.FormatYaml(ctx.Stdout, info)
+	ctx.Infof("Check operation status with 'juju show-operation %s'", operationId)
+	ctx.Infof("Cancel the schedule by cancelling its tasks with 'juju cancel-task <id> ...'")
+	return nil
+}
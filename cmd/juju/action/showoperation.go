@@ -165,7 +165,11 @@ func operationTimerLoop(api APIClient, requestedId string, tick, wait clock.Time
 		}
 
 		// Whether or not we're waiting for a result, if a completed
-		// result arrives, we're done.
+		// result arrives, we're done. A halted rolling operation won't
+		// progress until it is resumed, so there's no point waiting.
+		if result.Rollout != nil && result.Rollout.Halted != "" {
+			return result, nil
+		}
 		switch result.Status {
 		case params.ActionRunning, params.ActionPending:
 		default:
@@ -197,6 +197,42 @@ tasks:
     results:
       foo:
         bar: baz
+`[1:],
+	}, {
+		should:            "pretty-print rolling operation progress",
+		withClientQueryID: operationId,
+		withAPITimeout:    1 * time.Second,
+		withAPIResponse: []params.OperationResult{{
+			OperationTag: names.NewOperationTag(operationId).String(),
+			Summary:      "a rolling operation",
+			Status:       "running",
+			Rollout: &params.RolloutInfo{
+				RolloutParams: params.RolloutParams{
+					BatchSize:   2,
+					BatchWait:   time.Minute,
+					MaxFailures: 1,
+				},
+				Halted:  "1 of 3 tasks failed",
+				Batches: []params.RolloutBatch{{Completed: 1, Failed: 1}, {Pending: 1}},
+			},
+		}},
+		expectedOutput: `
+summary: a rolling operation
+status: running
+rollout:
+  batch-size: 2
+  batch-wait: 1m0s
+  max-failures: 1
+  halted: 1 of 3 tasks failed
+  batches:
+  - pending: 0
+    running: 0
+    completed: 1
+    failed: 1
+  - pending: 1
+    running: 0
+    completed: 0
+    failed: 0
 `[1:],
 	}, {
 		should:            "pretty-print action output with no completed time",
@@ -5,6 +5,7 @@ package application
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/juju/charm/v8"
 	charmresource "github.com/juju/charm/v8/resource"
@@ -114,6 +115,7 @@ type CharmRefreshClient interface {
 	GetCharmURLOrigin(string, string) (*charm.URL, commoncharm.Origin, error)
 	Get(string, string) (*params.ApplicationGetResults, error)
 	SetCharm(string, application.SetCharmConfig) error
+	CharmRefreshReport(string, application.CharmRefreshReportConfig) (params.CharmRefreshReport, error)
 }
 
 // NewCharmAdderFunc is the type of a function used to construct
@@ -178,6 +180,11 @@ type refreshCommand struct {
 	// defined in charm storage metadata, to add or update during upgrade.
 	Storage map[string]storage.Constraints
 
+	// DryRun reports what the refresh would change instead of
+	// refreshing the application.
+	DryRun bool
+	out    cmd.Output
+
 	catacomb catacomb.Catacomb
 	plan     catacomb.Plan
 }
@@ -259,6 +266,16 @@ unexpected behavior.
 --force option for LXD Profiles is not generally recommended when upgrading an 
 application; overriding profiles on the container may cause unexpected 
 behavior. 
+
+The --dry-run option reports what the refresh would change without changing
+the application: config keys added, removed or changing type, relation
+endpoints added or removed and the existing relations that would break,
+storage and device changes, the resources that would be fetched or uploaded,
+and LXD profile changes. The new charm is still added to the model so that
+the controller can compare it with the current one. If the controller would
+refuse the refresh, the reasons are listed and the command fails.
+
+  juju refresh foo --dry-run
 `
 
 func (c *refreshCommand) Info() *cmd.Info {
@@ -284,6 +301,8 @@ func (c *refreshCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(storageFlag{&c.Storage, nil}, "storage", "Charm storage constraints")
 	f.Var(&c.Config, "config", "Path to yaml-formatted application config")
 	f.StringVar(&c.BindToSpaces, "bind", "", "Configure application endpoint bindings to spaces")
+	f.BoolVar(&c.DryRun, "dry-run", false, "Report what the refresh would change without refreshing")
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters.Formatters())
 }
 
 func (c *refreshCommand) Init(args []string) error {
@@ -331,6 +350,12 @@ func (c *refreshCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
+	if c.DryRun {
+		if err := c.checkApplicationFacadeSupport(apiRoot, "reporting a dry run", 14); err != nil {
+			return err
+		}
+	}
+
 	generation, err := c.ActiveBranch()
 	if err != nil {
 		return errors.Trace(err)
@@ -430,6 +455,9 @@ func (c *refreshCommand) Run(ctx *cmd.Context) error {
 		URL:    curl,
 		Origin: commoncharm.CoreCharmOrigin(charmID.Origin),
 	}
+	if c.DryRun {
+		return c.reportRefresh(ctx, charmRefreshClient, generation, curl)
+	}
 	resourceIDs := make(map[string]string)
 	if !charm.CharmHub.Matches(curl.Schema) {
 		// Next, upgrade resources.
@@ -497,6 +525,38 @@ func (c *refreshCommand) Run(ctx *cmd.Context) error {
 	return nil
 }
 
+// reportRefresh writes the controller's report of what refreshing the
+// application to the given charm would change. It fails if the
+// controller would refuse the refresh.
+func (c *refreshCommand) reportRefresh(
+	ctx *cmd.Context,
+	client CharmRefreshClient,
+	generation string,
+	curl *charm.URL,
+) error {
+	var uploads []string
+	for name := range c.Resources {
+		uploads = append(uploads, name)
+	}
+	sort.Strings(uploads)
+	report, err := client.CharmRefreshReport(generation, application.CharmRefreshReportConfig{
+		ApplicationName: c.ApplicationName,
+		CharmURL:        curl,
+		ForceSeries:     c.ForceSeries,
+		ResourceUploads: uploads,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := c.out.Write(ctx, formatRefreshReport(report)); err != nil {
+		return errors.Trace(err)
+	}
+	if len(report.Blockers) > 0 {
+		return errors.Errorf("the controller would refuse to refresh %q", c.ApplicationName)
+	}
+	return nil
+}
+
 func (c *refreshCommand) validateEndpointNames(newCharmEndpoints set.Strings, oldEndpointsMap, userBindings map[string]string) error {
 	for epName := range userBindings {
 		if _, exists := oldEndpointsMap[epName]; exists || epName == "" {
@@ -315,6 +315,72 @@ func (s *RefreshSuite) TestConfigSettingsMinFacadeVersion(c *gc.C) {
 		"updating config at refresh time is not supported by server version 1.2.3")
 }
 
+func (s *RefreshSuite) TestDryRun(c *gc.C) {
+	s.apiConnection.bestFacadeVersion = 14
+	s.charmAPIClient.report = params.CharmRefreshReport{
+		ApplicationName: "foo",
+		CharmURL:        "cs:quantal/foo-1",
+		NewCharmURL:     "cs:quantal/foo-2",
+		Config: []params.CharmRefreshChange{
+			{Name: "port", Change: "changed", Old: "int", New: "string", Detail: "current value will be reset"},
+		},
+		Resources: []params.CharmRefreshResourceChange{
+			{Name: "image", Type: "file", Action: "upload"},
+		},
+	}
+	ctx, err := s.runRefresh(c, "foo", "--dry-run", "--resource", "image=/tmp/image.tgz")
+	c.Assert(err, jc.ErrorIsNil)
+	s.charmAPIClient.CheckCallNames(c, "GetCharmURLOrigin", "Get", "CharmRefreshReport")
+	s.charmAPIClient.CheckCall(c, 2, "CharmRefreshReport", model.GenerationMaster, application.CharmRefreshReportConfig{
+		ApplicationName: "foo",
+		CharmURL:        s.resolvedCharmURL,
+		ResourceUploads: []string{"image"},
+	})
+	for _, call := range s.Calls() {
+		c.Assert(call.FuncName, gc.Not(gc.Equals), "DeployResources")
+	}
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+application: foo
+charm: cs:quantal/foo-1
+new-charm: cs:quantal/foo-2
+config:
+- name: port
+  change: changed
+  old: int
+  new: string
+  detail: current value will be reset
+resources:
+- name: image
+  type: file
+  action: upload
+`[1:])
+}
+
+func (s *RefreshSuite) TestDryRunBlockers(c *gc.C) {
+	s.apiConnection.bestFacadeVersion = 14
+	s.charmAPIClient.report = params.CharmRefreshReport{
+		ApplicationName: "foo",
+		CharmURL:        "cs:quantal/foo-1",
+		NewCharmURL:     "cs:quantal/foo-2",
+		BrokenRelations: []string{"foo:db bar:db"},
+		Blockers:        []string{`would break relation "foo:db bar:db"`},
+	}
+	ctx, err := s.runRefresh(c, "foo", "--dry-run", "--format", "json")
+	c.Assert(err, gc.ErrorMatches, `the controller would refuse to refresh "foo"`)
+	s.charmAPIClient.CheckCallNames(c, "GetCharmURLOrigin", "Get", "CharmRefreshReport")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals,
+		`{"application":"foo","charm":"cs:quantal/foo-1","new-charm":"cs:quantal/foo-2",`+
+			`"broken-relations":["foo:db bar:db"],"blockers":["would break relation \"foo:db bar:db\""]}`+"\n")
+}
+
+func (s *RefreshSuite) TestDryRunMinFacadeVersion(c *gc.C) {
+	s.apiConnection.bestFacadeVersion = 13
+	_, err := s.runRefresh(c, "foo", "--dry-run")
+	c.Assert(err, gc.ErrorMatches,
+		"reporting a dry run at refresh time is not supported by server version 1.2.3")
+	s.charmAPIClient.CheckNoCalls(c)
+}
+
 func (s *RefreshSuite) TestUpgradeWithBindDefaults(c *gc.C) {
 	s.charmAPIClient.bindings = map[string]string{
 		"": "testing",
@@ -979,6 +1045,7 @@ type mockCharmRefreshClient struct {
 	charmOrigin commoncharm.Origin
 
 	bindings map[string]string
+	report   params.CharmRefreshReport
 }
 
 func (m *mockCharmRefreshClient) GetCharmURLOrigin(branchName, appName string) (*charm.URL, commoncharm.Origin, error) {
@@ -991,6 +1058,11 @@ func (m *mockCharmRefreshClient) SetCharm(branchName string, cfg application.Set
 	return m.NextErr()
 }
 
+func (m *mockCharmRefreshClient) CharmRefreshReport(branchName string, cfg application.CharmRefreshReportConfig) (params.CharmRefreshReport, error) {
+	m.MethodCall(m, "CharmRefreshReport", branchName, cfg)
+	return m.report, m.NextErr()
+}
+
 func (m *mockCharmRefreshClient) Get(branchName, applicationName string) (*params.ApplicationGetResults, error) {
 	m.MethodCall(m, "Get", applicationName)
 	return &params.ApplicationGetResults{
@@ -0,0 +1,84 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/juju/apiserver/params"
+)
+
+// RefreshReport is the output of refresh --dry-run.
+type RefreshReport struct {
+	Application     string                  `yaml:"application" json:"application"`
+	Charm           string                  `yaml:"charm" json:"charm"`
+	NewCharm        string                  `yaml:"new-charm" json:"new-charm"`
+	Config          []RefreshChange         `yaml:"config,omitempty" json:"config,omitempty"`
+	Endpoints       []RefreshChange         `yaml:"endpoints,omitempty" json:"endpoints,omitempty"`
+	BrokenRelations []string                `yaml:"broken-relations,omitempty" json:"broken-relations,omitempty"`
+	Storage         []RefreshChange         `yaml:"storage,omitempty" json:"storage,omitempty"`
+	Devices         []RefreshChange         `yaml:"devices,omitempty" json:"devices,omitempty"`
+	Resources       []RefreshResourceChange `yaml:"resources,omitempty" json:"resources,omitempty"`
+	LXDProfile      []RefreshChange         `yaml:"lxd-profile,omitempty" json:"lxd-profile,omitempty"`
+	Blockers        []string                `yaml:"blockers,omitempty" json:"blockers,omitempty"`
+}
+
+// RefreshChange is a single difference between an application's
+// current charm and the charm it would be refreshed to.
+type RefreshChange struct {
+	Name   string `yaml:"name" json:"name"`
+	Change string `yaml:"change" json:"change"`
+	Old    string `yaml:"old,omitempty" json:"old,omitempty"`
+	New    string `yaml:"new,omitempty" json:"new,omitempty"`
+	Detail string `yaml:"detail,omitempty" json:"detail,omitempty"`
+}
+
+// RefreshResourceChange describes what a refresh would do with one of
+// the charm's resources.
+type RefreshResourceChange struct {
+	Name        string `yaml:"name" json:"name"`
+	Type        string `yaml:"type,omitempty" json:"type,omitempty"`
+	Action      string `yaml:"action" json:"action"`
+	Origin      string `yaml:"origin,omitempty" json:"origin,omitempty"`
+	Revision    *int   `yaml:"revision,omitempty" json:"revision,omitempty"`
+	NewRevision *int   `yaml:"new-revision,omitempty" json:"new-revision,omitempty"`
+}
+
+func formatRefreshReport(in params.CharmRefreshReport) RefreshReport {
+	out := RefreshReport{
+		Application:     in.ApplicationName,
+		Charm:           in.CharmURL,
+		NewCharm:        in.NewCharmURL,
+		Config:          formatRefreshChanges(in.Config),
+		Endpoints:       formatRefreshChanges(in.Endpoints),
+		BrokenRelations: in.BrokenRelations,
+		Storage:         formatRefreshChanges(in.Storage),
+		Devices:         formatRefreshChanges(in.Devices),
+		LXDProfile:      formatRefreshChanges(in.LXDProfile),
+		Blockers:        in.Blockers,
+	}
+	for _, res := range in.Resources {
+		out.Resources = append(out.Resources, RefreshResourceChange{
+			Name:        res.Name,
+			Type:        res.Type,
+			Action:      res.Action,
+			Origin:      res.Origin,
+			Revision:    res.Revision,
+			NewRevision: res.NewRevision,
+		})
+	}
+	return out
+}
+
+func formatRefreshChanges(in []params.CharmRefreshChange) []RefreshChange {
+	var out []RefreshChange
+	for _, change := range in {
+		out = append(out, RefreshChange{
+			Name:   change.Name,
+			Change: change.Change,
+			Old:    change.Old,
+			New:    change.New,
+			Detail: change.Detail,
+		})
+	}
+	return out
+}
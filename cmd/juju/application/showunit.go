@@ -5,6 +5,7 @@ package application
 
 import (
 	"strings"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -15,6 +16,7 @@ import (
 	"github.com/juju/juju/api/application"
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/hooktrace"
 )
 
 const showUnitDoc = `
@@ -23,12 +25,17 @@ The command takes deployed unit names as an argument.
 Optionally, relation data for only a specified endpoint
 or related unit may be shown, or just the application data. 
 
+The --hook-traces option shows where the time went in the unit's
+recent hooks, actions and commands: waiting for the machine lock,
+running each hook tool and flushing the hook context.
+
 Examples:
     juju show-unit mysql/0
     juju show-unit mysql/0 wordpress/1
     juju show-unit mysql/0 --app
     juju show-unit mysql/0 --endpoint db
     juju show-unit mysql/0 --related-unit wordpress/2
+    juju show-unit mysql/0 --hook-traces
 `
 
 // NewShowUnitCommand returns a command that displays unit info.
@@ -48,6 +55,7 @@ type showUnitCommand struct {
 	endpoint    string
 	relatedUnit string
 	appOnly     bool
+	hookTraces  bool
 
 	newAPIFunc func() (UnitsInfoAPI, error)
 }
@@ -95,6 +103,7 @@ func (c *showUnitCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.endpoint, "endpoint", "", "only show relation data for the specified endpoint")
 	f.StringVar(&c.relatedUnit, "related-unit", "", "only show relation data for the specified unit")
 	f.BoolVar(&c.appOnly, "app", false, "only show application relation data")
+	f.BoolVar(&c.hookTraces, "hook-traces", false, "show the timing of the unit's recent hooks, actions and commands")
 }
 
 // UnitsInfoAPI defines the API methods that show-unit command uses.
@@ -193,6 +202,29 @@ type RelationData struct {
 	Data                    map[string]UnitRelationData `yaml:"related-units,omitempty" json:"related-units,omitempty"`
 }
 
+// HookToolCall defines the serialization behaviour of a hook tool run
+// by a hook.
+type HookToolCall struct {
+	Name     string `yaml:"name" json:"name"`
+	Args     string `yaml:"args,omitempty" json:"args,omitempty"`
+	Offset   string `yaml:"offset" json:"offset"`
+	Duration string `yaml:"duration" json:"duration"`
+	Failed   bool   `yaml:"failed,omitempty" json:"failed,omitempty"`
+}
+
+// HookTrace defines the serialization behaviour of a trace of a hook,
+// action or command run by a unit.
+type HookTrace struct {
+	Operation        string         `yaml:"operation" json:"operation"`
+	Started          time.Time      `yaml:"started" json:"started"`
+	LockWait         string         `yaml:"lock-wait" json:"lock-wait"`
+	Duration         string         `yaml:"duration" json:"duration"`
+	Flush            string         `yaml:"flush" json:"flush"`
+	ToolCalls        []HookToolCall `yaml:"tool-calls,omitempty" json:"tool-calls,omitempty"`
+	DroppedToolCalls int            `yaml:"dropped-tool-calls,omitempty" json:"dropped-tool-calls,omitempty"`
+	Error            string         `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
 // ApplicationInfo defines the serialization behaviour of the application information.
 type UnitInfo struct {
 	WorkloadVersion string         `yaml:"workload-version,omitempty" json:"workload-version,omitempty"`
@@ -202,6 +234,7 @@ type UnitInfo struct {
 	Charm           string         `yaml:"charm" json:"charm"`
 	Leader          bool           `yaml:"leader" json:"leader"`
 	RelationData    []RelationData `yaml:"relation-info,omitempty" json:"relation-info,omitempty"`
+	HookTraces      []HookTrace    `yaml:"hook-traces,omitempty" json:"hook-traces,omitempty"`
 
 	// The following are for CAAS models.
 	ProviderId string `yaml:"provider-id,omitempty" json:"provider-id,omitempty"`
@@ -224,6 +257,11 @@ func (c *showUnitCommand) createUnitInfo(details application.UnitInfo) (names.Un
 		ProviderId:      details.ProviderId,
 		Address:         details.Address,
 	}
+	if c.hookTraces {
+		for _, trace := range details.HookTraces {
+			info.HookTraces = append(info.HookTraces, formatHookTrace(trace))
+		}
+	}
 	for _, rdparams := range details.RelationData {
 		if c.endpoint != "" && rdparams.Endpoint != c.endpoint {
 			continue
@@ -281,3 +319,25 @@ func (c *showUnitCommand) createUnitInfo(details application.UnitInfo) (names.Un
 
 	return tag, info, nil
 }
+
+func formatHookTrace(trace hooktrace.Trace) HookTrace {
+	result := HookTrace{
+		Operation:        trace.Operation,
+		Started:          trace.Started.UTC(),
+		LockWait:         trace.LockWait.String(),
+		Duration:         trace.Duration.String(),
+		Flush:            trace.Flush.String(),
+		DroppedToolCalls: trace.DroppedToolCalls,
+		Error:            trace.Error,
+	}
+	for _, call := range trace.ToolCalls {
+		result.ToolCalls = append(result.ToolCalls, HookToolCall{
+			Name:     call.Name,
+			Args:     call.Args,
+			Offset:   call.Started.Sub(trace.Started).String(),
+			Duration: call.Duration.String(),
+			Failed:   call.Failed,
+		})
+	}
+	return result
+}
@@ -6,6 +6,7 @@ package application_test
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
@@ -15,6 +16,7 @@ import (
 
 	apiapplication "github.com/juju/juju/api/application"
 	"github.com/juju/juju/cmd/juju/application"
+	"github.com/juju/juju/core/hooktrace"
 	"github.com/juju/juju/jujuclient"
 	_ "github.com/juju/juju/provider/dummy"
 	jujutesting "github.com/juju/juju/testing"
@@ -299,6 +301,98 @@ wordpress/0:
 	})
 }
 
+func (s *ShowUnitSuite) createTestUnitInfoWithHookTraces(app string) apiapplication.UnitInfo {
+	result := s.createTestUnitInfo(app, "")
+	result.RelationData = nil
+	started := time.Date(2021, 4, 1, 12, 0, 0, 0, time.UTC)
+	result.HookTraces = []hooktrace.Trace{{
+		Operation: "run install hook",
+		Started:   started,
+		LockWait:  2 * time.Second,
+		Duration:  time.Minute,
+		Flush:     500 * time.Millisecond,
+		ToolCalls: []hooktrace.ToolCall{{
+			Name:     "status-set",
+			Args:     "maintenance installing",
+			Started:  started.Add(10 * time.Second),
+			Duration: 100 * time.Millisecond,
+		}, {
+			Name:     "relation-get",
+			Args:     "-r db:1 -",
+			Started:  started.Add(30 * time.Second),
+			Duration: time.Second,
+			Failed:   true,
+		}},
+		DroppedToolCalls: 3,
+		Error:            "hook failed",
+	}}
+	return result
+}
+
+func (s *ShowUnitSuite) TestShowHookTraces(c *gc.C) {
+	s.mockAPI.unitsInfoFunc = func([]names.UnitTag) ([]apiapplication.UnitInfo, error) {
+		return []apiapplication.UnitInfo{
+			s.createTestUnitInfoWithHookTraces("wordpress"),
+		}, nil
+	}
+	s.assertRunShow(c, showUnitTest{
+		args: []string{"wordpress/0", "--hook-traces"},
+		stdout: `
+wordpress/0:
+  workload-version: "666"
+  machine: "0"
+  opened-ports:
+  - 100-102/ip
+  public-address: 10.0.0.1
+  charm: charm-wordpress
+  leader: true
+  hook-traces:
+  - operation: run install hook
+    started: 2021-04-01T12:00:00Z
+    lock-wait: 2s
+    duration: 1m0s
+    flush: 500ms
+    tool-calls:
+    - name: status-set
+      args: maintenance installing
+      offset: 10s
+      duration: 100ms
+    - name: relation-get
+      args: -r db:1 -
+      offset: 30s
+      duration: 1s
+      failed: true
+    dropped-tool-calls: 3
+    error: hook failed
+  provider-id: provider-id
+  address: 192.168.1.1
+`[1:],
+	})
+}
+
+func (s *ShowUnitSuite) TestShowHookTracesNotRequested(c *gc.C) {
+	s.mockAPI.unitsInfoFunc = func([]names.UnitTag) ([]apiapplication.UnitInfo, error) {
+		return []apiapplication.UnitInfo{
+			s.createTestUnitInfoWithHookTraces("wordpress"),
+		}, nil
+	}
+	s.assertRunShow(c, showUnitTest{
+		args: []string{"wordpress/0"},
+		stdout: `
+wordpress/0:
+  workload-version: "666"
+  machine: "0"
+  opened-ports:
+  - 100-102/ip
+  public-address: 10.0.0.1
+  charm: charm-wordpress
+  leader: true
+  provider-id: provider-id
+  address: 192.168.1.1
+`[1:],
+	})
+}
+
 func (s *ShowUnitSuite) TestShowJSON(c *gc.C) {
 	s.mockAPI.unitsInfoFunc = func([]names.UnitTag) ([]apiapplication.UnitInfo, error) {
 		return []apiapplication.UnitInfo{
@@ -43,6 +43,10 @@ type APIClient interface {
 	Upload(ar io.ReadSeeker, meta params.BackupsMetadataResult) (string, error)
 	// Remove removes the stored backups.
 	Remove(ids ...string) ([]params.ErrorResult, error)
+	// Restore replaces the controller's state with a stored backup.
+	Restore(id string) error
+	// Contents describes what a stored backup holds.
+	Contents(id string) (*params.BackupsContentsResult, error)
 }
 
 // CommandBase is the base type for backups sub-commands.
@@ -63,3 +63,9 @@ func NewRemoveCommandForTest(store jujuclient.ClientStore) cmd.Command {
 	c.SetClientStore(store)
 	return modelcmd.Wrap(c)
 }
+
+func NewRestoreCommandForTest(store jujuclient.ClientStore) cmd.Command {
+	c := &restoreCommand{}
+	c.SetClientStore(store)
+	return modelcmd.Wrap(c)
+}
@@ -109,6 +109,19 @@ func (mr *MockAPIClientMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockAPIClient)(nil).Close))
 }
 
+// Contents mocks base method
+func (m *MockAPIClient) Contents(arg0 string) (*params.BackupsContentsResult, error) {
+	ret := m.ctrl.Call(m, "Contents", arg0)
+	ret0, _ := ret[0].(*params.BackupsContentsResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Contents indicates an expected call of Contents
+func (mr *MockAPIClientMockRecorder) Contents(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Contents", reflect.TypeOf((*MockAPIClient)(nil).Contents), arg0)
+}
+
 // Create mocks base method
 func (m *MockAPIClient) Create(arg0 string, arg1, arg2 bool) (*params.BackupsMetadataResult, error) {
 	ret := m.ctrl.Call(m, "Create", arg0, arg1, arg2)
@@ -178,6 +191,18 @@ func (mr *MockAPIClientMockRecorder) Remove(arg0 ...interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockAPIClient)(nil).Remove), arg0...)
 }
 
+// Restore mocks base method
+func (m *MockAPIClient) Restore(arg0 string) error {
+	ret := m.ctrl.Call(m, "Restore", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore
+func (mr *MockAPIClientMockRecorder) Restore(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockAPIClient)(nil).Restore), arg0)
+}
+
 // Upload mocks base method
 func (m *MockAPIClient) Upload(arg0 io.ReadSeeker, arg1 params.BackupsMetadataResult) (string, error) {
 	ret := m.ctrl.Call(m, "Upload", arg0, arg1)
@@ -168,6 +168,7 @@ func (s *BaseBackupsSuite) checkArchive(c *gc.C) {
 // Replace this fakeAPIClient with MockAPIClient for all tests.
 type fakeAPIClient struct {
 	metaresult *params.BackupsMetadataResult
+	contents   *params.BackupsContentsResult
 	archive    io.ReadCloser
 	err        error
 
@@ -252,6 +253,21 @@ func (c *fakeAPIClient) Remove(id ...string) ([]params.ErrorResult, error) {
 	return nil, nil
 }
 
+func (c *fakeAPIClient) Restore(id string) error {
+	c.calls = append(c.calls, "Restore")
+	c.idArg = id
+	return c.err
+}
+
+func (c *fakeAPIClient) Contents(id string) (*params.BackupsContentsResult, error) {
+	c.calls = append(c.calls, "Contents")
+	c.idArg = id
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.contents, nil
+}
+
 func (c *fakeAPIClient) Close() error {
 	return nil
 }
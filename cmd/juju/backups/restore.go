@@ -0,0 +1,154 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const restoreDoc = `
+restore-backup replaces the controller's current state with the contents
+of a backup archive.
+
+The backup may either be one already stored on the controller, identified
+by its ID, or a local archive file given with --file, which is uploaded
+to the controller before being restored.
+
+Backups can only be restored onto the controller and machine they were
+taken from, by a controller running the same major and minor version of
+Juju. The controller must not be in HA; remove the other controller
+machines before restoring and re-enable HA afterwards.
+
+Once the restore completes the controller agent restarts, so the
+controller will be briefly unavailable.
+
+Examples:
+    juju restore-backup <ID>
+    juju restore-backup --file juju-backup-20210101-120000.tar.gz
+
+See also:
+    create-backup
+    list-backups
+    upload-backup
+`
+
+var restoreMsg = `
+This command will replace the state of controller %q with the
+contents of the backup. Any changes made since the backup was
+created will be lost.
+
+Continue [y/N]?`[1:]
+
+// NewRestoreCommand returns a command used to restore a backup.
+func NewRestoreCommand() cmd.Command {
+	return modelcmd.Wrap(&restoreCommand{})
+}
+
+// restoreCommand is the sub-command for restoring a backup.
+type restoreCommand struct {
+	CommandBase
+	// ID is the backup ID to restore.
+	ID string
+	// Filename is where to find a local archive to restore.
+	Filename string
+	// AssumeYes skips the confirmation prompt.
+	AssumeYes bool
+}
+
+// Info implements Command.Info.
+func (c *restoreCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "restore-backup",
+		Args:    "[<ID>|--file <filename>]",
+		Purpose: "Restore a backup onto the controller.",
+		Doc:     restoreDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *restoreCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.StringVar(&c.Filename, "file", "", "Upload and restore a local backup archive")
+	f.BoolVar(&c.AssumeYes, "y", false, "Do not prompt for confirmation")
+	f.BoolVar(&c.AssumeYes, "yes", false, "")
+}
+
+// Init implements Command.Init.
+func (c *restoreCommand) Init(args []string) error {
+	switch {
+	case len(args) == 0 && c.Filename == "":
+		return errors.New("missing ID or --file option")
+	case len(args) != 0 && c.Filename != "":
+		return errors.New("cannot specify both an ID and --file")
+	case len(args) != 0:
+		id, args := args[0], args[1:]
+		if err := cmd.CheckEmpty(args); err != nil {
+			return errors.Trace(err)
+		}
+		c.ID = id
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *restoreCommand) Run(ctx *cmd.Context) error {
+	if err := c.validateIaasController(c.Info().Name); err != nil {
+		return errors.Trace(err)
+	}
+
+	if !c.AssumeYes {
+		controllerName, err := c.ControllerName()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		fmt.Fprintf(ctx.Stdout, restoreMsg, controllerName)
+		if err := jujucmd.UserConfirmYes(ctx); err != nil {
+			return errors.Annotate(err, "restoring backup")
+		}
+	}
+
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	id := c.ID
+	if c.Filename != "" {
+		if id, err = c.upload(ctx, client); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	if err := client.Restore(id); err != nil {
+		return errors.Annotatef(err, "restoring backup %v", id)
+	}
+	ctx.Infof("Restored backup %v; the controller agent is restarting.", id)
+	return nil
+}
+
+func (c *restoreCommand) upload(ctx *cmd.Context, client APIClient) (string, error) {
+	archive, meta, err := getArchive(c.Filename)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer archive.Close()
+
+	ctx.Verbosef("Uploading metadata:")
+	c.dumpMetadata(ctx, meta)
+
+	id, err := client.Upload(archive, *meta)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	ctx.Infof("Uploaded backup file, creating backup ID %v", id)
+	return id, nil
+}
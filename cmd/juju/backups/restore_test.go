@@ -0,0 +1,135 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"strings"
+
+	"github.com/golang/mock/gomock"
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/backups"
+)
+
+type restoreSuite struct {
+	BaseBackupsSuite
+
+	command cmd.Command
+}
+
+var _ = gc.Suite(&restoreSuite{})
+
+func (s *restoreSuite) SetUpTest(c *gc.C) {
+	s.BaseBackupsSuite.SetUpTest(c)
+
+	s.command = backups.NewRestoreCommandForTest(s.store)
+}
+
+func (s *restoreSuite) patch(c *gc.C) (*gomock.Controller, *MockAPIClient) {
+	ctrl := gomock.NewController(c)
+	client := NewMockAPIClient(ctrl)
+	s.PatchValue(backups.NewAPIClient,
+		func(c *backups.CommandBase) (backups.APIClient, error) {
+			return client, nil
+		},
+	)
+	return ctrl, client
+}
+
+func (s *restoreSuite) TestInitMissingArgs(c *gc.C) {
+	err := cmdtesting.InitCommand(s.command, nil)
+	c.Assert(err, gc.ErrorMatches, "missing ID or --file option")
+}
+
+func (s *restoreSuite) TestInitIDAndFile(c *gc.C) {
+	err := cmdtesting.InitCommand(s.command, []string{"spam", "--file", "backup.tar.gz"})
+	c.Assert(err, gc.ErrorMatches, "cannot specify both an ID and --file")
+}
+
+func (s *restoreSuite) TestInitExtraArgs(c *gc.C) {
+	err := cmdtesting.InitCommand(s.command, []string{"spam", "eggs"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["eggs"\]`)
+}
+
+func (s *restoreSuite) TestRestoreByID(c *gc.C) {
+	ctrl, client := s.patch(c)
+	defer ctrl.Finish()
+
+	gomock.InOrder(
+		client.EXPECT().Restore("spam").Return(nil),
+		client.EXPECT().Close(),
+	)
+	ctx, err := cmdtesting.RunCommand(c, s.command, "spam", "-y")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, "Restored backup spam; the controller agent is restarting.\n")
+}
+
+func (s *restoreSuite) TestRestoreFromFile(c *gc.C) {
+	ctrl, client := s.patch(c)
+	defer ctrl.Finish()
+
+	archive := NewMockArchiveReader(ctrl)
+	s.PatchValue(backups.GetArchive, func(filename string) (backups.ArchiveReader, *params.BackupsMetadataResult, error) {
+		c.Check(filename, gc.Equals, "backup.tar.gz")
+		return archive, s.metaresult, nil
+	})
+
+	gomock.InOrder(
+		client.EXPECT().Upload(archive, *s.metaresult).Return("eggs", nil),
+		archive.EXPECT().Close(),
+		client.EXPECT().Restore("eggs").Return(nil),
+		client.EXPECT().Close(),
+	)
+	ctx, err := cmdtesting.RunCommand(c, s.command, "--file", "backup.tar.gz", "--yes")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, ""+
+		"Uploaded backup file, creating backup ID eggs\n"+
+		"Restored backup eggs; the controller agent is restarting.\n")
+}
+
+func (s *restoreSuite) TestRestoreError(c *gc.C) {
+	ctrl, client := s.patch(c)
+	defer ctrl.Finish()
+
+	gomock.InOrder(
+		client.EXPECT().Restore("spam").Return(errors.New("failed!")),
+		client.EXPECT().Close(),
+	)
+	_, err := cmdtesting.RunCommand(c, s.command, "spam", "-y")
+	c.Assert(err, gc.ErrorMatches, "restoring backup spam: failed!")
+}
+
+func (s *restoreSuite) TestRestorePromptAborted(c *gc.C) {
+	ctrl, _ := s.patch(c)
+	defer ctrl.Finish()
+
+	ctx := cmdtesting.Context(c)
+	ctx.Stdin = strings.NewReader("n\n")
+	err := cmdtesting.InitCommand(s.command, []string{"spam"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.command.Run(ctx)
+	c.Assert(err, gc.ErrorMatches, "restoring backup: aborted")
+	c.Check(cmdtesting.Stdout(ctx), gc.Matches, `(?s)This command will replace the state of controller "arthur".*Continue \[y/N\]\?`)
+}
+
+func (s *restoreSuite) TestRestorePromptConfirmed(c *gc.C) {
+	ctrl, client := s.patch(c)
+	defer ctrl.Finish()
+
+	gomock.InOrder(
+		client.EXPECT().Restore("spam").Return(nil),
+		client.EXPECT().Close(),
+	)
+	ctx := cmdtesting.Context(c)
+	ctx.Stdin = strings.NewReader("y\n")
+	err := cmdtesting.InitCommand(s.command, []string{"spam"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.command.Run(ctx)
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -5,16 +5,37 @@ package backups
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 
+	"github.com/juju/juju/apiserver/params"
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
 )
 
 const showDoc = `
 show-backup provides the metadata associated with a backup.
+
+With --contents, the controller opens the backup archive and reports
+what it holds instead: the controller UUID and juju version it was taken
+with, the models with their machines, applications and units, and the
+number of documents and size of each database collection. Nothing is
+restored, so this can be used to check that a backup is usable without
+standing up a spare controller.
+
+Examples:
+
+    juju show-backup <ID>
+    juju show-backup --contents <ID>
+    juju show-backup --contents --format yaml <ID>
+
+See also:
+    create-backup
+    restore-backup
 `
 
 // NewShowCommand returns a command used to show metadata for a backup.
@@ -25,8 +46,14 @@ func NewShowCommand() cmd.Command {
 // showCommand is the sub-command for creating a new backup.
 type showCommand struct {
 	CommandBase
+	out cmd.Output
+
 	// ID is the backup ID to get.
 	ID string
+
+	// Contents indicates that the contents of the archive should be
+	// shown rather than its metadata.
+	Contents bool
 }
 
 // Info implements Command.Info.
@@ -39,8 +66,22 @@ func (c *showCommand) Info() *cmd.Info {
 	})
 }
 
+// SetFlags implements Command.SetFlags.
+func (c *showCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.BoolVar(&c.Contents, "contents", false, "Show the contents of the backup archive")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"tabular": formatContentsTabular,
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+	})
+}
+
 // Init implements Command.Init.
 func (c *showCommand) Init(args []string) error {
+	if !c.Contents && c.out.Name() != "tabular" {
+		return errors.New("--format can only be used with --contents")
+	}
 	if len(args) == 0 {
 		return errors.New("missing ID")
 	}
@@ -63,6 +104,14 @@ func (c *showCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
+	if c.Contents {
+		contents, err := client.Contents(c.ID)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return c.out.Write(ctx, newBackupContents(contents))
+	}
+
 	result, err := client.Info(c.ID)
 	if err != nil {
 		return errors.Trace(err)
@@ -71,3 +120,106 @@ func (c *showCommand) Run(ctx *cmd.Context) error {
 	fmt.Fprintln(ctx.Stdout, c.metadata(result))
 	return nil
 }
+
+// backupContents is the displayed form of the contents of a backup
+// archive.
+type backupContents struct {
+	ControllerUUID string             `yaml:"controller-uuid" json:"controller-uuid"`
+	JujuVersion    string             `yaml:"juju-version" json:"juju-version"`
+	Models         []backupModel      `yaml:"models" json:"models"`
+	Collections    []backupCollection `yaml:"collections" json:"collections"`
+}
+
+type backupModel struct {
+	Name         string              `yaml:"name" json:"name"`
+	Owner        string              `yaml:"owner" json:"owner"`
+	UUID         string              `yaml:"uuid" json:"uuid"`
+	Type         string              `yaml:"type" json:"type"`
+	Machines     int                 `yaml:"machines" json:"machines"`
+	Applications []backupApplication `yaml:"applications,omitempty" json:"applications,omitempty"`
+}
+
+type backupApplication struct {
+	Name  string `yaml:"name" json:"name"`
+	Charm string `yaml:"charm" json:"charm"`
+	Units int    `yaml:"units" json:"units"`
+}
+
+type backupCollection struct {
+	Database  string `yaml:"database" json:"database"`
+	Name      string `yaml:"name" json:"name"`
+	Documents int    `yaml:"documents" json:"documents"`
+	Size      int64  `yaml:"size" json:"size"`
+}
+
+func newBackupContents(result *params.BackupsContentsResult) backupContents {
+	contents := backupContents{
+		ControllerUUID: result.ControllerUUID,
+		JujuVersion:    result.JujuVersion.String(),
+		Models:         make([]backupModel, len(result.Models)),
+		Collections:    make([]backupCollection, len(result.Collections)),
+	}
+	for i, m := range result.Models {
+		model := backupModel{
+			Name:     m.Name,
+			Owner:    m.Owner,
+			UUID:     m.UUID,
+			Type:     m.Type,
+			Machines: m.Machines,
+		}
+		for _, app := range m.Applications {
+			model.Applications = append(model.Applications, backupApplication{
+				Name:  app.Name,
+				Charm: app.CharmURL,
+				Units: app.Units,
+			})
+		}
+		contents.Models[i] = model
+	}
+	for i, coll := range result.Collections {
+		contents.Collections[i] = backupCollection{
+			Database:  coll.Database,
+			Name:      coll.Name,
+			Documents: coll.Documents,
+			Size:      coll.Size,
+		}
+	}
+	return contents
+}
+
+func formatContentsTabular(writer io.Writer, value interface{}) error {
+	contents, ok := value.(backupContents)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", contents, value)
+	}
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Controller UUID", contents.ControllerUUID)
+	w.Println("Juju version", contents.JujuVersion)
+	w.Println()
+
+	w.Println("Model", "Type", "Machines", "Applications", "Units")
+	for _, m := range contents.Models {
+		units := 0
+		for _, app := range m.Applications {
+			units += app.Units
+		}
+		w.Println(m.Owner+"/"+m.Name, m.Type, m.Machines, len(m.Applications), units)
+	}
+	w.Println()
+
+	w.Println("Model", "Application", "Charm", "Units")
+	for _, m := range contents.Models {
+		for _, app := range m.Applications {
+			w.Println(m.Owner+"/"+m.Name, app.Name, app.Charm, app.Units)
+		}
+	}
+	w.Println()
+
+	w.Println("Database", "Collection", "Documents", "Size (B)")
+	for _, coll := range contents.Collections {
+		w.Println(coll.Database, coll.Name, coll.Documents, coll.Size)
+	}
+	return tw.Flush()
+}
@@ -8,8 +8,10 @@ import (
 	"github.com/juju/cmd/cmdtesting"
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/backups"
 )
 
@@ -39,3 +41,95 @@ func (s *showSuite) TestError(c *gc.C) {
 	_, err := cmdtesting.RunCommand(c, s.subcommand, s.metaresult.ID)
 	c.Check(errors.Cause(err), gc.ErrorMatches, "failed!")
 }
+
+func (s *showSuite) setContents() *fakeAPIClient {
+	client := s.setSuccess()
+	client.contents = &params.BackupsContentsResult{
+		ControllerUUID: "controller-uuid",
+		JujuVersion:    version.MustParse("2.9.1"),
+		Models: []params.BackupsModelContents{{
+			UUID:     "model-uuid",
+			Name:     "controller",
+			Owner:    "admin",
+			Type:     "iaas",
+			Machines: 1,
+			Applications: []params.BackupsApplicationContents{
+				{Name: "mysql", CharmURL: "cs:mysql-1", Units: 1},
+				{Name: "wordpress", CharmURL: "cs:wordpress-2", Units: 2},
+			},
+		}},
+		Collections: []params.BackupsCollectionContents{
+			{Database: "juju", Name: "models", Documents: 1, Size: 256},
+			{Database: "juju", Name: "units", Documents: 3, Size: 1024},
+		},
+	}
+	return client
+}
+
+func (s *showSuite) TestContents(c *gc.C) {
+	client := s.setContents()
+	ctx, err := cmdtesting.RunCommand(c, s.subcommand, "--contents", s.metaresult.ID)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client.Check(c, s.metaresult.ID, "", "Contents")
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+Controller UUID  controller-uuid
+Juju version     2.9.1
+
+Model             Type  Machines  Applications  Units
+admin/controller  iaas  1         2             3
+
+Model             Application  Charm           Units
+admin/controller  mysql        cs:mysql-1      1
+admin/controller  wordpress    cs:wordpress-2  2
+
+Database  Collection  Documents  Size (B)
+juju      models      1          256
+juju      units       3          1024
+
+`[1:])
+}
+
+func (s *showSuite) TestContentsYAML(c *gc.C) {
+	s.setContents()
+	ctx, err := cmdtesting.RunCommand(c, s.subcommand, "--contents", "--format", "yaml", s.metaresult.ID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+controller-uuid: controller-uuid
+juju-version: 2.9.1
+models:
+- name: controller
+  owner: admin
+  uuid: model-uuid
+  type: iaas
+  machines: 1
+  applications:
+  - name: mysql
+    charm: cs:mysql-1
+    units: 1
+  - name: wordpress
+    charm: cs:wordpress-2
+    units: 2
+collections:
+- database: juju
+  name: models
+  documents: 1
+  size: 256
+- database: juju
+  name: units
+  documents: 3
+  size: 1024
+`[1:])
+}
+
+func (s *showSuite) TestContentsError(c *gc.C) {
+	s.setFailure("failed!")
+	_, err := cmdtesting.RunCommand(c, s.subcommand, "--contents", s.metaresult.ID)
+	c.Check(errors.Cause(err), gc.ErrorMatches, "failed!")
+}
+
+func (s *showSuite) TestFormatWithoutContents(c *gc.C) {
+	s.setSuccess()
+	_, err := cmdtesting.RunCommand(c, s.subcommand, "--format", "yaml", s.metaresult.ID)
+	c.Check(err, gc.ErrorMatches, "--format can only be used with --contents")
+}
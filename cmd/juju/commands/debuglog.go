@@ -4,8 +4,10 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
@@ -52,14 +54,31 @@ The '--include-module' and '--exclude-module' options filter by (dotted)
 logging module name. The module name can be truncated such that all loggers
 with the prefix will match.
 
+The '--include-location' and '--exclude-location' options filter by the
+source location of the logging call, given as <filename>:<line-no>. If the
+line number is omitted, all lines in the file match.
+
+The '--include-message' option only shows messages matching the given
+regular expression.
+
+The '--end-time' option only shows messages logged before the given time,
+which may be in RFC3339 format or of the form "YYYY-MM-DD HH:MM:SS". Once
+the end time has passed, the command stops waiting for new messages.
+
 The filtering options combine as follows:
 * All --include options are logically ORed together.
 * All --exclude options are logically ORed together.
 * All --include-module options are logically ORed together.
 * All --exclude-module options are logically ORed together.
-* The combined --include, --exclude, --include-module and --exclude-module
+* All --include-location options are logically ORed together.
+* All --exclude-location options are logically ORed together.
+* The combined --include, --exclude, --include-module, --exclude-module,
+  --include-location, --exclude-location, --include-message and --end-time
   selections are logically ANDed to form the complete filter.
 
+With '--format json', each message is written as a single line JSON object
+with the entity, timestamp, severity, module, location and message fields.
+
 Examples:
 
 Exclude all machine 0 messages; show a maximum of 100 lines; and continue to
@@ -101,6 +120,13 @@ new WARNING and ERROR messages as they are logged:
 
     juju debug-log --replay --level WARNING
 
+Show all messages about failed hooks logged before midday UTC on the 1st
+of March 2021, as JSON:
+
+    juju debug-log --replay --no-tail --format json \
+        --include-message 'hook .* failed' \
+        --end-time 2021-03-01T12:00:00Z
+
 See also:
     status
     ssh`
@@ -138,6 +164,9 @@ type debugLogCommand struct {
 	notail bool
 	color  bool
 
+	endTime      string
+	outputFormat string
+
 	format string
 	tz     *time.Location
 }
@@ -150,6 +179,10 @@ func (c *debugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(cmd.NewAppendStringsValue(&c.params.ExcludeEntity), "exclude", "Do not show log messages for these entities")
 	f.Var(cmd.NewAppendStringsValue(&c.params.IncludeModule), "include-module", "Only show log messages for these logging modules")
 	f.Var(cmd.NewAppendStringsValue(&c.params.ExcludeModule), "exclude-module", "Do not show log messages for these logging modules")
+	f.Var(cmd.NewAppendStringsValue(&c.params.IncludeLocation), "include-location", "Only show log messages from these source locations")
+	f.Var(cmd.NewAppendStringsValue(&c.params.ExcludeLocation), "exclude-location", "Do not show log messages from these source locations")
+	f.StringVar(&c.params.IncludeMessage, "include-message", "", "Only show log messages matching this regular expression")
+	f.StringVar(&c.endTime, "end-time", "", "Only show log messages logged before this time")
 
 	f.StringVar(&c.level, "l", "", "Log level to show, one of [TRACE, DEBUG, INFO, WARNING, ERROR]")
 	f.StringVar(&c.level, "level", "", "")
@@ -167,6 +200,7 @@ func (c *debugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.location, "location", false, "Show filename and line numbers")
 	f.BoolVar(&c.date, "date", false, "Show dates as well as times")
 	f.BoolVar(&c.ms, "ms", false, "Show times to millisecond precision")
+	f.StringVar(&c.outputFormat, "format", "text", "Output format, one of [text, json]")
 }
 
 func (c *debugLogCommand) Init(args []string) error {
@@ -184,6 +218,23 @@ func (c *debugLogCommand) Init(args []string) error {
 	if c.utc {
 		c.tz = time.UTC
 	}
+	if c.params.IncludeMessage != "" {
+		if _, err := regexp.Compile(c.params.IncludeMessage); err != nil {
+			return errors.Annotate(err, "invalid --include-message")
+		}
+	}
+	if c.endTime != "" {
+		endTime, err := c.parseTime(c.endTime)
+		if err != nil {
+			return errors.Annotate(err, "invalid --end-time")
+		}
+		c.params.EndTime = endTime
+	}
+	switch c.outputFormat {
+	case "text", "json":
+	default:
+		return errors.NotValidf("format %q", c.outputFormat)
+	}
 	if c.date {
 		c.format = "2006-01-02 15:04:05"
 	} else {
@@ -202,6 +253,19 @@ func (c *debugLogCommand) Init(args []string) error {
 	return cmd.CheckEmpty(args)
 }
 
+// parseTime accepts either an RFC3339 time or a date and time without a
+// zone, which is interpreted in the command's time zone.
+func (c *debugLogCommand) parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", value, c.tz)
+	if err != nil {
+		return time.Time{}, errors.Errorf("%q is not a valid time, expected RFC3339 or YYYY-MM-DD HH:MM:SS", value)
+	}
+	return t, nil
+}
+
 func (c *debugLogCommand) processEntities(isCAAS bool, entities []string) []string {
 	if entities == nil {
 		return nil
@@ -276,6 +340,9 @@ func (c *debugLogCommand) Run(ctx *cmd.Context) (err error) {
 	if err != nil {
 		return err
 	}
+	if c.outputFormat == "json" {
+		return c.writeJSONRecords(ctx, messages)
+	}
 	writer := ansiterm.NewWriter(ctx.Stdout)
 	if c.color {
 		writer.SetColorCapable(true)
@@ -291,6 +358,34 @@ func (c *debugLogCommand) Run(ctx *cmd.Context) (err error) {
 	return nil
 }
 
+// jsonLogRecord is the form each log message takes with --format json.
+type jsonLogRecord struct {
+	Entity    string    `json:"entity"`
+	Timestamp time.Time `json:"timestamp"`
+	Severity  string    `json:"severity"`
+	Module    string    `json:"module"`
+	Location  string    `json:"location"`
+	Message   string    `json:"message"`
+}
+
+func (c *debugLogCommand) writeJSONRecords(ctx *cmd.Context, messages <-chan common.LogMessage) error {
+	encoder := json.NewEncoder(ctx.Stdout)
+	for msg := range messages {
+		err := encoder.Encode(jsonLogRecord{
+			Entity:    msg.Entity,
+			Timestamp: msg.Timestamp.In(c.tz),
+			Severity:  msg.Severity,
+			Module:    msg.Module,
+			Location:  msg.Location,
+			Message:   msg.Message,
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
 var SeverityColor = map[string]*ansiterm.Context{
 	"TRACE":   ansiterm.Foreground(ansiterm.Default),
 	"DEBUG":   ansiterm.Foreground(ansiterm.Green),
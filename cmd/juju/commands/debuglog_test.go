@@ -104,6 +104,45 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 				Backlog: 10,
 				Limit:   100,
 			},
+		}, {
+			args: []string{"--include-location", "uniter.go", "--include-location", "runner.go:12"},
+			expected: common.DebugLogParams{
+				IncludeLocation: []string{"uniter.go", "runner.go:12"},
+				Backlog:         10,
+			},
+		}, {
+			args: []string{"--exclude-location", "uniter.go"},
+			expected: common.DebugLogParams{
+				ExcludeLocation: []string{"uniter.go"},
+				Backlog:         10,
+			},
+		}, {
+			args: []string{"--include-message", "hook .* failed"},
+			expected: common.DebugLogParams{
+				IncludeMessage: "hook .* failed",
+				Backlog:        10,
+			},
+		}, {
+			args:     []string{"--include-message", "hook ("},
+			errMatch: `invalid --include-message: error parsing regexp: .*`,
+		}, {
+			args: []string{"--end-time", "2021-03-01T12:00:00+02:00"},
+			expected: common.DebugLogParams{
+				EndTime: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC),
+				Backlog: 10,
+			},
+		}, {
+			args: []string{"--utc", "--end-time", "2021-03-01 12:00:00"},
+			expected: common.DebugLogParams{
+				EndTime: time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC),
+				Backlog: 10,
+			},
+		}, {
+			args:     []string{"--end-time", "noon"},
+			errMatch: `invalid --end-time: "noon" is not a valid time, expected RFC3339 or YYYY-MM-DD HH:MM:SS`,
+		}, {
+			args:     []string{"--format", "yaml"},
+			errMatch: `format "yaml" not valid`,
 		},
 	} {
 		c.Logf("test %v", i)
@@ -112,6 +151,8 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 		err := cmdtesting.InitCommand(modelcmd.Wrap(command), test.args)
 		if test.errMatch == "" {
 			c.Check(err, jc.ErrorIsNil)
+			c.Check(command.params.EndTime.Equal(test.expected.EndTime), jc.IsTrue)
+			command.params.EndTime = test.expected.EndTime
 			c.Check(command.params, jc.DeepEquals, test.expected)
 		} else {
 			c.Check(err, gc.ErrorMatches, test.errMatch)
@@ -130,15 +171,23 @@ func (s *DebugLogSuite) TestParamsPassed(c *gc.C) {
 		"--lines=500",
 		"--level=WARNING",
 		"--no-tail",
+		"--include-location=uniter.go",
+		"--exclude-location=runner.go:12",
+		"--include-message=failed$",
+		"--end-time=2021-03-01T12:00:00Z",
 	)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(fake.params, gc.DeepEquals, common.DebugLogParams{
-		IncludeEntity: []string{"machine-1*"},
-		IncludeModule: []string{"juju.provisioner"},
-		ExcludeEntity: []string{"machine-1-lxd-1"},
-		Backlog:       500,
-		Level:         loggo.WARNING,
-		NoTail:        true,
+		IncludeEntity:   []string{"machine-1*"},
+		IncludeModule:   []string{"juju.provisioner"},
+		ExcludeEntity:   []string{"machine-1-lxd-1"},
+		IncludeLocation: []string{"uniter.go"},
+		ExcludeLocation: []string{"runner.go:12"},
+		IncludeMessage:  "failed$",
+		EndTime:         time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC),
+		Backlog:         500,
+		Level:           loggo.WARNING,
+		NoTail:          true,
 	})
 }
 
@@ -184,6 +233,39 @@ func (s *DebugLogSuite) TestLogOutput(c *gc.C) {
 		"machine-0: 14:15:23 INFO test.module somefile.go:123 this is the log output\n")
 }
 
+func (s *DebugLogSuite) TestJSONOutput(c *gc.C) {
+	tz := time.FixedZone("test", 6*60*60)
+	s.PatchValue(&getDebugLogAPI, func(_ *debugLogCommand) (DebugLogAPI, error) {
+		return &fakeDebugLogAPI{log: []common.LogMessage{
+			{
+				Entity:    "machine-0",
+				Timestamp: time.Date(2016, 10, 9, 8, 15, 23, 345000000, time.UTC),
+				Severity:  "INFO",
+				Module:    "test.module",
+				Location:  "somefile.go:123",
+				Message:   "this is the log output",
+			}, {
+				Entity:    "unit-foo-0",
+				Timestamp: time.Date(2016, 10, 9, 8, 15, 24, 0, time.UTC),
+				Severity:  "ERROR",
+				Module:    "juju.worker.uniter",
+				Location:  "uniter.go:42",
+				Message:   `hook "install" failed`,
+			},
+		}}, nil
+	})
+	ctx, err := cmdtesting.RunCommand(c, newDebugLogCommandTZ(jujuclienttesting.MinimalStore(), tz), "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		`{"entity":"machine-0","timestamp":"2016-10-09T14:15:23.345+06:00","severity":"INFO","module":"test.module","location":"somefile.go:123","message":"this is the log output"}`+"\n"+
+		`{"entity":"unit-foo-0","timestamp":"2016-10-09T14:15:24+06:00","severity":"ERROR","module":"juju.worker.uniter","location":"uniter.go:42","message":"hook \"install\" failed"}`+"\n",
+	)
+
+	ctx, err = cmdtesting.RunCommand(c, newDebugLogCommandTZ(jujuclienttesting.MinimalStore(), tz), "--format", "json", "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), jc.Contains, `"timestamp":"2016-10-09T08:15:23.345Z"`)
+}
+
 type fakeDebugLogAPI struct {
 	log    []common.LogMessage
 	params common.DebugLogParams
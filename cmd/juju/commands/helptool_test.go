@@ -73,6 +73,11 @@ Currently available charm hook tools are:
     relation-ids             list all relation ids with the given relation name
     relation-list            list relation units
     relation-set             set relation settings
+    secret-add               add a new secret
+    secret-get               print secret values
+    secret-grant             grant access to a secret
+    secret-revoke            revoke access to a secret
+    secret-set               update an existing secret
     state-delete             delete server-side-state key value pair
     state-get                print server-side-state value
     state-set                set server-side-state values
@@ -126,6 +131,11 @@ var expectedCommands = []string{
 	"relation-list",
 	"relation-set",
 	"resource-get",
+	"secret-add",
+	"secret-get",
+	"secret-grant",
+	"secret-revoke",
+	"secret-set",
 	"state-delete",
 	"state-get",
 	"state-set",
@@ -42,6 +42,7 @@ import (
 	"github.com/juju/juju/cmd/juju/storage"
 	"github.com/juju/juju/cmd/juju/subnet"
 	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/cmd/juju/waitfor"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/juju"
@@ -331,6 +332,8 @@ func registerCommands(r commandRegistry) {
 	r.Register(status.NewStatusCommand())
 	r.Register(newSwitchCommand())
 	r.Register(status.NewStatusHistoryCommand())
+	r.Register(status.NewModelStatusHistoryCommand())
+	r.Register(waitfor.NewWaitForCommand())
 
 	// Error resolution and debugging commands.
 	r.Register(action.NewExecCommand(nil))
@@ -363,6 +366,7 @@ func registerCommands(r commandRegistry) {
 	r.Register(backups.NewShowCommand())
 	r.Register(backups.NewListCommand())
 	r.Register(backups.NewRemoveCommand())
+	r.Register(backups.NewRestoreCommand())
 	r.Register(backups.NewUploadCommand())
 
 	// Manage authorized ssh keys.
@@ -403,6 +407,19 @@ func registerCommands(r commandRegistry) {
 	r.Register(model.NewRevokeCommand())
 	r.Register(model.NewShowCommand())
 	r.Register(model.NewModelCredentialCommand())
+	r.Register(model.NewModelQuotasCommand())
+	r.Register(model.NewSetModelQuotasCommand())
+	r.Register(model.NewAddWebhookCommand())
+	r.Register(model.NewWebhooksCommand())
+	r.Register(model.NewRemoveWebhookCommand())
+	r.Register(model.NewConfigHistoryCommand())
+	r.Register(model.NewDiffConfigCommand())
+	r.Register(model.NewRollbackConfigCommand())
+	r.Register(model.NewExportModelSpecCommand())
+	r.Register(model.NewApplyModelSpecCommand())
+	r.Register(model.NewSetDesiredBundleCommand())
+	r.Register(model.NewRemoveDesiredBundleCommand())
+	r.Register(model.NewShowBundleDriftCommand())
 	if featureflag.Enabled(feature.Branches) || featureflag.Enabled(feature.Generations) {
 		r.Register(model.NewAddBranchCommand())
 		r.Register(model.NewCommitCommand())
@@ -428,7 +445,9 @@ func registerCommands(r commandRegistry) {
 	r.Register(action.NewCancelCommand())
 	r.Register(action.NewRunCommand())
 	r.Register(action.NewListOperationsCommand())
+	r.Register(action.NewExportOperationsCommand())
 	r.Register(action.NewShowOperationCommand())
+	r.Register(action.NewResumeOperationCommand())
 	r.Register(action.NewShowTaskCommand())
 
 	// Manage controller availability
@@ -488,6 +507,7 @@ func registerCommands(r commandRegistry) {
 	r.Register(controller.NewEnableDestroyControllerCommand())
 	r.Register(controller.NewShowControllerCommand())
 	r.Register(controller.NewConfigCommand())
+	r.Register(controller.NewAuditLogCommand())
 
 	// Debug Metrics
 	r.Register(metricsdebug.New())
@@ -298,11 +298,14 @@ var commandNames = []string{
 	"add-subnet",
 	"add-unit",
 	"add-user",
+	"add-webhook",
 	"agree",
 	"agreements",
+	"apply-model-spec",
 	"attach",
 	"attach-resource",
 	"attach-storage",
+	"audit-log",
 	"autoload-credentials",
 	"backups",
 	"bind",
@@ -316,6 +319,7 @@ var commandNames = []string{
 	"clouds",
 	"collect-metrics",
 	"config",
+	"config-history",
 	"consume",
 	"controller-config",
 	"controllers",
@@ -335,6 +339,7 @@ var commandNames = []string{
 	"destroy-model",
 	"detach-storage",
 	"diff-bundle",
+	"diff-config",
 	"disable-command",
 	"disable-user",
 	"disabled-commands",
@@ -346,6 +351,8 @@ var commandNames = []string{
 	"enable-user",
 	"exec",
 	"export-bundle",
+	"export-model-spec",
+	"export-operations",
 	"expose",
 	"find",
 	"find-offers",
@@ -387,6 +394,7 @@ var commandNames = []string{
 	"list-subnets",
 	"list-users",
 	"list-wallets",
+	"list-webhooks",
 	"login",
 	"logout",
 	"machines",
@@ -395,6 +403,8 @@ var commandNames = []string{
 	"model-config",
 	"model-default",
 	"model-defaults",
+	"model-quotas",
+	"model-status-log",
 	"models",
 	"move-to-space",
 	"offer",
@@ -413,6 +423,7 @@ var commandNames = []string{
 	"remove-cloud",
 	"remove-consumed-application",
 	"remove-credential",
+	"remove-desired-bundle",
 	"remove-k8s",
 	"remove-machine",
 	"remove-offer",
@@ -424,14 +435,18 @@ var commandNames = []string{
 	"remove-storage-pool",
 	"remove-unit",
 	"remove-user",
+	"remove-webhook",
 	"rename-space",
 	"resolved",
 	"resolve",
 	"resources",
+	"restore-backup",
+	"resume-operation",
 	"resume-relation",
 	"retry-provisioning",
 	"revoke",
 	"revoke-cloud",
+	"rollback-config",
 	"run",
 	"scale-application",
 	"scp",
@@ -439,15 +454,18 @@ var commandNames = []string{
 	"set-constraints",
 	"set-default-credential",
 	"set-default-region",
+	"set-desired-bundle",
 	"set-firewall-rule",
 	"set-meter-status",
 	"set-model-constraints",
+	"set-model-quotas",
 	"set-plan",
 	"set-series",
 	"set-wallet",
 	"show-action",
 	"show-application",
 	"show-backup",
+	"show-bundle-drift",
 	"show-cloud",
 	"show-controller",
 	"show-credential",
@@ -494,7 +512,9 @@ var commandNames = []string{
 	"upload-backup",
 	"users",
 	"version",
+	"wait-for",
 	"wallets",
+	"webhooks",
 	"whoami",
 }
 
@@ -0,0 +1,220 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+// NewAuditLogCommand returns a command that queries the audit logs of
+// all the controller nodes.
+func NewAuditLogCommand() cmd.Command {
+	return modelcmd.WrapController(&auditLogCommand{})
+}
+
+const auditLogDoc = `
+Shows the API requests recorded in the audit log of every controller node,
+oldest first. Auditing must be enabled with the auditing-enabled controller
+config setting, and only requests recorded while it was enabled are shown.
+
+Requests can be filtered by the user that made them, the model they were
+made on, and the API facade or facade method called. Each filter may be
+repeated to match any of the given values. --from and --to limit the
+requests to a time range, and accept either an RFC3339 time or a date in
+YYYY-MM-DD format; --to is exclusive.
+
+If the audit log of a controller node couldn't be read, for example because
+the node is down, a warning is shown and the entries from the other nodes
+are still displayed.
+
+Only controller superusers can query the audit log.
+
+Examples:
+
+Show who removed an application from the "prod" model in the first week of
+March:
+
+    juju audit-log --model prod --method Application.DestroyApplication \
+        --from 2021-03-01 --to 2021-03-08
+
+Show the last 20 requests made by bob as JSON:
+
+    juju audit-log --user bob --limit 20 --format json
+
+See also:
+    controller-config
+`
+
+// AuditLogAPI defines the API methods used by the audit-log command.
+type AuditLogAPI interface {
+	AuditLog(params.AuditLogQueryArgs) (params.AuditLogResult, error)
+	Close() error
+}
+
+type auditLogCommand struct {
+	modelcmd.ControllerCommandBase
+	api AuditLogAPI
+	out cmd.Output
+
+	users   []string
+	models  []string
+	methods []string
+	from    string
+	to      string
+	limit   int
+	utc     bool
+
+	fromTime *time.Time
+	toTime   *time.Time
+}
+
+// Info implements Command.Info.
+func (c *auditLogCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "audit-log",
+		Purpose: "Query the audit log of the controller.",
+		Doc:     auditLogDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *auditLogCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.Var(cmd.NewAppendStringsValue(&c.users), "user", "Only show requests made by these users")
+	f.Var(cmd.NewAppendStringsValue(&c.models), "model", "Only show requests made on these models, by name or UUID")
+	f.Var(cmd.NewAppendStringsValue(&c.methods), "method", "Only show calls to these facades or Facade.Method API methods")
+	f.StringVar(&c.from, "from", "", "Only show requests made at or after this time")
+	f.StringVar(&c.to, "to", "", "Only show requests made before this time")
+	f.IntVar(&c.limit, "limit", 100, "Show at most this many of the most recent requests; 0 uses the controller's maximum")
+	f.BoolVar(&c.utc, "utc", false, "Display times in UTC")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"json":    cmd.FormatJson,
+		"tabular": c.formatTabular,
+		"yaml":    cmd.FormatYaml,
+	})
+}
+
+// Init implements Command.Init.
+func (c *auditLogCommand) Init(args []string) error {
+	if c.limit < 0 {
+		return errors.NotValidf("negative --limit")
+	}
+	var err error
+	if c.fromTime, err = parseAuditLogTime("--from", c.from); err != nil {
+		return errors.Trace(err)
+	}
+	if c.toTime, err = parseAuditLogTime("--to", c.to); err != nil {
+		return errors.Trace(err)
+	}
+	if c.fromTime != nil && c.toTime != nil && !c.toTime.After(*c.fromTime) {
+		return errors.New("--to must be after --from")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// parseAuditLogTime parses an RFC3339 time or a YYYY-MM-DD date in
+// local time.
+func parseAuditLogTime(flag, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return nil, errors.Errorf("%s %q is not a valid time, expected RFC3339 or YYYY-MM-DD", flag, value)
+	}
+	return &t, nil
+}
+
+func (c *auditLogCommand) getAPI() (AuditLogAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewControllerAPIClient()
+}
+
+// Run implements Command.Run.
+func (c *auditLogCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	result, err := client.AuditLog(params.AuditLogQueryArgs{
+		Users:   c.users,
+		Models:  c.models,
+		Methods: c.methods,
+		From:    c.fromTime,
+		To:      c.toTime,
+		Limit:   c.limit,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	ids := make([]string, 0, len(result.Missing))
+	for id := range result.Missing {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		ctx.Warningf("entries from controller %s are missing: %s", id, result.Missing[id])
+	}
+
+	if len(result.Entries) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No matching audit log entries.")
+		return nil
+	}
+	if result.Entries == nil {
+		result.Entries = []params.AuditLogEntry{}
+	}
+	return errors.Trace(c.out.Write(ctx, result.Entries))
+}
+
+func (c *auditLogCommand) formatTabular(writer io.Writer, value interface{}) error {
+	entries, ok := value.([]params.AuditLogEntry)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", entries, value)
+	}
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Time", "Controller", "User", "Model", "Method", "Errors")
+	for _, entry := range entries {
+		when := entry.When
+		if c.utc {
+			when = when.UTC()
+		} else {
+			when = when.Local()
+		}
+		var errs []string
+		for _, e := range entry.Errors {
+			errs = append(errs, e.Message)
+		}
+		w.Println(
+			when.Format(time.RFC3339),
+			entry.ControllerID,
+			entry.Who,
+			entry.ModelName,
+			entry.Facade+"."+entry.Method,
+			strings.Join(errs, "; "),
+		)
+	}
+	return tw.Flush()
+}
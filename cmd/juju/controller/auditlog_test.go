@@ -0,0 +1,189 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/controller"
+	"github.com/juju/juju/jujuclient"
+)
+
+type auditLogSuite struct {
+	baseControllerSuite
+	api   *fakeAuditLogAPI
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&auditLogSuite{})
+
+func (s *auditLogSuite) SetUpTest(c *gc.C) {
+	s.baseControllerSuite.SetUpTest(c)
+
+	s.api = &fakeAuditLogAPI{
+		result: params.AuditLogResult{
+			Entries: []params.AuditLogEntry{{
+				ControllerID: "0",
+				Who:          "bob",
+				ModelName:    "prod",
+				When:         time.Date(2021, 3, 2, 10, 0, 0, 0, time.UTC),
+				Facade:       "Application",
+				Method:       "DestroyApplication",
+				Version:      12,
+			}, {
+				ControllerID: "1",
+				Who:          "alice",
+				ModelName:    "prod",
+				When:         time.Date(2021, 3, 2, 11, 0, 0, 0, time.UTC),
+				Facade:       "Application",
+				Method:       "Deploy",
+				Version:      12,
+				Errors:       []params.AuditLogError{{Message: "boom"}},
+			}},
+		},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "fake"
+	s.store.Controllers["fake"] = jujuclient.ControllerDetails{}
+}
+
+func (s *auditLogSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, controller.NewAuditLogCommandForTest(s.api, s.store), args...)
+}
+
+func (s *auditLogSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"--limit", "-1"},
+		err:  "negative --limit not valid",
+	}, {
+		args: []string{"--from", "yesterday"},
+		err:  `--from "yesterday" is not a valid time, expected RFC3339 or YYYY-MM-DD`,
+	}, {
+		args: []string{"--to", "2021-13-01"},
+		err:  `--to "2021-13-01" is not a valid time, expected RFC3339 or YYYY-MM-DD`,
+	}, {
+		args: []string{"--from", "2021-03-02", "--to", "2021-03-01"},
+		err:  "--to must be after --from",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := s.run(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+	c.Assert(s.api.args, gc.IsNil)
+}
+
+func (s *auditLogSuite) TestQueryArgs(c *gc.C) {
+	_, err := s.run(c,
+		"--user", "bob", "--user", "alice",
+		"--model", "prod",
+		"--method", "Application.DestroyApplication",
+		"--from", "2021-03-01T00:00:00Z",
+		"--to", "2021-03-08T00:00:00Z",
+		"--limit", "5",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	from := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 3, 8, 0, 0, 0, 0, time.UTC)
+	c.Assert(s.api.args, jc.DeepEquals, &params.AuditLogQueryArgs{
+		Users:   []string{"bob", "alice"},
+		Models:  []string{"prod"},
+		Methods: []string{"Application.DestroyApplication"},
+		From:    &from,
+		To:      &to,
+		Limit:   5,
+	})
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *auditLogSuite) TestDefaultLimit(c *gc.C) {
+	_, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.args, jc.DeepEquals, &params.AuditLogQueryArgs{Limit: 100})
+}
+
+func (s *auditLogSuite) TestDateIsLocalTime(c *gc.C) {
+	_, err := s.run(c, "--from", "2021-03-01")
+	c.Assert(err, jc.ErrorIsNil)
+	from := time.Date(2021, 3, 1, 0, 0, 0, 0, time.Local)
+	c.Assert(s.api.args.From.Equal(from), jc.IsTrue)
+}
+
+func (s *auditLogSuite) TestTabular(c *gc.C) {
+	ctx, err := s.run(c, "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	// The errors column is padded even when it is empty.
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"Time                  Controller  User   Model  Method                          Errors\n"+
+		"2021-03-02T10:00:00Z  0           bob    prod   Application.DestroyApplication  \n"+
+		"2021-03-02T11:00:00Z  1           alice  prod   Application.Deploy              boom\n"+
+		"\n")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "")
+}
+
+func (s *auditLogSuite) TestJSON(c *gc.C) {
+	s.api.result.Entries = s.api.result.Entries[:1]
+	ctx, err := s.run(c, "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `[{"controller-id":"0","who":"bob","what":"","model-name":"prod","model-uuid":"","conversation-id":"","connection-id":"","request-id":0,"when":"2021-03-02T10:00:00Z","facade":"Application","method":"DestroyApplication","version":12}]`+"\n")
+}
+
+func (s *auditLogSuite) TestNoEntries(c *gc.C) {
+	s.api.result.Entries = nil
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No matching audit log entries.\n")
+
+	ctx, err = s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "[]\n")
+}
+
+func (s *auditLogSuite) TestMissingControllers(c *gc.C) {
+	s.api.result.Missing = map[string]string{
+		"2": "timed out waiting for response",
+		"1": "permission denied",
+	}
+	_, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(c.GetTestLog(), gc.Matches, `(?s).*`+
+		`WARNING cmd entries from controller 1 are missing: permission denied\n.*`+
+		`WARNING cmd entries from controller 2 are missing: timed out waiting for response\n.*`)
+}
+
+func (s *auditLogSuite) TestAPIError(c *gc.C) {
+	s.api.err = errors.New("boom")
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type fakeAuditLogAPI struct {
+	args   *params.AuditLogQueryArgs
+	result params.AuditLogResult
+	err    error
+	closed bool
+}
+
+func (f *fakeAuditLogAPI) AuditLog(args params.AuditLogQueryArgs) (params.AuditLogResult, error) {
+	f.args = &args
+	return f.result, f.err
+}
+
+func (f *fakeAuditLogAPI) Close() error {
+	f.closed = true
+	return nil
+}
@@ -171,6 +171,14 @@ func NewConfigCommandForTest(api controllerAPI, store jujuclient.ClientStore) cm
 	return modelcmd.WrapController(c)
 }
 
+// NewAuditLogCommandForTest returns an audit-log command with the API
+// provided as specified.
+func NewAuditLogCommandForTest(api AuditLogAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &auditLogCommand{api: api}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
 type CtrData ctrData
 type ModelData modelData
 
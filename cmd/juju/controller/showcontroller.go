@@ -6,6 +6,7 @@ package controller
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -16,6 +17,7 @@ import (
 	"github.com/juju/juju/api/controller"
 	"github.com/juju/juju/apiserver/params"
 	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/common"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/permission"
@@ -99,6 +101,7 @@ type ControllerAccessAPI interface {
 	MongoVersion() (string, error)
 	IdentityProviderURL() (string, error)
 	ControllerVersion() (controller.ControllerVersion, error)
+	ScheduledBackupStatus() (params.ScheduledBackupStatus, error)
 	Close() error
 }
 
@@ -193,6 +196,13 @@ func (c *showControllerCommand) Run(ctx *cmd.Context) error {
 				details.Errors = append(details.Errors, err.Error())
 				mongoVersion = "(error)"
 			}
+			// Fetch the scheduled backup status if the apiserver supports it
+			backupStatus, err := client.ScheduledBackupStatus()
+			if err != nil && !errors.IsNotSupported(err) {
+				details.Errors = append(details.Errors, err.Error())
+			} else if err == nil {
+				details.ScheduledBackups = convertScheduledBackupsForShow(backupStatus)
+			}
 		}
 
 		// Fetch identityURL if the apiserver supports it
@@ -296,6 +306,9 @@ type ShowControllerDetails struct {
 	// Account is the account details for the user logged into this controller.
 	Account *AccountDetails `yaml:"account,omitempty" json:"account,omitempty"`
 
+	// ScheduledBackups holds the outcome of the controller's scheduled backups.
+	ScheduledBackups *ScheduledBackupDetails `yaml:"scheduled-backups,omitempty" json:"scheduled-backups,omitempty"`
+
 	// Errors is a collection of errors related to accessing this controller details.
 	Errors []string `yaml:"errors,omitempty" json:"errors,omitempty"`
 }
@@ -391,6 +404,54 @@ type AccountDetails struct {
 	Password string `yaml:"password,omitempty" json:"password,omitempty"`
 }
 
+// ScheduledBackupDetails holds details of the controller's scheduled
+// backups to show.
+type ScheduledBackupDetails struct {
+	// Schedule is the cron-like schedule backups are created on.
+	Schedule string `yaml:"schedule,omitempty" json:"schedule,omitempty"`
+
+	// NextRun is when the next scheduled backup will be created.
+	NextRun string `yaml:"next-run,omitempty" json:"next-run,omitempty"`
+
+	// LastAttempt is when a scheduled backup was last attempted.
+	LastAttempt string `yaml:"last-attempt,omitempty" json:"last-attempt,omitempty"`
+
+	// LastSuccess is when a scheduled backup last succeeded.
+	LastSuccess string `yaml:"last-success,omitempty" json:"last-success,omitempty"`
+
+	// LastBackupID is the ID of the most recent scheduled backup.
+	LastBackupID string `yaml:"last-backup-id,omitempty" json:"last-backup-id,omitempty"`
+
+	// LastFailure is when a scheduled backup last failed.
+	LastFailure string `yaml:"last-failure,omitempty" json:"last-failure,omitempty"`
+
+	// LastError is the error from the last failed scheduled backup.
+	LastError string `yaml:"last-error,omitempty" json:"last-error,omitempty"`
+}
+
+// convertScheduledBackupsForShow returns the details to show for the
+// scheduled backup status, or nil if backups have never been scheduled.
+func convertScheduledBackupsForShow(status params.ScheduledBackupStatus) *ScheduledBackupDetails {
+	if status == (params.ScheduledBackupStatus{}) {
+		return nil
+	}
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return common.FormatTime(t, true)
+	}
+	return &ScheduledBackupDetails{
+		Schedule:     status.Schedule,
+		NextRun:      formatTime(status.NextRun),
+		LastAttempt:  formatTime(status.LastAttempt),
+		LastSuccess:  formatTime(status.LastSuccess),
+		LastBackupID: status.LastBackupID,
+		LastFailure:  formatTime(status.LastFailure),
+		LastError:    status.LastError,
+	}
+}
+
 func (c *showControllerCommand) convertControllerForShow(
 	controller *ShowControllerDetails,
 	controllerName string,
@@ -5,6 +5,7 @@ package controller_test
 
 import (
 	"regexp"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/juju/juju/api/base"
 	apicontroller "github.com/juju/juju/api/controller"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/controller"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/permission"
@@ -527,6 +529,39 @@ func (s *ShowControllerSuite) TestShowControllerWithIdentityProvider(c *gc.C) {
 	c.Assert(cmdtesting.Stdout(ctx), jc.Contains, "identity-url: "+expURL)
 }
 
+func (s *ShowControllerSuite) TestShowControllerWithScheduledBackups(c *gc.C) {
+	_ = s.createTestClientStore(c)
+	s.fakeController.bestAPIVersion = 11
+	ctx, err := s.runShowController(c, "aws-test")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Not(jc.Contains), "scheduled-backups")
+
+	lastSuccess := time.Date(2021, 3, 1, 2, 0, 0, 0, time.UTC)
+	lastFailure := lastSuccess.Add(24 * time.Hour)
+	nextRun := lastFailure.Add(24 * time.Hour)
+	s.fakeController.backupStatus = params.ScheduledBackupStatus{
+		Schedule:     "0 2 * * *",
+		NextRun:      &nextRun,
+		LastAttempt:  &lastFailure,
+		LastSuccess:  &lastSuccess,
+		LastBackupID: "20210301-020000.deadbeef",
+		LastFailure:  &lastFailure,
+		LastError:    "boom",
+	}
+	ctx, err = s.runShowController(c, "aws-test")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), jc.Contains, `
+  scheduled-backups:
+    schedule: 0 2 * * *
+    next-run: 2021-03-03 02:00:00Z
+    last-attempt: 2021-03-02 02:00:00Z
+    last-success: 2021-03-01 02:00:00Z
+    last-backup-id: 20210301-020000.deadbeef
+    last-failure: 2021-03-02 02:00:00Z
+    last-error: boom
+`[1:])
+}
+
 func (s *ShowControllerSuite) TestShowControllerWithCAFingerprint(c *gc.C) {
 	s.controllersYaml = `controllers:
   mallards:
@@ -668,6 +703,7 @@ type fakeController struct {
 	bestAPIVersion    int
 	identityURL       string
 	controllerVersion apicontroller.ControllerVersion
+	backupStatus      params.ScheduledBackupStatus
 }
 
 func (c *fakeController) GetControllerAccess(user string) (permission.Access, error) {
@@ -728,6 +764,13 @@ func (c *fakeController) ControllerVersion() (apicontroller.ControllerVersion, e
 	return c.controllerVersion, nil
 }
 
+func (c *fakeController) ScheduledBackupStatus() (params.ScheduledBackupStatus, error) {
+	if c.bestAPIVersion < 11 {
+		return params.ScheduledBackupStatus{}, errors.NotSupportedf("requires APIVersion >= 11")
+	}
+	return c.backupStatus, nil
+}
+
 func (*fakeController) Close() error {
 	return nil
 }
@@ -0,0 +1,295 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/bundlechanges/v4"
+	"github.com/juju/charm/v8"
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/api/annotations"
+	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/api/modelconfig"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	appcmd "github.com/juju/juju/cmd/juju/application"
+	appbundle "github.com/juju/juju/cmd/juju/application/bundle"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/constraints"
+)
+
+const applyModelSpecDoc = `
+Applies a model spec, as written by export-model-spec, to the model.
+Only the differences between the spec and the model are applied, so
+applying the same spec again makes no further changes.
+
+Parts of the model left out of the spec are left unchanged. Spaces,
+storage pools and firewall rules in the model but not in the spec are
+kept; if the spec has any model config, model config attributes set on
+the model but not in the spec are reset to their defaults. Secret model
+config, such as passwords, is neither set nor reset; use model-config to
+change it. The subnets of existing spaces, and the providers of existing
+storage pools, are not changed; if they differ from the spec, nothing is
+applied.
+
+The bundle in the spec is compared with the model as diff-bundle does,
+and any differences are deployed as deploy does for bundles.
+
+With --dry-run, the changes are shown but not applied.
+
+Examples:
+
+    juju apply-model-spec mymodel-spec.yaml --dry-run
+    juju apply-model-spec -m othermodel mymodel-spec.yaml
+
+See also:
+    export-model-spec
+    diff-bundle
+    deploy
+`
+
+// BundleApplier compares the bundle of a model spec with the model, and
+// deploys it.
+type BundleApplier interface {
+	// DiffBundle returns the differences between the bundle and the
+	// model. The bundle's local charms are found relative to
+	// bundleDir.
+	DiffBundle(bundleYAML, bundleDir string) (*bundlechanges.BundleDiff, error)
+
+	// DeployBundle deploys the bundle to the model.
+	DeployBundle(ctx *cmd.Context, bundleYAML, bundleDir string) error
+}
+
+// NewApplyModelSpecCommand returns a command that applies a model spec
+// to a model.
+func NewApplyModelSpecCommand() cmd.Command {
+	command := &applyModelSpecCommand{}
+	command.bundleApplier = &deployBundleApplier{command: command}
+	return modelcmd.Wrap(command)
+}
+
+type applyModelSpecCommand struct {
+	modelSpecCommandBase
+	bundleApplier BundleApplier
+
+	filename string
+	dryRun   bool
+}
+
+// Info implements Command.Info.
+func (c *applyModelSpecCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "apply-model-spec",
+		Args:    "<model spec file>",
+		Purpose: "Applies a declarative model spec to the model.",
+		Doc:     applyModelSpecDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *applyModelSpecCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.dryRun, "dry-run", false, "Show the changes without applying them")
+}
+
+// Init implements Command.Init.
+func (c *applyModelSpecCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no model spec file specified")
+	}
+	c.filename = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *applyModelSpecCommand) Run(ctx *cmd.Context) error {
+	path := ctx.AbsPath(c.filename)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var desired modelSpec
+	if err := yaml.UnmarshalStrict(data, &desired); err != nil {
+		return errors.Annotatef(err, "cannot parse model spec %q", c.filename)
+	}
+
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	current, err := readModelSpec(client, false)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	changes, err := planSpecChanges(current, desired)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var bundleDiff *bundlechanges.BundleDiff
+	if desired.Bundle != "" {
+		diff, err := c.bundleApplier.DiffBundle(desired.Bundle, filepath.Dir(path))
+		if err != nil {
+			return errors.Annotate(err, "comparing bundle with model")
+		}
+		if !diff.Empty() {
+			bundleDiff = diff
+		}
+	}
+
+	if len(changes) == 0 && bundleDiff == nil {
+		ctx.Infof("The model already matches the model spec.")
+		return nil
+	}
+
+	if c.dryRun {
+		fmt.Fprintln(ctx.Stdout, "Changes to apply:")
+		for _, change := range changes {
+			fmt.Fprintf(ctx.Stdout, "- %s\n", change.description)
+		}
+		if bundleDiff != nil {
+			diffYAML, err := yaml.Marshal(bundleDiff)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			fmt.Fprintln(ctx.Stdout, "- deploy bundle changes:")
+			for _, line := range strings.Split(strings.TrimRight(string(diffYAML), "\n"), "\n") {
+				fmt.Fprintf(ctx.Stdout, "    %s\n", line)
+			}
+		}
+		return nil
+	}
+
+	for _, change := range changes {
+		if err := change.apply(client); err != nil {
+			return block.ProcessBlockedError(errors.Annotatef(err, "cannot %s", change.description), block.BlockChange)
+		}
+		ctx.Infof("Applied: %s", change.description)
+	}
+	if bundleDiff != nil {
+		if err := c.bundleApplier.DeployBundle(ctx, desired.Bundle, filepath.Dir(path)); err != nil {
+			return errors.Annotate(err, "deploying bundle")
+		}
+		ctx.Infof("Applied: deploy bundle changes")
+	}
+	return nil
+}
+
+// deployBundleApplier compares bundles with the model using the bundle
+// changes machinery used by diff-bundle, and deploys them with the
+// deploy command.
+type deployBundleApplier struct {
+	command *applyModelSpecCommand
+}
+
+// DiffBundle is part of BundleApplier.
+func (a *deployBundleApplier) DiffBundle(bundleYAML, bundleDir string) (*bundlechanges.BundleDiff, error) {
+	source, err := charm.StreamBundleDataSource(strings.NewReader(bundleYAML), bundleDir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	data, err := appbundle.ComposeAndVerifyBundle(source, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	root, err := a.command.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer root.Close()
+
+	status, err := root.Client().Status(nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting model status")
+	}
+	extractor := &modelSpecExtractor{
+		application: application.NewClient(root),
+		annotations: annotations.NewClient(root),
+		modelConfig: modelconfig.NewClient(root),
+	}
+	model, err := appbundle.BuildModelRepresentation(status, extractor, true, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	diff, err := bundlechanges.BuildDiff(bundlechanges.DiffConfig{
+		Bundle: data,
+		Model:  model,
+		Logger: logger,
+	})
+	return diff, errors.Trace(err)
+}
+
+// DeployBundle is part of BundleApplier.
+func (a *deployBundleApplier) DeployBundle(ctx *cmd.Context, bundleYAML, bundleDir string) error {
+	// The bundle is written next to the model spec, so that the paths
+	// of any local charms resolve as they did when it was compared.
+	file, err := ioutil.TempFile(bundleDir, ".model-spec-bundle-*.yaml")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(bundleYAML)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	controllerName, err := a.command.ControllerName()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	modelName, err := a.command.ModelIdentifier()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	deploy := appcmd.NewDeployCommand()
+	args := []string{"-m", controllerName + ":" + modelName, file.Name()}
+	if code := cmd.Main(deploy, ctx, args); code != 0 {
+		return errors.Errorf("deploy exited with code %d", code)
+	}
+	return nil
+}
+
+// modelSpecExtractor implements bundle.ModelExtractor for comparing the
+// bundle of a model spec with the model.
+type modelSpecExtractor struct {
+	application *application.Client
+	annotations *annotations.Client
+	modelConfig *modelconfig.Client
+}
+
+// GetAnnotations is part of ModelExtractor.
+func (e *modelSpecExtractor) GetAnnotations(tags []string) ([]params.AnnotationsGetResult, error) {
+	return e.annotations.Get(tags)
+}
+
+// GetConstraints is part of ModelExtractor.
+func (e *modelSpecExtractor) GetConstraints(applications ...string) ([]constraints.Value, error) {
+	return e.application.GetConstraints(applications...)
+}
+
+// GetConfig is part of ModelExtractor.
+func (e *modelSpecExtractor) GetConfig(branchName string, applications ...string) ([]map[string]interface{}, error) {
+	return e.application.GetConfig(branchName, applications...)
+}
+
+// Sequences is part of ModelExtractor.
+func (e *modelSpecExtractor) Sequences() (map[string]int, error) {
+	return e.modelConfig.Sequences()
+}
@@ -0,0 +1,296 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"io/ioutil"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/api/bundledrift"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const setDesiredBundleDoc = `
+Sets the bundle, with any overlays applied in order, that the model is
+expected to match. The controller periodically compares the model with
+the desired bundle, in the same way as the diff-bundle command, and sets
+the model status message when they differ. Annotations are not compared.
+
+The bundle and overlays are read from local files and stored by the
+controller; they must be self-contained, so bundles which refer to
+local charms or include other files with include-file:// or
+include-base64:// are not accepted.
+
+Examples:
+
+    juju set-desired-bundle ./bundle.yaml
+    juju set-desired-bundle ./bundle.yaml --overlay ./production.yaml
+
+See also:
+    show-bundle-drift
+    remove-desired-bundle
+    diff-bundle
+`
+
+const removeDesiredBundleDoc = `
+Removes the bundle the model is expected to match, so the controller no
+longer compares the model with it.
+
+Examples:
+
+    juju remove-desired-bundle
+
+See also:
+    set-desired-bundle
+    show-bundle-drift
+`
+
+const showBundleDriftDoc = `
+Shows how the model differed from its desired bundle when the controller
+last compared them. Differences are shown in the same form as by the
+diff-bundle command.
+
+Examples:
+
+    juju show-bundle-drift
+    juju show-bundle-drift --format json
+
+See also:
+    set-desired-bundle
+    remove-desired-bundle
+    diff-bundle
+`
+
+// BundleDriftAPI defines the API methods used by the bundle drift
+// commands.
+type BundleDriftAPI interface {
+	SetDesiredBundle(bundle string, overlays []string) error
+	RemoveDesiredBundle() error
+	BundleDrift() (params.BundleDriftResult, error)
+	Close() error
+}
+
+// bundleDriftCommandBase holds what is common to the bundle drift
+// commands.
+type bundleDriftCommandBase struct {
+	modelcmd.ModelCommandBase
+	api BundleDriftAPI
+}
+
+func (c *bundleDriftCommandBase) getAPI() (BundleDriftAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return bundledrift.NewClient(root), nil
+}
+
+// NewSetDesiredBundleCommand returns a command that sets the bundle a
+// model is expected to match.
+func NewSetDesiredBundleCommand() cmd.Command {
+	return modelcmd.Wrap(&setDesiredBundleCommand{})
+}
+
+type setDesiredBundleCommand struct {
+	bundleDriftCommandBase
+
+	bundleFile   string
+	overlayFiles []string
+}
+
+// Info implements Command.Info.
+func (c *setDesiredBundleCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "set-desired-bundle",
+		Args:    "<bundle file>",
+		Purpose: "Sets the bundle the model is expected to match.",
+		Doc:     setDesiredBundleDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *setDesiredBundleCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.Var(cmd.NewAppendStringsValue(&c.overlayFiles), "overlay", "Bundles to overlay on the primary bundle, applied in order")
+}
+
+// Init implements Command.Init.
+func (c *setDesiredBundleCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no bundle file specified")
+	}
+	c.bundleFile = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *setDesiredBundleCommand) Run(ctx *cmd.Context) error {
+	bundle, err := ioutil.ReadFile(ctx.AbsPath(c.bundleFile))
+	if err != nil {
+		return errors.Annotate(err, "reading bundle")
+	}
+	var overlays []string
+	for _, path := range c.overlayFiles {
+		overlay, err := ioutil.ReadFile(ctx.AbsPath(path))
+		if err != nil {
+			return errors.Annotatef(err, "reading overlay %q", path)
+		}
+		overlays = append(overlays, string(overlay))
+	}
+
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	if err := client.SetDesiredBundle(string(bundle), overlays); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Desired bundle set; the model will be compared with it shortly.")
+	return nil
+}
+
+// NewRemoveDesiredBundleCommand returns a command that removes the
+// bundle a model is expected to match.
+func NewRemoveDesiredBundleCommand() cmd.Command {
+	return modelcmd.Wrap(&removeDesiredBundleCommand{})
+}
+
+type removeDesiredBundleCommand struct {
+	bundleDriftCommandBase
+}
+
+// Info implements Command.Info.
+func (c *removeDesiredBundleCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "remove-desired-bundle",
+		Purpose: "Removes the bundle the model is expected to match.",
+		Doc:     removeDesiredBundleDoc,
+	})
+}
+
+// Run implements Command.Run.
+func (c *removeDesiredBundleCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	if err := client.RemoveDesiredBundle(); err != nil {
+		if params.IsCodeNotFound(err) {
+			return errors.New("model has no desired bundle")
+		}
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	return nil
+}
+
+// NewShowBundleDriftCommand returns a command that shows how a model
+// has drifted from its desired bundle.
+func NewShowBundleDriftCommand() cmd.Command {
+	return modelcmd.Wrap(&showBundleDriftCommand{})
+}
+
+type showBundleDriftCommand struct {
+	bundleDriftCommandBase
+	out     cmd.Output
+	isoTime bool
+}
+
+// Info implements Command.Info.
+func (c *showBundleDriftCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "show-bundle-drift",
+		Purpose: "Shows how the model has drifted from its desired bundle.",
+		Doc:     showBundleDriftDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *showBundleDriftCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.isoTime, "utc", false, "Display time as UTC in RFC3339 format")
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"json": cmd.FormatJson,
+		"yaml": cmd.FormatYaml,
+	})
+}
+
+// The states of a model's drift from its desired bundle, as displayed
+// by the show-bundle-drift command.
+const (
+	driftNotChecked  = "not checked"
+	driftInSync      = "in sync"
+	driftDrifted     = "drifted"
+	driftCheckFailed = "check failed"
+)
+
+// bundleDriftInfo is the drift of a model from its desired bundle, as
+// displayed by the show-bundle-drift command.
+type bundleDriftInfo struct {
+	Status      string      `yaml:"status" json:"status"`
+	SetBy       string      `yaml:"set-by" json:"set-by"`
+	Set         string      `yaml:"set" json:"set"`
+	Overlays    int         `yaml:"overlays,omitempty" json:"overlays,omitempty"`
+	Checked     string      `yaml:"checked,omitempty" json:"checked,omitempty"`
+	Error       string      `yaml:"error,omitempty" json:"error,omitempty"`
+	Differences interface{} `yaml:"differences,omitempty" json:"differences,omitempty"`
+}
+
+// Run implements Command.Run.
+func (c *showBundleDriftCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	result, err := client.BundleDrift()
+	if params.IsCodeNotFound(err) {
+		return errors.New("model has no desired bundle, see set-desired-bundle")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	info := bundleDriftInfo{
+		Status:   driftNotChecked,
+		SetBy:    result.SetBy,
+		Set:      common.FormatTime(&result.Set, c.isoTime),
+		Overlays: len(result.Overlays),
+	}
+	if result.Checked != nil {
+		info.Checked = common.FormatTime(result.Checked, c.isoTime)
+		switch {
+		case result.CheckError != "":
+			info.Status = driftCheckFailed
+			info.Error = result.CheckError
+		case result.Diff != "":
+			info.Status = driftDrifted
+			// The differences are re-read so they're displayed in
+			// the requested format.
+			var differences interface{}
+			if err := yaml.Unmarshal([]byte(result.Diff), &differences); err != nil {
+				return errors.Annotate(err, "reading differences")
+			}
+			if info.Differences, err = common.ConformYAML(differences); err != nil {
+				return errors.Annotate(err, "reading differences")
+			}
+		default:
+			info.Status = driftInSync
+		}
+	}
+	return errors.Trace(c.out.Write(ctx, info))
+}
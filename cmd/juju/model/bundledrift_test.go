@@ -0,0 +1,205 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/model"
+	coremodel "github.com/juju/juju/core/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type BundleDriftCommandSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake  fakeBundleDriftClient
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&BundleDriftCommandSuite{})
+
+type fakeBundleDriftClient struct {
+	gitjujutesting.Stub
+	result params.BundleDriftResult
+}
+
+func (f *fakeBundleDriftClient) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeBundleDriftClient) SetDesiredBundle(bundle string, overlays []string) error {
+	f.MethodCall(f, "SetDesiredBundle", bundle, overlays)
+	return f.NextErr()
+}
+
+func (f *fakeBundleDriftClient) RemoveDesiredBundle() error {
+	f.MethodCall(f, "RemoveDesiredBundle")
+	return f.NextErr()
+}
+
+func (f *fakeBundleDriftClient) BundleDrift() (params.BundleDriftResult, error) {
+	f.MethodCall(f, "BundleDrift")
+	return f.result, f.NextErr()
+}
+
+func (s *BundleDriftCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = fakeBundleDriftClient{
+		result: params.BundleDriftResult{
+			Bundle:   "applications: {}\n",
+			Overlays: []string{"applications: {}\n"},
+			SetBy:    "bob",
+			Set:      time.Date(2021, 5, 6, 7, 0, 0, 0, time.UTC),
+		},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		ModelUUID: testing.ModelTag.Id(),
+		ModelType: coremodel.IAAS,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+}
+
+func (s *BundleDriftCommandSuite) runSet(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, model.NewSetDesiredBundleCommandForTest(&s.fake, s.store), args...)
+}
+
+func (s *BundleDriftCommandSuite) runRemove(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, model.NewRemoveDesiredBundleCommandForTest(&s.fake, s.store), args...)
+}
+
+func (s *BundleDriftCommandSuite) runShow(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, model.NewShowBundleDriftCommandForTest(&s.fake, s.store), args...)
+}
+
+func (s *BundleDriftCommandSuite) writeFile(c *gc.C, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *BundleDriftCommandSuite) TestSetDesiredBundle(c *gc.C) {
+	dir := c.MkDir()
+	bundlePath := s.writeFile(c, dir, "bundle.yaml", "bundle")
+	overlay1 := s.writeFile(c, dir, "overlay1.yaml", "overlay 1")
+	overlay2 := s.writeFile(c, dir, "overlay2.yaml", "overlay 2")
+	ctx, err := s.runSet(c, bundlePath, "--overlay", overlay1, "--overlay", overlay2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "Desired bundle set; the model will be compared with it shortly.\n")
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"SetDesiredBundle", []interface{}{"bundle", []string{"overlay 1", "overlay 2"}}},
+		{"Close", nil},
+	})
+}
+
+func (s *BundleDriftCommandSuite) TestSetDesiredBundleMissingFile(c *gc.C) {
+	_, err := s.runSet(c, filepath.Join(c.MkDir(), "missing.yaml"))
+	c.Assert(err, gc.ErrorMatches, "reading bundle: .*no such file or directory")
+	s.fake.CheckNoCalls(c)
+}
+
+func (s *BundleDriftCommandSuite) TestSetDesiredBundleError(c *gc.C) {
+	s.fake.SetErrors(errors.New("invalid desired bundle: boom"))
+	bundlePath := s.writeFile(c, c.MkDir(), "bundle.yaml", "bundle")
+	_, err := s.runSet(c, bundlePath)
+	c.Assert(err, gc.ErrorMatches, "invalid desired bundle: boom")
+}
+
+func (s *BundleDriftCommandSuite) TestSetDesiredBundleInitErrors(c *gc.C) {
+	_, err := s.runSet(c)
+	c.Assert(err, gc.ErrorMatches, "no bundle file specified")
+	_, err = s.runSet(c, "bundle.yaml", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *BundleDriftCommandSuite) TestRemoveDesiredBundle(c *gc.C) {
+	_, err := s.runRemove(c)
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCallNames(c, "RemoveDesiredBundle", "Close")
+}
+
+func (s *BundleDriftCommandSuite) TestRemoveDesiredBundleNotFound(c *gc.C) {
+	s.fake.SetErrors(&params.Error{Code: params.CodeNotFound, Message: "desired bundle not found"})
+	_, err := s.runRemove(c)
+	c.Assert(err, gc.ErrorMatches, "model has no desired bundle")
+}
+
+func (s *BundleDriftCommandSuite) TestShowBundleDriftNotChecked(c *gc.C) {
+	ctx, err := s.runShow(c, "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"status: not checked\n"+
+		"set-by: bob\n"+
+		"set: 2021-05-06 07:00:00Z\n"+
+		"overlays: 1\n")
+}
+
+func (s *BundleDriftCommandSuite) TestShowBundleDriftInSync(c *gc.C) {
+	checked := time.Date(2021, 5, 6, 7, 5, 0, 0, time.UTC)
+	s.fake.result.Checked = &checked
+	ctx, err := s.runShow(c, "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"status: in sync\n"+
+		"set-by: bob\n"+
+		"set: 2021-05-06 07:00:00Z\n"+
+		"overlays: 1\n"+
+		"checked: 2021-05-06 07:05:00Z\n")
+}
+
+func (s *BundleDriftCommandSuite) TestShowBundleDriftDrifted(c *gc.C) {
+	checked := time.Date(2021, 5, 6, 7, 5, 0, 0, time.UTC)
+	s.fake.result.Checked = &checked
+	s.fake.result.Diff = `
+applications:
+  mysql:
+    options:
+      max-connections:
+        bundle: null
+        model: 500
+`[1:]
+	ctx, err := s.runShow(c, "--utc", "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `{"status":"drifted","set-by":"bob","set":"2021-05-06 07:00:00Z","overlays":1,"checked":"2021-05-06 07:05:00Z","differences":{"applications":{"mysql":{"options":{"max-connections":{"bundle":null,"model":500}}}}}}`+"\n")
+}
+
+func (s *BundleDriftCommandSuite) TestShowBundleDriftCheckFailed(c *gc.C) {
+	checked := time.Date(2021, 5, 6, 7, 5, 0, 0, time.UTC)
+	s.fake.result.Overlays = nil
+	s.fake.result.Checked = &checked
+	s.fake.result.CheckError = `reading bundle: charm "cs:nope" not found`
+	ctx, err := s.runShow(c, "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"status: check failed\n"+
+		"set-by: bob\n"+
+		"set: 2021-05-06 07:00:00Z\n"+
+		"checked: 2021-05-06 07:05:00Z\n"+
+		`error: 'reading bundle: charm "cs:nope" not found'`+"\n")
+}
+
+func (s *BundleDriftCommandSuite) TestShowBundleDriftNotFound(c *gc.C) {
+	s.fake.SetErrors(&params.Error{Code: params.CodeNotFound, Message: "desired bundle not found"})
+	_, err := s.runShow(c)
+	c.Assert(err, gc.ErrorMatches, "model has no desired bundle, see set-desired-bundle")
+}
@@ -0,0 +1,461 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/api/confighistory"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+const configHistoryDoc = `
+Shows the recorded revisions of an application's charm config or, if no
+application is given, of the model config. A revision is recorded each
+time the config is changed with the config or model-config commands, or
+rolled back. Changes made in other ways, such as by deploying with
+config, deploying a bundle or committing a branch, are recorded without
+an author when the config is next changed with those commands. Changes
+to model defaults are not recorded.
+
+Examples:
+
+    juju config-history
+    juju config-history mysql
+    juju config-history mysql --format yaml
+
+See also:
+    diff-config
+    rollback-config
+`
+
+const diffConfigDoc = `
+Shows the config settings which differ between two recorded revisions of
+an application's charm config or, if no application is given, of the
+model config. If only one revision is given, it is compared with the
+latest revision. Settings which aren't set in a revision are shown as
+"(unset)".
+
+Examples:
+
+    juju diff-config 3
+    juju diff-config mysql 2 5
+
+See also:
+    config-history
+    rollback-config
+`
+
+const rollbackConfigDoc = `
+Sets an application's charm config or, if no application is given, the
+model config back to that of a recorded revision. The rollback is itself
+recorded as a new revision. The agent-version and charmhub-url model
+config settings are never rolled back.
+
+Examples:
+
+    juju rollback-config 3
+    juju rollback-config mysql 2
+
+See also:
+    config-history
+    diff-config
+`
+
+// ConfigHistoryAPI defines the API methods used by the config history
+// commands.
+type ConfigHistoryAPI interface {
+	ConfigRevisions(names.Tag) ([]params.ConfigRevision, error)
+	RollbackConfig(names.Tag, int) error
+	Close() error
+}
+
+// configHistoryCommandBase holds what is common to the config history
+// commands.
+type configHistoryCommandBase struct {
+	modelcmd.ModelCommandBase
+	api ConfigHistoryAPI
+
+	applicationName string
+}
+
+func (c *configHistoryCommandBase) getAPI() (ConfigHistoryAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return confighistory.NewClient(root), nil
+}
+
+// initApplication consumes the optional application name at the start
+// of the args. Application names can't be numbers, so any other first
+// arg is taken to be a revision.
+func (c *configHistoryCommandBase) initApplication(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return args, nil
+	}
+	if _, err := strconv.Atoi(args[0]); err == nil {
+		return args, nil
+	}
+	if !names.IsValidApplication(args[0]) {
+		return nil, errors.NotValidf("application name %q", args[0])
+	}
+	c.applicationName = args[0]
+	return args[1:], nil
+}
+
+// tag returns the tag of the application, or model, whose config
+// history the command acts on.
+func (c *configHistoryCommandBase) tag() (names.Tag, error) {
+	if c.applicationName != "" {
+		return names.NewApplicationTag(c.applicationName), nil
+	}
+	_, details, err := c.ModelDetails()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return names.NewModelTag(details.ModelUUID), nil
+}
+
+// configRevisions returns the recorded config revisions of the
+// application, or model.
+func (c *configHistoryCommandBase) configRevisions(client ConfigHistoryAPI) ([]params.ConfigRevision, error) {
+	tag, err := c.tag()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	revisions, err := client.ConfigRevisions(tag)
+	return revisions, errors.Trace(err)
+}
+
+func parseRevision(arg string) (int, error) {
+	revision, err := strconv.Atoi(arg)
+	if err != nil || revision < 1 {
+		return 0, errors.NotValidf("revision %q", arg)
+	}
+	return revision, nil
+}
+
+// NewConfigHistoryCommand returns a command that lists the recorded
+// config revisions of an application or model.
+func NewConfigHistoryCommand() cmd.Command {
+	return modelcmd.Wrap(&configHistoryCommand{})
+}
+
+type configHistoryCommand struct {
+	configHistoryCommandBase
+	out     cmd.Output
+	isoTime bool
+}
+
+// Info implements Command.Info.
+func (c *configHistoryCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "config-history",
+		Args:    "[<application>]",
+		Purpose: "Lists the recorded revisions of application or model config.",
+		Doc:     configHistoryDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *configHistoryCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.BoolVar(&c.isoTime, "utc", false, "Display time as UTC in RFC3339 format")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"json":    cmd.FormatJson,
+		"tabular": c.formatTabular,
+		"yaml":    cmd.FormatYaml,
+	})
+}
+
+// Init implements Command.Init.
+func (c *configHistoryCommand) Init(args []string) error {
+	args, err := c.initApplication(args)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// configRevisionInfo is a config revision, as displayed by the
+// config-history command.
+type configRevisionInfo struct {
+	Revision int                    `yaml:"revision" json:"revision"`
+	Author   string                 `yaml:"author,omitempty" json:"author,omitempty"`
+	Created  string                 `yaml:"created" json:"created"`
+	Config   map[string]interface{} `yaml:"config" json:"config"`
+}
+
+// Run implements Command.Run.
+func (c *configHistoryCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	revisions, err := c.configRevisions(client)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(revisions) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No config revisions to display.")
+		return nil
+	}
+	result := make([]configRevisionInfo, len(revisions))
+	for i, revision := range revisions {
+		result[i] = configRevisionInfo{
+			Revision: revision.Revision,
+			Author:   revision.Author,
+			Created:  common.FormatTime(&revision.Created, c.isoTime),
+			Config:   revision.Config,
+		}
+	}
+	return errors.Trace(c.out.Write(ctx, result))
+}
+
+func (c *configHistoryCommand) formatTabular(writer io.Writer, value interface{}) error {
+	revisions, ok := value.([]configRevisionInfo)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", revisions, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Revision", "Created", "Author", "Settings")
+	for _, revision := range revisions {
+		author := revision.Author
+		if author == "" {
+			author = "-"
+		}
+		w.Println(revision.Revision, revision.Created, author, len(revision.Config))
+	}
+	return tw.Flush()
+}
+
+// NewDiffConfigCommand returns a command that shows the differences
+// between two recorded config revisions of an application or model.
+func NewDiffConfigCommand() cmd.Command {
+	return modelcmd.Wrap(&diffConfigCommand{})
+}
+
+type diffConfigCommand struct {
+	configHistoryCommandBase
+	out cmd.Output
+
+	from, to int
+}
+
+// Info implements Command.Info.
+func (c *diffConfigCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "diff-config",
+		Args:    "[<application>] <revision> [<revision>]",
+		Purpose: "Compares two recorded revisions of application or model config.",
+		Doc:     diffConfigDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *diffConfigCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"json":    cmd.FormatJson,
+		"tabular": c.formatTabular,
+		"yaml":    cmd.FormatYaml,
+	})
+}
+
+// Init implements Command.Init.
+func (c *diffConfigCommand) Init(args []string) error {
+	args, err := c.initApplication(args)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(args) == 0 {
+		return errors.New("no revision specified")
+	}
+	if c.from, err = parseRevision(args[0]); err != nil {
+		return errors.Trace(err)
+	}
+	if len(args) > 1 {
+		if c.to, err = parseRevision(args[1]); err != nil {
+			return errors.Trace(err)
+		}
+		return cmd.CheckEmpty(args[2:])
+	}
+	return nil
+}
+
+// configDiff holds the differences between two config revisions, as
+// displayed by the diff-config command.
+type configDiff struct {
+	From    int                      `yaml:"from" json:"from"`
+	To      int                      `yaml:"to" json:"to"`
+	Changes map[string]settingChange `yaml:"changes" json:"changes"`
+}
+
+// settingChange holds the values of a setting in two config revisions.
+// A nil value means the setting isn't set in the revision.
+type settingChange struct {
+	From interface{} `yaml:"from,omitempty" json:"from,omitempty"`
+	To   interface{} `yaml:"to,omitempty" json:"to,omitempty"`
+}
+
+// Run implements Command.Run.
+func (c *diffConfigCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	revisions, err := c.configRevisions(client)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(revisions) == 0 {
+		return errors.New("no config revisions recorded")
+	}
+	to := c.to
+	if to == 0 {
+		to = revisions[len(revisions)-1].Revision
+	}
+	configs := make(map[int]map[string]interface{})
+	for _, revision := range revisions {
+		configs[revision.Revision] = revision.Config
+	}
+	for _, revision := range []int{c.from, to} {
+		if _, ok := configs[revision]; !ok {
+			return errors.NotFoundf("config revision %d", revision)
+		}
+	}
+	diff := diffConfigs(c.from, configs[c.from], to, configs[to])
+	if len(diff.Changes) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No differences between revisions %d and %d.", c.from, to)
+		return nil
+	}
+	return errors.Trace(c.out.Write(ctx, diff))
+}
+
+// diffConfigs returns the settings whose values differ between the two
+// configs.
+func diffConfigs(fromRevision int, from map[string]interface{}, toRevision int, to map[string]interface{}) configDiff {
+	diff := configDiff{
+		From:    fromRevision,
+		To:      toRevision,
+		Changes: make(map[string]settingChange),
+	}
+	for name, value := range from {
+		if !reflect.DeepEqual(value, to[name]) {
+			diff.Changes[name] = settingChange{From: value, To: to[name]}
+		}
+	}
+	for name, value := range to {
+		if _, ok := from[name]; !ok {
+			diff.Changes[name] = settingChange{To: value}
+		}
+	}
+	return diff
+}
+
+func (c *diffConfigCommand) formatTabular(writer io.Writer, value interface{}) error {
+	diff, ok := value.(configDiff)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", diff, value)
+	}
+	settings := make([]string, 0, len(diff.Changes))
+	for name := range diff.Changes {
+		settings = append(settings, name)
+	}
+	sort.Strings(settings)
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Setting", fmt.Sprintf("Revision %d", diff.From), fmt.Sprintf("Revision %d", diff.To))
+	for _, name := range settings {
+		change := diff.Changes[name]
+		w.Println(name, formatSettingValue(change.From), formatSettingValue(change.To))
+	}
+	return tw.Flush()
+}
+
+func formatSettingValue(value interface{}) string {
+	if value == nil {
+		return "(unset)"
+	}
+	return fmt.Sprint(value)
+}
+
+// NewRollbackConfigCommand returns a command that rolls the config of
+// an application or model back to a recorded revision.
+func NewRollbackConfigCommand() cmd.Command {
+	return modelcmd.Wrap(&rollbackConfigCommand{})
+}
+
+type rollbackConfigCommand struct {
+	configHistoryCommandBase
+	revision int
+}
+
+// Info implements Command.Info.
+func (c *rollbackConfigCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "rollback-config",
+		Args:    "[<application>] <revision>",
+		Purpose: "Rolls application or model config back to a recorded revision.",
+		Doc:     rollbackConfigDoc,
+	})
+}
+
+// Init implements Command.Init.
+func (c *rollbackConfigCommand) Init(args []string) error {
+	args, err := c.initApplication(args)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(args) == 0 {
+		return errors.New("no revision specified")
+	}
+	if c.revision, err = parseRevision(args[0]); err != nil {
+		return errors.Trace(err)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *rollbackConfigCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	tag, err := c.tag()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := client.RollbackConfig(tag, c.revision); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("Rolled back config to revision %d", c.revision)
+	return nil
+}
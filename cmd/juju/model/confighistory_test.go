@@ -0,0 +1,212 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/names/v4"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/model"
+	coremodel "github.com/juju/juju/core/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type ConfigHistoryCommandSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake  fakeConfigHistoryClient
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&ConfigHistoryCommandSuite{})
+
+type fakeConfigHistoryClient struct {
+	gitjujutesting.Stub
+	revisions []params.ConfigRevision
+}
+
+func (f *fakeConfigHistoryClient) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeConfigHistoryClient) ConfigRevisions(tag names.Tag) ([]params.ConfigRevision, error) {
+	f.MethodCall(f, "ConfigRevisions", tag)
+	return f.revisions, f.NextErr()
+}
+
+func (f *fakeConfigHistoryClient) RollbackConfig(tag names.Tag, revision int) error {
+	f.MethodCall(f, "RollbackConfig", tag, revision)
+	return f.NextErr()
+}
+
+func (s *ConfigHistoryCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = fakeConfigHistoryClient{
+		revisions: []params.ConfigRevision{{
+			Revision: 1,
+			Config:   map[string]interface{}{"blog-title": "first"},
+			Author:   "bob",
+			Created:  time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+		}, {
+			Revision: 2,
+			Config:   map[string]interface{}{"blog-title": "second", "port": 8080},
+			Created:  time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC),
+		}, {
+			Revision: 3,
+			Config:   map[string]interface{}{"blog-title": "first", "skill": "high"},
+			Author:   "alice",
+			Created:  time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		ModelUUID: testing.ModelTag.Id(),
+		ModelType: coremodel.IAAS,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+}
+
+func (s *ConfigHistoryCommandSuite) runHistory(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, model.NewConfigHistoryCommandForTest(&s.fake, s.store), args...)
+}
+
+func (s *ConfigHistoryCommandSuite) runDiff(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, model.NewDiffConfigCommandForTest(&s.fake, s.store), args...)
+}
+
+func (s *ConfigHistoryCommandSuite) runRollback(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, model.NewRollbackConfigCommandForTest(&s.fake, s.store), args...)
+}
+
+func (s *ConfigHistoryCommandSuite) TestConfigHistoryTabular(c *gc.C) {
+	ctx, err := s.runHistory(c, "wordpress", "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"Revision  Created               Author  Settings\n"+
+		"1         2021-01-02 00:00:00Z  bob     1\n"+
+		"2         2021-01-03 00:00:00Z  -       2\n"+
+		"3         2021-01-04 00:00:00Z  alice   2\n"+
+		"\n")
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"ConfigRevisions", []interface{}{names.NewApplicationTag("wordpress")}},
+		{"Close", nil},
+	})
+}
+
+func (s *ConfigHistoryCommandSuite) TestConfigHistoryModelYAML(c *gc.C) {
+	s.fake.revisions = s.fake.revisions[:1]
+	ctx, err := s.runHistory(c, "--utc", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"- revision: 1\n"+
+		"  author: bob\n"+
+		"  created: 2021-01-02 00:00:00Z\n"+
+		"  config:\n"+
+		"    blog-title: first\n")
+	s.fake.CheckCall(c, 0, "ConfigRevisions", testing.ModelTag)
+}
+
+func (s *ConfigHistoryCommandSuite) TestConfigHistoryNone(c *gc.C) {
+	s.fake.revisions = nil
+	ctx, err := s.runHistory(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No config revisions to display.\n")
+}
+
+func (s *ConfigHistoryCommandSuite) TestConfigHistoryInitErrors(c *gc.C) {
+	_, err := s.runHistory(c, "Word_press")
+	c.Assert(err, gc.ErrorMatches, `application name "Word_press" not valid`)
+	_, err = s.runHistory(c, "wordpress", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *ConfigHistoryCommandSuite) TestDiffConfig(c *gc.C) {
+	ctx, err := s.runDiff(c, "wordpress", "1", "2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"Setting     Revision 1  Revision 2\n"+
+		"blog-title  first       second\n"+
+		"port        (unset)     8080\n"+
+		"\n")
+}
+
+func (s *ConfigHistoryCommandSuite) TestDiffConfigLatest(c *gc.C) {
+	ctx, err := s.runDiff(c, "2", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"from: 2\n"+
+		"to: 3\n"+
+		"changes:\n"+
+		"  blog-title:\n"+
+		"    from: second\n"+
+		"    to: first\n"+
+		"  port:\n"+
+		"    from: 8080\n"+
+		"  skill:\n"+
+		"    to: high\n")
+	s.fake.CheckCall(c, 0, "ConfigRevisions", testing.ModelTag)
+}
+
+func (s *ConfigHistoryCommandSuite) TestDiffConfigNoDifferences(c *gc.C) {
+	ctx, err := s.runDiff(c, "1", "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No differences between revisions 1 and 1.\n")
+}
+
+func (s *ConfigHistoryCommandSuite) TestDiffConfigRevisionNotFound(c *gc.C) {
+	_, err := s.runDiff(c, "1", "7")
+	c.Assert(err, gc.ErrorMatches, "config revision 7 not found")
+}
+
+func (s *ConfigHistoryCommandSuite) TestDiffConfigInitErrors(c *gc.C) {
+	_, err := s.runDiff(c)
+	c.Assert(err, gc.ErrorMatches, "no revision specified")
+	_, err = s.runDiff(c, "wordpress")
+	c.Assert(err, gc.ErrorMatches, "no revision specified")
+	_, err = s.runDiff(c, "wordpress", "0")
+	c.Assert(err, gc.ErrorMatches, `revision "0" not valid`)
+	_, err = s.runDiff(c, "1", "2", "3")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["3"\]`)
+}
+
+func (s *ConfigHistoryCommandSuite) TestRollbackConfig(c *gc.C) {
+	ctx, err := s.runRollback(c, "wordpress", "2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "Rolled back config to revision 2\n")
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"RollbackConfig", []interface{}{names.NewApplicationTag("wordpress"), 2}},
+		{"Close", nil},
+	})
+}
+
+func (s *ConfigHistoryCommandSuite) TestRollbackModelConfig(c *gc.C) {
+	_, err := s.runRollback(c, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCall(c, 0, "RollbackConfig", testing.ModelTag, 1)
+}
+
+func (s *ConfigHistoryCommandSuite) TestRollbackConfigInitErrors(c *gc.C) {
+	_, err := s.runRollback(c)
+	c.Assert(err, gc.ErrorMatches, "no revision specified")
+	_, err = s.runRollback(c, "wordpress", "two")
+	c.Assert(err, gc.ErrorMatches, `revision "two" not valid`)
+	_, err = s.runRollback(c, "wordpress", "2", "3")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["3"\]`)
+}
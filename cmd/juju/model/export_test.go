@@ -246,3 +246,110 @@ func NewShowCommitCommandForTest(api ShowCommitCommandAPI, store jujuclient.Clie
 	cmd.SetClientStore(store)
 	return modelcmd.Wrap(cmd)
 }
+
+// NewModelQuotasCommandForTest returns a modelQuotasCommand with the api
+// provided as specified.
+func NewModelQuotasCommandForTest(api QuotasAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &modelQuotasCommand{quotasCommandBase: quotasCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewSetModelQuotasCommandForTest returns a setModelQuotasCommand with
+// the api provided as specified.
+func NewSetModelQuotasCommandForTest(api QuotasAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &setModelQuotasCommand{quotasCommandBase: quotasCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewAddWebhookCommandForTest returns an addWebhookCommand with the api
+// provided as specified.
+func NewAddWebhookCommandForTest(api WebhooksAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &addWebhookCommand{webhooksCommandBase: webhooksCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewWebhooksCommandForTest returns a webhooksCommand with the api
+// provided as specified.
+func NewWebhooksCommandForTest(api WebhooksAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &webhooksCommand{webhooksCommandBase: webhooksCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewRemoveWebhookCommandForTest returns a removeWebhookCommand with the
+// api provided as specified.
+func NewRemoveWebhookCommandForTest(api WebhooksAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &removeWebhookCommand{webhooksCommandBase: webhooksCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewConfigHistoryCommandForTest returns a configHistoryCommand with the
+// api provided as specified.
+func NewConfigHistoryCommandForTest(api ConfigHistoryAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &configHistoryCommand{configHistoryCommandBase: configHistoryCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewDiffConfigCommandForTest returns a diffConfigCommand with the api
+// provided as specified.
+func NewDiffConfigCommandForTest(api ConfigHistoryAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &diffConfigCommand{configHistoryCommandBase: configHistoryCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewRollbackConfigCommandForTest returns a rollbackConfigCommand with
+// the api provided as specified.
+func NewRollbackConfigCommandForTest(api ConfigHistoryAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &rollbackConfigCommand{configHistoryCommandBase: configHistoryCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewExportModelSpecCommandForTest returns an exportModelSpecCommand with
+// the api provided as specified.
+func NewExportModelSpecCommandForTest(api ModelSpecAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &exportModelSpecCommand{modelSpecCommandBase: modelSpecCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewApplyModelSpecCommandForTest returns an applyModelSpecCommand with
+// the api and bundle applier provided as specified.
+func NewApplyModelSpecCommandForTest(api ModelSpecAPI, applier BundleApplier, store jujuclient.ClientStore) cmd.Command {
+	cmd := &applyModelSpecCommand{
+		modelSpecCommandBase: modelSpecCommandBase{api: api},
+		bundleApplier:        applier,
+	}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewSetDesiredBundleCommandForTest returns a setDesiredBundleCommand
+// with the api provided as specified.
+func NewSetDesiredBundleCommandForTest(api BundleDriftAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &setDesiredBundleCommand{bundleDriftCommandBase: bundleDriftCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewRemoveDesiredBundleCommandForTest returns a
+// removeDesiredBundleCommand with the api provided as specified.
+func NewRemoveDesiredBundleCommandForTest(api BundleDriftAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &removeDesiredBundleCommand{bundleDriftCommandBase: bundleDriftCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewShowBundleDriftCommandForTest returns a showBundleDriftCommand with
+// the api provided as specified.
+func NewShowBundleDriftCommandForTest(api BundleDriftAPI, store jujuclient.ClientStore) cmd.Command {
+	cmd := &showBundleDriftCommand{bundleDriftCommandBase: bundleDriftCommandBase{api: api}}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
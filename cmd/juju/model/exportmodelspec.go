@@ -0,0 +1,134 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+	"gopkg.in/yaml.v2"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const exportModelSpecDoc = `
+Exports the model as a model spec: a YAML document describing the model
+config and constraints, spaces, storage pools, firewall rules and the
+cloud credential used, along with the applications, machines, relations
+and offers of the model as exported by export-bundle.
+
+Only the model config set on the model itself is exported; config
+inherited from the controller or cloud defaults is left out, as are the
+model's name, UUID, type and agent version. Secret model config, such as
+passwords, is left out too, so the spec can be shared safely.
+
+The spec can be kept under version control, edited, and applied to the
+same or another model with apply-model-spec.
+
+If --filename is not used, the spec is printed to stdout.
+
+Examples:
+
+    juju export-model-spec
+    juju export-model-spec -m mymodel --filename mymodel-spec.yaml
+
+See also:
+    apply-model-spec
+    export-bundle
+`
+
+// modelSpecCommandBase holds what is common to the model spec commands.
+type modelSpecCommandBase struct {
+	modelcmd.ModelCommandBase
+	api ModelSpecAPI
+}
+
+func (c *modelSpecCommandBase) getAPI() (ModelSpecAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	_, details, err := c.ModelDetails()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	modelRoot, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	controllerRoot, err := c.NewControllerAPIRoot()
+	if err != nil {
+		_ = modelRoot.Close()
+		return nil, errors.Trace(err)
+	}
+	return newModelSpecAPI(modelRoot, controllerRoot, names.NewModelTag(details.ModelUUID)), nil
+}
+
+// NewExportModelSpecCommand returns a command that exports a model as a
+// model spec.
+func NewExportModelSpecCommand() cmd.Command {
+	return modelcmd.Wrap(&exportModelSpecCommand{})
+}
+
+type exportModelSpecCommand struct {
+	modelSpecCommandBase
+	filename string
+}
+
+// Info implements Command.Info.
+func (c *exportModelSpecCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "export-model-spec",
+		Purpose: "Exports the model as a declarative model spec.",
+		Doc:     exportModelSpecDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *exportModelSpecCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.filename, "filename", "", "Model spec file")
+}
+
+// Init implements Command.Init.
+func (c *exportModelSpecCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// Run implements Command.Run.
+func (c *exportModelSpecCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	spec, err := readModelSpec(client, true)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	data, err := yaml.Marshal(spec)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	if c.filename == "" {
+		_, err := ctx.Stdout.Write(data)
+		return errors.Trace(err)
+	}
+	file, err := c.Filesystem().OpenFile(ctx.AbsPath(c.filename), os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Annotate(err, "while creating local file")
+	}
+	defer file.Close()
+	if _, err := file.Write(data); err != nil {
+		return errors.Annotate(err, "while writing local file")
+	}
+	fmt.Fprintln(ctx.Stdout, "Model spec successfully exported to", c.filename)
+	return nil
+}
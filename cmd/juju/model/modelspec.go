@@ -0,0 +1,536 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/bundle"
+	"github.com/juju/juju/api/firewallrules"
+	"github.com/juju/juju/api/modelconfig"
+	"github.com/juju/juju/api/modelmanager"
+	"github.com/juju/juju/api/spaces"
+	"github.com/juju/juju/api/storage"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/environs/config"
+)
+
+// modelSpec is the declarative description of a model written by
+// export-model-spec and applied by apply-model-spec.
+type modelSpec struct {
+	// Credential is the id of the cloud credential used by the model,
+	// as in "<cloud>/<owner>/<name>".
+	Credential string `yaml:"credential,omitempty"`
+
+	// Config holds the model config attributes set on the model,
+	// rather than inherited from the controller or cloud defaults.
+	Config map[string]interface{} `yaml:"config,omitempty"`
+
+	// Constraints are the model constraints.
+	Constraints string `yaml:"constraints,omitempty"`
+
+	// Spaces holds the CIDRs of the subnets in each space.
+	Spaces map[string][]string `yaml:"spaces,omitempty"`
+
+	// StoragePools holds the storage pools defined in the model.
+	StoragePools map[string]storagePoolSpec `yaml:"storage-pools,omitempty"`
+
+	// FirewallRules holds the CIDRs allowed access to each known
+	// service.
+	FirewallRules map[string][]string `yaml:"firewall-rules,omitempty"`
+
+	// Bundle holds the applications, machines, relations and offers
+	// of the model, as exported by export-bundle.
+	Bundle string `yaml:"bundle,omitempty"`
+}
+
+// storagePoolSpec describes a storage pool in a model spec.
+type storagePoolSpec struct {
+	Provider   string                 `yaml:"provider"`
+	Attributes map[string]interface{} `yaml:"attributes,omitempty"`
+}
+
+// specExcludedConfig holds the model config attributes which identify
+// a particular model, rather than describe how it is configured, or
+// which can't be changed, so are left out of model specs.
+var specExcludedConfig = map[string]bool{
+	config.NameKey:         true,
+	config.UUIDKey:         true,
+	config.TypeKey:         true,
+	config.AgentVersionKey: true,
+	config.CharmHubURLKey:  true,
+}
+
+// secretConfig returns the names of the secret model config attributes,
+// such as passwords. They're left out of model specs, so that a spec
+// can be kept without leaking them, and aren't changed by applying one.
+func secretConfig() (map[string]bool, error) {
+	fields, err := config.Schema(nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	secret := make(map[string]bool)
+	for name, field := range fields {
+		if field.Secret {
+			secret[name] = true
+		}
+	}
+	return secret, nil
+}
+
+// ModelSpecAPI defines the API methods used by the model spec commands.
+type ModelSpecAPI interface {
+	ExportBundle() (string, error)
+	ModelGetWithMetadata() (config.ConfigValues, error)
+	ModelSet(map[string]interface{}) error
+	ModelUnset(...string) error
+	GetModelConstraints() (constraints.Value, error)
+	SetModelConstraints(constraints.Value) error
+	ListSpaces() ([]params.Space, error)
+	CreateSpace(name string, cidrs []string, public bool) error
+	ListPools(providers, names []string) ([]params.StoragePool, error)
+	CreatePool(name, provider string, attrs map[string]interface{}) error
+	UpdatePool(name, provider string, attrs map[string]interface{}) error
+	ListFirewallRules() ([]params.FirewallRule, error)
+	SetFirewallRule(service string, whiteListCidrs []string) error
+	ModelCredential() (names.CloudCredentialTag, bool, error)
+	ChangeModelCredential(names.CloudCredentialTag) error
+	Close() error
+}
+
+// modelSpecAPI implements ModelSpecAPI with the clients of the facades
+// managing each part of a model spec.
+type modelSpecAPI struct {
+	modelRoot      api.Connection
+	controllerRoot api.Connection
+	modelTag       names.ModelTag
+
+	bundle        *bundle.Client
+	modelConfig   *modelconfig.Client
+	spaces        *spaces.API
+	storage       *storage.Client
+	firewallRules *firewallrules.Client
+	modelManager  *modelmanager.Client
+}
+
+func newModelSpecAPI(modelRoot, controllerRoot api.Connection, modelTag names.ModelTag) *modelSpecAPI {
+	return &modelSpecAPI{
+		modelRoot:      modelRoot,
+		controllerRoot: controllerRoot,
+		modelTag:       modelTag,
+		bundle:         bundle.NewClient(modelRoot),
+		modelConfig:    modelconfig.NewClient(modelRoot),
+		spaces:         spaces.NewAPI(modelRoot),
+		storage:        storage.NewClient(modelRoot),
+		firewallRules:  firewallrules.NewClient(modelRoot),
+		modelManager:   modelmanager.NewClient(controllerRoot),
+	}
+}
+
+func (a *modelSpecAPI) ExportBundle() (string, error) {
+	return a.bundle.ExportBundle()
+}
+
+func (a *modelSpecAPI) ModelGetWithMetadata() (config.ConfigValues, error) {
+	return a.modelConfig.ModelGetWithMetadata()
+}
+
+func (a *modelSpecAPI) ModelSet(attrs map[string]interface{}) error {
+	return a.modelConfig.ModelSet(attrs)
+}
+
+func (a *modelSpecAPI) ModelUnset(keys ...string) error {
+	return a.modelConfig.ModelUnset(keys...)
+}
+
+func (a *modelSpecAPI) GetModelConstraints() (constraints.Value, error) {
+	return a.modelRoot.Client().GetModelConstraints()
+}
+
+func (a *modelSpecAPI) SetModelConstraints(cons constraints.Value) error {
+	return a.modelRoot.Client().SetModelConstraints(cons)
+}
+
+func (a *modelSpecAPI) ListSpaces() ([]params.Space, error) {
+	return a.spaces.ListSpaces()
+}
+
+func (a *modelSpecAPI) CreateSpace(name string, cidrs []string, public bool) error {
+	return a.spaces.CreateSpace(name, cidrs, public)
+}
+
+func (a *modelSpecAPI) ListPools(providers, names []string) ([]params.StoragePool, error) {
+	return a.storage.ListPools(providers, names)
+}
+
+func (a *modelSpecAPI) CreatePool(name, provider string, attrs map[string]interface{}) error {
+	return a.storage.CreatePool(name, provider, attrs)
+}
+
+func (a *modelSpecAPI) UpdatePool(name, provider string, attrs map[string]interface{}) error {
+	return a.storage.UpdatePool(name, provider, attrs)
+}
+
+func (a *modelSpecAPI) ListFirewallRules() ([]params.FirewallRule, error) {
+	return a.firewallRules.ListFirewallRules()
+}
+
+func (a *modelSpecAPI) SetFirewallRule(service string, whiteListCidrs []string) error {
+	return a.firewallRules.SetFirewallRule(service, whiteListCidrs)
+}
+
+// ModelCredential returns the tag of the model's cloud credential, and
+// whether it has one.
+func (a *modelSpecAPI) ModelCredential() (names.CloudCredentialTag, bool, error) {
+	results, err := a.modelManager.ModelInfo([]names.ModelTag{a.modelTag})
+	if err != nil {
+		return names.CloudCredentialTag{}, false, errors.Trace(err)
+	}
+	if n := len(results); n != 1 {
+		return names.CloudCredentialTag{}, false, errors.Errorf("expected 1 result, got %d", n)
+	}
+	if err := results[0].Error; err != nil {
+		return names.CloudCredentialTag{}, false, errors.Trace(err)
+	}
+	if results[0].Result.CloudCredentialTag == "" {
+		return names.CloudCredentialTag{}, false, nil
+	}
+	tag, err := names.ParseCloudCredentialTag(results[0].Result.CloudCredentialTag)
+	if err != nil {
+		return names.CloudCredentialTag{}, false, errors.Trace(err)
+	}
+	return tag, true, nil
+}
+
+func (a *modelSpecAPI) ChangeModelCredential(credential names.CloudCredentialTag) error {
+	return a.modelManager.ChangeModelCredential(a.modelTag, credential)
+}
+
+func (a *modelSpecAPI) Close() error {
+	err := a.modelRoot.Close()
+	if err2 := a.controllerRoot.Close(); err == nil {
+		err = err2
+	}
+	return err
+}
+
+// readModelSpec reads the spec of the model through the API. The
+// bundle is only read if includeBundle is true.
+func readModelSpec(client ModelSpecAPI, includeBundle bool) (modelSpec, error) {
+	var spec modelSpec
+
+	credential, ok, err := client.ModelCredential()
+	if err != nil {
+		return spec, errors.Annotate(err, "getting model credential")
+	}
+	if ok {
+		spec.Credential = credential.Id()
+	}
+
+	values, err := client.ModelGetWithMetadata()
+	if err != nil {
+		return spec, errors.Annotate(err, "getting model config")
+	}
+	secret, err := secretConfig()
+	if err != nil {
+		return spec, errors.Trace(err)
+	}
+	for name, value := range values {
+		if value.Source != config.JujuModelConfigSource || specExcludedConfig[name] || secret[name] {
+			continue
+		}
+		if spec.Config == nil {
+			spec.Config = make(map[string]interface{})
+		}
+		spec.Config[name] = value.Value
+	}
+
+	cons, err := client.GetModelConstraints()
+	if err != nil {
+		return spec, errors.Annotate(err, "getting model constraints")
+	}
+	spec.Constraints = cons.String()
+
+	allSpaces, err := client.ListSpaces()
+	if err != nil {
+		return spec, errors.Annotate(err, "getting spaces")
+	}
+	for _, space := range allSpaces {
+		if space.Name == network.AlphaSpaceName {
+			continue
+		}
+		cidrs := make([]string, len(space.Subnets))
+		for i, subnet := range space.Subnets {
+			cidrs[i] = subnet.CIDR
+		}
+		sort.Strings(cidrs)
+		if spec.Spaces == nil {
+			spec.Spaces = make(map[string][]string)
+		}
+		spec.Spaces[space.Name] = cidrs
+	}
+
+	pools, err := client.ListPools(nil, nil)
+	if err != nil {
+		return spec, errors.Annotate(err, "getting storage pools")
+	}
+	for _, pool := range pools {
+		// The default pool of each storage provider has the name of
+		// the provider and no attributes; it needn't be created.
+		if pool.Name == pool.Provider && len(pool.Attrs) == 0 {
+			continue
+		}
+		if spec.StoragePools == nil {
+			spec.StoragePools = make(map[string]storagePoolSpec)
+		}
+		spec.StoragePools[pool.Name] = storagePoolSpec{
+			Provider:   pool.Provider,
+			Attributes: pool.Attrs,
+		}
+	}
+
+	rules, err := client.ListFirewallRules()
+	if err != nil {
+		return spec, errors.Annotate(err, "getting firewall rules")
+	}
+	for _, rule := range rules {
+		if spec.FirewallRules == nil {
+			spec.FirewallRules = make(map[string][]string)
+		}
+		spec.FirewallRules[string(rule.KnownService)] = rule.WhitelistCIDRS
+	}
+
+	if includeBundle {
+		if spec.Bundle, err = client.ExportBundle(); err != nil {
+			return spec, errors.Annotate(err, "exporting bundle")
+		}
+	}
+	return spec, nil
+}
+
+// specChange is a change made to a model to apply a model spec.
+type specChange struct {
+	description string
+	apply       func(ModelSpecAPI) error
+}
+
+// planSpecChanges returns the changes to make to the model described by
+// current, for it to match the desired spec. Parts of the model left out
+// of the desired spec are left unchanged, as are spaces, storage pools
+// and firewall rules missing from it; model config attributes missing
+// from it are reset if the spec has any config. Secret model config is
+// neither set nor reset. The bundle is not compared.
+func planSpecChanges(current, desired modelSpec) ([]specChange, error) {
+	var changes []specChange
+
+	if desired.Credential != "" && desired.Credential != current.Credential {
+		if !names.IsValidCloudCredential(desired.Credential) {
+			return nil, errors.NotValidf("cloud credential %q", desired.Credential)
+		}
+		tag := names.NewCloudCredentialTag(desired.Credential)
+		changes = append(changes, specChange{
+			description: fmt.Sprintf("change model credential to %q", desired.Credential),
+			apply: func(client ModelSpecAPI) error {
+				return client.ChangeModelCredential(tag)
+			},
+		})
+	}
+
+	if desired.Config != nil {
+		configChanges, err := planConfigChanges(current.Config, desired.Config)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		changes = append(changes, configChanges...)
+	}
+
+	if desired.Constraints != "" && desired.Constraints != current.Constraints {
+		cons, err := constraints.Parse(desired.Constraints)
+		if err != nil {
+			return nil, errors.Annotate(err, "parsing model constraints")
+		}
+		// The constraints are compared in their canonical form.
+		if cons.String() != current.Constraints {
+			changes = append(changes, specChange{
+				description: fmt.Sprintf("set model constraints %q", cons.String()),
+				apply: func(client ModelSpecAPI) error {
+					return client.SetModelConstraints(cons)
+				},
+			})
+		}
+	}
+
+	for _, name := range sortedKeys(desired.Spaces) {
+		name := name
+		cidrs := desired.Spaces[name]
+		currentCIDRs, ok := current.Spaces[name]
+		if !ok {
+			changes = append(changes, specChange{
+				description: fmt.Sprintf("create space %q with subnets %s", name, strings.Join(cidrs, ", ")),
+				apply: func(client ModelSpecAPI) error {
+					return client.CreateSpace(name, cidrs, true)
+				},
+			})
+			continue
+		}
+		if !sameStrings(cidrs, currentCIDRs) {
+			return nil, errors.Errorf(
+				"space %q has subnets %s, not %s; use move-to-space to change them",
+				name, strings.Join(currentCIDRs, ", "), strings.Join(cidrs, ", "))
+		}
+	}
+
+	for _, name := range sortedKeys(desired.StoragePools) {
+		name := name
+		pool := desired.StoragePools[name]
+		currentPool, ok := current.StoragePools[name]
+		switch {
+		case !ok:
+			changes = append(changes, specChange{
+				description: fmt.Sprintf("create storage pool %q with provider %q", name, pool.Provider),
+				apply: func(client ModelSpecAPI) error {
+					return client.CreatePool(name, pool.Provider, pool.Attributes)
+				},
+			})
+		case currentPool.Provider != pool.Provider:
+			return nil, errors.Errorf(
+				"storage pool %q has provider %q, not %q; remove it to change the provider",
+				name, currentPool.Provider, pool.Provider)
+		case !sameValues(currentPool.Attributes, pool.Attributes):
+			changes = append(changes, specChange{
+				description: fmt.Sprintf("update storage pool %q", name),
+				apply: func(client ModelSpecAPI) error {
+					return client.UpdatePool(name, pool.Provider, pool.Attributes)
+				},
+			})
+		}
+	}
+
+	for _, service := range sortedKeys(desired.FirewallRules) {
+		service := service
+		cidrs := desired.FirewallRules[service]
+		if currentCIDRs, ok := current.FirewallRules[service]; ok && sameStrings(cidrs, currentCIDRs) {
+			continue
+		}
+		changes = append(changes, specChange{
+			description: fmt.Sprintf("set firewall rule for %q to %s", service, strings.Join(cidrs, ", ")),
+			apply: func(client ModelSpecAPI) error {
+				return client.SetFirewallRule(service, cidrs)
+			},
+		})
+	}
+	return changes, nil
+}
+
+func planConfigChanges(current, desired map[string]interface{}) ([]specChange, error) {
+	secret, err := secretConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var changes []specChange
+	set := make(map[string]interface{})
+	for name, value := range desired {
+		if specExcludedConfig[name] {
+			continue
+		}
+		if secret[name] {
+			logger.Warningf("not applying secret model config %q; use model-config to set it", name)
+			continue
+		}
+		if currentValue, ok := current[name]; ok && sameValue(currentValue, value) {
+			continue
+		}
+		set[name] = value
+	}
+	var reset []string
+	for name := range current {
+		if _, ok := desired[name]; !ok && !secret[name] {
+			reset = append(reset, name)
+		}
+	}
+	sort.Strings(reset)
+
+	if len(set) > 0 {
+		names := sortedKeys(set)
+		changes = append(changes, specChange{
+			description: fmt.Sprintf("set model config %s", strings.Join(names, ", ")),
+			apply: func(client ModelSpecAPI) error {
+				return client.ModelSet(set)
+			},
+		})
+	}
+	if len(reset) > 0 {
+		changes = append(changes, specChange{
+			description: fmt.Sprintf("reset model config %s", strings.Join(reset, ", ")),
+			apply: func(client ModelSpecAPI) error {
+				return client.ModelUnset(reset...)
+			},
+		})
+	}
+	return changes, nil
+}
+
+// sameValue reports whether two config values are the same. Values read
+// from the API and from YAML can differ in type, such as in the size of
+// integers, so they're compared as strings.
+func sameValue(a, b interface{}) bool {
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+func sameValues(a, b map[string]interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, value := range a {
+		other, ok := b[name]
+		if !ok || !sameValue(value, other) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameStrings(a, b []string) bool {
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case map[string][]string:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	case map[string]storagePoolSpec:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	case map[string]interface{}:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
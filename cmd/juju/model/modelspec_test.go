@@ -0,0 +1,410 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/bundlechanges/v4"
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/model"
+	"github.com/juju/juju/core/constraints"
+	coremodel "github.com/juju/juju/core/model"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type ModelSpecCommandSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake    fakeModelSpecClient
+	applier fakeBundleApplier
+	store   *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&ModelSpecCommandSuite{})
+
+type fakeModelSpecClient struct {
+	gitjujutesting.Stub
+	bundle      string
+	config      config.ConfigValues
+	constraints constraints.Value
+	spaces      []params.Space
+	pools       []params.StoragePool
+	rules       []params.FirewallRule
+	credential  names.CloudCredentialTag
+}
+
+func (f *fakeModelSpecClient) ExportBundle() (string, error) {
+	f.MethodCall(f, "ExportBundle")
+	return f.bundle, f.NextErr()
+}
+
+func (f *fakeModelSpecClient) ModelGetWithMetadata() (config.ConfigValues, error) {
+	f.MethodCall(f, "ModelGetWithMetadata")
+	return f.config, f.NextErr()
+}
+
+func (f *fakeModelSpecClient) ModelSet(attrs map[string]interface{}) error {
+	f.MethodCall(f, "ModelSet", attrs)
+	return f.NextErr()
+}
+
+func (f *fakeModelSpecClient) ModelUnset(keys ...string) error {
+	f.MethodCall(f, "ModelUnset", keys)
+	return f.NextErr()
+}
+
+func (f *fakeModelSpecClient) GetModelConstraints() (constraints.Value, error) {
+	f.MethodCall(f, "GetModelConstraints")
+	return f.constraints, f.NextErr()
+}
+
+func (f *fakeModelSpecClient) SetModelConstraints(cons constraints.Value) error {
+	f.MethodCall(f, "SetModelConstraints", cons)
+	return f.NextErr()
+}
+
+func (f *fakeModelSpecClient) ListSpaces() ([]params.Space, error) {
+	f.MethodCall(f, "ListSpaces")
+	return f.spaces, f.NextErr()
+}
+
+func (f *fakeModelSpecClient) CreateSpace(name string, cidrs []string, public bool) error {
+	f.MethodCall(f, "CreateSpace", name, cidrs, public)
+	return f.NextErr()
+}
+
+func (f *fakeModelSpecClient) ListPools(providers, names []string) ([]params.StoragePool, error) {
+	f.MethodCall(f, "ListPools", providers, names)
+	return f.pools, f.NextErr()
+}
+
+func (f *fakeModelSpecClient) CreatePool(name, provider string, attrs map[string]interface{}) error {
+	f.MethodCall(f, "CreatePool", name, provider, attrs)
+	return f.NextErr()
+}
+
+func (f *fakeModelSpecClient) UpdatePool(name, provider string, attrs map[string]interface{}) error {
+	f.MethodCall(f, "UpdatePool", name, provider, attrs)
+	return f.NextErr()
+}
+
+func (f *fakeModelSpecClient) ListFirewallRules() ([]params.FirewallRule, error) {
+	f.MethodCall(f, "ListFirewallRules")
+	return f.rules, f.NextErr()
+}
+
+func (f *fakeModelSpecClient) SetFirewallRule(service string, whiteListCidrs []string) error {
+	f.MethodCall(f, "SetFirewallRule", service, whiteListCidrs)
+	return f.NextErr()
+}
+
+func (f *fakeModelSpecClient) ModelCredential() (names.CloudCredentialTag, bool, error) {
+	f.MethodCall(f, "ModelCredential")
+	return f.credential, f.credential != names.CloudCredentialTag{}, f.NextErr()
+}
+
+func (f *fakeModelSpecClient) ChangeModelCredential(credential names.CloudCredentialTag) error {
+	f.MethodCall(f, "ChangeModelCredential", credential)
+	return f.NextErr()
+}
+
+func (f *fakeModelSpecClient) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+type fakeBundleApplier struct {
+	gitjujutesting.Stub
+	diff *bundlechanges.BundleDiff
+}
+
+func (f *fakeBundleApplier) DiffBundle(bundleYAML, bundleDir string) (*bundlechanges.BundleDiff, error) {
+	f.MethodCall(f, "DiffBundle", bundleYAML, bundleDir)
+	return f.diff, f.NextErr()
+}
+
+func (f *fakeBundleApplier) DeployBundle(ctx *cmd.Context, bundleYAML, bundleDir string) error {
+	f.MethodCall(f, "DeployBundle", bundleYAML, bundleDir)
+	return f.NextErr()
+}
+
+func (s *ModelSpecCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = fakeModelSpecClient{
+		bundle: "applications:\n  mysql:\n    charm: mysql\n",
+		config: config.ConfigValues{
+			"name":           {Value: "mymodel", Source: config.JujuModelConfigSource},
+			"agent-version":  {Value: "2.9.0", Source: config.JujuModelConfigSource},
+			"logging-config": {Value: "<root>=DEBUG", Source: config.JujuModelConfigSource},
+			"update-status-hook-interval": {
+				Value: "5m", Source: config.JujuModelConfigSource,
+			},
+			// Secret config is left out of the exported spec.
+			"logforward-password": {Value: "hunter2", Source: config.JujuModelConfigSource},
+			"default-series":      {Value: "focal", Source: config.JujuControllerSource},
+		},
+		constraints: constraints.MustParse("mem=4G"),
+		spaces: []params.Space{{
+			Name:    "alpha",
+			Subnets: []params.Subnet{{CIDR: "10.0.0.0/24"}},
+		}, {
+			Name:    "db",
+			Subnets: []params.Subnet{{CIDR: "10.1.1.0/24"}, {CIDR: "10.1.0.0/24"}},
+		}},
+		pools: []params.StoragePool{{
+			Name: "loop", Provider: "loop",
+		}, {
+			Name: "fast", Provider: "ebs", Attrs: map[string]interface{}{"volume-type": "ssd"},
+		}},
+		rules: []params.FirewallRule{{
+			KnownService: params.SSHRule, WhitelistCIDRS: []string{"192.168.0.0/16"},
+		}},
+		credential: names.NewCloudCredentialTag("aws/bob/default"),
+	}
+	s.applier = fakeBundleApplier{diff: &bundlechanges.BundleDiff{}}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		ModelUUID: testing.ModelTag.Id(),
+		ModelType: coremodel.IAAS,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+}
+
+const exportedModelSpec = `
+credential: aws/bob/default
+config:
+  logging-config: <root>=DEBUG
+  update-status-hook-interval: 5m
+constraints: mem=4096M
+spaces:
+  db:
+  - 10.1.0.0/24
+  - 10.1.1.0/24
+storage-pools:
+  fast:
+    provider: ebs
+    attributes:
+      volume-type: ssd
+firewall-rules:
+  ssh:
+  - 192.168.0.0/16
+bundle: |
+  applications:
+    mysql:
+      charm: mysql
+`
+
+func (s *ModelSpecCommandSuite) runExport(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, model.NewExportModelSpecCommandForTest(&s.fake, s.store), args...)
+}
+
+func (s *ModelSpecCommandSuite) runApply(c *gc.C, spec string, args ...string) (*cmd.Context, error) {
+	path := filepath.Join(c.MkDir(), "spec.yaml")
+	err := ioutil.WriteFile(path, []byte(spec), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	command := model.NewApplyModelSpecCommandForTest(&s.fake, &s.applier, s.store)
+	return cmdtesting.RunCommand(c, command, append([]string{path}, args...)...)
+}
+
+func (s *ModelSpecCommandSuite) TestExport(c *gc.C) {
+	ctx, err := s.runExport(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, exportedModelSpec[1:])
+	s.fake.CheckCallNames(c,
+		"ModelCredential", "ModelGetWithMetadata", "GetModelConstraints",
+		"ListSpaces", "ListPools", "ListFirewallRules", "ExportBundle", "Close")
+}
+
+func (s *ModelSpecCommandSuite) TestExportToFile(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "spec.yaml")
+	ctx, err := s.runExport(c, "--filename", path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "Model spec successfully exported to "+path+"\n")
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, exportedModelSpec[1:])
+}
+
+func (s *ModelSpecCommandSuite) TestExportError(c *gc.C) {
+	s.fake.SetErrors(nil, errors.New("boom"))
+	_, err := s.runExport(c)
+	c.Assert(err, gc.ErrorMatches, "getting model config: boom")
+}
+
+func (s *ModelSpecCommandSuite) TestApplyUnchanged(c *gc.C) {
+	ctx, err := s.runApply(c, exportedModelSpec)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "The model already matches the model spec.\n")
+	s.fake.CheckCallNames(c,
+		"ModelCredential", "ModelGetWithMetadata", "GetModelConstraints",
+		"ListSpaces", "ListPools", "ListFirewallRules", "Close")
+	s.applier.CheckCallNames(c, "DiffBundle")
+}
+
+const changedModelSpec = `
+credential: aws/bob/other
+config:
+  logging-config: <root>=INFO
+constraints: mem=8G
+spaces:
+  db:
+  - 10.1.1.0/24
+  - 10.1.0.0/24
+  dmz:
+  - 10.2.0.0/24
+storage-pools:
+  fast:
+    provider: ebs
+    attributes:
+      volume-type: io1
+  slow:
+    provider: ebs
+firewall-rules:
+  ssh:
+  - 0.0.0.0/0
+bundle: |
+  applications:
+    mysql:
+      charm: mysql
+      num_units: 2
+`
+
+func (s *ModelSpecCommandSuite) TestApplyDryRun(c *gc.C) {
+	s.applier.diff = &bundlechanges.BundleDiff{
+		Applications: map[string]*bundlechanges.ApplicationDiff{
+			"mysql": {NumUnits: &bundlechanges.IntDiff{Bundle: 2, Model: 1}},
+		},
+	}
+	ctx, err := s.runApply(c, changedModelSpec, "--dry-run")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"Changes to apply:\n"+
+		"- change model credential to \"aws/bob/other\"\n"+
+		"- set model config logging-config\n"+
+		"- reset model config update-status-hook-interval\n"+
+		"- set model constraints \"mem=8192M\"\n"+
+		"- create space \"dmz\" with subnets 10.2.0.0/24\n"+
+		"- update storage pool \"fast\"\n"+
+		"- create storage pool \"slow\" with provider \"ebs\"\n"+
+		"- set firewall rule for \"ssh\" to 0.0.0.0/0\n"+
+		"- deploy bundle changes:\n"+
+		"    applications:\n"+
+		"      mysql:\n"+
+		"        num_units:\n"+
+		"          bundle: 2\n"+
+		"          model: 1\n")
+	s.fake.CheckCallNames(c,
+		"ModelCredential", "ModelGetWithMetadata", "GetModelConstraints",
+		"ListSpaces", "ListPools", "ListFirewallRules", "Close")
+	s.applier.CheckCallNames(c, "DiffBundle")
+}
+
+func (s *ModelSpecCommandSuite) TestApply(c *gc.C) {
+	s.applier.diff = &bundlechanges.BundleDiff{
+		Applications: map[string]*bundlechanges.ApplicationDiff{
+			"mysql": {NumUnits: &bundlechanges.IntDiff{Bundle: 2, Model: 1}},
+		},
+	}
+	ctx, err := s.runApply(c, changedModelSpec)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, ""+
+		"Applied: change model credential to \"aws/bob/other\"\n"+
+		"Applied: set model config logging-config\n"+
+		"Applied: reset model config update-status-hook-interval\n"+
+		"Applied: set model constraints \"mem=8192M\"\n"+
+		"Applied: create space \"dmz\" with subnets 10.2.0.0/24\n"+
+		"Applied: update storage pool \"fast\"\n"+
+		"Applied: create storage pool \"slow\" with provider \"ebs\"\n"+
+		"Applied: set firewall rule for \"ssh\" to 0.0.0.0/0\n"+
+		"Applied: deploy bundle changes\n")
+	calls := s.fake.Calls()[6:]
+	c.Assert(calls, jc.DeepEquals, []gitjujutesting.StubCall{
+		{"ChangeModelCredential", []interface{}{names.NewCloudCredentialTag("aws/bob/other")}},
+		{"ModelSet", []interface{}{map[string]interface{}{"logging-config": "<root>=INFO"}}},
+		{"ModelUnset", []interface{}{[]string{"update-status-hook-interval"}}},
+		{"SetModelConstraints", []interface{}{constraints.MustParse("mem=8G")}},
+		{"CreateSpace", []interface{}{"dmz", []string{"10.2.0.0/24"}, true}},
+		{"UpdatePool", []interface{}{"fast", "ebs", map[string]interface{}{"volume-type": "io1"}}},
+		{"CreatePool", []interface{}{"slow", "ebs", map[string]interface{}(nil)}},
+		{"SetFirewallRule", []interface{}{"ssh", []string{"0.0.0.0/0"}}},
+		{"Close", nil},
+	})
+	s.applier.CheckCallNames(c, "DiffBundle", "DeployBundle")
+	s.applier.CheckCall(c, 1, "DeployBundle",
+		"applications:\n  mysql:\n    charm: mysql\n    num_units: 2\n",
+		s.applier.Calls()[0].Args[1])
+}
+
+func (s *ModelSpecCommandSuite) TestApplyPartialSpec(c *gc.C) {
+	// Parts of the model left out of the spec are left unchanged.
+	_, err := s.runApply(c, "constraints: mem=8G\n")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCall(c, 6, "SetModelConstraints", constraints.MustParse("mem=8G"))
+	c.Assert(s.fake.Calls(), gc.HasLen, 8)
+	s.applier.CheckNoCalls(c)
+}
+
+func (s *ModelSpecCommandSuite) TestApplySkipsSecretConfig(c *gc.C) {
+	ctx, err := s.runApply(c, ""+
+		"config:\n"+
+		"  logging-config: <root>=DEBUG\n"+
+		"  update-status-hook-interval: 5m\n"+
+		"  logforward-password: s3cret\n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "The model already matches the model spec.\n")
+	c.Assert(s.fake.Calls(), gc.HasLen, 7)
+	s.fake.CheckCallNames(c,
+		"ModelCredential", "ModelGetWithMetadata", "GetModelConstraints",
+		"ListSpaces", "ListPools", "ListFirewallRules", "Close")
+}
+
+func (s *ModelSpecCommandSuite) TestApplySpaceSubnetsDiffer(c *gc.C) {
+	_, err := s.runApply(c, "spaces:\n  db:\n  - 10.1.0.0/24\n")
+	c.Assert(err, gc.ErrorMatches,
+		`space "db" has subnets 10.1.0.0/24, 10.1.1.0/24, not 10.1.0.0/24; use move-to-space to change them`)
+}
+
+func (s *ModelSpecCommandSuite) TestApplyPoolProviderDiffers(c *gc.C) {
+	_, err := s.runApply(c, "storage-pools:\n  fast:\n    provider: tmpfs\n")
+	c.Assert(err, gc.ErrorMatches,
+		`storage pool "fast" has provider "ebs", not "tmpfs"; remove it to change the provider`)
+}
+
+func (s *ModelSpecCommandSuite) TestApplyInvalidSpec(c *gc.C) {
+	_, err := s.runApply(c, "unknown: field\n")
+	c.Assert(err, gc.ErrorMatches, `(?s)cannot parse model spec ".*spec.yaml": .*field unknown not found.*`)
+}
+
+func (s *ModelSpecCommandSuite) TestApplyChangeError(c *gc.C) {
+	s.fake.SetErrors(nil, nil, nil, nil, nil, nil, errors.New("boom"))
+	_, err := s.runApply(c, "constraints: mem=8G\n")
+	c.Assert(err, gc.ErrorMatches, `cannot set model constraints "mem=8192M": boom`)
+}
+
+func (s *ModelSpecCommandSuite) TestApplyInitErrors(c *gc.C) {
+	command := model.NewApplyModelSpecCommandForTest(&s.fake, &s.applier, s.store)
+	_, err := cmdtesting.RunCommand(c, command)
+	c.Assert(err, gc.ErrorMatches, "no model spec file specified")
+	command = model.NewApplyModelSpecCommandForTest(&s.fake, &s.applier, s.store)
+	_, err = cmdtesting.RunCommand(c, command, "a.yaml", "b.yaml")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["b.yaml"\]`)
+}
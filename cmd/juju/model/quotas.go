@@ -0,0 +1,334 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+	"github.com/juju/utils/v2"
+
+	"github.com/juju/juju/api/modelquotas"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/core/quota"
+)
+
+const modelQuotasDoc = `
+Shows the quotas limiting the resources used by the model, and the amount
+of each resource it uses. With --user, the quotas of a user are shown
+instead; these limit the total used by all the models the user owns.
+
+The resources that can be limited are:
+
+    machines      the number of machines
+    units         the number of units
+    applications  the number of applications
+    storage       the total size of the storage instances
+    pods          the number of units in Kubernetes models
+
+Resources without a limit are shown with a limit of "-".
+
+Model users can see the quotas of the model, and users can see their own
+quotas. Controller superusers can see all quotas.
+
+Examples:
+
+    juju model-quotas
+    juju model-quotas -m mymodel
+    juju model-quotas --user bob --format yaml
+
+See also:
+    set-model-quotas
+`
+
+const setModelQuotasDoc = `
+Sets the quotas limiting the resources used by the model. With --user, the
+quotas of a user are set instead; these limit the total used by all the
+models the user owns. Adding a machine, unit, application or storage that
+would exceed the quotas of the model or its owner fails.
+
+The resources that can be limited are machines, units, applications,
+storage and pods; see model-quotas for details. The storage limit may be
+given with a size suffix (M, G, T or P); the default is megabytes.
+
+Lowering a limit below the amount already used doesn't remove anything,
+but prevents more being added. Limits are removed with --reset.
+
+Only controller superusers can set quotas.
+
+Examples:
+
+    juju set-model-quotas machines=10 units=50 storage=500G
+    juju set-model-quotas -m mymodel --reset machines,storage
+    juju set-model-quotas --user bob applications=20
+
+See also:
+    model-quotas
+`
+
+// QuotasAPI defines the API methods used by the model-quotas and
+// set-model-quotas commands.
+type QuotasAPI interface {
+	Quotas(names.Tag) (quota.Limits, quota.Usage, error)
+	SetQuotas(names.Tag, quota.Limits, []quota.Resource) error
+	Close() error
+}
+
+// quotasCommandBase holds what is common to the quota commands.
+type quotasCommandBase struct {
+	modelcmd.ModelCommandBase
+	api QuotasAPI
+
+	user string
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *quotasCommandBase) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.user, "user", "", "Use the quotas of this user rather than the model")
+}
+
+func (c *quotasCommandBase) initUser() error {
+	if c.user != "" && !names.IsValidUser(c.user) {
+		return errors.NotValidf("user name %q", c.user)
+	}
+	return nil
+}
+
+func (c *quotasCommandBase) getAPI() (QuotasAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return modelquotas.NewClient(root), nil
+}
+
+// entity returns the tag of the user or model whose quotas are used.
+func (c *quotasCommandBase) entity() (names.Tag, error) {
+	if c.user != "" {
+		return names.NewUserTag(c.user), nil
+	}
+	_, modelDetails, err := c.ModelDetails()
+	if err != nil {
+		return nil, errors.Annotate(err, "getting model details")
+	}
+	return names.NewModelTag(modelDetails.ModelUUID), nil
+}
+
+// NewModelQuotasCommand returns a command that shows the quotas of a
+// model or user.
+func NewModelQuotasCommand() cmd.Command {
+	return modelcmd.Wrap(&modelQuotasCommand{})
+}
+
+type modelQuotasCommand struct {
+	quotasCommandBase
+	out cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *modelQuotasCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "model-quotas",
+		Purpose: "Displays the resource quotas of a model or user.",
+		Doc:     modelQuotasDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *modelQuotasCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.quotasCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"json":    cmd.FormatJson,
+		"tabular": formatQuotasTabular,
+		"yaml":    cmd.FormatYaml,
+	})
+}
+
+// Init implements Command.Init.
+func (c *modelQuotasCommand) Init(args []string) error {
+	if err := c.initUser(); err != nil {
+		return errors.Trace(err)
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// quotaInfo is the amount of a resource used, and its limit if it has
+// one, as displayed by model-quotas.
+type quotaInfo struct {
+	Used  uint64  `yaml:"used" json:"used"`
+	Limit *uint64 `yaml:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// Run implements Command.Run.
+func (c *modelQuotasCommand) Run(ctx *cmd.Context) error {
+	tag, err := c.entity()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	limits, usage, err := client.Quotas(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	quotas := make(map[string]quotaInfo, len(usage))
+	for r, used := range usage {
+		quotas[string(r)] = quotaInfo{Used: used}
+	}
+	for r, limit := range limits {
+		limit := limit
+		info := quotas[string(r)]
+		info.Limit = &limit
+		quotas[string(r)] = info
+	}
+	return errors.Trace(c.out.Write(ctx, quotas))
+}
+
+func formatQuotasTabular(writer io.Writer, value interface{}) error {
+	quotas, ok := value.(map[string]quotaInfo)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", quotas, value)
+	}
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Resource", "Used", "Limit")
+	for _, r := range quota.AllResources {
+		info, ok := quotas[string(r)]
+		if !ok {
+			continue
+		}
+		limit := "-"
+		if info.Limit != nil {
+			limit = r.Format(*info.Limit)
+		}
+		w.Println(string(r), r.Format(info.Used), limit)
+	}
+	return tw.Flush()
+}
+
+// NewSetModelQuotasCommand returns a command that sets the quotas of a
+// model or user.
+func NewSetModelQuotasCommand() cmd.Command {
+	return modelcmd.Wrap(&setModelQuotasCommand{})
+}
+
+type setModelQuotasCommand struct {
+	quotasCommandBase
+
+	resetKeys []string
+	limits    quota.Limits
+	reset     []quota.Resource
+}
+
+// Info implements Command.Info.
+func (c *setModelQuotasCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "set-model-quotas",
+		Args:    "[<resource>=<limit> ...]",
+		Purpose: "Sets the resource quotas of a model or user.",
+		Doc:     setModelQuotasDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *setModelQuotasCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.quotasCommandBase.SetFlags(f)
+	f.Var(cmd.NewAppendStringsValue(&c.resetKeys), "reset", "Remove the limits on the provided comma delimited resources")
+}
+
+// Init implements Command.Init.
+func (c *setModelQuotasCommand) Init(args []string) error {
+	if err := c.initUser(); err != nil {
+		return errors.Trace(err)
+	}
+	c.limits = make(quota.Limits)
+	for _, arg := range args {
+		name, value := arg, ""
+		if i := strings.Index(arg, "="); i >= 0 {
+			name, value = arg[:i], arg[i+1:]
+		}
+		r := quota.Resource(name)
+		if err := r.Validate(); err != nil {
+			return errors.Trace(err)
+		}
+		if _, ok := c.limits[r]; ok {
+			return errors.Errorf("%s quota specified more than once", r)
+		}
+		limit, err := parseQuotaLimit(r, value)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		c.limits[r] = limit
+	}
+	c.reset = nil
+	var resetKeys []string
+	for _, value := range c.resetKeys {
+		resetKeys = append(resetKeys, strings.Split(strings.Trim(value, ","), ",")...)
+	}
+	for _, key := range resetKeys {
+		r := quota.Resource(key)
+		if err := r.Validate(); err != nil {
+			return errors.Trace(err)
+		}
+		if _, ok := c.limits[r]; ok {
+			return errors.Errorf("cannot set and reset %s quota", r)
+		}
+		c.reset = append(c.reset, r)
+	}
+	if len(c.limits) == 0 && len(c.reset) == 0 {
+		return errors.New("no quotas specified")
+	}
+	return nil
+}
+
+// parseQuotaLimit parses the limit for a resource. Storage limits may
+// have a size suffix.
+func parseQuotaLimit(r quota.Resource, value string) (uint64, error) {
+	if value == "" {
+		return 0, errors.Errorf("expected %s=<limit>", r)
+	}
+	if r == quota.Storage {
+		limit, err := utils.ParseSize(value)
+		if err != nil {
+			return 0, errors.Annotatef(err, "invalid %s limit", r)
+		}
+		return limit, nil
+	}
+	limit, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("invalid %s limit %q, expected a non-negative integer", r, value)
+	}
+	return limit, nil
+}
+
+// Run implements Command.Run.
+func (c *setModelQuotasCommand) Run(ctx *cmd.Context) error {
+	tag, err := c.entity()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	return errors.Trace(client.SetQuotas(tag, c.limits, c.reset))
+}
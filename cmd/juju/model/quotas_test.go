@@ -0,0 +1,185 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/names/v4"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/model"
+	coremodel "github.com/juju/juju/core/model"
+	"github.com/juju/juju/core/quota"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type QuotasCommandSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake  fakeQuotasClient
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&QuotasCommandSuite{})
+
+type fakeQuotasClient struct {
+	gitjujutesting.Stub
+	limits quota.Limits
+	usage  quota.Usage
+}
+
+func (f *fakeQuotasClient) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeQuotasClient) Quotas(tag names.Tag) (quota.Limits, quota.Usage, error) {
+	f.MethodCall(f, "Quotas", tag)
+	if err := f.NextErr(); err != nil {
+		return nil, nil, err
+	}
+	return f.limits, f.usage, nil
+}
+
+func (f *fakeQuotasClient) SetQuotas(tag names.Tag, limits quota.Limits, reset []quota.Resource) error {
+	f.MethodCall(f, "SetQuotas", tag, limits, reset)
+	return f.NextErr()
+}
+
+func (s *QuotasCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = fakeQuotasClient{
+		limits: quota.Limits{
+			quota.Machines: 10,
+			quota.Storage:  2048,
+		},
+		usage: quota.Usage{
+			quota.Machines:     3,
+			quota.Units:        5,
+			quota.Applications: 2,
+			quota.Storage:      1024,
+			quota.Pods:         0,
+		},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		ModelUUID: testing.ModelTag.Id(),
+		ModelType: coremodel.IAAS,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+}
+
+func (s *QuotasCommandSuite) runGet(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, model.NewModelQuotasCommandForTest(&s.fake, s.store), args...)
+}
+
+func (s *QuotasCommandSuite) runSet(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, model.NewSetModelQuotasCommandForTest(&s.fake, s.store), args...)
+}
+
+func (s *QuotasCommandSuite) TestModelQuotasTabular(c *gc.C) {
+	ctx, err := s.runGet(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"Resource      Used     Limit\n"+
+		"machines      3        10\n"+
+		"units         5        -\n"+
+		"applications  2        -\n"+
+		"storage       1024MiB  2048MiB\n"+
+		"pods          0        -\n"+
+		"\n")
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"Quotas", []interface{}{testing.ModelTag}},
+		{"Close", nil},
+	})
+}
+
+func (s *QuotasCommandSuite) TestModelQuotasYAML(c *gc.C) {
+	ctx, err := s.runGet(c, "--format", "yaml", "--user", "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"applications:\n"+
+		"  used: 2\n"+
+		"machines:\n"+
+		"  used: 3\n"+
+		"  limit: 10\n"+
+		"pods:\n"+
+		"  used: 0\n"+
+		"storage:\n"+
+		"  used: 1024\n"+
+		"  limit: 2048\n"+
+		"units:\n"+
+		"  used: 5\n")
+	s.fake.CheckCall(c, 0, "Quotas", names.NewUserTag("bob"))
+}
+
+func (s *QuotasCommandSuite) TestModelQuotasInitErrors(c *gc.C) {
+	_, err := s.runGet(c, "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+	_, err = s.runGet(c, "--user", "not/valid")
+	c.Assert(err, gc.ErrorMatches, `user name "not/valid" not valid`)
+}
+
+func (s *QuotasCommandSuite) TestSetModelQuotas(c *gc.C) {
+	_, err := s.runSet(c, "machines=10", "units=50", "storage=2G", "--reset", "pods,applications")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"SetQuotas", []interface{}{
+			testing.ModelTag,
+			quota.Limits{quota.Machines: 10, quota.Units: 50, quota.Storage: 2048},
+			[]quota.Resource{quota.Pods, quota.Applications},
+		}},
+		{"Close", nil},
+	})
+}
+
+func (s *QuotasCommandSuite) TestSetModelQuotasUser(c *gc.C) {
+	_, err := s.runSet(c, "--user", "bob", "--reset", "units")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCall(c, 0, "SetQuotas", names.NewUserTag("bob"), quota.Limits{}, []quota.Resource{quota.Units})
+}
+
+func (s *QuotasCommandSuite) TestSetModelQuotasInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no quotas specified",
+	}, {
+		args: []string{"cpus=4"},
+		err:  `quota resource "cpus" not valid`,
+	}, {
+		args: []string{"machines"},
+		err:  `expected machines=<limit>`,
+	}, {
+		args: []string{"machines=-1"},
+		err:  `invalid machines limit "-1", expected a non-negative integer`,
+	}, {
+		args: []string{"storage=lots"},
+		err:  `invalid storage limit: .*`,
+	}, {
+		args: []string{"units=1", "units=2"},
+		err:  `units quota specified more than once`,
+	}, {
+		args: []string{"units=1", "--reset", "units"},
+		err:  `cannot set and reset units quota`,
+	}, {
+		args: []string{"--reset", "cpus"},
+		err:  `quota resource "cpus" not valid`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := s.runSet(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+	c.Assert(s.fake.Calls(), gc.HasLen, 0)
+}
@@ -0,0 +1,357 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/api/webhooks"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/core/webhook"
+)
+
+const addWebhookDoc = `
+Registers a URL to be notified of events in the model. When one of the
+events happens, the controller POSTs a JSON payload describing it to the
+URL, retrying for a while if the request fails.
+
+The events are:
+
+    unit-error            a unit's workload or agent enters error status
+    unit-blocked          a unit's workload enters blocked status
+    machine-down          a machine's agent goes down
+    action-failed         an action fails
+    application-deployed  an application is deployed
+    application-removed   an application is removed
+
+By default the webhook is notified of all of them; --events limits it to
+the given comma delimited events.
+
+The payload is a JSON object with the "event", "model-uuid", "model",
+"entity", "status", "message" and "timestamp" of the event. A Go
+text/template given with --template-file renders the payload instead;
+it is passed the event, with fields Kind, ModelUUID, Model, Entity,
+Status, Message and Timestamp, and a "json" function to quote values. The
+template must render valid JSON.
+
+With --secret-file, each payload is signed with the secret read from the
+file; the X-Juju-Signature header holds "sha256=" and the hex encoded
+HMAC-SHA256 of the payload.
+
+Only model admins and controller superusers can manage webhooks.
+
+Examples:
+
+    juju add-webhook https://hooks.example.com/juju
+    juju add-webhook https://hooks.example.com/juju --events unit-error,machine-down
+    juju add-webhook https://hooks.example.com/chat --secret-file secret.txt --template-file chat.tmpl
+
+See also:
+    webhooks
+    remove-webhook
+`
+
+const webhooksDoc = `
+Shows the webhooks notified of events in the model. The secrets used to
+sign payloads are never shown; the "signed" column tells if a webhook
+has one.
+
+Examples:
+
+    juju webhooks
+    juju webhooks -m mymodel --format yaml
+
+See also:
+    add-webhook
+    remove-webhook
+`
+
+const removeWebhookDoc = `
+Removes a webhook, so that it is no longer notified of events in the
+model. The id of a webhook is shown by the webhooks command.
+
+Examples:
+
+    juju remove-webhook 2
+
+See also:
+    add-webhook
+    webhooks
+`
+
+// WebhooksAPI defines the API methods used by the webhook commands.
+type WebhooksAPI interface {
+	AddWebhook(webhook.Webhook) (string, error)
+	Webhooks() ([]params.Webhook, error)
+	RemoveWebhook(string) error
+	Close() error
+}
+
+// webhooksCommandBase holds what is common to the webhook commands.
+type webhooksCommandBase struct {
+	modelcmd.ModelCommandBase
+	api WebhooksAPI
+}
+
+func (c *webhooksCommandBase) getAPI() (WebhooksAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return webhooks.NewClient(root), nil
+}
+
+// NewAddWebhookCommand returns a command that registers a webhook.
+func NewAddWebhookCommand() cmd.Command {
+	return modelcmd.Wrap(&addWebhookCommand{})
+}
+
+type addWebhookCommand struct {
+	webhooksCommandBase
+
+	events       string
+	secretFile   cmd.FileVar
+	templateFile cmd.FileVar
+
+	hook webhook.Webhook
+}
+
+// Info implements Command.Info.
+func (c *addWebhookCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "add-webhook",
+		Args:    "<url>",
+		Purpose: "Registers a webhook to be notified of events in the model.",
+		Doc:     addWebhookDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *addWebhookCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.events, "events", "", "The comma delimited events to notify the webhook of")
+	f.Var(&c.secretFile, "secret-file", "Path to a file holding the secret used to sign payloads")
+	f.Var(&c.templateFile, "template-file", "Path to a file holding the template used to render payloads")
+}
+
+// Init implements Command.Init.
+func (c *addWebhookCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no webhook URL specified")
+	}
+	c.hook = webhook.Webhook{URL: args[0]}
+	for _, event := range strings.Split(c.events, ",") {
+		if event = strings.TrimSpace(event); event == "" {
+			continue
+		}
+		kind := webhook.EventKind(event)
+		if err := kind.Validate(); err != nil {
+			return errors.Trace(err)
+		}
+		c.hook.Events = append(c.hook.Events, kind)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *addWebhookCommand) Run(ctx *cmd.Context) error {
+	hook := c.hook
+	if c.secretFile.Path != "" {
+		secret, err := c.secretFile.Read(ctx)
+		if err != nil {
+			return errors.Annotate(err, "reading secret")
+		}
+		hook.Secret = strings.TrimSpace(string(secret))
+		if hook.Secret == "" {
+			return errors.Errorf("secret file %q is empty", c.secretFile.Path)
+		}
+	}
+	if c.templateFile.Path != "" {
+		template, err := c.templateFile.Read(ctx)
+		if err != nil {
+			return errors.Annotate(err, "reading template")
+		}
+		hook.Template = string(template)
+	}
+	if err := hook.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	id, err := client.AddWebhook(hook)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ctx.Infof("Added webhook %s", id)
+	return nil
+}
+
+// NewWebhooksCommand returns a command that lists the webhooks
+// registered in a model.
+func NewWebhooksCommand() cmd.Command {
+	return modelcmd.Wrap(&webhooksCommand{})
+}
+
+type webhooksCommand struct {
+	webhooksCommandBase
+	out cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *webhooksCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "webhooks",
+		Purpose: "Lists the webhooks notified of events in the model.",
+		Doc:     webhooksDoc,
+		Aliases: []string{"list-webhooks"},
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *webhooksCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"json":    cmd.FormatJson,
+		"tabular": formatWebhooksTabular,
+		"yaml":    cmd.FormatYaml,
+	})
+}
+
+// Init implements Command.Init.
+func (c *webhooksCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// webhookInfo is a webhook, as displayed by the webhooks command.
+type webhookInfo struct {
+	URL       string    `yaml:"url" json:"url"`
+	Events    []string  `yaml:"events,omitempty" json:"events,omitempty"`
+	Signed    bool      `yaml:"signed" json:"signed"`
+	Template  string    `yaml:"template,omitempty" json:"template,omitempty"`
+	CreatedBy string    `yaml:"created-by" json:"created-by"`
+	Created   time.Time `yaml:"created" json:"created"`
+}
+
+// Run implements Command.Run.
+func (c *webhooksCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	hooks, err := client.Webhooks()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(hooks) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No webhooks to display.")
+		return nil
+	}
+	result := make(map[string]webhookInfo, len(hooks))
+	for _, hook := range hooks {
+		result[hook.Id] = webhookInfo{
+			URL:       hook.URL,
+			Events:    hook.Events,
+			Signed:    hook.Signed,
+			Template:  hook.Template,
+			CreatedBy: hook.CreatedBy,
+			Created:   hook.Created,
+		}
+	}
+	return errors.Trace(c.out.Write(ctx, result))
+}
+
+func formatWebhooksTabular(writer io.Writer, value interface{}) error {
+	hooks, ok := value.(map[string]webhookInfo)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", hooks, value)
+	}
+	ids := make([]string, 0, len(hooks))
+	for id := range hooks {
+		ids = append(ids, id)
+	}
+	// Webhook ids are sequential, so sort them numerically.
+	sort.Slice(ids, func(i, j int) bool {
+		a, _ := strconv.Atoi(ids[i])
+		b, _ := strconv.Atoi(ids[j])
+		return a < b
+	})
+
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("ID", "URL", "Events", "Signed", "Created by")
+	for _, id := range ids {
+		hook := hooks[id]
+		events := "all"
+		if len(hook.Events) > 0 {
+			events = strings.Join(hook.Events, ",")
+		}
+		w.Println(id, hook.URL, events, fmt.Sprint(hook.Signed), hook.CreatedBy)
+	}
+	return tw.Flush()
+}
+
+// NewRemoveWebhookCommand returns a command that removes a webhook.
+func NewRemoveWebhookCommand() cmd.Command {
+	return modelcmd.Wrap(&removeWebhookCommand{})
+}
+
+type removeWebhookCommand struct {
+	webhooksCommandBase
+	id string
+}
+
+// Info implements Command.Info.
+func (c *removeWebhookCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "remove-webhook",
+		Args:    "<id>",
+		Purpose: "Removes a webhook from the model.",
+		Doc:     removeWebhookDoc,
+	})
+}
+
+// Init implements Command.Init.
+func (c *removeWebhookCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no webhook id specified")
+	}
+	c.id = args[0]
+	if _, err := strconv.Atoi(c.id); err != nil {
+		return errors.NotValidf("webhook id %q", c.id)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *removeWebhookCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	return errors.Trace(client.RemoveWebhook(c.id))
+}
@@ -0,0 +1,207 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/model"
+	coremodel "github.com/juju/juju/core/model"
+	"github.com/juju/juju/core/webhook"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/testing"
+)
+
+type WebhooksCommandSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	fake  fakeWebhooksClient
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&WebhooksCommandSuite{})
+
+type fakeWebhooksClient struct {
+	gitjujutesting.Stub
+	webhooks []params.Webhook
+}
+
+func (f *fakeWebhooksClient) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeWebhooksClient) AddWebhook(hook webhook.Webhook) (string, error) {
+	f.MethodCall(f, "AddWebhook", hook)
+	return "3", f.NextErr()
+}
+
+func (f *fakeWebhooksClient) Webhooks() ([]params.Webhook, error) {
+	f.MethodCall(f, "Webhooks")
+	return f.webhooks, f.NextErr()
+}
+
+func (f *fakeWebhooksClient) RemoveWebhook(id string) error {
+	f.MethodCall(f, "RemoveWebhook", id)
+	return f.NextErr()
+}
+
+func (s *WebhooksCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.fake = fakeWebhooksClient{
+		webhooks: []params.Webhook{{
+			Id:        "2",
+			URL:       "https://example.com/chat",
+			Template:  `{"text": {{json .Entity}}}`,
+			CreatedBy: "bob",
+			Created:   time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+		}, {
+			Id:        "10",
+			URL:       "https://example.com/hook",
+			Events:    []string{"unit-error", "machine-down"},
+			Signed:    true,
+			CreatedBy: "admin",
+			Created:   time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		ModelUUID: testing.ModelTag.Id(),
+		ModelType: coremodel.IAAS,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+}
+
+func (s *WebhooksCommandSuite) runAdd(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, model.NewAddWebhookCommandForTest(&s.fake, s.store), args...)
+}
+
+func (s *WebhooksCommandSuite) runList(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, model.NewWebhooksCommandForTest(&s.fake, s.store), args...)
+}
+
+func (s *WebhooksCommandSuite) runRemove(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, model.NewRemoveWebhookCommandForTest(&s.fake, s.store), args...)
+}
+
+func (s *WebhooksCommandSuite) TestAddWebhook(c *gc.C) {
+	ctx, err := s.runAdd(c, "https://example.com/hook", "--events", "unit-error,action-failed")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "Added webhook 3\n")
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"AddWebhook", []interface{}{webhook.Webhook{
+			URL:    "https://example.com/hook",
+			Events: []webhook.EventKind{webhook.UnitError, webhook.ActionFailed},
+		}}},
+		{"Close", nil},
+	})
+}
+
+func (s *WebhooksCommandSuite) TestAddWebhookSecretAndTemplate(c *gc.C) {
+	dir := c.MkDir()
+	secretFile := filepath.Join(dir, "secret")
+	err := ioutil.WriteFile(secretFile, []byte("s3cret\n"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	templateFile := filepath.Join(dir, "template")
+	err = ioutil.WriteFile(templateFile, []byte(`{"text": {{json .Entity}}}`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.runAdd(c, "https://example.com/chat", "--secret-file", secretFile, "--template-file", templateFile)
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCall(c, 0, "AddWebhook", webhook.Webhook{
+		URL:      "https://example.com/chat",
+		Secret:   "s3cret",
+		Template: `{"text": {{json .Entity}}}`,
+	})
+}
+
+func (s *WebhooksCommandSuite) TestAddWebhookInvalidTemplate(c *gc.C) {
+	templateFile := filepath.Join(c.MkDir(), "template")
+	err := ioutil.WriteFile(templateFile, []byte(`text: {{.Entity}}`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.runAdd(c, "https://example.com/chat", "--template-file", templateFile)
+	c.Assert(err, gc.ErrorMatches, `payload template rendering "text: mysql/0" as JSON not valid`)
+	c.Assert(s.fake.Calls(), gc.HasLen, 0)
+}
+
+func (s *WebhooksCommandSuite) TestAddWebhookInitErrors(c *gc.C) {
+	_, err := s.runAdd(c)
+	c.Assert(err, gc.ErrorMatches, "no webhook URL specified")
+	_, err = s.runAdd(c, "https://example.com/hook", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+	_, err = s.runAdd(c, "https://example.com/hook", "--events", "unit-error,bogus")
+	c.Assert(err, gc.ErrorMatches, `webhook event "bogus" not valid`)
+}
+
+func (s *WebhooksCommandSuite) TestWebhooksTabular(c *gc.C) {
+	ctx, err := s.runList(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"ID  URL                       Events                   Signed  Created by\n"+
+		"2   https://example.com/chat  all                      false   bob\n"+
+		"10  https://example.com/hook  unit-error,machine-down  true    admin\n"+
+		"\n")
+	s.fake.CheckCallNames(c, "Webhooks", "Close")
+}
+
+func (s *WebhooksCommandSuite) TestWebhooksYAML(c *gc.C) {
+	ctx, err := s.runList(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		`"2":`+"\n"+
+		"  url: https://example.com/chat\n"+
+		"  signed: false\n"+
+		"  template: '{\"text\": {{json .Entity}}}'\n"+
+		"  created-by: bob\n"+
+		"  created: 2021-01-02T00:00:00Z\n"+
+		`"10":`+"\n"+
+		"  url: https://example.com/hook\n"+
+		"  events:\n"+
+		"  - unit-error\n"+
+		"  - machine-down\n"+
+		"  signed: true\n"+
+		"  created-by: admin\n"+
+		"  created: 2021-01-03T00:00:00Z\n")
+}
+
+func (s *WebhooksCommandSuite) TestWebhooksNone(c *gc.C) {
+	s.fake.webhooks = nil
+	ctx, err := s.runList(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No webhooks to display.\n")
+}
+
+func (s *WebhooksCommandSuite) TestRemoveWebhook(c *gc.C) {
+	_, err := s.runRemove(c, "2")
+	c.Assert(err, jc.ErrorIsNil)
+	s.fake.CheckCalls(c, []gitjujutesting.StubCall{
+		{"RemoveWebhook", []interface{}{"2"}},
+		{"Close", nil},
+	})
+}
+
+func (s *WebhooksCommandSuite) TestRemoveWebhookInitErrors(c *gc.C) {
+	_, err := s.runRemove(c)
+	c.Assert(err, gc.ErrorMatches, "no webhook id specified")
+	_, err = s.runRemove(c, "two")
+	c.Assert(err, gc.ErrorMatches, `webhook id "two" not valid`)
+	_, err = s.runRemove(c, "2", "3")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["3"\]`)
+}
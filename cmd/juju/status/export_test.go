@@ -18,3 +18,12 @@ func NewTestStatusCommand(statusapi statusAPI, storageapi storage.StorageListAPI
 	return modelcmd.Wrap(
 		&statusCommand{statusAPI: statusapi, storageAPI: storageapi, clock: clock})
 }
+
+func NewTestWatchStatusCommand(statusapi statusAPI, storageapi storage.StorageListAPI, watchapi watchAPI, clock Clock) cmd.Command {
+	return modelcmd.Wrap(
+		&statusCommand{statusAPI: statusapi, storageAPI: storageapi, watchAPI: watchapi, clock: clock})
+}
+
+func NewTestModelStatusHistoryCommand(api ModelHistoryAPI, clock Clock) cmd.Command {
+	return &modelStatusHistoryCommand{api: api, clock: clock}
+}
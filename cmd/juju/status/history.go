@@ -189,6 +189,11 @@ func (c *statusHistoryCommand) Run(ctx *cmd.Context) error {
 			return errors.Errorf("%q is not a valid name for a %s", c.entityName, kind)
 		}
 		tag = names.NewUnitTag(c.entityName)
+	case status.KindApplication:
+		if !names.IsValidApplication(c.entityName) {
+			return errors.Errorf("%q is not a valid name for an %s", c.entityName, kind)
+		}
+		tag = names.NewApplicationTag(c.entityName)
 	default:
 		if !names.IsValidMachine(c.entityName) {
 			return errors.Errorf("%q is not a valid name for a %s", c.entityName, kind)
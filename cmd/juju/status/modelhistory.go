@@ -0,0 +1,298 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/juju/osenv"
+)
+
+// modelHistoryBatchSize is the number of status changes fetched from
+// the controller at a time.
+const modelHistoryBatchSize = 1000
+
+// NewModelStatusHistoryCommand returns a command that reports the
+// changes in status of all of the entities in a model in a time window.
+func NewModelStatusHistoryCommand() cmd.Command {
+	return modelcmd.Wrap(&modelStatusHistoryCommand{})
+}
+
+// ModelHistoryAPI is the API surface for the model-status-log command.
+type ModelHistoryAPI interface {
+	ModelStatusHistory(params.ModelStatusHistoryArgs) (params.ModelStatusHistoryResult, error)
+	Close() error
+}
+
+type modelStatusHistoryCommand struct {
+	modelcmd.ModelCommandBase
+	api     ModelHistoryAPI
+	clock   Clock
+	out     cmd.Output
+	from    string
+	to      string
+	kinds   []string
+	status  []string
+	isoTime bool
+
+	fromTime time.Time
+	toTime   time.Time
+}
+
+var modelStatusHistoryDoc = fmt.Sprintf(`
+Report the changes in status of all of the units, machines and
+applications in the model in a time window, oldest first.
+
+The window is given with --from and --to, either as a time in RFC3339
+format or as a duration before now. Without either, the changes in the
+last hour are reported.
+
+The changes can be restricted to some types of status with --type, and
+to the changes into some status values with --status. The types are:
+%v
+The changes are reported as a timeline, or in json, yaml or csv format
+with --format.
+
+Examples:
+
+    juju model-status-log
+    juju model-status-log --from 2021-06-01T02:00:00Z --to 2021-06-01T02:15:00Z
+    juju model-status-log --from 6h --status error,blocked,lost
+    juju model-status-log --from 1d --type workload,application --format csv
+
+See also:
+    show-status-log
+    status
+`, supportedHistoryKindDescs())
+
+// Info implements Command.
+func (c *modelStatusHistoryCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "model-status-log",
+		Purpose: "Output the changes in status of all entities in the model in a time window.",
+		Doc:     modelStatusHistoryDoc,
+	})
+}
+
+// SetFlags implements Command.
+func (c *modelStatusHistoryCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"csv":     c.formatCSV,
+		"tabular": c.formatTabular,
+	})
+	f.StringVar(&c.from, "from", "", "Report the changes at or after this time (RFC3339, or a duration before now such as 30m or 2d)")
+	f.StringVar(&c.to, "to", "", "Report the changes before this time (RFC3339, or a duration before now such as 30m or 2d)")
+	f.Var(cmd.NewStringsValue(nil, &c.kinds), "type", fmt.Sprintf("Comma separated list of status types to report [%v]", supportedHistoryKindTypes()))
+	f.Var(cmd.NewStringsValue(nil, &c.status), "status", "Comma separated list of status values to report")
+	f.BoolVar(&c.isoTime, "utc", false, "Display time as UTC in RFC3339 format")
+}
+
+// Init implements Command.
+func (c *modelStatusHistoryCommand) Init(args []string) error {
+	for _, kind := range c.kinds {
+		if !status.HistoryKind(kind).Valid() {
+			return errors.Errorf("unexpected status type %q", kind)
+		}
+	}
+	if c.clock == nil {
+		c.clock = clock.WallClock
+	}
+	now := c.clock.Now()
+	var err error
+	if c.from != "" {
+		if c.fromTime, err = parseHistoryTime(now, c.from); err != nil {
+			return errors.Annotate(err, "invalid --from")
+		}
+	}
+	if c.to != "" {
+		if c.toTime, err = parseHistoryTime(now, c.to); err != nil {
+			return errors.Annotate(err, "invalid --to")
+		}
+	}
+	if c.fromTime.IsZero() && c.toTime.IsZero() {
+		c.fromTime = now.Add(-time.Hour)
+	}
+	if !c.fromTime.IsZero() && !c.toTime.IsZero() && !c.fromTime.Before(c.toTime) {
+		return errors.New("--from time must be before --to time")
+	}
+	// If use of ISO time not specified on command line,
+	// check env var.
+	if !c.isoTime {
+		envVarValue := os.Getenv(osenv.JujuStatusIsoTimeEnvKey)
+		if envVarValue != "" {
+			if c.isoTime, err = strconv.ParseBool(envVarValue); err != nil {
+				return errors.Annotatef(err, "invalid %s env var, expected true|false", osenv.JujuStatusIsoTimeEnvKey)
+			}
+		}
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// parseHistoryTime parses a time given either in RFC3339 format, or as
+// a duration before now. Durations may be given in days.
+func parseHistoryTime(now time.Time, value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	var (
+		d   time.Duration
+		err error
+	)
+	if days := value[:len(value)-1]; value[len(value)-1] == 'd' {
+		var n int
+		if n, err = strconv.Atoi(days); err == nil {
+			d = time.Duration(n) * 24 * time.Hour
+		}
+	} else {
+		d, err = time.ParseDuration(value)
+	}
+	if err != nil || d < 0 {
+		return time.Time{}, errors.Errorf("expected a time in RFC3339 format or a duration, got %q", value)
+	}
+	return now.Add(-d), nil
+}
+
+func (c *modelStatusHistoryCommand) getAPI() (ModelHistoryAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewAPIClient()
+}
+
+// modelStatusChange is the serialisation-friendly form of a status
+// transition.
+type modelStatusChange struct {
+	Time    time.Time              `json:"time" yaml:"time"`
+	Entity  string                 `json:"entity" yaml:"entity"`
+	Type    string                 `json:"type" yaml:"type"`
+	Status  string                 `json:"status" yaml:"status"`
+	Message string                 `json:"message,omitempty" yaml:"message,omitempty"`
+	Data    map[string]interface{} `json:"data,omitempty" yaml:"data,omitempty"`
+}
+
+// Run implements Command.
+func (c *modelStatusHistoryCommand) Run(ctx *cmd.Context) error {
+	apiclient, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer apiclient.Close()
+
+	args := params.ModelStatusHistoryArgs{
+		Kinds:    c.kinds,
+		Statuses: c.status,
+	}
+	if !c.fromTime.IsZero() {
+		args.From = &c.fromTime
+	}
+	if !c.toTime.IsZero() {
+		args.To = &c.toTime
+	}
+	limit := modelHistoryBatchSize
+	args.Limit = &limit
+
+	var changes []modelStatusChange
+	for {
+		offset := len(changes)
+		args.Offset = &offset
+		result, err := apiclient.ModelStatusHistory(args)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, t := range result.Transitions {
+			changes = append(changes, makeModelStatusChange(t, c.isoTime))
+		}
+		if !result.Truncated || len(result.Transitions) == 0 {
+			break
+		}
+	}
+	if len(changes) == 0 {
+		ctx.Infof("no status changes in the time window")
+		return nil
+	}
+	return c.out.Write(ctx, changes)
+}
+
+func makeModelStatusChange(t params.StatusTransition, isoTime bool) modelStatusChange {
+	change := modelStatusChange{
+		Entity:  t.Tag,
+		Type:    t.Kind,
+		Status:  t.Status,
+		Message: t.Info,
+		Data:    t.Data,
+	}
+	if tag, err := names.ParseTag(t.Tag); err == nil {
+		change.Entity = tag.Id()
+	}
+	if t.Since != nil {
+		change.Time = *t.Since
+		if isoTime {
+			change.Time = change.Time.UTC()
+		}
+	}
+	return change
+}
+
+func (c *modelStatusHistoryCommand) formatTabular(writer io.Writer, value interface{}) error {
+	changes, ok := value.([]modelStatusChange)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", changes, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+
+	w.Println("Time", "Entity", "Type", "Status", "Message")
+	for _, change := range changes {
+		since := change.Time
+		w.Print(common.FormatTime(&since, c.isoTime), change.Entity, change.Type)
+		w.PrintStatus(status.Status(change.Status))
+		w.Println(change.Message)
+	}
+	return tw.Flush()
+}
+
+func (c *modelStatusHistoryCommand) formatCSV(writer io.Writer, value interface{}) error {
+	changes, ok := value.([]modelStatusChange)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", changes, value)
+	}
+	w := csv.NewWriter(writer)
+	if err := w.Write([]string{"time", "entity", "type", "status", "message"}); err != nil {
+		return errors.Trace(err)
+	}
+	for _, change := range changes {
+		record := []string{
+			change.Time.Format(time.RFC3339Nano),
+			change.Entity,
+			change.Type,
+			change.Status,
+			change.Message,
+		}
+		if err := w.Write(record); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	w.Flush()
+	return errors.Trace(w.Error())
+}
@@ -0,0 +1,194 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status_test
+
+import (
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	statuscmd "github.com/juju/juju/cmd/juju/status"
+)
+
+type ModelStatusHistorySuite struct {
+	testing.IsolationSuite
+	api   *fakeModelHistoryAPI
+	clock *testclock.Clock
+}
+
+var _ = gc.Suite(&ModelStatusHistorySuite{})
+
+func (s *ModelStatusHistorySuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.api = &fakeModelHistoryAPI{}
+	s.clock = testclock.NewClock(time.Date(2021, 6, 1, 3, 0, 0, 0, time.UTC))
+}
+
+func (s *ModelStatusHistorySuite) newCommand() cmd.Command {
+	return statuscmd.NewTestModelStatusHistoryCommand(s.api, s.clock)
+}
+
+func (s *ModelStatusHistorySuite) at(hour, minute int) *time.Time {
+	t := time.Date(2021, 6, 1, hour, minute, 0, 0, time.UTC)
+	return &t
+}
+
+func (s *ModelStatusHistorySuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		errMatch string
+	}{{
+		args:     []string{"--type", "relation"},
+		errMatch: `unexpected status type "relation"`,
+	}, {
+		args:     []string{"--from", "yesterday"},
+		errMatch: `invalid --from: expected a time in RFC3339 format or a duration, got "yesterday"`,
+	}, {
+		args:     []string{"--to", "-5m"},
+		errMatch: `invalid --to: expected a time in RFC3339 format or a duration, got "-5m"`,
+	}, {
+		args:     []string{"--from", "1h", "--to", "2h"},
+		errMatch: "--from time must be before --to time",
+	}, {
+		args:     []string{"foo"},
+		errMatch: `unrecognized args: \["foo"\]`,
+	}} {
+		c.Logf("test %d", i)
+		err := cmdtesting.InitCommand(s.newCommand(), test.args)
+		c.Check(err, gc.ErrorMatches, test.errMatch)
+	}
+}
+
+func (s *ModelStatusHistorySuite) TestDefaultWindow(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, s.newCommand())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.args, gc.HasLen, 1)
+	c.Check(*s.api.args[0].From, gc.Equals, s.clock.Now().Add(-time.Hour))
+	c.Check(s.api.args[0].To, gc.IsNil)
+}
+
+func (s *ModelStatusHistorySuite) TestArgs(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, s.newCommand(),
+		"--from", "2d", "--to", "2021-06-01T02:15:00Z", "--type", "workload,juju-machine", "--status", "error,blocked")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.args, gc.HasLen, 1)
+	args := s.api.args[0]
+	c.Check(*args.From, gc.Equals, s.clock.Now().Add(-48*time.Hour))
+	c.Check(*args.To, gc.Equals, *s.at(2, 15))
+	c.Check(args.Kinds, jc.DeepEquals, []string{"workload", "juju-machine"})
+	c.Check(args.Statuses, jc.DeepEquals, []string{"error", "blocked"})
+	c.Check(*args.Offset, gc.Equals, 0)
+	c.Check(*args.Limit, gc.Equals, 1000)
+}
+
+func (s *ModelStatusHistorySuite) TestNoChanges(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, "no status changes in the time window\n")
+}
+
+func (s *ModelStatusHistorySuite) setTransitions() {
+	s.api.transitions = []params.StatusTransition{{
+		Tag:    "machine-1",
+		Kind:   "juju-machine",
+		Status: "down",
+		Info:   "agent is not communicating with the server",
+		Since:  s.at(2, 3),
+	}, {
+		Tag:    "unit-mysql-0",
+		Kind:   "workload",
+		Status: "blocked",
+		Info:   "waiting for quorum",
+		Since:  s.at(2, 4),
+	}, {
+		Tag:    "application-mysql",
+		Kind:   "application",
+		Status: "blocked",
+		Info:   "waiting for quorum, \"db\"",
+		Since:  s.at(2, 5),
+	}}
+}
+
+func (s *ModelStatusHistorySuite) TestTabular(c *gc.C) {
+	s.setTransitions()
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(), "--utc")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"Time                  Entity   Type          Status   Message\n"+
+		"2021-06-01 02:03:00Z  1        juju-machine  down     agent is not communicating with the server\n"+
+		"2021-06-01 02:04:00Z  mysql/0  workload      blocked  waiting for quorum\n"+
+		"2021-06-01 02:05:00Z  mysql    application   blocked  waiting for quorum, \"db\"\n"+
+		"\n")
+}
+
+func (s *ModelStatusHistorySuite) TestCSV(c *gc.C) {
+	s.setTransitions()
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(), "--format", "csv")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, ""+
+		"time,entity,type,status,message\n"+
+		"2021-06-01T02:03:00Z,1,juju-machine,down,agent is not communicating with the server\n"+
+		"2021-06-01T02:04:00Z,mysql/0,workload,blocked,waiting for quorum\n"+
+		"2021-06-01T02:05:00Z,mysql,application,blocked,\"waiting for quorum, \"\"db\"\"\"\n"+
+		"\n")
+}
+
+func (s *ModelStatusHistorySuite) TestJSON(c *gc.C) {
+	s.setTransitions()
+	s.api.transitions = s.api.transitions[1:2]
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(), "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals,
+		`[{"time":"2021-06-01T02:04:00Z","entity":"mysql/0","type":"workload","status":"blocked","message":"waiting for quorum"}]`+"\n")
+}
+
+func (s *ModelStatusHistorySuite) TestBatches(c *gc.C) {
+	for i := 0; i < 2500; i++ {
+		s.api.transitions = append(s.api.transitions, params.StatusTransition{
+			Tag:    "unit-mysql-0",
+			Kind:   "workload",
+			Status: "active",
+			Since:  s.at(2, 0),
+		})
+	}
+	_, err := cmdtesting.RunCommand(c, s.newCommand(), "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.args, gc.HasLen, 3)
+	for i, args := range s.api.args {
+		c.Check(*args.Offset, gc.Equals, i*1000)
+	}
+}
+
+type fakeModelHistoryAPI struct {
+	args        []params.ModelStatusHistoryArgs
+	transitions []params.StatusTransition
+}
+
+func (*fakeModelHistoryAPI) Close() error {
+	return nil
+}
+
+func (f *fakeModelHistoryAPI) ModelStatusHistory(args params.ModelStatusHistoryArgs) (params.ModelStatusHistoryResult, error) {
+	// Copy the args, since the command reuses them for each batch.
+	copied := args
+	offset := *args.Offset
+	copied.Offset = &offset
+	f.args = append(f.args, copied)
+
+	transitions := f.transitions[offset:]
+	var result params.ModelStatusHistoryResult
+	if len(transitions) > *args.Limit {
+		transitions = transitions[:*args.Limit]
+		result.Truncated = true
+	}
+	result.Transitions = transitions
+	return result, nil
+}
@@ -44,6 +44,7 @@ func NewStatusCommand() cmd.Command {
 // Clock defines the methods needed for the status command.
 type Clock interface {
 	After(time.Duration) <-chan time.Time
+	Now() time.Time
 }
 
 type statusCommand struct {
@@ -53,6 +54,7 @@ type statusCommand struct {
 	isoTime    bool
 	statusAPI  statusAPI
 	storageAPI storage.StorageListAPI
+	watchAPI   watchAPI
 	clock      Clock
 
 	retryCount int
@@ -68,6 +70,9 @@ type statusCommand struct {
 
 	// storage indicates if 'storage' section is displayed
 	storage bool
+
+	// watch indicates if status is kept up to date until interrupted
+	watch bool
 }
 
 var usageSummary = `
@@ -134,6 +139,22 @@ Examples:
     # Provide output as valid JSON
     juju status --format=json
 
+    # Keep the status up to date as the model changes
+    juju status --watch
+
+
+Watching status
+
+The '--watch' option keeps the status up to date as the model changes, until
+interrupted. The status is fetched once and then updated from the changes the
+controller reports, rather than being fetched again. Tabular and the other
+human readable formats are redrawn in place. With '--format=json' the status
+is written as a single line, followed by a line for each machine, application,
+unit or relation that changes.
+
+When selectors are given, only the entities in the initial status are kept up
+to date. Storage is not kept up to date.
+
 Further reading:
 
     https://juju.is/docs/command/status
@@ -164,6 +185,7 @@ func (c *statusCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.color, "color", false, "Use ANSI color codes in tabular output")
 	f.BoolVar(&c.relations, "relations", false, "Show 'relations' section in tabular output")
 	f.BoolVar(&c.storage, "storage", false, "Show 'storage' section in tabular output")
+	f.BoolVar(&c.watch, "watch", false, "Keep the status up to date until interrupted")
 
 	f.IntVar(&c.retryCount, "retry-count", 3, "Number of times to retry API failures")
 	f.DurationVar(&c.retryDelay, "retry-delay", 100*time.Millisecond, "Time to wait between retry attempts")
@@ -350,6 +372,10 @@ func (c *statusCommand) Run(ctx *cmd.Context) error {
 		}
 	}
 
+	if c.watch {
+		return c.watchStatus(ctx, formatterParams)
+	}
+
 	formatted, err := newStatusFormatter(formatterParams).format()
 	if err != nil {
 		return errors.Trace(err)
@@ -133,9 +133,11 @@ func (s *MinimalStatusSuite) TestRetryCountOfZero(c *gc.C) {
 type fakeStatusAPI struct {
 	result *params.FullStatus
 	errors []error
+	calls  int
 }
 
 func (f *fakeStatusAPI) Status(patterns []string) (*params.FullStatus, error) {
+	f.calls++
 	if len(f.errors) > 0 {
 		err, rest := f.errors[0], f.errors[1:]
 		f.errors = rest
@@ -151,6 +153,11 @@ func (*fakeStatusAPI) Close() error {
 type timeRecorder struct {
 	waits  []time.Duration
 	result chan time.Time
+	now    time.Time
+}
+
+func (r *timeRecorder) Now() time.Time {
+	return r.now
 }
 
 func (r *timeRecorder) After(d time.Duration) <-chan time.Time {
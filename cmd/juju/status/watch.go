@@ -0,0 +1,522 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/core/network"
+)
+
+// clearScreen moves the cursor to the top left of the terminal and
+// clears it, so that each re-rendered status replaces the last.
+const clearScreen = "\x1b[H\x1b[2J"
+
+// watchAPI defines the API methods used to watch the entities in a
+// model.
+type watchAPI interface {
+	WatchAll() (api.AllWatch, error)
+}
+
+type watchAPIShim struct {
+	*api.Client
+}
+
+// WatchAll implements watchAPI.
+func (s watchAPIShim) WatchAll() (api.AllWatch, error) {
+	watcher, err := s.Client.WatchAll()
+	if err != nil {
+		return nil, err
+	}
+	return watcher, nil
+}
+
+var newAPIClientForWatch = func(c *statusCommand) (watchAPI, error) {
+	if c.watchAPI == nil {
+		// The watcher shares the status client's connection, which
+		// is closed along with it.
+		client, err := c.NewAPIClient()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		c.watchAPI = watchAPIShim{client}
+	}
+	return c.watchAPI, nil
+}
+
+// entityChange identifies an entity shown in status that was changed
+// by a delta from the AllWatcher.
+type entityChange struct {
+	kind    string
+	id      string
+	removed bool
+}
+
+// statusChange is written as a single JSON line for each change to an
+// entity when watching status in json format.
+type statusChange struct {
+	Kind    string      `json:"kind"`
+	Id      string      `json:"id"`
+	Removed bool        `json:"removed,omitempty"`
+	Status  interface{} `json:"status,omitempty"`
+}
+
+// watchStatus writes the status in p, then keeps it up to date by
+// applying the deltas from the model's AllWatcher until interrupted.
+// Tabular (and the other human readable formats) are re-rendered in
+// place; in json format the initial status is followed by one line for
+// each changed entity.
+func (c *statusCommand) watchStatus(ctx *cmd.Context, p newStatusFormatterParams) error {
+	client, err := newAPIClientForWatch(c)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	watcher, err := client.WatchAll()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = watcher.Stop() }()
+
+	// Next blocks, so it is called from its own goroutine to allow
+	// interrupts to be observed. Stopping the watcher unblocks it.
+	done := make(chan struct{})
+	defer close(done)
+	deltasCh := make(chan []params.Delta)
+	errCh := make(chan error, 1)
+	go func() {
+		for {
+			deltas, err := watcher.Next()
+			if err != nil {
+				errCh <- err
+				return
+			}
+			select {
+			case deltasCh <- deltas:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	interrupted := make(chan os.Signal, 1)
+	ctx.InterruptNotify(interrupted)
+	defer ctx.StopInterruptNotify(interrupted)
+
+	if p.status.Machines == nil {
+		p.status.Machines = make(map[string]params.MachineStatus)
+	}
+	if p.status.Applications == nil {
+		p.status.Applications = make(map[string]params.ApplicationStatus)
+	}
+	if p.status.RemoteApplications == nil {
+		p.status.RemoteApplications = make(map[string]params.RemoteApplicationStatus)
+	}
+
+	jsonLines := c.out.Name() == "json"
+	if err := c.writeWatchedStatus(ctx, p, nil, jsonLines); err != nil {
+		return errors.Trace(err)
+	}
+	// New entities are only added when there are no selectors, as
+	// the status is never re-fetched to find out whether they match.
+	addNew := len(c.patterns) == 0
+	for {
+		select {
+		case deltas := <-deltasCh:
+			var changes []entityChange
+			for _, delta := range deltas {
+				if change, ok := applyDelta(p.status, delta, addNew); ok {
+					changes = append(changes, change)
+				}
+			}
+			if len(changes) == 0 {
+				continue
+			}
+			now := c.clock.Now()
+			p.status.ControllerTimestamp = &now
+			if err := c.writeWatchedStatus(ctx, p, changes, jsonLines); err != nil {
+				return errors.Trace(err)
+			}
+		case err := <-errCh:
+			return errors.Annotate(err, "watching model")
+		case <-interrupted:
+			return nil
+		}
+	}
+}
+
+// writeWatchedStatus formats the status in p and writes it out. When
+// writing JSON lines, only the entities in changes are written after
+// the initial status.
+func (c *statusCommand) writeWatchedStatus(ctx *cmd.Context, p newStatusFormatterParams, changes []entityChange, jsonLines bool) error {
+	formatted, err := newStatusFormatter(p).format()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !jsonLines {
+		fmt.Fprint(ctx.Stdout, clearScreen)
+		return c.out.Write(ctx, formatted)
+	}
+	if changes == nil {
+		return c.out.Write(ctx, formatted)
+	}
+	encoder := json.NewEncoder(ctx.Stdout)
+	for _, change := range changes {
+		line := statusChange{
+			Kind:    change.kind,
+			Id:      change.id,
+			Removed: change.removed,
+		}
+		if !change.removed {
+			line.Status = formatted.entity(change)
+		}
+		if err := encoder.Encode(line); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// entity returns the formatted status of the changed entity, or nil if
+// it isn't shown.
+func (fs formattedStatus) entity(change entityChange) interface{} {
+	switch change.kind {
+	case "model":
+		return fs.Model
+	case "machine":
+		machines := fs.Machines
+		for _, id := range machineIdPath(change.id) {
+			m, ok := machines[id]
+			if !ok {
+				return nil
+			}
+			if id == change.id {
+				return m
+			}
+			machines = m.Containers
+		}
+	case "application":
+		if app, ok := fs.Applications[change.id]; ok {
+			return app
+		}
+	case "remoteApplication":
+		if app, ok := fs.RemoteApplications[change.id]; ok {
+			return app
+		}
+	case "unit":
+		for _, app := range fs.Applications {
+			if unit, ok := findFormattedUnit(app.Units, change.id); ok {
+				return unit
+			}
+		}
+	}
+	return nil
+}
+
+func findFormattedUnit(units map[string]unitStatus, name string) (unitStatus, bool) {
+	for unitName, unit := range units {
+		if unitName == name {
+			return unit, true
+		}
+		if sub, ok := findFormattedUnit(unit.Subordinates, name); ok {
+			return sub, true
+		}
+	}
+	return unitStatus{}, false
+}
+
+// applyDelta updates status with the entity in delta, returning the
+// change made or false if the entity isn't one shown in status.
+// Entities not already in status are only added if addNew is true.
+func applyDelta(status *params.FullStatus, delta params.Delta, addNew bool) (entityChange, bool) {
+	var applied bool
+	change := entityChange{
+		kind:    delta.Entity.EntityId().Kind,
+		id:      delta.Entity.EntityId().Id,
+		removed: delta.Removed,
+	}
+	switch info := delta.Entity.(type) {
+	case *params.ModelUpdate:
+		applied = applyModelUpdate(status, info, delta.Removed)
+	case *params.MachineInfo:
+		applied = applyMachineInfo(status, info, delta.Removed, addNew)
+	case *params.ApplicationInfo:
+		applied = applyApplicationInfo(status, info, delta.Removed, addNew)
+	case *params.RemoteApplicationUpdate:
+		applied = applyRemoteApplicationUpdate(status, info, delta.Removed)
+	case *params.UnitInfo:
+		applied = applyUnitInfo(status, info, delta.Removed, addNew)
+	case *params.RelationInfo:
+		applied = applyRelationInfo(status, info, delta.Removed, addNew)
+	}
+	return change, applied
+}
+
+func applyModelUpdate(status *params.FullStatus, info *params.ModelUpdate, removed bool) bool {
+	if removed {
+		return false
+	}
+	status.Model.Name = info.Name
+	status.Model.ModelStatus = detailedStatus(info.Status, info.Life, status.Model.ModelStatus.Kind)
+	status.Model.SLA = info.SLA.Level
+	return true
+}
+
+// machineIdPath returns the IDs of the machines to descend through to
+// find the machine with the given ID in status; containers are held by
+// their host machine.
+func machineIdPath(id string) []string {
+	parts := strings.Split(id, "/")
+	path := []string{parts[0]}
+	for i := 2; i < len(parts); i += 2 {
+		path = append(path, strings.Join(parts[:i+1], "/"))
+	}
+	return path
+}
+
+func applyMachineInfo(status *params.FullStatus, info *params.MachineInfo, removed, addNew bool) bool {
+	return updateMachine(status.Machines, machineIdPath(info.Id), func(machines map[string]params.MachineStatus) bool {
+		machine, ok := machines[info.Id]
+		if removed {
+			delete(machines, info.Id)
+			return ok
+		}
+		if !ok {
+			if !addNew {
+				return false
+			}
+			machine = params.MachineStatus{
+				Id:         info.Id,
+				Containers: make(map[string]params.MachineStatus),
+			}
+		}
+		machine.AgentStatus = detailedStatus(info.AgentStatus, info.Life, machine.AgentStatus.Kind)
+		machine.InstanceStatus = detailedStatus(info.InstanceStatus, info.Life, machine.InstanceStatus.Kind)
+		machine.InstanceId = instance.Id(info.InstanceId)
+		machine.Series = info.Series
+		machine.Jobs = info.Jobs
+		machine.HasVote = info.HasVote
+		machine.WantsVote = info.WantsVote
+		if info.HardwareCharacteristics != nil {
+			machine.Hardware = info.HardwareCharacteristics.String()
+		}
+		if dnsName := publicAddress(info.Addresses); dnsName != "" {
+			machine.DNSName = dnsName
+		}
+		machines[info.Id] = machine
+		return true
+	})
+}
+
+// updateMachine calls update with the map that holds, or would hold,
+// the last machine in path, writing back any host machines above it.
+func updateMachine(machines map[string]params.MachineStatus, path []string, update func(map[string]params.MachineStatus) bool) bool {
+	if len(path) == 1 {
+		return update(machines)
+	}
+	host, ok := machines[path[0]]
+	if !ok {
+		return false
+	}
+	if host.Containers == nil {
+		host.Containers = make(map[string]params.MachineStatus)
+	}
+	if !updateMachine(host.Containers, path[1:], update) {
+		return false
+	}
+	machines[path[0]] = host
+	return true
+}
+
+// publicAddress returns the address used as a machine's DNS name,
+// preferring public addresses to cloud local ones.
+func publicAddress(addresses []params.Address) string {
+	var cloudLocal string
+	for _, addr := range addresses {
+		switch network.Scope(addr.Scope) {
+		case network.ScopePublic:
+			return addr.Value
+		case network.ScopeCloudLocal:
+			if cloudLocal == "" {
+				cloudLocal = addr.Value
+			}
+		}
+	}
+	return cloudLocal
+}
+
+func applyApplicationInfo(status *params.FullStatus, info *params.ApplicationInfo, removed, addNew bool) bool {
+	app, ok := status.Applications[info.Name]
+	if removed {
+		delete(status.Applications, info.Name)
+		return ok
+	}
+	if !ok {
+		if !addNew {
+			return false
+		}
+		app = params.ApplicationStatus{
+			Units: make(map[string]params.UnitStatus),
+		}
+	}
+	app.Charm = info.CharmURL
+	app.Exposed = info.Exposed
+	app.Life = info.Life
+	app.Status = detailedStatus(info.Status, info.Life, app.Status.Kind)
+	app.WorkloadVersion = info.WorkloadVersion
+	status.Applications[info.Name] = app
+	return true
+}
+
+func applyRemoteApplicationUpdate(status *params.FullStatus, info *params.RemoteApplicationUpdate, removed bool) bool {
+	app, ok := status.RemoteApplications[info.Name]
+	if !ok {
+		// Remote applications are created along with relations
+		// to them, so status is needed to describe their endpoints.
+		return false
+	}
+	if removed {
+		delete(status.RemoteApplications, info.Name)
+		return true
+	}
+	app.OfferURL = info.OfferURL
+	app.Life = info.Life
+	app.Status = detailedStatus(info.Status, info.Life, app.Status.Kind)
+	status.RemoteApplications[info.Name] = app
+	return true
+}
+
+func applyUnitInfo(status *params.FullStatus, info *params.UnitInfo, removed, addNew bool) bool {
+	if info.Subordinate && info.Principal != "" {
+		// Subordinate units are shown under their principal.
+		appName := strings.Split(info.Principal, "/")[0]
+		app, ok := status.Applications[appName]
+		if !ok {
+			return false
+		}
+		principal, ok := app.Units[info.Principal]
+		if !ok {
+			return false
+		}
+		if principal.Subordinates == nil {
+			principal.Subordinates = make(map[string]params.UnitStatus)
+		}
+		if !updateUnit(principal.Subordinates, info, removed, addNew) {
+			return false
+		}
+		app.Units[info.Principal] = principal
+		return true
+	}
+	app, ok := status.Applications[info.Application]
+	if !ok {
+		return false
+	}
+	if app.Units == nil {
+		app.Units = make(map[string]params.UnitStatus)
+	}
+	if !updateUnit(app.Units, info, removed, addNew) {
+		return false
+	}
+	status.Applications[info.Application] = app
+	return true
+}
+
+func updateUnit(units map[string]params.UnitStatus, info *params.UnitInfo, removed, addNew bool) bool {
+	unit, ok := units[info.Name]
+	if removed {
+		delete(units, info.Name)
+		return ok
+	}
+	if !ok && !addNew {
+		return false
+	}
+	unit.WorkloadStatus = detailedStatus(info.WorkloadStatus, info.Life, unit.WorkloadStatus.Kind)
+	unit.AgentStatus = detailedStatus(info.AgentStatus, info.Life, unit.AgentStatus.Kind)
+	unit.Machine = info.MachineId
+	unit.PublicAddress = info.PublicAddress
+	unit.Charm = info.CharmURL
+	unit.OpenedPorts = make([]string, len(info.PortRanges))
+	for i, pr := range info.PortRanges {
+		unit.OpenedPorts[i] = pr.NetworkPortRange().String()
+	}
+	units[info.Name] = unit
+	return true
+}
+
+func applyRelationInfo(status *params.FullStatus, info *params.RelationInfo, removed, addNew bool) bool {
+	index := -1
+	for i, rel := range status.Relations {
+		if rel.Key == info.Key {
+			index = i
+			break
+		}
+	}
+	if removed {
+		if index < 0 {
+			return false
+		}
+		status.Relations = append(status.Relations[:index], status.Relations[index+1:]...)
+		return true
+	}
+	var rel params.RelationStatus
+	if index >= 0 {
+		rel = status.Relations[index]
+	} else if !addNew {
+		return false
+	}
+	rel.Id = info.Id
+	rel.Key = info.Key
+	rel.Endpoints = make([]params.EndpointStatus, len(info.Endpoints))
+	for i, ep := range info.Endpoints {
+		rel.Interface = ep.Relation.Interface
+		rel.Scope = ep.Relation.Scope
+		rel.Endpoints[i] = params.EndpointStatus{
+			ApplicationName: ep.ApplicationName,
+			Name:            ep.Relation.Name,
+			Role:            ep.Relation.Role,
+			Subordinate:     isSubordinate(status, ep.ApplicationName),
+		}
+	}
+	if index >= 0 {
+		status.Relations[index] = rel
+	} else {
+		status.Relations = append(status.Relations, rel)
+	}
+	return true
+}
+
+// isSubordinate reports whether the named application is known to be a
+// subordinate; subordinate applications have no units of their own.
+func isSubordinate(status *params.FullStatus, appName string) bool {
+	app, ok := status.Applications[appName]
+	return ok && len(app.SubordinateTo) > 0
+}
+
+// detailedStatus converts the status reported by the AllWatcher into
+// the form reported by FullStatus, keeping the kind of the existing
+// status.
+func detailedStatus(info params.StatusInfo, life life.Value, kind string) params.DetailedStatus {
+	result := params.DetailedStatus{
+		Status:  info.Current.String(),
+		Info:    info.Message,
+		Data:    info.Data,
+		Since:   info.Since,
+		Kind:    kind,
+		Version: info.Version,
+		Life:    life,
+	}
+	if info.Err != nil {
+		result.Err = &params.Error{Message: info.Err.Error()}
+	}
+	return result
+}
@@ -0,0 +1,205 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/status"
+	"github.com/juju/juju/core/life"
+	corestatus "github.com/juju/juju/core/status"
+	"github.com/juju/juju/testing"
+)
+
+type WatchStatusSuite struct {
+	testing.BaseSuite
+
+	statusapi  *fakeStatusAPI
+	storageapi *mockListStorageAPI
+	watchapi   *fakeWatchAPI
+	clock      *timeRecorder
+}
+
+var _ = gc.Suite(&WatchStatusSuite{})
+
+func (s *WatchStatusSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.statusapi = &fakeStatusAPI{
+		result: &params.FullStatus{
+			Model: params.ModelStatusInfo{
+				Name:     "test",
+				CloudTag: "cloud-foo",
+			},
+			Machines: map[string]params.MachineStatus{
+				"0": {
+					Id:             "0",
+					AgentStatus:    params.DetailedStatus{Status: "started"},
+					InstanceStatus: params.DetailedStatus{Status: "running"},
+					DNSName:        "10.0.0.1",
+					InstanceId:     "inst-0",
+					Series:         "focal",
+				},
+			},
+			Applications: map[string]params.ApplicationStatus{
+				"mysql": {
+					Charm:  "cs:mysql-1",
+					Series: "focal",
+					Status: params.DetailedStatus{Status: "waiting"},
+					Units: map[string]params.UnitStatus{
+						"mysql/0": {
+							Machine:        "0",
+							WorkloadStatus: params.DetailedStatus{Status: "waiting", Info: "installing"},
+							AgentStatus:    params.DetailedStatus{Status: "executing"},
+						},
+					},
+				},
+			},
+		},
+	}
+	s.storageapi = &mockListStorageAPI{}
+	s.watchapi = &fakeWatchAPI{}
+	s.clock = &timeRecorder{now: time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)}
+	s.SetModelAndController(c, "test", "admin/test")
+}
+
+func (s *WatchStatusSuite) runStatus(c *gc.C, args ...string) (*cmd.Context, error) {
+	statusCmd := status.NewTestWatchStatusCommand(s.statusapi, s.storageapi, s.watchapi, s.clock)
+	return cmdtesting.RunCommand(c, statusCmd, args...)
+}
+
+func unitDelta(workload corestatus.Status, message string) params.Delta {
+	return params.Delta{Entity: &params.UnitInfo{
+		Name:           "mysql/0",
+		Application:    "mysql",
+		CharmURL:       "cs:mysql-1",
+		Life:           life.Alive,
+		MachineId:      "0",
+		PublicAddress:  "10.0.0.1",
+		PortRanges:     []params.PortRange{{FromPort: 3306, ToPort: 3306, Protocol: "tcp"}},
+		WorkloadStatus: params.StatusInfo{Current: workload, Message: message},
+		AgentStatus:    params.StatusInfo{Current: corestatus.Idle},
+	}}
+}
+
+func (s *WatchStatusSuite) TestWatchTabular(c *gc.C) {
+	s.watchapi.deltas = [][]params.Delta{
+		{unitDelta(corestatus.Active, "ready")},
+		// Entities that aren't shown don't cause a redraw.
+		{{Entity: &params.CharmInfo{CharmURL: "cs:mysql-1"}}},
+	}
+
+	ctx, err := s.runStatus(c, "--watch", "--utc")
+	c.Assert(err, gc.ErrorMatches, "watching model: watcher stopped")
+	c.Assert(s.watchapi.stopped, jc.IsTrue)
+	c.Assert(s.statusapi.calls, gc.Equals, 1)
+
+	screens := strings.Split(cmdtesting.Stdout(ctx), "\x1b[H\x1b[2J")
+	c.Assert(screens, gc.HasLen, 3)
+	c.Assert(screens[0], gc.Equals, "")
+	c.Assert(screens[1], jc.Contains, "mysql/0  waiting   executing  0                               installing")
+	c.Assert(screens[1], gc.Not(jc.Contains), "Timestamp")
+	c.Assert(screens[2], jc.Contains, "mysql/0  active    idle   0        10.0.0.1        3306/tcp  ready")
+	c.Assert(screens[2], jc.Contains, "12:00:00")
+}
+
+func (s *WatchStatusSuite) TestWatchJSONLines(c *gc.C) {
+	s.watchapi.deltas = [][]params.Delta{
+		{unitDelta(corestatus.Active, "ready")},
+		{{
+			Entity: &params.MachineInfo{Id: "1", Series: "focal"},
+		}, {
+			Removed: true,
+			Entity:  &params.ApplicationInfo{Name: "mysql"},
+		}},
+	}
+
+	ctx, err := s.runStatus(c, "--watch", "--format", "json")
+	c.Assert(err, gc.ErrorMatches, "watching model: watcher stopped")
+
+	lines := strings.Split(strings.TrimSuffix(cmdtesting.Stdout(ctx), "\n"), "\n")
+	c.Assert(lines, gc.HasLen, 4)
+	c.Assert(lines[0], jc.HasPrefix, `{"model":{"name":"test"`)
+	c.Assert(lines[1], jc.HasPrefix, `{"kind":"unit","id":"mysql/0","status":{"workload-status":{"current":"active","message":"ready"`)
+	c.Assert(lines[2], jc.HasPrefix, `{"kind":"machine","id":"1","status":{`)
+	c.Assert(lines[2], jc.Contains, `"series":"focal"`)
+	c.Assert(lines[3], gc.Equals, `{"kind":"application","id":"mysql","removed":true}`)
+}
+
+func (s *WatchStatusSuite) TestWatchWithSelectorsIgnoresNewEntities(c *gc.C) {
+	s.watchapi.deltas = [][]params.Delta{
+		{{Entity: &params.MachineInfo{Id: "1", Series: "focal"}}},
+		{unitDelta(corestatus.Active, "ready")},
+	}
+
+	ctx, err := s.runStatus(c, "--watch", "--format", "json", "mysql")
+	c.Assert(err, gc.ErrorMatches, "watching model: watcher stopped")
+
+	lines := strings.Split(strings.TrimSuffix(cmdtesting.Stdout(ctx), "\n"), "\n")
+	c.Assert(lines, gc.HasLen, 2)
+	c.Assert(lines[1], jc.HasPrefix, `{"kind":"unit","id":"mysql/0"`)
+}
+
+func (s *WatchStatusSuite) TestWatchContainersAndSubordinates(c *gc.C) {
+	s.watchapi.deltas = [][]params.Delta{{
+		{Entity: &params.MachineInfo{Id: "0/lxd/0", Series: "focal"}},
+		{Entity: &params.UnitInfo{
+			Name:           "logging/0",
+			Application:    "logging",
+			Subordinate:    true,
+			Principal:      "mysql/0",
+			WorkloadStatus: params.StatusInfo{Current: corestatus.Active},
+			AgentStatus:    params.StatusInfo{Current: corestatus.Idle},
+		}},
+	}}
+
+	ctx, err := s.runStatus(c, "--watch", "--format", "json")
+	c.Assert(err, gc.ErrorMatches, "watching model: watcher stopped")
+
+	lines := strings.Split(strings.TrimSuffix(cmdtesting.Stdout(ctx), "\n"), "\n")
+	c.Assert(lines, gc.HasLen, 3)
+	c.Assert(lines[1], jc.HasPrefix, `{"kind":"machine","id":"0/lxd/0","status":{`)
+	c.Assert(lines[2], jc.HasPrefix, `{"kind":"unit","id":"logging/0","status":{"workload-status":{"current":"active"`)
+}
+
+func (s *WatchStatusSuite) TestWatchAllError(c *gc.C) {
+	s.watchapi.err = errors.New("boom")
+	_, err := s.runStatus(c, "--watch")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type fakeWatchAPI struct {
+	deltas  [][]params.Delta
+	err     error
+	stopped bool
+}
+
+func (f *fakeWatchAPI) WatchAll() (api.AllWatch, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f, nil
+}
+
+func (f *fakeWatchAPI) Next() ([]params.Delta, error) {
+	if len(f.deltas) == 0 {
+		return nil, errors.New("watcher stopped")
+	}
+	deltas := f.deltas[0]
+	f.deltas = f.deltas[1:]
+	return deltas, nil
+}
+
+func (f *fakeWatchAPI) Stop() error {
+	f.stopped = true
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package waitfor
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const applicationDoc = `
+Wait for an application to match a query. By default the command waits
+for the application to be alive with an active status.
+
+The identifiers available to the query are:
+    name              the application name
+    life              alive, dying or dead
+    status            the application status, eg. active or blocked
+    status-message    the application status message
+    exposed           true if the application is exposed
+    charm-url         the URL of the application's charm
+    subordinate       true if the application is a subordinate
+    workload-version  the workload version reported by the charm
+    min-units         the minimum number of units
+
+An application that is removed while waiting has a life of dead.
+
+Examples:
+    juju wait-for application mysql
+    juju wait-for application mysql --query='status=="blocked"'
+    juju wait-for application mysql --query='life=="dead"' --timeout=5m
+
+See also:
+    wait-for unit
+    status
+`
+
+const defaultApplicationQuery = `life=="alive" && status=="active"`
+
+func newApplicationCommand() cmd.Command {
+	return modelcmd.Wrap(&applicationCommand{})
+}
+
+// applicationCommand waits for an application to match a query.
+type applicationCommand struct {
+	waitForCommandBase
+	name string
+}
+
+// Info implements Command.Info.
+func (c *applicationCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "application",
+		Args:    "<name>",
+		Purpose: "Wait for an application to reach a specified state.",
+		Doc:     applicationDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *applicationCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.setFlags(f, defaultApplicationQuery)
+}
+
+// Init implements Command.Init.
+func (c *applicationCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no application name specified")
+	}
+	name, args := args[0], args[1:]
+	if !names.IsValidApplication(name) {
+		return errors.NotValidf("application name %q", name)
+	}
+	if err := cmd.CheckEmpty(args); err != nil {
+		return errors.Trace(err)
+	}
+	c.name = name
+	return c.parseQuery(applicationScope(&params.ApplicationInfo{}))
+}
+
+// Run implements Command.Run.
+func (c *applicationCommand) Run(ctx *cmd.Context) error {
+	entity := fmt.Sprintf("application %q", c.name)
+	return c.waitFor(ctx, entity, func(info params.EntityInfo) (entityScope, bool) {
+		app, ok := info.(*params.ApplicationInfo)
+		if !ok || app.Name != c.name {
+			return nil, false
+		}
+		return applicationScope(app), true
+	})
+}
+
+func applicationScope(app *params.ApplicationInfo) entityScope {
+	return entityScope{
+		"name":             app.Name,
+		"life":             string(app.Life),
+		"status":           string(app.Status.Current),
+		"status-message":   app.Status.Message,
+		"exposed":          app.Exposed,
+		"charm-url":        app.CharmURL,
+		"subordinate":      app.Subordinate,
+		"workload-version": app.WorkloadVersion,
+		"min-units":        app.MinUnits,
+	}
+}
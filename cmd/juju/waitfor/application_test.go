@@ -0,0 +1,138 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package waitfor_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/waitfor"
+	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/core/status"
+)
+
+type applicationSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&applicationSuite{})
+
+func (s *applicationSuite) newCommand() cmd.Command {
+	return waitfor.NewApplicationCommandForTest(s.api, s.clock, s.store)
+}
+
+func (s *applicationSuite) runCommand(c *gc.C, args ...string) (*cmd.Context, error) {
+	return cmdtesting.RunCommand(c, s.newCommand(), args...)
+}
+
+func application(name string, appStatus status.Status) *params.ApplicationInfo {
+	return &params.ApplicationInfo{
+		Name:   name,
+		Life:   life.Alive,
+		Status: params.StatusInfo{Current: appStatus},
+	}
+}
+
+func (s *applicationSuite) TestInitErrors(c *gc.C) {
+	tests := []struct {
+		args []string
+		err  string
+	}{
+		{nil, "no application name specified"},
+		{[]string{"mysql/0"}, `application name "mysql/0" not valid`},
+		{[]string{"mysql", "extra"}, `unrecognized args: \["extra"\]`},
+		{[]string{"mysql", "--query", `status==`}, `invalid query: syntax error at position 8: unexpected end of query`},
+		{[]string{"mysql", "--query", `agent-status=="idle"`}, `invalid query: unknown identifier "agent-status", expected one of: .*`},
+		{[]string{"mysql", "--timeout", "0s"}, `timeout 0s not valid`},
+	}
+	for i, test := range tests {
+		c.Logf("test %d: %v", i, test.args)
+		err := cmdtesting.InitCommand(s.newCommand(), test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *applicationSuite) TestDefaultQueryMatches(c *gc.C) {
+	s.api.watcher.deltas <- []params.Delta{
+		{Entity: application("wordpress", status.Active)},
+		{Entity: application("mysql", status.Waiting)},
+	}
+	s.api.watcher.deltas <- []params.Delta{
+		{Entity: application("mysql", status.Active)},
+	}
+
+	ctx, err := s.runCommand(c, "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, `application "mysql" matched query life=="alive" && status=="active"`+"\n")
+	c.Check(s.api.closed, jc.IsTrue)
+	c.Check(s.api.watcher.stopped, jc.Satisfies, isClosed)
+}
+
+func (s *applicationSuite) TestCustomQuery(c *gc.C) {
+	app := application("mysql", status.Blocked)
+	app.Exposed = true
+	s.api.watcher.deltas <- []params.Delta{{Entity: app}}
+
+	_, err := s.runCommand(c, "mysql", "--query", `status=="blocked" && exposed`)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *applicationSuite) TestRemoved(c *gc.C) {
+	s.api.watcher.deltas <- []params.Delta{
+		{Entity: application("mysql", status.Active)},
+	}
+	s.api.watcher.deltas <- []params.Delta{
+		{Entity: application("mysql", status.Active), Removed: true},
+	}
+
+	_, err := s.runCommand(c, "mysql", "--query", `life=="dead"`)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *applicationSuite) TestTimeout(c *gc.C) {
+	s.api.watcher.deltas <- []params.Delta{
+		{Entity: application("mysql", status.Blocked)},
+	}
+
+	ctx, errc := s.runAsync(c, s.newCommand(), "mysql")
+	err := s.timeout(c, errc)
+	c.Assert(err, gc.ErrorMatches, `timed out after 10m0s waiting for application "mysql" to match query life=="alive" && status=="active"`)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, `
+application "mysql" did not match query life=="alive" && status=="active":
+    life: alive
+    status: blocked
+`[1:])
+}
+
+func (s *applicationSuite) TestTimeoutNotFound(c *gc.C) {
+	ctx, errc := s.runAsync(c, s.newCommand(), "mysql")
+	err := s.timeout(c, errc)
+	c.Assert(err, gc.ErrorMatches, `timed out after 10m0s waiting for application "mysql" .*`)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, "application \"mysql\" was not found\n")
+}
+
+func (s *applicationSuite) TestWatchAllError(c *gc.C) {
+	s.api.err = errors.New("boom")
+	_, err := s.runCommand(c, "mysql")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *applicationSuite) TestWatcherError(c *gc.C) {
+	s.api.watcher.err = errors.New("boom")
+	_, err := s.runCommand(c, "mysql")
+	c.Assert(err, gc.ErrorMatches, "watching model: boom")
+}
+
+func isClosed(ch chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
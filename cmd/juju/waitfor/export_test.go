@@ -0,0 +1,37 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package waitfor
+
+import (
+	"github.com/juju/clock"
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+)
+
+func newBase(api WatchAllAPI, clock clock.Clock, store jujuclient.ClientStore) waitForCommandBase {
+	base := waitForCommandBase{
+		newWatchAllAPIFunc: func() (WatchAllAPI, error) { return api, nil },
+		clock:              clock,
+	}
+	base.SetClientStore(store)
+	return base
+}
+
+func NewApplicationCommandForTest(api WatchAllAPI, clock clock.Clock, store jujuclient.ClientStore) cmd.Command {
+	return modelcmd.Wrap(&applicationCommand{waitForCommandBase: newBase(api, clock, store)})
+}
+
+func NewUnitCommandForTest(api WatchAllAPI, clock clock.Clock, store jujuclient.ClientStore) cmd.Command {
+	return modelcmd.Wrap(&unitCommand{waitForCommandBase: newBase(api, clock, store)})
+}
+
+func NewMachineCommandForTest(api WatchAllAPI, clock clock.Clock, store jujuclient.ClientStore) cmd.Command {
+	return modelcmd.Wrap(&machineCommand{waitForCommandBase: newBase(api, clock, store)})
+}
+
+func NewModelCommandForTest(api WatchAllAPI, clock clock.Clock, store jujuclient.ClientStore) cmd.Command {
+	return modelcmd.Wrap(&modelCommand{waitForCommandBase: newBase(api, clock, store)}, modelcmd.WrapSkipModelFlags)
+}
@@ -0,0 +1,115 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package waitfor
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const machineDoc = `
+Wait for a machine to match a query. By default the command waits for the
+machine to be alive and started.
+
+The identifiers available to the query are:
+    id                the machine ID
+    life              alive, dying or dead
+    status            the machine agent status, eg. pending or started
+    status-message    the machine agent status message
+    instance-status   the status of the machine's cloud instance
+    instance-message  the instance status message
+    instance-id       the ID of the machine's cloud instance
+    series            the machine's series
+    has-vote          true if a controller machine has a vote
+    wants-vote        true if a controller machine wants a vote
+
+A machine that is removed while waiting has a life of dead.
+
+Examples:
+    juju wait-for machine 0
+    juju wait-for machine 0/lxd/1 --query='instance-status=="running"'
+    juju wait-for machine 2 --query='life=="dead"' --timeout=5m
+
+See also:
+    wait-for unit
+    status
+`
+
+const defaultMachineQuery = `life=="alive" && status=="started"`
+
+func newMachineCommand() cmd.Command {
+	return modelcmd.Wrap(&machineCommand{})
+}
+
+// machineCommand waits for a machine to match a query.
+type machineCommand struct {
+	waitForCommandBase
+	id string
+}
+
+// Info implements Command.Info.
+func (c *machineCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "machine",
+		Args:    "<id>",
+		Purpose: "Wait for a machine to reach a specified state.",
+		Doc:     machineDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *machineCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.setFlags(f, defaultMachineQuery)
+}
+
+// Init implements Command.Init.
+func (c *machineCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no machine ID specified")
+	}
+	id, args := args[0], args[1:]
+	if !names.IsValidMachine(id) {
+		return errors.NotValidf("machine ID %q", id)
+	}
+	if err := cmd.CheckEmpty(args); err != nil {
+		return errors.Trace(err)
+	}
+	c.id = id
+	return c.parseQuery(machineScope(&params.MachineInfo{}))
+}
+
+// Run implements Command.Run.
+func (c *machineCommand) Run(ctx *cmd.Context) error {
+	entity := fmt.Sprintf("machine %q", c.id)
+	return c.waitFor(ctx, entity, func(info params.EntityInfo) (entityScope, bool) {
+		machine, ok := info.(*params.MachineInfo)
+		if !ok || machine.Id != c.id {
+			return nil, false
+		}
+		return machineScope(machine), true
+	})
+}
+
+func machineScope(machine *params.MachineInfo) entityScope {
+	return entityScope{
+		"id":               machine.Id,
+		"life":             string(machine.Life),
+		"status":           string(machine.AgentStatus.Current),
+		"status-message":   machine.AgentStatus.Message,
+		"instance-status":  string(machine.InstanceStatus.Current),
+		"instance-message": machine.InstanceStatus.Message,
+		"instance-id":      machine.InstanceId,
+		"series":           machine.Series,
+		"has-vote":         machine.HasVote,
+		"wants-vote":       machine.WantsVote,
+	}
+}
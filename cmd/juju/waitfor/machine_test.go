@@ -0,0 +1,54 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package waitfor_test
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/waitfor"
+	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/core/status"
+)
+
+type machineSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&machineSuite{})
+
+func machine(id string, agent status.Status) *params.MachineInfo {
+	return &params.MachineInfo{
+		Id:          id,
+		Life:        life.Alive,
+		AgentStatus: params.StatusInfo{Current: agent},
+	}
+}
+
+func (s *machineSuite) TestInitErrors(c *gc.C) {
+	command := waitfor.NewMachineCommandForTest(s.api, s.clock, s.store)
+	err := cmdtesting.InitCommand(command, nil)
+	c.Check(err, gc.ErrorMatches, "no machine ID specified")
+
+	command = waitfor.NewMachineCommandForTest(s.api, s.clock, s.store)
+	err = cmdtesting.InitCommand(command, []string{"mysql"})
+	c.Check(err, gc.ErrorMatches, `machine ID "mysql" not valid`)
+}
+
+func (s *machineSuite) TestDefaultQueryMatches(c *gc.C) {
+	s.api.watcher.deltas <- []params.Delta{
+		{Entity: machine("0", status.Started)},
+		{Entity: machine("0/lxd/1", status.Pending)},
+	}
+	s.api.watcher.deltas <- []params.Delta{
+		{Entity: machine("0/lxd/1", status.Started)},
+	}
+
+	command := waitfor.NewMachineCommandForTest(s.api, s.clock, s.store)
+	ctx, err := cmdtesting.RunCommand(c, command, "0/lxd/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, `machine "0/lxd/1" matched query life=="alive" && status=="started"`+"\n")
+}
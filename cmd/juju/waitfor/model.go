@@ -0,0 +1,107 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package waitfor
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const modelDoc = `
+Wait for a model to match a query. By default the command waits for the
+model to be alive and available.
+
+The identifiers available to the query are:
+    name            the model name
+    life            alive, dying or dead
+    status          the model status, eg. available or busy
+    status-message  the model status message
+    owner           the name of the model's owner
+    is-controller   true if this is the controller model
+
+A model that is destroyed while waiting has a life of dead.
+
+Examples:
+    juju wait-for model default
+    juju wait-for model default --query='life=="dead"' --timeout=20m
+
+See also:
+    wait-for application
+    show-model
+`
+
+const defaultModelQuery = `life=="alive" && status=="available"`
+
+func newModelCommand() cmd.Command {
+	return modelcmd.Wrap(&modelCommand{}, modelcmd.WrapSkipModelFlags)
+}
+
+// modelCommand waits for a model to match a query.
+type modelCommand struct {
+	waitForCommandBase
+	name string
+}
+
+// Info implements Command.Info.
+func (c *modelCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "model",
+		Args:    "<name>",
+		Purpose: "Wait for a model to reach a specified state.",
+		Doc:     modelDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *modelCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.setFlags(f, defaultModelQuery)
+}
+
+// Init implements Command.Init.
+func (c *modelCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no model name specified")
+	}
+	name, args := args[0], args[1:]
+	if err := cmd.CheckEmpty(args); err != nil {
+		return errors.Trace(err)
+	}
+	// The model being waited for is the one the API connection is made
+	// to, so that its AllWatcher reports the model's updates.
+	if err := c.SetModelIdentifier(name, false); err != nil {
+		return errors.Trace(err)
+	}
+	c.name = name
+	return c.parseQuery(modelScope(&params.ModelUpdate{}))
+}
+
+// Run implements Command.Run.
+func (c *modelCommand) Run(ctx *cmd.Context) error {
+	entity := fmt.Sprintf("model %q", c.name)
+	return c.waitFor(ctx, entity, func(info params.EntityInfo) (entityScope, bool) {
+		model, ok := info.(*params.ModelUpdate)
+		if !ok {
+			return nil, false
+		}
+		return modelScope(model), true
+	})
+}
+
+func modelScope(model *params.ModelUpdate) entityScope {
+	return entityScope{
+		"name":           model.Name,
+		"life":           string(model.Life),
+		"status":         string(model.Status.Current),
+		"status-message": model.Status.Message,
+		"owner":          model.Owner,
+		"is-controller":  model.IsController,
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package waitfor_test
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/waitfor"
+	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/core/status"
+)
+
+type modelSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&modelSuite{})
+
+func (s *modelSuite) TestInitErrors(c *gc.C) {
+	command := waitfor.NewModelCommandForTest(s.api, s.clock, s.store)
+	err := cmdtesting.InitCommand(command, nil)
+	c.Check(err, gc.ErrorMatches, "no model name specified")
+}
+
+func (s *modelSuite) TestRemoved(c *gc.C) {
+	model := &params.ModelUpdate{
+		Name:   "sword",
+		Life:   life.Dying,
+		Status: params.StatusInfo{Current: status.Destroying},
+	}
+	s.api.watcher.deltas <- []params.Delta{{Entity: model}}
+	s.api.watcher.deltas <- []params.Delta{{Entity: model, Removed: true}}
+
+	command := waitfor.NewModelCommandForTest(s.api, s.clock, s.store)
+	ctx, err := cmdtesting.RunCommand(c, command, "sword", "--query", `life=="dead"`)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, `model "sword" matched query life=="dead"`+"\n")
+}
@@ -0,0 +1,119 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package waitfor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	coretesting "github.com/juju/juju/testing"
+)
+
+func TestPackage(t *testing.T) {
+	gc.TestingT(t)
+}
+
+type baseSuite struct {
+	jujutesting.IsolationSuite
+
+	api   *fakeWatchAllAPI
+	clock *testclock.Clock
+	store jujuclient.ClientStore
+}
+
+func (s *baseSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.api = &fakeWatchAllAPI{
+		watcher: &fakeAllWatcher{
+			deltas:  make(chan []params.Delta, 10),
+			stopped: make(chan struct{}),
+		},
+	}
+	s.clock = testclock.NewClock(time.Now())
+	s.store = jujuclienttesting.MinimalStore()
+}
+
+// runAsync initialises the command and runs it in the background,
+// returning the command's context and a channel that receives its error.
+func (s *baseSuite) runAsync(c *gc.C, command cmd.Command, args ...string) (*cmd.Context, <-chan error) {
+	err := cmdtesting.InitCommand(command, args)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := cmdtesting.Context(c)
+	errc := make(chan error, 1)
+	go func() {
+		errc <- command.Run(ctx)
+	}()
+	return ctx, errc
+}
+
+// timeout advances the clock past the default timeout and returns the
+// error from the command.
+func (s *baseSuite) timeout(c *gc.C, errc <-chan error) error {
+	err := s.clock.WaitAdvance(10*time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case err := <-errc:
+		return err
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for command to finish")
+	}
+	return nil
+}
+
+type fakeWatchAllAPI struct {
+	watcher *fakeAllWatcher
+	err     error
+	closed  bool
+}
+
+func (f *fakeWatchAllAPI) WatchAll() (api.AllWatch, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.watcher, nil
+}
+
+func (f *fakeWatchAllAPI) Close() error {
+	f.closed = true
+	return nil
+}
+
+type fakeAllWatcher struct {
+	deltas  chan []params.Delta
+	stopped chan struct{}
+	err     error
+}
+
+func (f *fakeAllWatcher) Next() ([]params.Delta, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	select {
+	case deltas := <-f.deltas:
+		return deltas, nil
+	case <-f.stopped:
+		return nil, errors.New("watcher stopped")
+	}
+}
+
+func (f *fakeAllWatcher) Stop() error {
+	select {
+	case <-f.stopped:
+	default:
+		close(f.stopped)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// TokenType identifies the kind of a lexed token.
+type TokenType int
+
+const (
+	UNKNOWN TokenType = iota
+	EOF
+
+	IDENT
+	STRING
+	INT
+	FLOAT
+	BOOL
+
+	EQ     // ==
+	NEQ    // !=
+	LT     // <
+	LE     // <=
+	GT     // >
+	GE     // >=
+	AND    // &&
+	OR     // ||
+	NOT    // !
+	LPAREN // (
+	RPAREN // )
+)
+
+var tokenNames = map[TokenType]string{
+	UNKNOWN: "unknown",
+	EOF:     "end of query",
+	IDENT:   "identifier",
+	STRING:  "string",
+	INT:     "integer",
+	FLOAT:   "float",
+	BOOL:    "bool",
+	EQ:      "==",
+	NEQ:     "!=",
+	LT:      "<",
+	LE:      "<=",
+	GT:      ">",
+	GE:      ">=",
+	AND:     "&&",
+	OR:      "||",
+	NOT:     "!",
+	LPAREN:  "(",
+	RPAREN:  ")",
+}
+
+func (t TokenType) String() string {
+	if name, ok := tokenNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("token(%d)", int(t))
+}
+
+// Position is the location of a token within the query source.
+type Position struct {
+	Offset int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d", p.Offset)
+}
+
+// Token is a single lexed element of a query.
+type Token struct {
+	Type    TokenType
+	Literal string
+	Pos     Position
+}
+
+// lexer splits a query into tokens.
+type lexer struct {
+	input []rune
+	pos   int
+}
+
+func newLexer(input string) *lexer {
+	return &lexer{input: []rune(input)}
+}
+
+func (l *lexer) peekRune(offset int) rune {
+	if l.pos+offset >= len(l.input) {
+		return 0
+	}
+	return l.input[l.pos+offset]
+}
+
+// next returns the next token in the input, or a token of type EOF if
+// the input has been consumed.
+func (l *lexer) next() (Token, error) {
+	for l.pos < len(l.input) && unicode.IsSpace(l.input[l.pos]) {
+		l.pos++
+	}
+	start := Position{Offset: l.pos}
+	if l.pos >= len(l.input) {
+		return Token{Type: EOF, Pos: start}, nil
+	}
+
+	r := l.input[l.pos]
+	two := string([]rune{r, l.peekRune(1)})
+	switch two {
+	case "==":
+		l.pos += 2
+		return Token{Type: EQ, Literal: two, Pos: start}, nil
+	case "!=":
+		l.pos += 2
+		return Token{Type: NEQ, Literal: two, Pos: start}, nil
+	case "<=":
+		l.pos += 2
+		return Token{Type: LE, Literal: two, Pos: start}, nil
+	case ">=":
+		l.pos += 2
+		return Token{Type: GE, Literal: two, Pos: start}, nil
+	case "&&":
+		l.pos += 2
+		return Token{Type: AND, Literal: two, Pos: start}, nil
+	case "||":
+		l.pos += 2
+		return Token{Type: OR, Literal: two, Pos: start}, nil
+	}
+
+	switch r {
+	case '<':
+		l.pos++
+		return Token{Type: LT, Literal: "<", Pos: start}, nil
+	case '>':
+		l.pos++
+		return Token{Type: GT, Literal: ">", Pos: start}, nil
+	case '!':
+		l.pos++
+		return Token{Type: NOT, Literal: "!", Pos: start}, nil
+	case '(':
+		l.pos++
+		return Token{Type: LPAREN, Literal: "(", Pos: start}, nil
+	case ')':
+		l.pos++
+		return Token{Type: RPAREN, Literal: ")", Pos: start}, nil
+	case '"', '\'':
+		return l.lexString(start, r)
+	}
+
+	switch {
+	case unicode.IsDigit(r) || (r == '-' && unicode.IsDigit(l.peekRune(1))):
+		return l.lexNumber(start), nil
+	case isIdentStart(r):
+		return l.lexIdent(start), nil
+	}
+	return Token{}, &SyntaxError{
+		Pos:     start,
+		Message: fmt.Sprintf("unexpected character %q", r),
+	}
+}
+
+func (l *lexer) lexString(start Position, quote rune) (Token, error) {
+	l.pos++
+	var b strings.Builder
+	for l.pos < len(l.input) {
+		r := l.input[l.pos]
+		switch {
+		case r == '\\' && l.pos+1 < len(l.input):
+			b.WriteRune(l.input[l.pos+1])
+			l.pos += 2
+		case r == quote:
+			l.pos++
+			return Token{Type: STRING, Literal: b.String(), Pos: start}, nil
+		default:
+			b.WriteRune(r)
+			l.pos++
+		}
+	}
+	return Token{}, &SyntaxError{Pos: start, Message: "unterminated string"}
+}
+
+func (l *lexer) lexNumber(start Position) Token {
+	tokenType := INT
+	if l.input[l.pos] == '-' {
+		l.pos++
+	}
+	for l.pos < len(l.input) {
+		r := l.input[l.pos]
+		if r == '.' && tokenType == INT && unicode.IsDigit(l.peekRune(1)) {
+			tokenType = FLOAT
+		} else if !unicode.IsDigit(r) {
+			break
+		}
+		l.pos++
+	}
+	return Token{Type: tokenType, Literal: string(l.input[start.Offset:l.pos]), Pos: start}
+}
+
+func (l *lexer) lexIdent(start Position) Token {
+	for l.pos < len(l.input) && isIdentPart(l.input[l.pos]) {
+		l.pos++
+	}
+	literal := string(l.input[start.Offset:l.pos])
+	if literal == "true" || literal == "false" {
+		return Token{Type: BOOL, Literal: literal, Pos: start}
+	}
+	return Token{Type: IDENT, Literal: literal, Pos: start}
+}
+
+func isIdentStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+// isIdentPart allows hyphens so that identifiers can mirror the
+// hyphenated keys used in juju status output, such as agent-status.
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r) || r == '-' || r == '.'
+}
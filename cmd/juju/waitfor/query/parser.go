@@ -0,0 +1,239 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package query
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// SyntaxError is returned when a query cannot be parsed.
+type SyntaxError struct {
+	Pos     Position
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %v: %s", e.Pos, e.Message)
+}
+
+// Expression is a node in a parsed query.
+type Expression interface {
+	fmt.Stringer
+}
+
+// Identifier references a value supplied by the Scope.
+type Identifier struct {
+	Token Token
+}
+
+func (e *Identifier) String() string { return e.Token.Literal }
+
+// Literal is a constant string, number or bool value.
+type Literal struct {
+	Token Token
+	Value interface{}
+}
+
+func (e *Literal) String() string {
+	if e.Token.Type == STRING {
+		return strconv.Quote(e.Token.Literal)
+	}
+	return e.Token.Literal
+}
+
+// Prefix applies a unary operator to its operand.
+type Prefix struct {
+	Operator Token
+	Right    Expression
+}
+
+func (e *Prefix) String() string {
+	return fmt.Sprintf("%s%v", e.Operator.Literal, e.Right)
+}
+
+// Infix applies a binary operator to its operands.
+type Infix struct {
+	Left     Expression
+	Operator Token
+	Right    Expression
+}
+
+func (e *Infix) String() string {
+	return fmt.Sprintf("(%v %s %v)", e.Left, e.Operator.Literal, e.Right)
+}
+
+// parser is a recursive descent parser over the tokens produced by
+// the lexer. In order of increasing precedence the grammar is:
+//
+//	or         := and ("||" and)*
+//	and        := unary ("&&" unary)*
+//	unary      := "!" unary | comparison
+//	comparison := primary (("==" | "!=" | "<" | "<=" | ">" | ">=") primary)?
+//	primary    := IDENT | STRING | INT | FLOAT | BOOL | "(" or ")"
+type parser struct {
+	lex     *lexer
+	current Token
+}
+
+func newParser(input string) (*parser, error) {
+	p := &parser{lex: newLexer(input)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.current = tok
+	return nil
+}
+
+func (p *parser) parse() (Expression, error) {
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.current.Type != EOF {
+		return nil, p.unexpected()
+	}
+	return expr, nil
+}
+
+func (p *parser) unexpected() error {
+	var found string
+	switch p.current.Type {
+	case EOF:
+		found = EOF.String()
+	case IDENT, STRING, INT, FLOAT, BOOL:
+		found = fmt.Sprintf("%s %q", p.current.Type, p.current.Literal)
+	default:
+		found = fmt.Sprintf("%q", p.current.Literal)
+	}
+	return &SyntaxError{
+		Pos:     p.current.Pos,
+		Message: "unexpected " + found,
+	}
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.current.Type == OR {
+		op := p.current
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Infix{Left: left, Operator: op, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.current.Type == AND {
+		op := p.current
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &Infix{Left: left, Operator: op, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expression, error) {
+	if p.current.Type != NOT {
+		return p.parseComparison()
+	}
+	op := p.current
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	right, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return &Prefix{Operator: op, Right: right}, nil
+}
+
+func (p *parser) parseComparison() (Expression, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	switch p.current.Type {
+	case EQ, NEQ, LT, LE, GT, GE:
+	default:
+		return left, nil
+	}
+	op := p.current
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return &Infix{Left: left, Operator: op, Right: right}, nil
+}
+
+func (p *parser) parsePrimary() (Expression, error) {
+	tok := p.current
+	var expr Expression
+	switch tok.Type {
+	case IDENT:
+		expr = &Identifier{Token: tok}
+	case STRING:
+		expr = &Literal{Token: tok, Value: tok.Literal}
+	case INT:
+		value, err := strconv.ParseInt(tok.Literal, 10, 64)
+		if err != nil {
+			return nil, &SyntaxError{Pos: tok.Pos, Message: err.Error()}
+		}
+		expr = &Literal{Token: tok, Value: value}
+	case FLOAT:
+		value, err := strconv.ParseFloat(tok.Literal, 64)
+		if err != nil {
+			return nil, &SyntaxError{Pos: tok.Pos, Message: err.Error()}
+		}
+		expr = &Literal{Token: tok, Value: value}
+	case BOOL:
+		expr = &Literal{Token: tok, Value: tok.Literal == "true"}
+	case LPAREN:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.current.Type != RPAREN {
+			return nil, p.unexpected()
+		}
+		expr = inner
+	default:
+		return nil, p.unexpected()
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
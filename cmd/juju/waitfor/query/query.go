@@ -0,0 +1,270 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package query implements the small expression language used by the
+// wait-for commands to describe the state an entity should reach, for
+// example:
+//
+//	status=="active" && agent-status=="idle"
+//
+// Identifiers are resolved against a Scope when the query is run.
+// Strings, integers, floats and bools can be compared with ==, !=, <,
+// <=, > and >=, and combined with &&, || and !.
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// Scope resolves the identifiers used in a query.
+type Scope interface {
+	// GetIdentValue returns the value of the named identifier. The value
+	// must be a string, int64, float64 or bool.
+	GetIdentValue(name string) (interface{}, error)
+
+	// GetIdents returns the names of all the identifiers in the scope.
+	GetIdents() []string
+}
+
+// InvalidIdentifierError is returned when a query references an
+// identifier that its scope doesn't know about.
+type InvalidIdentifierError struct {
+	Name   string
+	Idents []string
+}
+
+func (e *InvalidIdentifierError) Error() string {
+	idents := append([]string(nil), e.Idents...)
+	sort.Strings(idents)
+	return fmt.Sprintf("unknown identifier %q, expected one of: %s", e.Name, strings.Join(idents, ", "))
+}
+
+// IsInvalidIdentifierErr returns true if the error is an
+// InvalidIdentifierError.
+func IsInvalidIdentifierErr(err error) bool {
+	_, ok := errors.Cause(err).(*InvalidIdentifierError)
+	return ok
+}
+
+// Query is a parsed query expression.
+type Query struct {
+	source string
+	expr   Expression
+}
+
+// Parse parses the query source.
+func Parse(source string) (Query, error) {
+	p, err := newParser(source)
+	if err != nil {
+		return Query{}, errors.Trace(err)
+	}
+	expr, err := p.parse()
+	if err != nil {
+		return Query{}, errors.Trace(err)
+	}
+	return Query{source: source, expr: expr}, nil
+}
+
+// String returns the source the query was parsed from.
+func (q Query) String() string {
+	return q.source
+}
+
+// Identifiers returns the names of the identifiers referenced by the
+// query, sorted and without duplicates.
+func (q Query) Identifiers() []string {
+	seen := make(map[string]bool)
+	var walk func(Expression)
+	walk = func(expr Expression) {
+		switch e := expr.(type) {
+		case *Identifier:
+			seen[e.Token.Literal] = true
+		case *Prefix:
+			walk(e.Right)
+		case *Infix:
+			walk(e.Left)
+			walk(e.Right)
+		}
+	}
+	walk(q.expr)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run evaluates the query against the scope. The query must evaluate
+// to a bool.
+func (q Query) Run(scope Scope) (bool, error) {
+	if q.expr == nil {
+		return false, errors.New("empty query")
+	}
+	value, err := eval(q.expr, scope)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	result, ok := value.(bool)
+	if !ok {
+		return false, errors.Errorf("query %q evaluated to %v, expected a bool", q.source, value)
+	}
+	return result, nil
+}
+
+func eval(expr Expression, scope Scope) (interface{}, error) {
+	switch e := expr.(type) {
+	case *Literal:
+		return e.Value, nil
+	case *Identifier:
+		value, err := scope.GetIdentValue(e.Token.Literal)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return normalise(value), nil
+	case *Prefix:
+		right, err := eval(e.Right, scope)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		b, ok := right.(bool)
+		if !ok {
+			return nil, errors.Errorf("cannot apply ! to %v", e.Right)
+		}
+		return !b, nil
+	case *Infix:
+		return evalInfix(e, scope)
+	}
+	return nil, errors.Errorf("unexpected expression %v", expr)
+}
+
+func evalInfix(e *Infix, scope Scope) (interface{}, error) {
+	left, err := eval(e.Left, scope)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Logical operators short circuit, so that the right hand side is
+	// only evaluated when it can change the result.
+	switch e.Operator.Type {
+	case AND, OR:
+		l, ok := left.(bool)
+		if !ok {
+			return nil, errors.Errorf("cannot apply %s to %v", e.Operator.Literal, e.Left)
+		}
+		if (e.Operator.Type == AND && !l) || (e.Operator.Type == OR && l) {
+			return l, nil
+		}
+		right, err := eval(e.Right, scope)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		r, ok := right.(bool)
+		if !ok {
+			return nil, errors.Errorf("cannot apply %s to %v", e.Operator.Literal, e.Right)
+		}
+		return r, nil
+	}
+
+	right, err := eval(e.Right, scope)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cmp, err := compare(left, right)
+	if err != nil {
+		return nil, errors.Annotatef(err, "evaluating %v", e)
+	}
+	switch e.Operator.Type {
+	case EQ:
+		return cmp == 0, nil
+	case NEQ:
+		return cmp != 0, nil
+	case LT:
+		return cmp < 0, nil
+	case LE:
+		return cmp <= 0, nil
+	case GT:
+		return cmp > 0, nil
+	case GE:
+		return cmp >= 0, nil
+	}
+	return nil, errors.Errorf("unexpected operator %q", e.Operator.Literal)
+}
+
+// normalise converts the values supplied by a scope into the types used
+// for literals, so that they can be compared.
+func normalise(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case float32:
+		return float64(v)
+	case fmt.Stringer:
+		return v.String()
+	}
+	return value
+}
+
+// compare returns -1, 0 or 1 depending on whether left is less than,
+// equal to or greater than right. Integers and floats can be compared
+// with each other, but otherwise both sides must be of the same type.
+func compare(left, right interface{}) (int, error) {
+	switch l := left.(type) {
+	case string:
+		if r, ok := right.(string); ok {
+			return strings.Compare(l, r), nil
+		}
+	case bool:
+		if r, ok := right.(bool); ok {
+			if l == r {
+				return 0, nil
+			}
+			if !l {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	case int64:
+		switch r := right.(type) {
+		case int64:
+			return compareInts(l, r), nil
+		case float64:
+			return compareFloats(float64(l), r), nil
+		}
+	case float64:
+		switch r := right.(type) {
+		case int64:
+			return compareFloats(l, float64(r)), nil
+		case float64:
+			return compareFloats(l, r), nil
+		}
+	}
+	return 0, errors.Errorf("cannot compare %T with %T", left, right)
+}
+
+func compareInts(l, r int64) int {
+	switch {
+	case l < r:
+		return -1
+	case l > r:
+		return 1
+	}
+	return 0
+}
+
+func compareFloats(l, r float64) int {
+	switch {
+	case l < r:
+		return -1
+	case l > r:
+		return 1
+	}
+	return 0
+}
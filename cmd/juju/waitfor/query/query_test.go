@@ -0,0 +1,141 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package query_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/waitfor/query"
+)
+
+type querySuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&querySuite{})
+
+type mapScope map[string]interface{}
+
+func (s mapScope) GetIdentValue(name string) (interface{}, error) {
+	value, ok := s[name]
+	if !ok {
+		return nil, &query.InvalidIdentifierError{Name: name, Idents: s.GetIdents()}
+	}
+	return value, nil
+}
+
+func (s mapScope) GetIdents() []string {
+	var idents []string
+	for name := range s {
+		idents = append(idents, name)
+	}
+	return idents
+}
+
+var scope = mapScope{
+	"status":       "active",
+	"agent-status": "idle",
+	"life":         "alive",
+	"units":        3,
+	"ratio":        0.5,
+	"exposed":      false,
+}
+
+func (s *querySuite) TestRun(c *gc.C) {
+	tests := []struct {
+		query    string
+		expected bool
+	}{
+		{`status=="active"`, true},
+		{`status == 'active'`, true},
+		{`status!="active"`, false},
+		{`status=="active" && agent-status=="idle"`, true},
+		{`status=="active" && agent-status=="executing"`, false},
+		{`status=="blocked" || agent-status=="idle"`, true},
+		{`!(status=="blocked")`, true},
+		{`!exposed`, true},
+		{`exposed == false`, true},
+		{`units >= 3`, true},
+		{`units > 3`, false},
+		{`units < 4 && units <= 3`, true},
+		{`ratio < 1`, true},
+		{`ratio == 0.5`, true},
+		{`units > -1`, true},
+		{`true`, true},
+		{`(status=="blocked" || life=="alive") && units==3`, true},
+		{`status=="blocked" || status=="waiting" || status=="active"`, true},
+	}
+	for i, test := range tests {
+		c.Logf("test %d: %s", i, test.query)
+		q, err := query.Parse(test.query)
+		c.Assert(err, jc.ErrorIsNil)
+		result, err := q.Run(scope)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(result, gc.Equals, test.expected)
+	}
+}
+
+func (s *querySuite) TestShortCircuit(c *gc.C) {
+	q, err := query.Parse(`status=="blocked" && unknown=="x"`)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := q.Run(scope)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result, jc.IsFalse)
+}
+
+func (s *querySuite) TestParseErrors(c *gc.C) {
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{``, `syntax error at position 0: unexpected end of query`},
+		{`status==`, `syntax error at position 8: unexpected end of query`},
+		{`status=="active`, `syntax error at position 8: unterminated string`},
+		{`status = "active"`, `syntax error at position 7: unexpected character '='`},
+		{`(status=="active"`, `syntax error at position 17: unexpected end of query`},
+		{`status=="active" life`, `syntax error at position 17: unexpected identifier "life"`},
+		{`status == == "active"`, `syntax error at position 10: unexpected "=="`},
+	}
+	for i, test := range tests {
+		c.Logf("test %d: %s", i, test.query)
+		_, err := query.Parse(test.query)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *querySuite) TestRunErrors(c *gc.C) {
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{`foo=="bar"`, `unknown identifier "foo", expected one of: agent-status, exposed, life, ratio, status, units`},
+		{`status==1`, `evaluating \(status == 1\): cannot compare string with int64`},
+		{`status`, `query "status" evaluated to active, expected a bool`},
+		{`!status`, `cannot apply ! to status`},
+		{`status && exposed`, `cannot apply && to status`},
+	}
+	for i, test := range tests {
+		c.Logf("test %d: %s", i, test.query)
+		q, err := query.Parse(test.query)
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = q.Run(scope)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *querySuite) TestInvalidIdentifierErr(c *gc.C) {
+	q, err := query.Parse(`foo=="bar"`)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = q.Run(scope)
+	c.Check(query.IsInvalidIdentifierErr(err), jc.IsTrue)
+}
+
+func (s *querySuite) TestIdentifiers(c *gc.C) {
+	q, err := query.Parse(`status=="active" && (agent-status=="idle" || status=="blocked") && !exposed`)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(q.Identifiers(), jc.DeepEquals, []string{"agent-status", "exposed", "status"})
+	c.Check(q.String(), gc.Equals, `status=="active" && (agent-status=="idle" || status=="blocked") && !exposed`)
+}
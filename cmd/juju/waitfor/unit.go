@@ -0,0 +1,121 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package waitfor
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+const unitDoc = `
+Wait for a unit to match a query. By default the command waits for the
+unit to be alive with an active workload status.
+
+The identifiers available to the query are:
+    name              the unit name
+    application       the name of the unit's application
+    life              alive, dying or dead
+    status            the workload status, eg. active or blocked
+    workload-status   the workload status, the same as status
+    workload-message  the workload status message
+    agent-status      the agent status, eg. idle or executing
+    agent-message     the agent status message
+    machine           the ID of the machine hosting the unit
+    public-address    the unit's public address
+    private-address   the unit's private address
+    subordinate       true if the unit is a subordinate
+    principal         the name of a subordinate unit's principal
+
+A unit that is removed while waiting has a life of dead.
+
+Examples:
+    juju wait-for unit mysql/0
+    juju wait-for unit mysql/0 --query='status=="active" && agent-status=="idle"'
+    juju wait-for unit mysql/0 --query='life=="dead"' --timeout=5m
+
+See also:
+    wait-for application
+    status
+`
+
+const defaultUnitQuery = `life=="alive" && workload-status=="active"`
+
+func newUnitCommand() cmd.Command {
+	return modelcmd.Wrap(&unitCommand{})
+}
+
+// unitCommand waits for a unit to match a query.
+type unitCommand struct {
+	waitForCommandBase
+	name string
+}
+
+// Info implements Command.Info.
+func (c *unitCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "unit",
+		Args:    "<name>",
+		Purpose: "Wait for a unit to reach a specified state.",
+		Doc:     unitDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *unitCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.setFlags(f, defaultUnitQuery)
+}
+
+// Init implements Command.Init.
+func (c *unitCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no unit name specified")
+	}
+	name, args := args[0], args[1:]
+	if !names.IsValidUnit(name) {
+		return errors.NotValidf("unit name %q", name)
+	}
+	if err := cmd.CheckEmpty(args); err != nil {
+		return errors.Trace(err)
+	}
+	c.name = name
+	return c.parseQuery(unitScope(&params.UnitInfo{}))
+}
+
+// Run implements Command.Run.
+func (c *unitCommand) Run(ctx *cmd.Context) error {
+	entity := fmt.Sprintf("unit %q", c.name)
+	return c.waitFor(ctx, entity, func(info params.EntityInfo) (entityScope, bool) {
+		unit, ok := info.(*params.UnitInfo)
+		if !ok || unit.Name != c.name {
+			return nil, false
+		}
+		return unitScope(unit), true
+	})
+}
+
+func unitScope(unit *params.UnitInfo) entityScope {
+	return entityScope{
+		"name":             unit.Name,
+		"application":      unit.Application,
+		"life":             string(unit.Life),
+		"status":           string(unit.WorkloadStatus.Current),
+		"workload-status":  string(unit.WorkloadStatus.Current),
+		"workload-message": unit.WorkloadStatus.Message,
+		"agent-status":     string(unit.AgentStatus.Current),
+		"agent-message":    unit.AgentStatus.Message,
+		"machine":          unit.MachineId,
+		"public-address":   unit.PublicAddress,
+		"private-address":  unit.PrivateAddress,
+		"subordinate":      unit.Subordinate,
+		"principal":        unit.Principal,
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package waitfor_test
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/waitfor"
+	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/core/status"
+)
+
+type unitSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&unitSuite{})
+
+func unit(name string, workload, agent status.Status) *params.UnitInfo {
+	return &params.UnitInfo{
+		Name:           name,
+		Life:           life.Alive,
+		WorkloadStatus: params.StatusInfo{Current: workload},
+		AgentStatus:    params.StatusInfo{Current: agent},
+	}
+}
+
+func (s *unitSuite) TestInitErrors(c *gc.C) {
+	command := waitfor.NewUnitCommandForTest(s.api, s.clock, s.store)
+	err := cmdtesting.InitCommand(command, nil)
+	c.Check(err, gc.ErrorMatches, "no unit name specified")
+
+	command = waitfor.NewUnitCommandForTest(s.api, s.clock, s.store)
+	err = cmdtesting.InitCommand(command, []string{"mysql"})
+	c.Check(err, gc.ErrorMatches, `unit name "mysql" not valid`)
+}
+
+func (s *unitSuite) TestQueryMatches(c *gc.C) {
+	s.api.watcher.deltas <- []params.Delta{
+		{Entity: unit("mysql/0", status.Active, status.Executing)},
+		{Entity: unit("mysql/1", status.Active, status.Idle)},
+	}
+	s.api.watcher.deltas <- []params.Delta{
+		{Entity: unit("mysql/0", status.Active, status.Idle)},
+	}
+
+	command := waitfor.NewUnitCommandForTest(s.api, s.clock, s.store)
+	_, err := cmdtesting.RunCommand(c, command, "mysql/0", "--query", `status=="active" && agent-status=="idle"`)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *unitSuite) TestTimeout(c *gc.C) {
+	s.api.watcher.deltas <- []params.Delta{
+		{Entity: unit("mysql/0", status.Waiting, status.Executing)},
+	}
+
+	command := waitfor.NewUnitCommandForTest(s.api, s.clock, s.store)
+	ctx, errc := s.runAsync(c, command, "mysql/0")
+	err := s.timeout(c, errc)
+	c.Assert(err, gc.ErrorMatches, `timed out after 10m0s waiting for unit "mysql/0" .*`)
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, `
+unit "mysql/0" did not match query life=="alive" && workload-status=="active":
+    life: alive
+    workload-status: waiting
+`[1:])
+}
@@ -0,0 +1,243 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package waitfor
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/waitfor/query"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/core/life"
+)
+
+const waitForDoc = `
+The wait-for set of commands block until an entity in the model reaches
+the state described by a query, or until a timeout expires.
+
+Queries are made up of identifiers, which are resolved against the entity
+being waited on, and string, number or bool values, compared with ==, !=,
+<, <=, > and >=. Comparisons can be combined with && and ||, negated with
+!, and grouped with parentheses. See the help for each entity type for the
+identifiers it supports.
+
+If the timeout expires before the query matches, the current values of the
+identifiers used in the query are shown, and the command exits with an
+error.
+`
+
+const defaultTimeout = 10 * time.Minute
+
+// NewWaitForCommand returns the wait-for super-command.
+func NewWaitForCommand() cmd.Command {
+	waitFor := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "wait-for",
+		Doc:         waitForDoc,
+		UsagePrefix: "juju",
+		Purpose:     "Wait for an entity to reach a specified state.",
+	})
+	waitFor.Register(newApplicationCommand())
+	waitFor.Register(newUnitCommand())
+	waitFor.Register(newMachineCommand())
+	waitFor.Register(newModelCommand())
+	return waitFor
+}
+
+// WatchAllAPI defines the API methods used to watch the entities in a
+// model.
+type WatchAllAPI interface {
+	WatchAll() (api.AllWatch, error)
+	Close() error
+}
+
+type watchAllAPIShim struct {
+	*api.Client
+}
+
+// WatchAll implements WatchAllAPI.
+func (s watchAllAPIShim) WatchAll() (api.AllWatch, error) {
+	watcher, err := s.Client.WatchAll()
+	if err != nil {
+		return nil, err
+	}
+	return watcher, nil
+}
+
+// entityScope holds the values of the identifiers that a query may use
+// for a single entity.
+type entityScope map[string]interface{}
+
+// GetIdentValue implements query.Scope.
+func (s entityScope) GetIdentValue(name string) (interface{}, error) {
+	value, ok := s[name]
+	if !ok {
+		return nil, &query.InvalidIdentifierError{Name: name, Idents: s.GetIdents()}
+	}
+	return value, nil
+}
+
+// GetIdents implements query.Scope.
+func (s entityScope) GetIdents() []string {
+	idents := make([]string, 0, len(s))
+	for name := range s {
+		idents = append(idents, name)
+	}
+	sort.Strings(idents)
+	return idents
+}
+
+// scopeFunc returns the scope for an entity reported by the AllWatcher,
+// or false if the entity is not the one being waited for.
+type scopeFunc func(params.EntityInfo) (entityScope, bool)
+
+// waitForCommandBase holds the flags and behaviour shared by the
+// wait-for subcommands.
+type waitForCommandBase struct {
+	modelcmd.ModelCommandBase
+
+	newWatchAllAPIFunc func() (WatchAllAPI, error)
+	clock              clock.Clock
+
+	query   string
+	timeout time.Duration
+
+	parsedQuery query.Query
+}
+
+// setFlags registers the flags shared by all the subcommands, using
+// defaultQuery when --query isn't given.
+func (c *waitForCommandBase) setFlags(f *gnuflag.FlagSet, defaultQuery string) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.query, "query", defaultQuery, "Query the entity must match")
+	f.DurationVar(&c.timeout, "timeout", defaultTimeout, "How long to wait before giving up")
+}
+
+// parseQuery parses the query flag, checking that it only uses
+// identifiers known to the given scope.
+func (c *waitForCommandBase) parseQuery(known entityScope) error {
+	if c.timeout <= 0 {
+		return errors.NotValidf("timeout %v", c.timeout)
+	}
+	q, err := query.Parse(c.query)
+	if err != nil {
+		return errors.Annotate(err, "invalid query")
+	}
+	for _, name := range q.Identifiers() {
+		if _, err := known.GetIdentValue(name); err != nil {
+			return errors.Annotate(err, "invalid query")
+		}
+	}
+	c.parsedQuery = q
+	return nil
+}
+
+func (c *waitForCommandBase) newWatchAllAPI() (WatchAllAPI, error) {
+	if c.newWatchAllAPIFunc != nil {
+		return c.newWatchAllAPIFunc()
+	}
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return watchAllAPIShim{client}, nil
+}
+
+func (c *waitForCommandBase) getClock() clock.Clock {
+	if c.clock != nil {
+		return c.clock
+	}
+	return clock.WallClock
+}
+
+// waitFor watches the model until the entity described by entity and
+// selected by scopeFor matches the query, or the timeout expires.
+func (c *waitForCommandBase) waitFor(ctx *cmd.Context, entity string, scopeFor scopeFunc) error {
+	client, err := c.newWatchAllAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	watcher, err := client.WatchAll()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = watcher.Stop() }()
+
+	// Next blocks, so it is called from its own goroutine to allow the
+	// timeout to be observed. Stopping the watcher unblocks it.
+	done := make(chan struct{})
+	defer close(done)
+	deltasCh := make(chan []params.Delta)
+	errCh := make(chan error, 1)
+	go func() {
+		for {
+			deltas, err := watcher.Next()
+			if err != nil {
+				errCh <- err
+				return
+			}
+			select {
+			case deltasCh <- deltas:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	timeout := c.getClock().After(c.timeout)
+	var current entityScope
+	for {
+		select {
+		case deltas := <-deltasCh:
+			for _, delta := range deltas {
+				scope, ok := scopeFor(delta.Entity)
+				if !ok {
+					continue
+				}
+				if delta.Removed {
+					scope["life"] = string(life.Dead)
+				}
+				current = scope
+			}
+			if current == nil {
+				continue
+			}
+			matched, err := c.parsedQuery.Run(current)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if matched {
+				ctx.Infof("%s matched query %s", entity, c.parsedQuery)
+				return nil
+			}
+		case err := <-errCh:
+			return errors.Annotate(err, "watching model")
+		case <-timeout:
+			c.writeSummary(ctx, entity, current)
+			return errors.Errorf("timed out after %v waiting for %s to match query %s", c.timeout, entity, c.parsedQuery)
+		}
+	}
+}
+
+// writeSummary reports the values of the identifiers used by the query,
+// so that it is clear which parts of it did not converge.
+func (c *waitForCommandBase) writeSummary(ctx *cmd.Context, entity string, current entityScope) {
+	if current == nil {
+		fmt.Fprintf(ctx.Stderr, "%s was not found\n", entity)
+		return
+	}
+	fmt.Fprintf(ctx.Stderr, "%s did not match query %s:\n", entity, c.parsedQuery)
+	for _, name := range c.parsedQuery.Identifiers() {
+		fmt.Fprintf(ctx.Stderr, "    %s: %v\n", name, current[name])
+	}
+}
@@ -155,6 +155,7 @@ var (
 		"storage-provisioner",
 		"upgrade-series",
 		"unconverted-api-workers",
+		"wrench-updater",
 	}
 )
 
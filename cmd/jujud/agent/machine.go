@@ -841,6 +841,7 @@ func (a *MachineAgent) openStateForUpgrade() (*state.StatePool, error) {
 		// to pass in the max-txn-log-size value.
 		InitDatabaseFunc:       state.InitDatabase,
 		RunTransactionObserver: a.mongoTxnCollector.AfterRunTransaction,
+		DataDir:                agentConfig.DataDir(),
 	})
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -997,6 +998,7 @@ func (a *MachineAgent) initController(agentConfig agent.Config) (*state.Controll
 		MongoSession:           session,
 		NewPolicy:              stateenvirons.GetNewPolicyFunc(),
 		RunTransactionObserver: a.mongoTxnCollector.AfterRunTransaction,
+		DataDir:                agentConfig.DataDir(),
 	})
 	return ctrl, errors.Trace(err)
 }
@@ -1216,6 +1218,7 @@ func openStatePool(
 		MongoSession:           session,
 		NewPolicy:              stateenvirons.GetNewPolicyFunc(),
 		RunTransactionObserver: runTransactionObserver,
+		DataDir:                agentConfig.DataDir(),
 	})
 	if err != nil {
 		return nil, err
@@ -42,7 +42,10 @@ import (
 	"github.com/juju/juju/worker/apiserver"
 	"github.com/juju/juju/worker/apiservercertwatcher"
 	"github.com/juju/juju/worker/auditconfigupdater"
+	"github.com/juju/juju/worker/auditlogquery"
 	"github.com/juju/juju/worker/authenticationworker"
+	"github.com/juju/juju/worker/backupscheduler"
+	"github.com/juju/juju/worker/bundledrift"
 	"github.com/juju/juju/worker/caasupgrader"
 	"github.com/juju/juju/worker/centralhub"
 	"github.com/juju/juju/worker/certupdater"
@@ -83,6 +86,7 @@ import (
 	"github.com/juju/juju/worker/raft/raftforwarder"
 	"github.com/juju/juju/worker/raft/rafttransport"
 	"github.com/juju/juju/worker/reboot"
+	"github.com/juju/juju/worker/restoreflag"
 	"github.com/juju/juju/worker/resumer"
 	"github.com/juju/juju/worker/singular"
 	workerstate "github.com/juju/juju/worker/state"
@@ -95,6 +99,8 @@ import (
 	"github.com/juju/juju/worker/upgrader"
 	"github.com/juju/juju/worker/upgradeseries"
 	"github.com/juju/juju/worker/upgradesteps"
+	"github.com/juju/juju/worker/webhooks"
+	"github.com/juju/juju/worker/wrenchupdater"
 )
 
 const (
@@ -311,6 +317,11 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 	agentTag := agentConfig.Tag()
 	controllerTag := agentConfig.Controller()
 
+	// restoreStarted is unlocked when a backup starts being restored
+	// onto this controller, and stays unlocked until the agent is
+	// restarted.
+	restoreStarted := gate.NewLock()
+
 	manifolds := dependency.Manifolds{
 		// The agent manifold references the enclosing agent, and is the
 		// foundation stone on which most other manifolds ultimately depend.
@@ -602,6 +613,17 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 			UpdateAgentFunc: config.UpdateLoggerConfig,
 		})),
 
+		// The wrench updater is a leaf worker that activates the
+		// wrenches (fault injection points) listed in the model
+		// config. It uninstalls itself unless the remote-wrenches
+		// developer feature flag is set. The wrenches apply to the
+		// whole process, so we should only need one of these in a
+		// consolidated agent.
+		wrenchUpdaterName: ifNotMigrating(wrenchupdater.Manifold(wrenchupdater.ManifoldConfig{
+			APICallerName: apiCallerName,
+			Logger:        loggo.GetLogger("juju.worker.wrenchupdater"),
+		})),
+
 		// The log sender is a leaf worker that sends log messages to some
 		// API server, when configured so to do. We should only need one of
 		// these in a consolidated agent.
@@ -636,14 +658,36 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 			},
 		))),
 
-		txnPrunerName: ifNotMigrating(ifPrimaryController(txnpruner.Manifold(
+		txnPrunerName: ifNotMigrating(ifNotRestoring(ifPrimaryController(txnpruner.Manifold(
 			txnpruner.ManifoldConfig{
 				ClockName:     clockName,
 				StateName:     stateName,
 				PruneInterval: config.TransactionPruneInterval,
 				NewWorker:     txnpruner.New,
 			},
-		))),
+		)))),
+
+		// The webhook notifier watches all the models on the
+		// controller, and notifies the webhooks registered in them of
+		// the events they want.
+		webhookNotifierName: ifNotMigrating(ifNotRestoring(ifPrimaryController(webhooks.Manifold(webhooks.ManifoldConfig{
+			ClockName:        clockName,
+			StateName:        stateName,
+			MultiwatcherName: multiwatcherName,
+			Logger:           loggo.GetLogger("juju.worker.webhooks"),
+			NewWorker:        webhooks.NewWorker,
+		})))),
+
+		// The bundle drift worker compares the models on the
+		// controller with their desired bundles, and records how they
+		// have drifted from them.
+		bundleDriftName: ifNotMigrating(ifNotRestoring(ifPrimaryController(bundledrift.Manifold(bundledrift.ManifoldConfig{
+			ClockName:            clockName,
+			StateName:            stateName,
+			Logger:               loggo.GetLogger("juju.worker.bundledrift"),
+			PrometheusRegisterer: config.PrometheusRegisterer,
+			NewWorker:            bundledrift.NewWorker,
+		})))),
 
 		httpServerArgsName: httpserverargs.Manifold(httpserverargs.ManifoldConfig{
 			ClockName:             clockName,
@@ -708,7 +752,7 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 			NewMetricsCollector:               apiserver.NewMetricsCollector,
 		})),
 
-		modelWorkerManagerName: ifFullyUpgraded(modelworkermanager.Manifold(modelworkermanager.ManifoldConfig{
+		modelWorkerManagerName: ifFullyUpgraded(ifNotRestoring(modelworkermanager.Manifold(modelworkermanager.ManifoldConfig{
 			AgentName:      agentName,
 			AuthorityName:  certificateWatcherName,
 			StateName:      stateName,
@@ -717,9 +761,9 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 			NewWorker:      modelworkermanager.New,
 			NewModelWorker: config.NewModelWorker,
 			Logger:         loggo.GetLogger("juju.workers.modelworkermanager"),
-		})),
+		}))),
 
-		peergrouperName: ifFullyUpgraded(peergrouper.Manifold(peergrouper.ManifoldConfig{
+		peergrouperName: ifFullyUpgraded(ifNotRestoring(peergrouper.Manifold(peergrouper.ManifoldConfig{
 			AgentName:            agentName,
 			ClockName:            clockName,
 			ControllerPortName:   controllerPortName,
@@ -727,14 +771,41 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 			Hub:                  config.CentralHub,
 			PrometheusRegisterer: config.PrometheusRegisterer,
 			NewWorker:            peergrouper.New,
-		})),
+		}))),
 
 		auditConfigUpdaterName: ifController(auditconfigupdater.Manifold(auditconfigupdater.ManifoldConfig{
 			AgentName: agentName,
 			StateName: stateName,
+			Clock:     config.Clock,
 			NewWorker: auditconfigupdater.New,
 		})),
 
+		// The audit log query worker answers requests for the records
+		// in this controller's audit log, so that the API server can
+		// aggregate them across all controllers.
+		auditLogQueryName: ifController(auditlogquery.Manifold(auditlogquery.ManifoldConfig{
+			CentralHubName: centralHubName,
+			LogDir:         agentConfig.LogDir(),
+			Logger:         loggo.GetLogger("juju.worker.auditlogquery"),
+			NewWorker:      auditlogquery.NewWorker,
+		})),
+
+		// The restore fortress is occupied by the controller workers
+		// that write to the database, and the restore flag is set
+		// while no backup is being restored. When a restore starts,
+		// the flag worker clears the flag and locks down the fortress,
+		// so that the restore only replaces the database once those
+		// workers have stopped.
+		restoreFortressName: ifController(fortress.Manifold()),
+		restoreFlagName: ifController(restoreflag.Manifold(restoreflag.ManifoldConfig{
+			AgentName:      agentName,
+			CentralHubName: centralHubName,
+			FortressName:   restoreFortressName,
+			Started:        restoreStarted,
+			Logger:         loggo.GetLogger("juju.worker.restoreflag"),
+			NewWorker:      restoreflag.NewManifoldWorker,
+		})),
+
 		raftTransportName: ifController(rafttransport.Manifold(rafttransport.ManifoldConfig{
 			ClockName:         clockName,
 			AgentName:         agentName,
@@ -872,6 +943,18 @@ func IAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 			NewMachineAddressWatcher: certupdater.NewMachineAddressWatcher,
 		})),
 
+		// The backup scheduler creates backups of the controller on
+		// the schedule in controller config. Backups aren't supported
+		// on kubernetes controllers.
+		backupSchedulerName: ifNotMigrating(ifNotRestoring(ifPrimaryController(backupscheduler.Manifold(backupscheduler.ManifoldConfig{
+			AgentName:            agentName,
+			ClockName:            clockName,
+			StateName:            stateName,
+			Logger:               loggo.GetLogger("juju.worker.backupscheduler"),
+			PrometheusRegisterer: config.PrometheusRegisterer,
+			NewWorker:            backupscheduler.NewWorker,
+		})))),
+
 		// The machiner Worker will wait for the identified machine to become
 		// Dying and make it Dead; or until the machine becomes Dead by other
 		// means. This worker needs to be launched after fanconfigurer
@@ -1033,6 +1116,17 @@ var ifNotMigrating = engine.Housing{
 	Occupy: migrationFortressName,
 }.Decorate
 
+// ifNotRestoring stops the controller workers that write to the
+// database while a backup is being restored. Workers that are also
+// guarded against migrations occupy both fortresses; the migration
+// fortress is always entered first, so the two can't deadlock.
+var ifNotRestoring = engine.Housing{
+	Flags: []string{
+		restoreFlagName,
+	},
+	Occupy: restoreFortressName,
+}.Decorate
+
 var ifPrimaryController = engine.Housing{
 	Flags: []string{
 		isPrimaryControllerFlagName,
@@ -1098,9 +1192,13 @@ const (
 	migrationInactiveFlagName = "migration-inactive-flag"
 	migrationMinionName       = "migration-minion"
 
+	restoreFortressName = "restore-fortress"
+	restoreFlagName     = "restore-flag"
+
 	apiWorkersName                = "unconverted-api-workers"
 	rebootName                    = "reboot-executor"
 	loggingConfigUpdaterName      = "logging-config-updater"
+	wrenchUpdaterName             = "wrench-updater"
 	diskManagerName               = "disk-manager"
 	proxyConfigUpdater            = "proxy-config-updater"
 	apiAddressUpdaterName         = "api-address-updater"
@@ -1130,6 +1228,10 @@ const (
 	peergrouperName               = "peer-grouper"
 	certificateUpdaterName        = "certificate-updater"
 	auditConfigUpdaterName        = "audit-config-updater"
+	auditLogQueryName             = "audit-log-query"
+	backupSchedulerName           = "backup-scheduler"
+	webhookNotifierName           = "webhook-notifier"
+	bundleDriftName               = "bundle-drift"
 	leaseManagerName              = "lease-manager"
 
 	upgradeSeriesWorkerName = "upgrade-series"
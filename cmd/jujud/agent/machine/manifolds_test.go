@@ -65,7 +65,10 @@ func (ms *ManifoldsSuite) TestManifoldNamesIAAS(c *gc.C) {
 			"api-config-watcher",
 			"api-server",
 			"audit-config-updater",
+			"audit-log-query",
+			"backup-scheduler",
 			"broker-tracker",
+			"bundle-drift",
 			"central-hub",
 			"certificate-updater",
 			"certificate-watcher",
@@ -107,6 +110,8 @@ func (ms *ManifoldsSuite) TestManifoldNamesIAAS(c *gc.C) {
 			"raft-leader-flag",
 			"raft-transport",
 			"reboot-executor",
+			"restore-flag",
+			"restore-fortress",
 			"ssh-authkeys-updater",
 			"ssh-identity-writer",
 			"state",
@@ -127,6 +132,8 @@ func (ms *ManifoldsSuite) TestManifoldNamesIAAS(c *gc.C) {
 			"upgrade-steps-runner",
 			"upgrader",
 			"valid-credential-flag",
+			"webhook-notifier",
+			"wrench-updater",
 		},
 	)
 }
@@ -143,6 +150,8 @@ func (ms *ManifoldsSuite) TestManifoldNamesCAAS(c *gc.C) {
 			"api-config-watcher",
 			"api-server",
 			"audit-config-updater",
+			"audit-log-query",
+			"bundle-drift",
 			"central-hub",
 			"certificate-watcher",
 			"clock",
@@ -175,6 +184,8 @@ func (ms *ManifoldsSuite) TestManifoldNamesCAAS(c *gc.C) {
 			"raft-forwarder",
 			"raft-leader-flag",
 			"raft-transport",
+			"restore-flag",
+			"restore-fortress",
 			"ssh-identity-writer",
 			"state",
 			"state-config-watcher",
@@ -191,6 +202,8 @@ func (ms *ManifoldsSuite) TestManifoldNamesCAAS(c *gc.C) {
 			"upgrade-steps-runner",
 			"upgrader",
 			"valid-credential-flag",
+			"webhook-notifier",
+			"wrench-updater",
 		},
 	)
 }
@@ -222,6 +235,7 @@ func (ms *ManifoldsSuite) TestMigrationGuardsUsed(c *gc.C) {
 		"api-config-watcher",
 		"api-server",
 		"audit-config-updater",
+		"audit-log-query",
 		"certificate-updater",
 		"certificate-watcher",
 		"central-hub",
@@ -268,6 +282,8 @@ func (ms *ManifoldsSuite) TestMigrationGuardsUsed(c *gc.C) {
 		"raft-forwarder",
 		"raft-leader-flag",
 		"raft-transport",
+		"restore-flag",
+		"restore-fortress",
 		"valid-credential-flag",
 	)
 	manifolds := machine.IAASManifolds(machine.ManifoldsConfig{
@@ -289,6 +305,7 @@ func (*ManifoldsSuite) TestSingularGuardsUsed(c *gc.C) {
 	controllerWorkers := set.NewStrings(
 		"certificate-watcher",
 		"audit-config-updater",
+		"audit-log-query",
 		"is-primary-controller-flag",
 		"model-cache",
 		"model-cache-initialized-flag",
@@ -297,13 +314,18 @@ func (*ManifoldsSuite) TestSingularGuardsUsed(c *gc.C) {
 		"lease-manager",
 		"legacy-leases-flag",
 		"raft-transport",
+		"restore-flag",
+		"restore-fortress",
 		"upgrade-database-flag",
 		"upgrade-database-gate",
 		"upgrade-database-runner",
 	)
 	primaryControllerWorkers := set.NewStrings(
+		"backup-scheduler",
+		"bundle-drift",
 		"external-controller-updater",
 		"transaction-pruner",
+		"webhook-notifier",
 	)
 	for name, manifold := range manifolds {
 		c.Logf(name)
@@ -464,6 +486,34 @@ var expectedMachineManifoldsWithDependencies = map[string][]string{
 		"state-config-watcher",
 	},
 
+	"audit-log-query": {
+		"agent",
+		"central-hub",
+		"is-controller-flag",
+		"state",
+		"state-config-watcher",
+	},
+
+	"backup-scheduler": {
+		"agent",
+		"api-caller",
+		"api-config-watcher",
+		"central-hub",
+		"clock",
+		"is-controller-flag",
+		"is-primary-controller-flag",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"restore-flag",
+		"restore-fortress",
+		"state",
+		"state-config-watcher",
+		"upgrade-check-flag",
+		"upgrade-check-gate",
+		"upgrade-steps-flag",
+		"upgrade-steps-gate",
+	},
+
 	"broker-tracker": {
 		"agent",
 		"api-caller",
@@ -476,6 +526,26 @@ var expectedMachineManifoldsWithDependencies = map[string][]string{
 		"upgrade-steps-gate",
 	},
 
+	"bundle-drift": {
+		"agent",
+		"api-caller",
+		"api-config-watcher",
+		"central-hub",
+		"clock",
+		"is-controller-flag",
+		"is-primary-controller-flag",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"restore-flag",
+		"restore-fortress",
+		"state",
+		"state-config-watcher",
+		"upgrade-check-flag",
+		"upgrade-check-gate",
+		"upgrade-steps-flag",
+		"upgrade-steps-gate",
+	},
+
 	"central-hub": {"agent", "state-config-watcher"},
 
 	"certificate-updater": {
@@ -773,6 +843,8 @@ var expectedMachineManifoldsWithDependencies = map[string][]string{
 		"controller-port",
 		"http-server-args",
 		"is-controller-flag",
+		"restore-flag",
+		"restore-fortress",
 		"state",
 		"state-config-watcher",
 		"upgrade-check-flag",
@@ -795,6 +867,9 @@ var expectedMachineManifoldsWithDependencies = map[string][]string{
 		"central-hub",
 		"clock",
 		"controller-port",
+		"is-controller-flag",
+		"restore-flag",
+		"restore-fortress",
 		"state",
 		"state-config-watcher",
 		"upgrade-check-flag",
@@ -932,6 +1007,22 @@ var expectedMachineManifoldsWithDependencies = map[string][]string{
 		"upgrade-steps-gate",
 	},
 
+	"restore-flag": {
+		"agent",
+		"central-hub",
+		"is-controller-flag",
+		"restore-fortress",
+		"state",
+		"state-config-watcher",
+	},
+
+	"restore-fortress": {
+		"agent",
+		"is-controller-flag",
+		"state",
+		"state-config-watcher",
+	},
+
 	"ssh-authkeys-updater": {
 		"agent",
 		"api-caller",
@@ -991,11 +1082,14 @@ var expectedMachineManifoldsWithDependencies = map[string][]string{
 		"agent",
 		"api-caller",
 		"api-config-watcher",
+		"central-hub",
 		"clock",
 		"is-controller-flag",
 		"is-primary-controller-flag",
 		"migration-fortress",
 		"migration-inactive-flag",
+		"restore-flag",
+		"restore-fortress",
 		"state",
 		"state-config-watcher",
 		"upgrade-check-flag",
@@ -1079,6 +1173,41 @@ var expectedMachineManifoldsWithDependencies = map[string][]string{
 		"api-caller",
 		"api-config-watcher",
 	},
+
+	"webhook-notifier": {
+		"agent",
+		"api-caller",
+		"api-config-watcher",
+		"central-hub",
+		"clock",
+		"is-controller-flag",
+		"is-primary-controller-flag",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"multiwatcher",
+		"restore-flag",
+		"restore-fortress",
+		"state",
+		"state-config-watcher",
+		"upgrade-check-flag",
+		"upgrade-check-gate",
+		"upgrade-database-flag",
+		"upgrade-database-gate",
+		"upgrade-steps-flag",
+		"upgrade-steps-gate",
+	},
+
+	"wrench-updater": {
+		"agent",
+		"api-caller",
+		"api-config-watcher",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"upgrade-check-flag",
+		"upgrade-check-gate",
+		"upgrade-steps-flag",
+		"upgrade-steps-gate",
+	},
 }
 
 type mockAgent struct {
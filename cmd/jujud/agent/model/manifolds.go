@@ -14,9 +14,11 @@ import (
 
 	coreagent "github.com/juju/juju/agent"
 	"github.com/juju/juju/api"
+	actionschedulesapi "github.com/juju/juju/api/actionschedules"
 	"github.com/juju/juju/api/base"
 	caasfirewallerapi "github.com/juju/juju/api/caasfirewaller"
 	caasunitprovisionerapi "github.com/juju/juju/api/caasunitprovisioner"
+	rolloutsapi "github.com/juju/juju/api/rollouts"
 	"github.com/juju/juju/apiserver/apiserverhttp"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/caas"
@@ -41,6 +43,7 @@ import (
 	"github.com/juju/juju/worker/cleaner"
 	"github.com/juju/juju/worker/common"
 	"github.com/juju/juju/worker/credentialvalidator"
+	"github.com/juju/juju/worker/duework"
 	"github.com/juju/juju/worker/environ"
 	"github.com/juju/juju/worker/firewaller"
 	"github.com/juju/juju/worker/fortress"
@@ -283,6 +286,22 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 			Clock:         config.Clock,
 			Logger:        config.LoggingContext.GetLogger("juju.worker.cleaner"),
 		})),
+		operationRolloutsName: ifNotMigrating(duework.Manifold(duework.ManifoldConfig{
+			APICallerName: apiCallerName,
+			Clock:         config.Clock,
+			Logger:        config.LoggingContext.GetLogger("juju.worker.rollouts"),
+			NewRunner: func(apiCaller base.APICaller) duework.Runner {
+				return rolloutsapi.NewAPI(apiCaller)
+			},
+		})),
+		actionSchedulerName: ifNotMigrating(duework.Manifold(duework.ManifoldConfig{
+			APICallerName: apiCallerName,
+			Clock:         config.Clock,
+			Logger:        config.LoggingContext.GetLogger("juju.worker.actionscheduler"),
+			NewRunner: func(apiCaller base.APICaller) duework.Runner {
+				return actionschedulesapi.NewAPI(apiCaller)
+			},
+		})),
 		statusHistoryPrunerName: ifNotMigrating(pruner.Manifold(pruner.ManifoldConfig{
 			APICallerName: apiCallerName,
 			Clock:         config.Clock,
@@ -303,8 +322,9 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 			APICallerName: apiCallerName,
 			Sinks: []logforwarder.LogSinkSpec{{
 				Name:   "juju-log-forward",
-				OpenFn: sinks.OpenSyslog,
+				OpenFn: sinks.Open,
 			}},
+			Clock:  config.Clock,
 			Logger: config.LoggingContext.GetLogger("juju.worker.logforwarder"),
 		})),
 		// The environ upgrader runs on all controller agents, and
@@ -680,6 +700,8 @@ const (
 	stateCleanerName         = "state-cleaner"
 	statusHistoryPrunerName  = "status-history-pruner"
 	actionPrunerName         = "action-pruner"
+	actionSchedulerName      = "action-scheduler"
+	operationRolloutsName    = "operation-rollouts"
 	machineUndertakerName    = "machine-undertaker"
 	remoteRelationsName      = "remote-relations"
 	logForwarderName         = "log-forwarder"
@@ -35,6 +35,7 @@ func (s *ManifoldsSuite) TestIAASNames(c *gc.C) {
 	// also fail. Search for 'ModelWorkers' to find affected vars.
 	c.Check(actual.SortedValues(), jc.DeepEquals, []string{
 		"action-pruner",
+		"action-scheduler",
 		"agent",
 		"api-caller",
 		"api-config-watcher",
@@ -59,6 +60,7 @@ func (s *ManifoldsSuite) TestIAASNames(c *gc.C) {
 		"model-upgrader",
 		"not-alive-flag",
 		"not-dead-flag",
+		"operation-rollouts",
 		"remote-relations",
 		"state-cleaner",
 		"status-history-pruner",
@@ -82,6 +84,7 @@ func (s *ManifoldsSuite) TestCAASNames(c *gc.C) {
 	// also fail. Search for 'ModelWorkers' to find affected vars.
 	c.Check(actual.SortedValues(), jc.DeepEquals, []string{
 		"action-pruner",
+		"action-scheduler",
 		"agent",
 		"api-caller",
 		"api-config-watcher",
@@ -106,6 +109,7 @@ func (s *ManifoldsSuite) TestCAASNames(c *gc.C) {
 		"model-upgrader",
 		"not-alive-flag",
 		"not-dead-flag",
+		"operation-rollouts",
 		"remote-relations",
 		"state-cleaner",
 		"status-history-pruner",
@@ -212,6 +216,16 @@ var expectedCAASModelManifoldsWithDependencies = map[string][]string{
 		"model-upgraded-flag",
 		"not-dead-flag"},
 
+	"action-scheduler": {
+		"agent",
+		"api-caller",
+		"is-responsible-flag",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"model-upgrade-gate",
+		"model-upgraded-flag",
+		"not-dead-flag"},
+
 	"agent": {},
 
 	"api-caller": {"agent"},
@@ -362,6 +376,16 @@ var expectedCAASModelManifoldsWithDependencies = map[string][]string{
 
 	"not-dead-flag": {"agent", "api-caller"},
 
+	"operation-rollouts": {
+		"agent",
+		"api-caller",
+		"is-responsible-flag",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"model-upgrade-gate",
+		"model-upgraded-flag",
+		"not-dead-flag"},
+
 	"remote-relations": {
 		"agent",
 		"api-caller",
@@ -418,6 +442,17 @@ var expectedIAASModelManifoldsWithDependencies = map[string][]string{
 		"not-dead-flag",
 	},
 
+	"action-scheduler": {
+		"agent",
+		"api-caller",
+		"is-responsible-flag",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"model-upgrade-gate",
+		"model-upgraded-flag",
+		"not-dead-flag",
+	},
+
 	"agent": {},
 
 	"api-caller": {"agent"},
@@ -592,6 +627,16 @@ var expectedIAASModelManifoldsWithDependencies = map[string][]string{
 
 	"not-dead-flag": {"agent", "api-caller"},
 
+	"operation-rollouts": {
+		"agent",
+		"api-caller",
+		"is-responsible-flag",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"model-upgrade-gate",
+		"model-upgraded-flag",
+		"not-dead-flag"},
+
 	"remote-relations": {
 		"agent",
 		"api-caller",
@@ -78,6 +78,7 @@ func (*importSuite) TestImports(c *gc.C) {
 		"feature",
 		"juju/osenv",
 		"logfwd",
+		"logfwd/httpfwd",
 		"logfwd/syslog",
 		"mongo",
 		"network",
@@ -101,6 +102,7 @@ func (*importSuite) TestImports(c *gc.C) {
 		"version",
 		"worker",
 		"worker/apicaller",
+		"wrench",
 	)
 
 	unexpected := found.Difference(expected)
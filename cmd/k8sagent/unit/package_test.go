@@ -145,6 +145,7 @@ func (*importSuite) TestImports(c *gc.C) {
 		"juju/sockets",
 		"jujuclient",
 		"logfwd",
+		"logfwd/httpfwd",
 		"logfwd/syslog",
 		"mongo", // TODO: move mongo dependency from JUJU CLI if we decide to split the `agent.Config` for controller and machineagent/unitagent/k8sagent.
 		"network",
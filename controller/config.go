@@ -6,7 +6,9 @@ package controller
 import (
 	"fmt"
 	"net/url"
+	"path/filepath"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/juju/charmrepo/v6/csclient"
@@ -18,9 +20,14 @@ import (
 	"github.com/juju/utils/v2"
 	"gopkg.in/juju/environschema.v1"
 	"gopkg.in/macaroon-bakery.v2/bakery"
+	"gopkg.in/robfig/cron.v2"
 
 	"github.com/juju/juju/core/resources"
+	"github.com/juju/juju/core/secrets"
+	"github.com/juju/juju/logfwd/httpfwd"
+	"github.com/juju/juju/logfwd/syslog"
 	"github.com/juju/juju/pki"
+	"github.com/juju/juju/secretbackend/vault"
 )
 
 const (
@@ -82,6 +89,123 @@ const (
 	// interesting calls though.)
 	AuditLogExcludeMethods = "audit-log-exclude-methods"
 
+	// AuditLogTargets is the list of places audit records are sent
+	// to: any of "file", "syslog" and "http". Each record is sent to
+	// all of them.
+	AuditLogTargets = "audit-log-targets"
+
+	// AuditLogBufferSize is the maximum size of the unsent records
+	// kept on disk for each remote audit log target while it can't be
+	// reached, eg "100M".
+	AuditLogBufferSize = "audit-log-buffer-size"
+
+	// AuditLogBufferFullFail makes API requests fail when a remote
+	// audit log target's buffer is full, rather than dropping their
+	// audit records for that target.
+	AuditLogBufferFullFail = "audit-log-buffer-full-fail"
+
+	// AuditLogSyslogHost is the host-port of the syslog server audit
+	// records are sent to when "syslog" is one of the audit log
+	// targets.
+	AuditLogSyslogHost = "audit-log-syslog-host"
+
+	// AuditLogSyslogCACert is the CA certificate used to validate the
+	// audit log syslog server's certificate.
+	AuditLogSyslogCACert = "audit-log-syslog-ca-cert"
+
+	// AuditLogSyslogClientCert is the client certificate used when
+	// connecting to the audit log syslog server.
+	AuditLogSyslogClientCert = "audit-log-syslog-client-cert"
+
+	// AuditLogSyslogClientKey is the key for the client certificate
+	// used when connecting to the audit log syslog server. It's a
+	// secret attribute.
+	AuditLogSyslogClientKey = "audit-log-syslog-client-key"
+
+	// AuditLogHTTPURL is the endpoint audit records are posted to when
+	// "http" is one of the audit log targets.
+	AuditLogHTTPURL = "audit-log-http-url"
+
+	// AuditLogHTTPCACert is the CA certificate used to validate the
+	// audit log HTTP endpoint's certificate.
+	AuditLogHTTPCACert = "audit-log-http-ca-cert"
+
+	// AuditLogHTTPUsername and AuditLogHTTPPassword are the basic
+	// authentication credentials for the audit log HTTP endpoint. The
+	// password is a secret attribute.
+	AuditLogHTTPUsername = "audit-log-http-username"
+	AuditLogHTTPPassword = "audit-log-http-password"
+
+	// BackupSchedule is when the controller creates backups of itself,
+	// as a cron spec ("0 3 * * *") or descriptor ("@daily", "@every 6h").
+	// No scheduled backups are made if it is empty.
+	BackupSchedule = "backup-schedule"
+
+	// BackupRetentionCount is the number of scheduled backups kept in
+	// the controller's backup storage. Older scheduled backups are
+	// removed; backups created with create-backup are never removed.
+	BackupRetentionCount = "backup-retention-count"
+
+	// BackupRetentionAge is how long scheduled backups are kept in the
+	// controller's backup storage, eg "720h". Scheduled backups are kept
+	// regardless of their age if it is empty.
+	BackupRetentionAge = "backup-retention-age"
+
+	// BackupCopyDir is a directory on the controller machines that each
+	// scheduled backup is copied to, typically a mounted network share.
+	BackupCopyDir = "backup-copy-dir"
+
+	// ActionResultsArchiveDir is a directory on the controller machines
+	// that action results are archived to before they're pruned, for
+	// the models whose action-results-archive is "directory". It should
+	// be shared by all of the controller machines, typically a mounted
+	// network share, as any of them may prune a model's results.
+	ActionResultsArchiveDir = "action-results-archive-dir"
+
+	// BackupS3Endpoint is the URL of the S3-compatible service each
+	// scheduled backup is uploaded to. AWS S3 is used if it is empty and
+	// a bucket is configured.
+	BackupS3Endpoint = "backup-s3-endpoint"
+
+	// BackupS3Bucket is the bucket scheduled backups are uploaded to.
+	// Backups are only uploaded if it is set.
+	BackupS3Bucket = "backup-s3-bucket"
+
+	// BackupS3Region is the region of the backup S3 bucket.
+	BackupS3Region = "backup-s3-region"
+
+	// BackupS3AccessKey and BackupS3SecretKey are the credentials used
+	// to upload scheduled backups. They're secret attributes.
+	BackupS3AccessKey = "backup-s3-access-key"
+	BackupS3SecretKey = "backup-s3-secret-key"
+
+	// SecretBackendVaultAddress is the URL of the vault server used by
+	// models whose secret-backend is "vault".
+	SecretBackendVaultAddress = "secret-backend-vault-address"
+
+	// SecretBackendVaultToken is the token used to authenticate with
+	// the vault server. It's a secret attribute.
+	SecretBackendVaultToken = "secret-backend-vault-token"
+
+	// SecretBackendVaultMount is the mount path of the vault KV
+	// version 2 secrets engine.
+	SecretBackendVaultMount = "secret-backend-vault-mount"
+
+	// SecretBackendVaultCACert is the certificate of the CA that signed
+	// the vault server's certificate.
+	SecretBackendVaultCACert = "secret-backend-vault-ca-cert"
+
+	// SecretBackendFileDir is the directory, relative to the data dir
+	// of each controller machine, that models whose secret-backend is
+	// "file" store values in.
+	SecretBackendFileDir = "secret-backend-file-dir"
+
+	// AuditLogTargetFile, AuditLogTargetSyslog and AuditLogTargetHTTP
+	// are the values allowed in the audit-log-targets list.
+	AuditLogTargetFile   = "file"
+	AuditLogTargetSyslog = "syslog"
+	AuditLogTargetHTTP   = "http"
+
 	// ReadOnlyMethodsWildcard is the special value that can be added
 	// to the exclude-methods list that represents all of the read
 	// only methods (see apiserver/observer/auditfilter.go). This
@@ -225,6 +349,18 @@ const (
 	// keep.
 	DefaultAuditLogMaxBackups = 10
 
+	// DefaultAuditLogBufferSizeMB is the default size in MB of the
+	// unsent records kept for each remote audit log target.
+	DefaultAuditLogBufferSizeMB = 100
+
+	// DefaultBackupRetentionCount is the default number of scheduled
+	// backups kept.
+	DefaultBackupRetentionCount = 7
+
+	// DefaultSecretBackendFileDir is the default directory, relative
+	// to the data dir, used by the file secret backend.
+	DefaultSecretBackendFileDir = "secret-backend"
+
 	// DefaultNUMAControlPolicy should not be used by default.
 	// Only use numactl if user specifically requests it
 	DefaultNUMAControlPolicy = false
@@ -358,6 +494,32 @@ var (
 		AuditLogMaxSize,
 		AuditLogMaxBackups,
 		AuditLogExcludeMethods,
+		AuditLogTargets,
+		AuditLogBufferSize,
+		AuditLogBufferFullFail,
+		AuditLogSyslogHost,
+		AuditLogSyslogCACert,
+		AuditLogSyslogClientCert,
+		AuditLogSyslogClientKey,
+		AuditLogHTTPURL,
+		AuditLogHTTPCACert,
+		AuditLogHTTPUsername,
+		AuditLogHTTPPassword,
+		BackupSchedule,
+		BackupRetentionCount,
+		BackupRetentionAge,
+		BackupCopyDir,
+		ActionResultsArchiveDir,
+		BackupS3Endpoint,
+		BackupS3Bucket,
+		BackupS3Region,
+		BackupS3AccessKey,
+		BackupS3SecretKey,
+		SecretBackendVaultAddress,
+		SecretBackendVaultToken,
+		SecretBackendVaultMount,
+		SecretBackendVaultCACert,
+		SecretBackendFileDir,
 		CAASOperatorImagePath,
 		CAASImageRepo,
 		Features,
@@ -389,6 +551,20 @@ var (
 		AuditingEnabled,
 		AuditLogCaptureArgs,
 		AuditLogExcludeMethods,
+		BackupSchedule,
+		BackupRetentionCount,
+		BackupRetentionAge,
+		BackupCopyDir,
+		ActionResultsArchiveDir,
+		BackupS3Endpoint,
+		BackupS3Bucket,
+		BackupS3Region,
+		BackupS3AccessKey,
+		BackupS3SecretKey,
+		SecretBackendVaultAddress,
+		SecretBackendVaultToken,
+		SecretBackendVaultMount,
+		SecretBackendVaultCACert,
 		// TODO Juju 3.0: ControllerAPIPort should be required and treated
 		// more like api-port.
 		ControllerAPIPort,
@@ -421,6 +597,18 @@ var (
 		ReadOnlyMethodsWildcard,
 	}
 
+	// DefaultAuditLogTargets is the default list of audit log
+	// targets, which only writes records to the local log file.
+	DefaultAuditLogTargets = []string{
+		AuditLogTargetFile,
+	}
+
+	auditLogTargetValues = set.NewStrings(
+		AuditLogTargetFile,
+		AuditLogTargetSyslog,
+		AuditLogTargetHTTP,
+	)
+
 	methodNameRE = regexp.MustCompile(`[[:alpha:]][[:alnum:]]*\.[[:alpha:]][[:alnum:]]*`)
 )
 
@@ -435,6 +623,13 @@ func ControllerOnlyAttribute(attr string) bool {
 	return false
 }
 
+// SecretAttribute returns true if the specified attribute holds a
+// credential. Secret attributes are stored encrypted, and are only
+// given to controller machine agents.
+func SecretAttribute(attr string) bool {
+	return ConfigSchema[attr].Secret
+}
+
 // Config is a string-keyed map of controller configuration attributes.
 type Config map[string]interface{}
 
@@ -626,6 +821,151 @@ func (c Config) AuditLogExcludeMethods() set.Strings {
 	return set.NewStrings(DefaultAuditLogExcludeMethods...)
 }
 
+// AuditLogTargets returns the names of the targets audit records are
+// sent to.
+func (c Config) AuditLogTargets() []string {
+	if value, ok := c[AuditLogTargets]; ok {
+		var targets []string
+		for _, item := range value.([]interface{}) {
+			targets = append(targets, item.(string))
+		}
+		return targets
+	}
+	return append([]string(nil), DefaultAuditLogTargets...)
+}
+
+// AuditLogBufferSizeMB returns the maximum size in MB of the unsent
+// records kept for each remote audit log target.
+func (c Config) AuditLogBufferSizeMB() int {
+	return c.sizeMBOrDefault(AuditLogBufferSize, DefaultAuditLogBufferSizeMB)
+}
+
+// AuditLogBufferFullFail returns whether API requests should fail when
+// a remote audit log target's buffer is full. The default is false,
+// which drops the records for that target instead.
+func (c Config) AuditLogBufferFullFail() bool {
+	if v, ok := c[AuditLogBufferFullFail]; ok {
+		return v.(bool)
+	}
+	return false
+}
+
+// AuditLogSyslog returns the settings for sending audit records to a
+// syslog server. The config is enabled if "syslog" is one of the audit
+// log targets.
+func (c Config) AuditLogSyslog() syslog.RawConfig {
+	return syslog.RawConfig{
+		Enabled:    c.hasAuditLogTarget(AuditLogTargetSyslog),
+		Host:       c.asString(AuditLogSyslogHost),
+		CACert:     c.asString(AuditLogSyslogCACert),
+		ClientCert: c.asString(AuditLogSyslogClientCert),
+		ClientKey:  c.asString(AuditLogSyslogClientKey),
+	}
+}
+
+// AuditLogHTTP returns the settings for posting audit records to an
+// HTTP endpoint. The config is enabled if "http" is one of the audit
+// log targets.
+func (c Config) AuditLogHTTP() httpfwd.RawConfig {
+	return httpfwd.RawConfig{
+		Enabled:  c.hasAuditLogTarget(AuditLogTargetHTTP),
+		Format:   httpfwd.FormatJSON,
+		URL:      c.asString(AuditLogHTTPURL),
+		CACert:   c.asString(AuditLogHTTPCACert),
+		Username: c.asString(AuditLogHTTPUsername),
+		Password: c.asString(AuditLogHTTPPassword),
+	}
+}
+
+func (c Config) hasAuditLogTarget(name string) bool {
+	for _, target := range c.AuditLogTargets() {
+		if target == name {
+			return true
+		}
+	}
+	return false
+}
+
+// BackupSchedule returns the cron spec for the controller's scheduled
+// backups, or "" if they aren't enabled.
+func (c Config) BackupSchedule() string {
+	return c.asString(BackupSchedule)
+}
+
+// BackupRetentionCount returns the number of scheduled backups kept in
+// backup storage.
+func (c Config) BackupRetentionCount() int {
+	if value, ok := c[BackupRetentionCount].(int); ok && value > 0 {
+		return value
+	}
+	return DefaultBackupRetentionCount
+}
+
+// BackupRetentionAge returns how long scheduled backups are kept in
+// backup storage. Zero means they are kept regardless of age.
+func (c Config) BackupRetentionAge() time.Duration {
+	return c.durationOrDefault(BackupRetentionAge, 0)
+}
+
+// BackupCopyDir returns the directory each scheduled backup is copied
+// to, or "" if they aren't copied.
+func (c Config) BackupCopyDir() string {
+	return c.asString(BackupCopyDir)
+}
+
+// ActionResultsArchiveDir returns the directory action results are
+// archived to, or "" if it isn't set.
+func (c Config) ActionResultsArchiveDir() string {
+	return c.asString(ActionResultsArchiveDir)
+}
+
+// BackupS3Config holds the settings for uploading scheduled backups
+// to an S3-compatible service.
+type BackupS3Config struct {
+	Endpoint  string
+	Bucket    string
+	Region    string
+	AccessKey string
+	SecretKey string
+}
+
+// Enabled returns whether scheduled backups are uploaded.
+func (cfg BackupS3Config) Enabled() bool {
+	return cfg.Bucket != ""
+}
+
+// BackupS3 returns the settings for uploading scheduled backups.
+func (c Config) BackupS3() BackupS3Config {
+	return BackupS3Config{
+		Endpoint:  c.asString(BackupS3Endpoint),
+		Bucket:    c.asString(BackupS3Bucket),
+		Region:    c.asString(BackupS3Region),
+		AccessKey: c.asString(BackupS3AccessKey),
+		SecretKey: c.asString(BackupS3SecretKey),
+	}
+}
+
+// SecretBackendVault returns the settings for storing secret values
+// in vault.
+func (c Config) SecretBackendVault() vault.Config {
+	return vault.Config{
+		Address: c.asString(SecretBackendVaultAddress),
+		Token:   c.asString(SecretBackendVaultToken),
+		Mount:   c.asString(SecretBackendVaultMount),
+		CACert:  c.asString(SecretBackendVaultCACert),
+	}
+}
+
+// SecretBackendFileDir returns the directory, relative to the data dir
+// of each controller machine, that the file secret backend stores
+// values in.
+func (c Config) SecretBackendFileDir() string {
+	if v := c.asString(SecretBackendFileDir); v != "" {
+		return v
+	}
+	return DefaultSecretBackendFileDir
+}
+
 // Features returns the controller config set features flags.
 func (c Config) Features() set.Strings {
 	features := set.NewStrings()
@@ -1010,6 +1350,93 @@ func Validate(c Config) error {
 		}
 	}
 
+	if v, ok := c[AuditLogTargets].([]interface{}); ok {
+		if len(v) == 0 {
+			return errors.NotValidf("empty %s", AuditLogTargets)
+		}
+		seen := set.NewStrings()
+		for _, name := range v {
+			name := name.(string)
+			if !auditLogTargetValues.Contains(name) {
+				return errors.NotValidf("%s value %q, expected one of %v", AuditLogTargets, name, auditLogTargetValues.SortedValues())
+			}
+			if seen.Contains(name) {
+				return errors.NotValidf("duplicate %s value %q", AuditLogTargets, name)
+			}
+			seen.Add(name)
+		}
+	}
+
+	if v, ok := c[AuditLogBufferSize].(string); ok {
+		mb, err := utils.ParseSize(v)
+		if err != nil {
+			return errors.Annotatef(err, "invalid %s in configuration", AuditLogBufferSize)
+		}
+		if mb < 1 {
+			return errors.NotValidf("%s less than 1 MB", AuditLogBufferSize)
+		}
+	}
+
+	if cfg := c.AuditLogSyslog(); cfg.Enabled {
+		if err := cfg.Validate(); err != nil {
+			return errors.Annotate(err, "invalid audit log syslog config")
+		}
+	}
+
+	if cfg := c.AuditLogHTTP(); cfg.Enabled {
+		if err := cfg.Validate(); err != nil {
+			return errors.Annotate(err, "invalid audit log http config")
+		}
+	}
+
+	if v, ok := c[BackupSchedule].(string); ok && v != "" {
+		if _, err := cron.Parse(v); err != nil {
+			return errors.NotValidf("%s %q: %v", BackupSchedule, v, err)
+		}
+	}
+
+	if v, ok := c[BackupRetentionCount].(int); ok && v < 1 {
+		return errors.NotValidf("%s less than 1", BackupRetentionCount)
+	}
+
+	if v, ok := c[BackupRetentionAge].(time.Duration); ok && v < 0 {
+		return errors.NotValidf("negative %s", BackupRetentionAge)
+	}
+
+	if s3 := c.BackupS3(); s3 != (BackupS3Config{}) {
+		if !s3.Enabled() {
+			return errors.NotValidf("backup S3 config without %s", BackupS3Bucket)
+		}
+		if (s3.AccessKey == "") != (s3.SecretKey == "") {
+			return errors.NotValidf("%s without %s", BackupS3AccessKey, BackupS3SecretKey)
+		}
+		if s3.Endpoint != "" {
+			if u, err := url.Parse(s3.Endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+				return errors.NotValidf("%s %q", BackupS3Endpoint, s3.Endpoint)
+			}
+		}
+	}
+
+	if v := c.SecretBackendVault(); v != (vault.Config{}) {
+		if err := v.Validate(); err != nil {
+			return errors.Annotate(err, "invalid secret backend vault config")
+		}
+	}
+
+	if v := c.asString(ActionResultsArchiveDir); v != "" && !filepath.IsAbs(v) {
+		return errors.NotValidf("%s %q: expected an absolute path", ActionResultsArchiveDir, v)
+	}
+
+	if v := c.asString(SecretBackendFileDir); v != "" {
+		// The values must stay in the data dir, and apart from the
+		// key they're encrypted with.
+		keysDir := secrets.KeysDir("")
+		if filepath.IsAbs(v) || filepath.Clean(v) != v || v == "." || strings.HasPrefix(v, "..") ||
+			v == keysDir || strings.HasPrefix(v, keysDir+string(filepath.Separator)) {
+			return errors.NotValidf("%s %q: expected a directory within the data dir", SecretBackendFileDir, v)
+		}
+	}
+
 	if v, ok := c[ControllerAPIPort].(int); ok {
 		// TODO: change the validation so 0 is invalid and --reset is used.
 		// However that doesn't exist yet.
@@ -1109,87 +1536,139 @@ func (c Config) AsSpaceConstraints(spaces *[]string) *[]string {
 }
 
 var configChecker = schema.FieldMap(schema.Fields{
-	AgentRateLimitMax:        schema.ForceInt(),
-	AgentRateLimitRate:       schema.TimeDuration(),
-	AuditingEnabled:          schema.Bool(),
-	AuditLogCaptureArgs:      schema.Bool(),
-	AuditLogMaxSize:          schema.String(),
-	AuditLogMaxBackups:       schema.ForceInt(),
-	AuditLogExcludeMethods:   schema.List(schema.String()),
-	APIPort:                  schema.ForceInt(),
-	APIPortOpenDelay:         schema.String(),
-	ControllerAPIPort:        schema.ForceInt(),
-	ControllerName:           schema.String(),
-	StatePort:                schema.ForceInt(),
-	IdentityURL:              schema.String(),
-	IdentityPublicKey:        schema.String(),
-	SetNUMAControlPolicyKey:  schema.Bool(),
-	AutocertURLKey:           schema.String(),
-	AutocertDNSNameKey:       schema.String(),
-	AllowModelAccessKey:      schema.Bool(),
-	MongoMemoryProfile:       schema.String(),
-	JujuDBSnapChannel:        schema.String(),
-	MaxDebugLogDuration:      schema.TimeDuration(),
-	MaxTxnLogSize:            schema.String(),
-	MaxPruneTxnBatchSize:     schema.ForceInt(),
-	MaxPruneTxnPasses:        schema.ForceInt(),
-	ModelLogfileMaxBackups:   schema.ForceInt(),
-	ModelLogfileMaxSize:      schema.String(),
-	ModelLogsSize:            schema.String(),
-	PruneTxnQueryCount:       schema.ForceInt(),
-	PruneTxnSleepTime:        schema.String(),
-	PublicDNSAddress:         schema.String(),
-	JujuHASpace:              schema.String(),
-	JujuManagementSpace:      schema.String(),
-	CAASOperatorImagePath:    schema.String(),
-	CAASImageRepo:            schema.String(),
-	Features:                 schema.List(schema.String()),
-	CharmStoreURL:            schema.String(),
-	MeteringURL:              schema.String(),
-	MaxCharmStateSize:        schema.ForceInt(),
-	MaxAgentStateSize:        schema.ForceInt(),
-	NonSyncedWritesToRaftLog: schema.Bool(),
+	AgentRateLimitMax:         schema.ForceInt(),
+	AgentRateLimitRate:        schema.TimeDuration(),
+	AuditingEnabled:           schema.Bool(),
+	AuditLogCaptureArgs:       schema.Bool(),
+	AuditLogMaxSize:           schema.String(),
+	AuditLogMaxBackups:        schema.ForceInt(),
+	AuditLogExcludeMethods:    schema.List(schema.String()),
+	AuditLogTargets:           schema.List(schema.String()),
+	AuditLogBufferSize:        schema.String(),
+	AuditLogBufferFullFail:    schema.Bool(),
+	AuditLogSyslogHost:        schema.String(),
+	AuditLogSyslogCACert:      schema.String(),
+	AuditLogSyslogClientCert:  schema.String(),
+	AuditLogSyslogClientKey:   schema.String(),
+	AuditLogHTTPURL:           schema.String(),
+	AuditLogHTTPCACert:        schema.String(),
+	AuditLogHTTPUsername:      schema.String(),
+	AuditLogHTTPPassword:      schema.String(),
+	BackupSchedule:            schema.String(),
+	BackupRetentionCount:      schema.ForceInt(),
+	BackupRetentionAge:        schema.TimeDuration(),
+	BackupCopyDir:             schema.String(),
+	ActionResultsArchiveDir:   schema.String(),
+	BackupS3Endpoint:          schema.String(),
+	BackupS3Bucket:            schema.String(),
+	BackupS3Region:            schema.String(),
+	BackupS3AccessKey:         schema.String(),
+	BackupS3SecretKey:         schema.String(),
+	SecretBackendVaultAddress: schema.String(),
+	SecretBackendVaultToken:   schema.String(),
+	SecretBackendVaultMount:   schema.String(),
+	SecretBackendVaultCACert:  schema.String(),
+	SecretBackendFileDir:      schema.String(),
+	APIPort:                   schema.ForceInt(),
+	APIPortOpenDelay:          schema.String(),
+	ControllerAPIPort:         schema.ForceInt(),
+	ControllerName:            schema.String(),
+	StatePort:                 schema.ForceInt(),
+	IdentityURL:               schema.String(),
+	IdentityPublicKey:         schema.String(),
+	SetNUMAControlPolicyKey:   schema.Bool(),
+	AutocertURLKey:            schema.String(),
+	AutocertDNSNameKey:        schema.String(),
+	AllowModelAccessKey:       schema.Bool(),
+	MongoMemoryProfile:        schema.String(),
+	JujuDBSnapChannel:         schema.String(),
+	MaxDebugLogDuration:       schema.TimeDuration(),
+	MaxTxnLogSize:             schema.String(),
+	MaxPruneTxnBatchSize:      schema.ForceInt(),
+	MaxPruneTxnPasses:         schema.ForceInt(),
+	ModelLogfileMaxBackups:    schema.ForceInt(),
+	ModelLogfileMaxSize:       schema.String(),
+	ModelLogsSize:             schema.String(),
+	PruneTxnQueryCount:        schema.ForceInt(),
+	PruneTxnSleepTime:         schema.String(),
+	PublicDNSAddress:          schema.String(),
+	JujuHASpace:               schema.String(),
+	JujuManagementSpace:       schema.String(),
+	CAASOperatorImagePath:     schema.String(),
+	CAASImageRepo:             schema.String(),
+	Features:                  schema.List(schema.String()),
+	CharmStoreURL:             schema.String(),
+	MeteringURL:               schema.String(),
+	MaxCharmStateSize:         schema.ForceInt(),
+	MaxAgentStateSize:         schema.ForceInt(),
+	NonSyncedWritesToRaftLog:  schema.Bool(),
 }, schema.Defaults{
-	AgentRateLimitMax:        schema.Omit,
-	AgentRateLimitRate:       schema.Omit,
-	APIPort:                  DefaultAPIPort,
-	APIPortOpenDelay:         DefaultAPIPortOpenDelay,
-	ControllerAPIPort:        schema.Omit,
-	ControllerName:           schema.Omit,
-	AuditingEnabled:          DefaultAuditingEnabled,
-	AuditLogCaptureArgs:      DefaultAuditLogCaptureArgs,
-	AuditLogMaxSize:          fmt.Sprintf("%vM", DefaultAuditLogMaxSizeMB),
-	AuditLogMaxBackups:       DefaultAuditLogMaxBackups,
-	AuditLogExcludeMethods:   DefaultAuditLogExcludeMethods,
-	StatePort:                DefaultStatePort,
-	IdentityURL:              schema.Omit,
-	IdentityPublicKey:        schema.Omit,
-	SetNUMAControlPolicyKey:  DefaultNUMAControlPolicy,
-	AutocertURLKey:           schema.Omit,
-	AutocertDNSNameKey:       schema.Omit,
-	AllowModelAccessKey:      schema.Omit,
-	MongoMemoryProfile:       DefaultMongoMemoryProfile,
-	JujuDBSnapChannel:        DefaultJujuDBSnapChannel,
-	MaxDebugLogDuration:      DefaultMaxDebugLogDuration,
-	MaxTxnLogSize:            fmt.Sprintf("%vM", DefaultMaxTxnLogCollectionMB),
-	MaxPruneTxnBatchSize:     DefaultMaxPruneTxnBatchSize,
-	MaxPruneTxnPasses:        DefaultMaxPruneTxnPasses,
-	ModelLogfileMaxBackups:   DefaultModelLogfileMaxBackups,
-	ModelLogfileMaxSize:      fmt.Sprintf("%vM", DefaultModelLogfileMaxSize),
-	ModelLogsSize:            fmt.Sprintf("%vM", DefaultModelLogsSizeMB),
-	PruneTxnQueryCount:       DefaultPruneTxnQueryCount,
-	PruneTxnSleepTime:        DefaultPruneTxnSleepTime,
-	PublicDNSAddress:         schema.Omit,
-	JujuHASpace:              schema.Omit,
-	JujuManagementSpace:      schema.Omit,
-	CAASOperatorImagePath:    schema.Omit,
-	CAASImageRepo:            schema.Omit,
-	Features:                 schema.Omit,
-	CharmStoreURL:            csclient.ServerURL,
-	MeteringURL:              romulus.DefaultAPIRoot,
-	MaxCharmStateSize:        DefaultMaxCharmStateSize,
-	MaxAgentStateSize:        DefaultMaxAgentStateSize,
-	NonSyncedWritesToRaftLog: DefaultNonSyncedWritesToRaftLog,
+	AgentRateLimitMax:         schema.Omit,
+	AgentRateLimitRate:        schema.Omit,
+	APIPort:                   DefaultAPIPort,
+	APIPortOpenDelay:          DefaultAPIPortOpenDelay,
+	ControllerAPIPort:         schema.Omit,
+	ControllerName:            schema.Omit,
+	AuditingEnabled:           DefaultAuditingEnabled,
+	AuditLogCaptureArgs:       DefaultAuditLogCaptureArgs,
+	AuditLogMaxSize:           fmt.Sprintf("%vM", DefaultAuditLogMaxSizeMB),
+	AuditLogMaxBackups:        DefaultAuditLogMaxBackups,
+	AuditLogExcludeMethods:    DefaultAuditLogExcludeMethods,
+	AuditLogTargets:           schema.Omit,
+	AuditLogBufferSize:        fmt.Sprintf("%vM", DefaultAuditLogBufferSizeMB),
+	AuditLogBufferFullFail:    schema.Omit,
+	AuditLogSyslogHost:        schema.Omit,
+	AuditLogSyslogCACert:      schema.Omit,
+	AuditLogSyslogClientCert:  schema.Omit,
+	AuditLogSyslogClientKey:   schema.Omit,
+	AuditLogHTTPURL:           schema.Omit,
+	AuditLogHTTPCACert:        schema.Omit,
+	AuditLogHTTPUsername:      schema.Omit,
+	AuditLogHTTPPassword:      schema.Omit,
+	BackupSchedule:            schema.Omit,
+	BackupRetentionCount:      schema.Omit,
+	BackupRetentionAge:        schema.Omit,
+	BackupCopyDir:             schema.Omit,
+	ActionResultsArchiveDir:   schema.Omit,
+	BackupS3Endpoint:          schema.Omit,
+	BackupS3Bucket:            schema.Omit,
+	BackupS3Region:            schema.Omit,
+	BackupS3AccessKey:         schema.Omit,
+	BackupS3SecretKey:         schema.Omit,
+	SecretBackendVaultAddress: schema.Omit,
+	SecretBackendVaultToken:   schema.Omit,
+	SecretBackendVaultMount:   schema.Omit,
+	SecretBackendVaultCACert:  schema.Omit,
+	SecretBackendFileDir:      schema.Omit,
+	StatePort:                 DefaultStatePort,
+	IdentityURL:               schema.Omit,
+	IdentityPublicKey:         schema.Omit,
+	SetNUMAControlPolicyKey:   DefaultNUMAControlPolicy,
+	AutocertURLKey:            schema.Omit,
+	AutocertDNSNameKey:        schema.Omit,
+	AllowModelAccessKey:       schema.Omit,
+	MongoMemoryProfile:        DefaultMongoMemoryProfile,
+	JujuDBSnapChannel:         DefaultJujuDBSnapChannel,
+	MaxDebugLogDuration:       DefaultMaxDebugLogDuration,
+	MaxTxnLogSize:             fmt.Sprintf("%vM", DefaultMaxTxnLogCollectionMB),
+	MaxPruneTxnBatchSize:      DefaultMaxPruneTxnBatchSize,
+	MaxPruneTxnPasses:         DefaultMaxPruneTxnPasses,
+	ModelLogfileMaxBackups:    DefaultModelLogfileMaxBackups,
+	ModelLogfileMaxSize:       fmt.Sprintf("%vM", DefaultModelLogfileMaxSize),
+	ModelLogsSize:             fmt.Sprintf("%vM", DefaultModelLogsSizeMB),
+	PruneTxnQueryCount:        DefaultPruneTxnQueryCount,
+	PruneTxnSleepTime:         DefaultPruneTxnSleepTime,
+	PublicDNSAddress:          schema.Omit,
+	JujuHASpace:               schema.Omit,
+	JujuManagementSpace:       schema.Omit,
+	CAASOperatorImagePath:     schema.Omit,
+	CAASImageRepo:             schema.Omit,
+	Features:                  schema.Omit,
+	CharmStoreURL:             csclient.ServerURL,
+	MeteringURL:               romulus.DefaultAPIRoot,
+	MaxCharmStateSize:         DefaultMaxCharmStateSize,
+	MaxAgentStateSize:         DefaultMaxAgentStateSize,
+	NonSyncedWritesToRaftLog:  DefaultNonSyncedWritesToRaftLog,
 })
 
 // ConfigSchema holds information on all the fields defined by
@@ -1224,6 +1703,115 @@ var ConfigSchema = environschema.Fields{
 		Type:        environschema.FieldType("list of strings"),
 		Description: "The list of Facade.Method names that aren't interesting for audit logging purposes.",
 	},
+	AuditLogTargets: {
+		Type:        environschema.FieldType("list of strings"),
+		Description: `Where audit records are sent: any of "file", "syslog" and "http"`,
+	},
+	AuditLogBufferSize: {
+		Type:        environschema.Tstring,
+		Description: "The maximum size of the unsent records kept for each remote audit log target",
+	},
+	AuditLogBufferFullFail: {
+		Type:        environschema.Tbool,
+		Description: "Whether API requests fail when a remote audit log target's buffer is full, rather than their audit records being dropped",
+	},
+	AuditLogSyslogHost: {
+		Type:        environschema.Tstring,
+		Description: "The host-port of the syslog server audit records are sent to",
+	},
+	AuditLogSyslogCACert: {
+		Type:        environschema.Tstring,
+		Description: "The CA certificate used to validate the audit log syslog server",
+	},
+	AuditLogSyslogClientCert: {
+		Type:        environschema.Tstring,
+		Description: "The client certificate used to connect to the audit log syslog server",
+	},
+	AuditLogSyslogClientKey: {
+		Type:        environschema.Tstring,
+		Description: "The client key used to connect to the audit log syslog server",
+		Secret:      true,
+	},
+	AuditLogHTTPURL: {
+		Type:        environschema.Tstring,
+		Description: "The URL of the HTTP endpoint audit records are posted to",
+	},
+	AuditLogHTTPCACert: {
+		Type:        environschema.Tstring,
+		Description: "The CA certificate used to validate the audit log HTTP endpoint",
+	},
+	AuditLogHTTPUsername: {
+		Type:        environschema.Tstring,
+		Description: "The username used to authenticate with the audit log HTTP endpoint",
+	},
+	AuditLogHTTPPassword: {
+		Type:        environschema.Tstring,
+		Description: "The password used to authenticate with the audit log HTTP endpoint",
+		Secret:      true,
+	},
+	BackupSchedule: {
+		Type:        environschema.Tstring,
+		Description: `When the controller backs itself up, as a cron spec or descriptor such as "@daily"; empty disables scheduled backups`,
+	},
+	BackupRetentionCount: {
+		Type:        environschema.Tint,
+		Description: "The number of scheduled backups kept in the controller's backup storage",
+	},
+	BackupRetentionAge: {
+		Type:        environschema.Tstring,
+		Description: "How long scheduled backups are kept in the controller's backup storage",
+	},
+	BackupCopyDir: {
+		Type:        environschema.Tstring,
+		Description: "A directory on the controller machines that scheduled backups are copied to",
+	},
+	ActionResultsArchiveDir: {
+		Type:        environschema.Tstring,
+		Description: `A directory shared by the controller machines that action results are archived to, for models with action-results-archive set to "directory"`,
+	},
+	BackupS3Endpoint: {
+		Type:        environschema.Tstring,
+		Description: "The URL of the S3-compatible service scheduled backups are uploaded to; empty means AWS S3",
+	},
+	BackupS3Bucket: {
+		Type:        environschema.Tstring,
+		Description: "The S3 bucket scheduled backups are uploaded to",
+	},
+	BackupS3Region: {
+		Type:        environschema.Tstring,
+		Description: "The region of the backup S3 bucket",
+	},
+	BackupS3AccessKey: {
+		Type:        environschema.Tstring,
+		Description: "The access key used to upload scheduled backups",
+		Secret:      true,
+	},
+	BackupS3SecretKey: {
+		Type:        environschema.Tstring,
+		Description: "The secret key used to upload scheduled backups",
+		Secret:      true,
+	},
+	SecretBackendVaultAddress: {
+		Type:        environschema.Tstring,
+		Description: "The URL of the vault server used by models whose secret-backend is vault",
+	},
+	SecretBackendVaultToken: {
+		Type:        environschema.Tstring,
+		Description: "The token used to authenticate with the vault server",
+		Secret:      true,
+	},
+	SecretBackendVaultMount: {
+		Type:        environschema.Tstring,
+		Description: `The mount path of the vault KV version 2 secrets engine (default "secret")`,
+	},
+	SecretBackendVaultCACert: {
+		Type:        environschema.Tstring,
+		Description: "The certificate of the CA that signed the vault server's certificate, in PEM format",
+	},
+	SecretBackendFileDir: {
+		Type:        environschema.Tstring,
+		Description: `The directory, relative to the data dir of each controller machine, used by models whose secret-backend is file (default "secret-backend")`,
+	},
 	APIPort: {
 		Type:        environschema.Tint,
 		Description: "The port used for api connections",
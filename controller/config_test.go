@@ -15,6 +15,9 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/controller"
+	"github.com/juju/juju/logfwd/httpfwd"
+	"github.com/juju/juju/logfwd/syslog"
+	"github.com/juju/juju/secretbackend/vault"
 	"github.com/juju/juju/testing"
 )
 
@@ -155,6 +158,110 @@ var newConfigTests = []struct {
 		controller.AuditLogExcludeMethods: []interface{}{"Dap.Kings", "ReadOnlyMethods", "Sharon Jones"},
 	},
 	expectError: `invalid audit log exclude methods: should be a list of "Facade.Method" names \(or "ReadOnlyMethods"\), got "Sharon Jones" at position 3`,
+}, {
+	about: "empty audit log targets",
+	config: controller.Config{
+		controller.AuditLogTargets: []interface{}{},
+	},
+	expectError: `empty audit-log-targets not valid`,
+}, {
+	about: "unknown audit log target",
+	config: controller.Config{
+		controller.AuditLogTargets: []interface{}{"file", "kafka"},
+	},
+	expectError: `audit-log-targets value "kafka", expected one of \[file http syslog\] not valid`,
+}, {
+	about: "duplicate audit log target",
+	config: controller.Config{
+		controller.AuditLogTargets: []interface{}{"file", "file"},
+	},
+	expectError: `duplicate audit-log-targets value "file" not valid`,
+}, {
+	about: "invalid audit log buffer size",
+	config: controller.Config{
+		controller.AuditLogBufferSize: "0M",
+	},
+	expectError: `audit-log-buffer-size less than 1 MB not valid`,
+}, {
+	about: "audit log syslog target without host",
+	config: controller.Config{
+		controller.AuditLogTargets: []interface{}{"syslog"},
+	},
+	expectError: `invalid audit log syslog config: Host "" not valid`,
+}, {
+	about: "audit log http target without URL",
+	config: controller.Config{
+		controller.AuditLogTargets: []interface{}{"file", "http"},
+	},
+	expectError: `invalid audit log http config: empty URL not valid`,
+}, {
+	about: "invalid backup schedule",
+	config: controller.Config{
+		controller.BackupSchedule: "every day",
+	},
+	expectError: `backup-schedule "every day": Expected 5 or 6 fields, found 2: every day not valid`,
+}, {
+	about: "invalid backup retention count",
+	config: controller.Config{
+		controller.BackupRetentionCount: 0,
+	},
+	expectError: `backup-retention-count less than 1 not valid`,
+}, {
+	about: "negative backup retention age",
+	config: controller.Config{
+		controller.BackupRetentionAge: -time.Hour,
+	},
+	expectError: `negative backup-retention-age not valid`,
+}, {
+	about: "backup S3 config without bucket",
+	config: controller.Config{
+		controller.BackupS3Region: "us-east-1",
+	},
+	expectError: `backup S3 config without backup-s3-bucket not valid`,
+}, {
+	about: "backup S3 access key without secret key",
+	config: controller.Config{
+		controller.BackupS3Bucket:    "backups",
+		controller.BackupS3AccessKey: "access",
+	},
+	expectError: `backup-s3-access-key without backup-s3-secret-key not valid`,
+}, {
+	about: "invalid backup S3 endpoint",
+	config: controller.Config{
+		controller.BackupS3Bucket:   "backups",
+		controller.BackupS3Endpoint: "minio.local",
+	},
+	expectError: `backup-s3-endpoint "minio.local" not valid`,
+}, {
+	about: "secret backend vault config without token",
+	config: controller.Config{
+		controller.SecretBackendVaultAddress: "https://vault.example.com:8200",
+	},
+	expectError: `invalid secret backend vault config: empty token not valid`,
+}, {
+	about: "relative action results archive dir",
+	config: controller.Config{
+		controller.ActionResultsArchiveDir: "action-archive",
+	},
+	expectError: `action-results-archive-dir "action-archive": expected an absolute path not valid`,
+}, {
+	about: "absolute secret backend file dir",
+	config: controller.Config{
+		controller.SecretBackendFileDir: "/srv/secrets",
+	},
+	expectError: `secret-backend-file-dir "/srv/secrets": expected a directory within the data dir not valid`,
+}, {
+	about: "secret backend file dir outside data dir",
+	config: controller.Config{
+		controller.SecretBackendFileDir: "../secrets",
+	},
+	expectError: `secret-backend-file-dir "../secrets": expected a directory within the data dir not valid`,
+}, {
+	about: "secret backend file dir in secrets keys dir",
+	config: controller.Config{
+		controller.SecretBackendFileDir: "secrets/values",
+	},
+	expectError: `secret-backend-file-dir "secrets/values": expected a directory within the data dir not valid`,
 }, {
 	about: "invalid model log max size",
 	config: controller.Config{
@@ -515,6 +622,140 @@ func (s *ConfigSuite) TestAuditLogValues(c *gc.C) {
 	))
 }
 
+func (s *ConfigSuite) TestAuditLogTargetDefaults(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AuditLogTargets(), jc.DeepEquals, []string{"file"})
+	c.Assert(cfg.AuditLogBufferSizeMB(), gc.Equals, 100)
+	c.Assert(cfg.AuditLogBufferFullFail(), jc.IsFalse)
+	c.Assert(cfg.AuditLogSyslog().Enabled, jc.IsFalse)
+	c.Assert(cfg.AuditLogHTTP().Enabled, jc.IsFalse)
+}
+
+func (s *ConfigSuite) TestAuditLogTargetValues(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"audit-log-targets":            []string{"file", "syslog", "http"},
+			"audit-log-buffer-size":        "20M",
+			"audit-log-buffer-full-fail":   true,
+			"audit-log-syslog-host":        "syslog.example.com:6514",
+			"audit-log-syslog-ca-cert":     testing.CACert,
+			"audit-log-syslog-client-cert": testing.ServerCert,
+			"audit-log-syslog-client-key":  testing.ServerKey,
+			"audit-log-http-url":           "https://audit.example.com/records",
+			"audit-log-http-username":      "juju",
+			"audit-log-http-password":      "secret",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AuditLogTargets(), jc.DeepEquals, []string{"file", "syslog", "http"})
+	c.Assert(cfg.AuditLogBufferSizeMB(), gc.Equals, 20)
+	c.Assert(cfg.AuditLogBufferFullFail(), jc.IsTrue)
+	c.Assert(cfg.AuditLogSyslog(), jc.DeepEquals, syslog.RawConfig{
+		Enabled:    true,
+		Host:       "syslog.example.com:6514",
+		CACert:     testing.CACert,
+		ClientCert: testing.ServerCert,
+		ClientKey:  testing.ServerKey,
+	})
+	c.Assert(cfg.AuditLogHTTP(), jc.DeepEquals, httpfwd.RawConfig{
+		Enabled:  true,
+		Format:   httpfwd.FormatJSON,
+		URL:      "https://audit.example.com/records",
+		Username: "juju",
+		Password: "secret",
+	})
+}
+
+func (s *ConfigSuite) TestBackupScheduleDefaults(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.BackupSchedule(), gc.Equals, "")
+	c.Assert(cfg.BackupRetentionCount(), gc.Equals, 7)
+	c.Assert(cfg.BackupRetentionAge(), gc.Equals, time.Duration(0))
+	c.Assert(cfg.BackupCopyDir(), gc.Equals, "")
+	c.Assert(cfg.ActionResultsArchiveDir(), gc.Equals, "")
+	c.Assert(cfg.BackupS3().Enabled(), jc.IsFalse)
+}
+
+func (s *ConfigSuite) TestBackupScheduleValues(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"backup-schedule":            "0 3 * * *",
+			"backup-retention-count":     3,
+			"backup-retention-age":       "720h",
+			"backup-copy-dir":            "/srv/backups",
+			"action-results-archive-dir": "/srv/action-archive",
+			"backup-s3-endpoint":         "https://minio.example.com:9000",
+			"backup-s3-bucket":           "backups",
+			"backup-s3-region":           "eu-west-1",
+			"backup-s3-access-key":       "access",
+			"backup-s3-secret-key":       "secret",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.BackupSchedule(), gc.Equals, "0 3 * * *")
+	c.Assert(cfg.BackupRetentionCount(), gc.Equals, 3)
+	c.Assert(cfg.BackupRetentionAge(), gc.Equals, 720*time.Hour)
+	c.Assert(cfg.BackupCopyDir(), gc.Equals, "/srv/backups")
+	c.Assert(cfg.ActionResultsArchiveDir(), gc.Equals, "/srv/action-archive")
+	c.Assert(cfg.BackupS3(), jc.DeepEquals, controller.BackupS3Config{
+		Endpoint:  "https://minio.example.com:9000",
+		Bucket:    "backups",
+		Region:    "eu-west-1",
+		AccessKey: "access",
+		SecretKey: "secret",
+	})
+	c.Assert(cfg.BackupS3().Enabled(), jc.IsTrue)
+}
+
+func (s *ConfigSuite) TestSecretBackendValues(c *gc.C) {
+	cfg, err := controller.NewConfig(
+		testing.ControllerTag.Id(),
+		testing.CACert,
+		map[string]interface{}{
+			"secret-backend-vault-address": "https://vault.example.com:8200",
+			"secret-backend-vault-token":   "s3cret",
+			"secret-backend-vault-mount":   "juju",
+			"secret-backend-vault-ca-cert": testing.CACert,
+			"secret-backend-file-dir":      "shared/secret-backend",
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.SecretBackendVault(), jc.DeepEquals, vault.Config{
+		Address: "https://vault.example.com:8200",
+		Token:   "s3cret",
+		Mount:   "juju",
+		CACert:  testing.CACert,
+	})
+	c.Assert(cfg.SecretBackendFileDir(), gc.Equals, "shared/secret-backend")
+}
+
+func (s *ConfigSuite) TestSecretBackendDefaults(c *gc.C) {
+	cfg, err := controller.NewConfig(testing.ControllerTag.Id(), testing.CACert, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.SecretBackendVault(), jc.DeepEquals, vault.Config{})
+	c.Assert(cfg.SecretBackendFileDir(), gc.Equals, "secret-backend")
+}
+
+func (s *ConfigSuite) TestSecretAttribute(c *gc.C) {
+	c.Check(controller.SecretAttribute("secret-backend-vault-token"), jc.IsTrue)
+	c.Check(controller.SecretAttribute("secret-backend-vault-address"), jc.IsFalse)
+	c.Check(controller.SecretAttribute("backup-s3-access-key"), jc.IsTrue)
+	c.Check(controller.SecretAttribute("backup-s3-secret-key"), jc.IsTrue)
+	c.Check(controller.SecretAttribute("backup-s3-bucket"), jc.IsFalse)
+	c.Check(controller.SecretAttribute("audit-log-syslog-client-key"), jc.IsTrue)
+	c.Check(controller.SecretAttribute("audit-log-syslog-client-cert"), jc.IsFalse)
+	c.Check(controller.SecretAttribute("audit-log-http-password"), jc.IsTrue)
+	c.Check(controller.SecretAttribute("audit-log-http-username"), jc.IsFalse)
+	c.Check(controller.SecretAttribute("api-port"), jc.IsFalse)
+	c.Check(controller.SecretAttribute("no-such-attribute"), jc.IsFalse)
+}
+
 func (s *ConfigSuite) TestAuditLogExcludeMethodsType(c *gc.C) {
 	_, err := controller.NewConfig(
 		testing.ControllerTag.Id(),
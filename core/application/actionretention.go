@@ -0,0 +1,33 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// ActionResultsMaxAgeOptionName is the option name used to override the
+// model's max-action-results-age for the tasks run on an application's
+// units.
+const ActionResultsMaxAgeOptionName = "action-results-max-age"
+
+// ActionResultsMaxAge returns the maximum age of the results of tasks
+// run on the application, and whether it is set in the application
+// config.
+func ActionResultsMaxAge(cfg ConfigAttributes) (time.Duration, bool, error) {
+	value := cfg.GetString(ActionResultsMaxAgeOptionName, "")
+	if value == "" {
+		return 0, false, nil
+	}
+	maxAge, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, false, errors.NotValidf("%s %q", ActionResultsMaxAgeOptionName, value)
+	}
+	if maxAge < 0 {
+		return 0, false, errors.NotValidf("negative %s %q", ActionResultsMaxAgeOptionName, value)
+	}
+	return maxAge, true, nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/application"
+)
+
+type actionRetentionSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&actionRetentionSuite{})
+
+func (s *actionRetentionSuite) TestActionResultsMaxAgeUnset(c *gc.C) {
+	_, ok, err := application.ActionResultsMaxAge(application.ConfigAttributes{"trust": true})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *actionRetentionSuite) TestActionResultsMaxAge(c *gc.C) {
+	maxAge, ok, err := application.ActionResultsMaxAge(application.ConfigAttributes{
+		"action-results-max-age": "72h",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(maxAge, gc.Equals, 72*time.Hour)
+}
+
+func (s *actionRetentionSuite) TestActionResultsMaxAgeInvalid(c *gc.C) {
+	for i, test := range []struct {
+		value string
+		err   string
+	}{{
+		value: "a while",
+		err:   `action-results-max-age "a while" not valid`,
+	}, {
+		value: "-1h",
+		err:   `negative action-results-max-age "-1h" not valid`,
+	}} {
+		c.Logf("test %d: %v", i, test.value)
+		_, _, err := application.ActionResultsMaxAge(application.ConfigAttributes{
+			"action-results-max-age": test.value,
+		})
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
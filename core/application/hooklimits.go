@@ -0,0 +1,98 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/v2"
+)
+
+const (
+	// HookTimeoutOptionName is the option name used to set the maximum
+	// wall time of a hook run on an application's units.
+	HookTimeoutOptionName = "hook-timeout"
+
+	// ActionTimeoutOptionName is the option name used to set the
+	// maximum wall time of an action run on an application's units.
+	ActionTimeoutOptionName = "action-timeout"
+
+	// HookCPUQuotaOptionName is the option name used to cap the CPU
+	// time of hook and action processes on an application's machine
+	// units, as a percentage of a single CPU.
+	HookCPUQuotaOptionName = "hook-cpu-quota"
+
+	// HookMemoryLimitOptionName is the option name used to cap the
+	// memory of hook and action processes on an application's machine
+	// units.
+	HookMemoryLimitOptionName = "hook-memory-limit"
+)
+
+// HookLimits holds the limits applied to the hooks and actions run on an
+// application's units. Zero values mean no limit.
+type HookLimits struct {
+	// HookTimeout is the maximum wall time of a hook.
+	HookTimeout time.Duration
+
+	// ActionTimeout is the maximum wall time of an action.
+	ActionTimeout time.Duration
+
+	// CPUQuota is the maximum CPU time of a hook or action process, as
+	// a percentage of a single CPU.
+	CPUQuota int
+
+	// MemoryLimit is the maximum memory, in MiB, of a hook or action
+	// process.
+	MemoryLimit uint64
+}
+
+// HasResourceLimits reports whether CPU or memory limits are set.
+func (l HookLimits) HasResourceLimits() bool {
+	return l.CPUQuota > 0 || l.MemoryLimit > 0
+}
+
+// ParseHookLimits returns the hook limits set in the application config.
+func ParseHookLimits(cfg ConfigAttributes) (HookLimits, error) {
+	var limits HookLimits
+	var err error
+	if limits.HookTimeout, err = parseTimeout(cfg, HookTimeoutOptionName); err != nil {
+		return HookLimits{}, errors.Trace(err)
+	}
+	if limits.ActionTimeout, err = parseTimeout(cfg, ActionTimeoutOptionName); err != nil {
+		return HookLimits{}, errors.Trace(err)
+	}
+	if value := cfg.GetString(HookCPUQuotaOptionName, ""); value != "" {
+		quota, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+		if err != nil || quota < 0 {
+			return HookLimits{}, errors.NotValidf("%s %q", HookCPUQuotaOptionName, value)
+		}
+		limits.CPUQuota = quota
+	}
+	if value := cfg.GetString(HookMemoryLimitOptionName, ""); value != "" {
+		limit, err := utils.ParseSize(value)
+		if err != nil {
+			return HookLimits{}, errors.NotValidf("%s %q", HookMemoryLimitOptionName, value)
+		}
+		limits.MemoryLimit = limit
+	}
+	return limits, nil
+}
+
+func parseTimeout(cfg ConfigAttributes, name string) (time.Duration, error) {
+	value := cfg.GetString(name, "")
+	if value == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.NotValidf("%s %q", name, value)
+	}
+	if timeout < 0 {
+		return 0, errors.NotValidf("negative %s %q", name, value)
+	}
+	return timeout, nil
+}
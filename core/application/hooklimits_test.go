@@ -0,0 +1,78 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/application"
+)
+
+type hookLimitsSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&hookLimitsSuite{})
+
+func (s *hookLimitsSuite) TestParseHookLimitsEmpty(c *gc.C) {
+	limits, err := application.ParseHookLimits(application.ConfigAttributes{"trust": true})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(limits, gc.Equals, application.HookLimits{})
+	c.Assert(limits.HasResourceLimits(), jc.IsFalse)
+}
+
+func (s *hookLimitsSuite) TestParseHookLimits(c *gc.C) {
+	limits, err := application.ParseHookLimits(application.ConfigAttributes{
+		"hook-timeout":      "30m",
+		"action-timeout":    "2h",
+		"hook-cpu-quota":    "50%",
+		"hook-memory-limit": "1G",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(limits, gc.Equals, application.HookLimits{
+		HookTimeout:   30 * time.Minute,
+		ActionTimeout: 2 * time.Hour,
+		CPUQuota:      50,
+		MemoryLimit:   1024,
+	})
+	c.Assert(limits.HasResourceLimits(), jc.IsTrue)
+}
+
+func (s *hookLimitsSuite) TestParseHookLimitsCPUQuotaWithoutPercent(c *gc.C) {
+	limits, err := application.ParseHookLimits(application.ConfigAttributes{
+		"hook-cpu-quota": "200",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(limits.CPUQuota, gc.Equals, 200)
+}
+
+func (s *hookLimitsSuite) TestParseHookLimitsInvalid(c *gc.C) {
+	for i, test := range []struct {
+		attrs application.ConfigAttributes
+		err   string
+	}{{
+		attrs: application.ConfigAttributes{"hook-timeout": "forever"},
+		err:   `hook-timeout "forever" not valid`,
+	}, {
+		attrs: application.ConfigAttributes{"action-timeout": "-1m"},
+		err:   `negative action-timeout "-1m" not valid`,
+	}, {
+		attrs: application.ConfigAttributes{"hook-cpu-quota": "lots"},
+		err:   `hook-cpu-quota "lots" not valid`,
+	}, {
+		attrs: application.ConfigAttributes{"hook-cpu-quota": "-5%"},
+		err:   `hook-cpu-quota "-5%" not valid`,
+	}, {
+		attrs: application.ConfigAttributes{"hook-memory-limit": "big"},
+		err:   `hook-memory-limit "big" not valid`,
+	}} {
+		c.Logf("test %d: %v", i, test.attrs)
+		_, err := application.ParseHookLimits(test.attrs)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
@@ -90,12 +90,23 @@ type Error struct {
 	Code    string `json:"code"`
 }
 
+// Gap marks where records were dropped by a remote audit log whose
+// buffer was full.
+type Gap struct {
+	Target  string `json:"target"`
+	Dropped int64  `json:"dropped"`
+	From    string `json:"from"` // ISO 8601 to second precision
+	To      string `json:"to"`   // ISO 8601 to second precision
+}
+
 // Record is the top-level entry type in an audit log, which serves as
-// a type discriminator. Only one of Conversation/Request/Errors should be set.
+// a type discriminator. Only one of Conversation/Request/Errors/Gap
+// should be set.
 type Record struct {
 	Conversation *Conversation   `json:"conversation,omitempty"`
 	Request      *Request        `json:"request,omitempty"`
 	Errors       *ResponseErrors `json:"errors,omitempty"`
+	Gap          *Gap            `json:"gap,omitempty"`
 }
 
 // AuditLog represents something that can store calls, requests and
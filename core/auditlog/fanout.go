@@ -0,0 +1,63 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+type fanOut []AuditLog
+
+// NewFanOut returns an AuditLog that writes every record to all of the
+// targets. A record is offered to each target even if an earlier one
+// fails, and any errors are combined into the one returned.
+func NewFanOut(targets ...AuditLog) AuditLog {
+	if len(targets) == 1 {
+		return targets[0]
+	}
+	return fanOut(targets)
+}
+
+// AddConversation implements AuditLog.
+func (f fanOut) AddConversation(c Conversation) error {
+	return f.each(func(target AuditLog) error {
+		return target.AddConversation(c)
+	})
+}
+
+// AddRequest implements AuditLog.
+func (f fanOut) AddRequest(r Request) error {
+	return f.each(func(target AuditLog) error {
+		return target.AddRequest(r)
+	})
+}
+
+// AddResponse implements AuditLog.
+func (f fanOut) AddResponse(r ResponseErrors) error {
+	return f.each(func(target AuditLog) error {
+		return target.AddResponse(r)
+	})
+}
+
+// Close implements AuditLog.
+func (f fanOut) Close() error {
+	return f.each(func(target AuditLog) error {
+		return target.Close()
+	})
+}
+
+func (f fanOut) each(call func(AuditLog) error) error {
+	var messages []string
+	for _, target := range f {
+		if err := call(target); err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+	if len(messages) > 0 {
+		return errors.New(strings.Join(messages, "; "))
+	}
+	return nil
+}
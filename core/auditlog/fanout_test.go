@@ -0,0 +1,76 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/auditlog"
+)
+
+type FanOutSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&FanOutSuite{})
+
+func (s *FanOutSuite) TestSingleTarget(c *gc.C) {
+	target := &stubLog{}
+	c.Assert(auditlog.NewFanOut(target), gc.Equals, target)
+}
+
+func (s *FanOutSuite) TestWritesToAllTargets(c *gc.C) {
+	first, second := &stubLog{}, &stubLog{}
+	log := auditlog.NewFanOut(first, second)
+
+	c.Assert(log.AddConversation(auditlog.Conversation{ConversationID: "abc"}), jc.ErrorIsNil)
+	c.Assert(log.AddRequest(auditlog.Request{ConversationID: "abc", RequestID: 1}), jc.ErrorIsNil)
+	c.Assert(log.AddResponse(auditlog.ResponseErrors{ConversationID: "abc", RequestID: 1}), jc.ErrorIsNil)
+	c.Assert(log.Close(), jc.ErrorIsNil)
+
+	for _, target := range []*stubLog{first, second} {
+		target.CheckCallNames(c, "AddConversation", "AddRequest", "AddResponse", "Close")
+		target.CheckCall(c, 1, "AddRequest", auditlog.Request{ConversationID: "abc", RequestID: 1})
+	}
+}
+
+func (s *FanOutSuite) TestCombinesErrors(c *gc.C) {
+	first, second, third := &stubLog{}, &stubLog{}, &stubLog{}
+	first.SetErrors(errors.New("disk full"))
+	third.SetErrors(errors.New("connection refused"))
+	log := auditlog.NewFanOut(first, second, third)
+
+	err := log.AddRequest(auditlog.Request{ConversationID: "abc"})
+	c.Assert(err, gc.ErrorMatches, "disk full; connection refused")
+	// A failing target doesn't stop the others being written to.
+	second.CheckCallNames(c, "AddRequest")
+	third.CheckCallNames(c, "AddRequest")
+}
+
+type stubLog struct {
+	testing.Stub
+}
+
+func (l *stubLog) AddConversation(c auditlog.Conversation) error {
+	l.MethodCall(l, "AddConversation", c)
+	return l.NextErr()
+}
+
+func (l *stubLog) AddRequest(r auditlog.Request) error {
+	l.MethodCall(l, "AddRequest", r)
+	return l.NextErr()
+}
+
+func (l *stubLog) AddResponse(r auditlog.ResponseErrors) error {
+	l.MethodCall(l, "AddResponse", r)
+	return l.NextErr()
+}
+
+func (l *stubLog) Close() error {
+	l.MethodCall(l, "Close")
+	return l.NextErr()
+}
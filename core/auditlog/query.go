@@ -0,0 +1,269 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+)
+
+const (
+	// logFileName is the name of the audit log file being written to.
+	logFileName = "audit.log"
+
+	// backupTimeFormat is the format lumberjack uses for the timestamp
+	// in the names of rotated log files, such as
+	// audit-2021-03-01T12-00-00.000.log.gz.
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+
+	// maxRecordSize is the largest audit record that will be read.
+	// Records can include the API call args, so may be large.
+	maxRecordSize = 16 * 1024 * 1024
+)
+
+// Filter selects the entries returned when reading an audit log.
+type Filter struct {
+	// Users, if set, only matches requests made in conversations
+	// started by these users.
+	Users []string
+
+	// Models, if set, only matches requests made on these models.
+	// Models can be given by name or UUID.
+	Models []string
+
+	// Methods, if set, only matches these API calls. Each is either a
+	// facade name, matching all the methods on the facade, or of the
+	// form Facade.Method.
+	Methods []string
+
+	// From, if set, only matches requests made at or after this time.
+	From time.Time
+
+	// To, if set, only matches requests made before this time.
+	To time.Time
+}
+
+// Entry is a request recorded in an audit log, along with the
+// conversation it was part of and any errors that were returned.
+type Entry struct {
+	Conversation Conversation `json:"conversation"`
+	Request      Request      `json:"request"`
+	Errors       []*Error     `json:"errors,omitempty"`
+}
+
+// When returns the time the request was made.
+func (e Entry) When() time.Time {
+	t, _ := time.Parse(time.RFC3339, e.Request.When)
+	return t
+}
+
+// ReadEntries reads the audit log files in logDir, including rotated
+// backups, returning the entries matching the filter in the order they
+// were recorded. If limit is greater than zero, only the most recent
+// limit entries are returned.
+func ReadEntries(logDir string, filter Filter, limit int) ([]Entry, error) {
+	paths, err := logFiles(logDir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	r := newEntryReader(filter, limit)
+	for _, path := range paths {
+		if err := r.readFile(path); err != nil {
+			return nil, errors.Annotatef(err, "reading %s", path)
+		}
+	}
+	return r.result(), nil
+}
+
+// logFiles returns the paths of the audit log files in logDir, oldest
+// first. All the backups are needed even when filtering by time, as a
+// conversation may have started long before the requests made in it.
+func logFiles(logDir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(logDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	prefix := strings.TrimSuffix(logFileName, ".log") + "-"
+	var backups []string
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		stamp := strings.TrimPrefix(name, prefix)
+		stamp = strings.TrimSuffix(stamp, ".gz")
+		if !strings.HasSuffix(stamp, ".log") {
+			continue
+		}
+		if _, err := time.Parse(backupTimeFormat, strings.TrimSuffix(stamp, ".log")); err != nil {
+			continue
+		}
+		backups = append(backups, name)
+	}
+	// The timestamp format sorts chronologically.
+	sort.Strings(backups)
+
+	var paths []string
+	for _, name := range backups {
+		paths = append(paths, filepath.Join(logDir, name))
+	}
+	current := filepath.Join(logDir, logFileName)
+	if _, err := os.Stat(current); err == nil {
+		paths = append(paths, current)
+	} else if !os.IsNotExist(err) {
+		return nil, errors.Trace(err)
+	}
+	return paths, nil
+}
+
+type requestKey struct {
+	conversationID string
+	requestID      uint64
+}
+
+// entryReader joins the records read from the log files into entries.
+type entryReader struct {
+	filter  Filter
+	limit   int
+	users   set.Strings
+	models  set.Strings
+	methods set.Strings
+
+	conversations map[string]*Conversation
+	entries       []*Entry
+	pending       map[requestKey]*Entry
+}
+
+func newEntryReader(filter Filter, limit int) *entryReader {
+	return &entryReader{
+		filter:        filter,
+		limit:         limit,
+		users:         set.NewStrings(filter.Users...),
+		models:        set.NewStrings(filter.Models...),
+		methods:       set.NewStrings(filter.Methods...),
+		conversations: make(map[string]*Conversation),
+		pending:       make(map[requestKey]*Entry),
+	}
+}
+
+func (r *entryReader) readFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+
+	var source io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer gz.Close()
+		source = gz
+	}
+
+	scanner := bufio.NewScanner(source)
+	scanner.Buffer(make([]byte, 64*1024), maxRecordSize)
+	for scanner.Scan() {
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// The last record may have been partially written, or
+			// the file truncated; neither should stop the rest of
+			// the log being read.
+			logger.Debugf("skipping invalid audit record in %s: %v", path, err)
+			continue
+		}
+		r.add(record)
+	}
+	return errors.Trace(scanner.Err())
+}
+
+func (r *entryReader) add(record Record) {
+	switch {
+	case record.Conversation != nil:
+		if r.matchConversation(record.Conversation) {
+			r.conversations[record.Conversation.ConversationID] = record.Conversation
+		}
+	case record.Request != nil:
+		conversation, ok := r.conversations[record.Request.ConversationID]
+		if !ok || !r.matchRequest(record.Request) {
+			return
+		}
+		entry := &Entry{
+			Conversation: *conversation,
+			Request:      *record.Request,
+		}
+		r.entries = append(r.entries, entry)
+		r.pending[keyFor(record.Request.ConversationID, record.Request.RequestID)] = entry
+		if r.limit > 0 && len(r.entries) > r.limit {
+			dropped := r.entries[0]
+			r.entries = r.entries[1:]
+			delete(r.pending, keyFor(dropped.Request.ConversationID, dropped.Request.RequestID))
+		}
+	case record.Errors != nil:
+		key := keyFor(record.Errors.ConversationID, record.Errors.RequestID)
+		if entry, ok := r.pending[key]; ok {
+			entry.Errors = record.Errors.Errors
+			delete(r.pending, key)
+		}
+	}
+}
+
+func keyFor(conversationID string, requestID uint64) requestKey {
+	return requestKey{conversationID: conversationID, requestID: requestID}
+}
+
+func (r *entryReader) matchConversation(c *Conversation) bool {
+	if !r.users.IsEmpty() && !r.users.Contains(c.Who) {
+		return false
+	}
+	if !r.models.IsEmpty() && !r.models.Contains(c.ModelName) && !r.models.Contains(c.ModelUUID) {
+		return false
+	}
+	return true
+}
+
+func (r *entryReader) matchRequest(req *Request) bool {
+	if !r.methods.IsEmpty() &&
+		!r.methods.Contains(req.Facade) &&
+		!r.methods.Contains(req.Facade+"."+req.Method) {
+		return false
+	}
+	if r.filter.From.IsZero() && r.filter.To.IsZero() {
+		return true
+	}
+	when, err := time.Parse(time.RFC3339, req.When)
+	if err != nil {
+		return false
+	}
+	if !r.filter.From.IsZero() && when.Before(r.filter.From) {
+		return false
+	}
+	if !r.filter.To.IsZero() && !when.Before(r.filter.To) {
+		return false
+	}
+	return true
+}
+
+func (r *entryReader) result() []Entry {
+	result := make([]Entry, len(r.entries))
+	for i, entry := range r.entries {
+		result[i] = *entry
+	}
+	return result
+}
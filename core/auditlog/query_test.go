@@ -0,0 +1,198 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog_test
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/auditlog"
+)
+
+type QuerySuite struct {
+	testing.IsolationSuite
+
+	dir string
+}
+
+var _ = gc.Suite(&QuerySuite{})
+
+var (
+	bobDefault = auditlog.Conversation{
+		Who:            "bob",
+		What:           "juju remove-application mysql",
+		When:           "2021-03-01T10:00:00Z",
+		ModelName:      "default",
+		ModelUUID:      "deadbeef-0bad-400d-8000-4b1d0d06f00d",
+		ConversationID: "0000000000000001",
+		ConnectionID:   "A",
+	}
+	aliceProd = auditlog.Conversation{
+		Who:            "alice",
+		What:           "juju deploy mysql",
+		When:           "2021-03-02T10:00:00Z",
+		ModelName:      "prod",
+		ModelUUID:      "badf00d0-0bad-400d-8000-4b1d0d06f00d",
+		ConversationID: "0000000000000002",
+		ConnectionID:   "B",
+	}
+)
+
+func (s *QuerySuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+
+	// The rotated backup holds bob's conversation, which carries on
+	// into the current log file.
+	s.writeLog(c, "audit-2021-03-01T10-00-03.000.log.gz",
+		auditlog.Record{Conversation: &bobDefault},
+		auditlog.Record{Request: request(bobDefault, 1, "2021-03-01T10:00:01Z", "Application", "DestroyApplication")},
+		auditlog.Record{Errors: &auditlog.ResponseErrors{
+			ConversationID: bobDefault.ConversationID,
+			RequestID:      1,
+			When:           "2021-03-01T10:00:02Z",
+			Errors:         []*auditlog.Error{{Message: "oops", Code: "not found"}},
+		}},
+	)
+	s.writeLog(c, "audit.log",
+		auditlog.Record{Request: request(bobDefault, 2, "2021-03-01T10:00:04Z", "Client", "FullStatus")},
+		auditlog.Record{Conversation: &aliceProd},
+		auditlog.Record{Request: request(aliceProd, 1, "2021-03-02T10:00:01Z", "Application", "Deploy")},
+		auditlog.Record{Request: request(aliceProd, 2, "2021-03-02T10:00:02Z", "Client", "FullStatus")},
+	)
+}
+
+func request(conv auditlog.Conversation, id uint64, when, facade, method string) *auditlog.Request {
+	return &auditlog.Request{
+		ConversationID: conv.ConversationID,
+		ConnectionID:   conv.ConnectionID,
+		RequestID:      id,
+		When:           when,
+		Facade:         facade,
+		Method:         method,
+		Version:        1,
+	}
+}
+
+func (s *QuerySuite) writeLog(c *gc.C, name string, records ...auditlog.Record) {
+	f, err := os.Create(filepath.Join(s.dir, name))
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+
+	var encoder *json.Encoder
+	if filepath.Ext(name) == ".gz" {
+		gz := gzip.NewWriter(f)
+		defer gz.Close()
+		encoder = json.NewEncoder(gz)
+	} else {
+		encoder = json.NewEncoder(f)
+	}
+	for _, record := range records {
+		c.Assert(encoder.Encode(record), jc.ErrorIsNil)
+	}
+}
+
+func (s *QuerySuite) readMethods(c *gc.C, filter auditlog.Filter, limit int) []string {
+	entries, err := auditlog.ReadEntries(s.dir, filter, limit)
+	c.Assert(err, jc.ErrorIsNil)
+	var methods []string
+	for _, entry := range entries {
+		methods = append(methods, entry.Conversation.Who+" "+entry.Request.Facade+"."+entry.Request.Method)
+	}
+	return methods
+}
+
+func (s *QuerySuite) TestReadAll(c *gc.C) {
+	entries, err := auditlog.ReadEntries(s.dir, auditlog.Filter{}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 4)
+	c.Check(entries[0], jc.DeepEquals, auditlog.Entry{
+		Conversation: bobDefault,
+		Request:      *request(bobDefault, 1, "2021-03-01T10:00:01Z", "Application", "DestroyApplication"),
+		Errors:       []*auditlog.Error{{Message: "oops", Code: "not found"}},
+	})
+	c.Check(entries[0].When(), gc.Equals, time.Date(2021, 3, 1, 10, 0, 1, 0, time.UTC))
+	c.Check(entries[1].Conversation, jc.DeepEquals, bobDefault)
+	c.Check(entries[1].Errors, gc.HasLen, 0)
+	c.Check(entries[3].Conversation, jc.DeepEquals, aliceProd)
+}
+
+func (s *QuerySuite) TestFilterUsers(c *gc.C) {
+	c.Check(s.readMethods(c, auditlog.Filter{Users: []string{"alice"}}, 0), jc.DeepEquals, []string{
+		"alice Application.Deploy",
+		"alice Client.FullStatus",
+	})
+}
+
+func (s *QuerySuite) TestFilterModels(c *gc.C) {
+	c.Check(s.readMethods(c, auditlog.Filter{Models: []string{"default"}}, 0), jc.DeepEquals, []string{
+		"bob Application.DestroyApplication",
+		"bob Client.FullStatus",
+	})
+	c.Check(s.readMethods(c, auditlog.Filter{Models: []string{aliceProd.ModelUUID}}, 0), jc.DeepEquals, []string{
+		"alice Application.Deploy",
+		"alice Client.FullStatus",
+	})
+}
+
+func (s *QuerySuite) TestFilterMethods(c *gc.C) {
+	c.Check(s.readMethods(c, auditlog.Filter{Methods: []string{"Application"}}, 0), jc.DeepEquals, []string{
+		"bob Application.DestroyApplication",
+		"alice Application.Deploy",
+	})
+	c.Check(s.readMethods(c, auditlog.Filter{Methods: []string{"Application.DestroyApplication", "Client.FullStatus"}}, 0), jc.DeepEquals, []string{
+		"bob Application.DestroyApplication",
+		"bob Client.FullStatus",
+		"alice Client.FullStatus",
+	})
+}
+
+func (s *QuerySuite) TestFilterTime(c *gc.C) {
+	c.Check(s.readMethods(c, auditlog.Filter{
+		From: time.Date(2021, 3, 1, 10, 0, 4, 0, time.UTC),
+		To:   time.Date(2021, 3, 2, 10, 0, 2, 0, time.UTC),
+	}, 0), jc.DeepEquals, []string{
+		"bob Client.FullStatus",
+		"alice Application.Deploy",
+	})
+}
+
+func (s *QuerySuite) TestIgnoresOtherFiles(c *gc.C) {
+	for _, name := range []string{"audit-junk.log", "audit.log.bak", "machine-0.log"} {
+		err := ioutil.WriteFile(filepath.Join(s.dir, name), []byte("junk"), 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Check(s.readMethods(c, auditlog.Filter{}, 0), gc.HasLen, 4)
+}
+
+func (s *QuerySuite) TestLimitKeepsMostRecent(c *gc.C) {
+	c.Check(s.readMethods(c, auditlog.Filter{}, 2), jc.DeepEquals, []string{
+		"alice Application.Deploy",
+		"alice Client.FullStatus",
+	})
+}
+
+func (s *QuerySuite) TestSkipsInvalidRecords(c *gc.C) {
+	f, err := os.OpenFile(filepath.Join(s.dir, "audit.log"), os.O_APPEND|os.O_WRONLY, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = f.WriteString(`{"request": {"conversation-id": "00`)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(f.Close(), jc.ErrorIsNil)
+
+	c.Check(s.readMethods(c, auditlog.Filter{}, 0), gc.HasLen, 4)
+}
+
+func (s *QuerySuite) TestNoLogDir(c *gc.C) {
+	entries, err := auditlog.ReadEntries(filepath.Join(s.dir, "missing"), auditlog.Filter{}, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 0)
+}
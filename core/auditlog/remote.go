@@ -0,0 +1,451 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+)
+
+// sendBatchSize is the most records passed to a Sender at once.
+const sendBatchSize = 100
+
+// Sender delivers audit records to a remote store.
+type Sender interface {
+	// Send delivers the records, in order, and returns how many of
+	// them were delivered. If an error is returned the records that
+	// weren't delivered will be sent again later.
+	Send([]Record) (int, error)
+
+	// Close releases any resources held by the sender.
+	Close() error
+}
+
+// RemoteConfig holds the parameters for an audit log that sends records
+// to a remote store.
+type RemoteConfig struct {
+	// Name identifies the remote store, and is used to name the
+	// buffer files.
+	Name string
+
+	// Sender delivers the records to the remote store.
+	Sender Sender
+
+	// BufferDir is the directory holding the file where records are
+	// kept until they have been sent.
+	BufferDir string
+
+	// MaxBufferSize is the most bytes of unsent records that will be
+	// kept. Records added when the buffer is full are dropped, unless
+	// FailWhenFull is set, until the buffer has been sent; then a Gap
+	// record is added saying how many were dropped.
+	MaxBufferSize int64
+
+	// FailWhenFull makes adding a record fail, rather than drop the
+	// record, when the buffer is full.
+	FailWhenFull bool
+
+	// RetryDelay is how long to wait before trying again when records
+	// can't be sent.
+	RetryDelay time.Duration
+
+	// Clock is used to wait between retries.
+	Clock clock.Clock
+}
+
+// Validate checks the remote audit log configuration.
+func (cfg RemoteConfig) Validate() error {
+	if cfg.Name == "" {
+		return errors.NotValidf("empty Name")
+	}
+	if cfg.Sender == nil {
+		return errors.NotValidf("nil Sender")
+	}
+	if cfg.BufferDir == "" {
+		return errors.NotValidf("empty BufferDir")
+	}
+	if cfg.MaxBufferSize <= 0 {
+		return errors.NotValidf("non-positive MaxBufferSize")
+	}
+	if cfg.RetryDelay <= 0 {
+		return errors.NotValidf("non-positive RetryDelay")
+	}
+	if cfg.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	return nil
+}
+
+// remoteLog appends records to a local buffer file, from which a
+// background goroutine sends them to the remote store. The offset of
+// the first unsent record is saved alongside the buffer, so records
+// that couldn't be sent survive restarts. Once everything in the
+// buffer has been sent it is truncated.
+type remoteLog struct {
+	cfg        RemoteConfig
+	bufferPath string
+	offsetPath string
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	offset int64
+	full   bool
+	closed bool
+
+	// dropped counts the records dropped since the buffer filled up,
+	// between droppedFrom and droppedTo.
+	dropped     int64
+	droppedFrom time.Time
+	droppedTo   time.Time
+
+	wake      chan struct{}
+	closing   chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewRemoteLog returns an AuditLog that sends records to a remote store.
+// Records are written to a buffer file before being sent, so that they
+// are kept while the remote store can't be reached, and are sent in the
+// order they were added once it is available again.
+func NewRemoteLog(cfg RemoteConfig) (AuditLog, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	l := &remoteLog{
+		cfg:        cfg,
+		bufferPath: filepath.Join(cfg.BufferDir, "audit-"+cfg.Name+".buffer"),
+		offsetPath: filepath.Join(cfg.BufferDir, "audit-"+cfg.Name+".offset"),
+		wake:       make(chan struct{}, 1),
+		closing:    make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	if err := l.open(); err != nil {
+		return nil, errors.Annotatef(err, "opening %s audit log buffer", cfg.Name)
+	}
+	go l.loop()
+	return l, nil
+}
+
+func (l *remoteLog) open() error {
+	file, err := os.OpenFile(l.bufferPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return errors.Trace(err)
+	}
+	l.file = file
+	l.size = info.Size()
+
+	data, err := ioutil.ReadFile(l.offsetPath)
+	if err != nil && !os.IsNotExist(err) {
+		_ = file.Close()
+		return errors.Trace(err)
+	}
+	if offset, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err == nil &&
+		offset >= 0 && offset <= l.size {
+		l.offset = offset
+	}
+	return nil
+}
+
+// AddConversation implements AuditLog.
+func (l *remoteLog) AddConversation(c Conversation) error {
+	return errors.Trace(l.addRecord(Record{Conversation: &c}))
+}
+
+// AddRequest implements AuditLog.
+func (l *remoteLog) AddRequest(r Request) error {
+	return errors.Trace(l.addRecord(Record{Request: &r}))
+}
+
+// AddResponse implements AuditLog.
+func (l *remoteLog) AddResponse(r ResponseErrors) error {
+	return errors.Trace(l.addRecord(Record{Errors: &r}))
+}
+
+// Close implements AuditLog. Any records that haven't been sent are
+// left in the buffer, to be sent by the next remote log using it.
+func (l *remoteLog) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		close(l.closing)
+		<-l.done
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.closed = true
+		if l.dropped > 0 {
+			logger.Errorf("%s audit log closed after dropping %d records", l.cfg.Name, l.dropped)
+		}
+		err = l.file.Close()
+		if sendErr := l.cfg.Sender.Close(); err == nil {
+			err = sendErr
+		}
+	})
+	return errors.Trace(err)
+}
+
+func (l *remoteLog) addRecord(r Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return errors.Errorf("%s audit log closed", l.cfg.Name)
+	}
+	if l.full || l.size-l.offset+int64(len(data)) > l.cfg.MaxBufferSize {
+		if l.cfg.FailWhenFull {
+			return errors.Errorf("%s audit log buffer full", l.cfg.Name)
+		}
+		// Failing the record would fail the API request being
+		// audited, and with it every request until the remote store
+		// comes back. Drop it instead, complaining once, and count it
+		// so the gap can be recorded once the buffer has been sent;
+		// any other audit targets will still record it.
+		now := l.cfg.Clock.Now()
+		if !l.full {
+			logger.Errorf("%s audit log buffer full, dropping records until it can be sent", l.cfg.Name)
+			l.full = true
+		}
+		if l.dropped == 0 {
+			l.droppedFrom = now
+		}
+		l.dropped++
+		l.droppedTo = now
+		return nil
+	}
+	return errors.Trace(l.write(data))
+}
+
+// write appends the encoded record to the buffer, and wakes the loop
+// to send it. It must be called with the mutex held.
+func (l *remoteLog) write(data []byte) error {
+	if _, err := l.file.Write(data); err != nil {
+		return errors.Trace(err)
+	}
+	l.size += int64(len(data))
+
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// writeGap adds a Gap record for the records dropped while the buffer
+// was full. It must be called with the mutex held.
+func (l *remoteLog) writeGap() error {
+	data, err := json.Marshal(Record{Gap: &Gap{
+		Target:  l.cfg.Name,
+		Dropped: l.dropped,
+		From:    l.droppedFrom.Format(time.RFC3339),
+		To:      l.droppedTo.Format(time.RFC3339),
+	}})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := l.write(append(data, '\n')); err != nil {
+		return errors.Trace(err)
+	}
+	logger.Errorf("%s audit log dropped %d records while its buffer was full", l.cfg.Name, l.dropped)
+	l.dropped = 0
+	return nil
+}
+
+func (l *remoteLog) loop() {
+	defer close(l.done)
+	for {
+		if err := l.sendBuffered(); err != nil {
+			logger.Warningf("sending audit records to %s (will retry): %v", l.cfg.Name, err)
+			select {
+			case <-l.closing:
+				return
+			case <-l.cfg.Clock.After(l.cfg.RetryDelay):
+			}
+			continue
+		}
+		select {
+		case <-l.closing:
+			return
+		case <-l.wake:
+		}
+	}
+}
+
+// sendBuffered sends the records in the buffer until it is empty.
+func (l *remoteLog) sendBuffered() error {
+	for {
+		batch, ok, err := l.readBatch()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !ok {
+			return nil
+		}
+		if len(batch.records) > 0 {
+			sent, err := l.cfg.Sender.Send(batch.records)
+			if err != nil {
+				// Skip the records that were delivered, so
+				// they aren't sent again.
+				if sent > 0 && sent <= len(batch.records) {
+					if err := l.advance(batch.ends[sent-1]); err != nil {
+						return errors.Trace(err)
+					}
+				}
+				return errors.Trace(err)
+			}
+		}
+		if err := l.advance(batch.next); err != nil {
+			return errors.Trace(err)
+		}
+		select {
+		case <-l.closing:
+			return nil
+		default:
+		}
+	}
+}
+
+// batch holds records read from the buffer.
+type batch struct {
+	records []Record
+
+	// ends holds the offset following each record.
+	ends []int64
+
+	// next is the offset following everything read, including any
+	// invalid records that were skipped.
+	next int64
+}
+
+// readBatch returns the next records to send from the buffer. It
+// returns false if the buffer is empty.
+func (l *remoteLog) readBatch() (batch, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.offset >= l.size {
+		return batch{}, false, nil
+	}
+
+	f, err := os.Open(l.bufferPath)
+	if err != nil {
+		return batch{}, false, errors.Trace(err)
+	}
+	defer f.Close()
+	if _, err := f.Seek(l.offset, io.SeekStart); err != nil {
+		return batch{}, false, errors.Trace(err)
+	}
+
+	reader := bufio.NewReader(io.LimitReader(f, l.size-l.offset))
+	result := batch{next: l.offset}
+	for len(result.records) < sendBatchSize {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return batch{}, false, errors.Trace(err)
+		}
+		result.next += int64(len(line))
+		var record Record
+		if err := json.Unmarshal(line, &record); err != nil {
+			logger.Warningf("skipping invalid record in %s: %v", l.bufferPath, err)
+			continue
+		}
+		result.records = append(result.records, record)
+		result.ends = append(result.ends, result.next)
+	}
+	return result, true, nil
+}
+
+// advance records that everything before offset has been sent. The
+// buffer is truncated once it has all been sent, and compacted if the
+// records already sent would otherwise take up more space than the
+// unsent ones are allowed.
+func (l *remoteLog) advance(offset int64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.offset = offset
+	switch {
+	case l.offset >= l.size:
+		if err := l.file.Truncate(0); err != nil {
+			return errors.Trace(err)
+		}
+		l.size = 0
+		l.offset = 0
+		if l.full {
+			logger.Infof("%s audit log buffer sent, no longer dropping records", l.cfg.Name)
+			l.full = false
+		}
+		if l.dropped > 0 {
+			if err := l.writeGap(); err != nil {
+				return errors.Annotate(err, "recording dropped records")
+			}
+		}
+	case l.offset > l.cfg.MaxBufferSize:
+		if err := l.compact(); err != nil {
+			return errors.Annotate(err, "compacting buffer")
+		}
+	}
+	err := ioutil.WriteFile(l.offsetPath, []byte(strconv.FormatInt(l.offset, 10)), 0600)
+	return errors.Trace(err)
+}
+
+// compact replaces the buffer file with one holding only the unsent
+// records. It must be called with the mutex held.
+func (l *remoteLog) compact() error {
+	src, err := os.Open(l.bufferPath)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer src.Close()
+	if _, err := src.Seek(l.offset, io.SeekStart); err != nil {
+		return errors.Trace(err)
+	}
+
+	tmpPath := l.bufferPath + ".tmp"
+	dst, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	n, err := io.Copy(dst, io.LimitReader(src, l.size-l.offset))
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := os.Rename(tmpPath, l.bufferPath); err != nil {
+		return errors.Trace(err)
+	}
+
+	file, err := os.OpenFile(l.bufferPath, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_ = l.file.Close()
+	l.file = file
+	l.size = n
+	l.offset = 0
+	return nil
+}
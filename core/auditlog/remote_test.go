@@ -0,0 +1,400 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/auditlog"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type RemoteSuite struct {
+	testing.IsolationSuite
+
+	dir    string
+	clock  *testclock.Clock
+	sender *fakeSender
+	config auditlog.RemoteConfig
+}
+
+var _ = gc.Suite(&RemoteSuite{})
+
+func (s *RemoteSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+	s.clock = testclock.NewClock(time.Now())
+	s.sender = newFakeSender()
+	s.config = auditlog.RemoteConfig{
+		Name:          "syslog",
+		Sender:        s.sender,
+		BufferDir:     s.dir,
+		MaxBufferSize: 1024 * 1024,
+		RetryDelay:    time.Minute,
+		Clock:         s.clock,
+	}
+}
+
+func (s *RemoteSuite) newLog(c *gc.C) auditlog.AuditLog {
+	log, err := auditlog.NewRemoteLog(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { _ = log.Close() })
+	return log
+}
+
+func (s *RemoteSuite) bufferPath() string {
+	return filepath.Join(s.dir, "audit-syslog.buffer")
+}
+
+func (s *RemoteSuite) offsetPath() string {
+	return filepath.Join(s.dir, "audit-syslog.offset")
+}
+
+func (s *RemoteSuite) readBuffer(c *gc.C) []auditlog.Record {
+	f, err := os.Open(s.bufferPath())
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+	var records []auditlog.Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record auditlog.Record
+		c.Assert(json.Unmarshal(scanner.Bytes(), &record), jc.ErrorIsNil)
+		records = append(records, record)
+	}
+	c.Assert(scanner.Err(), jc.ErrorIsNil)
+	return records
+}
+
+func (s *RemoteSuite) writeBuffer(c *gc.C, records ...auditlog.Record) {
+	f, err := os.Create(s.bufferPath())
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+	encoder := json.NewEncoder(f)
+	for _, record := range records {
+		c.Assert(encoder.Encode(record), jc.ErrorIsNil)
+	}
+}
+
+func requestRecord(id uint64) auditlog.Record {
+	return auditlog.Record{Request: &auditlog.Request{
+		ConversationID: "0123456789abcdef",
+		RequestID:      id,
+		Facade:         "Application",
+		Method:         "Deploy",
+	}}
+}
+
+func addRequests(c *gc.C, log auditlog.AuditLog, ids ...uint64) {
+	for _, id := range ids {
+		c.Assert(log.AddRequest(*requestRecord(id).Request), jc.ErrorIsNil)
+	}
+}
+
+func requestIDs(records []auditlog.Record) []uint64 {
+	var ids []uint64
+	for _, record := range records {
+		ids = append(ids, record.Request.RequestID)
+	}
+	return ids
+}
+
+func (s *RemoteSuite) TestValidate(c *gc.C) {
+	type test struct {
+		f      func(*auditlog.RemoteConfig)
+		expect string
+	}
+	tests := []test{{
+		func(cfg *auditlog.RemoteConfig) { cfg.Name = "" },
+		"empty Name not valid",
+	}, {
+		func(cfg *auditlog.RemoteConfig) { cfg.Sender = nil },
+		"nil Sender not valid",
+	}, {
+		func(cfg *auditlog.RemoteConfig) { cfg.BufferDir = "" },
+		"empty BufferDir not valid",
+	}, {
+		func(cfg *auditlog.RemoteConfig) { cfg.MaxBufferSize = 0 },
+		"non-positive MaxBufferSize not valid",
+	}, {
+		func(cfg *auditlog.RemoteConfig) { cfg.RetryDelay = 0 },
+		"non-positive RetryDelay not valid",
+	}, {
+		func(cfg *auditlog.RemoteConfig) { cfg.Clock = nil },
+		"nil Clock not valid",
+	}}
+	for i, test := range tests {
+		c.Logf("test #%d (%s)", i, test.expect)
+		config := s.config
+		test.f(&config)
+		_, err := auditlog.NewRemoteLog(config)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
+
+func (s *RemoteSuite) TestSendsRecords(c *gc.C) {
+	log := s.newLog(c)
+	c.Assert(log.AddConversation(auditlog.Conversation{ConversationID: "0123456789abcdef"}), jc.ErrorIsNil)
+	addRequests(c, log, 1)
+	c.Assert(log.AddResponse(auditlog.ResponseErrors{ConversationID: "0123456789abcdef", RequestID: 1}), jc.ErrorIsNil)
+
+	records := s.sender.waitForRecords(c, 3)
+	c.Assert(records[0].Conversation, gc.NotNil)
+	c.Assert(records[1].Request, gc.NotNil)
+	c.Assert(records[2].Errors, gc.NotNil)
+
+	c.Assert(log.Close(), jc.ErrorIsNil)
+	c.Assert(s.readBuffer(c), gc.HasLen, 0)
+	c.Assert(s.sender.isClosed(), jc.IsTrue)
+}
+
+func (s *RemoteSuite) TestRetriesWhenRemoteDown(c *gc.C) {
+	s.sender.setErrors(errors.New("connection refused"))
+	log := s.newLog(c)
+	addRequests(c, log, 1, 2)
+
+	// The first attempt fails, and the records are kept.
+	s.sender.waitForAttempt(c)
+	c.Assert(requestIDs(s.readBuffer(c)), jc.DeepEquals, []uint64{1, 2})
+
+	// Records added while waiting to retry are sent after the
+	// earlier ones.
+	addRequests(c, log, 3)
+	c.Assert(s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	c.Assert(requestIDs(s.sender.waitForRecords(c, 3)), jc.DeepEquals, []uint64{1, 2, 3})
+}
+
+func (s *RemoteSuite) TestRetrySkipsDeliveredRecords(c *gc.C) {
+	s.sender.setErrors(errors.New("broken pipe"))
+	s.sender.partial = 1
+	s.writeBuffer(c, requestRecord(1), requestRecord(2), requestRecord(3))
+	s.newLog(c)
+
+	// The first attempt delivers one record before failing; only the
+	// others are sent again.
+	s.sender.waitForAttempt(c)
+	c.Assert(s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	c.Assert(requestIDs(s.sender.waitForRecords(c, 3)), jc.DeepEquals, []uint64{1, 2, 3})
+}
+
+func (s *RemoteSuite) TestBufferSurvivesRestart(c *gc.C) {
+	s.sender.setErrors(errors.New("connection refused"))
+	log := s.newLog(c)
+	addRequests(c, log, 1, 2)
+	s.sender.waitForAttempt(c)
+	c.Assert(log.Close(), jc.ErrorIsNil)
+
+	s.sender = newFakeSender()
+	s.config.Sender = s.sender
+	s.newLog(c)
+	c.Assert(requestIDs(s.sender.waitForRecords(c, 2)), jc.DeepEquals, []uint64{1, 2})
+}
+
+func (s *RemoteSuite) TestSkipsSentRecordsAfterRestart(c *gc.C) {
+	s.writeBuffer(c, requestRecord(1), requestRecord(2))
+	data, err := ioutil.ReadFile(s.bufferPath())
+	c.Assert(err, jc.ErrorIsNil)
+	firstLen := strings.Index(string(data), "\n") + 1
+	err = ioutil.WriteFile(s.offsetPath(), []byte(strconv.Itoa(firstLen)), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.newLog(c)
+	c.Assert(requestIDs(s.sender.waitForRecords(c, 1)), jc.DeepEquals, []uint64{2})
+}
+
+func (s *RemoteSuite) TestDropsRecordsWhenFull(c *gc.C) {
+	s.sender.setErrors(errors.New("connection refused"))
+	record, err := json.Marshal(requestRecord(1))
+	c.Assert(err, jc.ErrorIsNil)
+	s.config.MaxBufferSize = int64(2*len(record) + 2)
+	log := s.newLog(c)
+
+	addRequests(c, log, 1, 2, 3, 4)
+	c.Assert(requestIDs(s.readBuffer(c)), jc.DeepEquals, []uint64{1, 2})
+	c.Assert(c.GetTestLog(), jc.Contains, "syslog audit log buffer full, dropping records")
+
+	// Once the buffer has been sent, the gap is recorded and records
+	// are kept again.
+	s.sender.waitForAttempt(c)
+	c.Assert(s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	c.Assert(requestIDs(s.sender.waitForRecords(c, 2)), jc.DeepEquals, []uint64{1, 2})
+	now := s.clock.Now().Add(-time.Minute).Format(time.RFC3339)
+	c.Assert(s.sender.waitForRecords(c, 1), jc.DeepEquals, []auditlog.Record{{
+		Gap: &auditlog.Gap{Target: "syslog", Dropped: 2, From: now, To: now},
+	}})
+	c.Assert(c.GetTestLog(), jc.Contains, "syslog audit log dropped 2 records while its buffer was full")
+	addRequests(c, log, 5)
+	c.Assert(requestIDs(s.sender.waitForRecords(c, 1)), jc.DeepEquals, []uint64{5})
+}
+
+func (s *RemoteSuite) TestDropsRecordsUntilBufferSent(c *gc.C) {
+	s.sender.setErrors(errors.New("connection refused"))
+	record, err := json.Marshal(requestRecord(1))
+	c.Assert(err, jc.ErrorIsNil)
+	s.config.MaxBufferSize = int64(3*len(record) + 3)
+	log := s.newLog(c)
+
+	// Once a record has been dropped, later ones are dropped too, even
+	// if they'd fit, so the gap comes after all the records kept.
+	addRequests(c, log, 1, 2)
+	big := *requestRecord(3).Request
+	big.Args = strings.Repeat("x", len(record))
+	c.Assert(log.AddRequest(big), jc.ErrorIsNil)
+	addRequests(c, log, 4)
+	c.Assert(requestIDs(s.readBuffer(c)), jc.DeepEquals, []uint64{1, 2})
+
+	s.sender.waitForAttempt(c)
+	c.Assert(s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	records := s.sender.waitForRecords(c, 2)
+	c.Assert(requestIDs(records), jc.DeepEquals, []uint64{1, 2})
+	records = s.sender.waitForRecords(c, 1)
+	c.Assert(records[0].Gap, gc.NotNil)
+	c.Assert(records[0].Gap.Dropped, gc.Equals, int64(2))
+}
+
+func (s *RemoteSuite) TestFailWhenFull(c *gc.C) {
+	s.sender.setErrors(errors.New("connection refused"))
+	record, err := json.Marshal(requestRecord(1))
+	c.Assert(err, jc.ErrorIsNil)
+	s.config.MaxBufferSize = int64(2*len(record) + 2)
+	s.config.FailWhenFull = true
+	log := s.newLog(c)
+
+	addRequests(c, log, 1, 2)
+	err = log.AddRequest(*requestRecord(3).Request)
+	c.Assert(err, gc.ErrorMatches, "syslog audit log buffer full")
+	c.Assert(requestIDs(s.readBuffer(c)), jc.DeepEquals, []uint64{1, 2})
+
+	// Nothing was dropped, so no gap is recorded.
+	s.sender.waitForAttempt(c)
+	c.Assert(s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1), jc.ErrorIsNil)
+	c.Assert(requestIDs(s.sender.waitForRecords(c, 2)), jc.DeepEquals, []uint64{1, 2})
+	addRequests(c, log, 3)
+	c.Assert(requestIDs(s.sender.waitForRecords(c, 1)), jc.DeepEquals, []uint64{3})
+}
+
+func (s *RemoteSuite) TestCompactsSentRecords(c *gc.C) {
+	var records []auditlog.Record
+	for i := uint64(1); i <= 150; i++ {
+		records = append(records, requestRecord(i))
+	}
+	s.writeBuffer(c, records...)
+	// The first batch sent is bigger than the buffer is allowed to
+	// be, so once it has been sent the buffer is compacted.
+	data, err := json.Marshal(requestRecord(1))
+	c.Assert(err, jc.ErrorIsNil)
+	s.config.MaxBufferSize = int64(60 * len(data))
+	s.sender.setErrors(nil, errors.New("connection refused"))
+	s.newLog(c)
+
+	c.Assert(s.sender.waitForRecords(c, 100), gc.HasLen, 100)
+	s.sender.waitForAttempt(c)
+	buffered := s.readBuffer(c)
+	c.Assert(buffered, gc.HasLen, 50)
+	c.Assert(buffered[0].Request.RequestID, gc.Equals, uint64(101))
+	offset, err := ioutil.ReadFile(s.offsetPath())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(offset), gc.Equals, "0")
+}
+
+func (s *RemoteSuite) TestAddAfterClose(c *gc.C) {
+	log := s.newLog(c)
+	c.Assert(log.Close(), jc.ErrorIsNil)
+	err := log.AddRequest(*requestRecord(1).Request)
+	c.Assert(err, gc.ErrorMatches, "syslog audit log closed")
+}
+
+// fakeSender reports each attempt to send records, failing those for
+// which an error has been set. A failed attempt delivers the number of
+// records given by partial.
+type fakeSender struct {
+	mu       sync.Mutex
+	errs     []error
+	partial  int
+	closed   bool
+	attempts chan []auditlog.Record
+	sent     chan []auditlog.Record
+}
+
+func newFakeSender() *fakeSender {
+	return &fakeSender{
+		attempts: make(chan []auditlog.Record, 100),
+		sent:     make(chan []auditlog.Record, 100),
+	}
+}
+
+func (s *fakeSender) setErrors(errs ...error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errs = errs
+}
+
+func (s *fakeSender) Send(records []auditlog.Record) (int, error) {
+	s.mu.Lock()
+	var err error
+	if len(s.errs) > 0 {
+		err, s.errs = s.errs[0], s.errs[1:]
+	}
+	partial := s.partial
+	s.mu.Unlock()
+	if err != nil {
+		if partial > 0 {
+			s.sent <- records[:partial]
+		}
+		s.attempts <- records
+		return partial, err
+	}
+	s.sent <- records
+	return len(records), nil
+}
+
+func (s *fakeSender) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *fakeSender) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// waitForAttempt waits for a failed attempt to send records.
+func (s *fakeSender) waitForAttempt(c *gc.C) {
+	select {
+	case <-s.attempts:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for send attempt")
+	}
+}
+
+// waitForRecords waits for n records to be sent successfully.
+func (s *fakeSender) waitForRecords(c *gc.C, n int) []auditlog.Record {
+	var records []auditlog.Record
+	for len(records) < n {
+		select {
+		case sent := <-s.sent:
+			records = append(records, sent...)
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for records, got %d of %d", len(records), n)
+		}
+	}
+	c.Assert(records, gc.HasLen, n)
+	return records
+}
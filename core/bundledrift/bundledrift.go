@@ -0,0 +1,100 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package bundledrift compares models with the bundles registered as
+// describing how they should be deployed.
+package bundledrift
+
+import (
+	"strings"
+
+	"github.com/juju/bundlechanges/v4"
+	"github.com/juju/charm/v8"
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Logger defines the methods needed to log the comparison.
+type Logger interface {
+	Tracef(string, ...interface{})
+}
+
+// ReadBundle returns the bundle data of the bundle YAML, with the
+// overlays applied in order. As the bundle is read on the controller,
+// include-file directives are not allowed.
+func ReadBundle(bundle string, overlays []string) (*charm.BundleData, error) {
+	var sources []charm.BundleDataSource
+	for i, part := range append([]string{bundle}, overlays...) {
+		source, err := charm.StreamBundleDataSource(strings.NewReader(part), "")
+		if err != nil {
+			if i == 0 {
+				return nil, errors.Annotate(err, "reading bundle")
+			}
+			return nil, errors.Annotatef(err, "reading overlay %d", i)
+		}
+		sources = append(sources, noIncludesSource{source})
+	}
+	data, err := charm.ReadAndMergeBundleData(sources...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := data.Verify(nil, nil, nil); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return data, nil
+}
+
+// noIncludesSource is a charm.BundleDataSource that refuses to resolve
+// include-file directives, which would read files on the controller.
+type noIncludesSource struct {
+	charm.BundleDataSource
+}
+
+// ResolveInclude is part of charm.BundleDataSource.
+func (noIncludesSource) ResolveInclude(path string) ([]byte, error) {
+	return nil, errors.NotSupportedf("include-file %q in desired bundle", path)
+}
+
+// Diff returns the differences between the bundle, with the overlays
+// applied, and the model. Annotations are not compared.
+func Diff(bundle string, overlays []string, model *bundlechanges.Model, logger Logger) (*bundlechanges.BundleDiff, error) {
+	data, err := ReadBundle(bundle, overlays)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	diff, err := bundlechanges.BuildDiff(bundlechanges.DiffConfig{
+		Bundle: data,
+		Model:  model,
+		Logger: logger,
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "comparing model with bundle")
+	}
+	return diff, nil
+}
+
+// Count returns the number of differences in the diff: one for each
+// application, machine and relation which differs.
+func Count(diff *bundlechanges.BundleDiff) int {
+	if diff == nil {
+		return 0
+	}
+	count := len(diff.Applications) + len(diff.Machines)
+	if diff.Relations != nil {
+		count += len(diff.Relations.BundleAdditions) + len(diff.Relations.ModelAdditions)
+	}
+	return count
+}
+
+// Format returns the diff in the YAML format of diff-bundle, or an empty
+// string if there are no differences.
+func Format(diff *bundlechanges.BundleDiff) (string, error) {
+	if diff == nil || diff.Empty() {
+		return "", nil
+	}
+	data, err := yaml.Marshal(diff)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundledrift_test
+
+import (
+	"github.com/juju/bundlechanges/v4"
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/bundledrift"
+)
+
+type BundleDriftSuite struct{}
+
+var _ = gc.Suite(&BundleDriftSuite{})
+
+const bundle = `
+applications:
+  mysql:
+    charm: cs:mysql-58
+    num_units: 1
+    to: ["0"]
+  wordpress:
+    charm: cs:wordpress-5
+    num_units: 1
+    options:
+      blog-title: hello
+    to: ["1"]
+machines:
+  "0": {}
+  "1": {}
+relations:
+- ["wordpress:db", "mysql:server"]
+`
+
+func (s *BundleDriftSuite) model() *bundlechanges.Model {
+	return &bundlechanges.Model{
+		Applications: map[string]*bundlechanges.Application{
+			"mysql": {
+				Name:  "mysql",
+				Charm: "cs:mysql-58",
+				Units: []bundlechanges.Unit{{Name: "mysql/0", Machine: "0"}},
+			},
+			"wordpress": {
+				Name:    "wordpress",
+				Charm:   "cs:wordpress-5",
+				Options: map[string]interface{}{"blog-title": "hello"},
+				Units:   []bundlechanges.Unit{{Name: "wordpress/0", Machine: "1"}},
+			},
+		},
+		Machines: map[string]*bundlechanges.Machine{
+			"0": {ID: "0"},
+			"1": {ID: "1"},
+		},
+		Relations: []bundlechanges.Relation{{
+			App1: "wordpress", Endpoint1: "db", App2: "mysql", Endpoint2: "server",
+		}},
+	}
+}
+
+func (s *BundleDriftSuite) TestDiffNone(c *gc.C) {
+	diff, err := bundledrift.Diff(bundle, nil, s.model(), loggo.GetLogger("test"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(diff.Empty(), jc.IsTrue)
+	c.Check(bundledrift.Count(diff), gc.Equals, 0)
+	formatted, err := bundledrift.Format(diff)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(formatted, gc.Equals, "")
+}
+
+func (s *BundleDriftSuite) TestDiffHandEdited(c *gc.C) {
+	model := s.model()
+	model.Applications["wordpress"].Options["blog-title"] = "edited"
+	model.Relations = nil
+	diff, err := bundledrift.Diff(bundle, nil, model, loggo.GetLogger("test"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(bundledrift.Count(diff), gc.Equals, 2)
+	formatted, err := bundledrift.Format(diff)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(formatted, gc.Equals, `
+applications:
+  wordpress:
+    options:
+      blog-title:
+        bundle: hello
+        model: edited
+relations:
+  bundle-additions:
+  - - mysql:server
+    - wordpress:db
+`[1:])
+}
+
+func (s *BundleDriftSuite) TestDiffOverlay(c *gc.C) {
+	overlay := `
+applications:
+  wordpress:
+    options:
+      blog-title: edited
+`
+	model := s.model()
+	model.Applications["wordpress"].Options["blog-title"] = "edited"
+	diff, err := bundledrift.Diff(bundle, []string{overlay}, model, loggo.GetLogger("test"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(diff.Empty(), jc.IsTrue)
+}
+
+func (s *BundleDriftSuite) TestReadBundleInvalid(c *gc.C) {
+	_, err := bundledrift.ReadBundle("applications: [", nil)
+	c.Assert(err, gc.ErrorMatches, "reading bundle: .*")
+	_, err = bundledrift.ReadBundle(bundle, []string{"applications: ["})
+	c.Assert(err, gc.ErrorMatches, "reading overlay 1: .*")
+}
+
+func (s *BundleDriftSuite) TestReadBundleNoIncludes(c *gc.C) {
+	_, err := bundledrift.ReadBundle(`
+applications:
+  wordpress:
+    charm: cs:wordpress-5
+    options:
+      blog-title: include-file:///etc/passwd
+`, nil)
+	c.Assert(err, gc.ErrorMatches, `.*include-file "/etc/passwd" in desired bundle not supported.*`)
+}
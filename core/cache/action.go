@@ -0,0 +1,71 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cache
+
+import (
+	"time"
+)
+
+// Action represents a pending or running action in a cached model.
+type Action struct {
+	// Resident identifies the action as a type-agnostic cached entity
+	// and tracks resources that it is responsible for cleaning up.
+	*Resident
+
+	details ActionChange
+}
+
+func newAction(res *Resident) *Action {
+	return &Action{
+		Resident: res,
+	}
+}
+
+// Note that these property accessors are not lock-protected.
+// They are intended for calling from external packages that have retrieved a
+// deep copy from the cache.
+
+// Id returns the id of the action.
+func (a *Action) Id() string {
+	return a.details.Id
+}
+
+// Receiver returns the name of the entity the action runs on.
+func (a *Action) Receiver() string {
+	return a.details.Receiver
+}
+
+// Name returns the name of the action.
+func (a *Action) Name() string {
+	return a.details.Name
+}
+
+// Status returns the status of the action; either pending or running.
+func (a *Action) Status() string {
+	return a.details.Status
+}
+
+// Enqueued returns the time the action was added.
+func (a *Action) Enqueued() time.Time {
+	return a.details.Enqueued
+}
+
+// Started returns the time the action started running, or the zero
+// time if it is still pending.
+func (a *Action) Started() time.Time {
+	return a.details.Started
+}
+
+func (a *Action) setDetails(details ActionChange) {
+	a.setRemovalMessage(RemoveAction{
+		ModelUUID: details.ModelUUID,
+		Id:        details.Id,
+	})
+	a.details = details
+}
+
+// copy returns a copy of the action.
+func (a *Action) copy() Action {
+	return *a
+}
@@ -72,3 +72,13 @@ func BranchEvents(change interface{}) bool {
 	}
 	return false
 }
+
+func ActionEvents(change interface{}) bool {
+	switch change.(type) {
+	case cache.ActionChange:
+		return true
+	case cache.RemoveAction:
+		return true
+	}
+	return false
+}
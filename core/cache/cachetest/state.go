@@ -51,6 +51,7 @@ func ModelChange(c *gc.C, model *state.Model) cache.ModelChange {
 		IsController:    model.IsControllerModel(),
 		Config:          cfg.AllAttrs(),
 		Status:          status,
+		MigrationMode:   string(model.MigrationMode()),
 		UserPermissions: permissions,
 	}
 }
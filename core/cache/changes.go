@@ -38,6 +38,7 @@ type ModelChange struct {
 	Annotations     map[string]string
 	Config          map[string]interface{}
 	Status          status.StatusInfo
+	MigrationMode   string
 
 	UserPermissions map[string]permission.Access
 }
@@ -358,6 +359,27 @@ type RemoveBranch struct {
 	Id        string
 }
 
+// ActionChange represents a change to a pending or running action.
+// Note that, as with branches, the cache only holds actions which are
+// in-flight; a completed action is removed from the cache.
+type ActionChange struct {
+	ModelUUID string
+	Id        string
+	Receiver  string
+	Name      string
+	Status    string
+	Enqueued  time.Time
+	Started   time.Time
+}
+
+// RemoveAction represents the situation when an action is to be removed
+// from the cache, either because it has been removed from the database,
+// or because it is no longer pending or running.
+type RemoveAction struct {
+	ModelUUID string
+	Id        string
+}
+
 func copyStatusInfo(info status.StatusInfo) status.StatusInfo {
 	var cSince *time.Time
 	if info.Since != nil {
@@ -186,6 +186,10 @@ func (c *Controller) loop() error {
 				c.updateBranch(ch)
 			case RemoveBranch:
 				err = c.removeBranch(ch)
+			case ActionChange:
+				c.updateAction(ch)
+			case RemoveAction:
+				err = c.removeAction(ch)
 			}
 			if c.notify != nil {
 				c.notify(change)
@@ -404,6 +408,16 @@ func (c *Controller) removeBranch(ch RemoveBranch) error {
 	return errors.Trace(c.removeResident(ch.ModelUUID, func(m *Model) error { return m.removeBranch(ch) }))
 }
 
+// updateAction adds or updates the action in the specified model.
+func (c *Controller) updateAction(ch ActionChange) {
+	c.ensureModel(ch.ModelUUID).updateAction(ch, c.manager)
+}
+
+// removeAction removes the action from the cached model.
+func (c *Controller) removeAction(ch RemoveAction) error {
+	return errors.Trace(c.removeResident(ch.ModelUUID, func(m *Model) error { return m.removeAction(ch) }))
+}
+
 // removeResident uses the input removal function to remove a cache resident,
 // including cleaning up resources it was responsible for creating.
 // If the cache does not have the model loaded for the resident yet,
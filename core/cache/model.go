@@ -53,6 +53,7 @@ func newModel(config modelConfig) *Model {
 		units:         make(map[string]*Unit),
 		relations:     make(map[string]*Relation),
 		branches:      make(map[string]*Branch),
+		actions:       make(map[string]*Action),
 	}
 	return m
 }
@@ -82,6 +83,7 @@ type Model struct {
 	units        map[string]*Unit
 	relations    map[string]*Relation
 	branches     map[string]*Branch
+	actions      map[string]*Action
 
 	// lastSummaryPublish is here for testing purposes to ensure
 	// synchronisation between the test and the handling of the
@@ -123,6 +125,13 @@ func (m *Model) Name() string {
 	return m.details.Name
 }
 
+// MigrationMode returns the model's migration mode; empty if the
+// model is not being migrated, otherwise "exporting" or "importing".
+func (m *Model) MigrationMode() string {
+	defer m.doLocked()()
+	return m.details.MigrationMode
+}
+
 // Summary returns a copy of the current summary, and its hash.
 func (m *Model) Summary() (ModelSummary, string) {
 	defer m.doLocked()()
@@ -557,6 +566,47 @@ func (m *Model) removeBranch(ch RemoveBranch) error {
 	return nil
 }
 
+// Actions returns all pending and running actions in the model.
+func (m *Model) Actions() map[string]Action {
+	m.mu.Lock()
+
+	actions := make(map[string]Action, len(m.actions))
+	for id, a := range m.actions {
+		actions[id] = a.copy()
+	}
+
+	m.mu.Unlock()
+	return actions
+}
+
+// updateAction adds or updates the action in the model.
+func (m *Model) updateAction(ch ActionChange, rm *residentManager) {
+	m.mu.Lock()
+
+	action, found := m.actions[ch.Id]
+	if !found {
+		action = newAction(rm.new())
+		m.actions[ch.Id] = action
+	}
+	action.setDetails(ch)
+
+	m.mu.Unlock()
+}
+
+// removeAction removes the action from the model.
+func (m *Model) removeAction(ch RemoveAction) error {
+	defer m.doLocked()()
+
+	action, ok := m.actions[ch.Id]
+	if ok {
+		if err := action.evict(); err != nil {
+			return errors.Trace(err)
+		}
+		delete(m.actions, ch.Id)
+	}
+	return nil
+}
+
 func (m *Model) setDetails(details ModelChange) {
 	m.mu.Lock()
 
@@ -0,0 +1,232 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cache
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	modelMetricsNamespace = "juju_model"
+
+	modelLabel         = "model"
+	modelUUIDLabel     = "model_uuid"
+	applicationLabel   = "application"
+	migrationModeLabel = "mode"
+
+	// migrationModeNone is the migration mode label value for a
+	// model which is not being migrated.
+	migrationModeNone = "none"
+
+	actionPending = "pending"
+	actionRunning = "running"
+)
+
+var (
+	modelStatusLabelNames = []string{
+		modelLabel,
+		modelUUIDLabel,
+		statusLabel,
+	}
+
+	modelUnitStatusLabelNames = []string{
+		modelLabel,
+		modelUUIDLabel,
+		applicationLabel,
+		statusLabel,
+	}
+
+	modelMigrationLabelNames = []string{
+		modelLabel,
+		modelUUIDLabel,
+		migrationModeLabel,
+	}
+
+	migrationModes = []string{
+		migrationModeNone,
+		"exporting",
+		"importing",
+	}
+)
+
+// ModelCollector is a prometheus.Collector that collects metrics about
+// the status of the entities in a single cached model.
+type ModelCollector struct {
+	model *Model
+
+	unitWorkloadStatus    *prometheus.GaugeVec
+	unitAgentStatus       *prometheus.GaugeVec
+	machineAgentStatus    *prometheus.GaugeVec
+	machineInstanceStatus *prometheus.GaugeVec
+	relations             *prometheus.GaugeVec
+	actions               *prometheus.GaugeVec
+	migration             *prometheus.GaugeVec
+
+	// As with the Collector, the GaugeVecs are reset on each collect,
+	// so we need to ensure that we don't have overlapping collect calls.
+	mu sync.Mutex
+}
+
+// NewModelMetricsCollector returns a new ModelCollector for the model.
+func NewModelMetricsCollector(model *Model) *ModelCollector {
+	return &ModelCollector{
+		model: model,
+		unitWorkloadStatus: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: modelMetricsNamespace,
+				Name:      "unit_workload_status",
+				Help:      "Number of units in the model by application and workload status.",
+			},
+			modelUnitStatusLabelNames,
+		),
+		unitAgentStatus: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: modelMetricsNamespace,
+				Name:      "unit_agent_status",
+				Help:      "Number of units in the model by application and agent status.",
+			},
+			modelUnitStatusLabelNames,
+		),
+		machineAgentStatus: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: modelMetricsNamespace,
+				Name:      "machine_agent_status",
+				Help:      "Number of machines in the model by agent status.",
+			},
+			modelStatusLabelNames,
+		),
+		machineInstanceStatus: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: modelMetricsNamespace,
+				Name:      "machine_instance_status",
+				Help:      "Number of machines in the model by instance status.",
+			},
+			modelStatusLabelNames,
+		),
+		relations: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: modelMetricsNamespace,
+				Name:      "relations",
+				Help:      "Number of relations in the model.",
+			},
+			[]string{modelLabel, modelUUIDLabel},
+		),
+		actions: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: modelMetricsNamespace,
+				Name:      "actions",
+				Help:      "Number of pending and running actions in the model.",
+			},
+			modelStatusLabelNames,
+		),
+		migration: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: modelMetricsNamespace,
+				Name:      "migration_mode",
+				Help:      "Whether the model is in the migration mode; none, exporting or importing.",
+			},
+			modelMigrationLabelNames,
+		),
+	}
+}
+
+// Describe is part of the prometheus.Collector interface.
+func (c *ModelCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.unitWorkloadStatus.Describe(ch)
+	c.unitAgentStatus.Describe(ch)
+	c.machineAgentStatus.Describe(ch)
+	c.machineInstanceStatus.Describe(ch)
+	c.relations.Describe(ch)
+	c.actions.Describe(ch)
+	c.migration.Describe(ch)
+}
+
+// Collect is part of the prometheus.Collector interface.
+func (c *ModelCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.unitWorkloadStatus.Reset()
+	c.unitAgentStatus.Reset()
+	c.machineAgentStatus.Reset()
+	c.machineInstanceStatus.Reset()
+	c.relations.Reset()
+	c.actions.Reset()
+	c.migration.Reset()
+
+	c.updateMetrics()
+
+	c.unitWorkloadStatus.Collect(ch)
+	c.unitAgentStatus.Collect(ch)
+	c.machineAgentStatus.Collect(ch)
+	c.machineInstanceStatus.Collect(ch)
+	c.relations.Collect(ch)
+	c.actions.Collect(ch)
+	c.migration.Collect(ch)
+}
+
+func (c *ModelCollector) updateMetrics() {
+	model := c.model
+	model.mu.Lock()
+	defer model.mu.Unlock()
+
+	modelLabels := prometheus.Labels{
+		modelLabel:     model.details.Owner + "/" + model.details.Name,
+		modelUUIDLabel: model.details.ModelUUID,
+	}
+	with := func(extra prometheus.Labels) prometheus.Labels {
+		labels := make(prometheus.Labels, len(modelLabels)+len(extra))
+		for k, v := range modelLabels {
+			labels[k] = v
+		}
+		for k, v := range extra {
+			labels[k] = v
+		}
+		return labels
+	}
+
+	for _, unit := range model.units {
+		c.unitWorkloadStatus.With(with(prometheus.Labels{
+			applicationLabel: unit.details.Application,
+			statusLabel:      string(unit.details.WorkloadStatus.Status),
+		})).Inc()
+		c.unitAgentStatus.With(with(prometheus.Labels{
+			applicationLabel: unit.details.Application,
+			statusLabel:      string(unit.details.AgentStatus.Status),
+		})).Inc()
+	}
+	for _, machine := range model.machines {
+		c.machineAgentStatus.With(with(prometheus.Labels{
+			statusLabel: string(machine.details.AgentStatus.Status),
+		})).Inc()
+		c.machineInstanceStatus.With(with(prometheus.Labels{
+			statusLabel: string(machine.details.InstanceStatus.Status),
+		})).Inc()
+	}
+	c.relations.With(modelLabels).Set(float64(len(model.relations)))
+
+	// Always report both pending and running actions, so that they
+	// can be alerted on without the series coming and going.
+	for _, status := range []string{actionPending, actionRunning} {
+		c.actions.With(with(prometheus.Labels{statusLabel: status}))
+	}
+	for _, action := range model.actions {
+		c.actions.With(with(prometheus.Labels{
+			statusLabel: action.details.Status,
+		})).Inc()
+	}
+
+	mode := model.details.MigrationMode
+	if mode == "" {
+		mode = migrationModeNone
+	}
+	for _, m := range migrationModes {
+		gauge := c.migration.With(with(prometheus.Labels{migrationModeLabel: m}))
+		if m == mode {
+			gauge.Set(1)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cache_test
+
+import (
+	"bytes"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v2/workertest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/cache"
+	"github.com/juju/juju/core/status"
+)
+
+// The model metrics also hook into the ControllerSuite.
+
+func (s *ControllerSuite) TestCollectModelMetrics(c *gc.C) {
+	controller, events := s.New(c)
+
+	errorUnit := unitChange
+	errorUnit.Name = "application-name/1"
+	errorUnit.WorkloadStatus = status.StatusInfo{Status: status.Error}
+	exporting := modelChange
+	exporting.MigrationMode = "exporting"
+
+	s.ProcessChange(c, machineChange, events)
+	s.ProcessChange(c, unitChange, events)
+	s.ProcessChange(c, errorUnit, events)
+	s.ProcessChange(c, relationChange, events)
+	s.ProcessChange(c, cache.ActionChange{
+		ModelUUID: modelChange.ModelUUID,
+		Id:        "1",
+		Receiver:  unitChange.Name,
+		Name:      "backup",
+		Status:    "running",
+	}, events)
+	s.ProcessChange(c, exporting, events)
+
+	mod, err := controller.Model(modelChange.ModelUUID)
+	c.Assert(err, jc.ErrorIsNil)
+	collector := cache.NewModelMetricsCollector(mod)
+
+	expected := bytes.NewBuffer([]byte(`
+# HELP juju_model_actions Number of pending and running actions in the model.
+# TYPE juju_model_actions gauge
+juju_model_actions{model="model-owner/test-model",model_uuid="model-uuid",status="pending"} 0
+juju_model_actions{model="model-owner/test-model",model_uuid="model-uuid",status="running"} 1
+# HELP juju_model_machine_agent_status Number of machines in the model by agent status.
+# TYPE juju_model_machine_agent_status gauge
+juju_model_machine_agent_status{model="model-owner/test-model",model_uuid="model-uuid",status="active"} 1
+# HELP juju_model_migration_mode Whether the model is in the migration mode; none, exporting or importing.
+# TYPE juju_model_migration_mode gauge
+juju_model_migration_mode{mode="exporting",model="model-owner/test-model",model_uuid="model-uuid"} 1
+juju_model_migration_mode{mode="importing",model="model-owner/test-model",model_uuid="model-uuid"} 0
+juju_model_migration_mode{mode="none",model="model-owner/test-model",model_uuid="model-uuid"} 0
+# HELP juju_model_relations Number of relations in the model.
+# TYPE juju_model_relations gauge
+juju_model_relations{model="model-owner/test-model",model_uuid="model-uuid"} 1
+# HELP juju_model_unit_agent_status Number of units in the model by application and agent status.
+# TYPE juju_model_unit_agent_status gauge
+juju_model_unit_agent_status{application="application-name",model="model-owner/test-model",model_uuid="model-uuid",status="active"} 2
+# HELP juju_model_unit_workload_status Number of units in the model by application and workload status.
+# TYPE juju_model_unit_workload_status gauge
+juju_model_unit_workload_status{application="application-name",model="model-owner/test-model",model_uuid="model-uuid",status="active"} 1
+juju_model_unit_workload_status{application="application-name",model="model-owner/test-model",model_uuid="model-uuid",status="error"} 1
+		`[1:]))
+
+	err = testutil.CollectAndCompare(
+		collector, expected,
+		"juju_model_actions",
+		"juju_model_machine_agent_status",
+		"juju_model_migration_mode",
+		"juju_model_relations",
+		"juju_model_unit_agent_status",
+		"juju_model_unit_workload_status")
+	if !c.Check(err, jc.ErrorIsNil) {
+		c.Logf("\nerror:\n%v", err)
+	}
+
+	// Once the action completes, it is removed from the cache.
+	s.ProcessChange(c, cache.RemoveAction{ModelUUID: modelChange.ModelUUID, Id: "1"}, events)
+	c.Check(mod.Actions(), gc.HasLen, 0)
+
+	workertest.CleanKill(c, controller)
+}
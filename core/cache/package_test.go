@@ -87,7 +87,8 @@ func (s *BaseSuite) CaptureEvents(c *gc.C) <-chan interface{} {
 			MachineChange, RemoveMachine,
 			UnitChange, RemoveUnit,
 			RelationChange, RemoveRelation,
-			BranchChange, RemoveBranch:
+			BranchChange, RemoveBranch,
+			ActionChange, RemoveAction:
 			send = true
 		default:
 			// no-op
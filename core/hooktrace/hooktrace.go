@@ -0,0 +1,267 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package hooktrace records where the time goes when a unit runs its
+// hooks, actions and commands: waiting for the machine lock, running
+// hook tools and flushing the hook context.
+package hooktrace
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// MaxTraces is the number of recent traces kept by a Recorder.
+	MaxTraces = 10
+
+	// MaxToolCalls is the number of hook tool calls recorded in a
+	// single trace. Later calls are only counted.
+	MaxToolCalls = 50
+
+	// maxSummaryArgs is the number of arguments included in the
+	// summary of a hook tool call.
+	maxSummaryArgs = 8
+
+	// maxSummaryArgLen is the length at which arguments are truncated
+	// in the summary of a hook tool call.
+	maxSummaryArgLen = 32
+)
+
+// ToolCall records a single hook tool invocation.
+type ToolCall struct {
+	// Name is the name of the hook tool.
+	Name string `yaml:"name"`
+
+	// Args summarises the arguments the tool was called with. The
+	// values of key=value arguments are never recorded.
+	Args string `yaml:"args,omitempty"`
+
+	// Started is when the tool started running.
+	Started time.Time `yaml:"started"`
+
+	// Duration is how long the tool ran for.
+	Duration time.Duration `yaml:"duration"`
+
+	// Failed is true if the tool returned an error.
+	Failed bool `yaml:"failed,omitempty"`
+}
+
+// Trace records a single run of a hook, action or command.
+type Trace struct {
+	// Operation describes what was run, eg "run install hook".
+	Operation string `yaml:"operation"`
+
+	// Started is when the operation was started, before waiting for
+	// the machine lock.
+	Started time.Time `yaml:"started"`
+
+	// LockWait is how long was spent waiting for the machine lock.
+	LockWait time.Duration `yaml:"lock-wait"`
+
+	// Duration is how long the operation took, including the time
+	// spent waiting for the machine lock.
+	Duration time.Duration `yaml:"duration"`
+
+	// Flush is how long was spent flushing the hook context.
+	Flush time.Duration `yaml:"flush"`
+
+	// ToolCalls holds the first MaxToolCalls hook tools run.
+	ToolCalls []ToolCall `yaml:"tool-calls,omitempty"`
+
+	// DroppedToolCalls is the number of hook tools run once
+	// MaxToolCalls had been recorded.
+	DroppedToolCalls int `yaml:"dropped-tool-calls,omitempty"`
+
+	// Error holds the error the operation failed with, if any.
+	Error string `yaml:"error,omitempty"`
+}
+
+// Clock provides the time for a Recorder.
+type Clock interface {
+	Now() time.Time
+}
+
+// Recorder records a trace for each operation run, one at a time, and
+// keeps the most recent MaxTraces of them. A nil Recorder records
+// nothing.
+type Recorder struct {
+	clock Clock
+
+	mu      sync.Mutex
+	current *Trace
+	traces  []Trace
+}
+
+// NewRecorder returns a Recorder using the given clock.
+func NewRecorder(clock Clock) *Recorder {
+	return &Recorder{clock: clock}
+}
+
+// Start begins the trace of the named operation, before it waits for
+// the machine lock. Any unfinished trace is discarded.
+func (r *Recorder) Start(operation string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.current = &Trace{
+		Operation: operation,
+		Started:   r.clock.Now(),
+	}
+}
+
+// LockAcquired records that the current operation holds the machine
+// lock, or doesn't need it.
+func (r *Recorder) LockAcquired() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.current == nil {
+		return
+	}
+	r.current.LockWait = r.clock.Now().Sub(r.current.Started)
+}
+
+// ToolCall records a hook tool, started at the given time, that has
+// just finished running.
+func (r *Recorder) ToolCall(name string, args []string, started time.Time, err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.current == nil {
+		return
+	}
+	if len(r.current.ToolCalls) >= MaxToolCalls {
+		r.current.DroppedToolCalls++
+		return
+	}
+	r.current.ToolCalls = append(r.current.ToolCalls, ToolCall{
+		Name:     name,
+		Args:     SummarizeArgs(args),
+		Started:  started,
+		Duration: r.clock.Now().Sub(started),
+		Failed:   err != nil,
+	})
+}
+
+// Flushed records that the hook context, whose flush started at the
+// given time, has just been flushed.
+func (r *Recorder) Flushed(started time.Time) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.current == nil {
+		return
+	}
+	r.current.Flush += r.clock.Now().Sub(started)
+}
+
+// Finish completes the current trace, recording the error the
+// operation failed with, if any.
+func (r *Recorder) Finish(err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.current == nil {
+		return
+	}
+	trace := *r.current
+	r.current = nil
+	trace.Duration = r.clock.Now().Sub(trace.Started)
+	if err != nil {
+		trace.Error = err.Error()
+	}
+	r.traces = append(r.traces, trace)
+	if len(r.traces) > MaxTraces {
+		r.traces = r.traces[len(r.traces)-MaxTraces:]
+	}
+}
+
+// Restore replaces the recent traces with those given, typically the
+// traces recorded before the agent restarted.
+func (r *Recorder) Restore(traces []Trace) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(traces) > MaxTraces {
+		traces = traces[len(traces)-MaxTraces:]
+	}
+	r.traces = make([]Trace, len(traces))
+	copy(r.traces, traces)
+}
+
+// Traces returns the recent traces, oldest first.
+func (r *Recorder) Traces() []Trace {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	traces := make([]Trace, len(r.traces))
+	copy(traces, r.traces)
+	return traces
+}
+
+// SummarizeArgs returns a short, single line summary of the arguments
+// a hook tool was called with. As arguments may hold secrets, such as
+// relation settings, the values of key=value arguments are elided and
+// long arguments are truncated.
+func SummarizeArgs(args []string) string {
+	summary := make([]string, 0, len(args))
+	for i, arg := range args {
+		if i == maxSummaryArgs {
+			summary = append(summary, "...")
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			if i := strings.Index(arg, "="); i > 0 {
+				arg = arg[:i+1] + "..."
+			}
+		}
+		if len(arg) > maxSummaryArgLen {
+			arg = arg[:maxSummaryArgLen] + "..."
+		}
+		summary = append(summary, strings.Join(strings.Fields(arg), " "))
+	}
+	return strings.Join(summary, " ")
+}
+
+// Encode serialises traces to be stored in the unit's state.
+func Encode(traces []Trace) (string, error) {
+	if len(traces) == 0 {
+		return "", nil
+	}
+	data, err := yaml.Marshal(traces)
+	if err != nil {
+		return "", errors.Annotate(err, "encoding hook traces")
+	}
+	return string(data), nil
+}
+
+// Decode returns the traces serialised by Encode.
+func Decode(data string) ([]Trace, error) {
+	if data == "" {
+		return nil, nil
+	}
+	var traces []Trace
+	if err := yaml.Unmarshal([]byte(data), &traces); err != nil {
+		return nil, errors.Annotate(err, "decoding hook traces")
+	}
+	return traces, nil
+}
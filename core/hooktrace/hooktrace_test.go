@@ -0,0 +1,168 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package hooktrace_test
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/hooktrace"
+)
+
+type recorderSuite struct {
+	testing.IsolationSuite
+
+	clock    *testclock.Clock
+	recorder *hooktrace.Recorder
+}
+
+var _ = gc.Suite(&recorderSuite{})
+
+func (s *recorderSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Date(2021, 4, 1, 12, 0, 0, 0, time.UTC))
+	s.recorder = hooktrace.NewRecorder(s.clock)
+}
+
+func (s *recorderSuite) TestRecordTrace(c *gc.C) {
+	started := s.clock.Now()
+	s.recorder.Start("run install hook")
+	s.clock.Advance(3 * time.Second)
+	s.recorder.LockAcquired()
+
+	toolStarted := s.clock.Now()
+	s.clock.Advance(time.Second)
+	s.recorder.ToolCall("relation-set", []string{"-r", "db:1", "password=secret"}, toolStarted, nil)
+
+	flushStarted := s.clock.Now()
+	s.clock.Advance(2 * time.Second)
+	s.recorder.Flushed(flushStarted)
+	s.recorder.Finish(errors.New("boom"))
+
+	c.Assert(s.recorder.Traces(), jc.DeepEquals, []hooktrace.Trace{{
+		Operation: "run install hook",
+		Started:   started,
+		LockWait:  3 * time.Second,
+		Duration:  6 * time.Second,
+		Flush:     2 * time.Second,
+		ToolCalls: []hooktrace.ToolCall{{
+			Name:     "relation-set",
+			Args:     "-r db:1 password=...",
+			Started:  toolStarted,
+			Duration: time.Second,
+		}},
+		Error: "boom",
+	}})
+}
+
+func (s *recorderSuite) TestToolCallsLimited(c *gc.C) {
+	s.recorder.Start("run config-changed hook")
+	for i := 0; i < hooktrace.MaxToolCalls+3; i++ {
+		s.recorder.ToolCall("juju-log", nil, s.clock.Now(), errors.New("failed"))
+	}
+	s.recorder.Finish(nil)
+
+	traces := s.recorder.Traces()
+	c.Assert(traces, gc.HasLen, 1)
+	c.Check(traces[0].ToolCalls, gc.HasLen, hooktrace.MaxToolCalls)
+	c.Check(traces[0].ToolCalls[0].Failed, jc.IsTrue)
+	c.Check(traces[0].DroppedToolCalls, gc.Equals, 3)
+}
+
+func (s *recorderSuite) TestTracesLimited(c *gc.C) {
+	for i := 0; i < hooktrace.MaxTraces+2; i++ {
+		s.recorder.Start(fmt.Sprintf("op %d", i))
+		s.recorder.Finish(nil)
+	}
+	traces := s.recorder.Traces()
+	c.Assert(traces, gc.HasLen, hooktrace.MaxTraces)
+	c.Check(traces[0].Operation, gc.Equals, "op 2")
+	c.Check(traces[hooktrace.MaxTraces-1].Operation, gc.Equals, fmt.Sprintf("op %d", hooktrace.MaxTraces+1))
+}
+
+func (s *recorderSuite) TestNotStarted(c *gc.C) {
+	s.recorder.LockAcquired()
+	s.recorder.ToolCall("juju-log", nil, s.clock.Now(), nil)
+	s.recorder.Flushed(s.clock.Now())
+	s.recorder.Finish(nil)
+	c.Assert(s.recorder.Traces(), gc.HasLen, 0)
+}
+
+func (s *recorderSuite) TestNilRecorder(c *gc.C) {
+	var recorder *hooktrace.Recorder
+	recorder.Start("run install hook")
+	recorder.LockAcquired()
+	recorder.ToolCall("juju-log", nil, s.clock.Now(), nil)
+	recorder.Finish(nil)
+	c.Assert(recorder.Traces(), gc.HasLen, 0)
+}
+
+func (s *recorderSuite) TestRestore(c *gc.C) {
+	s.recorder.Restore([]hooktrace.Trace{{Operation: "run install hook"}})
+	s.recorder.Start("run start hook")
+	s.recorder.Finish(nil)
+
+	traces := s.recorder.Traces()
+	c.Assert(traces, gc.HasLen, 2)
+	c.Check(traces[0].Operation, gc.Equals, "run install hook")
+	c.Check(traces[1].Operation, gc.Equals, "run start hook")
+}
+
+func (s *recorderSuite) TestSummarizeArgs(c *gc.C) {
+	for i, test := range []struct {
+		args     []string
+		expected string
+	}{{
+		args:     nil,
+		expected: "",
+	}, {
+		args:     []string{"--format=json", "-r", "db:1", "password=secret"},
+		expected: "--format=json -r db:1 password=...",
+	}, {
+		args:     []string{"a very long status message that goes on and on"},
+		expected: "a very long status message that ...",
+	}, {
+		args:     []string{"multi\nline"},
+		expected: "multi line",
+	}, {
+		args:     []string{"1", "2", "3", "4", "5", "6", "7", "8", "9"},
+		expected: "1 2 3 4 5 6 7 8 ...",
+	}} {
+		c.Logf("test %d: %q", i, test.args)
+		c.Check(hooktrace.SummarizeArgs(test.args), gc.Equals, test.expected)
+	}
+}
+
+func (s *recorderSuite) TestEncodeDecode(c *gc.C) {
+	traces := []hooktrace.Trace{{
+		Operation: "run install hook",
+		Started:   s.clock.Now(),
+		LockWait:  time.Second,
+		Duration:  time.Minute,
+		ToolCalls: []hooktrace.ToolCall{{
+			Name:     "status-set",
+			Args:     "active",
+			Started:  s.clock.Now().Add(time.Second),
+			Duration: time.Millisecond,
+		}},
+	}}
+	data, err := hooktrace.Encode(traces)
+	c.Assert(err, jc.ErrorIsNil)
+	decoded, err := hooktrace.Decode(data)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(decoded, jc.DeepEquals, traces)
+
+	data, err = hooktrace.Encode(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, gc.Equals, "")
+	decoded, err = hooktrace.Decode("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(decoded, gc.HasLen, 0)
+}
@@ -566,6 +566,7 @@ type ModelInfo struct {
 	Status          StatusInfo
 	Constraints     constraints.Value
 	SLA             ModelSLAInfo
+	MigrationMode   string
 
 	UserPermissions map[string]permission.Access
 }
@@ -0,0 +1,134 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package quota
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+)
+
+// Resource identifies a kind of model resource that can be limited by
+// a quota.
+type Resource string
+
+const (
+	// Machines is the number of machines.
+	Machines Resource = "machines"
+
+	// Units is the number of units.
+	Units Resource = "units"
+
+	// Applications is the number of applications.
+	Applications Resource = "applications"
+
+	// Storage is the total size of the storage instances, in MiB.
+	Storage Resource = "storage"
+
+	// Pods is the number of units in CAAS models, each of which
+	// runs in its own pod.
+	Pods Resource = "pods"
+)
+
+// AllResources lists the resources that can be limited, in the order
+// they are reported.
+var AllResources = []Resource{
+	Machines,
+	Units,
+	Applications,
+	Storage,
+	Pods,
+}
+
+// Validate returns an error if the resource can't be limited.
+func (r Resource) Validate() error {
+	for _, known := range AllResources {
+		if r == known {
+			return nil
+		}
+	}
+	return errors.NotValidf("quota resource %q", string(r))
+}
+
+// Format returns the amount of the resource as a string.
+func (r Resource) Format(amount uint64) string {
+	if r == Storage {
+		return fmt.Sprintf("%dMiB", amount)
+	}
+	return fmt.Sprint(amount)
+}
+
+// Limits holds the most of each resource allowed. Resources without an
+// entry are unlimited.
+type Limits map[Resource]uint64
+
+// Validate returns an error if any of the limited resources is unknown.
+func (l Limits) Validate() error {
+	for r := range l {
+		if err := r.Validate(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// Usage holds the amount of each resource used.
+type Usage map[Resource]uint64
+
+var _ Checker = (*ResourceLimitChecker)(nil)
+
+// ResourceLimitChecker can be used to verify that adding one or more
+// amounts of a resource to its current usage does not exceed a limit.
+type ResourceLimitChecker struct {
+	resource Resource
+	limit    uint64
+	total    uint64
+	lastErr  error
+}
+
+// NewResourceLimitChecker returns a ResourceLimitChecker for a resource
+// with the given limit and current usage.
+func NewResourceLimitChecker(resource Resource, limit, usage uint64) *ResourceLimitChecker {
+	return &ResourceLimitChecker{
+		resource: resource,
+		limit:    limit,
+		total:    usage,
+	}
+}
+
+// Check adds v, which must be an integer amount of the resource, to
+// the total being checked.
+func (c *ResourceLimitChecker) Check(v interface{}) {
+	if c.lastErr != nil {
+		return
+	}
+
+	var amount uint64
+	switch v := v.(type) {
+	case int:
+		if v < 0 {
+			c.lastErr = errors.NotValidf("negative %s amount", c.resource)
+			return
+		}
+		amount = uint64(v)
+	case uint64:
+		amount = v
+	default:
+		c.lastErr = errors.NotImplementedf("%s quota check for %T values", c.resource, v)
+		return
+	}
+
+	c.total += amount
+	if c.total > c.limit {
+		c.lastErr = errors.QuotaLimitExceededf(
+			"%s quota (%s) exceeded", c.resource, c.resource.Format(c.limit),
+		)
+	}
+}
+
+// Outcome returns an error satisfying errors.IsQuotaLimitExceeded if
+// the checked amounts exceed the limit.
+func (c *ResourceLimitChecker) Outcome() error {
+	return c.lastErr
+}
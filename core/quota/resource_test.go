@@ -0,0 +1,80 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package quota_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/quota"
+)
+
+var _ = gc.Suite(&ResourceLimitCheckerSuite{})
+
+type ResourceLimitCheckerSuite struct {
+}
+
+func (s *ResourceLimitCheckerSuite) TestSuccessfulCheck(c *gc.C) {
+	chk := quota.NewResourceLimitChecker(quota.Machines, 5, 3)
+	chk.Check(1)
+	chk.Check(uint64(1))
+
+	err := chk.Outcome()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ResourceLimitCheckerSuite) TestExceedLimit(c *gc.C) {
+	chk := quota.NewResourceLimitChecker(quota.Units, 5, 4)
+	chk.Check(2)
+
+	err := chk.Outcome()
+	c.Assert(err, jc.Satisfies, errors.IsQuotaLimitExceeded)
+	c.Assert(err, gc.ErrorMatches, `units quota \(5\) exceeded`)
+}
+
+func (s *ResourceLimitCheckerSuite) TestExceedStorageLimit(c *gc.C) {
+	chk := quota.NewResourceLimitChecker(quota.Storage, 1024, 0)
+	chk.Check(uint64(1025))
+
+	err := chk.Outcome()
+	c.Assert(err, jc.Satisfies, errors.IsQuotaLimitExceeded)
+	c.Assert(err, gc.ErrorMatches, `storage quota \(1024MiB\) exceeded`)
+}
+
+func (s *ResourceLimitCheckerSuite) TestZeroLimit(c *gc.C) {
+	chk := quota.NewResourceLimitChecker(quota.Applications, 0, 0)
+	chk.Check(0)
+	c.Assert(chk.Outcome(), jc.ErrorIsNil)
+
+	chk.Check(1)
+	c.Assert(chk.Outcome(), jc.Satisfies, errors.IsQuotaLimitExceeded)
+}
+
+func (s *ResourceLimitCheckerSuite) TestInvalidValue(c *gc.C) {
+	chk := quota.NewResourceLimitChecker(quota.Pods, 10, 0)
+	chk.Check("lots")
+	c.Assert(chk.Outcome(), gc.ErrorMatches, `pods quota check for string values not implemented`)
+
+	chk = quota.NewResourceLimitChecker(quota.Pods, 10, 0)
+	chk.Check(-1)
+	c.Assert(chk.Outcome(), gc.ErrorMatches, `negative pods amount not valid`)
+}
+
+var _ = gc.Suite(&ResourceSuite{})
+
+type ResourceSuite struct {
+}
+
+func (s *ResourceSuite) TestValidate(c *gc.C) {
+	for _, r := range quota.AllResources {
+		c.Check(r.Validate(), jc.ErrorIsNil)
+	}
+	c.Check(quota.Resource("cpus").Validate(), gc.ErrorMatches, `quota resource "cpus" not valid`)
+}
+
+func (s *ResourceSuite) TestLimitsValidate(c *gc.C) {
+	c.Check(quota.Limits{quota.Machines: 10, quota.Storage: 0}.Validate(), jc.ErrorIsNil)
+	c.Check(quota.Limits{"cpus": 1}.Validate(), jc.Satisfies, errors.IsNotValid)
+}
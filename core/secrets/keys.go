@@ -0,0 +1,147 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets
+
+import (
+	"crypto/rand"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/v2"
+)
+
+const (
+	// keysDir is the name of the directory, in a controller machine's
+	// data dir, holding the secrets keys.
+	keysDir = "secrets"
+
+	// ControllerKey is the name of the key that the values of secrets
+	// stored in the controller database are encrypted with.
+	ControllerKey = "controller.key"
+
+	// FileBackendKey is the name of the key that the values stored by
+	// the file secret backend are encrypted with. It's kept here,
+	// rather than with the values, so that a copy of the backend's
+	// directory can't be read.
+	FileBackendKey = "file-backend.key"
+
+	// KeySize is the size in bytes of the AES-256 secrets keys.
+	KeySize = 32
+)
+
+// keyNames holds the names of all the secrets keys a controller needs.
+var keyNames = []string{
+	ControllerKey,
+	FileBackendKey,
+}
+
+// KeysDir returns the directory, in the data dir of a controller
+// machine, holding the keys that secret values are encrypted with.
+// The keys are the same on all of a controller's machines, but they
+// are never stored in the controller database or included in backups,
+// so that neither can be used to read the values.
+func KeysDir(dataDir string) string {
+	return filepath.Join(dataDir, keysDir)
+}
+
+// ReadKey returns the named key from the keys dir. It returns an error
+// satisfying errors.IsNotFound if there is no such key.
+func ReadKey(dir, name string) ([]byte, error) {
+	if dir == "" {
+		return nil, errors.NotFoundf("secrets keys dir")
+	}
+	path := filepath.Join(dir, name)
+	key, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, errors.NotFoundf("secrets key %q", name)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "reading secrets key %q", name)
+	}
+	if len(key) != KeySize {
+		return nil, errors.Errorf("secrets key %q has unexpected size %d", name, len(key))
+	}
+	return key, nil
+}
+
+// ReadKeys returns all the keys in the keys dir, by name.
+func ReadKeys(dir string) (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	for _, name := range keyNames {
+		key, err := ReadKey(dir, name)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		keys[name] = key
+	}
+	return keys, nil
+}
+
+// WriteKeys writes the keys, by name, into the keys dir, creating it
+// if necessary. Existing keys are never replaced, as that would make
+// the values encrypted with them unreadable.
+func WriteKeys(dir string, keys map[string][]byte) error {
+	for name, key := range keys {
+		if !isKeyName(name) {
+			return errors.NotValidf("secrets key name %q", name)
+		}
+		if len(key) != KeySize {
+			return errors.NotValidf("secrets key %q with size %d", name, len(key))
+		}
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return errors.Annotate(err, "creating secrets keys dir")
+	}
+	for name, key := range keys {
+		existing, err := ReadKey(dir, name)
+		if err == nil {
+			if string(existing) != string(key) {
+				return errors.Errorf("secrets key %q already exists with a different value", name)
+			}
+			continue
+		} else if !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+		if err := utils.AtomicWriteFile(filepath.Join(dir, name), key, 0600); err != nil {
+			return errors.Annotatef(err, "writing secrets key %q", name)
+		}
+	}
+	return nil
+}
+
+// EnsureKeys generates any of the keys a controller needs that are
+// missing from the keys dir. It's called when the controller is
+// bootstrapped; controller machines added later are sent the keys
+// by an existing one.
+func EnsureKeys(dir string) error {
+	existing, err := ReadKeys(dir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	keys := make(map[string][]byte)
+	for _, name := range keyNames {
+		if _, ok := existing[name]; ok {
+			continue
+		}
+		key := make([]byte, KeySize)
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return errors.Annotatef(err, "generating secrets key %q", name)
+		}
+		keys[name] = key
+	}
+	return errors.Trace(WriteKeys(dir, keys))
+}
+
+func isKeyName(name string) bool {
+	for _, n := range keyNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
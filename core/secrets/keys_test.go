@@ -0,0 +1,114 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/secrets"
+)
+
+type KeysSuite struct {
+	testing.IsolationSuite
+	dir string
+}
+
+var _ = gc.Suite(&KeysSuite{})
+
+func (s *KeysSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.dir = secrets.KeysDir(c.MkDir())
+}
+
+func (s *KeysSuite) TestKeysDir(c *gc.C) {
+	c.Assert(secrets.KeysDir("/var/lib/juju"), gc.Equals, "/var/lib/juju/secrets")
+}
+
+func (s *KeysSuite) TestEnsureKeys(c *gc.C) {
+	err := secrets.EnsureKeys(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	key, err := secrets.ReadKey(s.dir, secrets.ControllerKey)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(key, gc.HasLen, secrets.KeySize)
+	fileKey, err := secrets.ReadKey(s.dir, secrets.FileBackendKey)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fileKey, gc.HasLen, secrets.KeySize)
+	c.Check(fileKey, gc.Not(jc.DeepEquals), key)
+
+	info, err := os.Stat(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.Mode().Perm(), gc.Equals, os.FileMode(0700))
+	info, err = os.Stat(filepath.Join(s.dir, secrets.ControllerKey))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(info.Mode().Perm(), gc.Equals, os.FileMode(0600))
+
+	// Existing keys are kept.
+	err = secrets.EnsureKeys(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	again, err := secrets.ReadKey(s.dir, secrets.ControllerKey)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(again, jc.DeepEquals, key)
+}
+
+func (s *KeysSuite) TestReadKeyNotFound(c *gc.C) {
+	_, err := secrets.ReadKey(s.dir, secrets.ControllerKey)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = secrets.ReadKey("", secrets.ControllerKey)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *KeysSuite) TestReadKeyBadSize(c *gc.C) {
+	err := os.MkdirAll(s.dir, 0700)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(s.dir, secrets.ControllerKey), []byte("short"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = secrets.ReadKey(s.dir, secrets.ControllerKey)
+	c.Assert(err, gc.ErrorMatches, `secrets key "controller.key" has unexpected size 5`)
+}
+
+func (s *KeysSuite) TestReadWriteKeys(c *gc.C) {
+	keys, err := secrets.ReadKeys(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(keys, gc.HasLen, 0)
+
+	key := bytes.Repeat([]byte{1}, secrets.KeySize)
+	err = secrets.WriteKeys(s.dir, map[string][]byte{secrets.ControllerKey: key})
+	c.Assert(err, jc.ErrorIsNil)
+
+	keys, err = secrets.ReadKeys(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(keys, jc.DeepEquals, map[string][]byte{secrets.ControllerKey: key})
+
+	// Writing the same key again is fine.
+	err = secrets.WriteKeys(s.dir, keys)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *KeysSuite) TestWriteKeysWontReplace(c *gc.C) {
+	err := secrets.EnsureKeys(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	other := bytes.Repeat([]byte{1}, secrets.KeySize)
+	err = secrets.WriteKeys(s.dir, map[string][]byte{secrets.ControllerKey: other})
+	c.Assert(err, gc.ErrorMatches, `secrets key "controller.key" already exists with a different value`)
+}
+
+func (s *KeysSuite) TestWriteKeysInvalid(c *gc.C) {
+	err := secrets.WriteKeys(s.dir, map[string][]byte{"../escape": make([]byte, secrets.KeySize)})
+	c.Assert(err, gc.ErrorMatches, `secrets key name "../escape" not valid`)
+
+	err = secrets.WriteKeys(s.dir, map[string][]byte{secrets.ControllerKey: []byte("short")})
+	c.Assert(err, gc.ErrorMatches, `secrets key "controller.key" with size 5 not valid`)
+}
@@ -0,0 +1,142 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package secrets holds the secrets charms store in the controller,
+// rather than passing them through relation or leader settings, and
+// the policies for rotating them.
+package secrets
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/utils/v2"
+)
+
+// uriScheme is the scheme of secret URIs.
+const uriScheme = "secret"
+
+// URI identifies a secret.
+type URI struct {
+	// ID is the unique id of the secret.
+	ID string
+}
+
+// NewURI returns the URI of a new secret.
+func NewURI() *URI {
+	return &URI{ID: utils.MustNewUUID().String()}
+}
+
+// ParseURI parses a secret URI of the form "secret:<id>".
+func ParseURI(str string) (*URI, error) {
+	id := strings.TrimPrefix(str, uriScheme+":")
+	if id == str || !utils.IsValidUUIDString(id) {
+		return nil, errors.NotValidf("secret URI %q", str)
+	}
+	return &URI{ID: id}, nil
+}
+
+// String returns the URI in the form parsed by ParseURI.
+func (u *URI) String() string {
+	return fmt.Sprintf("%s:%s", uriScheme, u.ID)
+}
+
+// RotatePolicy defines how often a secret should be rotated by its
+// owner.
+type RotatePolicy string
+
+const (
+	RotateNever     RotatePolicy = "never"
+	RotateHourly    RotatePolicy = "hourly"
+	RotateDaily     RotatePolicy = "daily"
+	RotateWeekly    RotatePolicy = "weekly"
+	RotateMonthly   RotatePolicy = "monthly"
+	RotateQuarterly RotatePolicy = "quarterly"
+	RotateYearly    RotatePolicy = "yearly"
+)
+
+var rotateIntervals = map[RotatePolicy]time.Duration{
+	RotateHourly:    time.Hour,
+	RotateDaily:     24 * time.Hour,
+	RotateWeekly:    7 * 24 * time.Hour,
+	RotateMonthly:   30 * 24 * time.Hour,
+	RotateQuarterly: 90 * 24 * time.Hour,
+	RotateYearly:    365 * 24 * time.Hour,
+}
+
+// IsValid reports whether the policy is known. The empty policy is
+// taken to be RotateNever.
+func (p RotatePolicy) IsValid() bool {
+	if p == "" || p == RotateNever {
+		return true
+	}
+	_, ok := rotateIntervals[p]
+	return ok
+}
+
+// NextRotateTime returns when a secret last rotated at the given time
+// should next be rotated, or nil if it should never be rotated.
+func (p RotatePolicy) NextRotateTime(lastRotated time.Time) *time.Time {
+	interval, ok := rotateIntervals[p]
+	if !ok {
+		return nil
+	}
+	next := lastRotated.Add(interval)
+	return &next
+}
+
+// SecretData holds the key/value pairs of a revision of a secret.
+type SecretData map[string]string
+
+var keyRegexp = regexp.MustCompile(`^[a-z](?:-?[a-z0-9])*$`)
+
+// Validate returns an error if the data is empty, or has a key that
+// isn't lower case letters and digits, separated by hyphens.
+func (d SecretData) Validate() error {
+	if len(d) == 0 {
+		return errors.NotValidf("empty secret")
+	}
+	for key := range d {
+		if !keyRegexp.MatchString(key) {
+			return errors.NotValidf("secret key %q", key)
+		}
+	}
+	return nil
+}
+
+// SecretMetadata describes a secret, and its latest revision.
+type SecretMetadata struct {
+	// URI identifies the secret.
+	URI *URI
+
+	// OwnerTag is the tag of the application or unit that owns the
+	// secret, and so may update it and grant access to it.
+	OwnerTag names.Tag
+
+	// Description describes the secret, for the owner's benefit.
+	Description string
+
+	// RotatePolicy is how often the secret should be rotated.
+	RotatePolicy RotatePolicy
+
+	// NextRotateTime is when the secret should next be rotated, or nil
+	// if it should never be.
+	NextRotateTime *time.Time
+
+	// ExpireTime is when the secret expires, or nil if it doesn't.
+	ExpireTime *time.Time
+
+	// LatestRevision is the revision of the secret's latest value.
+	// Revisions count up from 1.
+	LatestRevision int
+
+	// CreateTime is when the secret was created.
+	CreateTime time.Time
+
+	// UpdateTime is when the secret was last updated.
+	UpdateTime time.Time
+}
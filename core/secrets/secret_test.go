@@ -0,0 +1,77 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secrets_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/secrets"
+)
+
+type SecretSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&SecretSuite{})
+
+func (s *SecretSuite) TestURIRoundTrip(c *gc.C) {
+	uri := secrets.NewURI()
+	parsed, err := secrets.ParseURI(uri.String())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(parsed, jc.DeepEquals, uri)
+}
+
+func (s *SecretSuite) TestParseURI(c *gc.C) {
+	uri, err := secrets.ParseURI("secret:0b8c6e39-8c1a-4b6f-8f0a-2f5c3e1d2a4b")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uri.ID, gc.Equals, "0b8c6e39-8c1a-4b6f-8f0a-2f5c3e1d2a4b")
+}
+
+func (s *SecretSuite) TestParseURIInvalid(c *gc.C) {
+	for _, str := range []string{
+		"",
+		"0b8c6e39-8c1a-4b6f-8f0a-2f5c3e1d2a4b",
+		"secret:",
+		"secret:password",
+		"vault:0b8c6e39-8c1a-4b6f-8f0a-2f5c3e1d2a4b",
+	} {
+		_, err := secrets.ParseURI(str)
+		c.Check(err, gc.ErrorMatches, `secret URI ".*" not valid`, gc.Commentf("%q", str))
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+func (s *SecretSuite) TestRotatePolicyIsValid(c *gc.C) {
+	for _, policy := range []secrets.RotatePolicy{
+		"", secrets.RotateNever, secrets.RotateHourly, secrets.RotateDaily,
+		secrets.RotateWeekly, secrets.RotateMonthly, secrets.RotateQuarterly,
+		secrets.RotateYearly,
+	} {
+		c.Check(policy.IsValid(), jc.IsTrue, gc.Commentf("%q", policy))
+	}
+	c.Check(secrets.RotatePolicy("fortnightly").IsValid(), jc.IsFalse)
+}
+
+func (s *SecretSuite) TestNextRotateTime(c *gc.C) {
+	now := time.Date(2021, 5, 6, 7, 0, 0, 0, time.UTC)
+	c.Check(secrets.RotateNever.NextRotateTime(now), gc.IsNil)
+	c.Check(secrets.RotatePolicy("").NextRotateTime(now), gc.IsNil)
+	next := secrets.RotateDaily.NextRotateTime(now)
+	c.Assert(next, gc.NotNil)
+	c.Check(*next, gc.Equals, now.Add(24*time.Hour))
+}
+
+func (s *SecretSuite) TestSecretDataValidate(c *gc.C) {
+	c.Check(secrets.SecretData{"password": "x", "api-key2": "y"}.Validate(), jc.ErrorIsNil)
+	c.Check(secrets.SecretData{}.Validate(), gc.ErrorMatches, "empty secret not valid")
+	for _, key := range []string{"Password", "api--key", "api-", "2fa", "pass_word", ""} {
+		err := secrets.SecretData{key: "x"}.Validate()
+		c.Check(err, gc.ErrorMatches, `secret key ".*" not valid`, gc.Commentf("%q", key))
+	}
+}
@@ -94,6 +94,8 @@ const (
 	KindContainerInstance HistoryKind = "container"
 	// KindContainer represents an entry for a container agent.
 	KindContainer HistoryKind = "juju-container"
+	// KindApplication represents an entry for an application.
+	KindApplication HistoryKind = "application"
 )
 
 // String returns a string representation of the HistoryKind.
@@ -106,7 +108,8 @@ func (k HistoryKind) Valid() bool {
 	switch k {
 	case KindUnit, KindUnitAgent, KindWorkload,
 		KindMachineInstance, KindMachine,
-		KindContainerInstance, KindContainer:
+		KindContainerInstance, KindContainer,
+		KindApplication:
 		return true
 	}
 	return false
@@ -122,5 +125,6 @@ func AllHistoryKind() map[HistoryKind]string {
 		KindMachine:           "status of the agent that is managing a machine",
 		KindContainerInstance: "statuses from the agent that is managing containers",
 		KindContainer:         "statuses from the containers only and not their host machines",
+		KindApplication:       "statuses for specified application",
 	}
 }
@@ -0,0 +1,198 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package webhook holds the events that webhooks can be notified of,
+// and the rendering and signing of the payloads sent for them.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// SignatureHeader is the HTTP header holding the signature of the
+// payload, when the webhook has a secret.
+const SignatureHeader = "X-Juju-Signature"
+
+// EventKind identifies a kind of model event.
+type EventKind string
+
+const (
+	// UnitError is sent when a unit's workload or agent enters the
+	// error status.
+	UnitError EventKind = "unit-error"
+
+	// UnitBlocked is sent when a unit's workload enters the blocked
+	// status.
+	UnitBlocked EventKind = "unit-blocked"
+
+	// MachineDown is sent when a machine's agent goes down.
+	MachineDown EventKind = "machine-down"
+
+	// ActionFailed is sent when an action fails.
+	ActionFailed EventKind = "action-failed"
+
+	// ApplicationDeployed is sent when an application is deployed.
+	ApplicationDeployed EventKind = "application-deployed"
+
+	// ApplicationRemoved is sent when an application is removed.
+	ApplicationRemoved EventKind = "application-removed"
+)
+
+// AllEventKinds holds all of the kinds of events webhooks can be
+// notified of.
+var AllEventKinds = []EventKind{
+	UnitError,
+	UnitBlocked,
+	MachineDown,
+	ActionFailed,
+	ApplicationDeployed,
+	ApplicationRemoved,
+}
+
+// Validate returns an error if the event kind is not known.
+func (k EventKind) Validate() error {
+	for _, kind := range AllEventKinds {
+		if k == kind {
+			return nil
+		}
+	}
+	return errors.NotValidf("webhook event %q", string(k))
+}
+
+// Event describes something which happened in a model.
+type Event struct {
+	Kind      EventKind `json:"event"`
+	ModelUUID string    `json:"model-uuid"`
+	Model     string    `json:"model"`
+	Entity    string    `json:"entity"`
+	Status    string    `json:"status,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Webhook describes a URL which is notified of events in a model.
+type Webhook struct {
+	Id  string
+	URL string
+
+	// Events holds the kinds of events the webhook is notified of; all
+	// of them if empty.
+	Events []EventKind
+
+	// Secret, if set, is used to sign the payloads.
+	Secret string
+
+	// Template, if set, is the text/template used to render the
+	// payloads; otherwise the event is sent as JSON.
+	Template string
+}
+
+// Validate returns an error if the webhook is not valid.
+func (w Webhook) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil {
+		return errors.NotValidf("webhook URL %q", w.URL)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return errors.NotValidf("webhook URL %q, expected an http or https URL,", w.URL)
+	}
+	for _, kind := range w.Events {
+		if err := kind.Validate(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if w.Template != "" {
+		if _, err := ParseTemplate(w.Template); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// Wants reports whether the webhook is notified of the event kind.
+func (w Webhook) Wants(kind EventKind) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, k := range w.Events {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// templateFuncs holds the functions available to payload templates.
+var templateFuncs = template.FuncMap{
+	// json renders a value as JSON, so that strings are quoted
+	// and escaped.
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// ParseTemplate parses a payload template, and checks that it renders
+// the events as JSON.
+func ParseTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("payload").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, errors.Annotate(err, "parsing payload template")
+	}
+	_, err = render(tmpl, Event{
+		Kind:      UnitError,
+		ModelUUID: "deadbeef-0bad-400d-8000-4b1d0d06f00d",
+		Model:     "admin/default",
+		Entity:    "mysql/0",
+		Status:    "error",
+		Message:   `hook failed: "install"`,
+		Timestamp: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return tmpl, nil
+}
+
+// Payload returns the payload sent to the webhook for the event.
+func (w Webhook) Payload(ev Event) ([]byte, error) {
+	if w.Template == "" {
+		data, err := json.Marshal(ev)
+		return data, errors.Trace(err)
+	}
+	tmpl, err := ParseTemplate(w.Template)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return render(tmpl, ev)
+}
+
+func render(tmpl *template.Template, ev Event) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, ev); err != nil {
+		return nil, errors.Annotate(err, "rendering payload template")
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, errors.NotValidf("payload template rendering %q as JSON", strings.TrimSpace(buf.String()))
+	}
+	return buf.Bytes(), nil
+}
+
+// Sign returns the signature of the payload, for the SignatureHeader
+// header, using the secret: the hex encoded HMAC-SHA256 of the payload,
+// prefixed with "sha256=".
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
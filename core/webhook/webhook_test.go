@@ -0,0 +1,105 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhook_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/webhook"
+)
+
+type WebhookSuite struct{}
+
+var _ = gc.Suite(&WebhookSuite{})
+
+var event = webhook.Event{
+	Kind:      webhook.UnitError,
+	ModelUUID: "model-uuid",
+	Model:     "admin/default",
+	Entity:    "mysql/0",
+	Status:    "error",
+	Message:   `hook failed: "install"`,
+	Timestamp: time.Date(2021, 6, 1, 2, 3, 4, 0, time.UTC),
+}
+
+func (s *WebhookSuite) TestValidate(c *gc.C) {
+	for _, kind := range webhook.AllEventKinds {
+		c.Check(kind.Validate(), jc.ErrorIsNil)
+	}
+	c.Check(webhook.EventKind("unit-happy").Validate(), gc.ErrorMatches, `webhook event "unit-happy" not valid`)
+}
+
+func (s *WebhookSuite) TestValidateWebhook(c *gc.C) {
+	for i, test := range []struct {
+		hook     webhook.Webhook
+		errMatch string
+	}{{
+		hook: webhook.Webhook{URL: "https://chat.example.com/hooks/1", Events: []webhook.EventKind{webhook.UnitError}},
+	}, {
+		hook:     webhook.Webhook{URL: "ftp://chat.example.com/hooks/1"},
+		errMatch: `webhook URL "ftp://chat.example.com/hooks/1", expected an http or https URL, not valid`,
+	}, {
+		hook:     webhook.Webhook{URL: "chat.example.com"},
+		errMatch: `webhook URL "chat.example.com", expected an http or https URL, not valid`,
+	}, {
+		hook:     webhook.Webhook{URL: "http://chat.example.com", Events: []webhook.EventKind{"unit-happy"}},
+		errMatch: `webhook event "unit-happy" not valid`,
+	}, {
+		hook:     webhook.Webhook{URL: "http://chat.example.com", Template: "{{"},
+		errMatch: `parsing payload template: .*`,
+	}} {
+		c.Logf("test %d", i)
+		err := test.hook.Validate()
+		if test.errMatch == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.errMatch)
+		}
+	}
+}
+
+func (s *WebhookSuite) TestWants(c *gc.C) {
+	all := webhook.Webhook{}
+	c.Check(all.Wants(webhook.MachineDown), jc.IsTrue)
+
+	some := webhook.Webhook{Events: []webhook.EventKind{webhook.UnitError, webhook.ActionFailed}}
+	c.Check(some.Wants(webhook.ActionFailed), jc.IsTrue)
+	c.Check(some.Wants(webhook.MachineDown), jc.IsFalse)
+}
+
+func (s *WebhookSuite) TestDefaultPayload(c *gc.C) {
+	payload, err := webhook.Webhook{}.Payload(event)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(payload), gc.Equals, `{"event":"unit-error","model-uuid":"model-uuid","model":"admin/default",`+
+		`"entity":"mysql/0","status":"error","message":"hook failed: \"install\"","timestamp":"2021-06-01T02:03:04Z"}`)
+}
+
+func (s *WebhookSuite) TestTemplatePayload(c *gc.C) {
+	hook := webhook.Webhook{
+		Template: `{"text": {{json (printf "%s is in %s: %s" .Entity .Status .Message)}}}`,
+	}
+	payload, err := hook.Payload(event)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(payload), gc.Equals, `{"text": "mysql/0 is in error: hook failed: \"install\""}`)
+}
+
+func (s *WebhookSuite) TestParseTemplateErrors(c *gc.C) {
+	_, err := webhook.ParseTemplate(`{"text": {{.Entity}`)
+	c.Check(err, gc.ErrorMatches, `parsing payload template: .*`)
+
+	_, err = webhook.ParseTemplate(`{"text": {{.Nope}}}`)
+	c.Check(err, gc.ErrorMatches, `rendering payload template: .*`)
+
+	_, err = webhook.ParseTemplate(`{"text": {{.Message}}}`)
+	c.Check(err, gc.ErrorMatches, `payload template rendering .* as JSON not valid`)
+}
+
+func (s *WebhookSuite) TestSign(c *gc.C) {
+	// Known answer from RFC 4231, test case 2.
+	signature := webhook.Sign("Jefe", []byte("what do ya want for nothing?"))
+	c.Assert(signature, gc.Equals, "sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843")
+}
@@ -26,9 +26,12 @@ import (
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/logfwd/httpfwd"
 	"github.com/juju/juju/logfwd/syslog"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/secretbackend"
 	jujuversion "github.com/juju/juju/version"
+	"github.com/juju/juju/wrench"
 )
 
 var logger = loggo.GetLogger("juju.environs.config")
@@ -195,6 +198,39 @@ const (
 	// forwarding.
 	LogFwdSyslogClientKey = "syslog-client-key"
 
+	// LogForwardType selects the kind of sink that logs are forwarded
+	// to. It is one of the LogFwdType* values, and defaults to syslog.
+	LogForwardType = "logforward-type"
+
+	// WrenchesKey lists the wrenches (fault injection points) that are
+	// active on every agent in the model, in the form accepted by
+	// wrench.Parse. Agents ignore it unless they were started with the
+	// remote-wrenches developer feature flag.
+	WrenchesKey = "wrenches"
+
+	// LogFwdURL sets the endpoint of an http, loki or elasticsearch
+	// log forwarding sink.
+	LogFwdURL = "logforward-url"
+
+	// LogFwdCACert sets the certificate of the CA that signed the
+	// certificate of an http, loki or elasticsearch log forwarding sink.
+	LogFwdCACert = "logforward-ca-cert"
+
+	// LogFwdUsername sets the username used to authenticate with an
+	// http, loki or elasticsearch log forwarding sink.
+	LogFwdUsername = "logforward-username"
+
+	// LogFwdPassword sets the password used to authenticate with an
+	// http, loki or elasticsearch log forwarding sink.
+	LogFwdPassword = "logforward-password"
+
+	// SecretBackendKey selects where cloud credential attributes and
+	// charm secret values are stored. It is one of the secretbackend
+	// types, and defaults to internal. The settings of the external
+	// backends are in the controller config, so that their credentials
+	// are never visible to model users.
+	SecretBackendKey = "secret-backend"
+
 	// AutomaticallyRetryHooks determines whether the uniter will
 	// automatically retry a hook that has failed
 	AutomaticallyRetryHooks = "automatically-retry-hooks"
@@ -223,6 +259,12 @@ const (
 	// grow to before it is pruned, eg "5M"
 	MaxActionResultsSize = "max-action-results-size"
 
+	// ActionResultsArchive is where action results are archived before
+	// they are pruned: "storage" for the controller's blob storage, or
+	// "directory" for the controller's action-results-archive-dir.
+	// Results are not archived if it is empty.
+	ActionResultsArchive = "action-results-archive"
+
 	// UpdateStatusHookInterval is how often to run the update-status hook.
 	UpdateStatusHookInterval = "update-status-hook-interval"
 
@@ -448,6 +490,15 @@ const (
 
 	// DefaultActionResultsSize is the default size of the action results.
 	DefaultActionResultsSize = "5G"
+
+	// ActionResultsArchiveStorage is the value of ActionResultsArchive
+	// used to archive action results in the controller's blob storage.
+	ActionResultsArchiveStorage = "storage"
+
+	// ActionResultsArchiveDirectory is the value of ActionResultsArchive
+	// used to archive action results in the directory set by the
+	// controller's action-results-archive-dir.
+	ActionResultsArchiveDirectory = "directory"
 )
 
 var defaultConfigValues = map[string]interface{}{
@@ -636,12 +687,29 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
-	if lfCfg, ok := cfg.LogFwdSyslog(); ok {
-		if err := lfCfg.Validate(); err != nil {
-			return errors.Annotate(err, "invalid syslog forwarding config")
+	if v, ok := cfg.defined[WrenchesKey].(string); ok {
+		if _, err := wrench.Parse(v); err != nil {
+			return errors.Annotate(err, "invalid wrenches in model configuration")
 		}
 	}
 
+	switch fwdType := cfg.LogFwdType(); fwdType {
+	case LogFwdTypeSyslog:
+		if lfCfg, ok := cfg.LogFwdSyslog(); ok {
+			if err := lfCfg.Validate(); err != nil {
+				return errors.Annotate(err, "invalid syslog forwarding config")
+			}
+		}
+	case LogFwdTypeHTTP, LogFwdTypeLoki, LogFwdTypeElasticsearch:
+		if lfCfg, ok := cfg.LogFwdHTTP(); ok {
+			if err := lfCfg.Validate(); err != nil {
+				return errors.Annotatef(err, "invalid %s forwarding config", fwdType)
+			}
+		}
+	default:
+		return errors.NotValidf("%s %q", LogForwardType, fwdType)
+	}
+
 	if uuid := cfg.UUID(); !utils.IsValidUUIDString(uuid) {
 		return errors.Errorf("uuid: expected UUID, got string(%q)", uuid)
 	}
@@ -675,6 +743,12 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
+	if v, ok := cfg.defined[ActionResultsArchive].(string); ok {
+		if v != "" && v != ActionResultsArchiveStorage && v != ActionResultsArchiveDirectory {
+			return errors.Errorf("invalid action results archive %q in model configuration: expected %q or %q", v, ActionResultsArchiveStorage, ActionResultsArchiveDirectory)
+		}
+	}
+
 	if v, ok := cfg.defined[UpdateStatusHookInterval].(string); ok {
 		duration, err := time.ParseDuration(v)
 		if err != nil {
@@ -1161,6 +1235,76 @@ func (c *Config) LogFwdSyslog() (*syslog.RawConfig, bool) {
 	return &lfCfg, true
 }
 
+// Wrenches returns the wrenches that are active on every agent in the
+// model.
+func (c *Config) Wrenches() []wrench.Wrench {
+	// The value has already passed Validate().
+	wrenches, _ := wrench.Parse(c.asString(WrenchesKey))
+	return wrenches
+}
+
+// LogFwdType returns the kind of sink that logs are forwarded to.
+func (c *Config) LogFwdType() string {
+	if s, ok := c.defined[LogForwardType].(string); ok && s != "" {
+		return s
+	}
+	return LogFwdTypeSyslog
+}
+
+// LogFwdHTTP returns the config for forwarding logs to an http, loki
+// or elasticsearch sink.
+func (c *Config) LogFwdHTTP() (*httpfwd.RawConfig, bool) {
+	partial := false
+	var lfCfg httpfwd.RawConfig
+
+	if s, ok := c.defined[LogForwardEnabled]; ok {
+		partial = true
+		lfCfg.Enabled = s.(bool)
+	}
+
+	if s, ok := c.defined[LogFwdURL]; ok && s != "" {
+		partial = true
+		lfCfg.URL = s.(string)
+	}
+
+	if s, ok := c.defined[LogFwdCACert]; ok && s != "" {
+		partial = true
+		lfCfg.CACert = s.(string)
+	}
+
+	if s, ok := c.defined[LogFwdUsername]; ok && s != "" {
+		partial = true
+		lfCfg.Username = s.(string)
+	}
+
+	if s, ok := c.defined[LogFwdPassword]; ok && s != "" {
+		partial = true
+		lfCfg.Password = s.(string)
+	}
+
+	if !partial {
+		return nil, false
+	}
+	lfCfg.Format = logFwdFormats[c.LogFwdType()]
+	return &lfCfg, true
+}
+
+// LogFwd returns the config for forwarding logs to the sink selected
+// by logforward-type.
+func (c *Config) LogFwd() (*LogFwdConfig, bool) {
+	lfCfg := &LogFwdConfig{Type: c.LogFwdType()}
+	var ok bool
+	if lfCfg.Type == LogFwdTypeSyslog {
+		lfCfg.Syslog, ok = c.LogFwdSyslog()
+	} else {
+		lfCfg.HTTP, ok = c.LogFwdHTTP()
+	}
+	if !ok {
+		return nil, false
+	}
+	return lfCfg, true
+}
+
 // FirewallMode returns whether the firewall should
 // manage ports per machine, globally, or not at all.
 // (FwInstance, FwGlobal, or FwNone).
@@ -1468,6 +1612,13 @@ func (c *Config) MaxActionResultsSizeMB() uint {
 	return uint(val)
 }
 
+// ActionResultsArchive returns where action results are archived before
+// they are pruned: ActionResultsArchiveStorage,
+// ActionResultsArchiveDirectory, or "" if they are not archived.
+func (c *Config) ActionResultsArchive() string {
+	return c.asString(ActionResultsArchive)
+}
+
 // UpdateStatusHookInterval is how often to run the charm
 // update-status hook.
 func (c *Config) UpdateStatusHookInterval() time.Duration {
@@ -1599,6 +1750,13 @@ var alwaysOptional = schema.Defaults{
 	LogFwdSyslogCACert:     schema.Omit,
 	LogFwdSyslogClientCert: schema.Omit,
 	LogFwdSyslogClientKey:  schema.Omit,
+	LogForwardType:         schema.Omit,
+	LogFwdURL:              schema.Omit,
+	LogFwdCACert:           schema.Omit,
+	LogFwdUsername:         schema.Omit,
+	LogFwdPassword:         schema.Omit,
+	WrenchesKey:            schema.Omit,
+	SecretBackendKey:       schema.Omit,
 
 	// Storage related config.
 	// Environ providers will specify their own defaults.
@@ -1653,6 +1811,7 @@ var alwaysOptional = schema.Defaults{
 	MaxStatusHistorySize:          schema.Omit,
 	MaxActionResultsAge:           schema.Omit,
 	MaxActionResultsSize:          schema.Omit,
+	ActionResultsArchive:          schema.Omit,
 	UpdateStatusHookInterval:      schema.Omit,
 	EgressSubnets:                 schema.Omit,
 	FanConfig:                     schema.Omit,
@@ -2065,6 +2224,44 @@ global or per instance security groups.`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	LogForwardType: {
+		Description: `The kind of sink that logs are forwarded to: syslog (the default), http, loki or elasticsearch.`,
+		Type:        environschema.Tstring,
+		Values:      []interface{}{LogFwdTypeSyslog, LogFwdTypeHTTP, LogFwdTypeLoki, LogFwdTypeElasticsearch},
+		Group:       environschema.EnvironGroup,
+	},
+	WrenchesKey: {
+		Description: `Wrenches (fault injection points) active on every agent in the model, as a comma separated list of category/feature, each optionally followed by @ and the RFC3339 time it expires. For testing only; ignored unless the agents have the remote-wrenches developer feature flag.`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdURL: {
+		Description: `The URL that logs are posted to when forwarding to an http, loki or elasticsearch sink.`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdCACert: {
+		Description: `The certificate of the CA that signed the http, loki or elasticsearch sink's certificate, in PEM format.`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdUsername: {
+		Description: `The username used to authenticate with an http, loki or elasticsearch sink.`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdPassword: {
+		Description: `The password used to authenticate with an http, loki or elasticsearch sink.`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+		Secret:      true,
+	},
+	SecretBackendKey: {
+		Description: `Where cloud credentials and charm secret values are stored: internal (the controller database, the default), vault or file. The vault and file backends are configured in the controller config.`,
+		Type:        environschema.Tstring,
+		Values:      []interface{}{secretbackend.Internal, secretbackend.Vault, secretbackend.File},
+		Group:       environschema.EnvironGroup,
+	},
 	"ssl-hostname-verification": {
 		Description: "Whether SSL hostname verification is enabled (default true)",
 		Type:        environschema.Tbool,
@@ -2148,6 +2345,11 @@ where possible. (default "")`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	ActionResultsArchive: {
+		Description: `Where action results are archived, as JSON Lines, before they are pruned: "storage" for the controller's blob storage, or "directory" for the directory set by the controller's action-results-archive-dir. Results are not archived if unset.`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	UpdateStatusHookInterval: {
 		Description: "How often to run the charm update-status hook, in human-readable time format (default 5m, range 1-60m)",
 		Type:        environschema.Tstring,
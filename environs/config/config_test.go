@@ -21,8 +21,12 @@ import (
 	"github.com/juju/juju/charmhub"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/logfwd/httpfwd"
+	"github.com/juju/juju/logfwd/syslog"
+	"github.com/juju/juju/secretbackend"
 	"github.com/juju/juju/testing"
 	jujuversion "github.com/juju/juju/version"
+	"github.com/juju/juju/wrench"
 )
 
 func Test(t *stdtesting.T) {
@@ -564,6 +568,96 @@ var configTests = []configTest{
 			"syslog-client-cert": testing.ServerCert,
 			"syslog-client-key":  testing.ServerKey,
 		}),
+	}, {
+		about:       "Valid loki config values",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logforward-enabled":  true,
+			"logforward-type":     "loki",
+			"logforward-url":      "https://loki.example.com/loki/api/v1/push",
+			"logforward-ca-cert":  testing.CACert,
+			"logforward-username": "juju",
+			"logforward-password": "secret",
+		}),
+	}, {
+		about:       "Syslog config is not validated for other log forwarding types",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logforward-enabled": true,
+			"logforward-type":    "http",
+			"logforward-url":     "http://10.0.0.1:8080/logs",
+			"syslog-ca-cert":     "abc",
+		}),
+	}, {
+		about:       "Invalid log forwarding type",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logforward-type": "gelf",
+		}),
+		err: `logforward-type: expected one of \[syslog http loki elasticsearch\], got "gelf"`,
+	}, {
+		about:       "Missing elasticsearch URL",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logforward-enabled": true,
+			"logforward-type":    "elasticsearch",
+		}),
+		err: `invalid elasticsearch forwarding config: empty URL not valid`,
+	}, {
+		about:       "Invalid log forwarding URL",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logforward-enabled": true,
+			"logforward-type":    "http",
+			"logforward-url":     "tcp://10.0.0.1:8080",
+		}),
+		err: `invalid http forwarding config: URL scheme "tcp" not valid`,
+	}, {
+		about:       "Valid wrenches",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"wrenches": "machine-agent/slow uniter/fail@2021-06-01T12:00:00Z",
+		}),
+	}, {
+		about:       "Invalid wrenches",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"wrenches": "uniter",
+		}),
+		err: `invalid wrenches in model configuration: wrench "uniter", expected category/feature not valid`,
+	}, {
+		about:       "Valid action-results-archive",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"action-results-archive": "directory",
+		}),
+	}, {
+		about:       "Invalid action-results-archive",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"action-results-archive": "archive",
+		}),
+		err: `invalid action results archive "archive" in model configuration: expected "storage" or "directory"`,
+	}, {
+		about:       "Path for action-results-archive",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"action-results-archive": "/var/lib/juju/action-archive",
+		}),
+		err: `invalid action results archive "/var/lib/juju/action-archive" in model configuration: expected "storage" or "directory"`,
+	}, {
+		about:       "Valid secret backend type",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"secret-backend": "vault",
+		}),
+	}, {
+		about:       "Invalid secret backend type",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"secret-backend": "mongo",
+		}),
+		err: `secret-backend: expected one of \[internal vault file\], got "mongo"`,
 	}, {
 		about:       "Valid container-inherit-properties",
 		useDefaults: config.UseDefaults,
@@ -712,6 +806,27 @@ func (test configTest) check(c *gc.C, home *gitjujutesting.FakeHome) {
 		c.Check(lfCfg.ClientKey, gc.Equals, "")
 	}
 
+	if v, ok := test.attrs["logforward-type"].(string); ok {
+		c.Assert(cfg.LogFwdType(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.LogFwdType(), gc.Equals, config.LogFwdTypeSyslog)
+	}
+	httpCfg, hasHTTPCfg := cfg.LogFwdHTTP()
+	if v, ok := test.attrs["logforward-url"].(string); ok {
+		c.Assert(hasHTTPCfg, jc.IsTrue)
+		c.Assert(httpCfg.URL, gc.Equals, v)
+	}
+	if v, ok := test.attrs["logforward-username"].(string); ok {
+		c.Assert(hasHTTPCfg, jc.IsTrue)
+		c.Assert(httpCfg.Username, gc.Equals, v)
+	}
+
+	if v, ok := test.attrs["secret-backend"].(string); ok {
+		c.Assert(cfg.SecretBackend(), gc.Equals, v)
+	} else {
+		c.Assert(cfg.SecretBackend(), gc.Equals, secretbackend.Internal)
+	}
+
 	if v, ok := test.attrs["ssl-hostname-verification"]; ok {
 		c.Assert(cfg.SSLHostnameVerification(), gc.Equals, v)
 	}
@@ -1596,3 +1711,87 @@ runcmd:
     - mkdir /tmp/runcmd
 package_upgrade: true
 `[1:]
+
+func (s *ConfigSuite) TestLogFwd(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"logforward-enabled": true,
+		"logforward-type":    "loki",
+		"logforward-url":     "https://loki.example.com/loki/api/v1/push",
+		"syslog-host":        "localhost:1234",
+	})
+	lfCfg, ok := cfg.LogFwd()
+	c.Assert(ok, jc.IsTrue)
+	c.Check(lfCfg, jc.DeepEquals, &config.LogFwdConfig{
+		Type: config.LogFwdTypeLoki,
+		HTTP: &httpfwd.RawConfig{
+			Enabled: true,
+			Format:  httpfwd.FormatLoki,
+			URL:     "https://loki.example.com/loki/api/v1/push",
+		},
+	})
+	c.Check(lfCfg.Enabled(), jc.IsTrue)
+	c.Check(lfCfg.Validate(), jc.ErrorIsNil)
+}
+
+func (s *ConfigSuite) TestLogFwdDefaultsToSyslog(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"syslog-host": "localhost:1234",
+	})
+	lfCfg, ok := cfg.LogFwd()
+	c.Assert(ok, jc.IsTrue)
+	c.Check(lfCfg, jc.DeepEquals, &config.LogFwdConfig{
+		Type:   config.LogFwdTypeSyslog,
+		Syslog: &syslog.RawConfig{Host: "localhost:1234"},
+	})
+	c.Check(lfCfg.Enabled(), jc.IsFalse)
+}
+
+func (s *ConfigSuite) TestWrenches(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"wrenches": "machine-agent/slow, uniter/fail@2021-06-01T12:00:00Z",
+	})
+	c.Assert(cfg.Wrenches(), jc.DeepEquals, []wrench.Wrench{
+		{Category: "machine-agent", Feature: "slow"},
+		{Category: "uniter", Feature: "fail", Expires: time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)},
+	})
+}
+
+func (s *ConfigSuite) TestWrenchesDefault(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.Wrenches(), gc.HasLen, 0)
+}
+
+func (s *ConfigSuite) TestActionResultsArchive(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.ActionResultsArchive(), gc.Equals, "")
+	cfg = newTestConfig(c, testing.Attrs{
+		"action-results-archive": "storage",
+	})
+	c.Assert(cfg.ActionResultsArchive(), gc.Equals, config.ActionResultsArchiveStorage)
+	cfg = newTestConfig(c, testing.Attrs{
+		"action-results-archive": "directory",
+	})
+	c.Assert(cfg.ActionResultsArchive(), gc.Equals, config.ActionResultsArchiveDirectory)
+}
+
+func (s *ConfigSuite) TestSecretBackend(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+	c.Assert(cfg.SecretBackend(), gc.Equals, secretbackend.Internal)
+	cfg = newTestConfig(c, testing.Attrs{
+		"secret-backend": "vault",
+	})
+	c.Assert(cfg.SecretBackend(), gc.Equals, secretbackend.Vault)
+}
+
+func (s *ConfigSuite) TestSecretBackendSettingsNotInModelConfig(c *gc.C) {
+	for _, key := range []string{
+		"secret-backend-vault-address",
+		"secret-backend-vault-token",
+		"secret-backend-vault-mount",
+		"secret-backend-vault-ca-cert",
+		"secret-backend-file-path",
+	} {
+		_, ok := config.ConfigSchema[key]
+		c.Check(ok, jc.IsFalse, gc.Commentf("%s", key))
+	}
+}
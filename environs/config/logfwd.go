@@ -0,0 +1,72 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/logfwd/httpfwd"
+	"github.com/juju/juju/logfwd/syslog"
+)
+
+const (
+	// LogFwdTypeSyslog forwards logs to a remote syslog (RFC 5424) host.
+	LogFwdTypeSyslog = "syslog"
+
+	// LogFwdTypeHTTP posts logs as JSON to an HTTP(S) endpoint.
+	LogFwdTypeHTTP = "http"
+
+	// LogFwdTypeLoki pushes logs to a Loki push API.
+	LogFwdTypeLoki = "loki"
+
+	// LogFwdTypeElasticsearch indexes logs using an Elasticsearch
+	// bulk API.
+	LogFwdTypeElasticsearch = "elasticsearch"
+)
+
+// logFwdFormats maps the HTTP based log forwarding types to the format
+// their records are sent in.
+var logFwdFormats = map[string]httpfwd.Format{
+	LogFwdTypeHTTP:          httpfwd.FormatJSON,
+	LogFwdTypeLoki:          httpfwd.FormatLoki,
+	LogFwdTypeElasticsearch: httpfwd.FormatElasticsearch,
+}
+
+// LogFwdConfig holds the config for forwarding logs to the sink
+// selected by logforward-type. Only the config for that sink is set.
+type LogFwdConfig struct {
+	// Type is the kind of sink that logs are forwarded to.
+	Type string
+
+	// Syslog is the config used when Type is LogFwdTypeSyslog.
+	Syslog *syslog.RawConfig
+
+	// HTTP is the config used by the other sink types.
+	HTTP *httpfwd.RawConfig
+}
+
+// Enabled returns true if forwarding to the selected sink is enabled.
+func (c LogFwdConfig) Enabled() bool {
+	switch {
+	case c.Syslog != nil:
+		return c.Syslog.Enabled
+	case c.HTTP != nil:
+		return c.HTTP.Enabled
+	}
+	return false
+}
+
+// Validate ensures that the config for the selected sink is valid.
+func (c LogFwdConfig) Validate() error {
+	switch {
+	case c.Type == LogFwdTypeSyslog && c.Syslog != nil:
+		return errors.Trace(c.Syslog.Validate())
+	case c.Type != LogFwdTypeSyslog && c.HTTP != nil:
+		if _, ok := logFwdFormats[c.Type]; !ok {
+			return errors.NotValidf("log forwarding type %q", c.Type)
+		}
+		return errors.Trace(c.HTTP.Validate())
+	}
+	return errors.NotValidf("missing %s forwarding config", c.Type)
+}
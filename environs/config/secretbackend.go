@@ -0,0 +1,17 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package config
+
+import (
+	"github.com/juju/juju/secretbackend"
+)
+
+// SecretBackend returns the type of the backend that cloud credential
+// attributes and charm secret values are stored in.
+func (c *Config) SecretBackend() string {
+	if s, ok := c.defined[SecretBackendKey].(string); ok && s != "" {
+		return s
+	}
+	return secretbackend.Internal
+}
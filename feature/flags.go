@@ -45,3 +45,8 @@ const RawK8sSpec = "raw-k8s-spec"
 
 // ActionsV2 enables the next generation actions UX.
 const ActionsV2 = "actions-v2"
+
+// RemoteWrenches allows wrenches (fault injection points) to be set on
+// every agent in a model through the "wrenches" model config. It is
+// only for test deployments; without it the model config is ignored.
+const RemoteWrenches = "remote-wrenches"
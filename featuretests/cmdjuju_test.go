@@ -115,6 +115,31 @@ func (s *cmdJujuSuite) TestApplicationUnset(c *gc.C) {
 func (s *cmdJujuSuite) TestApplicationGetIAASModel(c *gc.C) {
 	expected := `application: dummy-application
 application-config:
+  action-results-max-age:
+    description: The maximum age for the results of tasks run on this application
+      before they are pruned, overriding the model's max-action-results-age
+    source: unset
+    type: string
+  action-timeout:
+    description: The maximum time an action may run on this application's units before
+      it is terminated and fails, eg "2h"
+    source: unset
+    type: string
+  hook-cpu-quota:
+    description: The maximum CPU time of hook and action processes on this application's
+      machine units, as a percentage of one CPU, eg "50%"
+    source: unset
+    type: string
+  hook-memory-limit:
+    description: The maximum memory of hook and action processes on this application's
+      machine units, eg "512M"
+    source: unset
+    type: string
+  hook-timeout:
+    description: The maximum time a hook may run on this application's units before
+      it is terminated and the unit is put into error, eg "30m"
+    source: unset
+    type: string
   trust:
     default: false
     description: Does this application have access to trusted credentials
@@ -158,6 +183,31 @@ settings:
 func (s *cmdJujuSuite) TestApplicationGetCAASModel(c *gc.C) {
 	expected := `application: gitlab-application
 application-config:
+  action-results-max-age:
+    description: The maximum age for the results of tasks run on this application
+      before they are pruned, overriding the model's max-action-results-age
+    source: unset
+    type: string
+  action-timeout:
+    description: The maximum time an action may run on this application's units before
+      it is terminated and fails, eg "2h"
+    source: unset
+    type: string
+  hook-cpu-quota:
+    description: The maximum CPU time of hook and action processes on this application's
+      machine units, as a percentage of one CPU, eg "50%"
+    source: unset
+    type: string
+  hook-memory-limit:
+    description: The maximum memory of hook and action processes on this application's
+      machine units, eg "512M"
+    source: unset
+    type: string
+  hook-timeout:
+    description: The maximum time a hook may run on this application's units before
+      it is terminated and the unit is put into error, eg "30m"
+    source: unset
+    type: string
   juju-application-path:
     default: /
     description: the relative http path used to access an application
@@ -282,6 +332,31 @@ settings:
 func (s *cmdJujuSuite) TestApplicationGetWeirdYAML(c *gc.C) {
 	expected := `application: yaml-config
 application-config:
+  action-results-max-age:
+    description: The maximum age for the results of tasks run on this application
+      before they are pruned, overriding the model's max-action-results-age
+    source: unset
+    type: string
+  action-timeout:
+    description: The maximum time an action may run on this application's units before
+      it is terminated and fails, eg "2h"
+    source: unset
+    type: string
+  hook-cpu-quota:
+    description: The maximum CPU time of hook and action processes on this application's
+      machine units, as a percentage of one CPU, eg "50%"
+    source: unset
+    type: string
+  hook-memory-limit:
+    description: The maximum memory of hook and action processes on this application's
+      machine units, eg "512M"
+    source: unset
+    type: string
+  hook-timeout:
+    description: The maximum time a hook may run on this application's units before
+      it is terminated and the unit is put into error, eg "30m"
+    source: unset
+    type: string
   trust:
     default: false
     description: Does this application have access to trusted credentials
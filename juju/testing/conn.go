@@ -714,6 +714,7 @@ func newState(controllerUUID string, environ environs.Environ, mongoInfo *mongo.
 		ControllerModelTag: modelTag,
 		MongoSession:       session,
 		NewPolicy:          newPolicyFunc,
+		DataDir:            dummy.DataDir,
 	}
 	pool, err := state.OpenStatePool(args)
 	if errors.IsUnauthorized(errors.Cause(err)) {
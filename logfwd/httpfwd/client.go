@@ -0,0 +1,150 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package httpfwd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/retry"
+
+	"github.com/juju/juju/logfwd"
+)
+
+const (
+	// requestTimeout is how long a single request may take.
+	requestTimeout = 30 * time.Second
+
+	// sendAttempts is how many times a batch is sent before giving up.
+	sendAttempts = 6
+
+	// initialRetryDelay is how long to wait before retrying a failed
+	// request. The delay doubles with each attempt up to maxRetryDelay.
+	initialRetryDelay = time.Second
+	maxRetryDelay     = 30 * time.Second
+)
+
+// Client sends log records to a remote HTTP endpoint.
+type Client struct {
+	cfg     RawConfig
+	encoder encoder
+	client  *http.Client
+	clock   clock.Clock
+	stop    chan struct{}
+}
+
+// Open returns a client that sends records to the endpoint described
+// by the config. No connection is made until records are sent.
+func Open(cfg RawConfig, clock clock.Clock) (*Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	enc, err := newEncoder(cfg.Format)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tlsCfg, err := cfg.TLSConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsCfg != nil {
+		transport.TLSClientConfig = tlsCfg
+	}
+	return &Client{
+		cfg:     cfg,
+		encoder: enc,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   requestTimeout,
+		},
+		clock: clock,
+		stop:  make(chan struct{}),
+	}, nil
+}
+
+// Close implements io.Closer.
+func (c *Client) Close() error {
+	select {
+	case <-c.stop:
+	default:
+		close(c.stop)
+	}
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+// Send sends the records to the endpoint in a single request. Requests
+// that fail because of network or server errors are retried with an
+// exponential backoff; requests rejected by the server are not.
+func (c *Client) Send(records []logfwd.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	body, err := c.encoder.Encode(records)
+	if err != nil {
+		return errors.Annotate(err, "encoding log records")
+	}
+	err = retry.Call(retry.CallArgs{
+		Func: func() error {
+			return c.post(body)
+		},
+		IsFatalError: func(err error) bool {
+			_, ok := errors.Cause(err).(*permanentError)
+			return ok
+		},
+		Attempts:    sendAttempts,
+		Delay:       initialRetryDelay,
+		MaxDelay:    maxRetryDelay,
+		BackoffFunc: retry.DoubleDelay,
+		Clock:       c.clock,
+		Stop:        c.stop,
+	})
+	if retry.IsAttemptsExceeded(err) || retry.IsRetryStopped(err) {
+		err = retry.LastError(err)
+	}
+	if err != nil {
+		return errors.Annotatef(err, "sending %d log records to %s", len(records), c.cfg.URL)
+	}
+	return nil
+}
+
+func (c *Client) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, c.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return &permanentError{err}
+	}
+	req.Header.Set("Content-Type", c.encoder.ContentType())
+	if c.cfg.Username != "" {
+		req.SetBasicAuth(c.cfg.Username, c.cfg.Password)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return c.encoder.CheckResponse(respBody)
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return errors.Errorf("server returned %s", resp.Status)
+	}
+	return &permanentError{fmt.Errorf("server rejected request: %s: %s", resp.Status, bytes.TrimSpace(respBody))}
+}
+
+// permanentError indicates that a request failed in a way that retrying
+// won't fix.
+type permanentError struct {
+	error
+}
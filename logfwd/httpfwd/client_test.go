@@ -0,0 +1,321 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package httpfwd_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/logfwd"
+	"github.com/juju/juju/logfwd/httpfwd"
+	coretesting "github.com/juju/juju/testing"
+)
+
+const (
+	controllerUUID = "9f484882-2f18-4fd2-967d-db9663db7bea"
+	modelUUID      = "deadbeef-2f18-4fd2-967d-db9663db7bea"
+)
+
+type request struct {
+	contentType string
+	username    string
+	password    string
+	body        string
+}
+
+type ClientSuite struct {
+	testing.IsolationSuite
+
+	clock *testclock.Clock
+
+	mu        sync.Mutex
+	requests  []request
+	responses []func(http.ResponseWriter)
+	server    *httptest.Server
+}
+
+var _ = gc.Suite(&ClientSuite{})
+
+func (s *ClientSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Now())
+	s.requests = nil
+	s.responses = nil
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+}
+
+func (s *ClientSuite) handle(w http.ResponseWriter, req *http.Request) {
+	body, _ := ioutil.ReadAll(req.Body)
+	username, password, _ := req.BasicAuth()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, request{
+		contentType: req.Header.Get("Content-Type"),
+		username:    username,
+		password:    password,
+		body:        string(body),
+	})
+	if len(s.responses) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	respond := s.responses[0]
+	s.responses = s.responses[1:]
+	respond(w)
+}
+
+func respondWith(status int, body string) func(http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func (s *ClientSuite) open(c *gc.C, format httpfwd.Format) *httpfwd.Client {
+	client, err := httpfwd.Open(httpfwd.RawConfig{
+		Enabled: true,
+		Format:  format,
+		URL:     s.server.URL + "/logs",
+	}, s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { client.Close() })
+	return client
+}
+
+func newRecord(id int64, origin string, level loggo.Level, msg string) logfwd.Record {
+	return logfwd.Record{
+		ID: id,
+		Origin: logfwd.Origin{
+			ControllerUUID: controllerUUID,
+			ModelUUID:      modelUUID,
+			Type:           logfwd.OriginTypeMachine,
+			Name:           origin,
+			Software: logfwd.Software{
+				PrivateEnterpriseNumber: 28978,
+				Name:                    "jujud-machine-agent",
+				Version:                 version.MustParse("2.9.0"),
+			},
+		},
+		Timestamp: time.Date(2021, 3, 4, 5, 6, 7, 8, time.UTC),
+		Level:     level,
+		Location: logfwd.SourceLocation{
+			Module:   "juju.worker.uniter",
+			Filename: "uniter.go",
+			Line:     42,
+		},
+		Message: msg,
+	}
+}
+
+func (s *ClientSuite) TestOpenInvalidConfig(c *gc.C) {
+	_, err := httpfwd.Open(httpfwd.RawConfig{Enabled: true, Format: httpfwd.FormatJSON}, s.clock)
+	c.Assert(err, gc.ErrorMatches, `empty URL not valid`)
+}
+
+func (s *ClientSuite) TestSendJSON(c *gc.C) {
+	client := s.open(c, httpfwd.FormatJSON)
+
+	err := client.Send([]logfwd.Record{
+		newRecord(10, "0", loggo.INFO, "hello"),
+		newRecord(11, "0", loggo.ERROR, "world"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.requests, gc.HasLen, 1)
+	c.Check(s.requests[0].contentType, gc.Equals, "application/json")
+	c.Check(s.requests[0].body, jc.JSONEquals, []map[string]interface{}{{
+		"id":               10,
+		"timestamp":        "2021-03-04T05:06:07.000000008Z",
+		"controller-uuid":  controllerUUID,
+		"model-uuid":       modelUUID,
+		"origin-type":      "machine",
+		"origin":           "0",
+		"software":         "jujud-machine-agent",
+		"software-version": "2.9.0",
+		"level":            "INFO",
+		"module":           "juju.worker.uniter",
+		"location":         "uniter.go:42",
+		"message":          "hello",
+	}, {
+		"id":               11,
+		"timestamp":        "2021-03-04T05:06:07.000000008Z",
+		"controller-uuid":  controllerUUID,
+		"model-uuid":       modelUUID,
+		"origin-type":      "machine",
+		"origin":           "0",
+		"software":         "jujud-machine-agent",
+		"software-version": "2.9.0",
+		"level":            "ERROR",
+		"module":           "juju.worker.uniter",
+		"location":         "uniter.go:42",
+		"message":          "world",
+	}})
+}
+
+func (s *ClientSuite) TestSendLoki(c *gc.C) {
+	client := s.open(c, httpfwd.FormatLoki)
+
+	err := client.Send([]logfwd.Record{
+		newRecord(10, "0", loggo.INFO, "one"),
+		newRecord(11, "1", loggo.INFO, "two"),
+		newRecord(12, "0", loggo.INFO, "three"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	labels := func(origin string) map[string]interface{} {
+		return map[string]interface{}{
+			"job":             "juju",
+			"controller_uuid": controllerUUID,
+			"model_uuid":      modelUUID,
+			"origin":          origin,
+			"level":           "info",
+		}
+	}
+	ts := "1614834367000000008"
+	c.Assert(s.requests, gc.HasLen, 1)
+	c.Check(s.requests[0].contentType, gc.Equals, "application/json")
+	c.Check(s.requests[0].body, jc.JSONEquals, map[string]interface{}{
+		"streams": []interface{}{
+			map[string]interface{}{
+				"stream": labels("0"),
+				"values": []interface{}{
+					[]interface{}{ts, "juju.worker.uniter uniter.go:42 one"},
+					[]interface{}{ts, "juju.worker.uniter uniter.go:42 three"},
+				},
+			},
+			map[string]interface{}{
+				"stream": labels("1"),
+				"values": []interface{}{
+					[]interface{}{ts, "juju.worker.uniter uniter.go:42 two"},
+				},
+			},
+		},
+	})
+}
+
+func (s *ClientSuite) TestSendElasticsearch(c *gc.C) {
+	client := s.open(c, httpfwd.FormatElasticsearch)
+	s.responses = append(s.responses, respondWith(http.StatusOK, `{"errors":false,"items":[]}`))
+
+	err := client.Send([]logfwd.Record{
+		newRecord(10, "0", loggo.WARNING, "hello"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.requests, gc.HasLen, 1)
+	c.Check(s.requests[0].contentType, gc.Equals, "application/x-ndjson")
+	c.Check(s.requests[0].body, gc.Equals, `
+{"index":{"_id":"deadbeef-2f18-4fd2-967d-db9663db7bea-10"}}
+{"id":10,"timestamp":"2021-03-04T05:06:07.000000008Z","controller-uuid":"9f484882-2f18-4fd2-967d-db9663db7bea","model-uuid":"deadbeef-2f18-4fd2-967d-db9663db7bea","origin-type":"machine","origin":"0","software":"jujud-machine-agent","software-version":"2.9.0","level":"WARNING","module":"juju.worker.uniter","location":"uniter.go:42","message":"hello"}
+`[1:])
+}
+
+func (s *ClientSuite) TestSendElasticsearchItemErrors(c *gc.C) {
+	client := s.open(c, httpfwd.FormatElasticsearch)
+	s.responses = append(s.responses, respondWith(http.StatusOK, `{
+		"errors": true,
+		"items": [
+			{"index": {"status": 201}},
+			{"index": {"status": 400, "error": {"type": "mapper_parsing_exception", "reason": "failed to parse"}}}
+		]
+	}`))
+
+	err := client.Send([]logfwd.Record{
+		newRecord(10, "0", loggo.INFO, "hello"),
+		newRecord(11, "0", loggo.INFO, "world"),
+	})
+	c.Assert(err, gc.ErrorMatches, `sending 2 log records to .*: 1 of 2 records not indexed, first error: mapper_parsing_exception: failed to parse`)
+	c.Check(s.requests, gc.HasLen, 1)
+}
+
+func (s *ClientSuite) TestSendBasicAuth(c *gc.C) {
+	client, err := httpfwd.Open(httpfwd.RawConfig{
+		Enabled:  true,
+		Format:   httpfwd.FormatJSON,
+		URL:      s.server.URL,
+		Username: "juju",
+		Password: "secret",
+	}, s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+	defer client.Close()
+
+	err = client.Send([]logfwd.Record{newRecord(10, "0", loggo.INFO, "hello")})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.requests, gc.HasLen, 1)
+	c.Check(s.requests[0].username, gc.Equals, "juju")
+	c.Check(s.requests[0].password, gc.Equals, "secret")
+}
+
+func (s *ClientSuite) TestSendNoRecords(c *gc.C) {
+	client := s.open(c, httpfwd.FormatJSON)
+	err := client.Send(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.requests, gc.HasLen, 0)
+}
+
+func (s *ClientSuite) TestSendRetriesServerErrors(c *gc.C) {
+	client := s.open(c, httpfwd.FormatJSON)
+	s.responses = append(s.responses,
+		respondWith(http.StatusServiceUnavailable, ""),
+		respondWith(http.StatusTooManyRequests, ""),
+	)
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- client.Send([]logfwd.Record{newRecord(10, "0", loggo.INFO, "hello")})
+	}()
+	// The delay doubles after each failed attempt.
+	c.Assert(s.clock.WaitAdvance(time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+	c.Assert(s.clock.WaitAdvance(2*time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+
+	select {
+	case err := <-errc:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for send")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c.Check(s.requests, gc.HasLen, 3)
+}
+
+func (s *ClientSuite) TestSendDoesNotRetryRejectedRequests(c *gc.C) {
+	client := s.open(c, httpfwd.FormatJSON)
+	s.responses = append(s.responses, respondWith(http.StatusBadRequest, "bad record\n"))
+
+	err := client.Send([]logfwd.Record{newRecord(10, "0", loggo.INFO, "hello")})
+	c.Assert(err, gc.ErrorMatches, `sending 1 log records to .*: server rejected request: 400 Bad Request: bad record`)
+	c.Check(s.requests, gc.HasLen, 1)
+}
+
+func (s *ClientSuite) TestCloseStopsRetries(c *gc.C) {
+	client := s.open(c, httpfwd.FormatJSON)
+	s.responses = append(s.responses, respondWith(http.StatusBadGateway, ""))
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- client.Send([]logfwd.Record{newRecord(10, "0", loggo.INFO, "hello")})
+	}()
+	c.Assert(s.clock.WaitAdvance(0, coretesting.LongWait, 1), jc.ErrorIsNil)
+	client.Close()
+
+	select {
+	case err := <-errc:
+		c.Assert(err, gc.ErrorMatches, `sending 1 log records to .*: server returned 502 Bad Gateway`)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for send")
+	}
+}
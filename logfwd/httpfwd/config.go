@@ -0,0 +1,116 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package httpfwd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/url"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/v2/cert"
+)
+
+// Format identifies how records are encoded for a remote endpoint.
+type Format string
+
+const (
+	// FormatJSON sends each batch of records as a JSON array.
+	FormatJSON Format = "json"
+
+	// FormatLoki sends records to a Loki push API.
+	FormatLoki Format = "loki"
+
+	// FormatElasticsearch sends records to an Elasticsearch bulk API.
+	FormatElasticsearch Format = "elasticsearch"
+)
+
+// Validate ensures that the format is supported.
+func (f Format) Validate() error {
+	switch f {
+	case FormatJSON, FormatLoki, FormatElasticsearch:
+		return nil
+	}
+	return errors.NotValidf("format %q", string(f))
+}
+
+// RawConfig holds the raw configuration data for forwarding logs to an
+// HTTP endpoint.
+type RawConfig struct {
+	// Enabled is true if the log forwarding feature is enabled.
+	Enabled bool
+
+	// Format is how records are encoded when they are sent.
+	Format Format
+
+	// URL is the endpoint that records are posted to, for example
+	// https://loki.example.com/loki/api/v1/push for Loki or
+	// https://es.example.com:9200/juju-logs/_bulk for Elasticsearch.
+	URL string
+
+	// CACert is the TLS CA certificate (x.509, PEM-encoded) to use for
+	// validating the server certificate. If it is empty the system
+	// certificate pool is used.
+	CACert string
+
+	// Username and Password, if set, are sent using HTTP basic
+	// authentication.
+	Username string
+	Password string
+}
+
+// Validate ensures that the config is currently valid.
+func (cfg RawConfig) Validate() error {
+	if err := cfg.Format.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := cfg.validateURL(); err != nil {
+		return errors.Trace(err)
+	}
+	if cfg.Password != "" && cfg.Username == "" {
+		return errors.NotValidf("password without username")
+	}
+	if _, err := cfg.TLSConfig(); err != nil {
+		return errors.Annotate(err, "validating TLS config")
+	}
+	return nil
+}
+
+func (cfg RawConfig) validateURL() error {
+	if cfg.URL == "" {
+		if cfg.Enabled {
+			return errors.NotValidf("empty URL")
+		}
+		return nil
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return errors.NotValidf("URL %q", cfg.URL)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.NotValidf("URL scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return errors.NotValidf("URL %q without host", cfg.URL)
+	}
+	return nil
+}
+
+// TLSConfig returns the TLS configuration for connecting to the
+// endpoint. It returns nil if the system certificate pool should be
+// used.
+func (cfg RawConfig) TLSConfig() (*tls.Config, error) {
+	if cfg.CACert == "" {
+		return nil, nil
+	}
+	caCert, err := cert.ParseCert(cfg.CACert)
+	if err != nil {
+		return nil, errors.Annotate(err, "parsing CA certificate")
+	}
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(caCert)
+	return &tls.Config{
+		RootCAs: rootCAs,
+	}, nil
+}
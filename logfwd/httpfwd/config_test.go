@@ -0,0 +1,85 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package httpfwd_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/logfwd/httpfwd"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ConfigSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ConfigSuite{})
+
+func (s *ConfigSuite) TestRawValidateFull(c *gc.C) {
+	cfg := httpfwd.RawConfig{
+		Enabled:  true,
+		Format:   httpfwd.FormatLoki,
+		URL:      "https://loki.example.com/loki/api/v1/push",
+		CACert:   coretesting.CACert,
+		Username: "juju",
+		Password: "secret",
+	}
+
+	err := cfg.Validate()
+
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *ConfigSuite) TestRawValidateDisabledWithoutURL(c *gc.C) {
+	cfg := httpfwd.RawConfig{
+		Format: httpfwd.FormatJSON,
+	}
+	err := cfg.Validate()
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *ConfigSuite) TestRawValidateErrors(c *gc.C) {
+	valid := httpfwd.RawConfig{
+		Enabled: true,
+		Format:  httpfwd.FormatElasticsearch,
+		URL:     "http://es.example.com:9200/juju-logs/_bulk",
+	}
+	tests := []struct {
+		about  string
+		mutate func(*httpfwd.RawConfig)
+		err    string
+	}{{
+		about:  "bad format",
+		mutate: func(cfg *httpfwd.RawConfig) { cfg.Format = "gelf" },
+		err:    `format "gelf" not valid`,
+	}, {
+		about:  "missing URL",
+		mutate: func(cfg *httpfwd.RawConfig) { cfg.URL = "" },
+		err:    `empty URL not valid`,
+	}, {
+		about:  "bad scheme",
+		mutate: func(cfg *httpfwd.RawConfig) { cfg.URL = "ftp://example.com/" },
+		err:    `URL scheme "ftp" not valid`,
+	}, {
+		about:  "missing host",
+		mutate: func(cfg *httpfwd.RawConfig) { cfg.URL = "https:///_bulk" },
+		err:    `URL "https:///_bulk" without host not valid`,
+	}, {
+		about:  "password without username",
+		mutate: func(cfg *httpfwd.RawConfig) { cfg.Password = "secret" },
+		err:    `password without username not valid`,
+	}, {
+		about:  "bad CA cert",
+		mutate: func(cfg *httpfwd.RawConfig) { cfg.CACert = "nope" },
+		err:    `validating TLS config: parsing CA certificate: .*`,
+	}}
+	for i, test := range tests {
+		c.Logf("test %d: %s", i, test.about)
+		cfg := valid
+		test.mutate(&cfg)
+		c.Check(cfg.Validate(), gc.ErrorMatches, test.err)
+	}
+}
@@ -0,0 +1,7 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The httpfwd package holds the tools needed to perform log forwarding
+// from Juju to a remote HTTP(S) endpoint. Records can be sent as plain
+// JSON, to a Loki push API or to an Elasticsearch bulk API.
+package httpfwd
@@ -0,0 +1,259 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package httpfwd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/logfwd"
+)
+
+// encoder converts a batch of records into the body of a request.
+type encoder interface {
+	// ContentType is the media type of the encoded records.
+	ContentType() string
+
+	// Encode returns the request body for the records.
+	Encode([]logfwd.Record) ([]byte, error)
+
+	// CheckResponse reports any failure described by the body of a
+	// successful response. Failures that won't be fixed by resending
+	// the records are returned as a *permanentError.
+	CheckResponse(body []byte) error
+}
+
+func newEncoder(format Format) (encoder, error) {
+	switch format {
+	case FormatJSON:
+		return jsonEncoder{}, nil
+	case FormatLoki:
+		return lokiEncoder{}, nil
+	case FormatElasticsearch:
+		return elasticsearchEncoder{}, nil
+	}
+	return nil, errors.NotValidf("format %q", string(format))
+}
+
+// jsonRecord is the JSON representation of a record, used directly by
+// the JSON format and as the Elasticsearch document.
+type jsonRecord struct {
+	ID              int64     `json:"id"`
+	Timestamp       time.Time `json:"timestamp"`
+	ControllerUUID  string    `json:"controller-uuid"`
+	ModelUUID       string    `json:"model-uuid"`
+	Hostname        string    `json:"hostname,omitempty"`
+	OriginType      string    `json:"origin-type"`
+	Origin          string    `json:"origin"`
+	Software        string    `json:"software,omitempty"`
+	SoftwareVersion string    `json:"software-version,omitempty"`
+	Level           string    `json:"level"`
+	Module          string    `json:"module,omitempty"`
+	Location        string    `json:"location,omitempty"`
+	Message         string    `json:"message"`
+}
+
+func newJSONRecord(rec logfwd.Record) jsonRecord {
+	result := jsonRecord{
+		ID:             rec.ID,
+		Timestamp:      rec.Timestamp.UTC(),
+		ControllerUUID: rec.Origin.ControllerUUID,
+		ModelUUID:      rec.Origin.ModelUUID,
+		Hostname:       rec.Origin.Hostname,
+		OriginType:     rec.Origin.Type.String(),
+		Origin:         rec.Origin.Name,
+		Software:       rec.Origin.Software.Name,
+		Level:          rec.Level.String(),
+		Module:         rec.Location.Module,
+		Location:       rec.Location.String(),
+		Message:        rec.Message,
+	}
+	if result.Software != "" {
+		result.SoftwareVersion = rec.Origin.Software.Version.String()
+	}
+	return result
+}
+
+type jsonEncoder struct{}
+
+// ContentType implements encoder.
+func (jsonEncoder) ContentType() string {
+	return "application/json"
+}
+
+// Encode implements encoder.
+func (jsonEncoder) Encode(records []logfwd.Record) ([]byte, error) {
+	out := make([]jsonRecord, len(records))
+	for i, rec := range records {
+		out[i] = newJSONRecord(rec)
+	}
+	data, err := json.Marshal(out)
+	return data, errors.Trace(err)
+}
+
+// CheckResponse implements encoder.
+func (jsonEncoder) CheckResponse([]byte) error {
+	return nil
+}
+
+// lokiEncoder encodes records for the Loki push API, described at
+// https://grafana.com/docs/loki/latest/api/#post-lokiapiv1push.
+type lokiEncoder struct{}
+
+type lokiPush struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// ContentType implements encoder.
+func (lokiEncoder) ContentType() string {
+	return "application/json"
+}
+
+// Encode implements encoder. Records are grouped into a stream for each
+// distinct set of labels; Loki indexes labels, so only low cardinality
+// fields are used for them and everything else is kept in the line.
+func (lokiEncoder) Encode(records []logfwd.Record) ([]byte, error) {
+	var push lokiPush
+	streams := make(map[string]int)
+	for _, rec := range records {
+		labels := map[string]string{
+			"job":             "juju",
+			"controller_uuid": rec.Origin.ControllerUUID,
+			"model_uuid":      rec.Origin.ModelUUID,
+			"origin":          rec.Origin.Name,
+			"level":           strings.ToLower(rec.Level.String()),
+		}
+		key := lokiStreamKey(labels)
+		index, ok := streams[key]
+		if !ok {
+			index = len(push.Streams)
+			streams[key] = index
+			push.Streams = append(push.Streams, lokiStream{Stream: labels})
+		}
+		push.Streams[index].Values = append(push.Streams[index].Values, [2]string{
+			strconv.FormatInt(rec.Timestamp.UnixNano(), 10),
+			lokiLine(rec),
+		})
+	}
+	data, err := json.Marshal(push)
+	return data, errors.Trace(err)
+}
+
+// CheckResponse implements encoder.
+func (lokiEncoder) CheckResponse([]byte) error {
+	return nil
+}
+
+func lokiStreamKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s=%q,", k, labels[k])
+	}
+	return b.String()
+}
+
+func lokiLine(rec logfwd.Record) string {
+	parts := make([]string, 0, 3)
+	if rec.Location.Module != "" {
+		parts = append(parts, rec.Location.Module)
+	}
+	if loc := rec.Location.String(); loc != "" {
+		parts = append(parts, loc)
+	}
+	parts = append(parts, rec.Message)
+	return strings.Join(parts, " ")
+}
+
+// elasticsearchEncoder encodes records for the Elasticsearch bulk API,
+// described at https://www.elastic.co/guide/en/elasticsearch/reference/current/docs-bulk.html.
+// The target index is taken from the URL, eg. /juju-logs/_bulk.
+type elasticsearchEncoder struct{}
+
+// ContentType implements encoder.
+func (elasticsearchEncoder) ContentType() string {
+	return "application/x-ndjson"
+}
+
+// Encode implements encoder.
+func (elasticsearchEncoder) Encode(records []logfwd.Record) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, rec := range records {
+		// The document ID is derived from the record so that a batch
+		// resent after a failure doesn't duplicate documents.
+		action := map[string]map[string]string{
+			"index": {"_id": fmt.Sprintf("%s-%d", rec.Origin.ModelUUID, rec.ID)},
+		}
+		if err := enc.Encode(action); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := enc.Encode(newJSONRecord(rec)); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+type elasticsearchBulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// CheckResponse implements encoder. The bulk API reports failures for
+// individual documents in the body of a 200 response.
+func (elasticsearchEncoder) CheckResponse(body []byte) error {
+	var resp elasticsearchBulkResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return errors.Annotate(err, "parsing bulk response")
+	}
+	if !resp.Errors {
+		return nil
+	}
+	failed, throttled := 0, 0
+	var first string
+	for _, item := range resp.Items {
+		for _, result := range item {
+			if result.Status < 300 {
+				continue
+			}
+			if failed == 0 {
+				first = fmt.Sprintf("%s: %s", result.Error.Type, result.Error.Reason)
+			}
+			failed++
+			if result.Status == http.StatusTooManyRequests {
+				throttled++
+			}
+		}
+	}
+	err := errors.Errorf("%d of %d records not indexed, first error: %s", failed, len(resp.Items), first)
+	if throttled == failed {
+		// The cluster is overloaded, so the batch can be retried.
+		return err
+	}
+	return &permanentError{err}
+}
@@ -68,6 +68,7 @@ import (
 	corenetwork "github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/network/firewall"
 	"github.com/juju/juju/core/presence"
+	"github.com/juju/juju/core/secrets"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs"
 	environscloudspec "github.com/juju/juju/environs/cloudspec"
@@ -881,6 +882,10 @@ func (e *environ) Bootstrap(ctx environs.BootstrapContext, callCtx context.Provi
 			}
 			defer session.Close()
 
+			if err := secrets.EnsureKeys(secrets.KeysDir(DataDir)); err != nil {
+				return errors.Trace(err)
+			}
+
 			// Since the admin user isn't setup until after here,
 			// the password in the info structure is empty, so the admin
 			// user is constructed with an empty password here.
@@ -903,6 +908,7 @@ func (e *environ) Bootstrap(ctx environs.BootstrapContext, callCtx context.Provi
 				MongoSession:     session,
 				NewPolicy:        estate.newStatePolicy,
 				AdminPassword:    icfg.APIInfo.Password,
+				DataDir:          DataDir,
 			})
 			if err != nil {
 				return err
@@ -0,0 +1,41 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog
+
+import "github.com/juju/juju/core/auditlog"
+
+// QueryTopic is the topic that audit log queries are published on. The
+// audit log query worker on every controller node responds with the
+// matching entries from its local audit log.
+// data: `QueryRequest`
+const QueryTopic = "auditlog.query"
+
+// QueryRequest asks the controller nodes for the entries in their audit
+// logs that match the filter.
+type QueryRequest struct {
+	Users   []string `yaml:"users,omitempty"`
+	Models  []string `yaml:"models,omitempty"`
+	Methods []string `yaml:"methods,omitempty"`
+
+	// From and To are RFC3339 times bounding the requests returned.
+	From string `yaml:"from,omitempty"`
+	To   string `yaml:"to,omitempty"`
+
+	// Limit is the maximum number of entries each node should send.
+	Limit int `yaml:"limit,omitempty"`
+
+	// ResponseTopic is the topic the nodes should publish their
+	// QueryResponse on.
+	ResponseTopic string `yaml:"response-topic"`
+}
+
+// QueryResponse holds the matching entries from one controller node's
+// audit log.
+type QueryResponse struct {
+	// Origin is the tag of the controller agent responding; it is
+	// filled in by the central hub.
+	Origin  string           `yaml:"origin"`
+	Entries []auditlog.Entry `yaml:"entries"`
+	Error   string           `yaml:"error,omitempty"`
+}
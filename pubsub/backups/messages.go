@@ -0,0 +1,28 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+// RestoreLockdownTopic is published by the API server restoring a
+// backup to ask the controller agent on the same machine to stop the
+// workers that write to the database. The agent stays locked down
+// until it is restarted.
+// data: `RestoreLockdownRequest`
+const RestoreLockdownTopic = "backups.restore-lockdown"
+
+// RestoreLockdownRequest asks the controller agent on a machine to
+// stop its database workers before a restore.
+type RestoreLockdownRequest struct {
+	// MachineID identifies the controller machine being restored;
+	// agents on other machines ignore the request.
+	MachineID string `yaml:"machine-id"`
+
+	// ResponseTopic is the topic the agent should publish its
+	// RestoreLockdownResponse on once the workers have stopped.
+	ResponseTopic string `yaml:"response-topic"`
+}
+
+// RestoreLockdownResponse reports whether the workers were stopped.
+type RestoreLockdownResponse struct {
+	Error string `yaml:"error,omitempty"`
+}
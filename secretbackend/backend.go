@@ -0,0 +1,89 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package secretbackend defines where sensitive values, such as cloud
+// credential attributes and charm secrets, are stored. By default they
+// are stored in the controller database; a model can instead be
+// configured to store them in an external backend.
+package secretbackend
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/secretbackend/file"
+	"github.com/juju/juju/secretbackend/vault"
+)
+
+const (
+	// Internal stores values in the controller database.
+	Internal = "internal"
+
+	// Vault stores values in a HashiCorp Vault compatible KV
+	// secrets engine.
+	Vault = "vault"
+
+	// File stores values in encrypted files local to the controller.
+	File = "file"
+)
+
+// Backend stores secret values outside of the controller database.
+type Backend interface {
+	// Get returns the value stored with the key, or an error
+	// satisfying errors.IsNotFound if there is none.
+	Get(key string) (map[string]string, error)
+
+	// Put stores the value with the key, replacing any existing
+	// value.
+	Put(key string, value map[string]string) error
+
+	// Delete removes the value stored with the key. It is not an
+	// error if there is no such value.
+	Delete(key string) error
+}
+
+// Config holds the configuration for a secret backend.
+type Config struct {
+	// Type is the type of backend, one of Internal, Vault or File.
+	// An empty type is the same as Internal.
+	Type string
+
+	// Vault holds the configuration used when Type is Vault.
+	Vault vault.Config
+
+	// File holds the configuration used when Type is File.
+	File file.Config
+}
+
+// IsExternal reports whether values are stored outside of the
+// controller database.
+func (cfg Config) IsExternal() bool {
+	return cfg.Type != "" && cfg.Type != Internal
+}
+
+// Validate ensures that the config is currently valid.
+func (cfg Config) Validate() error {
+	switch cfg.Type {
+	case "", Internal:
+		return nil
+	case Vault:
+		return errors.Annotate(cfg.Vault.Validate(), "vault")
+	case File:
+		return errors.Annotate(cfg.File.Validate(), "file")
+	}
+	return errors.NotValidf("secret backend type %q", cfg.Type)
+}
+
+// New returns the external backend described by the config. It returns
+// an error satisfying errors.IsNotSupported if the config describes
+// the internal backend.
+func New(cfg Config) (Backend, error) {
+	switch cfg.Type {
+	case "", Internal:
+		return nil, errors.NotSupportedf("external storage with %q secret backend", Internal)
+	case Vault:
+		return vault.NewBackend(cfg.Vault)
+	case File:
+		return file.NewBackend(cfg.File)
+	}
+	return nil, errors.NotValidf("secret backend type %q", cfg.Type)
+}
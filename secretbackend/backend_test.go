@@ -0,0 +1,105 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package secretbackend_test
+
+import (
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/secretbackend"
+	"github.com/juju/juju/secretbackend/file"
+	"github.com/juju/juju/secretbackend/vault"
+	"github.com/juju/juju/secretbackend/vault/vaulttesting"
+	"github.com/juju/juju/testing"
+)
+
+type backendSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&backendSuite{})
+
+func (s *backendSuite) TestIsExternal(c *gc.C) {
+	c.Assert(secretbackend.Config{}.IsExternal(), jc.IsFalse)
+	c.Assert(secretbackend.Config{Type: secretbackend.Internal}.IsExternal(), jc.IsFalse)
+	c.Assert(secretbackend.Config{Type: secretbackend.Vault}.IsExternal(), jc.IsTrue)
+	c.Assert(secretbackend.Config{Type: secretbackend.File}.IsExternal(), jc.IsTrue)
+}
+
+func (s *backendSuite) TestValidate(c *gc.C) {
+	for i, test := range []struct {
+		cfg secretbackend.Config
+		err string
+	}{{
+		cfg: secretbackend.Config{},
+	}, {
+		cfg: secretbackend.Config{Type: secretbackend.Internal},
+	}, {
+		cfg: secretbackend.Config{Type: "bogus"},
+		err: `secret backend type "bogus" not valid`,
+	}, {
+		cfg: secretbackend.Config{
+			Type:  secretbackend.Vault,
+			Vault: vault.Config{Address: "https://vault.example.com", Token: "s3cret"},
+		},
+	}, {
+		cfg: secretbackend.Config{Type: secretbackend.Vault},
+		err: "vault: empty address not valid",
+	}, {
+		cfg: secretbackend.Config{
+			Type: secretbackend.File,
+			File: file.Config{Path: "/var/lib/juju/secret-backend", Key: make([]byte, 32)},
+		},
+	}, {
+		cfg: secretbackend.Config{Type: secretbackend.File},
+		err: "file: empty path not valid",
+	}} {
+		c.Logf("test %d: %+v", i, test.cfg)
+		err := test.cfg.Validate()
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *backendSuite) TestNewInternal(c *gc.C) {
+	_, err := secretbackend.New(secretbackend.Config{})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *backendSuite) TestNewVault(c *gc.C) {
+	server := vaulttesting.NewServer("s3cret")
+	defer server.Close()
+
+	backend, err := secretbackend.New(secretbackend.Config{
+		Type:  secretbackend.Vault,
+		Vault: vault.Config{Address: server.URL, Token: "s3cret"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertRoundTrip(c, backend)
+	_, ok := server.Value("secret/foo")
+	c.Assert(ok, jc.IsTrue)
+}
+
+func (s *backendSuite) TestNewFile(c *gc.C) {
+	backend, err := secretbackend.New(secretbackend.Config{
+		Type: secretbackend.File,
+		File: file.Config{Path: filepath.Join(c.MkDir(), "secret-backend"), Key: make([]byte, 32)},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertRoundTrip(c, backend)
+}
+
+func (s *backendSuite) assertRoundTrip(c *gc.C, backend secretbackend.Backend) {
+	err := backend.Put("foo", map[string]string{"a": "1"})
+	c.Assert(err, jc.ErrorIsNil)
+	value, err := backend.Get("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(value, jc.DeepEquals, map[string]string{"a": "1"})
+}
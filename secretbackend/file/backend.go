@@ -0,0 +1,155 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package file stores secret values in encrypted files on the local
+// filesystem. Values are only available on the machine that stored
+// them, so in a highly available controller the directory should be on
+// storage shared by all controller machines. The key the values are
+// encrypted with is never stored in the directory.
+package file
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/v2"
+)
+
+// keySize is the size in bytes of the AES-256 encryption key.
+const keySize = 32
+
+// Config holds the configuration of a file backend.
+type Config struct {
+	// Path is the absolute path of the directory holding the
+	// encrypted values.
+	Path string
+
+	// Key is the AES-256 key the values are encrypted with. It must
+	// be kept outside of Path.
+	Key []byte
+}
+
+// Validate ensures that the config is currently valid.
+func (cfg Config) Validate() error {
+	if cfg.Path == "" {
+		return errors.NotValidf("empty path")
+	}
+	if !filepath.IsAbs(cfg.Path) {
+		return errors.NotValidf("relative path %q", cfg.Path)
+	}
+	if len(cfg.Key) != keySize {
+		return errors.NotValidf("key with size %d", len(cfg.Key))
+	}
+	return nil
+}
+
+// Backend stores each secret value in its own file, encrypted with
+// AES-GCM.
+type Backend struct {
+	path string
+	aead cipher.AEAD
+
+	// mu serialises writes and removals.
+	mu sync.Mutex
+}
+
+// NewBackend returns a backend storing values in the directory
+// specified by the config, creating the directory if necessary.
+func NewBackend(cfg Config) (*Backend, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := os.MkdirAll(cfg.Path, 0700); err != nil {
+		return nil, errors.Annotate(err, "creating secret backend directory")
+	}
+	block, err := aes.NewCipher(cfg.Key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Backend{path: cfg.Path, aead: aead}, nil
+}
+
+// Get returns the value stored with the key, or an error satisfying
+// errors.IsNotFound if there is none.
+func (b *Backend) Get(key string) (map[string]string, error) {
+	if key == "" {
+		return nil, errors.NotValidf("empty key")
+	}
+	data, err := ioutil.ReadFile(b.filename(key))
+	if os.IsNotExist(err) {
+		return nil, errors.NotFoundf("secret value %q", key)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	nonceSize := b.aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, errors.Errorf("secret value %q is corrupt", key)
+	}
+	plaintext, err := b.aead.Open(nil, data[:nonceSize], data[nonceSize:], []byte(key))
+	if err != nil {
+		return nil, errors.Annotatef(err, "decrypting secret value %q", key)
+	}
+	var value map[string]string
+	if err := json.Unmarshal(plaintext, &value); err != nil {
+		return nil, errors.Annotatef(err, "decoding secret value %q", key)
+	}
+	return value, nil
+}
+
+// Put stores the value with the key, replacing any existing value.
+func (b *Backend) Put(key string, value map[string]string) error {
+	if key == "" {
+		return errors.NotValidf("empty key")
+	}
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return errors.Trace(err)
+	}
+	// The key is authenticated along with the value so that
+	// encrypted values can't be swapped between files.
+	data := b.aead.Seal(nonce, nonce, plaintext, []byte(key))
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return errors.Trace(utils.AtomicWriteFile(b.filename(key), data, 0600))
+}
+
+// Delete removes the value stored with the key. It is not an error
+// if there is no such value.
+func (b *Backend) Delete(key string) error {
+	if key == "" {
+		return errors.NotValidf("empty key")
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := os.Remove(b.filename(key)); err != nil && !os.IsNotExist(err) {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// filename returns the name of the file holding the value for the
+// key. Keys are hashed so they can contain any characters without
+// escaping the backend directory.
+func (b *Backend) filename(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(b.path, hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,154 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package file_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/secretbackend/file"
+	"github.com/juju/juju/testing"
+)
+
+type backendSuite struct {
+	testing.BaseSuite
+
+	dir     string
+	key     []byte
+	backend *file.Backend
+}
+
+var _ = gc.Suite(&backendSuite{})
+
+func (s *backendSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.dir = filepath.Join(c.MkDir(), "secret-backend")
+	s.key = bytes.Repeat([]byte{1}, 32)
+	var err error
+	s.backend, err = file.NewBackend(file.Config{Path: s.dir, Key: s.key})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *backendSuite) TestNewBackendCreatesDir(c *gc.C) {
+	info, err := os.Stat(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Mode().Perm(), gc.Equals, os.FileMode(0700))
+	files, err := ioutil.ReadDir(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(files, gc.HasLen, 0)
+}
+
+func (s *backendSuite) TestNewBackendInvalidConfig(c *gc.C) {
+	_, err := file.NewBackend(file.Config{Key: s.key})
+	c.Assert(err, gc.ErrorMatches, "empty path not valid")
+	_, err = file.NewBackend(file.Config{Path: "relative", Key: s.key})
+	c.Assert(err, gc.ErrorMatches, `relative path "relative" not valid`)
+	_, err = file.NewBackend(file.Config{Path: s.dir})
+	c.Assert(err, gc.ErrorMatches, `key with size 0 not valid`)
+}
+
+func (s *backendSuite) TestPutGet(c *gc.C) {
+	err := s.backend.Put("cloudcredentials/foo", map[string]string{"password": "hunter2"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	got, err := s.backend.Get("cloudcredentials/foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, map[string]string{"password": "hunter2"})
+}
+
+func (s *backendSuite) TestValuesEncrypted(c *gc.C) {
+	err := s.backend.Put("foo", map[string]string{"password": "hunter2"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	files, err := ioutil.ReadDir(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(files, gc.HasLen, 1)
+	for _, f := range files {
+		data, err := ioutil.ReadFile(filepath.Join(s.dir, f.Name()))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(bytes.Contains(data, []byte("hunter2")), jc.IsFalse)
+		c.Assert(f.Mode().Perm(), gc.Equals, os.FileMode(0600))
+	}
+}
+
+func (s *backendSuite) TestReopen(c *gc.C) {
+	err := s.backend.Put("foo", map[string]string{"a": "1"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	backend, err := file.NewBackend(file.Config{Path: s.dir, Key: s.key})
+	c.Assert(err, jc.ErrorIsNil)
+	got, err := backend.Get("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, map[string]string{"a": "1"})
+}
+
+func (s *backendSuite) TestGetOtherKey(c *gc.C) {
+	err := s.backend.Put("foo", map[string]string{"a": "1"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	other, err := file.NewBackend(file.Config{
+		Path: filepath.Join(c.MkDir(), "other"),
+		Key:  bytes.Repeat([]byte{2}, 32),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	// Copy the encrypted value into a directory with a different key.
+	s.copyValues(c, other)
+	_, err = other.Get("foo")
+	c.Assert(err, gc.ErrorMatches, `decrypting secret value "foo": .*`)
+}
+
+func (s *backendSuite) copyValues(c *gc.C, other *file.Backend) {
+	files, err := ioutil.ReadDir(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, f := range files {
+		data, err := ioutil.ReadFile(filepath.Join(s.dir, f.Name()))
+		c.Assert(err, jc.ErrorIsNil)
+		err = ioutil.WriteFile(filepath.Join(file.BackendPath(other), f.Name()), data, 0600)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *backendSuite) TestPutReplaces(c *gc.C) {
+	err := s.backend.Put("foo", map[string]string{"a": "1"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.backend.Put("foo", map[string]string{"b": "2"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	got, err := s.backend.Get("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, map[string]string{"b": "2"})
+}
+
+func (s *backendSuite) TestGetNotFound(c *gc.C) {
+	_, err := s.backend.Get("missing")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `secret value "missing" not found`)
+}
+
+func (s *backendSuite) TestDelete(c *gc.C) {
+	err := s.backend.Put("foo", map[string]string{"a": "1"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.backend.Delete("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.backend.Get("foo")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.backend.Delete("foo")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *backendSuite) TestEmptyKey(c *gc.C) {
+	_, err := s.backend.Get("")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	err = s.backend.Put("", map[string]string{"a": "1"})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	err = s.backend.Delete("")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
@@ -0,0 +1,9 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package file
+
+// BackendPath returns the directory the backend stores values in.
+func BackendPath(b *Backend) string {
+	return b.path
+}
@@ -0,0 +1,157 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package vault stores secret values in a HashiCorp Vault compatible
+// KV (version 2) secrets engine, using its HTTP API.
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// requestTimeout is how long a single request may take.
+const requestTimeout = 30 * time.Second
+
+// Backend stores secret values in a KV secrets engine.
+type Backend struct {
+	cfg    Config
+	client *http.Client
+}
+
+// NewBackend returns a backend storing values in the KV secrets engine
+// described by the config. No connection is made until it's used.
+func NewBackend(cfg Config) (*Backend, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	tlsCfg, err := cfg.TLSConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsCfg != nil {
+		transport.TLSClientConfig = tlsCfg
+	}
+	return &Backend{
+		cfg: cfg,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   requestTimeout,
+		},
+	}, nil
+}
+
+// kvData is the body of KV version 2 read and write requests.
+type kvData struct {
+	Data map[string]string `json:"data"`
+}
+
+// kvReadResponse is the body of the response to a KV version 2 read.
+type kvReadResponse struct {
+	Data kvData `json:"data"`
+}
+
+// errorResponse is the body of an error response.
+type errorResponse struct {
+	Errors []string `json:"errors"`
+}
+
+// Get returns the value stored under the key, or an error satisfying
+// errors.IsNotFound if there's none.
+func (b *Backend) Get(key string) (map[string]string, error) {
+	resp, err := b.do(http.MethodGet, "data", key, nil)
+	if err != nil {
+		return nil, errors.Annotatef(err, "reading %q", key)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.NotFoundf("secret value %q", key)
+	}
+	if err := checkResponse(resp); err != nil {
+		return nil, errors.Annotatef(err, "reading %q", key)
+	}
+	var result kvReadResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, errors.Annotatef(err, "decoding %q", key)
+	}
+	return result.Data.Data, nil
+}
+
+// Put stores the value under the key, as a new version of any value
+// already stored there.
+func (b *Backend) Put(key string, value map[string]string) error {
+	body, err := json.Marshal(kvData{Data: value})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resp, err := b.do(http.MethodPost, "data", key, body)
+	if err != nil {
+		return errors.Annotatef(err, "writing %q", key)
+	}
+	defer resp.Body.Close()
+	return errors.Annotatef(checkResponse(resp), "writing %q", key)
+}
+
+// Delete removes all versions of the value stored under the key. It's
+// not an error if there's none.
+func (b *Backend) Delete(key string) error {
+	resp, err := b.do(http.MethodDelete, "metadata", key, nil)
+	if err != nil {
+		return errors.Annotatef(err, "deleting %q", key)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return errors.Annotatef(checkResponse(resp), "deleting %q", key)
+}
+
+// do sends a request to the KV secrets engine's data or metadata
+// endpoint for the key.
+func (b *Backend) do(method, endpoint, key string, body []byte) (*http.Response, error) {
+	if key == "" {
+		return nil, errors.NotValidf("empty key")
+	}
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	u := fmt.Sprintf("%s/v1/%s/%s/%s",
+		strings.TrimSuffix(b.cfg.Address, "/"), b.cfg.mount(), endpoint, strings.Join(segments, "/"))
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, u, reader)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	req.Header.Set("X-Vault-Token", b.cfg.Token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return b.client.Do(req)
+}
+
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	data, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+	var errResp errorResponse
+	if err := json.Unmarshal(data, &errResp); err == nil && len(errResp.Errors) > 0 {
+		return errors.Errorf("%s: %s", resp.Status, strings.Join(errResp.Errors, "; "))
+	}
+	return errors.Errorf("%s", resp.Status)
+}
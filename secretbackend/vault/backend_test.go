@@ -0,0 +1,111 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package vault_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/secretbackend/vault"
+	"github.com/juju/juju/secretbackend/vault/vaulttesting"
+	"github.com/juju/juju/testing"
+)
+
+type backendSuite struct {
+	testing.BaseSuite
+
+	server  *vaulttesting.Server
+	backend *vault.Backend
+}
+
+var _ = gc.Suite(&backendSuite{})
+
+func (s *backendSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.server = vaulttesting.NewServer("s3cret")
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+
+	var err error
+	s.backend, err = vault.NewBackend(vault.Config{
+		Address: s.server.URL,
+		Token:   "s3cret",
+		Mount:   "juju",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *backendSuite) TestPutGet(c *gc.C) {
+	err := s.backend.Put("cloudcredentials/foo", map[string]string{"password": "hunter2"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	value, ok := s.server.Value("juju/cloudcredentials/foo")
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(value, jc.DeepEquals, map[string]string{"password": "hunter2"})
+
+	got, err := s.backend.Get("cloudcredentials/foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, map[string]string{"password": "hunter2"})
+}
+
+func (s *backendSuite) TestPutNewVersion(c *gc.C) {
+	err := s.backend.Put("foo", map[string]string{"a": "1"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.backend.Put("foo", map[string]string{"a": "2"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.server.Versions("juju/foo"), gc.Equals, 2)
+
+	got, err := s.backend.Get("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(got, jc.DeepEquals, map[string]string{"a": "2"})
+}
+
+func (s *backendSuite) TestGetNotFound(c *gc.C) {
+	_, err := s.backend.Get("missing")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `secret value "missing" not found`)
+}
+
+func (s *backendSuite) TestDelete(c *gc.C) {
+	err := s.backend.Put("foo", map[string]string{"a": "1"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.backend.Delete("foo")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, ok := s.server.Value("juju/foo")
+	c.Assert(ok, jc.IsFalse)
+	_, err = s.backend.Get("foo")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *backendSuite) TestDeleteMissing(c *gc.C) {
+	err := s.backend.Delete("missing")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *backendSuite) TestEmptyKey(c *gc.C) {
+	_, err := s.backend.Get("")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	err = s.backend.Put("", map[string]string{"a": "1"})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	err = s.backend.Delete("")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *backendSuite) TestBadToken(c *gc.C) {
+	backend, err := vault.NewBackend(vault.Config{
+		Address: s.server.URL,
+		Token:   "wrong",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = backend.Get("foo")
+	c.Assert(err, gc.ErrorMatches, `.*403 Forbidden: permission denied`)
+	err = backend.Put("foo", map[string]string{"a": "1"})
+	c.Assert(err, gc.ErrorMatches, `.*403 Forbidden: permission denied`)
+}
+
+func (s *backendSuite) TestNewBackendInvalidConfig(c *gc.C) {
+	_, err := vault.NewBackend(vault.Config{Address: s.server.URL})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package vault
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/url"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/v2/cert"
+)
+
+// DefaultMount is the path the KV secrets engine is mounted at when
+// none is configured, as in a default Vault install.
+const DefaultMount = "secret"
+
+// Config holds the configuration for storing secret values in a
+// HashiCorp Vault compatible KV (version 2) secrets engine.
+type Config struct {
+	// Address is the URL of the server, for example
+	// https://vault.example.com:8200.
+	Address string
+
+	// Token authenticates requests to the server.
+	Token string
+
+	// Mount is the path the KV secrets engine is mounted at. It
+	// defaults to DefaultMount.
+	Mount string
+
+	// CACert is the TLS CA certificate (x.509, PEM-encoded) to use for
+	// validating the server certificate. If it is empty the system
+	// certificate pool is used.
+	CACert string
+}
+
+// Validate ensures that the config is currently valid.
+func (cfg Config) Validate() error {
+	if cfg.Address == "" {
+		return errors.NotValidf("empty address")
+	}
+	u, err := url.Parse(cfg.Address)
+	if err != nil {
+		return errors.NotValidf("address %q", cfg.Address)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.NotValidf("address scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return errors.NotValidf("address %q without host", cfg.Address)
+	}
+	if cfg.Token == "" {
+		return errors.NotValidf("empty token")
+	}
+	if _, err := cfg.TLSConfig(); err != nil {
+		return errors.Annotate(err, "validating TLS config")
+	}
+	return nil
+}
+
+func (cfg Config) mount() string {
+	if cfg.Mount == "" {
+		return DefaultMount
+	}
+	return cfg.Mount
+}
+
+// TLSConfig returns the TLS configuration for connecting to the
+// server. It returns nil if the system certificate pool should be used.
+func (cfg Config) TLSConfig() (*tls.Config, error) {
+	if cfg.CACert == "" {
+		return nil, nil
+	}
+	caCert, err := cert.ParseCert(cfg.CACert)
+	if err != nil {
+		return nil, errors.Annotate(err, "parsing CA certificate")
+	}
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(caCert)
+	return &tls.Config{
+		RootCAs: rootCAs,
+	}, nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package vault_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/secretbackend/vault"
+	"github.com/juju/juju/testing"
+)
+
+type configSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&configSuite{})
+
+func validConfig() vault.Config {
+	return vault.Config{
+		Address: "https://vault.example.com:8200",
+		Token:   "s3cret",
+	}
+}
+
+func (s *configSuite) TestValidate(c *gc.C) {
+	c.Assert(validConfig().Validate(), jc.ErrorIsNil)
+
+	cfg := validConfig()
+	cfg.CACert = testing.CACert
+	c.Assert(cfg.Validate(), jc.ErrorIsNil)
+}
+
+func (s *configSuite) TestValidateErrors(c *gc.C) {
+	for i, test := range []struct {
+		about  string
+		modify func(*vault.Config)
+		err    string
+	}{{
+		about:  "empty address",
+		modify: func(cfg *vault.Config) { cfg.Address = "" },
+		err:    "empty address not valid",
+	}, {
+		about:  "bad scheme",
+		modify: func(cfg *vault.Config) { cfg.Address = "ftp://vault.example.com" },
+		err:    `address scheme "ftp" not valid`,
+	}, {
+		about:  "missing host",
+		modify: func(cfg *vault.Config) { cfg.Address = "https://" },
+		err:    `address "https://" without host not valid`,
+	}, {
+		about:  "empty token",
+		modify: func(cfg *vault.Config) { cfg.Token = "" },
+		err:    "empty token not valid",
+	}, {
+		about:  "bad CA cert",
+		modify: func(cfg *vault.Config) { cfg.CACert = "not a cert" },
+		err:    "validating TLS config: .*",
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		cfg := validConfig()
+		test.modify(&cfg)
+		c.Check(cfg.Validate(), gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *configSuite) TestTLSConfig(c *gc.C) {
+	tlsConfig, err := validConfig().TLSConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tlsConfig, gc.IsNil)
+
+	cfg := validConfig()
+	cfg.CACert = testing.CACert
+	tlsConfig, err = cfg.TLSConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tlsConfig.RootCAs, gc.NotNil)
+}
@@ -0,0 +1,121 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package vaulttesting provides an in-process fake of a HashiCorp Vault
+// compatible KV (version 2) secrets engine, for testing.
+package vaulttesting
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Server is a fake KV version 2 secrets engine. It accepts requests
+// made with its token to any mount, and keeps all versions of the
+// values written to it in memory.
+type Server struct {
+	*httptest.Server
+
+	// Token is the token requests must be made with.
+	Token string
+
+	mu       sync.Mutex
+	versions map[string][]map[string]string
+}
+
+// NewServer starts and returns a new fake KV server accepting requests
+// made with the given token. The server should be closed when it's no
+// longer needed.
+func NewServer(token string) *Server {
+	s := &Server{
+		Token:    token,
+		versions: make(map[string][]map[string]string),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Value returns the latest version of the value stored at the path,
+// including the mount, for example "secret/foo".
+func (s *Server) Value(path string) (map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	versions := s.versions[path]
+	if len(versions) == 0 {
+		return nil, false
+	}
+	return versions[len(versions)-1], true
+}
+
+// Versions returns the number of versions of the value stored at the
+// path, including the mount.
+func (s *Server) Versions(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.versions[path])
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Header.Get("X-Vault-Token") != s.Token {
+		writeError(w, http.StatusForbidden, "permission denied")
+		return
+	}
+	// Paths are /v1/<mount>/data/<key> or /v1/<mount>/metadata/<key>.
+	parts := strings.SplitN(strings.TrimPrefix(req.URL.Path, "/v1/"), "/", 3)
+	if len(parts) != 3 || parts[2] == "" {
+		writeError(w, http.StatusNotFound, "")
+		return
+	}
+	path := parts[0] + "/" + parts[2]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch endpoint := parts[1]; {
+	case endpoint == "data" && req.Method == http.MethodGet:
+		versions := s.versions[path]
+		if len(versions) == 0 {
+			writeError(w, http.StatusNotFound, "")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{
+				"data":     versions[len(versions)-1],
+				"metadata": map[string]interface{}{"version": len(versions)},
+			},
+		})
+	case endpoint == "data" && (req.Method == http.MethodPost || req.Method == http.MethodPut):
+		var body struct {
+			Data map[string]string `json:"data"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil || body.Data == nil {
+			writeError(w, http.StatusBadRequest, "no data provided")
+			return
+		}
+		s.versions[path] = append(s.versions[path], body.Data)
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{"version": len(s.versions[path])},
+		})
+	case endpoint == "metadata" && req.Method == http.MethodDelete:
+		delete(s.versions, path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "unsupported operation")
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	errs := []string{}
+	if message != "" {
+		errs = append(errs, message)
+	}
+	writeJSON(w, status, map[string]interface{}{"errors": errs})
+}
@@ -407,7 +407,18 @@ func (a *action) removeAndLogBuildTxn(finalStatus ActionStatus, results map[stri
 					numComplete++
 				}
 			}
-			if numComplete == len(tasks)-1 {
+			// A rolling operation is only complete once all
+			// of its tasks have been released.
+			allReleased := true
+			operationAssert := assertNotComplete
+			if rollout := parentOperation.(*operation).doc.Rollout; rollout != nil {
+				allReleased = rollout.Released == len(rollout.Tasks)
+				if rollout.hasErrors() {
+					statusStats.Add(string(ActionFailed))
+				}
+				operationAssert = append(bson.D{{"rollout.released", rollout.Released}}, assertNotComplete...)
+			}
+			if numComplete == len(tasks)-1 && allReleased {
 				// Set the operation status based on the individual
 				// task status values. eg if any task is failed,
 				// the entire operation is considered failed.
@@ -421,7 +432,7 @@ func (a *action) removeAndLogBuildTxn(finalStatus ActionStatus, results map[stri
 				updateOperationOp = &txn.Op{
 					C:      operationsC,
 					Id:     a.st.docID(parentOperation.Id()),
-					Assert: assertNotComplete,
+					Assert: operationAssert,
 					Update: bson.D{{"$set", bson.D{
 						{"status", finalOperationStatus},
 						{"completed", completedTime},
@@ -740,34 +751,8 @@ func (st *State) matchingActionsByReceiverAndStatus(tag names.Tag, statusConditi
 // only logs newer than <maxLogTime> remain and also ensures
 // that the actions collection is smaller than <maxLogsMB> after the deletion.
 func PruneOperations(st *State, maxHistoryTime time.Duration, maxHistoryMB int) error {
-	// There may be older actions without parent operations so try those first.
-	hasNoOperation := bson.D{{"$or", []bson.D{
-		{{"operation", ""}},
-		{{"operation", bson.D{{"$exists", false}}}},
-	}}}
-	err := pruneCollection(st, maxHistoryTime, maxHistoryMB, actionsC, "completed", hasNoOperation, GoTime)
-	if err != nil {
-		return errors.Trace(err)
-	}
-	// First calculate the average ratio of tasks to operations. Since deletion is
-	// done at the operation level, and any associated tasks are then deleted, but
-	// the actions collection is where the disk space goes, we approximate the
-	// number of operations to delete to achieve a given size deduction based on
-	// the average ratio of number of operations to tasks.
-	operationsColl, closer := st.db().GetRawCollection(operationsC)
-	defer closer()
-	operationsCount, err := operationsColl.Count()
-	if err != nil {
-		return errors.Annotate(err, "retrieving operations collection count")
-	}
-	actionsColl, closer := st.db().GetRawCollection(actionsC)
-	defer closer()
-	actionsCount, err := actionsColl.Count()
-	if err != nil {
-		return errors.Annotate(err, "retrieving actions collection count")
-	}
-	sizeFactor := float64(actionsCount) / float64(operationsCount)
-
-	err = pruneCollectionAndChildren(st, maxHistoryTime, maxHistoryMB, operationsC, "completed", actionsC, "operation", nil, sizeFactor, GoTime)
-	return errors.Trace(err)
+	return PruneOperationsWithRetention(st, OperationRetention{
+		MaxAge:    maxHistoryTime,
+		MaxSizeMB: maxHistoryMB,
+	})
 }
@@ -0,0 +1,522 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+	"gopkg.in/robfig/cron.v2"
+)
+
+// minScheduleInterval is the shortest time allowed between two runs
+// of a recurring action schedule.
+const minScheduleInterval = time.Minute
+
+// ScheduledTask describes an action run on a receiver each time an
+// action schedule fires.
+type ScheduledTask struct {
+	// Receiver is the tag of the unit or machine to run the action on.
+	// It may also be the tag of an application, to run the action on
+	// every unit of the application, or "<application>/leader" to run
+	// it on the leader. These are resolved each time a run is scheduled.
+	Receiver string
+
+	Name       string
+	Parameters map[string]interface{}
+}
+
+// ActionScheduleArgs holds the arguments for adding an action schedule.
+type ActionScheduleArgs struct {
+	// Summary describes the schedule. It is used in the summary of
+	// each operation started by the schedule.
+	Summary string
+
+	// At is the time of the first run. If it is zero, the first run
+	// is the first time matching Recurrence.
+	At time.Time
+
+	// Recurrence is a cron spec, such as "0 2 * * *", or descriptor,
+	// such as "@daily", for when the tasks are run again. The schedule
+	// is interpreted in UTC. If it is empty, the tasks are only run once.
+	Recurrence string
+
+	Tasks []ScheduledTask
+}
+
+// Validate returns an error if the arguments are not valid.
+func (args ActionScheduleArgs) Validate() error {
+	if args.At.IsZero() && args.Recurrence == "" {
+		return errors.NotValidf("action schedule without a time or recurrence")
+	}
+	if args.Recurrence != "" {
+		schedule, err := cron.Parse(args.Recurrence)
+		if err != nil {
+			return errors.NotValidf("recurrence %q", args.Recurrence)
+		}
+		// Check a handful of runs, as not all intervals of a
+		// recurrence are the same.
+		last := schedule.Next(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+		for i := 0; i < 10; i++ {
+			next := schedule.Next(last)
+			if next.Sub(last) < minScheduleInterval {
+				return errors.NotValidf("recurrence %q more often than every %v", args.Recurrence, minScheduleInterval)
+			}
+			last = next
+		}
+	}
+	if len(args.Tasks) == 0 {
+		return errors.NotValidf("action schedule without tasks")
+	}
+	return nil
+}
+
+type actionScheduleDoc struct {
+	DocId      string             `bson:"_id"`
+	ModelUUID  string             `bson:"model-uuid"`
+	Summary    string             `bson:"summary"`
+	Recurrence string             `bson:"recurrence,omitempty"`
+	Tasks      []scheduledTaskDoc `bson:"tasks"`
+	Next       time.Time          `bson:"next"`
+	Operation  string             `bson:"operation"`
+	Run        []scheduledRunTask `bson:"run,omitempty"`
+}
+
+type scheduledTaskDoc struct {
+	Receiver   string                 `bson:"receiver"`
+	Name       string                 `bson:"name"`
+	Parameters map[string]interface{} `bson:"parameters,omitempty"`
+}
+
+// scheduledRunTask records a task of the next run of a schedule; it
+// is held back from its receiver until the run is due.
+type scheduledRunTask struct {
+	ActionID string `bson:"action-id"`
+	Receiver string `bson:"receiver"`
+}
+
+// nextRun returns the time of the run of the schedule after the given
+// time, or the zero time if the schedule doesn't recur.
+func (doc *actionScheduleDoc) nextRun(after time.Time) (time.Time, error) {
+	if doc.Recurrence == "" {
+		return time.Time{}, nil
+	}
+	schedule, err := cron.Parse(doc.Recurrence)
+	if err != nil {
+		return time.Time{}, errors.Annotatef(err, "parsing recurrence %q", doc.Recurrence)
+	}
+	return schedule.Next(after.UTC()), nil
+}
+
+// AddActionSchedule records a schedule for running actions at a later
+// time, and possibly again on a recurring basis. Each run is recorded
+// as an operation as soon as it is scheduled, with a pending task for
+// each receiver which isn't released until the run is due. Cancelling
+// all of the tasks of a scheduled run cancels the schedule.
+//
+// It returns the id of the operation for the first run.
+func (m *Model) AddActionSchedule(args ActionScheduleArgs) (string, error) {
+	if err := args.Validate(); err != nil {
+		return "", errors.Trace(err)
+	}
+	now := m.st.clock().Now()
+	if !args.At.IsZero() && !args.At.After(now) {
+		return "", errors.NotValidf("action schedule time %v in the past", args.At.UTC().Format(time.RFC3339))
+	}
+
+	id, err := sequence(m.st, "actionschedule")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	doc := &actionScheduleDoc{
+		DocId:      m.st.docID(strconv.Itoa(id)),
+		ModelUUID:  m.st.ModelUUID(),
+		Summary:    args.Summary,
+		Recurrence: args.Recurrence,
+		Tasks:      make([]scheduledTaskDoc, len(args.Tasks)),
+		Next:       args.At.UTC(),
+	}
+	for i, task := range args.Tasks {
+		doc.Tasks[i] = scheduledTaskDoc{
+			Receiver:   task.Receiver,
+			Name:       task.Name,
+			Parameters: task.Parameters,
+		}
+	}
+	if doc.Next.IsZero() {
+		if doc.Next, err = doc.nextRun(now); err != nil {
+			return "", errors.Trace(err)
+		}
+	}
+
+	// Every task must be runnable when the schedule is added; later
+	// runs skip the receivers which have since gone away.
+	runOps, operationID, run, err := m.scheduleRunOps(doc, true)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	doc.Operation = operationID
+	doc.Run = run
+	ops := append([]txn.Op{{
+		C:      actionSchedulesC,
+		Id:     doc.DocId,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}}, runOps...)
+	if err := m.st.db().RunTransaction(ops); err != nil {
+		return "", errors.Trace(err)
+	}
+	return operationID, nil
+}
+
+// scheduleRunOps returns the operations needed to record the next run
+// of the schedule, along with the id of its operation and its tasks. If
+// strict is true, any task which cannot be scheduled is an error;
+// otherwise it is skipped.
+func (m *Model) scheduleRunOps(doc *actionScheduleDoc, strict bool) ([]txn.Op, string, []scheduledRunTask, error) {
+	agentVersion, err := m.AgentVersion()
+	if err != nil {
+		return nil, "", nil, errors.Trace(err)
+	}
+
+	type runTask struct {
+		receiver names.Tag
+		task     scheduledTaskDoc
+		payload  map[string]interface{}
+	}
+	var tasks []runTask
+	for _, task := range doc.Tasks {
+		receivers, err := m.st.resolveScheduledReceiver(task.Receiver)
+		if err == nil && len(receivers) == 0 {
+			err = errors.Errorf("no units of %s", task.Receiver)
+		}
+		if err != nil {
+			if strict {
+				return nil, "", nil, errors.Trace(err)
+			}
+			logger.Warningf("skipping %q for schedule %q: %v", task.Name, m.st.localID(doc.DocId), err)
+			continue
+		}
+		for _, receiver := range receivers {
+			payload, err := m.st.prepareRolloutTask(RolloutTask{
+				Receiver:   receiver,
+				Name:       task.Name,
+				Parameters: task.Parameters,
+			})
+			if err != nil {
+				if strict {
+					return nil, "", nil, errors.Trace(err)
+				}
+				logger.Warningf("skipping %q on %s for schedule %q: %v",
+					task.Name, names.ReadableString(receiver), m.st.localID(doc.DocId), err)
+				continue
+			}
+			tasks = append(tasks, runTask{receiver: receiver, task: task, payload: payload})
+		}
+	}
+	if len(tasks) == 0 {
+		return nil, "", nil, nil
+	}
+
+	summary := fmt.Sprintf("%s scheduled for %s", doc.Summary, doc.Next.UTC().Format(time.RFC3339))
+	operationDoc, operationID, err := newOperationDoc(m.st, summary)
+	if err != nil {
+		return nil, "", nil, errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      operationsC,
+		Id:     operationDoc.DocId,
+		Assert: txn.DocMissing,
+		Insert: operationDoc,
+	}}
+	run := make([]scheduledRunTask, len(tasks))
+	for i, task := range tasks {
+		// The notification is only added when the run is due,
+		// which is what makes the task visible to its receiver.
+		actionDoc, _, err := newActionDoc(m.st, operationID, task.receiver, task.task.Name, task.payload, agentVersion)
+		if err != nil {
+			return nil, "", nil, errors.Trace(err)
+		}
+		ops = append(ops, txn.Op{
+			C:      actionsC,
+			Id:     actionDoc.DocId,
+			Assert: txn.DocMissing,
+			Insert: actionDoc,
+		})
+		run[i] = scheduledRunTask{
+			ActionID: m.st.localID(actionDoc.DocId),
+			Receiver: task.receiver.String(),
+		}
+	}
+	return ops, operationID, run, nil
+}
+
+// resolveScheduledReceiver returns the tags of the receivers to run a
+// scheduled task on.
+func (st *State) resolveScheduledReceiver(receiver string) ([]names.Tag, error) {
+	if strings.HasSuffix(receiver, "/leader") {
+		appName := strings.TrimSuffix(receiver, "/leader")
+		leaders, err := st.ApplicationLeaders()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		leader, ok := leaders[appName]
+		if !ok {
+			return nil, errors.Errorf("could not determine leader for %q", appName)
+		}
+		return []names.Tag{names.NewUnitTag(leader)}, nil
+	}
+	tag, err := names.ParseTag(receiver)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	appTag, ok := tag.(names.ApplicationTag)
+	if !ok {
+		return []names.Tag{tag}, nil
+	}
+	app, err := st.Application(appTag.Id())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	units, err := app.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []names.Tag
+	for _, unit := range units {
+		if unit.Life() == Alive {
+			result = append(result, unit.Tag())
+		}
+	}
+	return result, nil
+}
+
+// RunActionSchedules releases the tasks of any scheduled runs which are
+// due, schedules the next run of recurring schedules and removes those
+// which have finished or been cancelled. It returns the time of the
+// next scheduled run, or the zero time if there are none.
+func (m *Model) RunActionSchedules() (time.Time, error) {
+	schedules, closer := m.st.db().GetCollection(actionSchedulesC)
+	defer closer()
+
+	var docs []actionScheduleDoc
+	if err := schedules.Find(nil).Select(bson.D{{"_id", 1}}).All(&docs); err != nil {
+		return time.Time{}, errors.Annotate(err, "cannot get action schedules")
+	}
+	var next time.Time
+	for _, doc := range docs {
+		id := m.st.localID(doc.DocId)
+		due, err := m.runActionSchedule(id)
+		if err != nil {
+			return time.Time{}, errors.Annotatef(err, "running action schedule %q", id)
+		}
+		if !due.IsZero() && (next.IsZero() || due.Before(next)) {
+			next = due
+		}
+	}
+	return next, nil
+}
+
+func (m *Model) actionSchedule(id string) (*actionScheduleDoc, error) {
+	schedules, closer := m.st.db().GetCollection(actionSchedulesC)
+	defer closer()
+
+	var doc actionScheduleDoc
+	err := schedules.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("action schedule %q", id)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get action schedule %q", id)
+	}
+	return &doc, nil
+}
+
+func (m *Model) runActionSchedule(id string) (time.Time, error) {
+	var (
+		due   time.Time
+		again bool
+		dead  []string
+	)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		due, again, dead = time.Time{}, false, nil
+		doc, err := m.actionSchedule(id)
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		now := m.st.clock().Now()
+		assertUnchanged := bson.D{{"operation", doc.Operation}, {"next", doc.Next}}
+
+		if doc.Operation == "" {
+			if now.Before(doc.Next) {
+				runOps, operationID, run, err := m.scheduleRunOps(doc, false)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				due = doc.Next
+				if operationID == "" {
+					// Nothing to run at the moment; try again
+					// later in case that changes.
+					return nil, jujutxn.ErrNoOperations
+				}
+				return append(runOps, txn.Op{
+					C:      actionSchedulesC,
+					Id:     doc.DocId,
+					Assert: assertUnchanged,
+					Update: bson.D{{"$set", bson.D{
+						{"operation", operationID},
+						{"run", run},
+					}}},
+				}), nil
+			}
+			// The run was missed, as there was nothing to run.
+			again = true
+			return m.nextRunOps(doc, now, assertUnchanged)
+		}
+
+		operation, _, err := m.st.getOperationDoc(doc.Operation)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		if err != nil || operation.Status != ActionPending {
+			// The tasks of the run have all been cancelled,
+			// which cancels the schedule.
+			return []txn.Op{{
+				C:      actionSchedulesC,
+				Id:     doc.DocId,
+				Assert: assertUnchanged,
+				Remove: true,
+			}}, nil
+		}
+		if now.Before(doc.Next) {
+			due = doc.Next
+			return nil, jujutxn.ErrNoOperations
+		}
+
+		actions, err := m.st.operationActionDocs(doc.Operation)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var ops []txn.Op
+		for _, task := range doc.Run {
+			if action, ok := actions[task.ActionID]; !ok || action.Status != ActionPending {
+				// Cancelled before the run was due.
+				continue
+			}
+			receiver, err := names.ParseTag(task.Receiver)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			taskOps, err := m.releaseScheduledTaskOps(task.ActionID, receiver)
+			if err == errReceiverGone {
+				dead = append(dead, task.ActionID)
+				continue
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, taskOps...)
+		}
+		nextOps, err := m.nextRunOps(doc, now, assertUnchanged)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		again = true
+		return append(ops, nextOps...), nil
+	}
+	if err := m.st.db().Run(buildTxn); err != nil {
+		return time.Time{}, errors.Trace(err)
+	}
+	for _, actionID := range dead {
+		action, err := m.Action(actionID)
+		if err != nil {
+			return time.Time{}, errors.Trace(err)
+		}
+		if _, err := action.Finish(ActionResults{
+			Status:  ActionFailed,
+			Message: "receiver is no longer alive",
+		}); err != nil {
+			return time.Time{}, errors.Trace(err)
+		}
+	}
+	if again {
+		// Schedule the next run, if there is one.
+		return m.runActionSchedule(id)
+	}
+	return due, nil
+}
+
+var errReceiverGone = errors.New("receiver is no longer alive")
+
+// releaseScheduledTaskOps returns the operations needed to make a
+// scheduled task visible to its receiver.
+func (m *Model) releaseScheduledTaskOps(actionID string, receiver names.Tag) ([]txn.Op, error) {
+	collectionName, receiverID, err := m.st.tagToCollectionAndId(receiver)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	notDead, err := isNotDead(m.st, collectionName, receiverID)
+	if errors.IsNotFound(err) || (err == nil && !notDead) {
+		return nil, errReceiverGone
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ndoc := actionNotificationDoc{
+		DocId:     m.st.docID(ensureActionMarker(receiver.Id()) + actionID),
+		ModelUUID: m.st.ModelUUID(),
+		Receiver:  receiver.Id(),
+		ActionID:  actionID,
+	}
+	return []txn.Op{{
+		C:      collectionName,
+		Id:     receiverID,
+		Assert: notDeadDoc,
+	}, {
+		C:      actionNotificationsC,
+		Id:     ndoc.DocId,
+		Assert: txn.DocMissing,
+		Insert: ndoc,
+	}}, nil
+}
+
+// nextRunOps returns the operations needed to move the schedule on to
+// its next run after the given time, or to remove it if it doesn't recur.
+func (m *Model) nextRunOps(doc *actionScheduleDoc, after time.Time, assert bson.D) ([]txn.Op, error) {
+	next, err := doc.nextRun(after)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if next.IsZero() {
+		return []txn.Op{{
+			C:      actionSchedulesC,
+			Id:     doc.DocId,
+			Assert: assert,
+			Remove: true,
+		}}, nil
+	}
+	return []txn.Op{{
+		C:      actionSchedulesC,
+		Id:     doc.DocId,
+		Assert: assert,
+		Update: bson.D{
+			{"$set", bson.D{{"next", next}, {"operation", ""}}},
+			{"$unset", bson.D{{"run", nil}}},
+		},
+	}}, nil
+}
+
+// WatchActionSchedules returns a watcher which notifies when the action
+// schedules of the model change.
+func (st *State) WatchActionSchedules() NotifyWatcher {
+	return newNotifyCollWatcher(st, actionSchedulesC, isLocalID(st))
+}
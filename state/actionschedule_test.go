@@ -0,0 +1,238 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/clock/testclock"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type ActionScheduleSuite struct {
+	ConnSuite
+	clock *testclock.Clock
+	units []*state.Unit
+}
+
+var _ = gc.Suite(&ActionScheduleSuite{})
+
+func (s *ActionScheduleSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	// Start on the hour so that recurrences are easy to follow.
+	s.clock = testclock.NewClock(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC))
+	err := s.State.SetClockForTesting(s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+
+	charm := s.AddTestingCharm(c, "dummy")
+	application := s.AddTestingApplication(c, "dummy", charm)
+	s.units = nil
+	for i := 0; i < 2; i++ {
+		unit, err := application.AddUnit(state.AddUnitParams{})
+		c.Assert(err, jc.ErrorIsNil)
+		s.units = append(s.units, unit)
+	}
+}
+
+func (s *ActionScheduleSuite) actions(c *gc.C, operationID string) []state.Action {
+	info, err := s.Model.OperationWithActions(operationID)
+	c.Assert(err, jc.ErrorIsNil)
+	return info.Actions
+}
+
+func (s *ActionScheduleSuite) operationIDs(c *gc.C) []string {
+	operations, err := s.Model.AllOperations()
+	c.Assert(err, jc.ErrorIsNil)
+	var ids []string
+	for _, operation := range operations {
+		ids = append(ids, operation.Id())
+	}
+	return ids
+}
+
+func (s *ActionScheduleSuite) TestAddActionScheduleInvalid(c *gc.C) {
+	tasks := []state.ScheduledTask{{Receiver: s.units[0].Tag().String(), Name: "snapshot"}}
+	for i, test := range []struct {
+		args     state.ActionScheduleArgs
+		errMatch string
+	}{{
+		args:     state.ActionScheduleArgs{Tasks: tasks},
+		errMatch: "action schedule without a time or recurrence not valid",
+	}, {
+		args:     state.ActionScheduleArgs{Recurrence: "every day", Tasks: tasks},
+		errMatch: `recurrence "every day" not valid`,
+	}, {
+		args:     state.ActionScheduleArgs{Recurrence: "@every 10s", Tasks: tasks},
+		errMatch: `recurrence "@every 10s" more often than every 1m0s not valid`,
+	}, {
+		args:     state.ActionScheduleArgs{Recurrence: "@daily"},
+		errMatch: "action schedule without tasks not valid",
+	}, {
+		args:     state.ActionScheduleArgs{At: s.clock.Now().Add(-time.Hour), Tasks: tasks},
+		errMatch: "action schedule time 2021-05-31T23:00:00Z in the past not valid",
+	}, {
+		args: state.ActionScheduleArgs{Recurrence: "@daily", Tasks: []state.ScheduledTask{{
+			Receiver: s.units[0].Tag().String(),
+			Name:     "missing",
+		}}},
+		errMatch: `action "missing" not defined on unit "dummy/0"`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := s.Model.AddActionSchedule(test.args)
+		c.Check(err, gc.ErrorMatches, test.errMatch)
+	}
+}
+
+func (s *ActionScheduleSuite) TestRunOnce(c *gc.C) {
+	w := s.units[0].WatchPendingActionNotifications()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChange()
+
+	operationID, err := s.Model.AddActionSchedule(state.ActionScheduleArgs{
+		Summary: "snapshot run on dummy/0",
+		At:      s.clock.Now().Add(time.Hour),
+		Tasks:   []state.ScheduledTask{{Receiver: s.units[0].Tag().String(), Name: "snapshot"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	operation, err := s.Model.Operation(operationID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(operation.Summary(), gc.Equals, "snapshot run on dummy/0 scheduled for 2021-06-01T01:00:00Z")
+	c.Assert(operation.Status(), gc.Equals, state.ActionPending)
+	actions := s.actions(c, operationID)
+	c.Assert(actions, gc.HasLen, 1)
+
+	// The task isn't visible to the unit until the run is due.
+	due, err := s.Model.RunActionSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(due, gc.Equals, s.clock.Now().Add(time.Hour))
+	wc.AssertNoChange()
+
+	s.clock.Advance(time.Hour)
+	due, err = s.Model.RunActionSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(due.IsZero(), jc.IsTrue)
+	wc.AssertChange(actions[0].Id())
+	c.Assert(s.operationIDs(c), jc.DeepEquals, []string{operationID})
+}
+
+func (s *ActionScheduleSuite) TestRecurring(c *gc.C) {
+	operationID, err := s.Model.AddActionSchedule(state.ActionScheduleArgs{
+		Summary:    "snapshot run on dummy",
+		Recurrence: "0 * * * *",
+		Tasks:      []state.ScheduledTask{{Receiver: "application-dummy", Name: "snapshot"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.actions(c, operationID), gc.HasLen, 2)
+
+	s.clock.Advance(time.Hour)
+	due, err := s.Model.RunActionSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(due, gc.Equals, s.clock.Now().Add(time.Hour))
+
+	// The next run is scheduled as soon as the last one is released.
+	ids := s.operationIDs(c)
+	c.Assert(ids, gc.HasLen, 2)
+	next, err := s.Model.Operation(ids[1])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(next.Summary(), gc.Equals, "snapshot run on dummy scheduled for 2021-06-01T02:00:00Z")
+	c.Assert(s.actions(c, ids[1]), gc.HasLen, 2)
+}
+
+func (s *ActionScheduleSuite) TestCancelTasksCancelsSchedule(c *gc.C) {
+	operationID, err := s.Model.AddActionSchedule(state.ActionScheduleArgs{
+		Summary:    "snapshot run on dummy",
+		Recurrence: "@hourly",
+		Tasks:      []state.ScheduledTask{{Receiver: "application-dummy", Name: "snapshot"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	for _, action := range s.actions(c, operationID) {
+		_, err := action.Cancel()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	operation, err := s.Model.Operation(operationID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(operation.Status(), gc.Equals, state.ActionCancelled)
+
+	due, err := s.Model.RunActionSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(due.IsZero(), jc.IsTrue)
+
+	s.clock.Advance(time.Hour)
+	_, err = s.Model.RunActionSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.operationIDs(c), jc.DeepEquals, []string{operationID})
+}
+
+func (s *ActionScheduleSuite) TestCancelOneTask(c *gc.C) {
+	w := s.units[1].WatchPendingActionNotifications()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChange()
+
+	operationID, err := s.Model.AddActionSchedule(state.ActionScheduleArgs{
+		Summary: "snapshot run on dummy",
+		At:      s.clock.Now().Add(time.Minute),
+		Tasks:   []state.ScheduledTask{{Receiver: "application-dummy", Name: "snapshot"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	actions := s.actions(c, operationID)
+	c.Assert(actions, gc.HasLen, 2)
+	var released string
+	for _, action := range actions {
+		if action.Receiver() == s.units[1].Name() {
+			released = action.Id()
+			continue
+		}
+		_, err = action.Cancel()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	s.clock.Advance(time.Minute)
+	_, err = s.Model.RunActionSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange(released)
+	operation, err := s.Model.Operation(operationID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(operation.Status(), gc.Equals, state.ActionPending)
+}
+
+func (s *ActionScheduleSuite) TestDeadReceiverFails(c *gc.C) {
+	operationID, err := s.Model.AddActionSchedule(state.ActionScheduleArgs{
+		Summary: "snapshot run on dummy/0",
+		At:      s.clock.Now().Add(time.Minute),
+		Tasks:   []state.ScheduledTask{{Receiver: s.units[0].Tag().String(), Name: "snapshot"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.clock.Advance(time.Minute)
+	_, err = s.Model.RunActionSchedules()
+	c.Assert(err, jc.ErrorIsNil)
+	actions := s.actions(c, operationID)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(actions[0].Status(), gc.Equals, state.ActionFailed)
+	operation, err := s.Model.Operation(operationID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(operation.Status(), gc.Equals, state.ActionFailed)
+}
+
+func (s *ActionScheduleSuite) TestWatchActionSchedules(c *gc.C) {
+	w := s.State.WatchActionSchedules()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	_, err := s.Model.AddActionSchedule(state.ActionScheduleArgs{
+		Recurrence: "@daily",
+		Tasks:      []state.ScheduledTask{{Receiver: s.units[0].Tag().String(), Name: "snapshot"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
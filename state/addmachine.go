@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/quota"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/storage"
 )
@@ -107,21 +108,17 @@ type HostFilesystemParams struct {
 // of the given type inside another new machine. The two given templates
 // specify the form of the child and parent respectively.
 func (st *State) AddMachineInsideNewMachine(template, parentTemplate MachineTemplate, containerType instance.ContainerType) (*Machine, error) {
-	mdoc, ops, err := st.addMachineInsideNewMachineOps(template, parentTemplate, containerType)
-	if err != nil {
-		return nil, errors.Annotate(err, "cannot add a new machine")
-	}
-	return st.addMachine(mdoc, ops)
+	return st.addMachine(func() (*machineDoc, []txn.Op, error) {
+		return st.addMachineInsideNewMachineOps(template, parentTemplate, containerType)
+	})
 }
 
 // AddMachineInsideMachine adds a machine inside a container of the
 // given type on the existing machine with id=parentId.
 func (st *State) AddMachineInsideMachine(template MachineTemplate, parentId string, containerType instance.ContainerType) (*Machine, error) {
-	mdoc, ops, err := st.addMachineInsideMachineOps(template, parentId, containerType)
-	if err != nil {
-		return nil, errors.Annotate(err, "cannot add a new machine")
-	}
-	return st.addMachine(mdoc, ops)
+	return st.addMachine(func() (*machineDoc, []txn.Op, error) {
+		return st.addMachineInsideMachineOps(template, parentId, containerType)
+	})
 }
 
 // AddMachine adds a machine with the given series and jobs.
@@ -152,44 +149,68 @@ func (st *State) AddOneMachine(template MachineTemplate) (*Machine, error) {
 func (st *State) AddMachines(templates ...MachineTemplate) (_ []*Machine, err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot add a new machine")
 	var ms []*Machine
-	var ops []txn.Op
-	var controllerIds []string
-	for _, template := range templates {
-		mdoc, addOps, err := st.addMachineOps(template)
-		if err != nil {
-			return nil, errors.Trace(err)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := checkModelActive(st); err != nil {
+				return nil, errors.Trace(err)
+			}
 		}
-		if isController(mdoc) {
-			controllerIds = append(controllerIds, mdoc.Id)
+		// Each machine is checked against the quotas as its ops are
+		// built, but they're all added at once.
+		var ops []txn.Op
+		if len(templates) > 1 {
+			quotaOps, err := st.quotaOps(quota.Usage{quota.Machines: uint64(len(templates))})
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, quotaOps...)
 		}
-		ms = append(ms, newMachine(st, mdoc))
-		ops = append(ops, addOps...)
-	}
-	ssOps, err := st.maintainControllersOps(controllerIds, true)
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-	ops = append(ops, ssOps...)
-	ops = append(ops, assertModelActiveOp(st.ModelUUID()))
-	if err := st.db().RunTransaction(ops); err != nil {
-		if errors.Cause(err) == txn.ErrAborted {
-			if err := checkModelActive(st); err != nil {
+		ms = nil
+		var controllerIds []string
+		for _, template := range templates {
+			mdoc, addOps, err := st.addMachineOps(template)
+			if err != nil {
 				return nil, errors.Trace(err)
 			}
+			if isController(mdoc) {
+				controllerIds = append(controllerIds, mdoc.Id)
+			}
+			ms = append(ms, newMachine(st, mdoc))
+			ops = append(ops, addOps...)
 		}
+		ssOps, err := st.maintainControllersOps(controllerIds, true)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, ssOps...)
+		ops = append(ops, assertModelActiveOp(st.ModelUUID()))
+		return ops, nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
 		return nil, errors.Trace(err)
 	}
 	return ms, nil
 }
 
-func (st *State) addMachine(mdoc *machineDoc, ops []txn.Op) (*Machine, error) {
-	ops = append([]txn.Op{assertModelActiveOp(st.ModelUUID())}, ops...)
-	if err := st.db().RunTransaction(ops); err != nil {
-		if errors.Cause(err) == txn.ErrAborted {
+// addMachine runs the operations returned by addOps to add a machine,
+// building them again if the transaction is aborted.
+func (st *State) addMachine(addOps func() (*machineDoc, []txn.Op, error)) (*Machine, error) {
+	var mdoc *machineDoc
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
 			if err := checkModelActive(st); err != nil {
 				return nil, errors.Trace(err)
 			}
 		}
+		var ops []txn.Op
+		var err error
+		mdoc, ops, err = addOps()
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot add a new machine")
+		}
+		return append([]txn.Op{assertModelActiveOp(st.ModelUUID())}, ops...), nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
 		return nil, errors.Trace(err)
 	}
 	return newMachine(st, mdoc), nil
@@ -260,6 +281,10 @@ func (st *State) addMachineOps(template MachineTemplate) (*machineDoc, []txn.Op,
 	if err != nil {
 		return nil, nil, err
 	}
+	quotaOps, err := st.quotaOps(quota.Usage{quota.Machines: 1})
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
 	if template.InstanceId == "" {
 		volumeAttachments, err := st.machineTemplateVolumeAttachmentParams(template)
 		if err != nil {
@@ -283,6 +308,7 @@ func (st *State) addMachineOps(template MachineTemplate) (*machineDoc, []txn.Op,
 	if err != nil {
 		return nil, nil, errors.Trace(err)
 	}
+	prereqOps = append(prereqOps, quotaOps...)
 	prereqOps = append(prereqOps, assertModelActiveOp(st.ModelUUID()))
 	prereqOps = append(prereqOps, insertNewContainerRefOp(st, mdoc.Id))
 	if template.InstanceId != "" {
@@ -342,6 +368,10 @@ func (st *State) addMachineInsideMachineOps(template MachineTemplate, parentId s
 	if err != nil {
 		return nil, nil, err
 	}
+	quotaOps, err := st.quotaOps(quota.Usage{quota.Machines: 1})
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
 	if containerType == "" {
 		return nil, nil, errors.New("no container type specified")
 	}
@@ -375,6 +405,7 @@ func (st *State) addMachineInsideMachineOps(template MachineTemplate, parentId s
 	if err != nil {
 		return nil, nil, errors.Trace(err)
 	}
+	prereqOps = append(prereqOps, quotaOps...)
 	prereqOps = append(prereqOps,
 		// Update containers record for host machine.
 		addChildToContainerRefOp(st, parentId, mdoc.Id),
@@ -402,6 +433,10 @@ func (st *State) addMachineInsideNewMachineOps(template, parentTemplate MachineT
 	if template.InstanceId != "" || parentTemplate.InstanceId != "" {
 		return nil, nil, errors.New("cannot specify instance id for a new container")
 	}
+	quotaOps, err := st.quotaOps(quota.Usage{quota.Machines: 2})
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
 	seq, err := sequence(st, "machine")
 	if err != nil {
 		return nil, nil, err
@@ -448,6 +483,7 @@ func (st *State) addMachineInsideNewMachineOps(template, parentTemplate MachineT
 		return nil, nil, errors.Trace(err)
 	}
 	prereqOps = append(prereqOps, parentPrereqOps...)
+	prereqOps = append(prereqOps, quotaOps...)
 	prereqOps = append(prereqOps,
 		// The host machine doesn't exist yet, create a new containers record.
 		insertNewContainerRefOp(st, mdoc.Id),
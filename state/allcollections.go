@@ -163,6 +163,10 @@ func allCollections() CollectionSchema {
 		// different models at a time.
 		usermodelnameC: {global: true},
 
+		// This collection holds the quota limits set for models, and
+		// for all the models owned by a user.
+		quotasC: {global: true},
+
 		// This collection holds cloud definitions.
 		cloudsC: {global: true},
 
@@ -415,6 +419,14 @@ func allCollections() CollectionSchema {
 		operationsC: {
 			indexes: []mgo.Index{{
 				Key: []string{"model-uuid", "_id"},
+			}, {
+				Key: []string{"model-uuid", "completed"},
+			}},
+		},
+		actionSchedulesC: {},
+		operationArchivesC: {
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "earliest"},
 			}},
 		},
 
@@ -440,6 +452,53 @@ func allCollections() CollectionSchema {
 
 		// -----
 
+		// This collection holds the webhooks notified of events in
+		// each model.
+		webhooksC: {},
+
+		// This collection holds the recorded revisions of the charm
+		// config of applications, and of the model config.
+		configRevisionsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "entity", "revision"},
+			}},
+		},
+
+		// This collection holds the bundle each model is expected to
+		// match, and the drift of the model from it.
+		desiredBundlesC: {},
+
+		// These collections hold the secrets charms store in the
+		// controller, the revisions of their encrypted values, the
+		// applications and units granted access to them, and the
+		// revisions those units are using.
+		secretMetadataC: {
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "owner-tag"},
+			}},
+		},
+		secretRevisionsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "secret-id"},
+			}},
+		},
+		secretPermissionsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "secret-id"},
+			}, {
+				Key: []string{"model-uuid", "subject-tag"},
+			}},
+		},
+		secretConsumersC: {
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "secret-id"},
+			}, {
+				Key: []string{"model-uuid", "consumer-tag"},
+			}},
+		},
+
+		// -----
+
 		// The remaining non-global collections share the property of being
 		// relevant to multiple other kinds of entities, and are thus generally
 		// indexed by globalKey(). This is unhelpfully named in this context --
@@ -550,6 +609,7 @@ func allCollections() CollectionSchema {
 // inspection.
 const (
 	actionNotificationsC       = "actionnotifications"
+	actionSchedulesC           = "actionschedules"
 	actionresultsC             = "actionresults"
 	actionsC                   = "actions"
 	annotationsC               = "annotations"
@@ -596,11 +656,13 @@ const (
 	modelsC                    = "models"
 	modelEntityRefsC           = "modelEntityRefs"
 	openedPortsC               = "openedPorts"
+	operationArchivesC         = "operationarchives"
 	operationsC                = "operations"
 	payloadsC                  = "payloads"
 	permissionsC               = "permissions"
 	podSpecsC                  = "podSpecs"
 	providerIDsC               = "providerIDs"
+	quotasC                    = "quotas"
 	rebootC                    = "reboot"
 	relationScopesC            = "relationscopes"
 	relationsC                 = "relations"
@@ -634,6 +696,13 @@ const (
 	volumeAttachmentsC         = "volumeattachments"
 	volumeAttachmentPlanC      = "volumeattachmentplan"
 	volumesC                   = "volumes"
+	webhooksC                  = "webhooks"
+	configRevisionsC           = "configRevisions"
+	desiredBundlesC            = "desiredBundles"
+	secretMetadataC            = "secretMetadata"
+	secretRevisionsC           = "secretRevisions"
+	secretPermissionsC         = "secretPermissions"
+	secretConsumersC           = "secretConsumers"
 
 	// "resources" (see state/resources_mongo.go)
 
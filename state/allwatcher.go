@@ -159,6 +159,7 @@ func (e *backingModel) updated(ctx *allWatcherContext) error {
 			Level: e.SLA.Level.String(),
 			Owner: e.SLA.Owner,
 		},
+		MigrationMode: string(e.MigrationMode),
 	}
 
 	oldInfo := ctx.store.Get(info.EntityID())
@@ -33,6 +33,7 @@ import (
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/network/firewall"
+	"github.com/juju/juju/core/quota"
 	"github.com/juju/juju/core/status"
 	mgoutils "github.com/juju/juju/mongo/utils"
 	stateerrors "github.com/juju/juju/state/errors"
@@ -661,6 +662,19 @@ func (a *Application) removeOps(asserts bson.D, op *ForcedOperation) ([]txn.Op,
 	ops = append(ops, finalAppCharmRemoveOps(name, curl)...)
 
 	ops = append(ops, a.removeCloudServiceOps()...)
+
+	configRevisionsOps, err := removeConfigRevisionsOps(a.st, a.ApplicationTag())
+	if op.FatalError(err) {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, configRevisionsOps...)
+
+	secretsOps, err := removeSecretsOps(a.st, a.ApplicationTag())
+	if op.FatalError(err) {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, secretsOps...)
+
 	globalKey := a.globalKey()
 	ops = append(ops,
 		removeEndpointBindingsOp(globalKey),
@@ -1915,6 +1929,11 @@ func (a *Application) ChangeScale(scaleChange int) (int, error) {
 			},
 			Update: bson.D{{"$set", bson.D{{"scale", newScale}}}},
 		}}
+		quotaOps, err := a.scaleQuotaOps(newScale)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, quotaOps...)
 
 		cloudSvcDoc := cloudServiceDoc{
 			DocID:                 a.globalKey(),
@@ -1927,13 +1946,26 @@ func (a *Application) ChangeScale(scaleChange int) (int, error) {
 		ops = append(ops, cloudSvcOp...)
 		return ops, nil
 	}
-	if err := a.st.db().Run(buildTxn); err != nil {
+	if err := a.st.db().Run(buildTxn); errors.IsQuotaLimitExceeded(err) {
+		return a.doc.DesiredScale, errors.Annotatef(err, "cannot set scale for application %q to %v", a, newScale)
+	} else if err != nil {
 		return a.doc.DesiredScale, errors.Errorf("cannot set scale for application %q to %v: %v", a, newScale, onAbort(err, applicationNotAliveErr))
 	}
 	a.doc.DesiredScale = newScale
 	return newScale, nil
 }
 
+// scaleQuotaOps returns operations checking that the units needed for
+// the application to reach the given scale can be added without
+// exceeding the quotas.
+func (a *Application) scaleQuotaOps(scale int) ([]txn.Op, error) {
+	if scale <= a.doc.UnitCount {
+		return nil, nil
+	}
+	ops, err := a.st.quotaOps(quota.Usage{quota.Units: uint64(scale - a.doc.UnitCount)})
+	return ops, errors.Trace(err)
+}
+
 // SetScale sets the application's desired scale value.
 // This is used on CAAS models.
 func (a *Application) SetScale(scale int, generation int64, force bool) error {
@@ -1958,7 +1990,6 @@ func (a *Application) SetScale(scale int, generation int64, force bool) error {
 			)
 		}
 	}
-
 	buildTxn := func(attempt int) ([]txn.Op, error) {
 		if attempt > 0 {
 			if err := a.Refresh(); err != nil {
@@ -1987,6 +2018,14 @@ func (a *Application) SetScale(scale int, generation int64, force bool) error {
 		if force {
 			// scale from cli.
 			cloudSvcDoc.DesiredScaleProtected = true
+			// Only scale requested by a user is checked against the
+			// quotas; units the cluster has already created must
+			// still be recorded.
+			quotaOps, err := a.scaleQuotaOps(scale)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, quotaOps...)
 		} else {
 			// scale from cluster always has a valid generation (>= current generation).
 			cloudSvcDoc.Generation = generation
@@ -1998,7 +2037,9 @@ func (a *Application) SetScale(scale int, generation int64, force bool) error {
 		ops = append(ops, cloudSvcOp...)
 		return ops, nil
 	}
-	if err := a.st.db().Run(buildTxn); err != nil {
+	if err := a.st.db().Run(buildTxn); errors.IsQuotaLimitExceeded(err) {
+		return errors.Annotatef(err, "cannot set scale for application %q to %v", a, scale)
+	} else if err != nil {
 		return errors.Errorf("cannot set scale for application %q to %v: %v", a, scale, onAbort(err, applicationNotAliveErr))
 	}
 	a.doc.DesiredScale = scale
@@ -2067,6 +2108,10 @@ func (a *Application) addUnitOps(
 	args AddUnitParams,
 	asserts bson.D,
 ) (string, []txn.Op, error) {
+	quotaOps, err := a.st.quotaOps(quota.Usage{quota.Units: 1})
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
 	var cons constraints.Value
 	if !a.doc.Subordinate {
 		scons, err := a.Constraints()
@@ -2105,6 +2150,7 @@ func (a *Application) addUnitOps(
 	// we verify the application is alive
 	asserts = append(isAliveDoc, asserts...)
 	ops = append(ops, a.incUnitCountOp(asserts))
+	ops = append(ops, quotaOps...)
 	return uNames, ops, nil
 }
 
@@ -2432,19 +2478,24 @@ type AddUnitParams struct {
 // AddUnit adds a new principal unit to the application.
 func (a *Application) AddUnit(args AddUnitParams) (unit *Unit, err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot add unit to application %q", a)
-	name, ops, err := a.addUnitOps("", args, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	if err := a.st.db().RunTransaction(ops); err == txn.ErrAborted {
-		if alive, err := isAlive(a.st, applicationsC, a.doc.DocID); err != nil {
-			return nil, err
-		} else if !alive {
-			return nil, applicationNotAliveErr
+	var name string
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		// The transaction may be aborted by a concurrent addition
+		// that counts towards the same quotas, so the ops are built
+		// again to check them.
+		if attempt > 0 {
+			if alive, err := isAlive(a.st, applicationsC, a.doc.DocID); err != nil {
+				return nil, err
+			} else if !alive {
+				return nil, applicationNotAliveErr
+			}
 		}
-		return nil, errors.New("inconsistent state")
-	} else if err != nil {
+		var ops []txn.Op
+		var err error
+		name, ops, err = a.addUnitOps("", args, nil)
+		return ops, err
+	}
+	if err := a.st.db().Run(buildTxn); err != nil {
 		return nil, err
 	}
 	return a.st.Unit(name)
@@ -2520,6 +2571,12 @@ func (a *Application) removeUnitOps(u *Unit, asserts bson.D, op *ForcedOperation
 	}
 	ops = append(ops, storageInstanceOps...)
 
+	secretsOps, err := removeSecretsOps(a.st, u.Tag())
+	if op.FatalError(err) {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, secretsOps...)
+
 	if u.doc.CharmURL != nil {
 		// If the unit has a different URL to the application, allow any final
 		// cleanup to happen; otherwise we just do it when the app itself is removed.
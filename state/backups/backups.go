@@ -100,6 +100,14 @@ type Backups interface {
 
 	// Remove deletes the backup from storage.
 	Remove(id string) error
+
+	// Restore replaces the controller's state with the contents of
+	// the identified backup archive.
+	Restore(id string, args RestoreArgs) error
+
+	// Contents describes what the identified backup archive holds,
+	// without restoring it.
+	Contents(id string) (*ArchiveContents, error)
 }
 
 type backups struct {
@@ -0,0 +1,369 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// jujuDBName is the name of the database holding juju's state.
+const jujuDBName = "juju"
+
+// bsonExt is the extension of the collection files written by mongodump.
+const bsonExt = ".bson"
+
+// maxDocSize is the largest BSON document mongo allows, used to detect
+// corrupt dump files.
+const maxDocSize = 16 * 1024 * 1024
+
+// ArchiveContents describes what a backup archive holds, as read from
+// the database dump within it.
+type ArchiveContents struct {
+	// ControllerUUID is the UUID of the controller that was backed up.
+	ControllerUUID string
+
+	// JujuVersion is the juju version the controller was running.
+	JujuVersion version.Number
+
+	// Models describes the models in the backup.
+	Models []ModelContents
+
+	// Collections describes each of the collections dumped into the
+	// backup.
+	Collections []CollectionContents
+}
+
+// ModelContents describes a model held in a backup archive.
+type ModelContents struct {
+	UUID     string
+	Name     string
+	Owner    string
+	Type     string
+	Machines int
+
+	// Applications describes the model's applications, ordered by name.
+	Applications []ApplicationContents
+}
+
+// ApplicationContents describes an application held in a backup
+// archive.
+type ApplicationContents struct {
+	Name     string
+	CharmURL string
+	Units    int
+}
+
+// CollectionContents describes a collection dumped into a backup
+// archive.
+type CollectionContents struct {
+	Database  string
+	Name      string
+	Documents int
+
+	// Size is the size in bytes of the collection's dump file.
+	Size int64
+}
+
+// Contents unpacks the identified backup archive into a temporary
+// workspace and describes what it holds.
+func (b *backups) Contents(id string) (*ArchiveContents, error) {
+	_, archive, err := b.Get(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer archive.Close()
+
+	workspace, err := NewArchiveWorkspaceReader(archive)
+	if err != nil {
+		return nil, errors.Annotate(err, "while unpacking backup archive")
+	}
+	defer func() {
+		if err := workspace.Close(); err != nil {
+			logger.Errorf("cannot remove backup contents workspace: %v", err)
+		}
+	}()
+
+	contents, err := workspace.Contents()
+	return contents, errors.Trace(err)
+}
+
+// Contents reads the models, applications and units, and the sizes of
+// the collections, in the database dump unpacked in the workspace.
+// Neither the dump nor the files bundle is restored anywhere; only the
+// workspace is read.
+func (ws *ArchiveWorkspace) Contents() (*ArchiveContents, error) {
+	var contents ArchiveContents
+	meta, err := ws.Metadata()
+	if os.IsNotExist(errors.Cause(err)) {
+		contents.JujuVersion = legacyVersion
+	} else if err != nil {
+		return nil, errors.Annotate(err, "while reading backup metadata")
+	} else {
+		contents.ControllerUUID = meta.Controller.UUID
+		contents.JujuVersion = meta.Origin.Version
+	}
+
+	contents.Collections, err = dumpCollections(ws.DBDumpDir)
+	if err != nil {
+		return nil, errors.Annotate(err, "while reading database dump")
+	}
+
+	jujuDir := filepath.Join(ws.DBDumpDir, jujuDBName)
+	models, controllerUUID, err := readModels(jujuDir)
+	if err != nil {
+		return nil, errors.Annotate(err, "while reading models")
+	}
+	if contents.ControllerUUID == "" {
+		contents.ControllerUUID = controllerUUID
+	}
+	contents.Models = models
+	return &contents, nil
+}
+
+// dumpCollections returns the collections in each database directory
+// of the dump, ordered by database and collection name.
+func dumpCollections(dumpDir string) ([]CollectionContents, error) {
+	databases, err := listDatabases(dumpDir)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var result []CollectionContents
+	for _, dbName := range databases.SortedValues() {
+		files, err := ioutil.ReadDir(filepath.Join(dumpDir, dbName))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, info := range files {
+			name := info.Name()
+			if info.IsDir() || !strings.HasSuffix(name, bsonExt) {
+				// Notably, the .metadata.json files are skipped.
+				continue
+			}
+			count, err := countBSONDocs(filepath.Join(dumpDir, dbName, name))
+			if err != nil {
+				return nil, errors.Annotatef(err, "counting documents in %s/%s", dbName, name)
+			}
+			result = append(result, CollectionContents{
+				Database:  dbName,
+				Name:      strings.TrimSuffix(name, bsonExt),
+				Documents: count,
+				Size:      info.Size(),
+			})
+		}
+	}
+	return result, nil
+}
+
+// countBSONDocs returns the number of documents in a mongodump
+// collection file, without decoding them.
+func countBSONDocs(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	count := 0
+	for {
+		size, err := readBSONDocSize(r)
+		if err == io.EOF {
+			return count, nil
+		} else if err != nil {
+			return 0, errors.Trace(err)
+		}
+		if _, err := r.Discard(size - 4); err != nil {
+			return 0, errors.Annotate(err, "truncated document")
+		}
+		count++
+	}
+}
+
+// readBSONDocs calls handle with each of the documents in a mongodump
+// collection file. A missing file has no documents.
+func readBSONDocs(path string, handle func(bson.Raw) error) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for {
+		size, err := readBSONDocSize(r)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		data := make([]byte, size)
+		binary.LittleEndian.PutUint32(data, uint32(size))
+		if _, err := io.ReadFull(r, data[4:]); err != nil {
+			return errors.Annotate(err, "truncated document")
+		}
+		if err := handle(bson.Raw{Kind: 0x03, Data: data}); err != nil {
+			return errors.Trace(err)
+		}
+	}
+}
+
+// readBSONDocSize reads the length prefix of the next document, which
+// includes the prefix itself. It returns io.EOF if there are no more
+// documents.
+func readBSONDocSize(r io.Reader) (int, error) {
+	var prefix [4]byte
+	if _, err := io.ReadFull(r, prefix[:]); err == io.EOF {
+		return 0, io.EOF
+	} else if err != nil {
+		return 0, errors.Annotate(err, "truncated document")
+	}
+	size := int(int32(binary.LittleEndian.Uint32(prefix[:])))
+	if size < 5 || size > maxDocSize {
+		return 0, errors.Errorf("invalid document size %d", size)
+	}
+	return size, nil
+}
+
+// deadLife is the value of state.Dead, which is stored in the life
+// field of documents that are about to be removed.
+const deadLife = 2
+
+type contentsModelDoc struct {
+	UUID           string `bson:"_id"`
+	Name           string `bson:"name"`
+	Type           string `bson:"type"`
+	Owner          string `bson:"owner"`
+	ControllerUUID string `bson:"controller-uuid"`
+	Life           int    `bson:"life"`
+}
+
+type contentsApplicationDoc struct {
+	Name      string `bson:"name"`
+	ModelUUID string `bson:"model-uuid"`
+	CharmURL  string `bson:"charmurl"`
+	Life      int    `bson:"life"`
+}
+
+type contentsEntityDoc struct {
+	ModelUUID   string `bson:"model-uuid"`
+	Application string `bson:"application"`
+	Life        int    `bson:"life"`
+}
+
+// readModels reads the models, with their machines, applications and
+// units, from the juju database dump. It also returns the controller
+// UUID recorded in the models, for archives without metadata.
+func readModels(jujuDir string) ([]ModelContents, string, error) {
+	var (
+		models         []*ModelContents
+		controllerUUID string
+	)
+	byUUID := make(map[string]*ModelContents)
+	err := readBSONDocs(filepath.Join(jujuDir, "models"+bsonExt), func(raw bson.Raw) error {
+		var doc contentsModelDoc
+		if err := raw.Unmarshal(&doc); err != nil {
+			return errors.Trace(err)
+		}
+		if doc.Life == deadLife {
+			return nil
+		}
+		controllerUUID = doc.ControllerUUID
+		m := &ModelContents{
+			UUID:  doc.UUID,
+			Name:  doc.Name,
+			Owner: doc.Owner,
+			Type:  doc.Type,
+		}
+		models = append(models, m)
+		byUUID[doc.UUID] = m
+		return nil
+	})
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+
+	err = readBSONDocs(filepath.Join(jujuDir, "machines"+bsonExt), func(raw bson.Raw) error {
+		var doc contentsEntityDoc
+		if err := raw.Unmarshal(&doc); err != nil {
+			return errors.Trace(err)
+		}
+		if m, ok := byUUID[doc.ModelUUID]; ok && doc.Life != deadLife {
+			m.Machines++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+
+	type appKey struct {
+		modelUUID string
+		name      string
+	}
+	apps := make(map[appKey]*ApplicationContents)
+	err = readBSONDocs(filepath.Join(jujuDir, "applications"+bsonExt), func(raw bson.Raw) error {
+		var doc contentsApplicationDoc
+		if err := raw.Unmarshal(&doc); err != nil {
+			return errors.Trace(err)
+		}
+		if _, ok := byUUID[doc.ModelUUID]; !ok || doc.Life == deadLife {
+			return nil
+		}
+		apps[appKey{doc.ModelUUID, doc.Name}] = &ApplicationContents{
+			Name:     doc.Name,
+			CharmURL: doc.CharmURL,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+
+	err = readBSONDocs(filepath.Join(jujuDir, "units"+bsonExt), func(raw bson.Raw) error {
+		var doc contentsEntityDoc
+		if err := raw.Unmarshal(&doc); err != nil {
+			return errors.Trace(err)
+		}
+		if app, ok := apps[appKey{doc.ModelUUID, doc.Application}]; ok && doc.Life != deadLife {
+			app.Units++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+
+	for key, app := range apps {
+		m := byUUID[key.modelUUID]
+		m.Applications = append(m.Applications, *app)
+	}
+	result := make([]ModelContents, len(models))
+	for i, m := range models {
+		sort.Slice(m.Applications, func(i, j int) bool {
+			return m.Applications[i].Name < m.Applications[j].Name
+		})
+		result[i] = *m
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Owner != result[j].Owner {
+			return result[i].Owner < result[j].Owner
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result, controllerUUID, nil
+}
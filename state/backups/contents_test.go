@@ -0,0 +1,135 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"bytes"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/state/backups"
+	bt "github.com/juju/juju/state/backups/testing"
+)
+
+type contentsSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&contentsSuite{})
+
+func bsonFile(c *gc.C, name string, docs ...bson.M) bt.File {
+	var buf bytes.Buffer
+	for _, doc := range docs {
+		data, err := bson.Marshal(doc)
+		c.Assert(err, jc.ErrorIsNil)
+		buf.Write(data)
+	}
+	return bt.File{Name: name, Content: buf.String()}
+}
+
+func (s *contentsSuite) newWorkspace(c *gc.C, meta *backups.Metadata, dump []bt.File) *backups.ArchiveWorkspace {
+	archive, err := bt.NewArchive(meta, nil, dump)
+	c.Assert(err, jc.ErrorIsNil)
+	ws, err := backups.NewArchiveWorkspaceReader(archive)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { ws.Close() })
+	return ws
+}
+
+func (s *contentsSuite) dump(c *gc.C) []bt.File {
+	return []bt.File{
+		{Name: "juju", IsDir: true},
+		bsonFile(c, "juju/models.bson",
+			bson.M{"_id": "uuid-1", "name": "controller", "owner": "admin", "type": "iaas", "controller-uuid": "ctrl-uuid"},
+			bson.M{"_id": "uuid-2", "name": "k8s", "owner": "bob", "type": "caas", "controller-uuid": "ctrl-uuid"},
+			bson.M{"_id": "uuid-3", "name": "gone", "owner": "bob", "type": "iaas", "life": 2},
+		),
+		bsonFile(c, "juju/machines.bson",
+			bson.M{"_id": "uuid-1:0", "model-uuid": "uuid-1"},
+			bson.M{"_id": "uuid-1:1", "model-uuid": "uuid-1"},
+			bson.M{"_id": "uuid-1:2", "model-uuid": "uuid-1", "life": 2},
+		),
+		bsonFile(c, "juju/applications.bson",
+			bson.M{"_id": "uuid-1:mysql", "name": "mysql", "model-uuid": "uuid-1", "charmurl": "cs:mysql-1"},
+			bson.M{"_id": "uuid-1:wordpress", "name": "wordpress", "model-uuid": "uuid-1", "charmurl": "cs:wordpress-2"},
+			bson.M{"_id": "uuid-2:gitlab", "name": "gitlab", "model-uuid": "uuid-2", "charmurl": "cs:gitlab-3"},
+		),
+		bsonFile(c, "juju/units.bson",
+			bson.M{"_id": "uuid-1:mysql/0", "model-uuid": "uuid-1", "application": "mysql"},
+			bson.M{"_id": "uuid-1:wordpress/0", "model-uuid": "uuid-1", "application": "wordpress"},
+			bson.M{"_id": "uuid-1:wordpress/1", "model-uuid": "uuid-1", "application": "wordpress"},
+			bson.M{"_id": "uuid-2:gitlab/0", "model-uuid": "uuid-2", "application": "gitlab", "life": 2},
+		),
+		{Name: "juju/units.metadata.json", Content: "{}"},
+		{Name: "logs", IsDir: true},
+		bsonFile(c, "logs/logs.uuid-1.bson", bson.M{"x": 1}),
+		{Name: "oplog.bson", Content: ""},
+	}
+}
+
+func (s *contentsSuite) TestContents(c *gc.C) {
+	meta := bt.NewMetadata()
+	meta.Controller.UUID = "meta-ctrl-uuid"
+	meta.Origin.Version = version.MustParse("2.9.1")
+	ws := s.newWorkspace(c, meta, s.dump(c))
+
+	contents, err := ws.Contents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(contents.ControllerUUID, gc.Equals, "meta-ctrl-uuid")
+	c.Check(contents.JujuVersion, gc.Equals, version.MustParse("2.9.1"))
+	c.Check(contents.Models, jc.DeepEquals, []backups.ModelContents{{
+		UUID:     "uuid-1",
+		Name:     "controller",
+		Owner:    "admin",
+		Type:     "iaas",
+		Machines: 2,
+		Applications: []backups.ApplicationContents{
+			{Name: "mysql", CharmURL: "cs:mysql-1", Units: 1},
+			{Name: "wordpress", CharmURL: "cs:wordpress-2", Units: 2},
+		},
+	}, {
+		UUID:  "uuid-2",
+		Name:  "k8s",
+		Owner: "bob",
+		Type:  "caas",
+		Applications: []backups.ApplicationContents{
+			{Name: "gitlab", CharmURL: "cs:gitlab-3"},
+		},
+	}})
+
+	c.Assert(contents.Collections, gc.HasLen, 5)
+	var names []string
+	for _, coll := range contents.Collections {
+		names = append(names, coll.Database+"."+coll.Name)
+	}
+	c.Check(names, jc.DeepEquals, []string{
+		"juju.applications", "juju.machines", "juju.models", "juju.units", "logs.logs.uuid-1",
+	})
+	units := contents.Collections[3]
+	c.Check(units.Documents, gc.Equals, 4)
+	c.Check(units.Size > 0, jc.IsTrue)
+}
+
+func (s *contentsSuite) TestContentsWithoutMetadata(c *gc.C) {
+	ws := s.newWorkspace(c, nil, s.dump(c))
+
+	contents, err := ws.Contents()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(contents.ControllerUUID, gc.Equals, "ctrl-uuid")
+	c.Check(contents.JujuVersion, gc.Equals, version.MustParse("1.20.0"))
+	c.Check(contents.Models, gc.HasLen, 2)
+}
+
+func (s *contentsSuite) TestContentsCorruptDump(c *gc.C) {
+	dump := s.dump(c)
+	dump = append(dump, bt.File{Name: "juju/charms.bson", Content: "\xff\xff\xff\xffjunk"})
+	ws := s.newWorkspace(c, bt.NewMetadata(), dump)
+
+	_, err := ws.Contents()
+	c.Assert(err, gc.ErrorMatches, `while reading database dump: counting documents in juju/charms.bson: invalid document size -1`)
+}
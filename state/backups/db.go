@@ -89,6 +89,7 @@ func getBackupTargetDatabases(session DBSession) (set.Strings, error) {
 
 const (
 	dumpName       = "mongodump"
+	restoreName    = "mongorestore"
 	snapToolPrefix = "juju-db."
 	snapTmpDir     = "/tmp/snap.juju-db"
 )
@@ -103,6 +104,10 @@ var getMongodumpPath = func() (string, error) {
 	return getMongoToolPath(dumpName, os.Stat, exec.LookPath)
 }
 
+var getMongorestorePath = func() (string, error) {
+	return getMongoToolPath(restoreName, os.Stat, exec.LookPath)
+}
+
 var getMongodPath = func() (string, error) {
 	finder := mongo.NewMongodFinder()
 	path, err := finder.InstalledAt()
@@ -269,6 +274,87 @@ func listDatabases(dumpDir string) (set.Strings, error) {
 	return databases, nil
 }
 
+// DBRestorer is any type that restores something from a dump dir.
+type DBRestorer interface {
+	// Restore something from dumpDir.
+	Restore(dumpDir string) error
+}
+
+type mongoRestorer struct {
+	*DBInfo
+	// binPath is the path to the restore executable.
+	binPath string
+}
+
+// NewDBRestorer returns a new value with a Restore method for loading
+// a database dump back into the juju state database.
+func NewDBRestorer(info *DBInfo) (DBRestorer, error) {
+	mongorestorePath, err := getMongorestorePath()
+	if err != nil {
+		return nil, errors.Annotate(err, "mongorestore not available")
+	}
+
+	restorer := mongoRestorer{
+		DBInfo:  info,
+		binPath: mongorestorePath,
+	}
+	return &restorer, nil
+}
+
+func (mr *mongoRestorer) options(dumpDir string) []string {
+	options := []string{
+		"--ssl",
+		"--sslAllowInvalidCertificates",
+		"--authenticationDatabase", "admin",
+		"--host", mr.Address,
+		"--username", mr.Username,
+		"--password", mr.Password,
+		"--drop",
+		"--oplogReplay",
+		"--batchSize", "10",
+		dumpDir,
+	}
+	return options
+}
+
+func (mr *mongoRestorer) isSnap() bool {
+	return filepath.Base(mr.binPath) == snapToolPrefix+restoreName
+}
+
+// Restore drops each of the databases found in the dump dir and
+// loads them back from the dump files, replaying the oplog captured
+// when the dump was taken.
+func (mr *mongoRestorer) Restore(dumpDir string) error {
+	logger.Tracef("restoring Mongo database from %q", dumpDir)
+	if _, err := listDatabases(dumpDir); err != nil {
+		return errors.Trace(err)
+	}
+
+	// The juju-db.mongorestore Snap can only read from within
+	// /tmp/snap.juju-db, so move the dump there first.
+	if mr.isSnap() {
+		snapDir := filepath.Join(snapTmpDir, dumpDir)
+		logger.Tracef("moving dump dir %q to Snap dump dir %q", dumpDir, snapDir)
+		if err := os.MkdirAll(filepath.Dir(snapDir), 0700); err != nil {
+			return errors.Trace(err)
+		}
+		if err := os.Rename(dumpDir, snapDir); err != nil {
+			return errors.Trace(err)
+		}
+		defer func() {
+			if err := os.RemoveAll(snapDir); err != nil {
+				logger.Errorf("cannot remove Snap dump dir %q: %v", snapDir, err)
+			}
+		}()
+	}
+
+	options := mr.options(dumpDir)
+	if err := runCommandFn(mr.binPath, options...); err != nil {
+		return errors.Annotate(err, "error restoring databases")
+	}
+	return nil
+}
+
 // MongoDB represents a mgo.DB.
 type MongoDB interface {
 	UpsertUser(*mgo.User) error
@@ -27,6 +27,10 @@ var (
 	StoreArchiveRef      = &storeArchive
 	GetMongodumpPath     = &getMongodumpPath
 	RunCommand           = &runCommandFn
+
+	GetDBRestorer         = &getDBRestorer
+	GetMongorestorePath   = &getMongorestorePath
+	SetAdminMongoPassword = &setAdminMongoPassword
 )
 
 var _ filestorage.DocStorage = (*backupsDocStorage)(nil)
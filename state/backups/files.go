@@ -40,7 +40,8 @@ type Paths struct {
 }
 
 // GetFilesToBackUp returns the paths that should be included in the
-// backup archive.
+// backup archive. The secrets keys dir is deliberately left out, so
+// that a backup can't be used to read the secret values in it.
 func GetFilesToBackUp(rootDir string, paths *Paths, oldmachine string) ([]string, error) {
 	var glob string
 
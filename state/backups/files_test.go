@@ -70,6 +70,10 @@ func (s *filesSuite) createFiles(c *gc.C, paths backups.Paths, root, machineID s
 	}
 	mkdir(filepath.Join(paths.DataDir, "tools"))
 
+	// The secrets keys must never be backed up.
+	dirname = mkdir(filepath.Join(paths.DataDir, "secrets"))
+	touch(dirname, "controller.key")
+
 	dirname = mkdir(filepath.Join(paths.DataDir, "agents"))
 	touch(dirname, "machine-"+machineID+".conf")
 
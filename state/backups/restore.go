@@ -0,0 +1,225 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/utils/v2"
+	"github.com/juju/version"
+	"gopkg.in/mgo.v2"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/mongo"
+)
+
+var (
+	getDBRestorer         = NewDBRestorer
+	setAdminMongoPassword = mongo.SetAdminMongoPassword
+)
+
+// AgentServices stops and starts the agents whose workers would
+// otherwise be using the database while it is being restored.
+type AgentServices interface {
+	// Stop stops the agents before the restore begins.
+	Stop() error
+
+	// Start starts the agents again once the restore has finished.
+	Start() error
+}
+
+// RestoreArgs holds the information needed to restore a backup archive
+// onto the running controller.
+type RestoreArgs struct {
+	// DBInfo holds the details of the database the dump is loaded into.
+	DBInfo *DBInfo
+
+	// Session is used to update the controller agent's mongo user
+	// once the agent config from the archive is in place.
+	Session *mgo.Session
+
+	// Paths holds the controller's data directory.
+	Paths *Paths
+
+	// ControllerUUID is the UUID of the controller being restored.
+	ControllerUUID string
+
+	// MachineID is the ID of the controller machine the restore runs on.
+	MachineID string
+
+	// Version is the juju version the controller is currently running.
+	Version version.Number
+
+	// Agents stops and starts the agents around the restore.
+	Agents AgentServices
+}
+
+// Validate ensures the args are complete.
+func (args RestoreArgs) Validate() error {
+	if args.DBInfo == nil {
+		return errors.NotValidf("missing DBInfo")
+	}
+	if args.Session == nil {
+		return errors.NotValidf("missing Session")
+	}
+	if args.Paths == nil {
+		return errors.NotValidf("missing Paths")
+	}
+	if args.ControllerUUID == "" {
+		return errors.NotValidf("missing ControllerUUID")
+	}
+	if !names.IsValidMachine(args.MachineID) {
+		return errors.NotValidf("machine ID %q", args.MachineID)
+	}
+	if args.Agents == nil {
+		return errors.NotValidf("missing Agents")
+	}
+	return nil
+}
+
+// CheckRestorable returns an error if the backup described by the
+// metadata cannot be restored onto the controller described by args.
+// Backups can only be restored onto the controller and machine they
+// were taken from, using the same major and minor juju version.
+func CheckRestorable(meta *Metadata, args RestoreArgs) error {
+	if meta.FormatVersion != currentFormatVersion {
+		return errors.NotSupportedf("backup format version %d", meta.FormatVersion)
+	}
+	if meta.Controller.UUID != args.ControllerUUID {
+		return errors.Errorf(
+			"backup was taken on controller %q, cannot restore onto controller %q",
+			meta.Controller.UUID, args.ControllerUUID)
+	}
+	if meta.Origin.Machine != args.MachineID {
+		return errors.Errorf(
+			"backup was taken on machine %q, cannot restore onto machine %q",
+			meta.Origin.Machine, args.MachineID)
+	}
+	backupVersion := meta.Origin.Version
+	if backupVersion.Major != args.Version.Major || backupVersion.Minor != args.Version.Minor {
+		return errors.Errorf(
+			"juju version %v cannot restore backups made using juju version %v",
+			args.Version, backupVersion)
+	}
+	if backupVersion.Compare(args.Version) > 0 {
+		return errors.Errorf(
+			"backup was made using juju version %v, which is newer than the controller's %v",
+			backupVersion, args.Version)
+	}
+	return nil
+}
+
+// Restore replaces the controller's database and agent config with
+// the contents of the identified backup archive.
+func (b *backups) Restore(id string, args RestoreArgs) error {
+	if err := args.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+
+	storedMeta, archive, err := b.Get(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archive.Close()
+
+	workspace, err := NewArchiveWorkspaceReader(archive)
+	if err != nil {
+		return errors.Annotate(err, "while unpacking backup archive")
+	}
+	defer func() {
+		if err := workspace.Close(); err != nil {
+			logger.Errorf("cannot remove restore workspace: %v", err)
+		}
+	}()
+
+	// Prefer the metadata written into the archive at creation time,
+	// falling back to what was stored alongside it.
+	meta, err := workspace.Metadata()
+	if os.IsNotExist(errors.Cause(err)) {
+		meta, err = storedMeta, nil
+	}
+	if err != nil {
+		return errors.Annotate(err, "while reading backup metadata")
+	}
+	if err := CheckRestorable(meta, args); err != nil {
+		return errors.Trace(err)
+	}
+
+	restorer, err := getDBRestorer(args.DBInfo)
+	if err != nil {
+		return errors.Annotate(err, "while preparing for DB restore")
+	}
+
+	logger.Infof("stopping agents for restore of backup %q", id)
+	err = args.Agents.Stop()
+	if err == nil {
+		err = restoreStopped(id, workspace, restorer, args)
+	} else {
+		err = errors.Annotate(err, "while stopping agents")
+	}
+
+	// The agents must be started again whether or not the restore
+	// succeeded, otherwise the controller is left without them.
+	if startErr := args.Agents.Start(); startErr != nil {
+		if err != nil {
+			logger.Errorf("cannot start agents after failed restore: %v", startErr)
+			return errors.Trace(err)
+		}
+		return errors.Annotate(startErr, "while starting agents")
+	}
+	return errors.Trace(err)
+}
+
+// restoreStopped replaces the database and agent config once the
+// agents using them have been stopped.
+func restoreStopped(id string, workspace *ArchiveWorkspace, restorer DBRestorer, args RestoreArgs) error {
+	if err := restorer.Restore(workspace.DBDumpDir); err != nil {
+		return errors.Annotate(err, "while restoring database")
+	}
+	logger.Infof("restored database from backup %q", id)
+
+	if err := restoreAgentConfig(workspace, args); err != nil {
+		return errors.Annotate(err, "while restoring agent config")
+	}
+	logger.Infof("restored agent config from backup %q", id)
+	return nil
+}
+
+// restoreAgentConfig replaces the controller agent's config file with
+// the one in the archive, and updates the agent's mongo user so that
+// it matches the restored password.
+func restoreAgentConfig(ws *ArchiveWorkspace, args RestoreArgs) error {
+	tag := names.NewMachineTag(args.MachineID)
+	confPath := agent.ConfigPath(args.Paths.DataDir, tag)
+
+	// Files in the bundle are stored relative to the filesystem root.
+	bundled := strings.TrimPrefix(filepath.ToSlash(confPath), "/")
+	r, err := ws.OpenBundledFile(bundled)
+	if err != nil {
+		return errors.Annotatef(err, "cannot find %q in archive", bundled)
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := utils.AtomicWriteFile(confPath, data, 0600); err != nil {
+		return errors.Trace(err)
+	}
+
+	conf, err := agent.ReadConfig(confPath)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	mongoInfo, ok := conf.MongoInfo()
+	if !ok {
+		return errors.Errorf("no mongo info found in restored agent config")
+	}
+	err = setAdminMongoPassword(args.Session, tag.String(), mongoInfo.Password)
+	return errors.Trace(err)
+}
@@ -0,0 +1,333 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state/backups"
+	backupstesting "github.com/juju/juju/state/backups/testing"
+	"github.com/juju/juju/testing"
+	jujuversion "github.com/juju/juju/version"
+)
+
+type restoreSuite struct {
+	backupstesting.BaseSuite
+
+	api     backups.Backups
+	dataDir string
+	calls   []string
+}
+
+var _ = gc.Suite(&restoreSuite{})
+
+func (s *restoreSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.api = backups.NewBackups(s.Storage)
+	s.dataDir = c.MkDir()
+	s.calls = nil
+}
+
+func (s *restoreSuite) Stop() error {
+	s.calls = append(s.calls, "Stop")
+	return nil
+}
+
+func (s *restoreSuite) Start() error {
+	s.calls = append(s.calls, "Start")
+	return nil
+}
+
+func (s *restoreSuite) Restore(dumpDir string) error {
+	s.calls = append(s.calls, "Restore")
+	return nil
+}
+
+func (s *restoreSuite) restoreArgs() backups.RestoreArgs {
+	return backups.RestoreArgs{
+		DBInfo:         &backups.DBInfo{"a", "b", "c", set.NewStrings("juju")},
+		Session:        &mgo.Session{},
+		Paths:          &backups.Paths{DataDir: s.dataDir},
+		ControllerUUID: testing.ControllerTag.Id(),
+		MachineID:      "0",
+		Version:        jujuversion.Current,
+		Agents:         s,
+	}
+}
+
+func (s *restoreSuite) newMetadata() *backups.Metadata {
+	meta := backupstesting.NewMetadataStarted()
+	meta.Controller.UUID = testing.ControllerTag.Id()
+	return meta
+}
+
+func (s *restoreSuite) writeAgentConfig(c *gc.C, password string) (string, []byte) {
+	conf, err := agent.NewStateMachineConfig(agent.AgentConfigParams{
+		Paths:             agent.Paths{DataDir: s.dataDir},
+		Tag:               names.NewMachineTag("0"),
+		UpgradedToVersion: jujuversion.Current,
+		Password:          password,
+		CACert:            "ca cert",
+		APIAddresses:      []string{"localhost:17070"},
+		Nonce:             "a nonce",
+		Controller:        testing.ControllerTag,
+		Model:             testing.ModelTag,
+	}, controller.StateServingInfo{
+		APIPort:      17070,
+		StatePort:    37017,
+		Cert:         "cert",
+		PrivateKey:   "key",
+		CAPrivateKey: "ca key",
+		SharedSecret: "shared",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	conf.SetPassword(password)
+	c.Assert(conf.Write(), jc.ErrorIsNil)
+
+	confPath := agent.ConfigPath(s.dataDir, names.NewMachineTag("0"))
+	data, err := ioutil.ReadFile(confPath)
+	c.Assert(err, jc.ErrorIsNil)
+	return confPath, data
+}
+
+func (s *restoreSuite) setArchive(c *gc.C, meta *backups.Metadata, files []backupstesting.File) {
+	dump := []backupstesting.File{
+		{Name: "juju", IsDir: true},
+		{Name: "oplog.bson", Content: "<oplog>"},
+	}
+	archive, err := backupstesting.NewArchive(meta, files, dump)
+	c.Assert(err, jc.ErrorIsNil)
+	s.Storage.Meta = meta
+	s.Storage.File = ioutil.NopCloser(archive)
+}
+
+func (s *restoreSuite) TestCheckRestorable(c *gc.C) {
+	err := backups.CheckRestorable(s.newMetadata(), s.restoreArgs())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *restoreSuite) TestCheckRestorableOlderPatch(c *gc.C) {
+	meta := s.newMetadata()
+	args := s.restoreArgs()
+	args.Version = meta.Origin.Version
+	args.Version.Patch++
+	err := backups.CheckRestorable(meta, args)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *restoreSuite) TestCheckRestorableWrongController(c *gc.C) {
+	meta := s.newMetadata()
+	meta.Controller.UUID = "another-uuid"
+	err := backups.CheckRestorable(meta, s.restoreArgs())
+	c.Assert(err, gc.ErrorMatches, `backup was taken on controller "another-uuid", cannot restore onto controller ".*"`)
+}
+
+func (s *restoreSuite) TestCheckRestorableWrongMachine(c *gc.C) {
+	meta := s.newMetadata()
+	meta.Origin.Machine = "1"
+	err := backups.CheckRestorable(meta, s.restoreArgs())
+	c.Assert(err, gc.ErrorMatches, `backup was taken on machine "1", cannot restore onto machine "0"`)
+}
+
+func (s *restoreSuite) TestCheckRestorableWrongMinorVersion(c *gc.C) {
+	meta := s.newMetadata()
+	meta.Origin.Version = version.MustParse("2.1.0")
+	args := s.restoreArgs()
+	args.Version = version.MustParse("2.2.0")
+	err := backups.CheckRestorable(meta, args)
+	c.Assert(err, gc.ErrorMatches, `juju version 2.2.0 cannot restore backups made using juju version 2.1.0`)
+}
+
+func (s *restoreSuite) TestCheckRestorableNewerVersion(c *gc.C) {
+	meta := s.newMetadata()
+	meta.Origin.Version = version.MustParse("2.2.3")
+	args := s.restoreArgs()
+	args.Version = version.MustParse("2.2.1")
+	err := backups.CheckRestorable(meta, args)
+	c.Assert(err, gc.ErrorMatches, `backup was made using juju version 2.2.3, which is newer than the controller's 2.2.1`)
+}
+
+func (s *restoreSuite) TestCheckRestorableFormatVersion(c *gc.C) {
+	meta := s.newMetadata()
+	meta.FormatVersion = 0
+	err := backups.CheckRestorable(meta, s.restoreArgs())
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *restoreSuite) TestRestore(c *gc.C) {
+	confPath, data := s.writeAgentConfig(c, "sekrit")
+	s.setArchive(c, s.newMetadata(), []backupstesting.File{{
+		Name:    strings.TrimPrefix(filepath.ToSlash(confPath), "/"),
+		Content: string(data),
+	}})
+	// Clobber the on-disk config so we can see it being restored.
+	s.writeAgentConfig(c, "new-sekrit")
+
+	var dumpDir string
+	s.PatchValue(backups.GetDBRestorer, func(info *backups.DBInfo) (backups.DBRestorer, error) {
+		return restorerFunc(func(dir string) error {
+			dumpDir = dir
+			return s.Restore(dir)
+		}), nil
+	})
+	var user, password string
+	s.PatchValue(backups.SetAdminMongoPassword, func(_ *mgo.Session, u, p string) error {
+		user, password = u, p
+		return nil
+	})
+
+	err := s.api.Restore("spam", s.restoreArgs())
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.calls, jc.DeepEquals, []string{"Stop", "Restore", "Start"})
+	c.Check(filepath.Base(dumpDir), gc.Equals, "dump")
+	c.Check(s.Storage.IDArg, gc.Equals, "spam")
+
+	restored, err := ioutil.ReadFile(confPath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(restored), gc.Equals, string(data))
+	c.Check(user, gc.Equals, "machine-0")
+	c.Check(password, gc.Equals, "sekrit")
+}
+
+func (s *restoreSuite) TestRestoreNotRestorable(c *gc.C) {
+	meta := s.newMetadata()
+	meta.Controller.UUID = "another-uuid"
+	s.setArchive(c, meta, nil)
+
+	err := s.api.Restore("spam", s.restoreArgs())
+	c.Assert(err, gc.ErrorMatches, `backup was taken on controller "another-uuid", .*`)
+	c.Check(s.calls, gc.HasLen, 0)
+}
+
+func (s *restoreSuite) TestRestoreDBFailure(c *gc.C) {
+	s.setArchive(c, s.newMetadata(), nil)
+	s.PatchValue(backups.GetDBRestorer, func(info *backups.DBInfo) (backups.DBRestorer, error) {
+		return restorerFunc(func(string) error {
+			return errors.New("boom")
+		}), nil
+	})
+
+	err := s.api.Restore("spam", s.restoreArgs())
+	c.Assert(err, gc.ErrorMatches, "while restoring database: boom")
+	c.Check(s.calls, jc.DeepEquals, []string{"Stop", "Start"})
+}
+
+func (s *restoreSuite) TestRestoreAgentConfigFailure(c *gc.C) {
+	// The archive has no agent config to restore.
+	s.setArchive(c, s.newMetadata(), nil)
+	s.PatchValue(backups.GetDBRestorer, func(info *backups.DBInfo) (backups.DBRestorer, error) {
+		return restorerFunc(s.Restore), nil
+	})
+
+	err := s.api.Restore("spam", s.restoreArgs())
+	c.Assert(err, gc.ErrorMatches, `while restoring agent config: cannot find ".*" in archive.*`)
+	c.Check(s.calls, jc.DeepEquals, []string{"Stop", "Restore", "Start"})
+}
+
+func (s *restoreSuite) TestRestoreStopFailure(c *gc.C) {
+	s.setArchive(c, s.newMetadata(), nil)
+	s.PatchValue(backups.GetDBRestorer, func(info *backups.DBInfo) (backups.DBRestorer, error) {
+		return restorerFunc(s.Restore), nil
+	})
+	args := s.restoreArgs()
+	agents := &failingAgents{stopErr: errors.New("boom")}
+	args.Agents = agents
+
+	err := s.api.Restore("spam", args)
+	c.Assert(err, gc.ErrorMatches, "while stopping agents: boom")
+	c.Check(agents.started, jc.IsTrue)
+	c.Check(s.calls, gc.HasLen, 0)
+}
+
+func (s *restoreSuite) TestRestoreStartFailure(c *gc.C) {
+	s.setArchive(c, s.newMetadata(), nil)
+	s.PatchValue(backups.GetDBRestorer, func(info *backups.DBInfo) (backups.DBRestorer, error) {
+		return restorerFunc(func(string) error {
+			return errors.New("boom")
+		}), nil
+	})
+	args := s.restoreArgs()
+	agents := &failingAgents{startErr: errors.New("no start")}
+	args.Agents = agents
+
+	// The restore error is reported in preference to the start error.
+	err := s.api.Restore("spam", args)
+	c.Assert(err, gc.ErrorMatches, "while restoring database: boom")
+	c.Check(agents.started, jc.IsTrue)
+}
+
+type failingAgents struct {
+	stopErr  error
+	startErr error
+	started  bool
+}
+
+func (a *failingAgents) Stop() error {
+	return a.stopErr
+}
+
+func (a *failingAgents) Start() error {
+	a.started = true
+	return a.startErr
+}
+
+func (s *restoreSuite) TestRestoreInvalidArgs(c *gc.C) {
+	args := s.restoreArgs()
+	args.Agents = nil
+	err := s.api.Restore("spam", args)
+	c.Assert(err, gc.ErrorMatches, "missing Agents not valid")
+}
+
+func (s *restoreSuite) TestDBRestorer(c *gc.C) {
+	s.PatchValue(backups.GetMongorestorePath, func() (string, error) {
+		return "bogusmongorestore", nil
+	})
+	var ranCommand string
+	var ranArgs []string
+	s.PatchValue(backups.RunCommand, func(cmd string, args ...string) error {
+		ranCommand, ranArgs = cmd, args
+		return nil
+	})
+	dumpDir := c.MkDir()
+	c.Assert(ioutil.WriteFile(filepath.Join(dumpDir, "oplog.bson"), nil, 0600), jc.ErrorIsNil)
+	c.Assert(ioutil.WriteFile(filepath.Join(dumpDir, "juju"), nil, 0600), jc.ErrorIsNil)
+
+	restorer, err := backups.NewDBRestorer(&backups.DBInfo{"a", "b", "c", nil})
+	c.Assert(err, jc.ErrorIsNil)
+	err = restorer.Restore(dumpDir)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(ranCommand, gc.Equals, "bogusmongorestore")
+	c.Check(ranArgs, jc.DeepEquals, []string{
+		"--ssl",
+		"--sslAllowInvalidCertificates",
+		"--authenticationDatabase", "admin",
+		"--host", "a",
+		"--username", "b",
+		"--password", "c",
+		"--drop",
+		"--oplogReplay",
+		"--batchSize", "10",
+		dumpDir,
+	})
+}
+
+type restorerFunc func(string) error
+
+func (f restorerFunc) Restore(dumpDir string) error {
+	return f(dumpDir)
+}
@@ -52,6 +52,10 @@ type FakeBackups struct {
 	KeepCopy bool
 	// NoDownload holds the noDownload bool that was passed in.
 	NoDownload bool
+	// RestoreArgs holds the RestoreArgs that was passed in.
+	RestoreArgs backups.RestoreArgs
+	// ArchiveContents holds the archive contents to return.
+	ArchiveContents *backups.ArchiveContents
 }
 
 var _ backups.Backups = (*FakeBackups)(nil)
@@ -111,6 +115,21 @@ func (b *FakeBackups) Remove(id string) error {
 	return errors.Trace(b.Error)
 }
 
+// Restore restores the identified backup onto the controller.
+func (b *FakeBackups) Restore(id string, args backups.RestoreArgs) error {
+	b.Calls = append(b.Calls, "Restore")
+	b.IDArg = id
+	b.RestoreArgs = args
+	return errors.Trace(b.Error)
+}
+
+// Contents returns the contents of the identified backup archive.
+func (b *FakeBackups) Contents(id string) (*backups.ArchiveContents, error) {
+	b.Calls = append(b.Calls, "Contents")
+	b.IDArg = id
+	return b.ArchiveContents, b.Error
+}
+
 // TODO(ericsnow) FakeStorage should probably move over to the utils repo.
 
 // FakeStorage is a FileStorage implementation to use when testing
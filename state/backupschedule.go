@@ -0,0 +1,108 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+const scheduledBackupStatusKey = "scheduledBackupStatus"
+
+// ScheduledBackupStatus records the outcome of the backups made by the
+// controller's backup schedule.
+type ScheduledBackupStatus struct {
+	// LastAttempt is when the most recent scheduled backup started.
+	LastAttempt time.Time
+
+	// LastSuccess is when the most recent successful scheduled backup
+	// started, and LastBackupID is the ID of the backup it made.
+	LastSuccess  time.Time
+	LastBackupID string
+
+	// LastFailure is when the most recent failed scheduled backup
+	// started, and LastError is why it failed.
+	LastFailure time.Time
+	LastError   string
+
+	// NextRun is when the next scheduled backup will start. It is zero
+	// if no schedule is configured.
+	NextRun time.Time
+}
+
+type scheduledBackupStatusDoc struct {
+	LastAttempt  time.Time `bson:"last-attempt"`
+	LastSuccess  time.Time `bson:"last-success"`
+	LastBackupID string    `bson:"last-backup-id"`
+	LastFailure  time.Time `bson:"last-failure"`
+	LastError    string    `bson:"last-error"`
+	NextRun      time.Time `bson:"next-run"`
+}
+
+// ScheduledBackupStatus returns the outcome of the controller's
+// scheduled backups. The status is empty if none have run.
+func (st *State) ScheduledBackupStatus() (ScheduledBackupStatus, error) {
+	controllers, closer := st.db().GetCollection(controllersC)
+	defer closer()
+
+	var doc scheduledBackupStatusDoc
+	err := controllers.FindId(scheduledBackupStatusKey).One(&doc)
+	if err == mgo.ErrNotFound {
+		return ScheduledBackupStatus{}, nil
+	} else if err != nil {
+		return ScheduledBackupStatus{}, errors.Annotate(err, "cannot get scheduled backup status")
+	}
+	return ScheduledBackupStatus{
+		LastAttempt:  doc.LastAttempt.UTC(),
+		LastSuccess:  doc.LastSuccess.UTC(),
+		LastBackupID: doc.LastBackupID,
+		LastFailure:  doc.LastFailure.UTC(),
+		LastError:    doc.LastError,
+		NextRun:      doc.NextRun.UTC(),
+	}, nil
+}
+
+// SetScheduledBackupStatus records the outcome of the controller's
+// scheduled backups.
+func (st *State) SetScheduledBackupStatus(status ScheduledBackupStatus) error {
+	doc := scheduledBackupStatusDoc{
+		LastAttempt:  status.LastAttempt.UTC(),
+		LastSuccess:  status.LastSuccess.UTC(),
+		LastBackupID: status.LastBackupID,
+		LastFailure:  status.LastFailure.UTC(),
+		LastError:    status.LastError,
+		NextRun:      status.NextRun.UTC(),
+	}
+	controllers, closer := st.db().GetCollection(controllersC)
+	defer closer()
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		n, err := controllers.FindId(scheduledBackupStatusKey).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if n == 0 {
+			return []txn.Op{{
+				C:      controllersC,
+				Id:     scheduledBackupStatusKey,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			}}, nil
+		}
+		return []txn.Op{{
+			C:      controllersC,
+			Id:     scheduledBackupStatusKey,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", &doc}},
+		}}, nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		return errors.Annotate(err, "cannot set scheduled backup status")
+	}
+	return nil
+}
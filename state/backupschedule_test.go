@@ -0,0 +1,54 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type ScheduledBackupStatusSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&ScheduledBackupStatusSuite{})
+
+func (s *ScheduledBackupStatusSuite) TestScheduledBackupStatusNoneSet(c *gc.C) {
+	status, err := s.State.ScheduledBackupStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, jc.DeepEquals, state.ScheduledBackupStatus{})
+}
+
+func (s *ScheduledBackupStatusSuite) TestSetScheduledBackupStatus(c *gc.C) {
+	now := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	expected := state.ScheduledBackupStatus{
+		LastAttempt:  now,
+		LastSuccess:  now,
+		LastBackupID: "backup-id",
+		NextRun:      now.Add(24 * time.Hour),
+	}
+	err := s.State.SetScheduledBackupStatus(expected)
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, err := s.State.ScheduledBackupStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, jc.DeepEquals, expected)
+
+	// Setting the status again replaces it.
+	later := now.Add(24 * time.Hour)
+	expected.LastAttempt = later
+	expected.LastFailure = later
+	expected.LastError = "boom"
+	expected.NextRun = later.Add(24 * time.Hour)
+	err = s.State.SetScheduledBackupStatus(expected)
+	c.Assert(err, jc.ErrorIsNil)
+
+	status, err = s.State.ScheduledBackupStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status, jc.DeepEquals, expected)
+}
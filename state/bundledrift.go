@@ -0,0 +1,246 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/status"
+)
+
+// BundleDriftStatusMessage is the message of the model status while the
+// model has drifted from its desired bundle.
+const BundleDriftStatusMessage = "model has drifted from its desired bundle"
+
+// desiredBundleKey is the local id of a model's desired bundle doc.
+const desiredBundleKey = "desired-bundle"
+
+// DesiredBundle is the bundle a model is expected to match, with the
+// drift of the model from it when it was last checked.
+type DesiredBundle struct {
+	// Bundle holds the YAML of the bundle.
+	Bundle string
+
+	// Overlays holds the YAML of the overlays applied to the bundle,
+	// in the order they are applied.
+	Overlays []string
+
+	// SetBy is the name of the user who set the desired bundle.
+	SetBy string
+
+	// Set is when the desired bundle was set.
+	Set time.Time
+
+	// Drift is the drift of the model from the bundle when it was last
+	// checked; it is nil if the model hasn't been checked since the
+	// bundle was set.
+	Drift *BundleDrift
+}
+
+// BundleDrift records how a model differs from its desired bundle.
+type BundleDrift struct {
+	// Checked is when the model was compared with the bundle.
+	Checked time.Time
+
+	// Diff holds the differences between the bundle and the model, in
+	// the YAML format of diff-bundle. It is empty if the model matches
+	// the bundle.
+	Diff string
+
+	// Error holds why the model couldn't be compared with the bundle,
+	// if it couldn't.
+	Error string
+}
+
+// Drifted reports whether the model differed from the bundle.
+func (d BundleDrift) Drifted() bool {
+	return d.Diff != ""
+}
+
+type desiredBundleDoc struct {
+	DocId     string     `bson:"_id"`
+	ModelUUID string     `bson:"model-uuid"`
+	Bundle    string     `bson:"bundle"`
+	Overlays  []string   `bson:"overlays,omitempty"`
+	SetBy     string     `bson:"set-by"`
+	Set       time.Time  `bson:"set"`
+	Checked   *time.Time `bson:"checked,omitempty"`
+	Diff      string     `bson:"diff,omitempty"`
+	Error     string     `bson:"error,omitempty"`
+}
+
+func (doc desiredBundleDoc) desiredBundle() DesiredBundle {
+	result := DesiredBundle{
+		Bundle:   doc.Bundle,
+		Overlays: doc.Overlays,
+		SetBy:    doc.SetBy,
+		Set:      doc.Set,
+	}
+	if doc.Checked != nil {
+		result.Drift = &BundleDrift{
+			Checked: *doc.Checked,
+			Diff:    doc.Diff,
+			Error:   doc.Error,
+		}
+	}
+	return result
+}
+
+// SetDesiredBundle sets the bundle, with overlays, that the model is
+// expected to match, replacing any set before. The drift of the model
+// from the bundle is unknown until it is next checked.
+func (m *Model) SetDesiredBundle(bundle string, overlays []string, setBy string) error {
+	if bundle == "" {
+		return errors.NotValidf("empty bundle")
+	}
+	doc := desiredBundleDoc{
+		DocId:     m.st.docID(desiredBundleKey),
+		ModelUUID: m.st.ModelUUID(),
+		Bundle:    bundle,
+		Overlays:  overlays,
+		SetBy:     setBy,
+		Set:       m.st.clock().Now().UTC().Round(time.Second),
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := m.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if m.Life() != Alive {
+			return nil, errors.Errorf("model %q is no longer alive", m.Name())
+		}
+		ops := []txn.Op{{
+			C:      modelsC,
+			Id:     m.st.ModelUUID(),
+			Assert: isAliveDoc,
+		}}
+		_, err := m.desiredBundleDoc()
+		switch {
+		case errors.IsNotFound(err):
+			ops = append(ops, txn.Op{
+				C:      desiredBundlesC,
+				Id:     doc.DocId,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			})
+		case err != nil:
+			return nil, errors.Trace(err)
+		default:
+			ops = append(ops, txn.Op{
+				C:      desiredBundlesC,
+				Id:     doc.DocId,
+				Assert: txn.DocExists,
+				Update: bson.D{
+					{"$set", bson.D{
+						{"bundle", doc.Bundle},
+						{"overlays", doc.Overlays},
+						{"set-by", doc.SetBy},
+						{"set", doc.Set},
+					}},
+					{"$unset", bson.D{
+						{"checked", nil},
+						{"diff", nil},
+						{"error", nil},
+					}},
+				},
+			})
+		}
+		return ops, nil
+	}
+	if err := m.st.db().Run(buildTxn); err != nil {
+		return errors.Annotate(err, "setting desired bundle")
+	}
+	return errors.Trace(m.setBundleDriftStatus(false))
+}
+
+// DesiredBundle returns the bundle the model is expected to match.
+func (m *Model) DesiredBundle() (DesiredBundle, error) {
+	doc, err := m.desiredBundleDoc()
+	if err != nil {
+		return DesiredBundle{}, errors.Trace(err)
+	}
+	return doc.desiredBundle(), nil
+}
+
+func (m *Model) desiredBundleDoc() (desiredBundleDoc, error) {
+	coll, closer := m.st.db().GetCollection(desiredBundlesC)
+	defer closer()
+
+	var doc desiredBundleDoc
+	err := coll.FindId(desiredBundleKey).One(&doc)
+	if err == mgo.ErrNotFound {
+		return doc, errors.NotFoundf("desired bundle")
+	}
+	return doc, errors.Trace(err)
+}
+
+// RemoveDesiredBundle removes the bundle the model is expected to
+// match, so the model is no longer checked for drift.
+func (m *Model) RemoveDesiredBundle() error {
+	ops := []txn.Op{{
+		C:      desiredBundlesC,
+		Id:     m.st.docID(desiredBundleKey),
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	if err := m.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("desired bundle")
+	} else if err != nil {
+		return errors.Annotate(err, "removing desired bundle")
+	}
+	return errors.Trace(m.setBundleDriftStatus(false))
+}
+
+// SetBundleDrift records the drift of the model from its desired bundle.
+// While the model has drifted, the message of the model status says so.
+func (m *Model) SetBundleDrift(drift BundleDrift) error {
+	checked := drift.Checked.UTC().Round(time.Second)
+	ops := []txn.Op{{
+		C:      desiredBundlesC,
+		Id:     m.st.docID(desiredBundleKey),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{
+			{"checked", &checked},
+			{"diff", drift.Diff},
+			{"error", drift.Error},
+		}}},
+	}}
+	if err := m.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("desired bundle")
+	} else if err != nil {
+		return errors.Annotate(err, "setting bundle drift")
+	}
+	return errors.Trace(m.setBundleDriftStatus(drift.Drifted()))
+}
+
+// setBundleDriftStatus sets the message of the model status to report
+// drift, or clears it. The status is only changed while the model is
+// available, and the message is only cleared if it reports drift, so
+// that other statuses aren't hidden.
+func (m *Model) setBundleDriftStatus(drifted bool) error {
+	current, err := getStatus(m.st.db(), m.globalKey(), "model")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if current.Status != status.Available {
+		return nil
+	}
+	message := ""
+	if drifted {
+		message = BundleDriftStatusMessage
+	}
+	if current.Message == message || (current.Message != "" && current.Message != BundleDriftStatusMessage) {
+		return nil
+	}
+	return errors.Trace(m.SetStatus(status.StatusInfo{
+		Status:  status.Available,
+		Message: message,
+	}))
+}
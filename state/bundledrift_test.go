@@ -0,0 +1,120 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/state"
+)
+
+type BundleDriftSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&BundleDriftSuite{})
+
+func (s *BundleDriftSuite) TestSetDesiredBundle(c *gc.C) {
+	err := s.Model.SetDesiredBundle("applications: {}\n", []string{"machines: {}\n"}, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+
+	desired, err := s.Model.DesiredBundle()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(desired.Bundle, gc.Equals, "applications: {}\n")
+	c.Check(desired.Overlays, jc.DeepEquals, []string{"machines: {}\n"})
+	c.Check(desired.SetBy, gc.Equals, "admin")
+	c.Check(desired.Set.IsZero(), jc.IsFalse)
+	c.Check(desired.Drift, gc.IsNil)
+}
+
+func (s *BundleDriftSuite) TestSetDesiredBundleEmpty(c *gc.C) {
+	err := s.Model.SetDesiredBundle("", nil, "admin")
+	c.Assert(err, gc.ErrorMatches, "empty bundle not valid")
+}
+
+func (s *BundleDriftSuite) TestDesiredBundleNotFound(c *gc.C) {
+	_, err := s.Model.DesiredBundle()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *BundleDriftSuite) TestSetBundleDrift(c *gc.C) {
+	err := s.Model.SetDesiredBundle("applications: {}\n", nil, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+
+	checked := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	err = s.Model.SetBundleDrift(state.BundleDrift{
+		Checked: checked,
+		Diff:    "applications:\n  mysql:\n    missing: bundle\n",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	desired, err := s.Model.DesiredBundle()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(desired.Drift, gc.NotNil)
+	c.Check(desired.Drift.Checked.Equal(checked), jc.IsTrue)
+	c.Check(desired.Drift.Drifted(), jc.IsTrue)
+
+	modelStatus, err := s.Model.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(modelStatus.Status, gc.Equals, status.Available)
+	c.Check(modelStatus.Message, gc.Equals, state.BundleDriftStatusMessage)
+
+	err = s.Model.SetBundleDrift(state.BundleDrift{Checked: checked})
+	c.Assert(err, jc.ErrorIsNil)
+	modelStatus, err = s.Model.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(modelStatus.Message, gc.Equals, "")
+}
+
+func (s *BundleDriftSuite) TestSetBundleDriftKeepsOtherStatus(c *gc.C) {
+	err := s.Model.SetDesiredBundle("applications: {}\n", nil, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.Model.SetStatus(status.StatusInfo{Status: status.Busy, Message: "migrating"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.Model.SetBundleDrift(state.BundleDrift{Checked: time.Now(), Diff: "machines: {}\n"})
+	c.Assert(err, jc.ErrorIsNil)
+	modelStatus, err := s.Model.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(modelStatus.Status, gc.Equals, status.Busy)
+	c.Check(modelStatus.Message, gc.Equals, "migrating")
+}
+
+func (s *BundleDriftSuite) TestSetDesiredBundleResetsDrift(c *gc.C) {
+	err := s.Model.SetDesiredBundle("applications: {}\n", nil, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.Model.SetBundleDrift(state.BundleDrift{Checked: time.Now(), Diff: "machines: {}\n"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.Model.SetDesiredBundle("machines: {}\n", nil, "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	desired, err := s.Model.DesiredBundle()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(desired.Bundle, gc.Equals, "machines: {}\n")
+	c.Check(desired.SetBy, gc.Equals, "bob")
+	c.Check(desired.Drift, gc.IsNil)
+
+	modelStatus, err := s.Model.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(modelStatus.Message, gc.Equals, "")
+}
+
+func (s *BundleDriftSuite) TestRemoveDesiredBundle(c *gc.C) {
+	err := s.Model.SetDesiredBundle("applications: {}\n", nil, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.Model.RemoveDesiredBundle()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.Model.DesiredBundle()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.Model.RemoveDesiredBundle()
+	c.Assert(err, gc.ErrorMatches, "desired bundle not found")
+	err = s.Model.SetBundleDrift(state.BundleDrift{Checked: time.Now()})
+	c.Assert(err, gc.ErrorMatches, "desired bundle not found")
+}
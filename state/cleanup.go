@@ -61,6 +61,7 @@ const (
 	cleanupStorageForDyingModel  cleanupKind = "modelStorage"
 	cleanupForceStorage          cleanupKind = "forceStorage"
 	cleanupBranchesForDyingModel cleanupKind = "branches"
+	cleanupSecretBackendValue    cleanupKind = "secretBackendValue"
 )
 
 // cleanupDoc originally represented a set of documents that should be
@@ -206,6 +207,8 @@ func (st *State) Cleanup() (err error) {
 			err = st.cleanupForceStorage(args)
 		case cleanupBranchesForDyingModel:
 			err = st.cleanupBranchesForDyingModel(args)
+		case cleanupSecretBackendValue:
+			err = st.cleanupSecretBackendValue(doc.Prefix, args)
 		default:
 			err = errors.Errorf("unknown cleanup kind %q", doc.Kind)
 		}
@@ -45,6 +45,11 @@ type cloudCredentialDoc struct {
 	AuthType   string            `bson:"auth-type"`
 	Attributes map[string]string `bson:"attributes,omitempty"`
 
+	// AttributesBackend, if set, is the type of the external secret
+	// backend that the attributes are stored in, instead of in this
+	// document.
+	AttributesBackend string `bson:"attributes-backend,omitempty"`
+
 	// Invalid stores flag that indicates if a credential is invalid.
 	// Note that the credential is valid:
 	//  * if the flag is explicitly set to 'false'; or
@@ -64,21 +69,35 @@ type cloudCredentialDoc struct {
 
 // CloudCredential returns the cloud credential for the given tag.
 func (st *State) CloudCredential(tag names.CloudCredentialTag) (Credential, error) {
+	doc, err := st.cloudCredentialDoc(tag)
+	if err != nil {
+		return Credential{}, errors.Trace(err)
+	}
+	if err := st.fillCloudCredentialAttributes(&doc); err != nil {
+		return Credential{}, errors.Trace(err)
+	}
+	return Credential{doc}, nil
+}
+
+// cloudCredentialDoc returns the document of the cloud credential for
+// the given tag, without reading any attributes stored in an external
+// secret backend.
+func (st *State) cloudCredentialDoc(tag names.CloudCredentialTag) (cloudCredentialDoc, error) {
 	coll, cleanup := st.db().GetCollection(cloudCredentialsC)
 	defer cleanup()
 
 	var doc cloudCredentialDoc
 	err := coll.FindId(cloudCredentialDocID(tag)).One(&doc)
 	if err == mgo.ErrNotFound {
-		return Credential{}, errors.NotFoundf(
+		return doc, errors.NotFoundf(
 			"cloud credential %q", tag.Id(),
 		)
 	} else if err != nil {
-		return Credential{}, errors.Annotatef(
+		return doc, errors.Annotatef(
 			err, "getting cloud credential %q", tag.Id(),
 		)
 	}
-	return Credential{doc}, nil
+	return doc, nil
 }
 
 // CloudCredentials returns the user's cloud credentials for a given cloud,
@@ -100,6 +119,9 @@ func (st *State) CloudCredentials(user names.UserTag, cloudName string) (map[str
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+		if err := st.fillCloudCredentialAttributes(&doc); err != nil {
+			return nil, errors.Trace(err)
+		}
 		credentials[tag.Id()] = Credential{doc}
 	}
 	if err := iter.Close(); err != nil {
@@ -146,7 +168,7 @@ func (st *State) UpdateCloudCredential(tag names.CloudCredentialTag, credential
 	}
 	annotationMsg := "updating cloud credentials"
 
-	existing, err := st.CloudCredential(tag)
+	existing, err := st.cloudCredentialDoc(tag)
 	if err != nil && !errors.IsNotFound(err) {
 		return errors.Annotatef(err, "fetching cloud credentials")
 	}
@@ -163,6 +185,13 @@ func (st *State) UpdateCloudCredential(tag names.CloudCredentialTag, credential
 		}
 	}
 
+	// Attributes are stored in an external secret backend before
+	// the credential document refers to them.
+	attributesBackend, err := st.storeCloudCredentialAttributes(tag, credential.Attributes())
+	if err != nil {
+		return errors.Annotate(err, annotationMsg)
+	}
+
 	buildTxn := func(attempt int) ([]txn.Op, error) {
 		cloudName := tag.Cloud().Id()
 		aCloud, err := st.Cloud(cloudName)
@@ -174,19 +203,26 @@ func (st *State) UpdateCloudCredential(tag names.CloudCredentialTag, credential
 			return nil, errors.Trace(err)
 		}
 		if exists {
-			ops = append(ops, updateCloudCredentialOp(tag, credential))
+			ops = append(ops, updateCloudCredentialOp(tag, credential, attributesBackend))
 		} else {
 			annotationMsg = "creating cloud credential"
 			if credential.Invalid || credential.InvalidReason != "" {
 				return nil, errors.NotSupportedf("adding invalid credential")
 			}
-			ops = append(ops, createCloudCredentialOp(tag, credential))
+			ops = append(ops, createCloudCredentialOp(tag, credential, attributesBackend))
 		}
 		return ops, nil
 	}
 	if err := st.db().Run(buildTxn); err != nil {
 		return errors.Annotate(err, annotationMsg)
 	}
+	if exists && existing.AttributesBackend != "" && attributesBackend == "" {
+		// The attributes have moved back into the controller database.
+		if err := st.deleteCloudCredentialAttributes(tag, existing.AttributesBackend); err != nil {
+			logger.Warningf("could not remove credential %v attributes from %q secret backend: %v",
+				tag.Id(), existing.AttributesBackend, err)
+		}
+	}
 	if len(revert) > 0 {
 		for m, closer := range revert {
 			if err := m.maybeRevertModelStatus(); err != nil {
@@ -245,7 +281,7 @@ func (m *Model) maybeRevertModelStatus() error {
 // InvalidateCloudCredential marks a cloud credential with the given tag as invalid.
 func (st *State) InvalidateCloudCredential(tag names.CloudCredentialTag, reason string) error {
 	buildTxn := func(attempt int) ([]txn.Op, error) {
-		_, err := st.CloudCredential(tag)
+		_, err := st.cloudCredentialDoc(tag)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -269,54 +305,81 @@ func (st *State) InvalidateCloudCredential(tag names.CloudCredentialTag, reason
 
 // RemoveCloudCredential removes a cloud credential with the given tag.
 func (st *State) RemoveCloudCredential(tag names.CloudCredentialTag) error {
+	var attributesBackend string
 	buildTxn := func(attempt int) ([]txn.Op, error) {
-		_, err := st.CloudCredential(tag)
+		doc, err := st.cloudCredentialDoc(tag)
 		if errors.IsNotFound(err) {
 			return nil, jujutxn.ErrNoOperations
 		}
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+		attributesBackend = doc.AttributesBackend
 		return removeCloudCredentialOps(tag), nil
 	}
 	if err := st.db().Run(buildTxn); err != nil {
 		return errors.Annotate(err, "removing cloud credential")
 	}
+	if attributesBackend != "" {
+		if err := st.deleteCloudCredentialAttributes(tag, attributesBackend); err != nil {
+			return errors.Annotate(err, "removing cloud credential attributes")
+		}
+	}
 	return nil
 }
 
 // createCloudCredentialOp returns a txn.Op that will create
-// a cloud credential.
-func createCloudCredentialOp(tag names.CloudCredentialTag, cred cloud.Credential) txn.Op {
+// a cloud credential. If attributesBackend is set, the attributes
+// have been stored in that external secret backend and are not
+// recorded in the document.
+func createCloudCredentialOp(tag names.CloudCredentialTag, cred cloud.Credential, attributesBackend string) txn.Op {
+	doc := &cloudCredentialDoc{
+		Owner:             tag.Owner().Id(),
+		Cloud:             tag.Cloud().Id(),
+		Name:              tag.Name(),
+		AuthType:          string(cred.AuthType()),
+		Attributes:        cred.Attributes(),
+		AttributesBackend: attributesBackend,
+		Revoked:           cred.Revoked,
+	}
+	if attributesBackend != "" {
+		doc.Attributes = nil
+	}
 	return txn.Op{
 		C:      cloudCredentialsC,
 		Id:     cloudCredentialDocID(tag),
 		Assert: txn.DocMissing,
-		Insert: &cloudCredentialDoc{
-			Owner:      tag.Owner().Id(),
-			Cloud:      tag.Cloud().Id(),
-			Name:       tag.Name(),
-			AuthType:   string(cred.AuthType()),
-			Attributes: cred.Attributes(),
-			Revoked:    cred.Revoked,
-		},
+		Insert: doc,
 	}
 }
 
 // updateCloudCredentialOp returns a txn.Op that will update
-// a cloud credential.
-func updateCloudCredentialOp(tag names.CloudCredentialTag, cred cloud.Credential) txn.Op {
+// a cloud credential. If attributesBackend is set, the attributes
+// have been stored in that external secret backend and are removed
+// from the document.
+func updateCloudCredentialOp(tag names.CloudCredentialTag, cred cloud.Credential, attributesBackend string) txn.Op {
+	set := bson.D{
+		{"auth-type", string(cred.AuthType())},
+		{"revoked", cred.Revoked},
+		{"invalid", cred.Invalid},
+		{"invalid-reason", cred.InvalidReason},
+	}
+	var unset bson.D
+	if attributesBackend != "" {
+		set = append(set, bson.DocElem{"attributes-backend", attributesBackend})
+		unset = append(unset, bson.DocElem{"attributes", 1})
+	} else {
+		set = append(set, bson.DocElem{"attributes", cred.Attributes()})
+		unset = append(unset, bson.DocElem{"attributes-backend", 1})
+	}
 	return txn.Op{
 		C:      cloudCredentialsC,
 		Id:     cloudCredentialDocID(tag),
 		Assert: txn.DocExists,
-		Update: bson.D{{"$set", bson.D{
-			{"auth-type", string(cred.AuthType())},
-			{"attributes", cred.Attributes()},
-			{"revoked", cred.Revoked},
-			{"invalid", cred.Invalid},
-			{"invalid-reason", cred.InvalidReason},
-		}}},
+		Update: bson.D{
+			{"$set", set},
+			{"$unset", unset},
+		},
 	}
 }
 
@@ -438,6 +501,9 @@ func (st *State) AllCloudCredentials(user names.UserTag) ([]Credential, error) {
 
 	credentials := make([]Credential, len(docs))
 	for i, doc := range docs {
+		if err := st.fillCloudCredentialAttributes(&doc); err != nil {
+			return nil, errors.Trace(err)
+		}
 		credentials[i] = Credential{doc}
 	}
 	return credentials, nil
@@ -0,0 +1,294 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/juju/charm/v8"
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/environs/config"
+)
+
+// ConfigRevision is a recorded revision of the charm config of an
+// application, or of the model config.
+type ConfigRevision struct {
+	// Revision numbers the revisions of an entity's config, starting
+	// at 1.
+	Revision int
+
+	// Config holds all the config settings of the revision.
+	Config map[string]interface{}
+
+	// Author is the name of the user who made the change recorded by
+	// the revision. It is empty for changes made other than by setting
+	// or rolling back config, such as when deploying with config,
+	// deploying a bundle or committing a branch.
+	Author string
+
+	// Created is when the revision was recorded.
+	Created time.Time
+}
+
+type configRevisionDoc struct {
+	DocId     string      `bson:"_id"`
+	ModelUUID string      `bson:"model-uuid"`
+	Entity    string      `bson:"entity"`
+	Revision  int         `bson:"revision"`
+	Config    settingsMap `bson:"config"`
+	Author    string      `bson:"author,omitempty"`
+	Created   time.Time   `bson:"created"`
+}
+
+func (doc configRevisionDoc) revision() ConfigRevision {
+	return ConfigRevision{
+		Revision: doc.Revision,
+		Config:   doc.Config,
+		Author:   doc.Author,
+		Created:  doc.Created,
+	}
+}
+
+func configRevisionDocID(tag names.Tag, revision int) string {
+	return fmt.Sprintf("%s#%d", tag.String(), revision)
+}
+
+// configSettingsKey returns the settings key of the config whose
+// revisions are recorded for the entity: the charm config of an
+// application, or the model config.
+func (st *State) configSettingsKey(tag names.Tag) (string, error) {
+	switch tag := tag.(type) {
+	case names.ApplicationTag:
+		app, err := st.Application(tag.Id())
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		return app.charmConfigKey(), nil
+	case names.ModelTag:
+		if tag.Id() != st.ModelUUID() {
+			return "", errors.NotFoundf("model %q", tag.Id())
+		}
+		return modelGlobalKey, nil
+	}
+	return "", errors.NotValidf("config history of %q", tag.String())
+}
+
+// RecordConfigRevision records the current charm config of the
+// application, or model config, with the given tag as a new revision,
+// unless it is unchanged since the latest revision. The recorded, or
+// latest, revision is returned.
+//
+// Revisions are not recorded when the config is updated; only the
+// application and modelconfig facades record them, either side of
+// setting config. Config changed in any other way, such as when
+// deploying with config, deploying a bundle or committing a branch, is
+// recorded without an author the next time a revision is recorded.
+// Model defaults are not part of the model config recorded.
+func (st *State) RecordConfigRevision(tag names.Tag, author string) (ConfigRevision, error) {
+	key, err := st.configSettingsKey(tag)
+	if err != nil {
+		return ConfigRevision{}, errors.Trace(err)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		current, err := readSettingsDoc(st.db(), settingsC, key)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		latest, err := st.latestConfigRevision(tag)
+		if err != nil && !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		if err == nil && reflect.DeepEqual(map[string]interface{}(current.Settings), latest.Config) {
+			return nil, jujutxn.ErrNoOperations
+		}
+		doc := configRevisionDoc{
+			DocId:     st.docID(configRevisionDocID(tag, latest.Revision+1)),
+			ModelUUID: st.ModelUUID(),
+			Entity:    tag.String(),
+			Revision:  latest.Revision + 1,
+			Config:    current.Settings,
+			Author:    author,
+			Created:   st.clock().Now().UTC().Round(time.Second),
+		}
+		return []txn.Op{{
+			C:      settingsC,
+			Id:     st.docID(key),
+			Assert: bson.D{{"version", current.Version}},
+		}, {
+			C:      configRevisionsC,
+			Id:     doc.DocId,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		}}, nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		return ConfigRevision{}, errors.Annotatef(err, "recording config revision of %q", tag.String())
+	}
+	revision, err := st.latestConfigRevision(tag)
+	return revision, errors.Trace(err)
+}
+
+func (st *State) latestConfigRevision(tag names.Tag) (ConfigRevision, error) {
+	coll, closer := st.db().GetCollection(configRevisionsC)
+	defer closer()
+
+	var doc configRevisionDoc
+	err := coll.Find(bson.D{{"entity", tag.String()}}).Sort("-revision").One(&doc)
+	if err == mgo.ErrNotFound {
+		return ConfigRevision{}, errors.NotFoundf("config revisions of %q", tag.String())
+	} else if err != nil {
+		return ConfigRevision{}, errors.Trace(err)
+	}
+	return doc.revision(), nil
+}
+
+// ConfigRevisions returns the recorded revisions of the charm config of
+// the application, or model config, with the given tag, oldest first.
+func (st *State) ConfigRevisions(tag names.Tag) ([]ConfigRevision, error) {
+	coll, closer := st.db().GetCollection(configRevisionsC)
+	defer closer()
+
+	var docs []configRevisionDoc
+	if err := coll.Find(bson.D{{"entity", tag.String()}}).Sort("revision").All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]ConfigRevision, len(docs))
+	for i, doc := range docs {
+		result[i] = doc.revision()
+	}
+	return result, nil
+}
+
+// ConfigRevision returns the given revision of the charm config of the
+// application, or model config, with the given tag.
+func (st *State) ConfigRevision(tag names.Tag, revision int) (ConfigRevision, error) {
+	coll, closer := st.db().GetCollection(configRevisionsC)
+	defer closer()
+
+	var doc configRevisionDoc
+	err := coll.FindId(configRevisionDocID(tag, revision)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return ConfigRevision{}, errors.NotFoundf("revision %d of %q config", revision, tag.String())
+	} else if err != nil {
+		return ConfigRevision{}, errors.Trace(err)
+	}
+	return doc.revision(), nil
+}
+
+// nonRevertibleModelConfig holds the model config attributes which are
+// left unchanged when model config is rolled back.
+var nonRevertibleModelConfig = []string{
+	config.AgentVersionKey,
+	config.CharmHubURLKey,
+}
+
+// RollbackConfig sets the charm config of the application, or model
+// config, with the given tag to that of the given revision, and records
+// the change as a new revision by the author. The agent version and
+// charmhub URL are not rolled back with the model config.
+func (st *State) RollbackConfig(tag names.Tag, revision int, author string) error {
+	target, err := st.ConfigRevision(tag, revision)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Record any changes made since the latest revision, so that they
+	// aren't lost from the history.
+	if _, err := st.RecordConfigRevision(tag, ""); err != nil {
+		return errors.Trace(err)
+	}
+
+	switch tag := tag.(type) {
+	case names.ApplicationTag:
+		err = st.rollbackCharmConfig(tag, target.Config)
+	case names.ModelTag:
+		err = st.rollbackModelConfig(target.Config)
+	default:
+		err = errors.NotValidf("config history of %q", tag.String())
+	}
+	if err != nil {
+		return errors.Annotatef(err, "rolling back %q config to revision %d", tag.String(), revision)
+	}
+	_, err = st.RecordConfigRevision(tag, author)
+	return errors.Trace(err)
+}
+
+func (st *State) rollbackCharmConfig(tag names.ApplicationTag, target map[string]interface{}) error {
+	app, err := st.Application(tag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	current, err := app.CharmConfig(model.GenerationMaster)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	changes := make(charm.Settings)
+	for name := range current {
+		if _, ok := target[name]; !ok {
+			changes[name] = nil
+		}
+	}
+	for name, value := range target {
+		changes[name] = value
+	}
+	return errors.Trace(app.UpdateCharmConfig(model.GenerationMaster, changes))
+}
+
+func (st *State) rollbackModelConfig(target map[string]interface{}) error {
+	m, err := st.Model()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	current, err := readSettings(st.db(), settingsC, modelGlobalKey)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	nonRevertible := set.NewStrings(nonRevertibleModelConfig...)
+	attrs := make(map[string]interface{}, len(target))
+	for name, value := range target {
+		if !nonRevertible.Contains(name) {
+			attrs[name] = value
+		}
+	}
+	var remove []string
+	for _, name := range current.Keys() {
+		if _, ok := target[name]; !ok && !nonRevertible.Contains(name) {
+			remove = append(remove, name)
+		}
+	}
+	return errors.Trace(m.UpdateModelConfig(attrs, remove))
+}
+
+// removeConfigRevisionsOps returns the operations that remove the
+// recorded config revisions of the entity with the given tag.
+func removeConfigRevisionsOps(st *State, tag names.Tag) ([]txn.Op, error) {
+	coll, closer := st.db().GetCollection(configRevisionsC)
+	defer closer()
+
+	var docs []struct {
+		DocId string `bson:"_id"`
+	}
+	err := coll.Find(bson.D{{"entity", tag.String()}}).Select(bson.D{{"_id", 1}}).All(&docs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      configRevisionsC,
+			Id:     doc.DocId,
+			Remove: true,
+		}
+	}
+	return ops, nil
+}
@@ -0,0 +1,190 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/charm/v8"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/state"
+)
+
+type ConfigHistorySuite struct {
+	ConnSuite
+	app *state.Application
+}
+
+var _ = gc.Suite(&ConfigHistorySuite{})
+
+func (s *ConfigHistorySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.app = s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func (s *ConfigHistorySuite) setBlogTitle(c *gc.C, title interface{}) {
+	err := s.app.UpdateCharmConfig(model.GenerationMaster, charm.Settings{"blog-title": title})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ConfigHistorySuite) TestRecordConfigRevision(c *gc.C) {
+	tag := s.app.ApplicationTag()
+	s.setBlogTitle(c, "first")
+	rev, err := s.State.RecordConfigRevision(tag, "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(rev.Revision, gc.Equals, 1)
+	c.Check(rev.Author, gc.Equals, "bob")
+	c.Check(rev.Config, jc.DeepEquals, map[string]interface{}{"blog-title": "first"})
+	c.Check(rev.Created.IsZero(), jc.IsFalse)
+
+	s.setBlogTitle(c, "second")
+	rev, err = s.State.RecordConfigRevision(tag, "alice")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(rev.Revision, gc.Equals, 2)
+
+	revs, err := s.State.ConfigRevisions(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(revs, gc.HasLen, 2)
+	c.Check(revs[0].Config, jc.DeepEquals, map[string]interface{}{"blog-title": "first"})
+	c.Check(revs[0].Author, gc.Equals, "bob")
+	c.Check(revs[1].Config, jc.DeepEquals, map[string]interface{}{"blog-title": "second"})
+	c.Check(revs[1].Author, gc.Equals, "alice")
+}
+
+func (s *ConfigHistorySuite) TestRecordConfigRevisionUnchanged(c *gc.C) {
+	tag := s.app.ApplicationTag()
+	s.setBlogTitle(c, "first")
+	_, err := s.State.RecordConfigRevision(tag, "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	rev, err := s.State.RecordConfigRevision(tag, "alice")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(rev.Revision, gc.Equals, 1)
+	c.Check(rev.Author, gc.Equals, "bob")
+
+	revs, err := s.State.ConfigRevisions(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(revs, gc.HasLen, 1)
+}
+
+func (s *ConfigHistorySuite) TestRecordConfigRevisionModel(c *gc.C) {
+	tag := s.Model.ModelTag()
+	rev, err := s.State.RecordConfigRevision(tag, "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(rev.Revision, gc.Equals, 1)
+	c.Check(rev.Config["name"], gc.Equals, s.Model.Name())
+}
+
+func (s *ConfigHistorySuite) TestRecordConfigRevisionInvalidEntity(c *gc.C) {
+	_, err := s.State.RecordConfigRevision(names.NewMachineTag("0"), "bob")
+	c.Assert(err, gc.ErrorMatches, `config history of "machine-0" not valid`)
+	_, err = s.State.RecordConfigRevision(names.NewApplicationTag("mysql"), "bob")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ConfigHistorySuite) TestConfigRevisionNotFound(c *gc.C) {
+	_, err := s.State.ConfigRevision(s.app.ApplicationTag(), 3)
+	c.Assert(err, gc.ErrorMatches, `revision 3 of "application-wordpress" config not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ConfigHistorySuite) TestRollbackConfig(c *gc.C) {
+	tag := s.app.ApplicationTag()
+	s.setBlogTitle(c, "first")
+	_, err := s.State.RecordConfigRevision(tag, "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	// A change that isn't recorded is kept in the history by the
+	// rollback.
+	s.setBlogTitle(c, "second")
+
+	err = s.State.RollbackConfig(tag, 1, "alice")
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg, err := s.app.CharmConfig(model.GenerationMaster)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg["blog-title"], gc.Equals, "first")
+
+	revs, err := s.State.ConfigRevisions(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(revs, gc.HasLen, 3)
+	c.Check(revs[1].Config, jc.DeepEquals, map[string]interface{}{"blog-title": "second"})
+	c.Check(revs[1].Author, gc.Equals, "")
+	c.Check(revs[2].Config, jc.DeepEquals, map[string]interface{}{"blog-title": "first"})
+	c.Check(revs[2].Author, gc.Equals, "alice")
+}
+
+func (s *ConfigHistorySuite) TestRollbackConfigResetsAddedSettings(c *gc.C) {
+	tag := s.app.ApplicationTag()
+	_, err := s.State.RecordConfigRevision(tag, "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	s.setBlogTitle(c, "first")
+
+	err = s.State.RollbackConfig(tag, 1, "alice")
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg, err := s.app.CharmConfig(model.GenerationMaster)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg["blog-title"], gc.Equals, "My Title")
+}
+
+func (s *ConfigHistorySuite) TestRollbackModelConfig(c *gc.C) {
+	tag := s.Model.ModelTag()
+	_, err := s.State.RecordConfigRevision(tag, "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.Model.UpdateModelConfig(map[string]interface{}{"default-series": "focal"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RollbackConfig(tag, 1, "alice")
+	c.Assert(err, jc.ErrorIsNil)
+
+	first, err := s.State.ConfigRevision(tag, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err := s.Model.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg.AllAttrs()["default-series"], gc.Equals, first.Config["default-series"])
+}
+
+func (s *ConfigHistorySuite) TestRollbackModelConfigKeepsNonRevertibleSettings(c *gc.C) {
+	tag := s.Model.ModelTag()
+	_, err := s.State.RecordConfigRevision(tag, "bob")
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err := s.Model.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	charmHubURL, ok := cfg.CharmHubURL()
+	c.Assert(ok, jc.IsTrue)
+
+	// Revisions recorded before the charmhub URL was added to
+	// model config don't have it.
+	coll := s.State.MongoSession().DB("juju").C("configRevisions")
+	err = coll.UpdateId(s.State.ModelUUID()+":"+tag.String()+"#1", bson.D{{
+		"$unset", bson.D{{"config.charmhub-url", 1}},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RollbackConfig(tag, 1, "alice")
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg, err = s.Model.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	url, ok := cfg.CharmHubURL()
+	c.Check(ok, jc.IsTrue)
+	c.Check(url, gc.Equals, charmHubURL)
+}
+
+func (s *ConfigHistorySuite) TestRemoveApplicationRemovesHistory(c *gc.C) {
+	tag := s.app.ApplicationTag()
+	s.setBlogTitle(c, "first")
+	_, err := s.State.RecordConfigRevision(tag, "bob")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.app.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	revs, err := s.State.ConfigRevisions(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(revs, gc.HasLen, 0)
+}
@@ -86,7 +86,7 @@ func (st *State) ControllerConfig() (jujucontroller.Config, error) {
 	if err != nil {
 		return nil, errors.Annotatef(err, "controller %q", st.ControllerUUID())
 	}
-	return settings.Map(), nil
+	return st.decryptControllerConfig(settings.Map())
 }
 
 // UpdateControllerConfig allows changing some of the configuration
@@ -109,7 +109,10 @@ func (st *State) UpdateControllerConfig(updateAttrs map[string]interface{}, remo
 	settings.Update(updateAttrs)
 
 	// Ensure the resulting config is still valid.
-	newValues := settings.Map()
+	newValues, err := st.decryptControllerConfig(settings.Map())
+	if err != nil {
+		return errors.Trace(err)
+	}
 	_, err = jujucontroller.NewConfig(
 		newValues[jujucontroller.ControllerUUIDKey].(string),
 		newValues[jujucontroller.CACertKey].(string),
@@ -118,11 +121,53 @@ func (st *State) UpdateControllerConfig(updateAttrs map[string]interface{}, remo
 	if err != nil {
 		return errors.Trace(err)
 	}
+	encrypted, err := st.encryptControllerConfig(updateAttrs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	settings.Update(encrypted)
 
 	_, ops := settings.settingsUpdateOps()
 	return errors.Trace(settings.write(ops))
 }
 
+// encryptControllerConfig returns a copy of the controller config
+// attributes with the values of the secret attributes encrypted with
+// the controller's secrets key, so that they can't be read from the
+// database or a backup.
+func (st *State) encryptControllerConfig(attrs map[string]interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(attrs))
+	for name, value := range attrs {
+		if s, ok := value.(string); ok && s != "" && jujucontroller.SecretAttribute(name) {
+			ciphertext, err := st.encryptWithSecretsKey([]byte(s), name)
+			if err != nil {
+				return nil, errors.Annotatef(err, "encrypting %q", name)
+			}
+			value = ciphertext
+		}
+		result[name] = value
+	}
+	return result, nil
+}
+
+// decryptControllerConfig returns a copy of the controller config
+// attributes with the values encrypted by encryptControllerConfig
+// decrypted.
+func (st *State) decryptControllerConfig(attrs map[string]interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(attrs))
+	for name, value := range attrs {
+		if ciphertext, ok := value.([]byte); ok && jujucontroller.SecretAttribute(name) {
+			plaintext, err := st.decryptWithSecretsKey(ciphertext, name)
+			if err != nil {
+				return nil, errors.Annotatef(err, "decrypting %q", name)
+			}
+			value = string(plaintext)
+		}
+		result[name] = value
+	}
+	return result, nil
+}
+
 func (st *State) checkValidControllerConfig(updateAttrs map[string]interface{}, removeAttrs []string) error {
 	for k := range updateAttrs {
 		if err := checkUpdateControllerConfig(k); err != nil {
@@ -132,6 +132,36 @@ func (s *ControllerSuite) TestUpdateControllerConfigRemoveYieldsDefaults(c *gc.C
 	c.Assert(newCfg.AuditLogCaptureArgs(), gc.Equals, false)
 }
 
+func (s *ControllerSuite) TestUpdateControllerConfigEncryptsSecrets(c *gc.C) {
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		controller.SecretBackendVaultAddress: "https://vault.example.com:8200",
+		controller.SecretBackendVaultToken:   "s3cret",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	// Updating other attributes leaves the secret ones readable.
+	err = s.State.UpdateControllerConfig(map[string]interface{}{
+		controller.AuditingEnabled: true,
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	coll, closer := state.GetRawCollection(s.State, "controllers")
+	defer closer()
+	var doc struct {
+		Settings map[string]interface{} `bson:"settings"`
+	}
+	err = coll.FindId(state.ControllerSettingsGlobalKey).One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(doc.Settings[controller.SecretBackendVaultAddress], gc.Equals, "https://vault.example.com:8200")
+	stored, ok := doc.Settings[controller.SecretBackendVaultToken].([]byte)
+	c.Assert(ok, jc.IsTrue, gc.Commentf("stored token %#v", doc.Settings[controller.SecretBackendVaultToken]))
+	c.Check(string(stored), gc.Not(jc.Contains), "s3cret")
+
+	cfg, err := s.State.ControllerConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg.SecretBackendVault().Token, gc.Equals, "s3cret")
+	c.Check(cfg.AuditingEnabled(), jc.IsTrue)
+}
+
 func (s *ControllerSuite) TestUpdateControllerConfigRejectsDisallowedUpdates(c *gc.C) {
 	// Sanity check.
 	c.Assert(controller.AllowedUpdateConfigAttributes.Contains(controller.APIPort), jc.IsFalse)
@@ -68,6 +68,7 @@ var (
 	NewEntityWatcher              = newEntityWatcher
 	ApplicationHasConnectedOffers = applicationHasConnectedOffers
 	NewActionNotificationWatcher  = newActionNotificationWatcher
+	MaxScannedLines               = &maxScannedLines
 )
 
 type (
@@ -66,6 +66,10 @@ type InitializeParams struct {
 
 	// AdminPassword holds the password for the initial user.
 	AdminPassword string
+
+	// DataDir is the data dir of the controller machine, which holds
+	// the keys that secret values are encrypted with.
+	DataDir string
 }
 
 // Validate checks that the state initialization parameters are valid.
@@ -160,6 +164,7 @@ func Initialize(args InitializeParams) (_ *Controller, err error) {
 		MongoSession:       args.MongoSession,
 		NewPolicy:          args.NewPolicy,
 		InitDatabaseFunc:   InitDatabase,
+		DataDir:            args.DataDir,
 	})
 	if err != nil {
 		return nil, errors.Annotate(err, "opening controller")
@@ -226,6 +231,11 @@ func Initialize(args InitializeParams) (_ *Controller, err error) {
 		userGlobalKey(userAccessID(args.ControllerModelArgs.Owner)),
 		permission.AdminAccess)
 
+	controllerConfig, err := st.encryptControllerConfig(args.ControllerConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	bakeryConfig := st.NewBakeryConfig()
 	initBakeryConfigOp, err := bakeryConfig.InitialiseBakeryConfigOp()
 	if err != nil {
@@ -270,7 +280,7 @@ func Initialize(args InitializeParams) (_ *Controller, err error) {
 			Insert: &hostedModelCountDoc{},
 		},
 		initBakeryConfigOp,
-		createSettingsOp(controllersC, ControllerSettingsGlobalKey, args.ControllerConfig),
+		createSettingsOp(controllersC, ControllerSettingsGlobalKey, controllerConfig),
 		createSettingsOp(globalSettingsC, cloudGlobalKey(args.Cloud.Name), args.ControllerInheritedConfig),
 	)
 	for k, v := range args.Cloud.RegionConfig {
@@ -280,8 +290,16 @@ func Initialize(args InitializeParams) (_ *Controller, err error) {
 		ops = append(ops, createSettingsOp(globalSettingsC, regionSettingsGlobalKey(args.Cloud.Name, k), v))
 	}
 
+	secretBackend, err := st.secretBackendConfig(args.ControllerModelArgs.Config.SecretBackend(), args.ControllerConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	for tag, cred := range args.CloudCredentials {
-		ops = append(ops, createCloudCredentialOp(tag, cred))
+		attributesBackend, err := putCloudCredentialAttributes(secretBackend, tag, cred.Attributes())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, createCloudCredentialOp(tag, cred, attributesBackend))
 	}
 	ops = append(ops, modelOps...)
 	ops = append(ops, storagePoolOps...)
@@ -432,16 +432,20 @@ type LogRecord struct {
 // LogTailerParams specifies the filtering a LogTailer should apply to
 // logs in order to decide which to return.
 type LogTailerParams struct {
-	StartID       int64
-	StartTime     time.Time
-	MinLevel      loggo.Level
-	InitialLines  int
-	NoTail        bool
-	IncludeEntity []string
-	ExcludeEntity []string
-	IncludeModule []string
-	ExcludeModule []string
-	Oplog         *mgo.Collection // For testing only
+	StartID         int64
+	StartTime       time.Time
+	EndTime         time.Time
+	MinLevel        loggo.Level
+	InitialLines    int
+	NoTail          bool
+	IncludeEntity   []string
+	ExcludeEntity   []string
+	IncludeModule   []string
+	ExcludeModule   []string
+	IncludeLocation []string
+	ExcludeLocation []string
+	IncludeMessage  string
+	Oplog           *mgo.Collection // For testing only
 }
 
 // oplogOverlap is used to decide on the initial oplog timestamp to
@@ -465,6 +469,15 @@ var maxRecentLogIds = int(oplogOverlap.Minutes() * 150000)
 // so that we can iterate them in the correct order.
 var maxInitialLines = 10000
 
+// maxScannedLines limits the number of documents we will read, looking
+// for the initial lines which match a message pattern, so that a rare
+// pattern doesn't scan the whole logs collection.
+var maxScannedLines = 100000
+
+// scanBatchSize is the number of documents fetched at a time when
+// looking for the initial lines which match a message pattern.
+const scanBatchSize = 1000
+
 // LogTailerState describes the methods on State required for logging to
 // the database.
 type LogTailerState interface {
@@ -477,15 +490,27 @@ type LogTailerState interface {
 // NewLogTailer returns a LogTailer which filters according to the
 // parameters given.
 func NewLogTailer(st LogTailerState, params LogTailerParams) (LogTailer, error) {
+	// The message is matched here rather than with a $regex in the
+	// selector, so that it is interpreted by the same regular expression
+	// engine that validated it.
+	var message *regexp.Regexp
+	if params.IncludeMessage != "" {
+		var err error
+		if message, err = regexp.Compile(params.IncludeMessage); err != nil {
+			return nil, errors.NotValidf("message pattern %q", params.IncludeMessage)
+		}
+	}
 	session := st.MongoSession().Copy()
 	t := &logTailer{
 		modelUUID:       st.ModelUUID(),
 		session:         session,
 		logsColl:        session.DB(logsDB).C(logCollectionName(st.ModelUUID())).With(session),
 		params:          params,
+		message:         message,
 		logCh:           make(chan *LogRecord),
 		recentIds:       newRecentIdTracker(maxRecentLogIds),
 		maxInitialLines: maxInitialLines,
+		maxScannedLines: maxScannedLines,
 	}
 	t.tomb.Go(func() error {
 		defer close(t.logCh)
@@ -502,11 +527,13 @@ type logTailer struct {
 	session         *mgo.Session
 	logsColl        *mgo.Collection
 	params          LogTailerParams
+	message         *regexp.Regexp
 	logCh           chan *LogRecord
 	lastID          int64
 	lastTime        time.Time
 	recentIds       *recentIdTracker
 	maxInitialLines int
+	maxScannedLines int
 }
 
 // Logs implements the LogTailer interface.
@@ -546,6 +573,12 @@ func (t *logTailer) loop() error {
 	return t.tailOplog()
 }
 
+// matchesMessage reports whether the message of the log document
+// matches the IncludeMessage parameter, if one was given.
+func (t *logTailer) matchesMessage(doc *logDoc) bool {
+	return t.message == nil || t.message.MatchString(doc.Message)
+}
+
 func (t *logTailer) processReversed(query *mgo.Query) error {
 	// We must sort by exactly the fields in the index and exactly reversed
 	// so that Mongo will use the index and not try to sort in memory.
@@ -561,11 +594,19 @@ func (t *logTailer) processReversed(query *mgo.Query) error {
 			t.params.InitialLines, maxInitialLines)
 	}
 	query.Sort("-t", "-_id")
-	query.Limit(t.params.InitialLines)
+	if t.message == nil {
+		// Without a message filter, every document found is returned.
+		query.Limit(t.params.InitialLines)
+	} else {
+		// Otherwise read the documents a batch at a time, and give
+		// up after maxScannedLines of them.
+		query.Batch(scanBatchSize)
+	}
 	iter := query.Iter()
 	defer iter.Close()
 	queue := make([]logDoc, t.params.InitialLines)
 	cur := t.params.InitialLines
+	scanned := 0
 	var doc logDoc
 	for iter.Next(&doc) {
 		select {
@@ -573,6 +614,14 @@ func (t *logTailer) processReversed(query *mgo.Query) error {
 			return errors.Trace(tomb.ErrDying)
 		default:
 		}
+		if scanned == t.maxScannedLines {
+			return errors.Errorf("reached the maximum of %d log records scanned looking for %d lines matching %q",
+				t.maxScannedLines, t.params.InitialLines, t.params.IncludeMessage)
+		}
+		scanned++
+		if !t.matchesMessage(&doc) {
+			continue
+		}
 		cur--
 		queue[cur] = doc
 		if cur == 0 {
@@ -645,6 +694,11 @@ func (t *logTailer) processCollection() error {
 			}
 			deserialisationFailures = 0
 		}
+		if !t.matchesMessage(&doc) {
+			// Still track the id so that the oplog traversal skips it.
+			t.recentIds.Add(doc.Id)
+			continue
+		}
 		select {
 		case <-t.tomb.Dying():
 			return tomb.ErrDying
@@ -727,6 +781,9 @@ func (t *logTailer) tailOplog() error {
 				}
 				deserialisationFailures = 0
 			}
+			if !t.matchesMessage(doc) {
+				continue
+			}
 			select {
 			case <-t.tomb.Dying():
 				return tomb.ErrDying
@@ -738,8 +795,15 @@ func (t *logTailer) tailOplog() error {
 
 func (t *logTailer) paramsToSelector(params LogTailerParams, prefix string) bson.D {
 	sel := bson.D{}
+	timeRange := bson.M{}
 	if !params.StartTime.IsZero() {
-		sel = append(sel, bson.DocElem{"t", bson.M{"$gte": params.StartTime.UnixNano()}})
+		timeRange["$gte"] = params.StartTime.UnixNano()
+	}
+	if !params.EndTime.IsZero() {
+		timeRange["$lt"] = params.EndTime.UnixNano()
+	}
+	if len(timeRange) > 0 {
+		sel = append(sel, bson.DocElem{"t", timeRange})
 	}
 	if params.MinLevel > loggo.UNSPECIFIED {
 		sel = append(sel, bson.DocElem{"v", bson.M{"$gte": int(params.MinLevel)}})
@@ -760,6 +824,14 @@ func (t *logTailer) paramsToSelector(params LogTailerParams, prefix string) bson
 		sel = append(sel,
 			bson.DocElem{"m", bson.M{"$not": bson.RegEx{Pattern: makeModulePattern(params.ExcludeModule)}}})
 	}
+	if len(params.IncludeLocation) > 0 {
+		sel = append(sel,
+			bson.DocElem{"l", bson.RegEx{Pattern: makeLocationPattern(params.IncludeLocation)}})
+	}
+	if len(params.ExcludeLocation) > 0 {
+		sel = append(sel,
+			bson.DocElem{"l", bson.M{"$not": bson.RegEx{Pattern: makeLocationPattern(params.ExcludeLocation)}}})
+	}
 	if prefix != "" {
 		for i, elem := range sel {
 			sel[i].Name = prefix + elem.Name
@@ -786,6 +858,16 @@ func makeModulePattern(modules []string) string {
 	return `^(` + strings.Join(patterns, "|") + `)(\..+)?$`
 }
 
+// makeLocationPattern matches locations of the form "file.go:123". A
+// location given without a line number matches every line in the file.
+func makeLocationPattern(locations []string) string {
+	var patterns []string
+	for _, location := range locations {
+		patterns = append(patterns, regexp.QuoteMeta(location))
+	}
+	return `^(` + strings.Join(patterns, "|") + `)(:[0-9]+)?$`
+}
+
 func newRecentIdTracker(maxLen int) *recentIdTracker {
 	return &recentIdTracker{
 		ids: deque.NewWithMaxLen(maxLen),
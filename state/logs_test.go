@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names/v4"
 	jc "github.com/juju/testing/checkers"
@@ -274,6 +275,36 @@ func (s *LogTailerSuite) TestTimeFiltering(c *gc.C) {
 
 }
 
+func (s *LogTailerSuite) TestEndTimeFiltering(c *gc.C) {
+	// Add 5 logs that should be returned.
+	threshT := coretesting.NonZeroTime()
+	want := logTemplate{Message: "want"}
+	s.writeLogsT(c, s.otherUUID, threshT.Add(-5*time.Second), threshT.Add(-time.Millisecond), 5, want)
+
+	// Add 5 logs at or after the end time that shouldn't be returned.
+	s.writeLogsT(c,
+		s.otherUUID,
+		threshT, threshT.Add(5*time.Second), 5,
+		logTemplate{Message: "dont want"},
+	)
+
+	tailer, err := state.NewLogTailer(s.otherState, state.LogTailerParams{
+		EndTime: threshT,
+		NoTail:  true,
+		Oplog:   s.oplogColl,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer tailer.Stop()
+	s.assertTailer(c, tailer, 5, want)
+
+	select {
+	case log, ok := <-tailer.Logs():
+		c.Assert(ok, jc.IsFalse, gc.Commentf("unexpected log %#v", log))
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for tailer to finish")
+	}
+}
+
 func (s *LogTailerSuite) TestOplogTransition(c *gc.C) {
 	// Ensure that logs aren't repeated as the log tailer moves from
 	// reading from the logs collection to tailing the oplog.
@@ -627,6 +658,113 @@ func (s *LogTailerSuite) TestIncludeExcludeModule(c *gc.C) {
 	s.checkLogTailerFiltering(c, s.otherState, params, writeLogs, assert)
 }
 
+func (s *LogTailerSuite) TestIncludeLocation(c *gc.C) {
+	loc0 := logTemplate{Location: "uniter.go:12"}
+	loc1 := logTemplate{Location: "uniter.go:99"}
+	loc2 := logTemplate{Location: "runner.go:5"}
+	loc3 := logTemplate{Location: "unit.go:12"}
+	writeLogs := func() {
+		s.writeLogs(c, s.otherUUID, 1, loc0)
+		s.writeLogs(c, s.otherUUID, 1, loc1)
+		s.writeLogs(c, s.otherUUID, 1, loc2)
+		s.writeLogs(c, s.otherUUID, 1, loc3)
+	}
+	params := state.LogTailerParams{
+		IncludeLocation: []string{"uniter.go", "runner.go:5"},
+	}
+	assert := func(tailer state.LogTailer) {
+		s.assertTailer(c, tailer, 1, loc0)
+		s.assertTailer(c, tailer, 1, loc1)
+		s.assertTailer(c, tailer, 1, loc2)
+	}
+	s.checkLogTailerFiltering(c, s.otherState, params, writeLogs, assert)
+}
+
+func (s *LogTailerSuite) TestExcludeLocation(c *gc.C) {
+	loc0 := logTemplate{Location: "uniter.go:12"}
+	loc1 := logTemplate{Location: "uniter.go:99"}
+	loc2 := logTemplate{Location: "runner.go:5"}
+	writeLogs := func() {
+		s.writeLogs(c, s.otherUUID, 1, loc0)
+		s.writeLogs(c, s.otherUUID, 1, loc1)
+		s.writeLogs(c, s.otherUUID, 1, loc2)
+	}
+	params := state.LogTailerParams{
+		ExcludeLocation: []string{"uniter.go:12", "runner.go"},
+	}
+	assert := func(tailer state.LogTailer) {
+		s.assertTailer(c, tailer, 1, loc1)
+	}
+	s.checkLogTailerFiltering(c, s.otherState, params, writeLogs, assert)
+}
+
+func (s *LogTailerSuite) TestIncludeMessage(c *gc.C) {
+	hook := logTemplate{Message: `ran "install" hook`}
+	other := logTemplate{Message: "connection established"}
+	failed := logTemplate{Message: `hook "start" failed`}
+	writeLogs := func() {
+		s.writeLogs(c, s.otherUUID, 2, hook)
+		s.writeLogs(c, s.otherUUID, 1, other)
+		s.writeLogs(c, s.otherUUID, 1, failed)
+	}
+	params := state.LogTailerParams{
+		IncludeMessage: `(^ran .* hook$|failed$)`,
+	}
+	assert := func(tailer state.LogTailer) {
+		s.assertTailer(c, tailer, 2, hook)
+		s.assertTailer(c, tailer, 1, failed)
+	}
+	s.checkLogTailerFiltering(c, s.otherState, params, writeLogs, assert)
+}
+
+func (s *LogTailerSuite) TestIncludeMessageInitialLines(c *gc.C) {
+	expected := logTemplate{Message: "hook failed"}
+	s.writeLogs(c, s.otherUUID, 3, expected)
+	s.writeLogs(c, s.otherUUID, 5, logTemplate{Message: "connection established"})
+
+	tailer, err := state.NewLogTailer(s.otherState, state.LogTailerParams{
+		InitialLines:   2,
+		IncludeMessage: "failed$",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer tailer.Stop()
+
+	// Should see the last 2 matching lines, even though later lines
+	// didn't match.
+	s.assertTailer(c, tailer, 2, expected)
+}
+
+func (s *LogTailerSuite) TestIncludeMessageInitialLinesScanLimit(c *gc.C) {
+	s.PatchValue(state.MaxScannedLines, 5)
+	s.writeLogs(c, s.otherUUID, 1, logTemplate{Message: "hook failed"})
+	s.writeLogs(c, s.otherUUID, 5, logTemplate{Message: "connection established"})
+
+	tailer, err := state.NewLogTailer(s.otherState, state.LogTailerParams{
+		InitialLines:   2,
+		IncludeMessage: "failed$",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer tailer.Stop()
+
+	select {
+	case _, ok := <-tailer.Logs():
+		// No lines are returned once the limit is reached.
+		c.Assert(ok, jc.IsFalse)
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for logs channel to close")
+	}
+	c.Assert(tailer.Stop(), gc.ErrorMatches,
+		`reached the maximum of 5 log records scanned looking for 2 lines matching "failed\$"`)
+}
+
+func (s *LogTailerSuite) TestIncludeMessageNotValid(c *gc.C) {
+	_, err := state.NewLogTailer(s.otherState, state.LogTailerParams{
+		IncludeMessage: "hook (",
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `message pattern "hook \(" not valid`)
+}
+
 func (s *LogTailerSuite) checkLogTailerFiltering(
 	c *gc.C,
 	st *state.State,
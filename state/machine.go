@@ -2054,17 +2054,7 @@ func (m *Machine) VolumeAttachments() ([]VolumeAttachment, error) {
 
 // AddAction is part of the ActionReceiver interface.
 func (m *Machine) AddAction(operationID, name string, payload map[string]interface{}) (Action, error) {
-	spec, ok := actions.PredefinedActionsSpec[name]
-	if !ok {
-		return nil, errors.Errorf("cannot add action %q to a machine; only predefined actions allowed", name)
-	}
-
-	// Reject bad payloads before attempting to insert defaults.
-	err := spec.ValidateParams(payload)
-	if err != nil {
-		return nil, err
-	}
-	payloadWithDefaults, err := spec.InsertDefaults(payload)
+	payloadWithDefaults, err := m.prepareActionPayload(name, payload)
 	if err != nil {
 		return nil, err
 	}
@@ -2077,6 +2067,22 @@ func (m *Machine) AddAction(operationID, name string, payload map[string]interfa
 	return model.EnqueueAction(operationID, m.Tag(), name, payloadWithDefaults)
 }
 
+// prepareActionPayload validates the named predefined action and its
+// payload, returning the payload with defaults filled in.
+func (m *Machine) prepareActionPayload(name string, payload map[string]interface{}) (map[string]interface{}, error) {
+	spec, ok := actions.PredefinedActionsSpec[name]
+	if !ok {
+		return nil, errors.Errorf("cannot add action %q to a machine; only predefined actions allowed", name)
+	}
+
+	// Reject bad payloads before attempting to insert defaults.
+	err := spec.ValidateParams(payload)
+	if err != nil {
+		return nil, err
+	}
+	return spec.InsertDefaults(payload)
+}
+
 // CancelAction is part of the ActionReceiver interface.
 func (m *Machine) CancelAction(action Action) (Action, error) {
 	return action.Finish(ActionResults{Status: ActionCancelled})
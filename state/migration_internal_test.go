@@ -121,6 +121,9 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		controllerUsersC,
 		// userenvnameC is just to provide a unique key constraint.
 		usermodelnameC,
+		// Quotas are set by the controller's administrators, and
+		// aren't migrated.
+		quotasC,
 		// Metrics aren't migrated.
 		metricsC,
 		// Backup and restore information is not migrated.
@@ -176,6 +179,29 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// Recreated whilst migrating actions.
 		actionNotificationsC,
 
+		// Action schedules aren't migrated; they need to be added
+		// again in the target model.
+		actionSchedulesC,
+		// Archived operations stay with the blob storage of the
+		// source controller.
+		operationArchivesC,
+		// Webhooks are registered with the controller delivering
+		// the notifications, and are added again in the target model.
+		webhooksC,
+
+		// Config history isn't migrated; the history in the target
+		// model starts from the migrated config.
+		configRevisionsC,
+		// The desired bundle is registered again in the target
+		// model, where the drift is checked afresh.
+		desiredBundlesC,
+		// Secrets are encrypted with a key specific to the controller,
+		// and aren't yet migrated.
+		secretMetadataC,
+		secretRevisionsC,
+		secretPermissionsC,
+		secretConsumersC,
+
 		// Global settings store controller specific configuration settings
 		// and are not to be migrated.
 		globalSettingsC,
@@ -386,6 +386,7 @@ func (ctlr *Controller) NewModel(args ModelArgs) (_ *Model, _ *State, err error)
 		st.newPolicy,
 		st.clock(),
 		st.runTransactionObserver,
+		st.dataDir,
 	)
 	if err != nil {
 		return nil, nil, errors.Annotate(err, "could not create state for new model")
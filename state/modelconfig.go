@@ -385,6 +385,15 @@ func (m *Model) UpdateModelConfig(updateAttrs map[string]interface{}, removeAttr
 	if err != nil {
 		return errors.Trace(err)
 	}
+	// Values stored in the secret backend being replaced would be
+	// unreadable, so they're moved to the new one.
+	var migration *secretBackendMigration
+	if from, to := oldConfig.SecretBackend(), validCfg.SecretBackend(); from != to {
+		migration, err = st.migrateSecretBackend(from, to)
+		if err != nil {
+			return errors.Annotatef(err, "changing secret backend from %q to %q", from, to)
+		}
+	}
 
 	validAttrs := validCfg.AllAttrs()
 	for k := range oldConfig.AllAttrs() {
@@ -397,7 +406,15 @@ func (m *Model) UpdateModelConfig(updateAttrs map[string]interface{}, removeAttr
 
 	modelSettings.Update(validAttrs)
 	_, ops := modelSettings.settingsUpdateOps()
-	return modelSettings.write(ops)
+	if migration == nil {
+		return modelSettings.write(ops)
+	}
+	ops = append(ops, migration.ops...)
+	if err := modelSettings.write(ops); err != nil {
+		return err
+	}
+	migration.removeStale()
+	return nil
 }
 
 type modelConfigSourceFunc func() (attrValues, error)
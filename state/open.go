@@ -47,6 +47,12 @@ type OpenParams struct {
 	// InitDatabaseFunc, if non-nil, is a function that will be called
 	// just after the state database is opened.
 	InitDatabaseFunc InitDatabaseFunc
+
+	// DataDir is the data dir of the controller machine. It holds the
+	// keys that secret values are encrypted with, and the values stored
+	// by the file secret backend. It must be set for secret values to
+	// be read or written.
+	DataDir string
 }
 
 // Validate validates the OpenParams.
@@ -90,8 +96,9 @@ func open(
 	newPolicy NewPolicyFunc,
 	clock clock.Clock,
 	runTransactionObserver RunTransactionObserverFunc,
+	dataDir string,
 ) (*State, error) {
-	st, err := newState(controllerModelTag, controllerModelTag, session, newPolicy, clock, runTransactionObserver)
+	st, err := newState(controllerModelTag, controllerModelTag, session, newPolicy, clock, runTransactionObserver, dataDir)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -119,6 +126,7 @@ func newState(
 	newPolicy NewPolicyFunc,
 	clock clock.Clock,
 	runTransactionObserver RunTransactionObserverFunc,
+	dataDir string,
 ) (_ *State, err error) {
 
 	defer func() {
@@ -162,6 +170,7 @@ func newState(
 		database:               db,
 		newPolicy:              newPolicy,
 		runTransactionObserver: runTransactionObserver,
+		dataDir:                dataDir,
 	}
 	if newPolicy != nil {
 		st.policy = newPolicy(st)
@@ -75,6 +75,11 @@ type operationDoc struct {
 	// If not explicitly set, this is derived from the
 	// status of the associated actions.
 	Status ActionStatus `bson:"status"`
+
+	// Rollout holds the batching parameters and task queue for a
+	// rolling operation. It is nil for operations whose tasks are
+	// all enqueued up front.
+	Rollout *rolloutDoc `bson:"rollout,omitempty"`
 }
 
 // operation represents a group of associated actions.
@@ -0,0 +1,142 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/state/storage"
+)
+
+// OperationArchive describes a batch of pruned operations archived in
+// the controller's blob storage.
+type OperationArchive struct {
+	Id      string
+	Created time.Time
+
+	// Earliest and Latest are the completion times of the first and
+	// last operations in the archive.
+	Earliest time.Time
+	Latest   time.Time
+
+	// Count is the number of operations in the archive.
+	Count int
+
+	// Size is the size of the archive in bytes.
+	Size int64
+}
+
+type operationArchiveDoc struct {
+	DocId       string    `bson:"_id"`
+	ModelUUID   string    `bson:"model-uuid"`
+	StoragePath string    `bson:"storage-path"`
+	Created     time.Time `bson:"created"`
+	Earliest    time.Time `bson:"earliest"`
+	Latest      time.Time `bson:"latest"`
+	Count       int       `bson:"count"`
+	Size        int64     `bson:"size"`
+}
+
+func (m *Model) operationArchive(doc operationArchiveDoc) OperationArchive {
+	return OperationArchive{
+		Id:       m.st.localID(doc.DocId),
+		Created:  doc.Created,
+		Earliest: doc.Earliest,
+		Latest:   doc.Latest,
+		Count:    doc.Count,
+		Size:     doc.Size,
+	}
+}
+
+// AddOperationArchive stores an archive of pruned operations, read from
+// r, in the controller's blob storage. The Earliest, Latest and Count
+// fields of the archive describe its contents; the rest are filled in.
+func (m *Model) AddOperationArchive(archive OperationArchive, r io.Reader, size int64) (OperationArchive, error) {
+	seq, err := sequence(m.st, "operationarchive")
+	if err != nil {
+		return OperationArchive{}, errors.Trace(err)
+	}
+	id := fmt.Sprint(seq)
+	doc := operationArchiveDoc{
+		DocId:       m.st.docID(id),
+		ModelUUID:   m.st.ModelUUID(),
+		StoragePath: fmt.Sprintf("operationarchives/%s.jsonl", id),
+		Created:     m.st.clock().Now(),
+		Earliest:    archive.Earliest,
+		Latest:      archive.Latest,
+		Count:       archive.Count,
+		Size:        size,
+	}
+
+	stor := storage.NewStorage(m.st.ModelUUID(), m.st.MongoSession())
+	if err := stor.Put(doc.StoragePath, r, size); err != nil {
+		return OperationArchive{}, errors.Annotate(err, "storing operation archive")
+	}
+	ops := []txn.Op{{
+		C:      operationArchivesC,
+		Id:     doc.DocId,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := m.st.db().RunTransaction(ops); err != nil {
+		if removeErr := stor.Remove(doc.StoragePath); removeErr != nil {
+			logger.Warningf("cannot remove unrecorded operation archive %q: %v", doc.StoragePath, removeErr)
+		}
+		return OperationArchive{}, errors.Annotate(err, "recording operation archive")
+	}
+	return m.operationArchive(doc), nil
+}
+
+// OperationArchives returns the archives holding operations which
+// completed in the given time window, oldest first. A zero from or
+// to time leaves that end of the window open.
+func (m *Model) OperationArchives(from, to time.Time) ([]OperationArchive, error) {
+	coll, closer := m.st.db().GetCollection(operationArchivesC)
+	defer closer()
+
+	var query bson.D
+	if !from.IsZero() {
+		query = append(query, bson.DocElem{"latest", bson.D{{"$gte", from}}})
+	}
+	if !to.IsZero() {
+		query = append(query, bson.DocElem{"earliest", bson.D{{"$lt", to}}})
+	}
+	var docs []operationArchiveDoc
+	if err := coll.Find(query).Sort("earliest", "_id").All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]OperationArchive, len(docs))
+	for i, doc := range docs {
+		result[i] = m.operationArchive(doc)
+	}
+	return result, nil
+}
+
+// OpenOperationArchive returns a reader for the contents of the
+// operation archive with the given id.
+func (m *Model) OpenOperationArchive(id string) (io.ReadCloser, error) {
+	coll, closer := m.st.db().GetCollection(operationArchivesC)
+	defer closer()
+
+	var doc operationArchiveDoc
+	err := coll.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("operation archive %q", id)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	stor := storage.NewStorage(m.st.ModelUUID(), m.st.MongoSession())
+	r, _, err := stor.Get(doc.StoragePath)
+	if err != nil {
+		return nil, errors.Annotatef(err, "opening operation archive %q", id)
+	}
+	return r, nil
+}
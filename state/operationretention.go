@@ -0,0 +1,325 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// OperationRetention controls how long the results of operations are
+// kept, and what happens to them when they are pruned.
+type OperationRetention struct {
+	// MaxAge is how long operations are kept after they complete.
+	// If it is zero, operations are not pruned by age.
+	MaxAge time.Duration
+
+	// MaxSizeMB is the size the actions collection is kept under.
+	// If it is zero, operations are not pruned by size.
+	MaxSizeMB int
+
+	// ApplicationMaxAge overrides MaxAge for the tasks run on the
+	// units of the named applications. An operation is kept until
+	// the longest maximum age of any of its tasks has passed; a
+	// maximum age of zero keeps it indefinitely. Pruning by size
+	// takes no account of the overrides.
+	ApplicationMaxAge map[string]time.Duration
+
+	// Archive, if set, is called with each batch of operations
+	// before they are removed. If it returns an error, the batch
+	// is not removed.
+	Archive func([]OperationInfo) error
+}
+
+// maxAge returns how long an operation with tasks run on the given
+// receivers is kept, or zero if it is kept indefinitely.
+func (r OperationRetention) maxAge(receivers []string) time.Duration {
+	if len(receivers) == 0 {
+		return r.MaxAge
+	}
+	var result time.Duration
+	for _, receiver := range receivers {
+		maxAge := r.MaxAge
+		if names.IsValidUnit(receiver) {
+			appName, _ := names.UnitApplication(receiver)
+			if appMaxAge, ok := r.ApplicationMaxAge[appName]; ok {
+				maxAge = appMaxAge
+			}
+		}
+		if maxAge == 0 {
+			return 0
+		}
+		if maxAge > result {
+			result = maxAge
+		}
+	}
+	return result
+}
+
+// minAge returns the shortest time any operation is kept, or zero
+// if none are pruned by age.
+func (r OperationRetention) minAge() time.Duration {
+	result := r.MaxAge
+	for _, maxAge := range r.ApplicationMaxAge {
+		if maxAge > 0 && (result == 0 || maxAge < result) {
+			result = maxAge
+		}
+	}
+	return result
+}
+
+// PruneOperationsWithRetention removes operation entries and their
+// sub-tasks according to the retention policy. Operations are removed
+// once they are older than their maximum age, and then oldest first
+// until the actions collection is smaller than the maximum size.
+func PruneOperationsWithRetention(st *State, retention OperationRetention) error {
+	// There may be older actions without parent operations so try those first.
+	hasNoOperation := bson.D{{"$or", []bson.D{
+		{{"operation", ""}},
+		{{"operation", bson.D{{"$exists", false}}}},
+	}}}
+	err := pruneCollection(st, retention.MaxAge, retention.MaxSizeMB, actionsC, "completed", hasNoOperation, GoTime)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var archive archiveFunc
+	if retention.Archive != nil {
+		archive = func(ids []interface{}) error {
+			localIDs := make([]string, 0, len(ids))
+			for _, id := range ids {
+				if docID, ok := id.(string); ok {
+					localIDs = append(localIDs, st.localID(docID))
+				}
+			}
+			operations, err := operationsWithActions(st, localIDs)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			return retention.Archive(operations)
+		}
+	}
+
+	maxAge := retention.MaxAge
+	if len(retention.ApplicationMaxAge) > 0 {
+		if err := pruneOperationsByRetention(st, retention, archive); err != nil {
+			return errors.Trace(err)
+		}
+		// The operations have been pruned by age already, taking
+		// the overrides into account.
+		if retention.MaxSizeMB == 0 {
+			return nil
+		}
+		maxAge = 0
+	}
+
+	// First calculate the average ratio of tasks to operations. Since deletion is
+	// done at the operation level, and any associated tasks are then deleted, but
+	// the actions collection is where the disk space goes, we approximate the
+	// number of operations to delete to achieve a given size deduction based on
+	// the average ratio of number of operations to tasks.
+	operationsColl, closer := st.db().GetRawCollection(operationsC)
+	defer closer()
+	operationsCount, err := operationsColl.Count()
+	if err != nil {
+		return errors.Annotate(err, "retrieving operations collection count")
+	}
+	actionsColl, closer := st.db().GetRawCollection(actionsC)
+	defer closer()
+	actionsCount, err := actionsColl.Count()
+	if err != nil {
+		return errors.Annotate(err, "retrieving actions collection count")
+	}
+	sizeFactor := float64(actionsCount) / float64(operationsCount)
+
+	err = pruneCollectionAndChildren(st, maxAge, retention.MaxSizeMB, operationsC, "completed", actionsC, "operation", nil, sizeFactor, GoTime, archive)
+	return errors.Trace(err)
+}
+
+// pruneOperationsByRetention removes the operations which are older
+// than the maximum age for the receivers of their tasks.
+func pruneOperationsByRetention(st *State, retention OperationRetention, archive archiveFunc) error {
+	minAge := retention.minAge()
+	if minAge == 0 {
+		return nil
+	}
+	operations, closer := st.db().GetCollection(operationsC)
+	defer closer()
+	actions, closer := st.db().GetCollection(actionsC)
+	defer closer()
+
+	now := st.clock().Now()
+	iter := operations.Find(bson.D{
+		{"completed", bson.D{{"$gt", time.Time{}}, {"$lt", now.Add(-minAge)}}},
+	}).Select(bson.D{{"_id", 1}, {"completed", 1}}).Iter()
+	defer iter.Close()
+
+	var deleted int
+	prune := func(docs []operationDoc) error {
+		localIDs := make([]string, len(docs))
+		for i, doc := range docs {
+			localIDs[i] = st.localID(doc.DocId)
+		}
+		var tasks []actionDoc
+		err := actions.Find(bson.D{{"operation", bson.D{{"$in", localIDs}}}}).
+			Select(bson.D{{"operation", 1}, {"receiver", 1}}).All(&tasks)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		receivers := make(map[string][]string)
+		for _, task := range tasks {
+			receivers[task.Operation] = append(receivers[task.Operation], task.Receiver)
+		}
+
+		var expiredDocIDs []interface{}
+		var expiredIDs []string
+		for i, doc := range docs {
+			maxAge := retention.maxAge(receivers[localIDs[i]])
+			if maxAge == 0 || !doc.Completed.Before(now.Add(-maxAge)) {
+				continue
+			}
+			expiredDocIDs = append(expiredDocIDs, doc.DocId)
+			expiredIDs = append(expiredIDs, localIDs[i])
+		}
+		if len(expiredIDs) == 0 {
+			return nil
+		}
+		if archive != nil {
+			if err := archive(expiredDocIDs); err != nil {
+				return errors.Annotate(err, "archiving batch")
+			}
+		}
+		// Pruning bypasses transactions, as elsewhere.
+		if _, err := operations.Writeable().RemoveAll(bson.D{{"_id", bson.D{{"$in", expiredDocIDs}}}}); err != nil {
+			return errors.Annotate(err, "removing operations")
+		}
+		if _, err := actions.Writeable().RemoveAll(bson.D{{"operation", bson.D{{"$in", expiredIDs}}}}); err != nil {
+			return errors.Annotate(err, "removing tasks")
+		}
+		deleted += len(expiredIDs)
+		return nil
+	}
+
+	var doc operationDoc
+	var batch []operationDoc
+	for iter.Next(&doc) {
+		batch = append(batch, doc)
+		if len(batch) < historyPruneBatchSize {
+			continue
+		}
+		if err := prune(batch); err != nil {
+			return errors.Trace(err)
+		}
+		batch = nil
+	}
+	if err := iter.Close(); err != nil {
+		return errors.Annotate(err, "reading operations")
+	}
+	if len(batch) > 0 {
+		if err := prune(batch); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if deleted > 0 {
+		logger.Infof("operations retention pruning: %d operations deleted", deleted)
+	}
+	return nil
+}
+
+// operationsWithActions returns the operations with the given ids,
+// together with all of their tasks, in the order they completed.
+func operationsWithActions(st *State, ids []string) ([]OperationInfo, error) {
+	operations, closer := st.db().GetCollection(operationsC)
+	defer closer()
+
+	docIDs := make([]string, len(ids))
+	for i, id := range ids {
+		docIDs[i] = st.docID(id)
+	}
+	var docs []operationDoc
+	err := operations.Find(bson.D{{"_id", bson.D{{"$in", docIDs}}}}).
+		Sort("completed", "_id").All(&docs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return withActions(st, docs)
+}
+
+// withActions returns the given operations together with all of the
+// details of their tasks.
+func withActions(st *State, docs []operationDoc) ([]OperationInfo, error) {
+	if len(docs) == 0 {
+		return nil, nil
+	}
+	actions, closer := st.db().GetCollection(actionsC)
+	defer closer()
+
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		ids[i] = st.localID(doc.DocId)
+	}
+	var actionDocs []actionDoc
+	err := actions.Find(bson.D{{"operation", bson.D{{"$in", ids}}}}).
+		Sort("_id").All(&actionDocs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	operationActions := make(map[string][]actionDoc)
+	for _, action := range actionDocs {
+		operationActions[action.Operation] = append(operationActions[action.Operation], action)
+	}
+
+	result := make([]OperationInfo, len(docs))
+	for i, doc := range docs {
+		actions := operationActions[ids[i]]
+		taskStatus := make([]ActionStatus, len(actions))
+		result[i].Actions = make([]Action, len(actions))
+		for j, action := range actions {
+			result[i].Actions[j] = newAction(st, action)
+			taskStatus[j] = action.Status
+		}
+		result[i].Operation = newOperation(st, doc, taskStatus)
+	}
+	return result, nil
+}
+
+// ExportOperations returns the operations which completed in the given
+// time window, oldest first, with all of the details of their tasks.
+// A zero from or to time leaves that end of the window open. The
+// second result reports whether more operations match than the limit.
+func (m *Model) ExportOperations(from, to time.Time, offset, limit int) ([]OperationInfo, bool, error) {
+	operations, closer := m.st.db().GetCollection(operationsC)
+	defer closer()
+
+	completed := bson.D{{"$gt", time.Time{}}}
+	if !from.IsZero() {
+		completed = bson.D{{"$gte", from}}
+	}
+	if !to.IsZero() {
+		completed = append(completed, bson.DocElem{"$lt", to})
+	}
+	query := operations.Find(bson.D{{"completed", completed}})
+	nominalCount, err := query.Count()
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	if offset != 0 {
+		query = query.Skip(offset)
+	}
+	if limit <= 0 {
+		limit = defaultMaxOperationsLimit
+	}
+	var docs []operationDoc
+	if err := query.Limit(limit).Sort("completed", "_id").All(&docs); err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	result, err := withActions(m.st, docs)
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	return result, nominalCount > offset+len(docs), nil
+}
@@ -0,0 +1,166 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type OperationRetentionSuite struct {
+	statetesting.StateWithWallClockSuite
+	clock *testclock.Clock
+}
+
+var _ = gc.Suite(&OperationRetentionSuite{})
+
+func (s *OperationRetentionSuite) SetUpTest(c *gc.C) {
+	s.StateWithWallClockSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Now())
+	err := s.State.SetClockForTesting(s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *OperationRetentionSuite) makeUnit(c *gc.C, appName string) *state.Unit {
+	ch := s.Factory.MakeCharm(c, &factory.CharmParams{Name: "dummy"})
+	application := s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: appName, Charm: ch})
+	return s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+}
+
+func (s *OperationRetentionSuite) assertOperationCount(c *gc.C, unit *state.Unit, tasksPerOperation, expected int) {
+	actions, err := unit.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, tasksPerOperation*expected)
+}
+
+func (s *OperationRetentionSuite) TestApplicationMaxAge(c *gc.C) {
+	short := s.makeUnit(c, "short")
+	long := s.makeUnit(c, "long")
+	forever := s.makeUnit(c, "forever")
+	for _, unit := range []*state.Unit{short, long, forever} {
+		state.PrimeOperations(c, s.clock.Now().Add(-2*time.Hour), unit, 2, 1)
+		state.PrimeOperations(c, s.clock.Now().Add(-48*time.Hour), unit, 2, 1)
+	}
+
+	err := state.PruneOperationsWithRetention(s.State, state.OperationRetention{
+		MaxAge: time.Hour,
+		ApplicationMaxAge: map[string]time.Duration{
+			"long":    24 * time.Hour,
+			"forever": 0,
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertOperationCount(c, short, 1, 0)
+	s.assertOperationCount(c, long, 1, 2)
+	s.assertOperationCount(c, forever, 1, 4)
+	ops, err := s.Model.AllOperations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ops, gc.HasLen, 6)
+}
+
+func (s *OperationRetentionSuite) TestArchive(c *gc.C) {
+	unit := s.makeUnit(c, "dummy")
+	state.PrimeOperations(c, s.clock.Now(), unit, 2, 2)
+	state.PrimeOperations(c, s.clock.Now().Add(-10*time.Hour), unit, 3, 2)
+
+	var archived []state.OperationInfo
+	err := state.PruneOperationsWithRetention(s.State, state.OperationRetention{
+		MaxAge: time.Hour,
+		Archive: func(operations []state.OperationInfo) error {
+			archived = append(archived, operations...)
+			return nil
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(archived, gc.HasLen, 3)
+	for _, info := range archived {
+		c.Assert(info.Actions, gc.HasLen, 2)
+		c.Assert(info.Actions[0].Receiver(), gc.Equals, unit.Name())
+	}
+	s.assertOperationCount(c, unit, 2, 2)
+}
+
+func (s *OperationRetentionSuite) TestArchiveErrorKeepsOperations(c *gc.C) {
+	unit := s.makeUnit(c, "dummy")
+	state.PrimeOperations(c, s.clock.Now().Add(-10*time.Hour), unit, 3, 2)
+
+	err := state.PruneOperationsWithRetention(s.State, state.OperationRetention{
+		MaxAge: time.Hour,
+		Archive: func([]state.OperationInfo) error {
+			return errors.New("boom")
+		},
+	})
+	c.Assert(err, gc.ErrorMatches, ".*boom")
+	s.assertOperationCount(c, unit, 2, 3)
+}
+
+func (s *OperationRetentionSuite) TestExportOperations(c *gc.C) {
+	unit := s.makeUnit(c, "dummy")
+	now := s.clock.Now()
+	state.PrimeOperations(c, now.Add(-3*time.Hour), unit, 2, 1)
+	state.PrimeOperations(c, now.Add(-2*time.Hour), unit, 3, 1)
+	state.PrimeOperations(c, now.Add(-time.Hour), unit, 1, 1)
+	// Incomplete operations are never exported.
+	state.PrimeOperations(c, time.Time{}, unit, 1, 1)
+
+	all, truncated, err := s.Model.ExportOperations(time.Time{}, time.Time{}, 0, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(truncated, jc.IsFalse)
+	c.Assert(all, gc.HasLen, 6)
+
+	window, truncated, err := s.Model.ExportOperations(now.Add(-150*time.Minute), now.Add(-90*time.Minute), 0, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(truncated, jc.IsTrue)
+	c.Assert(window, gc.HasLen, 2)
+	c.Assert(window[0].Actions, gc.HasLen, 1)
+
+	rest, truncated, err := s.Model.ExportOperations(now.Add(-150*time.Minute), now.Add(-90*time.Minute), 2, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(truncated, jc.IsFalse)
+	c.Assert(rest, gc.HasLen, 1)
+}
+
+func (s *OperationRetentionSuite) TestOperationArchives(c *gc.C) {
+	now := s.clock.Now().Round(time.Second)
+	content := []byte("{\"operation\":\"operation-1\"}\n")
+	added, err := s.Model.AddOperationArchive(state.OperationArchive{
+		Earliest: now.Add(-3 * time.Hour),
+		Latest:   now.Add(-2 * time.Hour),
+		Count:    1,
+	}, bytes.NewReader(content), int64(len(content)))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(added.Count, gc.Equals, 1)
+	c.Assert(added.Size, gc.Equals, int64(len(content)))
+
+	archives, err := s.Model.OperationArchives(now.Add(-150*time.Minute), time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(archives, gc.HasLen, 1)
+	c.Assert(archives[0].Id, gc.Equals, added.Id)
+
+	archives, err = s.Model.OperationArchives(now.Add(-time.Hour), time.Time{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(archives, gc.HasLen, 0)
+
+	r, err := s.Model.OpenOperationArchive(added.Id)
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, content)
+
+	_, err = s.Model.OpenOperationArchive("666")
+	c.Assert(err, gc.ErrorMatches, `operation archive "666" not found`)
+}
@@ -145,6 +145,7 @@ func OpenStatePool(args OpenParams) (_ *StatePool, err error) {
 		args.NewPolicy,
 		args.Clock,
 		args.RunTransactionObserver,
+		args.DataDir,
 	)
 	if err != nil {
 		session.Close()
@@ -270,6 +271,7 @@ func (p *StatePool) openState(modelUUID string) (*State, error) {
 		modelTag, p.systemState.controllerModelTag,
 		session, p.systemState.newPolicy, p.systemState.stateClock,
 		p.systemState.runTransactionObserver,
+		p.systemState.dataDir,
 	)
 	if err != nil {
 		return nil, errors.Trace(err)
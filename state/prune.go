@@ -23,16 +23,17 @@ func pruneCollection(
 	collectionName string, ageField string, filter bson.D,
 	timeUnit TimeUnit,
 ) error {
-	return pruneCollectionAndChildren(mb, maxHistoryTime, maxHistoryMB, collectionName, ageField, "", "", filter, 1, timeUnit)
+	return pruneCollectionAndChildren(mb, maxHistoryTime, maxHistoryMB, collectionName, ageField, "", "", filter, 1, timeUnit, nil)
 }
 
 // pruneCollectionAndChildren removes collection entries until
 // only entries newer than <maxLogTime> remain and also ensures
 // that the collection (or child collection if specified) is smaller
-// than <maxLogsMB> after the deletion.
+// than <maxLogsMB> after the deletion. If archive is not nil, it is
+// called with the ids of each batch of entries before they are removed.
 func pruneCollectionAndChildren(mb modelBackend, maxHistoryTime time.Duration, maxHistoryMB int,
 	collectionName, ageField, childCollectionName, parentRefField string,
-	filter bson.D, sizeFactor float64, timeUnit TimeUnit,
+	filter bson.D, sizeFactor float64, timeUnit TimeUnit, archive archiveFunc,
 ) error {
 	// NOTE(axw) we require a raw collection to obtain the size of the
 	// collection. Take care to include model-uuid in queries where
@@ -57,6 +58,7 @@ func pruneCollectionAndChildren(mb modelBackend, maxHistoryTime time.Duration, m
 		ageField:        ageField,
 		filter:          filter,
 		timeUnit:        timeUnit,
+		archive:         archive,
 	}
 	if err := p.validate(); err != nil {
 		return errors.Trace(err)
@@ -84,6 +86,11 @@ const historyPruneProgressSeconds = 15
 
 type doneCheck func() (bool, error)
 
+// archiveFunc is called with the document ids of a batch of entries
+// before they are pruned. If it returns an error, the entries are
+// not removed.
+type archiveFunc func(ids []interface{}) error
+
 type TimeUnit string
 
 const (
@@ -109,6 +116,8 @@ type collectionPruner struct {
 
 	ageField string
 	timeUnit TimeUnit
+
+	archive archiveFunc
 }
 
 func (p *collectionPruner) validate() error {
@@ -157,7 +166,7 @@ func (p *collectionPruner) pruneByAge() error {
 		return errors.Trace(err)
 	}
 	logTemplate := fmt.Sprintf("%s age pruning (%s): %%d rows deleted", p.coll.Name, modelName)
-	deleted, err := deleteInBatches(p.coll, p.childColl, p.parentRefField, iter, logTemplate, loggo.INFO, noEarlyFinish)
+	deleted, err := deleteInBatches(p.coll, p.childColl, p.parentRefField, iter, logTemplate, loggo.INFO, noEarlyFinish, p.archive)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -258,7 +267,7 @@ func (p *collectionPruner) pruneBySize() error {
 			return true, nil
 		}
 		return false, nil
-	})
+	}, p.archive)
 
 	if err != nil {
 		return errors.Trace(err)
@@ -277,10 +286,12 @@ func deleteInBatches(
 	logTemplate string,
 	logLevel loggo.Level,
 	shouldStop doneCheck,
+	archive archiveFunc,
 ) (int, error) {
 	var doc bson.M
 	chunk := coll.Bulk()
 	chunkSize := 0
+	var chunkIds []interface{}
 
 	var childChunk *mgo.Bulk
 	if childColl != nil {
@@ -292,6 +303,7 @@ func deleteInBatches(
 	for iter.Next(&doc) {
 		parentId := doc["_id"]
 		chunk.Remove(bson.D{{"_id", parentId}})
+		chunkIds = append(chunkIds, parentId)
 		chunkSize++
 		if childChunk != nil {
 			if idStr, ok := parentId.(string); ok {
@@ -302,6 +314,11 @@ func deleteInBatches(
 			}
 		}
 		if chunkSize == historyPruneBatchSize {
+			if archive != nil {
+				if err := archive(chunkIds); err != nil {
+					return 0, errors.Annotate(err, "archiving batch")
+				}
+			}
 			_, err := chunk.Run()
 			// NotFound indicates that records were already deleted.
 			if err != nil && err != mgo.ErrNotFound {
@@ -311,6 +328,7 @@ func deleteInBatches(
 			deleted += chunkSize
 			chunk = coll.Bulk()
 			chunkSize = 0
+			chunkIds = nil
 
 			if childChunk != nil {
 				_, err := childChunk.Run()
@@ -342,6 +360,11 @@ func deleteInBatches(
 	}
 
 	if chunkSize > 0 {
+		if archive != nil {
+			if err := archive(chunkIds); err != nil {
+				return 0, errors.Annotate(err, "archiving remainder")
+			}
+		}
 		_, err := chunk.Run()
 		if err != nil && err != mgo.ErrNotFound {
 			return 0, errors.Annotate(err, "removing remainder")
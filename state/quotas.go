@@ -0,0 +1,377 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/quota"
+)
+
+// quotaDoc records the quota limits set for a model, or for all the
+// models owned by a user.
+//
+// Adds counts the transactions that have added resources limited by
+// the quota. Each of them asserts the count seen when the usage was
+// checked and increments it, so that concurrent additions can't both
+// pass the check against the same usage.
+type quotaDoc struct {
+	DocID  string            `bson:"_id"`
+	Limits map[string]uint64 `bson:"limits"`
+	Adds   int64             `bson:"adds"`
+}
+
+func (doc quotaDoc) limits() quota.Limits {
+	limits := make(quota.Limits, len(doc.Limits))
+	for r, limit := range doc.Limits {
+		limits[quota.Resource(r)] = limit
+	}
+	return limits
+}
+
+// quotaKey returns the key of the quota document for a model or user.
+func quotaKey(tag names.Tag) (string, error) {
+	switch tag := tag.(type) {
+	case names.ModelTag:
+		return modelKey(tag.Id()), nil
+	case names.UserTag:
+		return userGlobalKey(userAccessID(tag)), nil
+	}
+	return "", errors.NotValidf("quota entity %q", tag)
+}
+
+// QuotaLimits returns the quota limits set for the model or user with
+// the given tag. The limits for a user apply to the total used by all
+// of the models they own.
+func (st *State) QuotaLimits(tag names.Tag) (quota.Limits, error) {
+	key, err := quotaKey(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	quotas, closer := st.db().GetCollection(quotasC)
+	defer closer()
+
+	var doc quotaDoc
+	if err := quotas.FindId(key).One(&doc); err == mgo.ErrNotFound {
+		return quota.Limits{}, nil
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get quotas for %s", names.ReadableString(tag))
+	}
+	return doc.limits(), nil
+}
+
+// SetQuotaLimits sets the given quota limits for the model or user with
+// the given tag, replacing any existing limits for the same resources,
+// and removes the limits on the resources in reset. Lowering a limit
+// below the amount already used doesn't remove anything, but prevents
+// more being added.
+func (st *State) SetQuotaLimits(tag names.Tag, limits quota.Limits, reset []quota.Resource) error {
+	if err := limits.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	for _, r := range reset {
+		if err := r.Validate(); err != nil {
+			return errors.Trace(err)
+		}
+		if _, ok := limits[r]; ok {
+			return errors.Errorf("cannot set and reset %s quota", r)
+		}
+	}
+	key, err := quotaKey(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if modelTag, ok := tag.(names.ModelTag); ok {
+		models, closer := st.db().GetCollection(modelsC)
+		defer closer()
+		if n, err := models.FindId(modelTag.Id()).Count(); err != nil {
+			return errors.Trace(err)
+		} else if n == 0 {
+			return errors.NotFoundf("model %q", modelTag.Id())
+		}
+	}
+
+	buildTxn := func(int) ([]txn.Op, error) {
+		quotas, closer := st.db().GetCollection(quotasC)
+		defer closer()
+		n, err := quotas.FindId(key).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if n == 0 {
+			if len(limits) == 0 {
+				return nil, jujutxn.ErrNoOperations
+			}
+			doc := quotaDoc{
+				DocID:  key,
+				Limits: make(map[string]uint64, len(limits)),
+			}
+			for r, limit := range limits {
+				doc.Limits[string(r)] = limit
+			}
+			return []txn.Op{{
+				C:      quotasC,
+				Id:     key,
+				Assert: txn.DocMissing,
+				Insert: &doc,
+			}}, nil
+		}
+
+		var set, unset bson.D
+		for r, limit := range limits {
+			set = append(set, bson.DocElem{"limits." + string(r), limit})
+		}
+		for _, r := range reset {
+			unset = append(unset, bson.DocElem{"limits." + string(r), nil})
+		}
+		var update bson.D
+		if len(set) > 0 {
+			update = append(update, bson.DocElem{"$set", set})
+		}
+		if len(unset) > 0 {
+			update = append(update, bson.DocElem{"$unset", unset})
+		}
+		if len(update) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{{
+			C:      quotasC,
+			Id:     key,
+			Assert: txn.DocExists,
+			Update: update,
+		}}, nil
+	}
+	err = st.db().Run(buildTxn)
+	return errors.Annotatef(err, "cannot set quotas for %s", names.ReadableString(tag))
+}
+
+// QuotaUsage returns the amount of each resource used by the model, or
+// by all of the models owned by the user, with the given tag.
+func (st *State) QuotaUsage(tag names.Tag) (quota.Usage, error) {
+	models, err := st.quotaModels(tag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	usage, err := st.quotaUsage(models, quota.AllResources)
+	return usage, errors.Trace(err)
+}
+
+// quotaModel identifies a model whose resources count towards a quota.
+type quotaModel struct {
+	UUID string    `bson:"_id"`
+	Type ModelType `bson:"type"`
+}
+
+// quotaModels returns the models whose resources count towards the
+// quota of the model or user with the given tag.
+func (st *State) quotaModels(tag names.Tag) ([]quotaModel, error) {
+	var query bson.D
+	switch tag := tag.(type) {
+	case names.ModelTag:
+		query = bson.D{{"_id", tag.Id()}}
+	case names.UserTag:
+		query = bson.D{{"owner", tag.Id()}}
+	default:
+		return nil, errors.NotValidf("quota entity %q", tag)
+	}
+	models, closer := st.db().GetCollection(modelsC)
+	defer closer()
+
+	var result []quotaModel
+	if err := models.Find(query).Select(bson.D{{"type", 1}}).All(&result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// quotaUsage returns the amount of each of the given resources used by
+// the models.
+func (st *State) quotaUsage(models []quotaModel, resources []quota.Resource) (quota.Usage, error) {
+	var uuids, caasUUIDs []string
+	for _, m := range models {
+		uuids = append(uuids, m.UUID)
+		if m.Type == ModelTypeCAAS {
+			caasUUIDs = append(caasUUIDs, m.UUID)
+		}
+	}
+
+	count := func(collName string, uuids []string) (uint64, error) {
+		if len(uuids) == 0 {
+			return 0, nil
+		}
+		coll, closer := st.db().GetRawCollection(collName)
+		defer closer()
+		n, err := coll.Find(bson.D{
+			{"model-uuid", bson.D{{"$in", uuids}}},
+			{"life", bson.D{{"$ne", Dead}}},
+		}).Count()
+		return uint64(n), errors.Annotatef(err, "counting %s", collName)
+	}
+
+	usage := make(quota.Usage)
+	for _, r := range resources {
+		var (
+			amount uint64
+			err    error
+		)
+		switch r {
+		case quota.Machines:
+			amount, err = count(machinesC, uuids)
+		case quota.Units:
+			amount, err = count(unitsC, uuids)
+		case quota.Applications:
+			amount, err = count(applicationsC, uuids)
+		case quota.Pods:
+			amount, err = count(unitsC, caasUUIDs)
+		case quota.Storage:
+			amount, err = st.storageQuotaUsage(uuids)
+		default:
+			err = r.Validate()
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		usage[r] = amount
+	}
+	return usage, nil
+}
+
+// storageQuotaUsage returns the total size in MiB of the storage
+// instances in the models.
+func (st *State) storageQuotaUsage(uuids []string) (uint64, error) {
+	if len(uuids) == 0 {
+		return 0, nil
+	}
+	coll, closer := st.db().GetRawCollection(storageInstancesC)
+	defer closer()
+
+	var total uint64
+	var doc storageInstanceDoc
+	iter := coll.Find(bson.D{
+		{"model-uuid", bson.D{{"$in", uuids}}},
+		{"life", bson.D{{"$ne", Dead}}},
+	}).Select(bson.D{{"constraints", 1}}).Iter()
+	for iter.Next(&doc) {
+		total += doc.Constraints.Size
+	}
+	return total, errors.Annotate(iter.Close(), "reading storage instances")
+}
+
+// quotaOps returns operations that must be run in the same
+// transaction as the addition of the given amounts of resources to the
+// model. It returns an error satisfying errors.IsQuotaLimitExceeded if
+// the addition would exceed the quotas set for the model or its owner.
+// Units added to a CAAS model also count as pods.
+//
+// Usage is counted when the operations are built; the operations
+// assert the quota documents' addition counters haven't changed since
+// and increment them, so a concurrent addition aborts the transaction
+// and the quotas are checked again when it is retried.
+func (st *State) quotaOps(add quota.Usage) ([]txn.Op, error) {
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	modelKey, err := quotaKey(model.ModelTag())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ownerKey, err := quotaKey(model.Owner())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	quotas, closer := st.db().GetCollection(quotasC)
+	defer closer()
+	var docs []quotaDoc
+	if err := quotas.Find(bson.D{{"_id", bson.D{{"$in", []string{modelKey, ownerKey}}}}}).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get quotas")
+	}
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	if model.Type() == ModelTypeCAAS && add[quota.Units] > 0 {
+		withPods := make(quota.Usage, len(add)+1)
+		for r, amount := range add {
+			withPods[r] = amount
+		}
+		withPods[quota.Pods] += add[quota.Units]
+		add = withPods
+	}
+
+	byKey := make(map[string]quotaDoc, len(docs))
+	for _, doc := range docs {
+		byKey[doc.DocID] = doc
+	}
+	var ops []txn.Op
+	if doc, ok := byKey[modelKey]; ok {
+		checked, err := st.checkQuotaLimits(model.ModelTag(), doc.limits(), add)
+		if err != nil {
+			return nil, errors.Annotatef(err, "model %q", model.Name())
+		}
+		if checked {
+			ops = append(ops, quotaAddOp(doc))
+		}
+	}
+	if doc, ok := byKey[ownerKey]; ok {
+		checked, err := st.checkQuotaLimits(model.Owner(), doc.limits(), add)
+		if err != nil {
+			return nil, errors.Annotatef(err, "user %q", model.Owner().Id())
+		}
+		if checked {
+			ops = append(ops, quotaAddOp(doc))
+		}
+	}
+	return ops, nil
+}
+
+// quotaAddOp returns an operation that asserts the quota document's
+// addition counter is unchanged and increments it. A transaction may
+// include more than one of these for the same document, as all of its
+// assertions are checked before any of its updates are applied.
+func quotaAddOp(doc quotaDoc) txn.Op {
+	return txn.Op{
+		C:      quotasC,
+		Id:     doc.DocID,
+		Assert: bson.D{{"adds", doc.Adds}},
+		Update: bson.D{{"$inc", bson.D{{"adds", 1}}}},
+	}
+}
+
+// checkQuotaLimits checks that adding the given amounts to the usage of
+// the model or user with the given tag doesn't exceed the limits. It
+// reports whether any of the amounts added are limited.
+func (st *State) checkQuotaLimits(tag names.Tag, limits quota.Limits, add quota.Usage) (bool, error) {
+	var resources []quota.Resource
+	for _, r := range quota.AllResources {
+		if _, ok := limits[r]; ok && add[r] > 0 {
+			resources = append(resources, r)
+		}
+	}
+	if len(resources) == 0 {
+		return false, nil
+	}
+	models, err := st.quotaModels(tag)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	usage, err := st.quotaUsage(models, resources)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	for _, r := range resources {
+		checker := quota.NewResourceLimitChecker(r, limits[r], usage[r])
+		checker.Check(add[r])
+		if err := checker.Outcome(); err != nil {
+			return false, errors.Trace(err)
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,236 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/quota"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type QuotaSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&QuotaSuite{})
+
+func (s *QuotaSuite) TestQuotaLimitsNoneSet(c *gc.C) {
+	limits, err := s.State.QuotaLimits(s.modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(limits, gc.HasLen, 0)
+
+	limits, err = s.State.QuotaLimits(s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(limits, gc.HasLen, 0)
+}
+
+func (s *QuotaSuite) TestSetQuotaLimits(c *gc.C) {
+	err := s.State.SetQuotaLimits(s.modelTag, quota.Limits{
+		quota.Machines: 2,
+		quota.Units:    3,
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	limits, err := s.State.QuotaLimits(s.modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(limits, jc.DeepEquals, quota.Limits{
+		quota.Machines: 2,
+		quota.Units:    3,
+	})
+
+	err = s.State.SetQuotaLimits(s.modelTag, quota.Limits{
+		quota.Units:   5,
+		quota.Storage: 1024,
+	}, []quota.Resource{quota.Machines})
+	c.Assert(err, jc.ErrorIsNil)
+
+	limits, err = s.State.QuotaLimits(s.modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(limits, jc.DeepEquals, quota.Limits{
+		quota.Units:   5,
+		quota.Storage: 1024,
+	})
+
+	// The user's limits are separate.
+	limits, err = s.State.QuotaLimits(s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(limits, gc.HasLen, 0)
+}
+
+func (s *QuotaSuite) TestSetQuotaLimitsInvalid(c *gc.C) {
+	err := s.State.SetQuotaLimits(s.modelTag, quota.Limits{"cpus": 2}, nil)
+	c.Assert(err, gc.ErrorMatches, `quota resource "cpus" not valid`)
+
+	err = s.State.SetQuotaLimits(s.modelTag, quota.Limits{quota.Units: 2}, []quota.Resource{quota.Units})
+	c.Assert(err, gc.ErrorMatches, `cannot set and reset units quota`)
+
+	err = s.State.SetQuotaLimits(names.NewMachineTag("0"), quota.Limits{quota.Units: 2}, nil)
+	c.Assert(err, gc.ErrorMatches, `quota entity "machine-0" not valid`)
+
+	err = s.State.SetQuotaLimits(names.NewModelTag("deadbeef-0bad-400d-8000-4b1d0d06f00d"), quota.Limits{quota.Units: 2}, nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *QuotaSuite) TestQuotaUsage(c *gc.C) {
+	s.Factory.MakeMachine(c, nil)
+	app := s.Factory.MakeApplication(c, nil)
+	s.Factory.MakeUnit(c, &factory.UnitParams{Application: app})
+	s.Factory.MakeUnit(c, &factory.UnitParams{Application: app})
+
+	usage, err := s.State.QuotaUsage(s.modelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage, jc.DeepEquals, quota.Usage{
+		quota.Machines:     3,
+		quota.Units:        2,
+		quota.Applications: 1,
+		quota.Storage:      0,
+		quota.Pods:         0,
+	})
+}
+
+func (s *QuotaSuite) TestAddMachineExceedsModelQuota(c *gc.C) {
+	err := s.State.SetQuotaLimits(s.modelTag, quota.Limits{quota.Machines: 1}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.Satisfies, errors.IsQuotaLimitExceeded)
+	c.Assert(err, gc.ErrorMatches, `cannot add a new machine: model "testmodel": machines quota \(1\) exceeded`)
+}
+
+func (s *QuotaSuite) TestAddMachinesExceedsModelQuota(c *gc.C) {
+	err := s.State.SetQuotaLimits(s.modelTag, quota.Limits{quota.Machines: 1}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	template := state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}
+	_, err = s.State.AddMachines(template, template)
+	c.Assert(err, jc.Satisfies, errors.IsQuotaLimitExceeded)
+
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 0)
+}
+
+func (s *QuotaSuite) TestAddMachineConcurrentlyExceedsModelQuota(c *gc.C) {
+	err := s.State.SetQuotaLimits(s.modelTag, quota.Limits{quota.Machines: 1}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.Satisfies, errors.IsQuotaLimitExceeded)
+
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 1)
+}
+
+func (s *QuotaSuite) TestAddUnitExceedsModelQuota(c *gc.C) {
+	app := s.Factory.MakeApplication(c, nil)
+	err := s.State.SetQuotaLimits(s.modelTag, quota.Limits{quota.Units: 1}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.Satisfies, errors.IsQuotaLimitExceeded)
+	c.Assert(err, gc.ErrorMatches, `cannot add unit to application "mysql": model "testmodel": units quota \(1\) exceeded`)
+}
+
+func (s *QuotaSuite) TestAddUnitConcurrentlyExceedsUserQuota(c *gc.C) {
+	app := s.Factory.MakeApplication(c, nil)
+	st := s.Factory.MakeModel(c, &factory.ModelParams{Owner: s.Owner})
+	defer st.Close()
+	f := factory.NewFactory(st, s.StatePool)
+	otherApp := f.MakeApplication(c, nil)
+	err := s.State.SetQuotaLimits(s.Owner, quota.Limits{quota.Units: 1}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		_, err := otherApp.AddUnit(state.AddUnitParams{})
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	_, err = app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.Satisfies, errors.IsQuotaLimitExceeded)
+	c.Assert(err, gc.ErrorMatches, `cannot add unit to application "mysql": user "test-admin": units quota \(1\) exceeded`)
+}
+
+func (s *QuotaSuite) TestAddApplicationExceedsModelQuota(c *gc.C) {
+	err := s.State.SetQuotaLimits(s.modelTag, quota.Limits{quota.Applications: 1}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ch := s.AddTestingCharm(c, "dummy")
+	_, err = s.State.AddApplication(state.AddApplicationArgs{Name: "one", Charm: ch})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddApplication(state.AddApplicationArgs{Name: "two", Charm: ch})
+	c.Assert(err, jc.Satisfies, errors.IsQuotaLimitExceeded)
+	c.Assert(err, gc.ErrorMatches, `cannot add application "two": model "testmodel": applications quota \(1\) exceeded`)
+}
+
+func (s *QuotaSuite) TestAddApplicationUnitsExceedModelQuota(c *gc.C) {
+	err := s.State.SetQuotaLimits(s.modelTag, quota.Limits{quota.Units: 2}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ch := s.AddTestingCharm(c, "dummy")
+	_, err = s.State.AddApplication(state.AddApplicationArgs{Name: "dummy", Charm: ch, NumUnits: 3})
+	c.Assert(err, jc.Satisfies, errors.IsQuotaLimitExceeded)
+}
+
+func (s *QuotaSuite) TestAddUnitExceedsStorageQuota(c *gc.C) {
+	ch := s.AddTestingCharm(c, "storage-block")
+	app := s.AddTestingApplicationWithStorage(c, "storage-block", ch, map[string]state.StorageConstraints{
+		"data": makeStorageCons("loop", 1024, 1),
+	})
+	err := s.State.SetQuotaLimits(s.modelTag, quota.Limits{quota.Storage: 1536}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.Satisfies, errors.IsQuotaLimitExceeded)
+	c.Assert(err, gc.ErrorMatches, `.*model "testmodel": storage quota \(1536MiB\) exceeded`)
+}
+
+func (s *QuotaSuite) TestUserQuotaSpansModels(c *gc.C) {
+	err := s.State.SetQuotaLimits(s.Owner, quota.Limits{quota.Machines: 1}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	st := s.Factory.MakeModel(c, &factory.ModelParams{Owner: s.Owner})
+	defer st.Close()
+	_, err = st.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.Satisfies, errors.IsQuotaLimitExceeded)
+	c.Assert(err, gc.ErrorMatches, `cannot add a new machine: user "test-admin": machines quota \(1\) exceeded`)
+
+	usage, err := s.State.QuotaUsage(s.Owner)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(usage[quota.Machines], gc.Equals, uint64(1))
+}
+
+func (s *QuotaSuite) TestRemoveLimit(c *gc.C) {
+	err := s.State.SetQuotaLimits(s.modelTag, quota.Limits{quota.Machines: 0}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.Satisfies, errors.IsQuotaLimitExceeded)
+
+	err = s.State.SetQuotaLimits(s.modelTag, nil, []quota.Resource{quota.Machines})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -0,0 +1,580 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jujutxn "github.com/juju/txn"
+	"github.com/juju/version"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	stateerrors "github.com/juju/juju/state/errors"
+)
+
+// OperationRollout holds the parameters controlling how the tasks of a
+// rolling operation are released to their receivers.
+type OperationRollout struct {
+	// BatchSize is the number of tasks in each batch. A batch is only
+	// started once every task in the previous batch has finished.
+	// Zero means all tasks belong to a single batch.
+	BatchSize int
+
+	// MaxParallel is the maximum number of tasks which may be queued or
+	// running at any one time. Zero means no limit other than BatchSize.
+	MaxParallel int
+
+	// BatchWait is how long to wait after a batch has finished before
+	// the next batch is started.
+	BatchWait time.Duration
+
+	// MaxFailures halts the rollout once this many tasks have failed.
+	// Zero means the rollout is never halted.
+	MaxFailures int
+}
+
+// Validate returns an error if the rollout parameters are not valid.
+func (r OperationRollout) Validate() error {
+	if r.BatchSize < 0 {
+		return errors.NotValidf("negative batch size")
+	}
+	if r.MaxParallel < 0 {
+		return errors.NotValidf("negative max parallel")
+	}
+	if r.BatchWait < 0 {
+		return errors.NotValidf("negative batch wait")
+	}
+	if r.MaxFailures < 0 {
+		return errors.NotValidf("negative max failures")
+	}
+	return nil
+}
+
+// RolloutTask describes a single task of a rolling operation.
+type RolloutTask struct {
+	Receiver   names.Tag
+	Name       string
+	Parameters map[string]interface{}
+}
+
+// RolloutBatch holds the number of tasks of a batch in each state.
+type RolloutBatch struct {
+	Pending   int
+	Running   int
+	Completed int
+	Failed    int
+}
+
+// RolloutProgress describes how far a rolling operation has got.
+type RolloutProgress struct {
+	OperationRollout
+
+	// Halted holds the reason the rollout was halted, if it has been.
+	Halted string
+
+	// Batches holds the progress of each batch, in the order in
+	// which they are run.
+	Batches []RolloutBatch
+}
+
+type rolloutDoc struct {
+	BatchSize   int           `bson:"batch-size"`
+	MaxParallel int           `bson:"max-parallel"`
+	BatchWait   time.Duration `bson:"batch-wait"`
+	MaxFailures int           `bson:"max-failures"`
+
+	// Tasks holds every task of the operation in the order in
+	// which they are to be released.
+	Tasks []rolloutTaskDoc `bson:"tasks"`
+
+	// Released is the number of tasks, from the start of Tasks,
+	// which have been released to their receivers.
+	Released int `bson:"released"`
+
+	// FailuresIgnored is the number of failures which occurred
+	// before the rollout was last resumed; they no longer count
+	// towards MaxFailures.
+	FailuresIgnored int `bson:"failures-ignored"`
+
+	// Halted holds the reason the rollout was halted.
+	Halted string `bson:"halted,omitempty"`
+}
+
+type rolloutTaskDoc struct {
+	Receiver   string                 `bson:"receiver"`
+	Name       string                 `bson:"name"`
+	Parameters map[string]interface{} `bson:"parameters,omitempty"`
+
+	// ActionID is set once the task has been released.
+	ActionID string `bson:"action-id,omitempty"`
+
+	// Error is set if the task could not be enqueued.
+	Error string `bson:"error,omitempty"`
+}
+
+func (r *rolloutDoc) batchSize() int {
+	if r.BatchSize <= 0 || r.BatchSize > len(r.Tasks) {
+		return len(r.Tasks)
+	}
+	return r.BatchSize
+}
+
+// actionPayloadPreparer is implemented by action receivers which can
+// validate an action before it is enqueued.
+type actionPayloadPreparer interface {
+	prepareActionPayload(name string, payload map[string]interface{}) (map[string]interface{}, error)
+}
+
+// EnqueueRollingOperation records an operation whose tasks are released
+// to their receivers in batches, as governed by rollout, rather than all
+// at once. Tasks which cannot be run are reported in the returned slice
+// of errors, which has an entry for each task, and count as failed.
+func (m *Model) EnqueueRollingOperation(summary string, rollout OperationRollout, tasks []RolloutTask) (string, []error, error) {
+	if err := rollout.Validate(); err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	if len(tasks) == 0 {
+		return "", nil, errors.New("no tasks to run")
+	}
+
+	taskErrors := make([]error, len(tasks))
+	rolloutTasks := make([]rolloutTaskDoc, len(tasks))
+	for i, task := range tasks {
+		payload, err := m.st.prepareRolloutTask(task)
+		rolloutTasks[i] = rolloutTaskDoc{
+			Receiver:   task.Receiver.String(),
+			Name:       task.Name,
+			Parameters: payload,
+		}
+		if err != nil {
+			taskErrors[i] = err
+			rolloutTasks[i].Error = err.Error()
+		}
+	}
+
+	var operationID string
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		var doc operationDoc
+		var err error
+		doc, operationID, err = newOperationDoc(m.st, summary)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		doc.Rollout = &rolloutDoc{
+			BatchSize:   rollout.BatchSize,
+			MaxParallel: rollout.MaxParallel,
+			BatchWait:   rollout.BatchWait,
+			MaxFailures: rollout.MaxFailures,
+			Tasks:       rolloutTasks,
+		}
+		return []txn.Op{{
+			C:      operationsC,
+			Id:     doc.DocId,
+			Assert: txn.DocMissing,
+			Insert: doc,
+		}}, nil
+	}
+	if err := m.st.db().Run(buildTxn); err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	return operationID, taskErrors, nil
+}
+
+func (st *State) prepareRolloutTask(task RolloutTask) (map[string]interface{}, error) {
+	if task.Receiver == nil {
+		return nil, errors.New("no receiver given")
+	}
+	entity, err := st.FindEntity(task.Receiver)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	preparer, ok := entity.(actionPayloadPreparer)
+	if !ok {
+		return nil, errors.Errorf("%s cannot run actions", names.ReadableString(task.Receiver))
+	}
+	return preparer.prepareActionPayload(task.Name, task.Parameters)
+}
+
+// rolloutSummary holds the state of the released tasks of a rollout.
+type rolloutSummary struct {
+	running       int
+	failed        int
+	lastCompleted time.Time
+	statuses      set.Strings
+}
+
+// taskStatus returns the status of a released rollout task.
+func (r *rolloutDoc) taskStatus(task rolloutTaskDoc, actions map[string]actionDoc) (ActionStatus, time.Time) {
+	if task.Error != "" {
+		return ActionFailed, time.Time{}
+	}
+	action, ok := actions[task.ActionID]
+	if !ok {
+		// The task has since been pruned, which only
+		// happens to those that have finished.
+		return ActionCompleted, time.Time{}
+	}
+	return action.Status, action.Completed
+}
+
+func isFailedStatus(status ActionStatus) bool {
+	switch status {
+	case ActionFailed, ActionAborted, ActionCancelled:
+		return true
+	}
+	return false
+}
+
+func isFinishedStatus(status ActionStatus) bool {
+	switch status {
+	case ActionPending, ActionRunning, ActionAborting:
+		return false
+	}
+	return true
+}
+
+func (r *rolloutDoc) summarise(actions map[string]actionDoc) rolloutSummary {
+	summary := rolloutSummary{statuses: set.NewStrings()}
+	for _, task := range r.Tasks[:r.Released] {
+		status, completed := r.taskStatus(task, actions)
+		summary.statuses.Add(string(status))
+		if !isFinishedStatus(status) {
+			summary.running++
+			continue
+		}
+		if isFailedStatus(status) {
+			summary.failed++
+		}
+		if completed.After(summary.lastCompleted) {
+			summary.lastCompleted = completed
+		}
+	}
+	return summary
+}
+
+// hasErrors returns true if any task of the rollout could not be enqueued.
+func (r *rolloutDoc) hasErrors() bool {
+	for _, task := range r.Tasks {
+		if task.Error != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// operationActionDocs returns the actions of the operation with the
+// given id, keyed on their local id.
+func (st *State) operationActionDocs(id string) (map[string]actionDoc, error) {
+	actionsCollection, closer := st.db().GetCollection(actionsC)
+	defer closer()
+
+	var docs []actionDoc
+	err := actionsCollection.Find(bson.D{{"operation", id}}).
+		Select(bson.D{{"status", 1}, {"completed", 1}}).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get tasks for operation %q", id)
+	}
+	result := make(map[string]actionDoc, len(docs))
+	for _, doc := range docs {
+		result[st.localID(doc.DocId)] = doc
+	}
+	return result, nil
+}
+
+// AdvanceRollouts releases the next tasks of any rolling operation
+// whose batch constraints allow it, halts those which have reached their
+// failure limit and completes those with nothing left to run. It returns
+// the earliest time at which a rollout waiting between batches may be
+// advanced, or the zero time if none are waiting.
+func (m *Model) AdvanceRollouts() (time.Time, error) {
+	operations, closer := m.st.db().GetCollection(operationsC)
+	defer closer()
+
+	var docs []operationDoc
+	err := operations.Find(bson.D{
+		{"rollout", bson.D{{"$exists", true}}},
+		{"rollout.halted", bson.D{{"$exists", false}}},
+		{"status", bson.D{{"$in", []ActionStatus{ActionPending, ActionRunning}}}},
+	}).Select(bson.D{{"_id", 1}}).All(&docs)
+	if err != nil {
+		return time.Time{}, errors.Annotate(err, "cannot get rolling operations")
+	}
+
+	var next time.Time
+	for _, doc := range docs {
+		id := m.st.localID(doc.DocId)
+		due, err := m.advanceRollout(id)
+		if err != nil {
+			return time.Time{}, errors.Annotatef(err, "advancing operation %q", id)
+		}
+		if !due.IsZero() && (next.IsZero() || due.Before(next)) {
+			next = due
+		}
+	}
+	return next, nil
+}
+
+func (m *Model) advanceRollout(id string) (time.Time, error) {
+	agentVersion, err := m.AgentVersion()
+	if err != nil {
+		return time.Time{}, errors.Trace(err)
+	}
+
+	var due time.Time
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		due = time.Time{}
+		doc, _, err := m.st.getOperationDoc(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		r := doc.Rollout
+		if r == nil || r.Halted != "" || isFinishedStatus(doc.Status) {
+			return nil, jujutxn.ErrNoOperations
+		}
+		actions, err := m.st.operationActionDocs(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		summary := r.summarise(actions)
+		assertUnchanged := bson.D{
+			{"rollout.released", r.Released},
+			{"rollout.halted", bson.D{{"$exists", false}}},
+		}
+
+		if r.Released == len(r.Tasks) {
+			if summary.running > 0 {
+				// The last task to finish completes the operation.
+				return nil, jujutxn.ErrNoOperations
+			}
+			// Tasks which could not be enqueued have no action to
+			// complete the operation, so do it here.
+			finalStatus := ActionCompleted
+			for _, s := range statusCompletedOrder {
+				if summary.statuses.Contains(string(s)) {
+					finalStatus = s
+					break
+				}
+			}
+			return []txn.Op{{
+				C:  operationsC,
+				Id: doc.DocId,
+				Assert: append(assertUnchanged, bson.D{{"status", bson.D{
+					{"$in", []ActionStatus{ActionPending, ActionRunning}},
+				}}}...),
+				Update: bson.D{{"$set", bson.D{
+					{"status", finalStatus},
+					{"completed", m.st.nowToTheSecond()},
+				}}},
+			}}, nil
+		}
+
+		if r.MaxFailures > 0 && summary.failed-r.FailuresIgnored >= r.MaxFailures {
+			return []txn.Op{{
+				C:      operationsC,
+				Id:     doc.DocId,
+				Assert: assertUnchanged,
+				Update: bson.D{{"$set", bson.D{
+					{"rollout.halted", fmt.Sprintf("%d of %d tasks failed", summary.failed, len(r.Tasks))},
+				}}},
+			}}, nil
+		}
+
+		batchSize := r.batchSize()
+		if r.Released%batchSize == 0 && r.Released > 0 {
+			// The next batch starts once the previous one has
+			// finished and the batch wait has elapsed.
+			if summary.running > 0 {
+				return nil, jujutxn.ErrNoOperations
+			}
+			if r.BatchWait > 0 && !summary.lastCompleted.IsZero() {
+				next := summary.lastCompleted.Add(r.BatchWait)
+				if m.st.clock().Now().Before(next) {
+					due = next
+					return nil, jujutxn.ErrNoOperations
+				}
+			}
+		}
+
+		batchEnd := (r.Released/batchSize + 1) * batchSize
+		if batchEnd > len(r.Tasks) {
+			batchEnd = len(r.Tasks)
+		}
+		count := batchEnd - r.Released
+		if r.MaxParallel > 0 && r.MaxParallel-summary.running < count {
+			count = r.MaxParallel - summary.running
+		}
+		if count <= 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+
+		var ops []txn.Op
+		updates := bson.D{{"rollout.released", r.Released + count}}
+		for i := r.Released; i < r.Released+count; i++ {
+			task := r.Tasks[i]
+			if task.Error != "" {
+				continue
+			}
+			taskOps, actionID, err := m.releaseRolloutTask(id, task, agentVersion)
+			if errors.Cause(err) == stateerrors.ErrDead || errors.IsNotFound(err) {
+				updates = append(updates, bson.DocElem{
+					Name: fmt.Sprintf("rollout.tasks.%d.error", i), Value: err.Error(),
+				})
+				continue
+			}
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, taskOps...)
+			updates = append(updates, bson.DocElem{
+				Name: fmt.Sprintf("rollout.tasks.%d.action-id", i), Value: actionID,
+			})
+		}
+		ops = append(ops, txn.Op{
+			C:      operationsC,
+			Id:     doc.DocId,
+			Assert: assertUnchanged,
+			Update: bson.D{{"$set", updates}},
+		})
+		return ops, nil
+	}
+	if err := m.st.db().Run(buildTxn); err != nil {
+		return time.Time{}, errors.Trace(err)
+	}
+	return due, nil
+}
+
+// releaseRolloutTask returns the operations needed to enqueue the
+// given rollout task as an action, along with the id of the action.
+func (m *Model) releaseRolloutTask(operationID string, task rolloutTaskDoc, agentVersion version.Number) ([]txn.Op, string, error) {
+	receiver, err := names.ParseTag(task.Receiver)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	receiverCollectionName, receiverId, err := m.st.tagToCollectionAndId(receiver)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	if notDead, err := isNotDead(m.st, receiverCollectionName, receiverId); err != nil {
+		return nil, "", errors.Trace(err)
+	} else if !notDead {
+		return nil, "", stateerrors.ErrDead
+	}
+	doc, ndoc, err := newActionDoc(m.st, operationID, receiver, task.Name, task.Parameters, agentVersion)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	return []txn.Op{{
+		C:      receiverCollectionName,
+		Id:     receiverId,
+		Assert: notDeadDoc,
+	}, {
+		C:      actionsC,
+		Id:     doc.DocId,
+		Assert: txn.DocMissing,
+		Insert: doc,
+	}, {
+		C:      actionNotificationsC,
+		Id:     ndoc.DocId,
+		Assert: txn.DocMissing,
+		Insert: ndoc,
+	}}, m.st.localID(doc.DocId), nil
+}
+
+// ResumeOperation restarts a rolling operation which was halted after
+// too many of its tasks failed. Failures up to this point no longer
+// count towards the rollout's failure limit.
+func (m *Model) ResumeOperation(id string) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		doc, _, err := m.st.getOperationDoc(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		r := doc.Rollout
+		if r == nil {
+			return nil, errors.Errorf("operation %q is not a rolling operation", id)
+		}
+		if r.Halted == "" {
+			return nil, errors.Errorf("operation %q is not halted", id)
+		}
+		actions, err := m.st.operationActionDocs(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		summary := r.summarise(actions)
+		return []txn.Op{{
+			C:  operationsC,
+			Id: doc.DocId,
+			Assert: bson.D{
+				{"rollout.released", r.Released},
+				{"rollout.halted", r.Halted},
+			},
+			Update: bson.D{
+				{"$set", bson.D{{"rollout.failures-ignored", summary.failed}}},
+				{"$unset", bson.D{{"rollout.halted", nil}}},
+			},
+		}}, nil
+	}
+	return errors.Trace(m.st.db().Run(buildTxn))
+}
+
+// RolloutProgress returns the progress of the rolling operation with
+// the given id, or nil if the operation is not a rolling operation.
+func (m *Model) RolloutProgress(id string) (*RolloutProgress, error) {
+	doc, _, err := m.st.getOperationDoc(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	r := doc.Rollout
+	if r == nil {
+		return nil, nil
+	}
+	actions, err := m.st.operationActionDocs(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	progress := &RolloutProgress{
+		OperationRollout: OperationRollout{
+			BatchSize:   r.BatchSize,
+			MaxParallel: r.MaxParallel,
+			BatchWait:   r.BatchWait,
+			MaxFailures: r.MaxFailures,
+		},
+		Halted: r.Halted,
+	}
+	batchSize := r.batchSize()
+	for i, task := range r.Tasks {
+		if i%batchSize == 0 {
+			progress.Batches = append(progress.Batches, RolloutBatch{})
+		}
+		batch := &progress.Batches[len(progress.Batches)-1]
+		if i >= r.Released {
+			batch.Pending++
+			continue
+		}
+		status, _ := r.taskStatus(task, actions)
+		switch {
+		case status == ActionPending:
+			batch.Pending++
+		case !isFinishedStatus(status):
+			batch.Running++
+		case isFailedStatus(status):
+			batch.Failed++
+		default:
+			batch.Completed++
+		}
+	}
+	return progress, nil
+}
+
+// WatchRollouts returns a watcher which notifies when operations in
+// the model change, so that rolling operations may be advanced.
+func (st *State) WatchRollouts() NotifyWatcher {
+	return newNotifyCollWatcher(st, operationsC, isLocalID(st))
+}
@@ -0,0 +1,239 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/clock/testclock"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type RolloutSuite struct {
+	ConnSuite
+	clock *testclock.Clock
+	units []*state.Unit
+}
+
+var _ = gc.Suite(&RolloutSuite{})
+
+func (s *RolloutSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(coretesting.NonZeroTime().Round(time.Second))
+	err := s.State.SetClockForTesting(s.clock)
+	c.Assert(err, jc.ErrorIsNil)
+
+	charm := s.AddTestingCharm(c, "dummy")
+	application := s.AddTestingApplication(c, "dummy", charm)
+	s.units = nil
+	for i := 0; i < 3; i++ {
+		unit, err := application.AddUnit(state.AddUnitParams{})
+		c.Assert(err, jc.ErrorIsNil)
+		s.units = append(s.units, unit)
+	}
+}
+
+func (s *RolloutSuite) enqueue(c *gc.C, rollout state.OperationRollout) string {
+	var tasks []state.RolloutTask
+	for _, unit := range s.units {
+		tasks = append(tasks, state.RolloutTask{
+			Receiver: unit.Tag(),
+			Name:     "snapshot",
+		})
+	}
+	operationID, taskErrors, err := s.Model.EnqueueRollingOperation("snapshot run on dummy", rollout, tasks)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(taskErrors, jc.DeepEquals, []error{nil, nil, nil})
+	return operationID
+}
+
+func (s *RolloutSuite) releasedActions(c *gc.C, operationID string) []state.Action {
+	info, err := s.Model.OperationWithActions(operationID)
+	c.Assert(err, jc.ErrorIsNil)
+	return info.Actions
+}
+
+func (s *RolloutSuite) finish(c *gc.C, action state.Action, status state.ActionStatus) {
+	_, err := action.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = action.Finish(state.ActionResults{Status: status})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *RolloutSuite) assertBatches(c *gc.C, operationID string, expected ...state.RolloutBatch) {
+	progress, err := s.Model.RolloutProgress(operationID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(progress.Batches, jc.DeepEquals, expected)
+}
+
+func (s *RolloutSuite) TestEnqueueRollingOperation(c *gc.C) {
+	operationID := s.enqueue(c, state.OperationRollout{BatchSize: 2, MaxFailures: 1})
+
+	operation, err := s.Model.Operation(operationID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(operation.Status(), gc.Equals, state.ActionPending)
+	c.Assert(s.releasedActions(c, operationID), gc.HasLen, 0)
+
+	progress, err := s.Model.RolloutProgress(operationID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(progress, jc.DeepEquals, &state.RolloutProgress{
+		OperationRollout: state.OperationRollout{BatchSize: 2, MaxFailures: 1},
+		Batches:          []state.RolloutBatch{{Pending: 2}, {Pending: 1}},
+	})
+}
+
+func (s *RolloutSuite) TestEnqueueRollingOperationTaskErrors(c *gc.C) {
+	_, taskErrors, err := s.Model.EnqueueRollingOperation("bad run", state.OperationRollout{}, []state.RolloutTask{{
+		Receiver: s.units[0].Tag(),
+		Name:     "snapshot",
+	}, {
+		Receiver: s.units[1].Tag(),
+		Name:     "missing",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(taskErrors, gc.HasLen, 2)
+	c.Assert(taskErrors[0], jc.ErrorIsNil)
+	c.Assert(taskErrors[1], gc.ErrorMatches, `action "missing" not defined on unit "dummy/1"`)
+}
+
+func (s *RolloutSuite) TestEnqueueRollingOperationInvalid(c *gc.C) {
+	_, _, err := s.Model.EnqueueRollingOperation("bad run", state.OperationRollout{BatchSize: -1}, nil)
+	c.Assert(err, gc.ErrorMatches, "negative batch size not valid")
+}
+
+func (s *RolloutSuite) TestNotRollingOperation(c *gc.C) {
+	operationID, err := s.Model.EnqueueOperation("an operation")
+	c.Assert(err, jc.ErrorIsNil)
+
+	progress, err := s.Model.RolloutProgress(operationID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(progress, gc.IsNil)
+
+	err = s.Model.ResumeOperation(operationID)
+	c.Assert(err, gc.ErrorMatches, `operation ".*" is not a rolling operation`)
+}
+
+func (s *RolloutSuite) TestAdvanceRolloutsInBatches(c *gc.C) {
+	operationID := s.enqueue(c, state.OperationRollout{BatchSize: 2, BatchWait: time.Minute})
+
+	due, err := s.Model.AdvanceRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(due.IsZero(), jc.IsTrue)
+	actions := s.releasedActions(c, operationID)
+	c.Assert(actions, gc.HasLen, 2)
+	s.assertBatches(c, operationID, state.RolloutBatch{Pending: 2}, state.RolloutBatch{Pending: 1})
+
+	// Nothing more is released until the batch is done.
+	s.finish(c, actions[0], state.ActionCompleted)
+	_, err = s.Model.AdvanceRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.releasedActions(c, operationID), gc.HasLen, 2)
+
+	// Once it is, the next batch waits for the batch wait.
+	s.finish(c, actions[1], state.ActionCompleted)
+	operation, err := s.Model.Operation(operationID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(operation.Status(), gc.Equals, state.ActionRunning)
+	due, err = s.Model.AdvanceRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(due, gc.Equals, s.clock.Now().Add(time.Minute))
+	c.Assert(s.releasedActions(c, operationID), gc.HasLen, 2)
+
+	s.clock.Advance(time.Minute)
+	due, err = s.Model.AdvanceRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(due.IsZero(), jc.IsTrue)
+	actions = s.releasedActions(c, operationID)
+	c.Assert(actions, gc.HasLen, 3)
+	s.assertBatches(c, operationID, state.RolloutBatch{Completed: 2}, state.RolloutBatch{Pending: 1})
+
+	s.finish(c, actions[2], state.ActionCompleted)
+	operation, err = s.Model.Operation(operationID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(operation.Status(), gc.Equals, state.ActionCompleted)
+}
+
+func (s *RolloutSuite) TestAdvanceRolloutsMaxParallel(c *gc.C) {
+	operationID := s.enqueue(c, state.OperationRollout{MaxParallel: 1})
+
+	_, err := s.Model.AdvanceRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	actions := s.releasedActions(c, operationID)
+	c.Assert(actions, gc.HasLen, 1)
+
+	s.finish(c, actions[0], state.ActionCompleted)
+	_, err = s.Model.AdvanceRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.releasedActions(c, operationID), gc.HasLen, 2)
+	s.assertBatches(c, operationID, state.RolloutBatch{Pending: 2, Completed: 1})
+}
+
+func (s *RolloutSuite) TestAdvanceRolloutsHaltsAndResumes(c *gc.C) {
+	operationID := s.enqueue(c, state.OperationRollout{BatchSize: 1, MaxFailures: 1})
+
+	_, err := s.Model.AdvanceRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	actions := s.releasedActions(c, operationID)
+	c.Assert(actions, gc.HasLen, 1)
+	s.finish(c, actions[0], state.ActionFailed)
+
+	_, err = s.Model.AdvanceRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.releasedActions(c, operationID), gc.HasLen, 1)
+	progress, err := s.Model.RolloutProgress(operationID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(progress.Halted, gc.Equals, "1 of 3 tasks failed")
+
+	// A halted operation is not completed.
+	operation, err := s.Model.Operation(operationID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(operation.Status(), gc.Equals, state.ActionRunning)
+
+	err = s.Model.ResumeOperation(operationID)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.Model.ResumeOperation(operationID)
+	c.Assert(err, gc.ErrorMatches, `operation ".*" is not halted`)
+
+	_, err = s.Model.AdvanceRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.releasedActions(c, operationID), gc.HasLen, 2)
+	s.assertBatches(c, operationID,
+		state.RolloutBatch{Failed: 1},
+		state.RolloutBatch{Pending: 1},
+		state.RolloutBatch{Pending: 1},
+	)
+}
+
+func (s *RolloutSuite) TestAdvanceRolloutsDeadReceiver(c *gc.C) {
+	operationID := s.enqueue(c, state.OperationRollout{})
+	err := s.units[1].EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.Model.AdvanceRollouts()
+	c.Assert(err, jc.ErrorIsNil)
+	actions := s.releasedActions(c, operationID)
+	c.Assert(actions, gc.HasLen, 2)
+	s.assertBatches(c, operationID, state.RolloutBatch{Pending: 2, Failed: 1})
+
+	s.finish(c, actions[0], state.ActionCompleted)
+	s.finish(c, actions[1], state.ActionCompleted)
+	operation, err := s.Model.Operation(operationID)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(operation.Status(), gc.Equals, state.ActionFailed)
+}
+
+func (s *RolloutSuite) TestWatchRollouts(c *gc.C) {
+	w := s.State.WatchRollouts()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	s.enqueue(c, state.OperationRollout{})
+	wc.AssertOneChange()
+}
@@ -0,0 +1,411 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/secrets"
+	"github.com/juju/juju/secretbackend"
+	"github.com/juju/juju/secretbackend/file"
+)
+
+// newSecretBackend is patched in tests.
+var newSecretBackend = secretbackend.New
+
+// secretBackendConfig returns the config of the secret backend of the
+// given type. The settings of the external backends are held in the
+// controller config rather than the model config, so that the
+// credentials they contain are never visible to model users. The file
+// backend stores values in the controller machine's data dir, and its
+// key is kept with the controller's other secrets keys.
+func (st *State) secretBackendConfig(backendType string, controllerCfg controller.Config) (secretbackend.Config, error) {
+	cfg := secretbackend.Config{Type: backendType}
+	switch backendType {
+	case secretbackend.Vault:
+		cfg.Vault = controllerCfg.SecretBackendVault()
+	case secretbackend.File:
+		if st.dataDir == "" {
+			return secretbackend.Config{}, errors.NotSupportedf("file secret backend without a data dir")
+		}
+		key, err := secrets.ReadKey(st.secretKeysDir(), secrets.FileBackendKey)
+		if err != nil {
+			return secretbackend.Config{}, errors.Annotate(err, "reading file secret backend key")
+		}
+		cfg.File = file.Config{
+			Path: filepath.Join(st.dataDir, controllerCfg.SecretBackendFileDir()),
+			Key:  key,
+		}
+	}
+	return cfg, nil
+}
+
+// controllerSecretBackend returns the config of the secret backend
+// selected by the controller model, which holds cloud credentials.
+func (st *State) controllerSecretBackend() (secretbackend.Config, error) {
+	uuid := st.ControllerModelUUID()
+	db, closer := st.db().CopyForModel(uuid)
+	defer closer()
+	cfg, err := getModelConfig(db, uuid)
+	if err != nil {
+		return secretbackend.Config{}, errors.Trace(err)
+	}
+	controllerCfg, err := st.ControllerConfig()
+	if err != nil {
+		return secretbackend.Config{}, errors.Trace(err)
+	}
+	return st.secretBackendConfig(cfg.SecretBackend(), controllerCfg)
+}
+
+// modelSecretBackend returns the config of the secret backend selected
+// by the state's model, which holds its charm secrets.
+func (st *State) modelSecretBackend() (secretbackend.Config, error) {
+	cfg, err := getModelConfig(st.db(), st.ModelUUID())
+	if err != nil {
+		return secretbackend.Config{}, errors.Trace(err)
+	}
+	controllerCfg, err := st.ControllerConfig()
+	if err != nil {
+		return secretbackend.Config{}, errors.Trace(err)
+	}
+	return st.secretBackendConfig(cfg.SecretBackend(), controllerCfg)
+}
+
+// openSecretBackend returns the backend described by the config, which
+// must be the type that values were stored in.
+func openSecretBackend(cfg secretbackend.Config, storedIn string) (secretbackend.Backend, error) {
+	if cfg.Type != storedIn {
+		return nil, errors.Errorf(
+			"value stored in %q secret backend, but %q secret backend is configured",
+			storedIn, cfg.Type,
+		)
+	}
+	backend, err := newSecretBackend(cfg)
+	return backend, errors.Annotatef(err, "opening %q secret backend", storedIn)
+}
+
+func cloudCredentialBackendKey(tag names.CloudCredentialTag) string {
+	return "cloudcredentials/" + cloudCredentialDocID(tag)
+}
+
+// putCloudCredentialAttributes stores the credential attributes in
+// the configured secret backend, if it is external. It returns the
+// type of backend the attributes were stored in, or "" if they should
+// be stored in the credential document.
+func putCloudCredentialAttributes(cfg secretbackend.Config, tag names.CloudCredentialTag, attrs map[string]string) (string, error) {
+	if !cfg.IsExternal() {
+		return "", nil
+	}
+	backend, err := newSecretBackend(cfg)
+	if err != nil {
+		return "", errors.Annotatef(err, "opening %q secret backend", cfg.Type)
+	}
+	if err := backend.Put(cloudCredentialBackendKey(tag), attrs); err != nil {
+		return "", errors.Annotatef(err, "storing cloud credential %q attributes", tag.Id())
+	}
+	return cfg.Type, nil
+}
+
+// storeCloudCredentialAttributes stores the credential attributes in
+// the secret backend selected by the controller model, if it is
+// external.
+func (st *State) storeCloudCredentialAttributes(tag names.CloudCredentialTag, attrs map[string]string) (string, error) {
+	cfg, err := st.controllerSecretBackend()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return putCloudCredentialAttributes(cfg, tag, attrs)
+}
+
+// fillCloudCredentialAttributes reads the attributes of a credential
+// from the secret backend they're stored in, if it's external.
+func (st *State) fillCloudCredentialAttributes(doc *cloudCredentialDoc) error {
+	if doc.AttributesBackend == "" {
+		return nil
+	}
+	tag, err := doc.cloudCredentialTag()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	cfg, err := st.controllerSecretBackend()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	backend, err := openSecretBackend(cfg, doc.AttributesBackend)
+	if err != nil {
+		return errors.Annotatef(err, "reading cloud credential %q attributes", tag.Id())
+	}
+	attrs, err := backend.Get(cloudCredentialBackendKey(tag))
+	if err != nil {
+		return errors.Annotatef(err, "reading cloud credential %q attributes", tag.Id())
+	}
+	doc.Attributes = attrs
+	return nil
+}
+
+// deleteCloudCredentialAttributes removes the attributes of a
+// credential from the external secret backend they're stored in.
+func (st *State) deleteCloudCredentialAttributes(tag names.CloudCredentialTag, storedIn string) error {
+	cfg, err := st.controllerSecretBackend()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	backend, err := openSecretBackend(cfg, storedIn)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(backend.Delete(cloudCredentialBackendKey(tag)))
+}
+
+func secretBackendKey(modelUUID, secretID string, revision int) string {
+	return fmt.Sprintf("secrets/%s/%s/%d", modelUUID, secretID, revision)
+}
+
+// storeSecretRevision stores the value of a secret revision in the
+// secret backend selected by the model. Values are stored, encrypted,
+// in the revision document unless the backend is external.
+func (st *State) storeSecretRevision(doc *secretRevisionDoc, data secrets.SecretData) error {
+	cfg, err := st.modelSecretBackend()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	uri := &secrets.URI{ID: doc.SecretID}
+	if !cfg.IsExternal() {
+		doc.Data, err = st.encryptSecretData(uri, doc.Revision, data)
+		return errors.Trace(err)
+	}
+	backend, err := newSecretBackend(cfg)
+	if err != nil {
+		return errors.Annotatef(err, "opening %q secret backend", cfg.Type)
+	}
+	key := secretBackendKey(st.ModelUUID(), doc.SecretID, doc.Revision)
+	if err := backend.Put(key, data); err != nil {
+		return errors.Annotatef(err, "storing revision %d of secret %q", doc.Revision, uri)
+	}
+	doc.Backend = cfg.Type
+	doc.Data = nil
+	return nil
+}
+
+// secretRevisionData returns the value of a secret revision, from the
+// document or the external secret backend it's stored in.
+func (st *State) secretRevisionData(doc *secretRevisionDoc) (secrets.SecretData, error) {
+	uri := &secrets.URI{ID: doc.SecretID}
+	if doc.Backend == "" {
+		return st.decryptSecretData(uri, doc.Revision, doc.Data)
+	}
+	cfg, err := st.modelSecretBackend()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	backend, err := openSecretBackend(cfg, doc.Backend)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	data, err := backend.Get(secretBackendKey(st.ModelUUID(), doc.SecretID, doc.Revision))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return data, nil
+}
+
+// removeExternalSecretRevisionsOps returns cleanup ops to remove the
+// values of the revisions of the given secrets that are stored in an
+// external secret backend.
+func (st *State) removeExternalSecretRevisionsOps(secretIDs []string) ([]txn.Op, error) {
+	coll, closer := st.db().GetCollection(secretRevisionsC)
+	defer closer()
+
+	var docs []secretRevisionDoc
+	err := coll.Find(bson.D{
+		{"secret-id", bson.D{{"$in", secretIDs}}},
+		{"backend", bson.D{{"$exists", true}}},
+	}).Select(bson.D{{"secret-id", 1}, {"revision", 1}, {"backend", 1}}).All(&docs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		key := secretBackendKey(st.ModelUUID(), doc.SecretID, doc.Revision)
+		ops[i] = newCleanupOp(cleanupSecretBackendValue, key, doc.Backend)
+	}
+	return ops, nil
+}
+
+// cleanupSecretBackendValue removes a secret revision's value from the
+// external secret backend it's stored in.
+func (st *State) cleanupSecretBackendValue(key string, cleanupArgs []bson.Raw) error {
+	var storedIn string
+	if n := len(cleanupArgs); n != 1 {
+		return errors.Errorf("expected 1 argument, got %d", n)
+	}
+	if err := cleanupArgs[0].Unmarshal(&storedIn); err != nil {
+		return errors.Annotate(err, "unmarshalling cleanup args")
+	}
+	cfg, err := st.modelSecretBackend()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	backend, err := openSecretBackend(cfg, storedIn)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(backend.Delete(key))
+}
+
+// secretBackendMigration holds the changes needed to move the values
+// stored in a model's external secret backend into the backend it is
+// being changed to.
+type secretBackendMigration struct {
+	// ops update the documents referring to the moved values.
+	ops []txn.Op
+
+	// from is the backend the values are moved from, and stale holds
+	// the keys to remove from it once ops have been run.
+	from  secretbackend.Backend
+	stale []string
+}
+
+// migrateSecretBackend copies the values stored in the external secret
+// backend of type "from" into the backend of type "to", so that they're
+// still readable once the model uses it. Cloud credential attributes
+// are moved too if the state's model is the controller model. Values
+// stored in the controller database are left there; they're readable
+// whichever backend is selected.
+//
+// The returned migration's ops must be run along with the model config
+// change, and its stale values removed afterwards.
+func (st *State) migrateSecretBackend(from, to string) (*secretBackendMigration, error) {
+	controllerCfg, err := st.ControllerConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	toCfg, err := st.secretBackendConfig(to, controllerCfg)
+	if err == nil {
+		err = toCfg.Validate()
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "%q secret backend not configured", to)
+	}
+	fromCfg, err := st.secretBackendConfig(from, controllerCfg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !fromCfg.IsExternal() {
+		return &secretBackendMigration{}, nil
+	}
+
+	migration := &secretBackendMigration{}
+	migration.from, err = newSecretBackend(fromCfg)
+	if err != nil {
+		return nil, errors.Annotatef(err, "opening %q secret backend", from)
+	}
+	var toBackend secretbackend.Backend
+	if toCfg.IsExternal() {
+		toBackend, err = newSecretBackend(toCfg)
+		if err != nil {
+			return nil, errors.Annotatef(err, "opening %q secret backend", to)
+		}
+	}
+
+	revisions, closer := st.db().GetCollection(secretRevisionsC)
+	defer closer()
+	var revisionDocs []secretRevisionDoc
+	err = revisions.Find(bson.D{{"backend", from}}).
+		Select(bson.D{{"secret-id", 1}, {"revision", 1}}).All(&revisionDocs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, doc := range revisionDocs {
+		key := secretBackendKey(st.ModelUUID(), doc.SecretID, doc.Revision)
+		value, err := migration.from.Get(key)
+		if err != nil {
+			return nil, errors.Annotatef(err, "reading revision %d of secret %q", doc.Revision, doc.SecretID)
+		}
+		var update bson.D
+		if toBackend != nil {
+			if err := toBackend.Put(key, value); err != nil {
+				return nil, errors.Annotatef(err, "storing revision %d of secret %q", doc.Revision, doc.SecretID)
+			}
+			update = bson.D{{"$set", bson.D{{"backend", to}}}}
+		} else {
+			uri := &secrets.URI{ID: doc.SecretID}
+			data, err := st.encryptSecretData(uri, doc.Revision, value)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			update = bson.D{
+				{"$set", bson.D{{"data", data}}},
+				{"$unset", bson.D{{"backend", 1}}},
+			}
+		}
+		migration.ops = append(migration.ops, txn.Op{
+			C:      secretRevisionsC,
+			Id:     doc.DocID,
+			Assert: bson.D{{"backend", from}},
+			Update: update,
+		})
+		migration.stale = append(migration.stale, key)
+	}
+
+	if !st.IsController() {
+		return migration, nil
+	}
+	credentials, closer := st.db().GetCollection(cloudCredentialsC)
+	defer closer()
+	var credentialDocs []cloudCredentialDoc
+	err = credentials.Find(bson.D{{"attributes-backend", from}}).All(&credentialDocs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, doc := range credentialDocs {
+		tag, err := doc.cloudCredentialTag()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		key := cloudCredentialBackendKey(tag)
+		attrs, err := migration.from.Get(key)
+		if err != nil {
+			return nil, errors.Annotatef(err, "reading cloud credential %q attributes", tag.Id())
+		}
+		var update bson.D
+		if toBackend != nil {
+			if err := toBackend.Put(key, attrs); err != nil {
+				return nil, errors.Annotatef(err, "storing cloud credential %q attributes", tag.Id())
+			}
+			update = bson.D{{"$set", bson.D{{"attributes-backend", to}}}}
+		} else {
+			update = bson.D{
+				{"$set", bson.D{{"attributes", attrs}}},
+				{"$unset", bson.D{{"attributes-backend", 1}}},
+			}
+		}
+		migration.ops = append(migration.ops, txn.Op{
+			C:      cloudCredentialsC,
+			Id:     doc.DocID,
+			Assert: bson.D{{"attributes-backend", from}},
+			Update: update,
+		})
+		migration.stale = append(migration.stale, key)
+	}
+	return migration, nil
+}
+
+// removeStale removes the moved values from the backend they were
+// moved from. Failures are logged rather than returned, as the values
+// are no longer referred to.
+func (m *secretBackendMigration) removeStale() {
+	for _, key := range m.stale {
+		if err := m.from.Delete(key); err != nil {
+			logger.Warningf("could not remove %q from old secret backend: %v", key, err)
+		}
+	}
+}
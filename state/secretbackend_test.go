@@ -0,0 +1,241 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/core/secrets"
+	"github.com/juju/juju/secretbackend/vault/vaulttesting"
+	"github.com/juju/juju/state"
+)
+
+type SecretBackendSuite struct {
+	ConnSuite
+	server *vaulttesting.Server
+}
+
+var _ = gc.Suite(&SecretBackendSuite{})
+
+func (s *SecretBackendSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.server = vaulttesting.NewServer("s3cret")
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+
+	err := s.State.UpdateControllerConfig(map[string]interface{}{
+		"secret-backend-vault-address": s.server.URL,
+		"secret-backend-vault-token":   "s3cret",
+		"secret-backend-vault-mount":   "juju",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.Model.UpdateModelConfig(map[string]interface{}{
+		"secret-backend": "vault",
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.AddCloud(cloud.Cloud{
+		Name:      "stratus",
+		Type:      "low",
+		AuthTypes: cloud.AuthTypes{cloud.AccessKeyAuthType},
+	}, s.Owner.Name())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SecretBackendSuite) rawCredentialDoc(c *gc.C, id string) bson.M {
+	coll, closer := state.GetRawCollection(s.State, "cloudCredentials")
+	defer closer()
+	var doc bson.M
+	err := coll.FindId(id).One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	return doc
+}
+
+func (s *SecretBackendSuite) TestBackendSettingsNotInModelConfig(c *gc.C) {
+	cfg, err := s.Model.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	for key := range cfg.AllAttrs() {
+		c.Check(key, gc.Not(gc.Matches), "secret-backend-.*")
+	}
+}
+
+func (s *SecretBackendSuite) TestCloudCredentialStoredInBackend(c *gc.C) {
+	tag := names.NewCloudCredentialTag("stratus/bob/foobar")
+	cred := cloud.NewCredential(cloud.AccessKeyAuthType, map[string]string{
+		"access-key": "AKIA",
+		"secret-key": "hunter2",
+	})
+	err := s.State.UpdateCloudCredential(tag, cred)
+	c.Assert(err, jc.ErrorIsNil)
+
+	doc := s.rawCredentialDoc(c, "stratus#bob#foobar")
+	c.Check(doc["attributes"], gc.IsNil)
+	c.Check(doc["attributes-backend"], gc.Equals, "vault")
+	value, ok := s.server.Value("juju/cloudcredentials/stratus#bob#foobar")
+	c.Assert(ok, jc.IsTrue)
+	c.Check(value, jc.DeepEquals, cred.Attributes())
+
+	out, err := s.State.CloudCredential(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(out.Attributes, jc.DeepEquals, cred.Attributes())
+
+	all, err := s.State.CloudCredentials(names.NewUserTag("bob"), "stratus")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(all["stratus/bob/foobar"].Attributes, jc.DeepEquals, cred.Attributes())
+}
+
+func (s *SecretBackendSuite) TestUpdateCloudCredentialInBackend(c *gc.C) {
+	tag := names.NewCloudCredentialTag("stratus/bob/foobar")
+	err := s.State.UpdateCloudCredential(tag, cloud.NewCredential(cloud.AccessKeyAuthType, map[string]string{
+		"access-key": "AKIA", "secret-key": "hunter2",
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.UpdateCloudCredential(tag, cloud.NewCredential(cloud.AccessKeyAuthType, map[string]string{
+		"access-key": "AKIA", "secret-key": "hunter3",
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+
+	out, err := s.State.CloudCredential(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(out.Attributes["secret-key"], gc.Equals, "hunter3")
+	c.Check(s.server.Versions("juju/cloudcredentials/stratus#bob#foobar"), gc.Equals, 2)
+}
+
+func (s *SecretBackendSuite) TestChangeBackendMovesCloudCredentialToInternal(c *gc.C) {
+	tag := names.NewCloudCredentialTag("stratus/bob/foobar")
+	cred := cloud.NewCredential(cloud.AccessKeyAuthType, map[string]string{
+		"access-key": "AKIA", "secret-key": "hunter2",
+	})
+	err := s.State.UpdateCloudCredential(tag, cred)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.Model.UpdateModelConfig(map[string]interface{}{"secret-backend": "internal"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	doc := s.rawCredentialDoc(c, "stratus#bob#foobar")
+	c.Check(doc["attributes-backend"], gc.IsNil)
+	_, ok := s.server.Value("juju/cloudcredentials/stratus#bob#foobar")
+	c.Check(ok, jc.IsFalse)
+	out, err := s.State.CloudCredential(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(out.Attributes, jc.DeepEquals, cred.Attributes())
+}
+
+func (s *SecretBackendSuite) TestChangeBackendMovesSecretsToFile(c *gc.C) {
+	owner := s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	uri := secrets.NewURI()
+	_, err := s.State.CreateSecret(uri, state.CreateSecretParams{
+		Owner: owner.ApplicationTag(),
+		Data:  secrets.SecretData{"password": "s3cret"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.Model.UpdateModelConfig(map[string]interface{}{"secret-backend": "file"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	coll, closer := state.GetRawCollection(s.State, "secretRevisions")
+	defer closer()
+	var doc bson.M
+	err = coll.FindId(s.State.ModelUUID() + ":" + uri.ID + "/1").One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(doc["backend"], gc.Equals, "file")
+	_, ok := s.server.Value(fmt.Sprintf("juju/secrets/%s/%s/1", s.State.ModelUUID(), uri.ID))
+	c.Check(ok, jc.IsFalse)
+	data, err := s.State.GetSecretValue(uri, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(data, jc.DeepEquals, secrets.SecretData{"password": "s3cret"})
+}
+
+func (s *SecretBackendSuite) TestChangeBackendNotConfigured(c *gc.C) {
+	err := s.Model.UpdateModelConfig(map[string]interface{}{"secret-backend": "internal"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.UpdateControllerConfig(nil, []string{
+		"secret-backend-vault-address",
+		"secret-backend-vault-token",
+		"secret-backend-vault-mount",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.Model.UpdateModelConfig(map[string]interface{}{"secret-backend": "vault"}, nil)
+	c.Assert(err, gc.ErrorMatches, `changing secret backend from "internal" to "vault": "vault" secret backend not configured: vault: empty address not valid`)
+
+	cfg, err := s.Model.ModelConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cfg.SecretBackend(), gc.Equals, "internal")
+}
+
+func (s *SecretBackendSuite) TestRemoveCloudCredentialRemovesFromBackend(c *gc.C) {
+	tag := names.NewCloudCredentialTag("stratus/bob/foobar")
+	err := s.State.UpdateCloudCredential(tag, cloud.NewCredential(cloud.AccessKeyAuthType, map[string]string{
+		"access-key": "AKIA", "secret-key": "hunter2",
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.State.RemoveCloudCredential(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := s.server.Value("juju/cloudcredentials/stratus#bob#foobar")
+	c.Check(ok, jc.IsFalse)
+	_, err = s.State.CloudCredential(tag)
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SecretBackendSuite) TestSecretStoredInBackend(c *gc.C) {
+	owner := s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	uri := secrets.NewURI()
+	_, err := s.State.CreateSecret(uri, state.CreateSecretParams{
+		Owner: owner.ApplicationTag(),
+		Data:  secrets.SecretData{"password": "s3cret"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.UpdateSecret(uri, state.UpdateSecretParams{
+		Data: secrets.SecretData{"password": "n3w"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	coll, closer := state.GetRawCollection(s.State, "secretRevisions")
+	defer closer()
+	var doc bson.M
+	err = coll.FindId(s.State.ModelUUID() + ":" + uri.ID + "/1").One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(doc["data"], gc.IsNil)
+	c.Check(doc["backend"], gc.Equals, "vault")
+
+	for revision, password := range map[int]string{1: "s3cret", 2: "n3w"} {
+		path := fmt.Sprintf("juju/secrets/%s/%s/%d", s.State.ModelUUID(), uri.ID, revision)
+		value, ok := s.server.Value(path)
+		c.Assert(ok, jc.IsTrue)
+		c.Check(value, jc.DeepEquals, map[string]string{"password": password})
+
+		data, err := s.State.GetSecretValue(uri, revision)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(data, jc.DeepEquals, secrets.SecretData{"password": password})
+	}
+}
+
+func (s *SecretBackendSuite) TestRemoveOwnerRemovesSecretsFromBackend(c *gc.C) {
+	owner := s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	uri := secrets.NewURI()
+	_, err := s.State.CreateSecret(uri, state.CreateSecretParams{
+		Owner: owner.ApplicationTag(),
+		Data:  secrets.SecretData{"password": "s3cret"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	path := fmt.Sprintf("juju/secrets/%s/%s/1", s.State.ModelUUID(), uri.ID)
+	_, ok := s.server.Value(path)
+	c.Assert(ok, jc.IsTrue)
+
+	err = owner.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, ok = s.server.Value(path)
+	c.Check(ok, jc.IsFalse)
+}
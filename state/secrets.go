@@ -0,0 +1,540 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/secrets"
+)
+
+// CreateSecretParams holds the attributes of a new secret.
+type CreateSecretParams struct {
+	// Owner is the application or unit that owns the secret.
+	Owner names.Tag
+
+	// Description describes the secret.
+	Description string
+
+	// RotatePolicy is how often the secret should be rotated.
+	RotatePolicy secrets.RotatePolicy
+
+	// ExpireTime is when the secret expires, if it does.
+	ExpireTime *time.Time
+
+	// Data is the value of the secret's first revision.
+	Data secrets.SecretData
+}
+
+// UpdateSecretParams holds the attributes of a secret to update; nil
+// attributes are left unchanged.
+type UpdateSecretParams struct {
+	Description  *string
+	RotatePolicy *secrets.RotatePolicy
+	ExpireTime   *time.Time
+
+	// Data, if set, is the value of a new revision of the secret.
+	Data secrets.SecretData
+}
+
+type secretMetadataDoc struct {
+	DocID          string     `bson:"_id"`
+	ModelUUID      string     `bson:"model-uuid"`
+	OwnerTag       string     `bson:"owner-tag"`
+	Description    string     `bson:"description,omitempty"`
+	RotatePolicy   string     `bson:"rotate-policy,omitempty"`
+	NextRotateTime *time.Time `bson:"next-rotate-time,omitempty"`
+	ExpireTime     *time.Time `bson:"expire-time,omitempty"`
+	LatestRevision int        `bson:"latest-revision"`
+	CreateTime     time.Time  `bson:"create-time"`
+	UpdateTime     time.Time  `bson:"update-time"`
+}
+
+// secretRevisionDoc holds a revision of the value of a secret. The
+// value is encrypted (see encryptSecretData), or stored in the external
+// secret backend named by Backend.
+type secretRevisionDoc struct {
+	DocID      string    `bson:"_id"`
+	ModelUUID  string    `bson:"model-uuid"`
+	SecretID   string    `bson:"secret-id"`
+	Revision   int       `bson:"revision"`
+	CreateTime time.Time `bson:"create-time"`
+	Data       []byte    `bson:"data,omitempty"`
+	Backend    string    `bson:"backend,omitempty"`
+}
+
+// secretPermissionDoc records that an application or unit, other than
+// the owner, may read a secret.
+type secretPermissionDoc struct {
+	DocID      string `bson:"_id"`
+	ModelUUID  string `bson:"model-uuid"`
+	SecretID   string `bson:"secret-id"`
+	SubjectTag string `bson:"subject-tag"`
+}
+
+// secretConsumerDoc records the revision of a secret a unit other than
+// the owner is using, so the unit only sees a new revision when it asks
+// for it.
+type secretConsumerDoc struct {
+	DocID           string `bson:"_id"`
+	ModelUUID       string `bson:"model-uuid"`
+	SecretID        string `bson:"secret-id"`
+	ConsumerTag     string `bson:"consumer-tag"`
+	CurrentRevision int    `bson:"current-revision"`
+}
+
+func secretRevisionKey(uri *secrets.URI, revision int) string {
+	return fmt.Sprintf("%s/%d", uri.ID, revision)
+}
+
+func secretEntityKey(uri *secrets.URI, tag names.Tag) string {
+	return fmt.Sprintf("%s#%s", uri.ID, tag)
+}
+
+func (doc *secretMetadataDoc) metadata(uri *secrets.URI) (*secrets.SecretMetadata, error) {
+	owner, err := names.ParseTag(doc.OwnerTag)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &secrets.SecretMetadata{
+		URI:            uri,
+		OwnerTag:       owner,
+		Description:    doc.Description,
+		RotatePolicy:   secrets.RotatePolicy(doc.RotatePolicy),
+		NextRotateTime: doc.NextRotateTime,
+		ExpireTime:     doc.ExpireTime,
+		LatestRevision: doc.LatestRevision,
+		CreateTime:     doc.CreateTime,
+		UpdateTime:     doc.UpdateTime,
+	}, nil
+}
+
+// secretEntityAliveOp returns an op asserting that the application or
+// unit with the given tag is alive, or an error if it isn't.
+func (st *State) secretEntityAliveOp(tag names.Tag) (txn.Op, error) {
+	var (
+		life Life
+		op   txn.Op
+	)
+	switch tag := tag.(type) {
+	case names.ApplicationTag:
+		app, err := st.Application(tag.Id())
+		if err != nil {
+			return txn.Op{}, errors.Trace(err)
+		}
+		life = app.Life()
+		op = txn.Op{C: applicationsC, Id: app.doc.DocID, Assert: isAliveDoc}
+	case names.UnitTag:
+		unit, err := st.Unit(tag.Id())
+		if err != nil {
+			return txn.Op{}, errors.Trace(err)
+		}
+		life = unit.Life()
+		op = txn.Op{C: unitsC, Id: unit.doc.DocID, Assert: isAliveDoc}
+	default:
+		return txn.Op{}, errors.NotValidf("secret owner or consumer %q", tag)
+	}
+	if life != Alive {
+		return txn.Op{}, errors.Errorf("%s is not alive", names.ReadableString(tag))
+	}
+	return op, nil
+}
+
+// CreateSecret creates a secret with the given URI, and its first
+// revision.
+func (st *State) CreateSecret(uri *secrets.URI, p CreateSecretParams) (*secrets.SecretMetadata, error) {
+	if err := p.Data.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !p.RotatePolicy.IsValid() {
+		return nil, errors.NotValidf("rotate policy %q", p.RotatePolicy)
+	}
+	now := st.nowToTheSecond()
+	metadataDoc := secretMetadataDoc{
+		DocID:          st.docID(uri.ID),
+		ModelUUID:      st.ModelUUID(),
+		OwnerTag:       p.Owner.String(),
+		Description:    p.Description,
+		RotatePolicy:   string(p.RotatePolicy),
+		NextRotateTime: p.RotatePolicy.NextRotateTime(now),
+		ExpireTime:     p.ExpireTime,
+		LatestRevision: 1,
+		CreateTime:     now,
+		UpdateTime:     now,
+	}
+	revisionDoc := secretRevisionDoc{
+		DocID:      st.docID(secretRevisionKey(uri, 1)),
+		ModelUUID:  st.ModelUUID(),
+		SecretID:   uri.ID,
+		Revision:   1,
+		CreateTime: now,
+	}
+	if err := st.storeSecretRevision(&revisionDoc, p.Data); err != nil {
+		return nil, errors.Annotatef(err, "cannot create secret")
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if _, err := st.GetSecret(uri); err == nil {
+				return nil, errors.AlreadyExistsf("secret %q", uri)
+			}
+		}
+		ownerOp, err := st.secretEntityAliveOp(p.Owner)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{ownerOp, {
+			C:      secretMetadataC,
+			Id:     metadataDoc.DocID,
+			Assert: txn.DocMissing,
+			Insert: &metadataDoc,
+		}, {
+			C:      secretRevisionsC,
+			Id:     revisionDoc.DocID,
+			Assert: txn.DocMissing,
+			Insert: &revisionDoc,
+		}}, nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		return nil, errors.Annotatef(err, "cannot create secret")
+	}
+	return metadataDoc.metadata(uri)
+}
+
+// UpdateSecret updates the attributes of a secret, adding a new
+// revision if new data is given.
+func (st *State) UpdateSecret(uri *secrets.URI, p UpdateSecretParams) (*secrets.SecretMetadata, error) {
+	if p.Description == nil && p.RotatePolicy == nil && p.ExpireTime == nil && p.Data == nil {
+		return nil, errors.NotValidf("empty secret update")
+	}
+	if p.Data != nil {
+		if err := p.Data.Validate(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if p.RotatePolicy != nil && !p.RotatePolicy.IsValid() {
+		return nil, errors.NotValidf("rotate policy %q", *p.RotatePolicy)
+	}
+	var metadataDoc secretMetadataDoc
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		doc, err := st.secretMetadataDoc(uri)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		now := st.nowToTheSecond()
+		updates := bson.D{{"update-time", now}}
+		doc.UpdateTime = now
+		if p.Description != nil {
+			doc.Description = *p.Description
+			updates = append(updates, bson.DocElem{"description", doc.Description})
+		}
+		if p.ExpireTime != nil {
+			doc.ExpireTime = p.ExpireTime
+			updates = append(updates, bson.DocElem{"expire-time", doc.ExpireTime})
+		}
+		// The next rotation is due an interval after the secret is
+		// rotated, or its rotate policy changes.
+		if p.RotatePolicy != nil || p.Data != nil {
+			if p.RotatePolicy != nil {
+				doc.RotatePolicy = string(*p.RotatePolicy)
+			}
+			doc.NextRotateTime = secrets.RotatePolicy(doc.RotatePolicy).NextRotateTime(now)
+			updates = append(updates,
+				bson.DocElem{"rotate-policy", doc.RotatePolicy},
+				bson.DocElem{"next-rotate-time", doc.NextRotateTime},
+			)
+		}
+		ops := []txn.Op{{
+			C:      secretMetadataC,
+			Id:     doc.DocID,
+			Assert: bson.D{{"latest-revision", doc.LatestRevision}},
+		}}
+		if p.Data != nil {
+			revision := doc.LatestRevision + 1
+			revisionDoc := &secretRevisionDoc{
+				DocID:      st.docID(secretRevisionKey(uri, revision)),
+				ModelUUID:  st.ModelUUID(),
+				SecretID:   uri.ID,
+				Revision:   revision,
+				CreateTime: now,
+			}
+			if err := st.storeSecretRevision(revisionDoc, p.Data); err != nil {
+				return nil, errors.Trace(err)
+			}
+			doc.LatestRevision = revision
+			updates = append(updates, bson.DocElem{"latest-revision", revision})
+			ops = append(ops, txn.Op{
+				C:      secretRevisionsC,
+				Id:     revisionDoc.DocID,
+				Assert: txn.DocMissing,
+				Insert: revisionDoc,
+			})
+		}
+		ops[0].Update = bson.D{{"$set", updates}}
+		metadataDoc = *doc
+		return ops, nil
+	}
+	if err := st.db().Run(buildTxn); err != nil {
+		return nil, errors.Annotatef(err, "cannot update secret")
+	}
+	return metadataDoc.metadata(uri)
+}
+
+func (st *State) secretMetadataDoc(uri *secrets.URI) (*secretMetadataDoc, error) {
+	coll, closer := st.db().GetCollection(secretMetadataC)
+	defer closer()
+
+	var doc secretMetadataDoc
+	err := coll.FindId(uri.ID).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("secret %q", uri)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &doc, nil
+}
+
+// GetSecret returns the metadata of the secret with the given URI.
+func (st *State) GetSecret(uri *secrets.URI) (*secrets.SecretMetadata, error) {
+	doc, err := st.secretMetadataDoc(uri)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return doc.metadata(uri)
+}
+
+// GetSecretValue returns the value of the given revision of a secret.
+func (st *State) GetSecretValue(uri *secrets.URI, revision int) (secrets.SecretData, error) {
+	coll, closer := st.db().GetCollection(secretRevisionsC)
+	defer closer()
+
+	var doc secretRevisionDoc
+	err := coll.FindId(secretRevisionKey(uri, revision)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("revision %d of secret %q", revision, uri)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	data, err := st.secretRevisionData(&doc)
+	if err != nil {
+		return nil, errors.Annotatef(err, "reading revision %d of secret %q", revision, uri)
+	}
+	return data, nil
+}
+
+// GrantSecretAccess lets the application or unit with the given tag
+// read the secret. Units may read the secrets their application may
+// read.
+func (st *State) GrantSecretAccess(uri *secrets.URI, subject names.Tag) error {
+	doc := secretPermissionDoc{
+		DocID:      st.docID(secretEntityKey(uri, subject)),
+		ModelUUID:  st.ModelUUID(),
+		SecretID:   uri.ID,
+		SubjectTag: subject.String(),
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if _, err := st.secretMetadataDoc(uri); err != nil {
+			return nil, errors.Trace(err)
+		}
+		granted, err := st.HasSecretAccess(uri, subject)
+		if err != nil {
+			return nil, errors.Trace(err)
+		} else if granted {
+			return nil, jujutxn.ErrNoOperations
+		}
+		subjectOp, err := st.secretEntityAliveOp(subject)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []txn.Op{subjectOp, {
+			C:      secretMetadataC,
+			Id:     st.docID(uri.ID),
+			Assert: txn.DocExists,
+		}, {
+			C:      secretPermissionsC,
+			Id:     doc.DocID,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		}}, nil
+	}
+	return errors.Annotatef(st.db().Run(buildTxn), "cannot grant access to secret")
+}
+
+// RevokeSecretAccess stops the application or unit with the given tag
+// reading the secret, unless it is granted access some other way.
+func (st *State) RevokeSecretAccess(uri *secrets.URI, subject names.Tag) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if _, err := st.secretMetadataDoc(uri); err != nil {
+			return nil, errors.Trace(err)
+		}
+		granted, err := st.HasSecretAccess(uri, subject)
+		if err != nil {
+			return nil, errors.Trace(err)
+		} else if !granted {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{{
+			C:      secretPermissionsC,
+			Id:     st.docID(secretEntityKey(uri, subject)),
+			Assert: txn.DocExists,
+			Remove: true,
+		}}, nil
+	}
+	return errors.Annotatef(st.db().Run(buildTxn), "cannot revoke access to secret")
+}
+
+// HasSecretAccess reports whether the application or unit with the
+// given tag has been granted access to the secret.
+func (st *State) HasSecretAccess(uri *secrets.URI, subject names.Tag) (bool, error) {
+	coll, closer := st.db().GetCollection(secretPermissionsC)
+	defer closer()
+
+	n, err := coll.FindId(secretEntityKey(uri, subject)).Count()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return n > 0, nil
+}
+
+// GetSecretConsumer returns the revision of the secret the unit with
+// the given tag is using.
+func (st *State) GetSecretConsumer(uri *secrets.URI, consumer names.Tag) (int, error) {
+	coll, closer := st.db().GetCollection(secretConsumersC)
+	defer closer()
+
+	var doc secretConsumerDoc
+	err := coll.FindId(secretEntityKey(uri, consumer)).One(&doc)
+	if err == mgo.ErrNotFound {
+		return 0, errors.NotFoundf("consumer %q of secret %q", consumer, uri)
+	} else if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return doc.CurrentRevision, nil
+}
+
+// SaveSecretConsumer records the revision of the secret the unit with
+// the given tag is using.
+func (st *State) SaveSecretConsumer(uri *secrets.URI, consumer names.Tag, revision int) error {
+	docID := st.docID(secretEntityKey(uri, consumer))
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if _, err := st.secretMetadataDoc(uri); err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      secretMetadataC,
+			Id:     st.docID(uri.ID),
+			Assert: txn.DocExists,
+		}}
+		current, err := st.GetSecretConsumer(uri, consumer)
+		switch {
+		case errors.IsNotFound(err):
+			consumerOp, err := st.secretEntityAliveOp(consumer)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, consumerOp, txn.Op{
+				C:      secretConsumersC,
+				Id:     docID,
+				Assert: txn.DocMissing,
+				Insert: &secretConsumerDoc{
+					DocID:           docID,
+					ModelUUID:       st.ModelUUID(),
+					SecretID:        uri.ID,
+					ConsumerTag:     consumer.String(),
+					CurrentRevision: revision,
+				},
+			})
+		case err != nil:
+			return nil, errors.Trace(err)
+		case current == revision:
+			return nil, jujutxn.ErrNoOperations
+		default:
+			ops = append(ops, txn.Op{
+				C:      secretConsumersC,
+				Id:     docID,
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{{"current-revision", revision}}}},
+			})
+		}
+		return ops, nil
+	}
+	return errors.Annotatef(st.db().Run(buildTxn), "cannot save secret consumer")
+}
+
+// removeSecretsOps returns the ops to remove the secrets owned by the
+// application or unit with the given tag, and any access to secrets it
+// was granted.
+func removeSecretsOps(st *State, tag names.Tag) ([]txn.Op, error) {
+	var ids []struct {
+		DocID string `bson:"_id"`
+	}
+	removeAll := func(collection string, query bson.D) error {
+		coll, closer := st.db().GetCollection(collection)
+		defer closer()
+		if err := coll.Find(query).Select(bson.D{{"_id", 1}}).All(&ids); err != nil {
+			return errors.Trace(err)
+		}
+		return nil
+	}
+	var ops []txn.Op
+	// Docs of owned secrets may also match the tag as a subject or
+	// consumer, and each doc may only be removed once.
+	removed := make(map[string]bool)
+	addRemoveOps := func(collection string) {
+		for _, id := range ids {
+			key := collection + "/" + id.DocID
+			if removed[key] {
+				continue
+			}
+			removed[key] = true
+			ops = append(ops, txn.Op{
+				C:      collection,
+				Id:     id.DocID,
+				Remove: true,
+			})
+		}
+	}
+
+	if err := removeAll(secretMetadataC, bson.D{{"owner-tag", tag.String()}}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var owned []string
+	for _, id := range ids {
+		owned = append(owned, st.localID(id.DocID))
+	}
+	addRemoveOps(secretMetadataC)
+	if len(owned) > 0 {
+		cleanupOps, err := st.removeExternalSecretRevisionsOps(owned)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, cleanupOps...)
+	}
+	for _, collection := range []string{secretRevisionsC, secretPermissionsC, secretConsumersC} {
+		if len(owned) == 0 {
+			break
+		}
+		if err := removeAll(collection, bson.D{{"secret-id", bson.D{{"$in", owned}}}}); err != nil {
+			return nil, errors.Trace(err)
+		}
+		addRemoveOps(collection)
+	}
+
+	if err := removeAll(secretPermissionsC, bson.D{{"subject-tag", tag.String()}}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	addRemoveOps(secretPermissionsC)
+	if err := removeAll(secretConsumersC, bson.D{{"consumer-tag", tag.String()}}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	addRemoveOps(secretConsumersC)
+	return ops, nil
+}
@@ -0,0 +1,258 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/core/secrets"
+	"github.com/juju/juju/state"
+)
+
+type SecretsSuite struct {
+	ConnSuite
+	owner    *state.Application
+	consumer *state.Unit
+}
+
+var _ = gc.Suite(&SecretsSuite{})
+
+func (s *SecretsSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.owner = s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	app := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	var err error
+	s.consumer, err = app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *SecretsSuite) createSecret(c *gc.C) *secrets.URI {
+	uri := secrets.NewURI()
+	_, err := s.State.CreateSecret(uri, state.CreateSecretParams{
+		Owner: s.owner.ApplicationTag(),
+		Data:  secrets.SecretData{"password": "s3cret"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return uri
+}
+
+func (s *SecretsSuite) TestCreateSecret(c *gc.C) {
+	uri := secrets.NewURI()
+	expire := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	md, err := s.State.CreateSecret(uri, state.CreateSecretParams{
+		Owner:        s.owner.ApplicationTag(),
+		Description:  "db password",
+		RotatePolicy: secrets.RotateDaily,
+		ExpireTime:   &expire,
+		Data:         secrets.SecretData{"password": "s3cret"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(md.URI, jc.DeepEquals, uri)
+	c.Check(md.OwnerTag, gc.Equals, s.owner.ApplicationTag())
+	c.Check(md.LatestRevision, gc.Equals, 1)
+	c.Assert(md.NextRotateTime, gc.NotNil)
+	c.Check(md.NextRotateTime.Sub(md.CreateTime), gc.Equals, 24*time.Hour)
+
+	md, err = s.State.GetSecret(uri)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(md.Description, gc.Equals, "db password")
+	c.Check(md.RotatePolicy, gc.Equals, secrets.RotateDaily)
+	c.Check(md.ExpireTime.Equal(expire), jc.IsTrue)
+
+	data, err := s.State.GetSecretValue(uri, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(data, jc.DeepEquals, secrets.SecretData{"password": "s3cret"})
+}
+
+func (s *SecretsSuite) TestCreateSecretEncrypted(c *gc.C) {
+	uri := s.createSecret(c)
+
+	coll, closer := state.GetRawCollection(s.State, "secretRevisions")
+	defer closer()
+	var doc bson.M
+	err := coll.FindId(s.State.ModelUUID() + ":" + uri.ID + "/1").One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	data, ok := doc["data"].([]byte)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(bytes.Contains(data, []byte("s3cret")), jc.IsFalse)
+}
+
+func (s *SecretsSuite) TestCreateSecretInvalid(c *gc.C) {
+	_, err := s.State.CreateSecret(secrets.NewURI(), state.CreateSecretParams{
+		Owner: s.owner.ApplicationTag(),
+	})
+	c.Assert(err, gc.ErrorMatches, "empty secret not valid")
+
+	_, err = s.State.CreateSecret(secrets.NewURI(), state.CreateSecretParams{
+		Owner:        s.owner.ApplicationTag(),
+		RotatePolicy: "fortnightly",
+		Data:         secrets.SecretData{"password": "s3cret"},
+	})
+	c.Assert(err, gc.ErrorMatches, `rotate policy "fortnightly" not valid`)
+}
+
+func (s *SecretsSuite) TestCreateSecretOwnerNotFound(c *gc.C) {
+	_, err := s.State.CreateSecret(secrets.NewURI(), state.CreateSecretParams{
+		Owner: names.NewApplicationTag("postgresql"),
+		Data:  secrets.SecretData{"password": "s3cret"},
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SecretsSuite) TestGetSecretNotFound(c *gc.C) {
+	_, err := s.State.GetSecret(secrets.NewURI())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SecretsSuite) TestUpdateSecret(c *gc.C) {
+	uri := s.createSecret(c)
+	description := "new password"
+	md, err := s.State.UpdateSecret(uri, state.UpdateSecretParams{
+		Description: &description,
+		Data:        secrets.SecretData{"password": "n3w"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(md.LatestRevision, gc.Equals, 2)
+	c.Check(md.Description, gc.Equals, "new password")
+
+	data, err := s.State.GetSecretValue(uri, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(data, jc.DeepEquals, secrets.SecretData{"password": "s3cret"})
+	data, err = s.State.GetSecretValue(uri, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(data, jc.DeepEquals, secrets.SecretData{"password": "n3w"})
+}
+
+func (s *SecretsSuite) TestUpdateSecretRotatePolicy(c *gc.C) {
+	uri := s.createSecret(c)
+	policy := secrets.RotateHourly
+	md, err := s.State.UpdateSecret(uri, state.UpdateSecretParams{
+		RotatePolicy: &policy,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(md.LatestRevision, gc.Equals, 1)
+	c.Check(md.RotatePolicy, gc.Equals, secrets.RotateHourly)
+	c.Assert(md.NextRotateTime, gc.NotNil)
+	c.Check(md.NextRotateTime.Sub(md.UpdateTime), gc.Equals, time.Hour)
+}
+
+func (s *SecretsSuite) TestUpdateSecretEmpty(c *gc.C) {
+	uri := s.createSecret(c)
+	_, err := s.State.UpdateSecret(uri, state.UpdateSecretParams{})
+	c.Assert(err, gc.ErrorMatches, "empty secret update not valid")
+}
+
+func (s *SecretsSuite) TestUpdateSecretNotFound(c *gc.C) {
+	_, err := s.State.UpdateSecret(secrets.NewURI(), state.UpdateSecretParams{
+		Data: secrets.SecretData{"password": "n3w"},
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SecretsSuite) TestGetSecretValueNotFound(c *gc.C) {
+	uri := s.createSecret(c)
+	_, err := s.State.GetSecretValue(uri, 2)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SecretsSuite) TestGrantRevokeSecretAccess(c *gc.C) {
+	uri := s.createSecret(c)
+	subject := names.NewApplicationTag("wordpress")
+	granted, err := s.State.HasSecretAccess(uri, subject)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(granted, jc.IsFalse)
+
+	err = s.State.GrantSecretAccess(uri, subject)
+	c.Assert(err, jc.ErrorIsNil)
+	// Granting again is a no-op.
+	err = s.State.GrantSecretAccess(uri, subject)
+	c.Assert(err, jc.ErrorIsNil)
+	granted, err = s.State.HasSecretAccess(uri, subject)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(granted, jc.IsTrue)
+
+	err = s.State.RevokeSecretAccess(uri, subject)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.RevokeSecretAccess(uri, subject)
+	c.Assert(err, jc.ErrorIsNil)
+	granted, err = s.State.HasSecretAccess(uri, subject)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(granted, jc.IsFalse)
+}
+
+func (s *SecretsSuite) TestGrantSecretAccessNotFound(c *gc.C) {
+	err := s.State.GrantSecretAccess(secrets.NewURI(), s.consumer.Tag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	uri := s.createSecret(c)
+	err = s.State.GrantSecretAccess(uri, names.NewUnitTag("wordpress/9"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SecretsSuite) TestSecretConsumer(c *gc.C) {
+	uri := s.createSecret(c)
+	_, err := s.State.GetSecretConsumer(uri, s.consumer.Tag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.State.SaveSecretConsumer(uri, s.consumer.Tag(), 1)
+	c.Assert(err, jc.ErrorIsNil)
+	revision, err := s.State.GetSecretConsumer(uri, s.consumer.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(revision, gc.Equals, 1)
+
+	err = s.State.SaveSecretConsumer(uri, s.consumer.Tag(), 2)
+	c.Assert(err, jc.ErrorIsNil)
+	revision, err = s.State.GetSecretConsumer(uri, s.consumer.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(revision, gc.Equals, 2)
+}
+
+func (s *SecretsSuite) TestRemoveOwnerRemovesSecrets(c *gc.C) {
+	uri := s.createSecret(c)
+	err := s.State.GrantSecretAccess(uri, s.consumer.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SaveSecretConsumer(uri, s.consumer.Tag(), 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.owner.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.GetSecret(uri)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.GetSecretValue(uri, 1)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.GetSecretConsumer(uri, s.consumer.Tag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	granted, err := s.State.HasSecretAccess(uri, s.consumer.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(granted, jc.IsFalse)
+}
+
+func (s *SecretsSuite) TestRemoveConsumerRemovesAccess(c *gc.C) {
+	uri := s.createSecret(c)
+	err := s.State.GrantSecretAccess(uri, s.consumer.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.SaveSecretConsumer(uri, s.consumer.Tag(), 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.consumer.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.consumer.Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	granted, err := s.State.HasSecretAccess(uri, s.consumer.Tag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(granted, jc.IsFalse)
+	_, err = s.State.GetSecretConsumer(uri, s.consumer.Tag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.GetSecret(uri)
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -0,0 +1,102 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/secrets"
+)
+
+// secretKeysDir returns the directory holding the keys secret values
+// are encrypted with, or "" if the state has no data dir.
+func (st *State) secretKeysDir() string {
+	if st.dataDir == "" {
+		return ""
+	}
+	return secrets.KeysDir(st.dataDir)
+}
+
+// secretsKey returns the controller's secrets key. The key is kept in
+// a file on each controller machine, rather than in the database, so
+// that the values of secrets can't be read from a database dump or a
+// backup.
+func (st *State) secretsKey() ([]byte, error) {
+	key, err := secrets.ReadKey(st.secretKeysDir(), secrets.ControllerKey)
+	return key, errors.Annotate(err, "reading controller secrets key")
+}
+
+func (st *State) secretsCipher() (cipher.AEAD, error) {
+	key, err := st.secretsKey()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptSecretData encrypts the value of a revision of a secret with
+// AES-GCM. The secret and revision are authenticated with it, so a
+// revision can't be passed off as another.
+func (st *State) encryptSecretData(uri *secrets.URI, revision int, data secrets.SecretData) ([]byte, error) {
+	plaintext, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return st.encryptWithSecretsKey(plaintext, secretRevisionKey(uri, revision))
+}
+
+// decryptSecretData decrypts the value of a revision of a secret
+// encrypted by encryptSecretData.
+func (st *State) decryptSecretData(uri *secrets.URI, revision int, ciphertext []byte) (secrets.SecretData, error) {
+	plaintext, err := st.decryptWithSecretsKey(ciphertext, secretRevisionKey(uri, revision))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var data secrets.SecretData
+	if err := json.Unmarshal(plaintext, &data); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return data, nil
+}
+
+// encryptWithSecretsKey encrypts plaintext with AES-GCM using the
+// controller's secrets key. The nonce is prepended to the result, and
+// the given name is authenticated with it, so that the result can only
+// be decrypted under the same name.
+func (st *State) encryptWithSecretsKey(plaintext []byte, name string) ([]byte, error) {
+	aead, err := st.secretsCipher()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, []byte(name)), nil
+}
+
+// decryptWithSecretsKey decrypts a value encrypted by
+// encryptWithSecretsKey under the same name.
+func (st *State) decryptWithSecretsKey(ciphertext []byte, name string) ([]byte, error) {
+	aead, err := st.secretsCipher()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("encrypted value too short")
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(name))
+	return plaintext, errors.Trace(err)
+}
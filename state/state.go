@@ -40,6 +40,7 @@ import (
 	"github.com/juju/juju/core/network"
 	corenetwork "github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/permission"
+	"github.com/juju/juju/core/quota"
 	"github.com/juju/juju/core/raftlease"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/mongo"
@@ -78,6 +79,10 @@ type State struct {
 	newPolicy              NewPolicyFunc
 	runTransactionObserver RunTransactionObserverFunc
 
+	// dataDir is the data dir of the controller machine, which holds
+	// the keys secret values are encrypted with.
+	dataDir string
+
 	// workers is responsible for keeping the various sub-workers
 	// available by starting new ones as they fail. It doesn't do
 	// that yet, but having a type that collects them together is the
@@ -97,6 +102,7 @@ func (st *State) newStateNoWorkers(modelUUID string) (*State, error) {
 		st.newPolicy,
 		st.stateClock,
 		st.runTransactionObserver,
+		st.dataDir,
 	)
 	// We explicitly don't start the workers.
 	if err != nil {
@@ -312,6 +318,10 @@ func (st *State) removeAllModelDocs(modelAssertion bson.D) error {
 		C:      modelEntityRefsC,
 		Id:     modelUUID,
 		Remove: true,
+	}, {
+		C:      quotasC,
+		Id:     modelKey(modelUUID),
+		Remove: true,
 	}, {
 		C:      modelsC,
 		Id:     modelUUID,
@@ -1093,6 +1103,19 @@ func (st *State) AddApplication(args AddApplicationArgs) (_ *Application, err er
 	if err := validateStorageConstraints(sb, args.Storage, args.Charm.Meta()); err != nil {
 		return nil, errors.Trace(err)
 	}
+	// The units' storage is otherwise only checked against the quotas
+	// one unit at a time.
+	var unitStorageSize uint64
+	for name, cons := range args.Storage {
+		if charmStorage, ok := args.Charm.Meta().Storage[name]; ok && !charmStorage.Shared {
+			unitStorageSize += cons.Size * cons.Count
+		}
+	}
+	addUsage := quota.Usage{
+		quota.Applications: 1,
+		quota.Units:        uint64(args.NumUnits),
+		quota.Storage:      unitStorageSize * uint64(args.NumUnits),
+	}
 	storagePools := make(set.Strings)
 	for _, storageParams := range args.Storage {
 		storagePools.Add(storageParams.Pool)
@@ -1224,6 +1247,11 @@ func (st *State) AddApplication(args AddApplicationArgs) (_ *Application, err er
 			assertModelActiveOp(st.ModelUUID()),
 			endpointBindingsOp,
 		}
+		quotaOps, err := st.quotaOps(addUsage)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, quotaOps...)
 		addOps, err := addApplicationOps(st, app, addApplicationOpsArgs{
 			applicationDoc:    appDoc,
 			statusDoc:         statusDoc,
@@ -503,7 +503,7 @@ func eraseStatusHistory(mb modelBackend, globalKey string) error {
 	deleted, err := deleteInBatches(
 		history.Writeable().Underlying(), nil, "", iter,
 		logFormat, loggo.DEBUG,
-		noEarlyFinish,
+		noEarlyFinish, nil,
 	)
 	if err != nil {
 		return errors.Trace(err)
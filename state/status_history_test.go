@@ -327,3 +327,68 @@ func (s *StatusHistorySuite) TestSameValueNotRepeated(c *gc.C) {
 	c.Assert(history[0].Message, gc.Equals, "current status")
 	c.Assert(history[1].Message, gc.Equals, "waiting for machine")
 }
+
+func (s *StatusHistorySuite) TestStatusTransitions(c *gc.C) {
+	application := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: application})
+	machine := s.Factory.MakeMachine(c, nil)
+
+	// Keep well clear of the statuses recorded as the entities were made.
+	start := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	at := func(d time.Duration) *time.Time {
+		t := start.Add(d)
+		return &t
+	}
+	err := unit.SetStatus(status.StatusInfo{Status: status.Maintenance, Message: "upgrading", Since: at(0)})
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetStatus(status.StatusInfo{Status: status.Error, Message: "disk full", Since: at(time.Minute)})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetStatus(status.StatusInfo{Status: status.Blocked, Message: "missing relation", Since: at(2 * time.Minute)})
+	c.Assert(err, jc.ErrorIsNil)
+	err = application.SetStatus(status.StatusInfo{Status: status.Blocked, Message: "missing relation", Since: at(3 * time.Minute)})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetStatus(status.StatusInfo{Status: status.Active, Since: at(time.Hour + time.Minute)})
+	c.Assert(err, jc.ErrorIsNil)
+
+	transitions, truncated, err := s.Model.StatusTransitions(state.StatusTransitionFilter{
+		From: start,
+		To:   start.Add(time.Hour),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(truncated, jc.IsFalse)
+	c.Assert(transitions, gc.HasLen, 4)
+	c.Check(transitions[0].Tag, gc.Equals, unit.Tag())
+	c.Check(transitions[0].Kind, gc.Equals, status.KindWorkload)
+	c.Check(transitions[0].Message, gc.Equals, "upgrading")
+	c.Check(transitions[1].Tag, gc.Equals, machine.Tag())
+	c.Check(transitions[1].Kind, gc.Equals, status.KindMachine)
+	c.Check(transitions[2].Status, gc.Equals, status.Blocked)
+	c.Check(transitions[3].Tag, gc.Equals, application.Tag())
+	c.Check(transitions[3].Kind, gc.Equals, status.KindApplication)
+
+	transitions, truncated, err = s.Model.StatusTransitions(state.StatusTransitionFilter{
+		From:     start,
+		Statuses: []status.Status{status.Blocked},
+		Kinds:    []status.HistoryKind{status.KindUnit},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(transitions, gc.HasLen, 1)
+	c.Check(transitions[0].Message, gc.Equals, "missing relation")
+	c.Check(transitions[0].Tag, gc.Equals, unit.Tag())
+
+	transitions, truncated, err = s.Model.StatusTransitions(state.StatusTransitionFilter{
+		From:  start,
+		Limit: 2,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(truncated, jc.IsTrue)
+	c.Assert(transitions, gc.HasLen, 2)
+}
+
+func (s *StatusHistorySuite) TestStatusTransitionsInvalidFilter(c *gc.C) {
+	now := time.Now()
+	_, _, err := s.Model.StatusTransitions(state.StatusTransitionFilter{From: now, To: now.Add(-time.Minute)})
+	c.Assert(err, gc.ErrorMatches, "time window ending before it starts not valid")
+	_, _, err = s.Model.StatusTransitions(state.StatusTransitionFilter{Kinds: []status.HistoryKind{"bad"}})
+	c.Assert(err, gc.ErrorMatches, `status history kind "bad" not valid`)
+}
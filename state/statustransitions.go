@@ -0,0 +1,192 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/mongo/utils"
+)
+
+// defaultStatusTransitionsLimit is the maximum number of status
+// transitions returned by a query if no limit is specified.
+const defaultStatusTransitionsLimit = 1000
+
+// statusHistoryKeyPatterns hold the patterns of the global keys under
+// which the status history of each kind of entity is recorded.
+var statusHistoryKeyPatterns = []struct {
+	kind    status.HistoryKind
+	pattern string
+}{
+	{status.KindWorkload, `u#[^#]+#charm`},
+	{status.KindUnitAgent, `u#[^#]+`},
+	{status.KindMachine, `m#\d+`},
+	{status.KindMachineInstance, `m#\d+#instance`},
+	{status.KindContainer, `m#\d+(/[a-z]+/\d+)+`},
+	{status.KindContainerInstance, `m#\d+(/[a-z]+/\d+)+#instance`},
+	{status.KindApplication, `a#[^#]+`},
+}
+
+var statusHistoryKeyRegexps = func() map[status.HistoryKind]*regexp.Regexp {
+	result := make(map[status.HistoryKind]*regexp.Regexp)
+	for _, p := range statusHistoryKeyPatterns {
+		result[p.kind] = regexp.MustCompile("^" + p.pattern + "$")
+	}
+	return result
+}()
+
+// StatusTransitionFilter holds the arguments used to select the status
+// transitions of the entities in a model.
+type StatusTransitionFilter struct {
+	// From and To bound the window the transitions happened in. A
+	// zero time leaves that end of the window open; From is
+	// inclusive and To exclusive.
+	From time.Time
+	To   time.Time
+
+	// Kinds, if not empty, restricts the transitions to those of
+	// the given kinds of status.
+	Kinds []status.HistoryKind
+
+	// Statuses, if not empty, restricts the transitions to those
+	// into the given status values.
+	Statuses []status.Status
+
+	// Offset and Limit are used to page through the transitions.
+	Offset int
+	Limit  int
+}
+
+// Validate checks that the filter is valid.
+func (f StatusTransitionFilter) Validate() error {
+	if !f.From.IsZero() && !f.To.IsZero() && !f.From.Before(f.To) {
+		return errors.NotValidf("time window ending before it starts")
+	}
+	for _, kind := range f.Kinds {
+		if !kind.Valid() {
+			return errors.NotValidf("status history kind %q", kind)
+		}
+	}
+	if f.Offset < 0 || f.Limit < 0 {
+		return errors.NotValidf("negative offset or limit")
+	}
+	return nil
+}
+
+// StatusTransition records a change in status of an entity in a model.
+type StatusTransition struct {
+	status.StatusInfo
+
+	// Tag identifies the entity whose status changed.
+	Tag names.Tag
+
+	// Kind is the kind of status which changed.
+	Kind status.HistoryKind
+}
+
+// statusHistoryEntity returns the entity and kind of status whose
+// history is recorded under the given global key.
+func statusHistoryEntity(globalKey string) (names.Tag, status.HistoryKind, bool) {
+	for _, p := range statusHistoryKeyPatterns {
+		if !statusHistoryKeyRegexps[p.kind].MatchString(globalKey) {
+			continue
+		}
+		id := strings.TrimSuffix(strings.TrimSuffix(globalKey[2:], "#charm"), "#instance")
+		switch globalKey[0] {
+		case 'u':
+			return names.NewUnitTag(id), p.kind, true
+		case 'm':
+			return names.NewMachineTag(id), p.kind, true
+		case 'a':
+			return names.NewApplicationTag(id), p.kind, true
+		}
+	}
+	return nil, "", false
+}
+
+// StatusTransitions returns the changes in status of the units,
+// machines and applications in the model which match the filter,
+// oldest first. The second result reports whether more transitions
+// match than the limit.
+func (m *Model) StatusTransitions(filter StatusTransitionFilter) ([]StatusTransition, bool, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	history, closer := m.st.db().GetCollection(statusesHistoryC)
+	defer closer()
+
+	kinds := set.NewStrings()
+	for _, kind := range filter.Kinds {
+		if kind == status.KindUnit {
+			kinds.Add(string(status.KindWorkload))
+			kinds.Add(string(status.KindUnitAgent))
+			continue
+		}
+		kinds.Add(string(kind))
+	}
+	var patterns []string
+	for _, p := range statusHistoryKeyPatterns {
+		if kinds.IsEmpty() || kinds.Contains(string(p.kind)) {
+			patterns = append(patterns, p.pattern)
+		}
+	}
+	query := bson.D{{
+		globalKeyField, bson.RegEx{Pattern: "^(?:" + strings.Join(patterns, "|") + ")$"},
+	}}
+	var updated bson.D
+	if !filter.From.IsZero() {
+		updated = append(updated, bson.DocElem{"$gte", filter.From.UnixNano()})
+	}
+	if !filter.To.IsZero() {
+		updated = append(updated, bson.DocElem{"$lt", filter.To.UnixNano()})
+	}
+	if len(updated) > 0 {
+		query = append(query, bson.DocElem{"updated", updated})
+	}
+	if len(filter.Statuses) > 0 {
+		query = append(query, bson.DocElem{"status", bson.D{{"$in", filter.Statuses}}})
+	}
+
+	limit := filter.Limit
+	if limit == 0 {
+		limit = defaultStatusTransitionsLimit
+	}
+	// Fetch one more than the limit to see if there are more.
+	var docs []historicalStatusDoc
+	err := history.Find(query).Sort("updated", "_id").Skip(filter.Offset).Limit(limit + 1).All(&docs)
+	if err != nil {
+		return nil, false, errors.Annotate(err, "cannot get status history")
+	}
+	truncated := len(docs) > limit
+	if truncated {
+		docs = docs[:limit]
+	}
+
+	result := make([]StatusTransition, 0, len(docs))
+	for _, doc := range docs {
+		tag, kind, ok := statusHistoryEntity(doc.GlobalKey)
+		if !ok {
+			continue
+		}
+		result = append(result, StatusTransition{
+			StatusInfo: status.StatusInfo{
+				Status:  doc.Status,
+				Message: doc.StatusInfo,
+				Data:    utils.UnescapeKeys(doc.StatusData),
+				Since:   unixNanoToTime(doc.Updated),
+			},
+			Tag:  tag,
+			Kind: kind,
+		})
+	}
+	return result, truncated, nil
+}
@@ -20,6 +20,7 @@ import (
 
 	k8sprovider "github.com/juju/juju/caas/kubernetes/provider"
 	k8sconstants "github.com/juju/juju/caas/kubernetes/provider/constants"
+	"github.com/juju/juju/core/quota"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/storage"
 	"github.com/juju/juju/storage/poolmanager"
@@ -97,6 +98,7 @@ func NewStorageBackend(st *State) (*storageBackend, error) {
 		settings:        NewStateSettings(st),
 		modelType:       m.Type(),
 		config:          m.ModelConfig,
+		quotaOps:        st.quotaOps,
 		application:     st.Application,
 		allApplications: st.AllApplications,
 		unit:            st.Unit,
@@ -111,6 +113,7 @@ func NewStorageBackend(st *State) (*storageBackend, error) {
 type storageBackend struct {
 	mb              modelBackend
 	config          func() (*config.Config, error)
+	quotaOps        func(quota.Usage) ([]txn.Op, error)
 	application     func(string) (*Application, error)
 	allApplications func() ([]*Application, error)
 	unit            func(string) (*Unit, error)
@@ -806,8 +809,20 @@ func createStorageOps(
 		})
 	}
 
+	var size uint64
+	for _, t := range templates {
+		size += t.cons.Size * t.cons.Count
+	}
+	var quotaOps []txn.Op
+	if size > 0 {
+		if quotaOps, err = sb.quotaOps(quota.Usage{quota.Storage: size}); err != nil {
+			return fail(errors.Trace(err))
+		}
+	}
+
 	storageTags = make(map[string][]names.StorageTag)
-	ops = make([]txn.Op, 0, len(templates)*3)
+	ops = make([]txn.Op, 0, len(templates)*3+len(quotaOps))
+	ops = append(ops, quotaOps...)
 	for _, t := range templates {
 		owner := entityTag.String()
 		var kind StorageKind
@@ -11,6 +11,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/core/secrets"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/state"
@@ -66,6 +67,9 @@ func InitializeWithArgs(c *gc.C, args InitializeArgs) *state.Controller {
 	for k, v := range args.ControllerConfig {
 		controllerCfg[k] = v
 	}
+	dataDir := c.MkDir()
+	err = secrets.EnsureKeys(secrets.KeysDir(dataDir))
+	c.Assert(err, jc.ErrorIsNil)
 	ctlr, err := state.Initialize(state.InitializeParams{
 		Clock:            args.Clock,
 		ControllerConfig: controllerCfg,
@@ -116,6 +120,7 @@ func InitializeWithArgs(c *gc.C, args InitializeArgs) *state.Controller {
 		MongoSession:  session,
 		NewPolicy:     args.NewPolicy,
 		AdminPassword: args.AdminPassword,
+		DataDir:       dataDir,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	return ctlr
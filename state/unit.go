@@ -2688,6 +2688,20 @@ type ActionSpecsByName map[string]charm.ActionSpec
 // this Unit, and returns its ID.  Note that the use of spec.InsertDefaults
 // mutates payload.
 func (u *Unit) AddAction(operationID, name string, payload map[string]interface{}) (Action, error) {
+	payloadWithDefaults, err := u.prepareActionPayload(name, payload)
+	if err != nil {
+		return nil, err
+	}
+	m, err := u.st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return m.EnqueueAction(operationID, u.Tag(), name, payloadWithDefaults)
+}
+
+// prepareActionPayload validates the named action and its payload
+// against the unit's charm, returning the payload with defaults filled in.
+func (u *Unit) prepareActionPayload(name string, payload map[string]interface{}) (map[string]interface{}, error) {
 	if len(name) == 0 {
 		return nil, errors.New("no action name given")
 	}
@@ -2728,12 +2742,7 @@ func (u *Unit) AddAction(operationID, name string, payload map[string]interface{
 			payloadWithDefaults["workload-context"] = false
 		}
 	}
-
-	m, err := u.st.Model()
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-	return m.EnqueueAction(operationID, u.Tag(), name, payloadWithDefaults)
+	return payloadWithDefaults, nil
 }
 
 // ActionSpecs gets the ActionSpec map for the Unit's charm.
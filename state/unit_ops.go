@@ -134,6 +134,10 @@ func (op *unitSetStateOperation) newUnitStateDoc(unitGlobalKey string) (unitStat
 		newStDoc.MeterStatusState = meterStatusState
 		quotaChecker.Check(meterStatusState)
 	}
+	if hookTraces, found := op.newState.HookTraces(); found {
+		newStDoc.HookTraces = hookTraces
+		quotaChecker.Check(hookTraces)
+	}
 	if err := quotaChecker.Outcome(); err != nil {
 		return unitStateDoc{}, errors.Annotatef(err, "persisting uniter state")
 	}
@@ -218,6 +222,15 @@ func (op *unitSetStateOperation) fields(currentDoc unitStateDoc) (bson.D, bson.D
 		}
 	}
 
+	if hookTraces, found := op.newState.HookTraces(); found {
+		if hookTraces == "" {
+			unsetFields = append(unsetFields, bson.DocElem{Name: "hook-traces"})
+		} else if hookTraces != currentDoc.HookTraces {
+			setFields = append(setFields, bson.DocElem{"hook-traces", hookTraces})
+			quotaChecker.Check(hookTraces)
+		}
+	}
+
 	if err := quotaChecker.Outcome(); err != nil {
 		if errors.IsQuotaLimitExceeded(err) {
 			return nil, nil, errors.Annotatef(err, "persisting internal uniter state")
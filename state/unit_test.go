@@ -329,6 +329,32 @@ func (s *UnitSuite) TestUnitStateMutateMeterStatusState(c *gc.C) {
 	assertUnitStateStorageState(c, uState, initState.storageState)
 }
 
+func (s *UnitSuite) TestUnitStateMutateHookTraces(c *gc.C) {
+	// Set initial state; this should create a new unitstate doc
+	initState := s.testUnitSuite(c)
+
+	// Set hook traces with an existing state doc
+	newHookTraces := "- operation: run install hook\n"
+	newUS := state.NewUnitState()
+	newUS.SetHookTraces(newHookTraces)
+	err := s.unit.SetState(newUS, state.UnitStateSizeLimits{})
+	c.Assert(err, gc.IsNil)
+
+	// Ensure hook traces changed
+	uState, err := s.unit.State()
+	c.Assert(err, gc.IsNil)
+	obtained, found := uState.HookTraces()
+	c.Assert(found, jc.IsTrue)
+	c.Assert(obtained, gc.Equals, newHookTraces)
+
+	// Ensure the other state did not.
+	assertUnitStateCharmState(c, uState, initState.charmState)
+	assertUnitStateUniterState(c, uState, initState.uniterState)
+	assertUnitStateRelationState(c, uState, initState.relationState)
+	assertUnitStateStorageState(c, uState, initState.storageState)
+	assertUnitStateMeterStatusState(c, uState, initState.meterStatusState)
+}
+
 func (s *UnitSuite) TestUnitStateDeleteState(c *gc.C) {
 	// Set initial state; this should create a new unitstate doc
 	initState := s.testUnitSuite(c)
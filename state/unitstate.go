@@ -40,6 +40,10 @@ type unitStateDoc struct {
 	// MeterStatusState is a serialized yaml string containing the internal
 	// state for this unit's meter status worker.
 	MeterStatusState string `bson:"meter-status-state,omitempty"`
+
+	// HookTraces is a serialized yaml string containing the recent
+	// hook traces recorded by the uniter for this unit.
+	HookTraces string `bson:"hook-traces,omitempty"`
 }
 
 // charmStateMatches returns true if the State map within the unitStateDoc matches
@@ -129,6 +133,11 @@ type UnitState struct {
 	// state for the meter status worker for this unit.
 	meterStatusState    string
 	meterStatusStateSet bool
+
+	// hookTraces is a serialized yaml string containing the recent hook
+	// traces recorded by the uniter for this unit.
+	hookTraces    string
+	hookTracesSet bool
 }
 
 // NewUnitState returns a new UnitState struct.
@@ -142,7 +151,8 @@ func (u *UnitState) Modified() bool {
 		u.storageStateSet ||
 		u.charmStateSet ||
 		u.uniterStateSet ||
-		u.meterStatusStateSet
+		u.meterStatusStateSet ||
+		u.hookTracesSet
 }
 
 // SetCharmState sets the charm state value.
@@ -215,6 +225,18 @@ func (u *UnitState) MeterStatusState() (string, bool) {
 	return u.meterStatusState, u.meterStatusStateSet
 }
 
+// SetHookTraces sets the hook traces value.
+func (u *UnitState) SetHookTraces(traces string) {
+	u.hookTracesSet = true
+	u.hookTraces = traces
+}
+
+// HookTraces returns the recent hook traces and a bool to indicate
+// whether the data was set.
+func (u *UnitState) HookTraces() (string, bool) {
+	return u.hookTraces, u.hookTracesSet
+}
+
 // SetState replaces the currently stored state for a unit with the contents
 // of the provided UnitState.
 //
@@ -273,6 +295,7 @@ func (u *Unit) State() (*UnitState, error) {
 	us.SetUniterState(stDoc.UniterState)
 	us.SetStorageState(stDoc.StorageState)
 	us.SetMeterStatusState(stDoc.MeterStatusState)
+	us.SetHookTraces(stDoc.HookTraces)
 
 	return us, nil
 }
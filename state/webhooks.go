@@ -0,0 +1,133 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/webhook"
+)
+
+// Webhook describes a webhook registered to be notified of events in
+// the model.
+type Webhook struct {
+	webhook.Webhook
+
+	CreatedBy string
+	Created   time.Time
+}
+
+type webhookDoc struct {
+	DocId     string    `bson:"_id"`
+	ModelUUID string    `bson:"model-uuid"`
+	URL       string    `bson:"url"`
+	Events    []string  `bson:"events,omitempty"`
+	Secret    string    `bson:"secret,omitempty"`
+	Template  string    `bson:"template,omitempty"`
+	CreatedBy string    `bson:"created-by"`
+	Created   time.Time `bson:"created"`
+}
+
+func (m *Model) webhook(doc webhookDoc) Webhook {
+	events := make([]webhook.EventKind, len(doc.Events))
+	for i, event := range doc.Events {
+		events[i] = webhook.EventKind(event)
+	}
+	return Webhook{
+		Webhook: webhook.Webhook{
+			Id:       m.st.localID(doc.DocId),
+			URL:      doc.URL,
+			Events:   events,
+			Secret:   doc.Secret,
+			Template: doc.Template,
+		},
+		CreatedBy: doc.CreatedBy,
+		Created:   doc.Created,
+	}
+}
+
+// AddWebhook registers a webhook to be notified of events in the model.
+// The Id of the webhook is ignored; the added webhook, with its Id, is
+// returned.
+func (m *Model) AddWebhook(hook webhook.Webhook, createdBy string) (Webhook, error) {
+	if err := hook.Validate(); err != nil {
+		return Webhook{}, errors.Trace(err)
+	}
+	seq, err := sequence(m.st, "webhook")
+	if err != nil {
+		return Webhook{}, errors.Trace(err)
+	}
+	doc := webhookDoc{
+		DocId:     m.st.docID(strconv.Itoa(seq)),
+		ModelUUID: m.st.ModelUUID(),
+		URL:       hook.URL,
+		Secret:    hook.Secret,
+		Template:  hook.Template,
+		CreatedBy: createdBy,
+		Created:   m.st.clock().Now().UTC().Round(time.Second),
+	}
+	for _, event := range hook.Events {
+		doc.Events = append(doc.Events, string(event))
+	}
+	ops := []txn.Op{{
+		C:      modelsC,
+		Id:     m.st.ModelUUID(),
+		Assert: isAliveDoc,
+	}, {
+		C:      webhooksC,
+		Id:     doc.DocId,
+		Assert: txn.DocMissing,
+		Insert: &doc,
+	}}
+	if err := m.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return Webhook{}, errors.Errorf("model %q is no longer alive", m.Name())
+	} else if err != nil {
+		return Webhook{}, errors.Annotate(err, "adding webhook")
+	}
+	return m.webhook(doc), nil
+}
+
+// Webhooks returns the webhooks registered in the model, in the order
+// they were added.
+func (m *Model) Webhooks() ([]Webhook, error) {
+	coll, closer := m.st.db().GetCollection(webhooksC)
+	defer closer()
+
+	var docs []webhookDoc
+	if err := coll.Find(nil).All(&docs); err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Webhook, len(docs))
+	for i, doc := range docs {
+		result[i] = m.webhook(doc)
+	}
+	// Webhook ids are sequential, so sort them numerically.
+	sort.Slice(result, func(i, j int) bool {
+		a, _ := strconv.Atoi(result[i].Id)
+		b, _ := strconv.Atoi(result[j].Id)
+		return a < b
+	})
+	return result, nil
+}
+
+// RemoveWebhook removes the webhook with the given id from the model.
+func (m *Model) RemoveWebhook(id string) error {
+	ops := []txn.Op{{
+		C:      webhooksC,
+		Id:     m.st.docID(id),
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	if err := m.st.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("webhook %q", id)
+	} else if err != nil {
+		return errors.Annotatef(err, "removing webhook %q", id)
+	}
+	return nil
+}
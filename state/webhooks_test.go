@@ -0,0 +1,89 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/webhook"
+	"github.com/juju/juju/state"
+)
+
+type WebhooksSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&WebhooksSuite{})
+
+func (s *WebhooksSuite) TestAddWebhook(c *gc.C) {
+	added, err := s.Model.AddWebhook(webhook.Webhook{
+		URL:    "https://chat.example.com/hooks/1",
+		Events: []webhook.EventKind{webhook.UnitError, webhook.MachineDown},
+		Secret: "s3cret",
+	}, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(added.Id, gc.Not(gc.Equals), "")
+	c.Check(added.CreatedBy, gc.Equals, "admin")
+	c.Check(added.Created.IsZero(), jc.IsFalse)
+
+	hooks, err := s.Model.Webhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hooks, jc.DeepEquals, []state.Webhook{added})
+}
+
+func (s *WebhooksSuite) TestAddWebhookInvalid(c *gc.C) {
+	_, err := s.Model.AddWebhook(webhook.Webhook{
+		URL:    "https://chat.example.com/hooks/1",
+		Events: []webhook.EventKind{"unit-happy"},
+	}, "admin")
+	c.Assert(err, gc.ErrorMatches, `webhook event "unit-happy" not valid`)
+
+	hooks, err := s.Model.Webhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hooks, gc.HasLen, 0)
+}
+
+func (s *WebhooksSuite) TestWebhooksOrdered(c *gc.C) {
+	var ids []string
+	for i := 0; i < 11; i++ {
+		added, err := s.Model.AddWebhook(webhook.Webhook{URL: "http://example.com"}, "admin")
+		c.Assert(err, jc.ErrorIsNil)
+		ids = append(ids, added.Id)
+	}
+	hooks, err := s.Model.Webhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hooks, gc.HasLen, 11)
+	for i, hook := range hooks {
+		c.Check(hook.Id, gc.Equals, ids[i])
+	}
+}
+
+func (s *WebhooksSuite) TestWebhooksPerModel(c *gc.C) {
+	_, err := s.Model.AddWebhook(webhook.Webhook{URL: "http://example.com"}, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+
+	otherState := s.Factory.MakeModel(c, nil)
+	defer otherState.Close()
+	otherModel, err := otherState.Model()
+	c.Assert(err, jc.ErrorIsNil)
+	hooks, err := otherModel.Webhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hooks, gc.HasLen, 0)
+}
+
+func (s *WebhooksSuite) TestRemoveWebhook(c *gc.C) {
+	added, err := s.Model.AddWebhook(webhook.Webhook{URL: "http://example.com"}, "admin")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.Model.RemoveWebhook(added.Id)
+	c.Assert(err, jc.ErrorIsNil)
+	hooks, err := s.Model.Webhooks()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hooks, gc.HasLen, 0)
+
+	err = s.Model.RemoveWebhook(added.Id)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -12,6 +12,7 @@ import (
 	coreagent "github.com/juju/juju/agent"
 	apiagent "github.com/juju/juju/api/agent"
 	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/core/secrets"
 	"github.com/juju/juju/mongo"
 	jworker "github.com/juju/juju/worker"
 )
@@ -107,6 +108,22 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 			if err != nil {
 				return nil, errors.Annotate(err, "getting state serving info")
 			}
+
+			// The secrets keys are written before the state serving
+			// info, so that a machine becoming a controller has them
+			// before it starts serving.
+			keys, err := apiState.ControllerSecretKeys()
+			if errors.IsNotSupported(err) {
+				logger.Warningf("controller can't send secrets keys: %v", err)
+			} else if err != nil {
+				return nil, errors.Annotate(err, "getting secrets keys")
+			} else {
+				keysDir := secrets.KeysDir(agent.CurrentConfig().DataDir())
+				if err := secrets.WriteKeys(keysDir, keys); err != nil {
+					return nil, errors.Trace(err)
+				}
+			}
+
 			err = agent.ChangeConfig(func(config coreagent.ConfigSetter) error {
 				existing, hasInfo := config.StateServingInfo()
 				if hasInfo {
@@ -4,6 +4,8 @@
 package agentconfigupdater_test
 
 import (
+	"bytes"
+
 	"github.com/juju/loggo"
 	"github.com/juju/names/v4"
 	"github.com/juju/pubsub"
@@ -19,6 +21,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/core/secrets"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/testing"
 	jworker "github.com/juju/juju/worker"
@@ -201,11 +204,19 @@ func (s *AgentConfigUpdaterSuite) TestCentralHubMissingFirstPass(c *gc.C) {
 	c.Check(err, gc.Equals, jworker.ErrRestartAgent)
 }
 
-func (s *AgentConfigUpdaterSuite) startManifold(c *gc.C, a agent.Agent, mockAPIPort int) (worker.Worker, error) {
+func (s *AgentConfigUpdaterSuite) startManifold(c *gc.C, a *mockAgent, mockAPIPort int) (worker.Worker, error) {
+	if a.conf.dataDir == "" {
+		a.conf.dataDir = c.MkDir()
+	}
 	apiCaller := basetesting.APICallerFunc(
 		func(objType string, version int, id, request string, args, response interface{}) error {
 			c.Assert(objType, gc.Equals, "Agent")
 			switch request {
+			case "ControllerSecretKeys":
+				result := response.(*params.SecretKeysResult)
+				*result = params.SecretKeysResult{
+					Keys: map[string][]byte{secrets.ControllerKey: testSecretsKey},
+				}
 			case "GetEntities":
 				c.Assert(args.(params.Entities).Entities, gc.HasLen, 1)
 				result := response.(*params.AgentGetEntitiesResults)
@@ -235,12 +246,14 @@ func (s *AgentConfigUpdaterSuite) startManifold(c *gc.C, a agent.Agent, mockAPIP
 	)
 	context := dt.StubContext(nil, map[string]interface{}{
 		"agent":       a,
-		"api-caller":  apiCaller,
+		"api-caller":  basetesting.BestVersionCaller{APICallerFunc: apiCaller, BestVersion: 3},
 		"central-hub": s.hub,
 	})
 	return s.manifold.Start(context)
 }
 
+var testSecretsKey = bytes.Repeat([]byte{7}, secrets.KeySize)
+
 func (s *AgentConfigUpdaterSuite) TestJobManageEnviron(c *gc.C) {
 	// State serving info should be set for machines with JobManageEnviron.
 	const mockAPIPort = 1234
@@ -257,6 +270,11 @@ func (s *AgentConfigUpdaterSuite) TestJobManageEnviron(c *gc.C) {
 	c.Assert(a.conf.ssi.APIPort, gc.Equals, mockAPIPort)
 	c.Assert(a.conf.ssi.Cert, gc.Equals, "cert")
 	c.Assert(a.conf.ssi.PrivateKey, gc.Equals, "key")
+
+	// The secrets keys were written to the data dir.
+	key, err := secrets.ReadKey(secrets.KeysDir(a.conf.dataDir), secrets.ControllerKey)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(key, jc.DeepEquals, testSecretsKey)
 }
 
 func (s *AgentConfigUpdaterSuite) TestProfileDifferenceRestarts(c *gc.C) {
@@ -345,9 +363,10 @@ func (ma *mockAgent) ChangeConfig(f agent.ConfigMutator) error {
 
 type mockConfig struct {
 	agent.ConfigSetter
-	tag    names.Tag
-	ssiSet bool
-	ssi    controller.StateServingInfo
+	tag     names.Tag
+	dataDir string
+	ssiSet  bool
+	ssi     controller.StateServingInfo
 
 	profile    string
 	profileSet bool
@@ -412,6 +431,10 @@ func (mc *mockConfig) SetNonSyncedWritesToRaftLog(nonSynced bool) {
 	mc.nonSyncedWritesToRaftLogSet = true
 }
 
+func (mc *mockConfig) DataDir() string {
+	return mc.dataDir
+}
+
 func (mc *mockConfig) LogDir() string {
 	return "log-dir"
 }
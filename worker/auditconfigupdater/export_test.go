@@ -0,0 +1,20 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditconfigupdater
+
+import (
+	"github.com/juju/juju/core/auditlog"
+	"github.com/juju/juju/logfwd/httpfwd"
+	"github.com/juju/juju/logfwd/syslog"
+)
+
+var NewTarget = newTarget
+
+func NewSyslogSender(cfg syslog.RawConfig, open func(syslog.RawConfig) (*syslog.Client, error)) auditlog.Sender {
+	return newSyslogSender(cfg, open)
+}
+
+func NewHTTPSender(cfg httpfwd.RawConfig) (auditlog.Sender, error) {
+	return newHTTPSender(cfg)
+}
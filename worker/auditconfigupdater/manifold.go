@@ -4,11 +4,13 @@
 package auditconfigupdater
 
 import (
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/worker/v2"
 	"github.com/juju/worker/v2/dependency"
 
 	jujuagent "github.com/juju/juju/agent"
+	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/auditlog"
 	"github.com/juju/juju/worker/common"
 	workerstate "github.com/juju/juju/worker/state"
@@ -19,6 +21,7 @@ import (
 type ManifoldConfig struct {
 	AgentName string
 	StateName string
+	Clock     clock.Clock
 	NewWorker func(ConfigSource, auditlog.Config, AuditLogFactory) (worker.Worker, error)
 }
 
@@ -30,6 +33,9 @@ func (config ManifoldConfig) Validate() error {
 	if config.StateName == "" {
 		return errors.NotValidf("empty StateName")
 	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
 	if config.NewWorker == nil {
 		return errors.NotValidf("nil NewWorker")
 	}
@@ -77,19 +83,27 @@ func (config ManifoldConfig) start(context dependency.Context) (_ worker.Worker,
 
 	st := statePool.SystemState()
 
-	logFactory := func(cfg auditlog.Config) auditlog.AuditLog {
-		return auditlog.NewLogFile(logDir, cfg.MaxSizeMB, cfg.MaxBackups)
-	}
-	auditConfig, err := initialConfig(st)
+	controllerConfig, err := st.ControllerConfig()
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	targetCfg := targetConfig(controllerConfig, logDir, config.Clock)
+	logFactory := func(cfg auditlog.Config) (auditlog.AuditLog, error) {
+		return newTarget(cfg, targetCfg)
+	}
+	auditConfig := initialConfig(controllerConfig)
 	if auditConfig.Enabled {
-		auditConfig.Target = logFactory(auditConfig)
+		auditConfig.Target, err = logFactory(auditConfig)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 
 	w, err := config.NewWorker(st, auditConfig, logFactory)
 	if err != nil {
+		if auditConfig.Target != nil {
+			_ = auditConfig.Target.Close()
+		}
 		return nil, errors.Trace(err)
 	}
 	return common.NewCleanupWorker(w, func() { stTracker.Done() }), nil
@@ -115,17 +129,12 @@ func output(in worker.Worker, out interface{}) error {
 	return nil
 }
 
-func initialConfig(source ConfigSource) (auditlog.Config, error) {
-	cfg, err := source.ControllerConfig()
-	if err != nil {
-		return auditlog.Config{}, errors.Trace(err)
-	}
-	result := auditlog.Config{
+func initialConfig(cfg controller.Config) auditlog.Config {
+	return auditlog.Config{
 		Enabled:        cfg.AuditingEnabled(),
 		CaptureAPIArgs: cfg.AuditLogCaptureArgs(),
 		MaxSizeMB:      cfg.AuditLogMaxSizeMB(),
 		MaxBackups:     cfg.AuditLogMaxBackups(),
 		ExcludeMethods: cfg.AuditLogExcludeMethods(),
 	}
-	return result, nil
 }
@@ -4,6 +4,7 @@
 package auditconfigupdater_test
 
 import (
+	"github.com/juju/clock"
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/testing"
@@ -57,6 +58,7 @@ func (s *manifoldSuite) SetUpTest(c *gc.C) {
 	s.manifold = auditconfigupdater.Manifold(auditconfigupdater.ManifoldConfig{
 		AgentName: "agent",
 		StateName: "state",
+		Clock:     clock.WallClock,
 		NewWorker: s.newWorker,
 	})
 }
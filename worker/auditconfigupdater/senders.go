@@ -0,0 +1,183 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditconfigupdater
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/rfc/rfc5424"
+
+	"github.com/juju/juju/core/auditlog"
+	"github.com/juju/juju/logfwd/httpfwd"
+	"github.com/juju/juju/logfwd/syslog"
+)
+
+// httpRequestTimeout is how long posting a batch of audit records may
+// take.
+const httpRequestTimeout = 30 * time.Second
+
+// syslogAppName is the app name audit records are sent to syslog with.
+const syslogAppName = "juju-audit"
+
+// syslogSender sends audit records to a syslog server, one message per
+// record. The connection is made when records are first sent, and is
+// remade after a failure.
+type syslogSender struct {
+	cfg      syslog.RawConfig
+	open     func(syslog.RawConfig) (*syslog.Client, error)
+	hostname string
+	client   *syslog.Client
+}
+
+func newSyslogSender(cfg syslog.RawConfig, open func(syslog.RawConfig) (*syslog.Client, error)) *syslogSender {
+	hostname, err := os.Hostname()
+	if err != nil {
+		logger.Warningf("unable to determine hostname for audit syslog messages: %v", err)
+	}
+	return &syslogSender{
+		cfg:      cfg,
+		open:     open,
+		hostname: hostname,
+	}
+}
+
+// Send implements auditlog.Sender.
+func (s *syslogSender) Send(records []auditlog.Record) (int, error) {
+	if s.client == nil {
+		client, err := s.open(s.cfg)
+		if err != nil {
+			return 0, errors.Annotatef(err, "connecting to %s", s.cfg.Host)
+		}
+		s.client = client
+	}
+	for i, record := range records {
+		msg, err := syslogMessage(record, s.hostname)
+		if err != nil {
+			return i, errors.Trace(err)
+		}
+		if err := s.client.Sender.Send(msg); err != nil {
+			_ = s.client.Close()
+			s.client = nil
+			return i, errors.Annotatef(err, "sending to %s", s.cfg.Host)
+		}
+	}
+	return len(records), nil
+}
+
+// Close implements auditlog.Sender.
+func (s *syslogSender) Close() error {
+	if s.client == nil {
+		return nil
+	}
+	err := s.client.Close()
+	s.client = nil
+	return errors.Trace(err)
+}
+
+func syslogMessage(record auditlog.Record, hostname string) (rfc5424.Message, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return rfc5424.Message{}, errors.Trace(err)
+	}
+	msg := rfc5424.Message{
+		Header: rfc5424.Header{
+			Priority: rfc5424.Priority{
+				Severity: rfc5424.SeverityNotice,
+				Facility: rfc5424.FacilityAuthpriv,
+			},
+			Hostname: rfc5424.Hostname{FQDN: hostname},
+			AppName:  syslogAppName,
+		},
+		Msg: string(data),
+	}
+	var when string
+	switch {
+	case record.Conversation != nil:
+		msg.MsgID = "conversation"
+		when = record.Conversation.When
+	case record.Request != nil:
+		msg.MsgID = "request"
+		when = record.Request.When
+	case record.Errors != nil:
+		msg.MsgID = "response"
+		when = record.Errors.When
+	case record.Gap != nil:
+		msg.MsgID = "gap"
+		when = record.Gap.To
+	}
+	if t, err := time.Parse(time.RFC3339, when); err == nil {
+		msg.Timestamp = rfc5424.Timestamp{Time: t}
+	}
+	if err := msg.Validate(); err != nil {
+		return msg, errors.Trace(err)
+	}
+	return msg, nil
+}
+
+// httpSender posts batches of audit records to an HTTP endpoint as a
+// JSON array.
+type httpSender struct {
+	cfg    httpfwd.RawConfig
+	client *http.Client
+}
+
+func newHTTPSender(cfg httpfwd.RawConfig) (*httpSender, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	tlsCfg, err := cfg.TLSConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsCfg != nil {
+		transport.TLSClientConfig = tlsCfg
+	}
+	return &httpSender{
+		cfg: cfg,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   httpRequestTimeout,
+		},
+	}, nil
+}
+
+// Send implements auditlog.Sender. The records are posted in one
+// request, so either all or none of them are delivered.
+func (s *httpSender) Send(records []auditlog.Record) (int, error) {
+	body, err := json.Marshal(records)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	req, err := http.NewRequest(http.MethodPost, s.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.cfg.Username != "" {
+		req.SetBasicAuth(s.cfg.Username, s.cfg.Password)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	respBody, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return 0, errors.Errorf("posting to %s: server returned %s: %s", s.cfg.URL, resp.Status, bytes.TrimSpace(respBody))
+	}
+	return len(records), nil
+}
+
+// Close implements auditlog.Sender.
+func (s *httpSender) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,238 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditconfigupdater_test
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/rfc/rfc5424"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/auditlog"
+	"github.com/juju/juju/logfwd/httpfwd"
+	"github.com/juju/juju/logfwd/syslog"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/auditconfigupdater"
+)
+
+var auditRecords = []auditlog.Record{{
+	Conversation: &auditlog.Conversation{
+		Who:            "user-bob",
+		ConversationID: "0123456789abcdef",
+		When:           "2021-03-04T05:06:07Z",
+	},
+}, {
+	Request: &auditlog.Request{
+		ConversationID: "0123456789abcdef",
+		RequestID:      1,
+		When:           "2021-03-04T05:06:08Z",
+		Facade:         "Application",
+		Method:         "Deploy",
+	},
+}, {
+	Errors: &auditlog.ResponseErrors{
+		ConversationID: "0123456789abcdef",
+		RequestID:      1,
+		When:           "2021-03-04T05:06:09Z",
+	},
+}, {
+	Gap: &auditlog.Gap{
+		Target:  "syslog",
+		Dropped: 2,
+		From:    "2021-03-04T05:06:10Z",
+		To:      "2021-03-04T05:06:11Z",
+	},
+}}
+
+type syslogSenderSuite struct {
+	testing.IsolationSuite
+
+	stub   *testing.Stub
+	opener *stubOpener
+	config syslog.RawConfig
+}
+
+var _ = gc.Suite(&syslogSenderSuite{})
+
+func (s *syslogSenderSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.stub = &testing.Stub{}
+	s.opener = &stubOpener{stub: s.stub}
+	s.config = syslog.RawConfig{
+		Enabled:    true,
+		Host:       "syslog.example.com:6514",
+		CACert:     coretesting.CACert,
+		ClientCert: coretesting.ServerCert,
+		ClientKey:  coretesting.ServerKey,
+	}
+}
+
+func (s *syslogSenderSuite) open(cfg syslog.RawConfig) (*syslog.Client, error) {
+	return syslog.OpenForSender(cfg, s.opener)
+}
+
+func (s *syslogSenderSuite) TestSend(c *gc.C) {
+	sender := auditconfigupdater.NewSyslogSender(s.config, s.open)
+	sent, err := sender.Send(auditRecords)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sent, gc.Equals, len(auditRecords))
+	c.Assert(sender.Close(), jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "DialFunc", "Open", "Send", "Send", "Send", "Send", "Close")
+	var msgIDs []rfc5424.MsgID
+	for i, call := range s.stub.Calls()[2:6] {
+		msg := call.Args[0].(rfc5424.Message)
+		c.Check(msg.AppName, gc.Equals, rfc5424.AppName("juju-audit"))
+		c.Check(msg.Priority, gc.Equals, rfc5424.Priority{
+			Severity: rfc5424.SeverityNotice,
+			Facility: rfc5424.FacilityAuthpriv,
+		})
+		var record auditlog.Record
+		c.Assert(json.Unmarshal([]byte(msg.Msg), &record), jc.ErrorIsNil)
+		c.Check(record, jc.DeepEquals, auditRecords[i])
+		msgIDs = append(msgIDs, msg.MsgID)
+	}
+	c.Assert(msgIDs, jc.DeepEquals, []rfc5424.MsgID{"conversation", "request", "response", "gap"})
+	msg := s.stub.Calls()[3].Args[0].(rfc5424.Message)
+	c.Assert(msg.Timestamp.Time, gc.Equals, time.Date(2021, 3, 4, 5, 6, 8, 0, time.UTC))
+}
+
+func (s *syslogSenderSuite) TestReconnectsAfterError(c *gc.C) {
+	sender := auditconfigupdater.NewSyslogSender(s.config, s.open)
+	s.stub.SetErrors(nil, nil, nil, errors.New("broken pipe"))
+	sent, err := sender.Send(auditRecords[:2])
+	c.Assert(err, gc.ErrorMatches, "sending to syslog.example.com:6514: broken pipe")
+	c.Assert(sent, gc.Equals, 1)
+
+	sent, err = sender.Send(auditRecords[1:2])
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sent, gc.Equals, 1)
+	s.stub.CheckCallNames(c, "DialFunc", "Open", "Send", "Send", "Close", "DialFunc", "Open", "Send")
+}
+
+func (s *syslogSenderSuite) TestConnectError(c *gc.C) {
+	sender := auditconfigupdater.NewSyslogSender(s.config, s.open)
+	s.stub.SetErrors(nil, errors.New("connection refused"))
+	sent, err := sender.Send(auditRecords[:1])
+	c.Assert(err, gc.ErrorMatches, "connecting to syslog.example.com:6514: .*connection refused")
+	c.Assert(sent, gc.Equals, 0)
+	c.Assert(sender.Close(), jc.ErrorIsNil)
+}
+
+type stubOpener struct {
+	stub *testing.Stub
+}
+
+func (o *stubOpener) DialFunc(cfg *tls.Config, timeout time.Duration) (rfc5424.DialFunc, error) {
+	o.stub.AddCall("DialFunc", cfg, timeout)
+	return nil, o.stub.NextErr()
+}
+
+func (o *stubOpener) Open(host string, cfg rfc5424.ClientConfig, dial rfc5424.DialFunc) (syslog.Sender, error) {
+	o.stub.AddCall("Open", host)
+	if err := o.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return &stubSyslogSender{stub: o.stub}, nil
+}
+
+type stubSyslogSender struct {
+	stub *testing.Stub
+}
+
+func (s *stubSyslogSender) Send(msg rfc5424.Message) error {
+	s.stub.AddCall("Send", msg)
+	return s.stub.NextErr()
+}
+
+func (s *stubSyslogSender) Close() error {
+	s.stub.AddCall("Close")
+	return s.stub.NextErr()
+}
+
+type httpSenderSuite struct {
+	testing.IsolationSuite
+
+	status   int
+	requests chan *receivedRequest
+	server   *httptest.Server
+}
+
+var _ = gc.Suite(&httpSenderSuite{})
+
+type receivedRequest struct {
+	contentType string
+	username    string
+	password    string
+	records     []auditlog.Record
+}
+
+func (s *httpSenderSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.status = http.StatusOK
+	s.requests = make(chan *receivedRequest, 10)
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := ioutil.ReadAll(req.Body)
+		c.Check(err, jc.ErrorIsNil)
+		received := &receivedRequest{contentType: req.Header.Get("Content-Type")}
+		received.username, received.password, _ = req.BasicAuth()
+		c.Check(json.Unmarshal(body, &received.records), jc.ErrorIsNil)
+		s.requests <- received
+		w.WriteHeader(s.status)
+		_, _ = w.Write([]byte("computer says no"))
+	}))
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+}
+
+func (s *httpSenderSuite) config() httpfwd.RawConfig {
+	return httpfwd.RawConfig{
+		Enabled:  true,
+		Format:   httpfwd.FormatJSON,
+		URL:      s.server.URL + "/audit",
+		Username: "juju",
+		Password: "secret",
+	}
+}
+
+func (s *httpSenderSuite) TestSend(c *gc.C) {
+	sender, err := auditconfigupdater.NewHTTPSender(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer sender.Close()
+
+	sent, err := sender.Send(auditRecords)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sent, gc.Equals, len(auditRecords))
+
+	received := <-s.requests
+	c.Assert(received.contentType, gc.Equals, "application/json")
+	c.Assert(received.username, gc.Equals, "juju")
+	c.Assert(received.password, gc.Equals, "secret")
+	c.Assert(received.records, jc.DeepEquals, auditRecords)
+}
+
+func (s *httpSenderSuite) TestServerError(c *gc.C) {
+	s.status = http.StatusServiceUnavailable
+	sender, err := auditconfigupdater.NewHTTPSender(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer sender.Close()
+
+	sent, err := sender.Send(auditRecords)
+	c.Assert(err, gc.ErrorMatches, `posting to .*/audit: server returned 503 Service Unavailable: computer says no`)
+	c.Assert(sent, gc.Equals, 0)
+}
+
+func (s *httpSenderSuite) TestInvalidConfig(c *gc.C) {
+	cfg := s.config()
+	cfg.URL = ""
+	_, err := auditconfigupdater.NewHTTPSender(cfg)
+	c.Assert(err, gc.ErrorMatches, "empty URL not valid")
+}
@@ -0,0 +1,112 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditconfigupdater
+
+import (
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/auditlog"
+	"github.com/juju/juju/logfwd/httpfwd"
+	"github.com/juju/juju/logfwd/syslog"
+)
+
+var logger = loggo.GetLogger("juju.worker.auditconfigupdater")
+
+// remoteRetryDelay is how long to wait before trying again to send
+// audit records to a remote target that couldn't be reached.
+const remoteRetryDelay = 10 * time.Second
+
+// TargetConfig holds the settings for the places audit records are
+// sent. They can't be changed after the controller is bootstrapped,
+// so they're read once when the worker starts.
+type TargetConfig struct {
+	// LogDir holds the audit log file and the buffers of records
+	// waiting to be sent to remote targets.
+	LogDir string
+
+	// Targets names the targets records are sent to.
+	Targets []string
+
+	// BufferSizeMB is the most unsent records kept for each remote
+	// target.
+	BufferSizeMB int
+
+	// BufferFullFail makes adding records fail when a remote target's
+	// buffer is full, rather than dropping them.
+	BufferFullFail bool
+
+	// Syslog and HTTP configure the remote targets.
+	Syslog syslog.RawConfig
+	HTTP   httpfwd.RawConfig
+
+	// Clock is used to wait before retrying remote targets.
+	Clock clock.Clock
+}
+
+func targetConfig(cfg controller.Config, logDir string, clock clock.Clock) TargetConfig {
+	return TargetConfig{
+		LogDir:         logDir,
+		Targets:        cfg.AuditLogTargets(),
+		BufferSizeMB:   cfg.AuditLogBufferSizeMB(),
+		BufferFullFail: cfg.AuditLogBufferFullFail(),
+		Syslog:         cfg.AuditLogSyslog(),
+		HTTP:           cfg.AuditLogHTTP(),
+		Clock:          clock,
+	}
+}
+
+// newTarget returns an audit log that writes records to every target
+// in the config. Records for remote targets are buffered on disk
+// until they can be sent, so they aren't lost while the remote target
+// is down.
+func newTarget(cfg auditlog.Config, targetCfg TargetConfig) (_ auditlog.AuditLog, err error) {
+	var targets []auditlog.AuditLog
+	defer func() {
+		if err != nil {
+			for _, target := range targets {
+				_ = target.Close()
+			}
+		}
+	}()
+	for _, name := range targetCfg.Targets {
+		var sender auditlog.Sender
+		switch name {
+		case controller.AuditLogTargetFile:
+			targets = append(targets, auditlog.NewLogFile(targetCfg.LogDir, cfg.MaxSizeMB, cfg.MaxBackups))
+			continue
+		case controller.AuditLogTargetSyslog:
+			sender = newSyslogSender(targetCfg.Syslog, syslog.Open)
+		case controller.AuditLogTargetHTTP:
+			sender, err = newHTTPSender(targetCfg.HTTP)
+			if err != nil {
+				return nil, errors.Annotate(err, "creating http audit log target")
+			}
+		default:
+			return nil, errors.NotValidf("audit log target %q", name)
+		}
+		target, err := auditlog.NewRemoteLog(auditlog.RemoteConfig{
+			Name:          name,
+			Sender:        sender,
+			BufferDir:     targetCfg.LogDir,
+			MaxBufferSize: int64(targetCfg.BufferSizeMB) * 1024 * 1024,
+			FailWhenFull:  targetCfg.BufferFullFail,
+			RetryDelay:    remoteRetryDelay,
+			Clock:         targetCfg.Clock,
+		})
+		if err != nil {
+			_ = sender.Close()
+			return nil, errors.Annotatef(err, "creating %s audit log target", name)
+		}
+		targets = append(targets, target)
+	}
+	if len(targets) == 0 {
+		return nil, errors.NotValidf("empty audit log targets")
+	}
+	return auditlog.NewFanOut(targets...), nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditconfigupdater_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/auditlog"
+	"github.com/juju/juju/logfwd/httpfwd"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/auditconfigupdater"
+)
+
+type targetsSuite struct {
+	testing.IsolationSuite
+
+	logDir string
+	config auditlog.Config
+}
+
+var _ = gc.Suite(&targetsSuite{})
+
+func (s *targetsSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.logDir = c.MkDir()
+	s.config = auditlog.Config{
+		Enabled:    true,
+		MaxSizeMB:  10,
+		MaxBackups: 2,
+	}
+}
+
+func (s *targetsSuite) targetConfig(targets ...string) auditconfigupdater.TargetConfig {
+	return auditconfigupdater.TargetConfig{
+		LogDir:       s.logDir,
+		Targets:      targets,
+		BufferSizeMB: 1,
+		Clock:        clock.WallClock,
+	}
+}
+
+func (s *targetsSuite) TestFileTarget(c *gc.C) {
+	target, err := auditconfigupdater.NewTarget(s.config, s.targetConfig("file"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(target.AddRequest(*auditRecords[1].Request), jc.ErrorIsNil)
+	c.Assert(target.Close(), jc.ErrorIsNil)
+
+	data, err := ioutil.ReadFile(filepath.Join(s.logDir, "audit.log"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), jc.Contains, `"facade":"Application"`)
+}
+
+func (s *targetsSuite) TestFanOutToHTTPTarget(c *gc.C) {
+	received := make(chan []auditlog.Record, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var records []auditlog.Record
+		c.Check(json.NewDecoder(req.Body).Decode(&records), jc.ErrorIsNil)
+		received <- records
+	}))
+	defer server.Close()
+
+	targetCfg := s.targetConfig("file", "http")
+	targetCfg.HTTP = httpfwd.RawConfig{
+		Enabled: true,
+		Format:  httpfwd.FormatJSON,
+		URL:     server.URL,
+	}
+	target, err := auditconfigupdater.NewTarget(s.config, targetCfg)
+	c.Assert(err, jc.ErrorIsNil)
+	defer target.Close()
+	c.Assert(target.AddRequest(*auditRecords[1].Request), jc.ErrorIsNil)
+
+	select {
+	case records := <-received:
+		c.Assert(records, jc.DeepEquals, auditRecords[1:2])
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for records")
+	}
+	data, err := ioutil.ReadFile(filepath.Join(s.logDir, "audit.log"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), jc.Contains, `"facade":"Application"`)
+}
+
+func (s *targetsSuite) TestInvalidHTTPTarget(c *gc.C) {
+	targetCfg := s.targetConfig("file", "http")
+	targetCfg.HTTP = httpfwd.RawConfig{
+		Enabled: true,
+		Format:  httpfwd.FormatJSON,
+	}
+	_, err := auditconfigupdater.NewTarget(s.config, targetCfg)
+	c.Assert(err, gc.ErrorMatches, "creating http audit log target: empty URL not valid")
+}
+
+func (s *targetsSuite) TestUnknownTarget(c *gc.C) {
+	_, err := auditconfigupdater.NewTarget(s.config, s.targetConfig("kafka"))
+	c.Assert(err, gc.ErrorMatches, `audit log target "kafka" not valid`)
+}
@@ -25,7 +25,7 @@ type ConfigSource interface {
 
 // AuditLogFactory is a function that will return an audit log given
 // config.
-type AuditLogFactory func(auditlog.Config) auditlog.AuditLog
+type AuditLogFactory func(auditlog.Config) (auditlog.AuditLog, error)
 
 // New returns a worker that will keep an up-to-date audit log config.
 func New(source ConfigSource, initial auditlog.Config, logFactory AuditLogFactory) (worker.Worker, error) {
@@ -97,7 +97,10 @@ func (u *updater) newConfig() (auditlog.Config, error) {
 		ExcludeMethods: cfg.AuditLogExcludeMethods(),
 	}
 	if result.Enabled && u.current.Target == nil {
-		result.Target = u.logFactory(result)
+		result.Target, err = u.logFactory(result)
+		if err != nil {
+			return auditlog.Config{}, errors.Annotate(err, "creating audit log")
+		}
 	} else {
 		// Keep the existing target to avoid file handle leaks from
 		// disabling and enabling auditing - we'll still stop logging
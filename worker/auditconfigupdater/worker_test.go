@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"github.com/juju/collections/set"
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/worker/v2"
@@ -43,9 +44,9 @@ func (s *updaterSuite) TestWorker(c *gc.C) {
 
 	fakeTarget := apitesting.FakeAuditLog{}
 	var calls []auditlog.Config
-	factory := func(cfg auditlog.Config) auditlog.AuditLog {
+	factory := func(cfg auditlog.Config) (auditlog.AuditLog, error) {
 		calls = append(calls, cfg)
-		return &fakeTarget
+		return &fakeTarget, nil
 	}
 
 	w, err := auditconfigupdater.New(&source, initial, factory)
@@ -66,6 +67,27 @@ func (s *updaterSuite) TestWorker(c *gc.C) {
 	c.Assert(calls, gc.HasLen, 1)
 }
 
+func (s *updaterSuite) TestFactoryError(c *gc.C) {
+	configChanged := make(chan struct{}, 1)
+	source := configSource{
+		watcher: watchertest.NewNotifyWatcher(configChanged),
+		cfg:     makeControllerConfig(false, false),
+	}
+	factory := func(cfg auditlog.Config) (auditlog.AuditLog, error) {
+		return nil, errors.New("no disk")
+	}
+
+	w, err := auditconfigupdater.New(&source, auditlog.Config{}, factory)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, w)
+
+	source.setConfig(makeControllerConfig(true, false))
+	configChanged <- ding
+
+	err = workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "getting new config: creating audit log: no disk")
+}
+
 func waitForConfig(c *gc.C, w worker.Worker, predicate func(auditlog.Config) bool) auditlog.Config {
 	for a := jujutesting.LongAttempt.Start(); a.Next(); {
 		config := getWorkerConfig(c, w)
@@ -0,0 +1,70 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlogquery
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/pubsub"
+	"github.com/juju/worker/v2"
+	"github.com/juju/worker/v2/dependency"
+)
+
+// ManifoldConfig holds the resources needed to run an audit log query
+// worker in a dependency engine.
+type ManifoldConfig struct {
+	CentralHubName string
+
+	LogDir    string
+	Logger    Logger
+	NewWorker func(Config) (worker.Worker, error)
+}
+
+// Validate checks that the config has all the required values.
+func (config ManifoldConfig) Validate() error {
+	if config.CentralHubName == "" {
+		return errors.NotValidf("empty CentralHubName")
+	}
+	if config.LogDir == "" {
+		return errors.NotValidf("empty LogDir")
+	}
+	if config.Logger == nil {
+		return errors.NotValidf("nil Logger")
+	}
+	if config.NewWorker == nil {
+		return errors.NotValidf("nil NewWorker")
+	}
+	return nil
+}
+
+// Manifold returns a dependency.Manifold that runs an audit log query
+// worker.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{
+			config.CentralHubName,
+		},
+		Start: config.start,
+	}
+}
+
+func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var hub *pubsub.StructuredHub
+	if err := context.Get(config.CentralHubName, &hub); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	w, err := config.NewWorker(Config{
+		Hub:    hub,
+		LogDir: config.LogDir,
+		Logger: config.Logger,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
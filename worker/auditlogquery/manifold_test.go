@@ -0,0 +1,118 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlogquery_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/pubsub"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v2"
+	"github.com/juju/worker/v2/dependency"
+	dt "github.com/juju/worker/v2/dependency/testing"
+	"github.com/juju/worker/v2/workertest"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/auditlogquery"
+)
+
+type ManifoldSuite struct {
+	testing.IsolationSuite
+
+	config   auditlogquery.ManifoldConfig
+	manifold dependency.Manifold
+	context  dependency.Context
+	hub      *pubsub.StructuredHub
+	logger   loggo.Logger
+	worker   worker.Worker
+
+	stub testing.Stub
+}
+
+var _ = gc.Suite(&ManifoldSuite{})
+
+func (s *ManifoldSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.hub = pubsub.NewStructuredHub(nil)
+	s.logger = loggo.GetLogger("auditlogquery_manifold")
+	s.worker = &struct{ worker.Worker }{}
+	s.stub.ResetCalls()
+
+	s.context = dt.StubContext(nil, map[string]interface{}{
+		"hub": s.hub,
+	})
+	s.config = auditlogquery.ManifoldConfig{
+		CentralHubName: "hub",
+		LogDir:         "/var/log/juju",
+		Logger:         s.logger,
+		NewWorker:      s.newWorker,
+	}
+	s.manifold = auditlogquery.Manifold(s.config)
+}
+
+func (s *ManifoldSuite) newWorker(config auditlogquery.Config) (worker.Worker, error) {
+	s.stub.MethodCall(s, "NewWorker", config)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return s.worker, nil
+}
+
+func (s *ManifoldSuite) TestInputs(c *gc.C) {
+	c.Assert(s.manifold.Inputs, jc.SameContents, []string{"hub"})
+}
+
+func (s *ManifoldSuite) TestMissingHub(c *gc.C) {
+	context := dt.StubContext(nil, map[string]interface{}{
+		"hub": dependency.ErrMissing,
+	})
+	_, err := s.manifold.Start(context)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrMissing)
+}
+
+func (s *ManifoldSuite) TestValidate(c *gc.C) {
+	type test struct {
+		f      func(*auditlogquery.ManifoldConfig)
+		expect string
+	}
+	tests := []test{{
+		func(cfg *auditlogquery.ManifoldConfig) { cfg.CentralHubName = "" },
+		"empty CentralHubName not valid",
+	}, {
+		func(cfg *auditlogquery.ManifoldConfig) { cfg.LogDir = "" },
+		"empty LogDir not valid",
+	}, {
+		func(cfg *auditlogquery.ManifoldConfig) { cfg.Logger = nil },
+		"nil Logger not valid",
+	}, {
+		func(cfg *auditlogquery.ManifoldConfig) { cfg.NewWorker = nil },
+		"nil NewWorker not valid",
+	}}
+	for i, test := range tests {
+		c.Logf("test #%d (%s)", i, test.expect)
+		config := s.config
+		test.f(&config)
+		manifold := auditlogquery.Manifold(config)
+		w, err := manifold.Start(s.context)
+		workertest.CheckNilOrKill(c, w)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
+
+func (s *ManifoldSuite) TestStart(c *gc.C) {
+	w, err := s.manifold.Start(s.context)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w, gc.Equals, s.worker)
+
+	s.stub.CheckCallNames(c, "NewWorker")
+	c.Assert(s.stub.Calls()[0].Args, jc.DeepEquals, []interface{}{
+		auditlogquery.Config{
+			Hub:    s.hub,
+			LogDir: "/var/log/juju",
+			Logger: s.logger,
+		},
+	})
+}
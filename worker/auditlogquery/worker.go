@@ -0,0 +1,143 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package auditlogquery provides a worker that answers audit log
+// queries published on the central hub from the audit log files on the
+// local controller node, so that the logs of all the nodes in an HA
+// controller can be queried through any of its API servers.
+package auditlogquery
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/pubsub"
+	"github.com/juju/worker/v2"
+	"github.com/juju/worker/v2/catacomb"
+
+	"github.com/juju/juju/core/auditlog"
+	pubsubauditlog "github.com/juju/juju/pubsub/auditlog"
+)
+
+// Logger defines the methods needed for the worker to log messages.
+type Logger interface {
+	Debugf(string, ...interface{})
+	Errorf(string, ...interface{})
+}
+
+// Config defines the resources the worker needs to run.
+type Config struct {
+	Hub    *pubsub.StructuredHub
+	LogDir string
+	Logger Logger
+}
+
+// Validate checks that this config can be used.
+func (config Config) Validate() error {
+	if config.Hub == nil {
+		return errors.NotValidf("nil Hub")
+	}
+	if config.LogDir == "" {
+		return errors.NotValidf("empty LogDir")
+	}
+	if config.Logger == nil {
+		return errors.NotValidf("nil Logger")
+	}
+	return nil
+}
+
+// NewWorker returns a worker that responds to audit log queries.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &queryWorker{config: config}
+	unsubscribe, err := config.Hub.Subscribe(pubsubauditlog.QueryTopic, w.handleQuery)
+	if err != nil {
+		return nil, errors.Annotatef(err, "subscribing to %q", pubsubauditlog.QueryTopic)
+	}
+	w.unsubscribe = unsubscribe
+	if err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	}); err != nil {
+		unsubscribe()
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type queryWorker struct {
+	catacomb    catacomb.Catacomb
+	config      Config
+	unsubscribe func()
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *queryWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *queryWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *queryWorker) loop() error {
+	defer w.unsubscribe()
+	<-w.catacomb.Dying()
+	return w.catacomb.ErrDying()
+}
+
+func (w *queryWorker) handleQuery(_ string, req pubsubauditlog.QueryRequest, err error) {
+	if err != nil {
+		w.config.Logger.Errorf("audit log query callback error: %v", err)
+		return
+	}
+	if req.ResponseTopic == "" {
+		w.config.Logger.Errorf("audit log query without response topic")
+		return
+	}
+	// Reading the logs may take a while, so don't hold up the other
+	// subscribers to the hub.
+	go func() {
+		response := w.query(req)
+		if _, err := w.config.Hub.Publish(req.ResponseTopic, response); err != nil {
+			w.config.Logger.Errorf("publishing audit log query response: %v", err)
+		}
+	}()
+}
+
+func (w *queryWorker) query(req pubsubauditlog.QueryRequest) pubsubauditlog.QueryResponse {
+	filter, err := requestFilter(req)
+	if err != nil {
+		return pubsubauditlog.QueryResponse{Error: err.Error()}
+	}
+	entries, err := auditlog.ReadEntries(w.config.LogDir, filter, req.Limit)
+	if err != nil {
+		w.config.Logger.Errorf("reading audit log: %v", err)
+		return pubsubauditlog.QueryResponse{Error: err.Error()}
+	}
+	w.config.Logger.Debugf("audit log query matched %d entries", len(entries))
+	return pubsubauditlog.QueryResponse{Entries: entries}
+}
+
+func requestFilter(req pubsubauditlog.QueryRequest) (auditlog.Filter, error) {
+	filter := auditlog.Filter{
+		Users:   req.Users,
+		Models:  req.Models,
+		Methods: req.Methods,
+	}
+	var err error
+	if req.From != "" {
+		if filter.From, err = time.Parse(time.RFC3339, req.From); err != nil {
+			return filter, errors.NotValidf("from time %q", req.From)
+		}
+	}
+	if req.To != "" {
+		if filter.To, err = time.Parse(time.RFC3339, req.To); err != nil {
+			return filter, errors.NotValidf("to time %q", req.To)
+		}
+	}
+	return filter, nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlogquery_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/juju/loggo"
+	"github.com/juju/names/v4"
+	"github.com/juju/pubsub"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v2/workertest"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/auditlog"
+	pubsubauditlog "github.com/juju/juju/pubsub/auditlog"
+	"github.com/juju/juju/pubsub/centralhub"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/auditlogquery"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+
+	hub    *pubsub.StructuredHub
+	config auditlogquery.Config
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+var conversation = auditlog.Conversation{
+	Who:            "bob",
+	What:           "juju remove-application mysql",
+	When:           "2021-03-01T10:00:00Z",
+	ModelName:      "default",
+	ModelUUID:      "deadbeef-0bad-400d-8000-4b1d0d06f00d",
+	ConversationID: "0000000000000001",
+	ConnectionID:   "A",
+}
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.hub = centralhub.New(names.NewMachineTag("0"))
+	s.config = auditlogquery.Config{
+		Hub:    s.hub,
+		LogDir: c.MkDir(),
+		Logger: loggo.GetLogger("test"),
+	}
+
+	f, err := os.Create(filepath.Join(s.config.LogDir, "audit.log"))
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+	encoder := json.NewEncoder(f)
+	for _, record := range []auditlog.Record{
+		{Conversation: &conversation},
+		{Request: request(1, "2021-03-01T10:00:01Z", "Application", "DestroyApplication")},
+		{Request: request(2, "2021-03-01T10:00:02Z", "Client", "FullStatus")},
+	} {
+		c.Assert(encoder.Encode(record), jc.ErrorIsNil)
+	}
+}
+
+func request(id uint64, when, facade, method string) *auditlog.Request {
+	return &auditlog.Request{
+		ConversationID: conversation.ConversationID,
+		ConnectionID:   conversation.ConnectionID,
+		RequestID:      id,
+		When:           when,
+		Facade:         facade,
+		Method:         method,
+		Version:        1,
+	}
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	type test struct {
+		f      func(*auditlogquery.Config)
+		expect string
+	}
+	tests := []test{{
+		func(cfg *auditlogquery.Config) { cfg.Hub = nil },
+		"nil Hub not valid",
+	}, {
+		func(cfg *auditlogquery.Config) { cfg.LogDir = "" },
+		"empty LogDir not valid",
+	}, {
+		func(cfg *auditlogquery.Config) { cfg.Logger = nil },
+		"nil Logger not valid",
+	}}
+	for i, test := range tests {
+		c.Logf("test #%d (%s)", i, test.expect)
+		config := s.config
+		test.f(&config)
+		w, err := auditlogquery.NewWorker(config)
+		workertest.CheckNilOrKill(c, w)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
+
+func (s *WorkerSuite) query(c *gc.C, req pubsubauditlog.QueryRequest) pubsubauditlog.QueryResponse {
+	responses := make(chan pubsubauditlog.QueryResponse, 1)
+	unsubscribe, err := s.hub.Subscribe("test.response", func(_ string, resp pubsubauditlog.QueryResponse, err error) {
+		c.Check(err, jc.ErrorIsNil)
+		responses <- resp
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer unsubscribe()
+
+	req.ResponseTopic = "test.response"
+	_, err = s.hub.Publish(pubsubauditlog.QueryTopic, req)
+	c.Assert(err, jc.ErrorIsNil)
+
+	select {
+	case resp := <-responses:
+		return resp
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for response")
+	}
+	return pubsubauditlog.QueryResponse{}
+}
+
+func (s *WorkerSuite) TestQuery(c *gc.C) {
+	w, err := auditlogquery.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	resp := s.query(c, pubsubauditlog.QueryRequest{
+		Users:   []string{"bob"},
+		Methods: []string{"Application"},
+		From:    "2021-03-01T00:00:00Z",
+	})
+	c.Assert(resp, jc.DeepEquals, pubsubauditlog.QueryResponse{
+		Origin: "machine-0",
+		Entries: []auditlog.Entry{{
+			Conversation: conversation,
+			Request:      *request(1, "2021-03-01T10:00:01Z", "Application", "DestroyApplication"),
+		}},
+	})
+}
+
+func (s *WorkerSuite) TestQueryLimit(c *gc.C) {
+	w, err := auditlogquery.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	resp := s.query(c, pubsubauditlog.QueryRequest{Limit: 1})
+	c.Assert(resp.Error, gc.Equals, "")
+	c.Assert(resp.Entries, gc.HasLen, 1)
+	c.Assert(resp.Entries[0].Request.Method, gc.Equals, "FullStatus")
+}
+
+func (s *WorkerSuite) TestQueryBadTime(c *gc.C) {
+	w, err := auditlogquery.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	resp := s.query(c, pubsubauditlog.QueryRequest{To: "yesterday"})
+	c.Assert(resp.Error, gc.Equals, `to time "yesterday" not valid`)
+	c.Assert(resp.Entries, gc.HasLen, 0)
+}
+
+func (s *WorkerSuite) TestUnsubscribesWhenStopped(c *gc.C) {
+	w, err := auditlogquery.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	workertest.CleanKill(c, w)
+
+	responses := make(chan pubsubauditlog.QueryResponse, 1)
+	unsubscribe, err := s.hub.Subscribe("test.response", func(_ string, resp pubsubauditlog.QueryResponse, err error) {
+		responses <- resp
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer unsubscribe()
+
+	_, err = s.hub.Publish(pubsubauditlog.QueryTopic, pubsubauditlog.QueryRequest{
+		ResponseTopic: "test.response",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case resp := <-responses:
+		c.Fatalf("unexpected response %#v", resp)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/juju/juju/state"
+)
+
+const (
+	metricsNamespace = "juju_backupscheduler"
+
+	resultLabel   = "result"
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+// collector is a prometheus.Collector that collects metrics about the
+// scheduled backups.
+type collector struct {
+	enabled      prometheus.Gauge
+	lastSuccess  prometheus.Gauge
+	lastFailure  prometheus.Gauge
+	lastDuration prometheus.Gauge
+	lastSize     prometheus.Gauge
+	runs         *prometheus.CounterVec
+}
+
+func newCollector() *collector {
+	return &collector{
+		enabled: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "enabled",
+			Help:      "Whether a backup schedule is configured (1) or not (0).",
+		}),
+		lastSuccess: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "last_success_timestamp_seconds",
+			Help:      "The time the last successful scheduled backup started.",
+		}),
+		lastFailure: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "last_failure_timestamp_seconds",
+			Help:      "The time the last failed scheduled backup started.",
+		}),
+		lastDuration: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "last_duration_seconds",
+			Help:      "How long the last scheduled backup took.",
+		}),
+		lastSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "last_size_bytes",
+			Help:      "The size of the last successful scheduled backup.",
+		}),
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "runs_total",
+			Help:      "The number of scheduled backups, by result.",
+		}, []string{resultLabel}),
+	}
+}
+
+// Describe is part of the prometheus.Collector interface.
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	c.enabled.Describe(ch)
+	c.lastSuccess.Describe(ch)
+	c.lastFailure.Describe(ch)
+	c.lastDuration.Describe(ch)
+	c.lastSize.Describe(ch)
+	c.runs.Describe(ch)
+}
+
+// Collect is part of the prometheus.Collector interface.
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	c.enabled.Collect(ch)
+	c.lastSuccess.Collect(ch)
+	c.lastFailure.Collect(ch)
+	c.lastDuration.Collect(ch)
+	c.lastSize.Collect(ch)
+	c.runs.Collect(ch)
+}
+
+func (c *collector) setEnabled(enabled bool) {
+	if enabled {
+		c.enabled.Set(1)
+	} else {
+		c.enabled.Set(0)
+	}
+}
+
+// setStatus seeds the timestamps from the recorded status, so they
+// survive the worker restarting or moving to another controller.
+func (c *collector) setStatus(status state.ScheduledBackupStatus) {
+	setTimestamp(c.lastSuccess, status.LastSuccess)
+	setTimestamp(c.lastFailure, status.LastFailure)
+}
+
+func (c *collector) recordSuccess(started time.Time, elapsed time.Duration, size int64) {
+	setTimestamp(c.lastSuccess, started)
+	c.lastDuration.Set(elapsed.Seconds())
+	c.lastSize.Set(float64(size))
+	c.runs.WithLabelValues(resultSuccess).Inc()
+}
+
+func (c *collector) recordFailure(started time.Time, elapsed time.Duration) {
+	setTimestamp(c.lastFailure, started)
+	c.lastDuration.Set(elapsed.Seconds())
+	c.runs.WithLabelValues(resultFailure).Inc()
+}
+
+func setTimestamp(g prometheus.Gauge, t time.Time) {
+	if !t.IsZero() {
+		g.Set(float64(t.UnixNano()) / 1e9)
+	}
+}
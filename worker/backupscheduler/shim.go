@@ -0,0 +1,158 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler
+
+import (
+	"io"
+
+	"github.com/juju/errors"
+	"github.com/juju/names/v4"
+	"github.com/juju/replicaset"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+)
+
+// This file contains untested shims to let us wrap state in a sensible
+// interface and avoid writing tests that depend on mongodb. If you were
+// to change any part of it so that it were no longer *obviously* and
+// *trivially* correct, you would be Doing It Wrong.
+
+// stateShim disambiguates the methods of the controller's state and
+// model, to satisfy backups.DB.
+type stateShim struct {
+	*state.State
+	*state.Model
+}
+
+// ModelTag disambiguates the ModelTag method pending further refactoring
+// to separate model functionality from state functionality.
+func (s stateShim) ModelTag() names.ModelTag {
+	return s.Model.ModelTag()
+}
+
+// stateBackups creates backups of the controller in the same way as
+// the Backups facade's Create method, storing them in the controller's
+// backup storage.
+type stateBackups struct {
+	st        stateShim
+	machineID string
+	dataDir   string
+	logDir    string
+	mongoInfo *mongo.MongoInfo
+}
+
+func newStateBackups(st *state.State, model *state.Model, agentConfig agent.Config) (*stateBackups, error) {
+	mongoInfo, ok := agentConfig.MongoInfo()
+	if !ok {
+		return nil, errors.New("mongo info missing from agent config")
+	}
+	return &stateBackups{
+		st:        stateShim{st, model},
+		machineID: agentConfig.Tag().Id(),
+		dataDir:   agentConfig.DataDir(),
+		logDir:    agentConfig.LogDir(),
+		mongoInfo: mongoInfo,
+	}, nil
+}
+
+func (b *stateBackups) open() (backups.Backups, io.Closer) {
+	stor := backups.NewStorage(b.st)
+	return backups.NewBackups(stor), stor
+}
+
+// Create is part of the Backups interface.
+func (b *stateBackups) Create(notes string) (*backups.Metadata, error) {
+	session := b.st.MongoSession().Copy()
+	defer session.Close()
+
+	// Don't go if HA isn't ready.
+	if err := replicaset.WaitUntilReady(session, 60); err != nil {
+		return nil, errors.Annotatef(err, "HA not ready")
+	}
+	dbInfo, err := backups.NewDBInfo(b.mongoInfo, session)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	machine, err := b.st.Machine(b.machineID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	meta, err := backups.NewMetadataState(b.st, b.machineID, machine.Series())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	meta.Notes = notes
+	meta.Controller.MachineID = b.machineID
+	instanceID, err := machine.InstanceId()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	meta.Controller.MachineInstanceID = string(instanceID)
+	nodes, err := b.st.ControllerNodes()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	meta.Controller.HANodes = int64(len(nodes))
+
+	modelConfig, err := b.st.ModelConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	paths := backups.Paths{
+		BackupDir: modelConfig.BackupDir(),
+		DataDir:   b.dataDir,
+		LogsDir:   b.logDir,
+	}
+
+	api, closer := b.open()
+	defer closer.Close()
+	if _, err := api.Create(meta, &paths, dbInfo, true, true); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return meta, nil
+}
+
+// List is part of the Backups interface.
+func (b *stateBackups) List() ([]*backups.Metadata, error) {
+	api, closer := b.open()
+	defer closer.Close()
+	return api.List()
+}
+
+// Get is part of the Backups interface. The backup storage is kept open
+// until the archive is closed.
+func (b *stateBackups) Get(id string) (*backups.Metadata, io.ReadCloser, error) {
+	api, closer := b.open()
+	meta, archive, err := api.Get(id)
+	if err != nil {
+		_ = closer.Close()
+		return nil, nil, errors.Trace(err)
+	}
+	return meta, &archiveCloser{archive, closer}, nil
+}
+
+// archiveCloser closes the backup storage along with the archive read
+// from it.
+type archiveCloser struct {
+	io.ReadCloser
+	storage io.Closer
+}
+
+// Close is part of io.Closer.
+func (a *archiveCloser) Close() error {
+	err := a.ReadCloser.Close()
+	_ = a.storage.Close()
+	return errors.Trace(err)
+}
+
+// Remove is part of the Backups interface.
+func (b *stateBackups) Remove(id string) error {
+	api, closer := b.open()
+	defer closer.Close()
+	return api.Remove(id)
+}
@@ -0,0 +1,146 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/controller"
+)
+
+// defaultS3Region is used for the backup S3 bucket if no region is
+// configured; S3-compatible services generally ignore it.
+const defaultS3Region = "us-east-1"
+
+// Uploader copies backup archives somewhere off the controller's
+// backup storage.
+type Uploader interface {
+	fmt.Stringer
+
+	// Upload writes the archive with the given file name.
+	Upload(name string, archive io.Reader) error
+}
+
+// NewUploaders returns the uploaders for the backup copy directory
+// and S3 bucket in the controller config, if they are set.
+func NewUploaders(cfg controller.Config) ([]Uploader, error) {
+	var uploaders []Uploader
+	if dir := cfg.BackupCopyDir(); dir != "" {
+		uploaders = append(uploaders, NewDirUploader(dir))
+	}
+	if s3Config := cfg.BackupS3(); s3Config.Enabled() {
+		uploader, err := NewS3Uploader(s3Config)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		uploaders = append(uploaders, uploader)
+	}
+	return uploaders, nil
+}
+
+// NewDirUploader returns an uploader that writes archives to the given
+// directory, creating it if necessary.
+func NewDirUploader(dir string) Uploader {
+	return dirUploader{dir: dir}
+}
+
+type dirUploader struct {
+	dir string
+}
+
+// String is part of fmt.Stringer.
+func (u dirUploader) String() string {
+	return fmt.Sprintf("directory %q", u.dir)
+}
+
+// Upload is part of the Uploader interface. The archive is written to
+// a temporary file which is renamed once complete, so a partial copy is
+// never left with the archive's name.
+func (u dirUploader) Upload(name string, archive io.Reader) (err error) {
+	if err := os.MkdirAll(u.dir, 0700); err != nil {
+		return errors.Trace(err)
+	}
+	f, err := ioutil.TempFile(u.dir, "."+name+".")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}()
+	if _, err := io.Copy(f, archive); err != nil {
+		return errors.Trace(err)
+	}
+	if err := f.Sync(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := f.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(os.Rename(f.Name(), filepath.Join(u.dir, name)))
+}
+
+// NewS3Uploader returns an uploader that puts archives in an S3 bucket.
+func NewS3Uploader(cfg controller.BackupS3Config) (Uploader, error) {
+	awsConfig := &aws.Config{
+		Region: aws.String(cfg.Region),
+	}
+	if cfg.Region == "" {
+		awsConfig.Region = aws.String(defaultS3Region)
+	}
+	if cfg.Endpoint != "" {
+		// S3-compatible services rarely support virtual-hosted
+		// buckets, so address them by path.
+		awsConfig.Endpoint = aws.String(cfg.Endpoint)
+		awsConfig.S3ForcePathStyle = aws.Bool(true)
+	}
+	if cfg.AccessKey != "" {
+		awsConfig.Credentials = credentials.NewStaticCredentials(cfg.AccessKey, cfg.SecretKey, "")
+	}
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, errors.Annotate(err, "creating S3 session")
+	}
+	return &s3Uploader{
+		bucket:   cfg.Bucket,
+		endpoint: cfg.Endpoint,
+		uploader: s3manager.NewUploader(sess),
+	}, nil
+}
+
+type s3Uploader struct {
+	bucket   string
+	endpoint string
+	uploader *s3manager.Uploader
+}
+
+// String is part of fmt.Stringer.
+func (u *s3Uploader) String() string {
+	if u.endpoint != "" {
+		return fmt.Sprintf("S3 bucket %q at %s", u.bucket, u.endpoint)
+	}
+	return fmt.Sprintf("S3 bucket %q", u.bucket)
+}
+
+// Upload is part of the Uploader interface.
+func (u *s3Uploader) Upload(name string, archive io.Reader) error {
+	_, err := u.uploader.Upload(&s3manager.UploadInput{
+		Bucket: aws.String(u.bucket),
+		Key:    aws.String(name),
+		Body:   archive,
+	})
+	return errors.Trace(err)
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/worker/backupscheduler"
+)
+
+type UploadersSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&UploadersSuite{})
+
+func (s *UploadersSuite) TestDirUploader(c *gc.C) {
+	dir := filepath.Join(c.MkDir(), "backups")
+	uploader := backupscheduler.NewDirUploader(dir)
+	c.Assert(uploader.String(), gc.Equals, `directory "`+dir+`"`)
+
+	err := uploader.Upload("juju-backup-1.tar.gz", strings.NewReader("archive"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	files, err := ioutil.ReadDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(files, gc.HasLen, 1)
+	c.Assert(files[0].Name(), gc.Equals, "juju-backup-1.tar.gz")
+	data, err := ioutil.ReadFile(filepath.Join(dir, "juju-backup-1.tar.gz"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "archive")
+}
+
+func (s *UploadersSuite) TestDirUploaderReadError(c *gc.C) {
+	dir := c.MkDir()
+	uploader := backupscheduler.NewDirUploader(dir)
+	err := uploader.Upload("juju-backup-1.tar.gz", errorReader{errors.New("boom")})
+	c.Assert(err, gc.ErrorMatches, "boom")
+
+	// No partial copy is left behind.
+	files, err := ioutil.ReadDir(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(files, gc.HasLen, 0)
+}
+
+func (s *UploadersSuite) TestNewUploadersNone(c *gc.C) {
+	uploaders, err := backupscheduler.NewUploaders(controller.Config{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uploaders, gc.HasLen, 0)
+}
+
+func (s *UploadersSuite) TestNewUploaders(c *gc.C) {
+	uploaders, err := backupscheduler.NewUploaders(controller.Config{
+		controller.BackupCopyDir:     "/srv/backups",
+		controller.BackupS3Endpoint:  "https://minio.example.com:9000",
+		controller.BackupS3Bucket:    "backups",
+		controller.BackupS3AccessKey: "access",
+		controller.BackupS3SecretKey: "secret",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uploaders, gc.HasLen, 2)
+	c.Assert(uploaders[0].String(), gc.Equals, `directory "/srv/backups"`)
+	c.Assert(uploaders[1].String(), gc.Equals, `S3 bucket "backups" at https://minio.example.com:9000`)
+}
+
+type errorReader struct {
+	err error
+}
+
+func (r errorReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,324 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package backupscheduler provides a worker that creates backups of the
+// controller on the schedule set in controller config. It removes old
+// scheduled backups from backup storage, and copies each new one to a
+// local directory or an S3-compatible service if so configured.
+package backupscheduler
+
+import (
+	"io"
+	"sort"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/worker/v2"
+	"github.com/juju/worker/v2/catacomb"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/robfig/cron.v2"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+)
+
+// ScheduledBackupNotes are the notes recorded in the metadata of the
+// backups made by the worker. Only backups with these notes are removed
+// by the retention policy.
+const ScheduledBackupNotes = "scheduled backup"
+
+// Logger defines the methods needed for the worker to log messages.
+type Logger interface {
+	Debugf(string, ...interface{})
+	Infof(string, ...interface{})
+	Warningf(string, ...interface{})
+	Errorf(string, ...interface{})
+}
+
+// Backend provides the controller state needed by the worker.
+type Backend interface {
+	ControllerConfig() (controller.Config, error)
+	WatchControllerConfig() state.NotifyWatcher
+	ScheduledBackupStatus() (state.ScheduledBackupStatus, error)
+	SetScheduledBackupStatus(state.ScheduledBackupStatus) error
+}
+
+// Backups creates and manages the backups in the controller's backup
+// storage.
+type Backups interface {
+	// Create creates and stores a backup of the controller with the
+	// given notes, returning its metadata.
+	Create(notes string) (*backups.Metadata, error)
+
+	// List returns the metadata of all the stored backups.
+	List() ([]*backups.Metadata, error)
+
+	// Get returns the identified backup's archive.
+	Get(id string) (*backups.Metadata, io.ReadCloser, error)
+
+	// Remove deletes the identified backup from storage.
+	Remove(id string) error
+}
+
+// Config defines the resources the worker needs to run.
+type Config struct {
+	Backend              Backend
+	Backups              Backups
+	Clock                clock.Clock
+	Logger               Logger
+	PrometheusRegisterer prometheus.Registerer
+
+	// NewUploaders returns the uploaders each scheduled backup is
+	// copied with, as configured in the controller config.
+	NewUploaders func(controller.Config) ([]Uploader, error)
+}
+
+// Validate checks that this config can be used.
+func (config Config) Validate() error {
+	if config.Backend == nil {
+		return errors.NotValidf("nil Backend")
+	}
+	if config.Backups == nil {
+		return errors.NotValidf("nil Backups")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Logger == nil {
+		return errors.NotValidf("nil Logger")
+	}
+	if config.PrometheusRegisterer == nil {
+		return errors.NotValidf("nil PrometheusRegisterer")
+	}
+	if config.NewUploaders == nil {
+		return errors.NotValidf("nil NewUploaders")
+	}
+	return nil
+}
+
+// NewWorker returns a worker that creates backups on the schedule in
+// controller config.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &schedulerWorker{
+		config:  config,
+		metrics: newCollector(),
+	}
+	if err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type schedulerWorker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+	metrics  *collector
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *schedulerWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *schedulerWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *schedulerWorker) loop() error {
+	_ = w.config.PrometheusRegisterer.Register(w.metrics)
+	defer w.config.PrometheusRegisterer.Unregister(w.metrics)
+
+	status, err := w.config.Backend.ScheduledBackupStatus()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	w.metrics.setStatus(status)
+
+	watcher := w.config.Backend.WatchControllerConfig()
+	if err := w.catacomb.Add(watcher); err != nil {
+		return errors.Trace(err)
+	}
+
+	var (
+		cfg      controller.Config
+		schedule cron.Schedule
+		timer    <-chan time.Time
+	)
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case _, ok := <-watcher.Changes():
+			if !ok {
+				return errors.New("controller config watcher closed")
+			}
+			cfg, err = w.config.Backend.ControllerConfig()
+			if err != nil {
+				return errors.Trace(err)
+			}
+			schedule = nil
+			if spec := cfg.BackupSchedule(); spec != "" {
+				// The spec has already been validated.
+				if schedule, err = cron.Parse(spec); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			w.metrics.setEnabled(schedule != nil)
+		case <-timer:
+			if err := w.backUp(cfg); err != nil {
+				return errors.Trace(err)
+			}
+		}
+
+		next, err := w.scheduleNext(schedule)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		timer = nil
+		if !next.IsZero() {
+			timer = w.config.Clock.After(next.Sub(w.config.Clock.Now()))
+		}
+	}
+}
+
+// scheduleNext records when the next backup is due according to the
+// schedule, and returns it. It returns the zero time if there is no
+// schedule.
+func (w *schedulerWorker) scheduleNext(schedule cron.Schedule) (time.Time, error) {
+	var next time.Time
+	if schedule != nil {
+		next = schedule.Next(w.config.Clock.Now()).UTC()
+	}
+	status, err := w.config.Backend.ScheduledBackupStatus()
+	if err != nil {
+		return time.Time{}, errors.Trace(err)
+	}
+	if !status.NextRun.Equal(next) {
+		status.NextRun = next
+		if err := w.config.Backend.SetScheduledBackupStatus(status); err != nil {
+			return time.Time{}, errors.Trace(err)
+		}
+	}
+	if schedule != nil {
+		w.config.Logger.Debugf("next scheduled backup at %s", next.Format(time.RFC3339))
+	}
+	return next, nil
+}
+
+// backUp creates a backup and copies it with the configured uploaders,
+// then applies the retention policy. The outcome is recorded in the
+// scheduled backup status; only a failure to record it is returned.
+func (w *schedulerWorker) backUp(cfg controller.Config) error {
+	started := w.config.Clock.Now().UTC()
+	w.config.Logger.Infof("creating scheduled backup")
+	meta, err := w.createAndCopy(cfg)
+	elapsed := w.config.Clock.Now().Sub(started)
+
+	status, serr := w.config.Backend.ScheduledBackupStatus()
+	if serr != nil {
+		return errors.Trace(serr)
+	}
+	status.LastAttempt = started
+	if meta != nil {
+		status.LastBackupID = meta.ID()
+	}
+	if err != nil {
+		w.config.Logger.Errorf("scheduled backup failed: %v", err)
+		status.LastFailure = started
+		status.LastError = err.Error()
+		w.metrics.recordFailure(started, elapsed)
+	} else {
+		w.config.Logger.Infof("created scheduled backup %s", meta.ID())
+		status.LastSuccess = started
+		w.metrics.recordSuccess(started, elapsed, meta.Size())
+	}
+	if err := w.config.Backend.SetScheduledBackupStatus(status); err != nil {
+		return errors.Trace(err)
+	}
+
+	if meta != nil {
+		if err := w.prune(cfg, meta.ID()); err != nil {
+			w.config.Logger.Warningf("cannot remove old scheduled backups: %v", err)
+		}
+	}
+	return nil
+}
+
+// createAndCopy creates a backup and copies it with the configured
+// uploaders. The metadata is returned if the backup was created, even
+// if it couldn't be copied.
+func (w *schedulerWorker) createAndCopy(cfg controller.Config) (*backups.Metadata, error) {
+	uploaders, err := w.config.NewUploaders(cfg)
+	if err != nil {
+		return nil, errors.Annotate(err, "configuring backup copies")
+	}
+	meta, err := w.config.Backups.Create(ScheduledBackupNotes)
+	if err != nil {
+		return nil, errors.Annotate(err, "creating backup")
+	}
+	name := meta.Started.UTC().Format(backups.FilenameTemplate)
+	for _, uploader := range uploaders {
+		if err := w.upload(uploader, meta.ID(), name); err != nil {
+			return meta, errors.Annotatef(err, "copying backup %s to %s", meta.ID(), uploader)
+		}
+		w.config.Logger.Debugf("copied backup %s to %s", meta.ID(), uploader)
+	}
+	return meta, nil
+}
+
+func (w *schedulerWorker) upload(uploader Uploader, id, name string) error {
+	_, archive, err := w.config.Backups.Get(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archive.Close()
+	return errors.Trace(uploader.Upload(name, archive))
+}
+
+// prune removes the scheduled backups beyond the retention count or
+// older than the retention age from backup storage. The backup with
+// the given ID, just created, is always kept.
+func (w *schedulerWorker) prune(cfg controller.Config, keepID string) error {
+	all, err := w.config.Backups.List()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var scheduled []*backups.Metadata
+	for _, meta := range all {
+		if meta.Notes == ScheduledBackupNotes {
+			scheduled = append(scheduled, meta)
+		}
+	}
+	// Newest first.
+	sort.Slice(scheduled, func(i, j int) bool {
+		return scheduled[i].Started.After(scheduled[j].Started)
+	})
+
+	count := cfg.BackupRetentionCount()
+	var cutoff time.Time
+	if age := cfg.BackupRetentionAge(); age > 0 {
+		cutoff = w.config.Clock.Now().Add(-age)
+	}
+	for i, meta := range scheduled {
+		if meta.ID() == keepID {
+			continue
+		}
+		if i < count && (cutoff.IsZero() || !meta.Started.Before(cutoff)) {
+			continue
+		}
+		w.config.Logger.Infof("removing scheduled backup %s from %s", meta.ID(), meta.Started.Format(time.RFC3339))
+		if err := w.config.Backups.Remove(meta.ID()); err != nil {
+			return errors.Annotatef(err, "removing backup %s", meta.ID())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,448 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v2"
+	"github.com/juju/worker/v2/workertest"
+	"github.com/prometheus/client_golang/prometheus"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/backupscheduler"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+
+	clock     *testclock.Clock
+	backend   *fakeBackend
+	backups   *fakeBackups
+	uploader  *fakeUploader
+	registry  *prometheus.Registry
+	uploadErr error
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+var startTime = time.Date(2021, 5, 6, 7, 0, 0, 0, time.UTC)
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(startTime)
+	s.backend = &fakeBackend{
+		config:    controller.Config{},
+		changes:   make(chan struct{}, 1),
+		statusSet: make(chan state.ScheduledBackupStatus, 10),
+	}
+	s.backups = &fakeBackups{
+		clock: s.clock,
+	}
+	s.uploader = &fakeUploader{}
+	s.uploadErr = nil
+	s.registry = prometheus.NewRegistry()
+}
+
+func (s *WorkerSuite) newWorker(c *gc.C) worker.Worker {
+	w, err := backupscheduler.NewWorker(backupscheduler.Config{
+		Backend:              s.backend,
+		Backups:              s.backups,
+		Clock:                s.clock,
+		Logger:               loggo.GetLogger("test"),
+		PrometheusRegisterer: s.registry,
+		NewUploaders: func(cfg controller.Config) ([]backupscheduler.Uploader, error) {
+			if s.uploadErr != nil {
+				return nil, s.uploadErr
+			}
+			return []backupscheduler.Uploader{s.uploader}, nil
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) { workertest.DirtyKill(c, w) })
+	return w
+}
+
+func (s *WorkerSuite) setConfig(cfg controller.Config) {
+	s.backend.mu.Lock()
+	s.backend.config = cfg
+	s.backend.mu.Unlock()
+	s.backend.changes <- struct{}{}
+}
+
+func (s *WorkerSuite) nextStatus(c *gc.C) state.ScheduledBackupStatus {
+	select {
+	case status := <-s.backend.statusSet:
+		return status
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for status")
+	}
+	panic("unreachable")
+}
+
+func (s *WorkerSuite) gauge(c *gc.C, name string) float64 {
+	families, err := s.registry.Gather()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, family := range families {
+		if family.GetName() == name {
+			return family.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+	c.Fatalf("metric %q not found", name)
+	return 0
+}
+
+func (s *WorkerSuite) runs(c *gc.C, result string) float64 {
+	families, err := s.registry.Gather()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, family := range families {
+		if family.GetName() != "juju_backupscheduler_runs_total" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			if m.GetLabel()[0].GetValue() == result {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	_, err := backupscheduler.NewWorker(backupscheduler.Config{})
+	c.Assert(err, gc.ErrorMatches, "nil Backend not valid")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *WorkerSuite) TestNoSchedule(c *gc.C) {
+	s.backend.status.NextRun = startTime
+	w := s.newWorker(c)
+	s.setConfig(controller.Config{})
+
+	status := s.nextStatus(c)
+	c.Assert(status.NextRun.IsZero(), jc.IsTrue)
+
+	err := s.clock.WaitAdvance(24*time.Hour, coretesting.ShortWait, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	s.backups.CheckNoCalls(c)
+	c.Assert(s.gauge(c, "juju_backupscheduler_enabled"), gc.Equals, float64(0))
+	workertest.CleanKill(c, w)
+}
+
+func (s *WorkerSuite) TestScheduledBackup(c *gc.C) {
+	w := s.newWorker(c)
+	s.setConfig(controller.Config{
+		controller.BackupSchedule: "@every 1h",
+	})
+
+	status := s.nextStatus(c)
+	c.Assert(status.NextRun, gc.Equals, startTime.Add(time.Hour))
+	c.Assert(s.gauge(c, "juju_backupscheduler_enabled"), gc.Equals, float64(1))
+
+	err := s.clock.WaitAdvance(time.Hour, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	started := startTime.Add(time.Hour)
+	status = s.nextStatus(c)
+	c.Assert(status, jc.DeepEquals, state.ScheduledBackupStatus{
+		LastAttempt:  started,
+		LastSuccess:  started,
+		LastBackupID: "backup-0",
+		NextRun:      started,
+	})
+	status = s.nextStatus(c)
+	c.Assert(status.NextRun, gc.Equals, started.Add(time.Hour))
+
+	s.backups.CheckCall(c, 0, "Create", backupscheduler.ScheduledBackupNotes)
+	s.backups.CheckCall(c, 1, "Get", "backup-0")
+	c.Assert(s.uploader.uploads, jc.DeepEquals, map[string]string{
+		"juju-backup-20210506-080000.tar.gz": "archive backup-0",
+	})
+
+	c.Assert(s.gauge(c, "juju_backupscheduler_last_success_timestamp_seconds"), gc.Equals, float64(started.Unix()))
+	c.Assert(s.gauge(c, "juju_backupscheduler_last_size_bytes"), gc.Equals, float64(1024))
+	c.Assert(s.runs(c, "success"), gc.Equals, float64(1))
+	workertest.CleanKill(c, w)
+}
+
+func (s *WorkerSuite) TestScheduleChange(c *gc.C) {
+	w := s.newWorker(c)
+	s.setConfig(controller.Config{
+		controller.BackupSchedule: "@every 1h",
+	})
+	status := s.nextStatus(c)
+	c.Assert(status.NextRun, gc.Equals, startTime.Add(time.Hour))
+
+	s.setConfig(controller.Config{
+		controller.BackupSchedule: "0 3 * * *",
+	})
+	status = s.nextStatus(c)
+	c.Assert(status.NextRun.In(time.Local).Hour(), gc.Equals, 3)
+
+	s.setConfig(controller.Config{})
+	status = s.nextStatus(c)
+	c.Assert(status.NextRun.IsZero(), jc.IsTrue)
+	workertest.CleanKill(c, w)
+}
+
+func (s *WorkerSuite) TestCreateFailure(c *gc.C) {
+	s.backups.SetErrors(errors.New("no space left on device"))
+	w := s.newWorker(c)
+	s.setConfig(controller.Config{
+		controller.BackupSchedule: "@every 1h",
+	})
+	s.nextStatus(c)
+
+	err := s.clock.WaitAdvance(time.Hour, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	started := startTime.Add(time.Hour)
+	status := s.nextStatus(c)
+	c.Assert(status, jc.DeepEquals, state.ScheduledBackupStatus{
+		LastAttempt: started,
+		LastFailure: started,
+		LastError:   "creating backup: no space left on device",
+		NextRun:     started,
+	})
+	s.nextStatus(c)
+	s.backups.CheckCallNames(c, "Create")
+	c.Assert(s.gauge(c, "juju_backupscheduler_last_failure_timestamp_seconds"), gc.Equals, float64(started.Unix()))
+	c.Assert(s.runs(c, "failure"), gc.Equals, float64(1))
+	workertest.CleanKill(c, w)
+}
+
+func (s *WorkerSuite) TestUploadFailure(c *gc.C) {
+	s.uploader.err = errors.New("bucket not found")
+	w := s.newWorker(c)
+	s.setConfig(controller.Config{
+		controller.BackupSchedule: "@every 1h",
+	})
+	s.nextStatus(c)
+
+	err := s.clock.WaitAdvance(time.Hour, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	status := s.nextStatus(c)
+	c.Assert(status.LastBackupID, gc.Equals, "backup-0")
+	c.Assert(status.LastSuccess.IsZero(), jc.IsTrue)
+	c.Assert(status.LastError, gc.Equals, `copying backup backup-0 to fake uploader: bucket not found`)
+	workertest.CleanKill(c, w)
+}
+
+func (s *WorkerSuite) TestRetention(c *gc.C) {
+	// Scheduled backups from the previous days, and one made by hand.
+	for i := 1; i <= 4; i++ {
+		s.backups.add(fmt.Sprintf("backup-%d", i), startTime.Add(time.Duration(-i)*24*time.Hour), backupscheduler.ScheduledBackupNotes)
+	}
+	s.backups.add("backup-5", startTime.Add(-30*24*time.Hour), "before upgrade")
+
+	w := s.newWorker(c)
+	s.setConfig(controller.Config{
+		controller.BackupSchedule:       "@every 1h",
+		controller.BackupRetentionCount: 3,
+	})
+	s.nextStatus(c)
+	err := s.clock.WaitAdvance(time.Hour, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.nextStatus(c)
+	s.nextStatus(c)
+
+	// The new backup and the two most recent are kept.
+	c.Assert(s.backups.ids(), jc.SameContents, []string{"backup-0", "backup-1", "backup-2", "backup-5"})
+	workertest.CleanKill(c, w)
+}
+
+func (s *WorkerSuite) TestRetentionAge(c *gc.C) {
+	for i := 1; i <= 4; i++ {
+		s.backups.add(fmt.Sprintf("backup-%d", i), startTime.Add(time.Duration(-i)*24*time.Hour), backupscheduler.ScheduledBackupNotes)
+	}
+
+	w := s.newWorker(c)
+	s.setConfig(controller.Config{
+		controller.BackupSchedule:     "@every 1h",
+		controller.BackupRetentionAge: 49 * time.Hour,
+	})
+	s.nextStatus(c)
+	err := s.clock.WaitAdvance(time.Hour, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.nextStatus(c)
+	s.nextStatus(c)
+
+	// Only backups from the last 49 hours are kept.
+	c.Assert(s.backups.ids(), jc.SameContents, []string{"backup-0", "backup-1", "backup-2"})
+	workertest.CleanKill(c, w)
+}
+
+func (s *WorkerSuite) TestUploadersError(c *gc.C) {
+	s.uploadErr = errors.New("bad S3 config")
+	w := s.newWorker(c)
+	s.setConfig(controller.Config{
+		controller.BackupSchedule: "@every 1h",
+	})
+	s.nextStatus(c)
+	err := s.clock.WaitAdvance(time.Hour, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	status := s.nextStatus(c)
+	c.Assert(status.LastError, gc.Equals, "configuring backup copies: bad S3 config")
+	s.backups.CheckNoCalls(c)
+	workertest.CleanKill(c, w)
+}
+
+type fakeBackend struct {
+	mu        sync.Mutex
+	config    controller.Config
+	status    state.ScheduledBackupStatus
+	changes   chan struct{}
+	statusSet chan state.ScheduledBackupStatus
+}
+
+func (b *fakeBackend) ControllerConfig() (controller.Config, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.config, nil
+}
+
+func (b *fakeBackend) WatchControllerConfig() state.NotifyWatcher {
+	return &fakeWatcher{
+		Worker:  workertest.NewErrorWorker(nil),
+		changes: b.changes,
+	}
+}
+
+func (b *fakeBackend) ScheduledBackupStatus() (state.ScheduledBackupStatus, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.status, nil
+}
+
+func (b *fakeBackend) SetScheduledBackupStatus(status state.ScheduledBackupStatus) error {
+	b.mu.Lock()
+	b.status = status
+	b.mu.Unlock()
+	b.statusSet <- status
+	return nil
+}
+
+type fakeWatcher struct {
+	worker.Worker
+	changes chan struct{}
+}
+
+func (w *fakeWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+func (w *fakeWatcher) Stop() error {
+	w.Kill()
+	return w.Wait()
+}
+
+func (w *fakeWatcher) Err() error {
+	return nil
+}
+
+type fakeBackups struct {
+	testing.Stub
+	clock *testclock.Clock
+
+	mu     sync.Mutex
+	stored []*backups.Metadata
+}
+
+func (b *fakeBackups) add(id string, started time.Time, notes string) *backups.Metadata {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	meta := backups.NewMetadata()
+	meta.Started = started
+	meta.Notes = notes
+	_ = meta.MarkComplete(1024, "checksum")
+	meta.SetID(id)
+	b.stored = append(b.stored, meta)
+	return meta
+}
+
+func (b *fakeBackups) ids() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var ids []string
+	for _, meta := range b.stored {
+		ids = append(ids, meta.ID())
+	}
+	return ids
+}
+
+func (b *fakeBackups) Create(notes string) (*backups.Metadata, error) {
+	b.MethodCall(b, "Create", notes)
+	if err := b.NextErr(); err != nil {
+		return nil, err
+	}
+	return b.add("backup-0", b.clock.Now(), notes), nil
+}
+
+func (b *fakeBackups) List() ([]*backups.Metadata, error) {
+	b.MethodCall(b, "List")
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*backups.Metadata(nil), b.stored...), b.NextErr()
+}
+
+func (b *fakeBackups) Get(id string) (*backups.Metadata, io.ReadCloser, error) {
+	b.MethodCall(b, "Get", id)
+	if err := b.NextErr(); err != nil {
+		return nil, nil, err
+	}
+	return nil, ioutil.NopCloser(bytes.NewBufferString("archive " + id)), nil
+}
+
+func (b *fakeBackups) Remove(id string) error {
+	b.MethodCall(b, "Remove", id)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, meta := range b.stored {
+		if meta.ID() == id {
+			b.stored = append(b.stored[:i], b.stored[i+1:]...)
+			break
+		}
+	}
+	return b.NextErr()
+}
+
+type fakeUploader struct {
+	uploads map[string]string
+	err     error
+}
+
+func (u *fakeUploader) String() string {
+	return "fake uploader"
+}
+
+func (u *fakeUploader) Upload(name string, archive io.Reader) error {
+	if u.err != nil {
+		return u.err
+	}
+	data, err := ioutil.ReadAll(archive)
+	if err != nil {
+		return err
+	}
+	if u.uploads == nil {
+		u.uploads = make(map[string]string)
+	}
+	u.uploads[name] = string(data)
+	return nil
+}
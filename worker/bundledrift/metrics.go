@@ -0,0 +1,120 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundledrift
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/juju/juju/state"
+)
+
+const (
+	metricsNamespace = "juju_bundledrift"
+
+	modelUUIDLabel = "model_uuid"
+	modelNameLabel = "model_name"
+)
+
+// collector is a prometheus.Collector that collects metrics about the
+// drift of the models from their desired bundles.
+type collector struct {
+	drifted     *prometheus.GaugeVec
+	differences *prometheus.GaugeVec
+	failed      *prometheus.GaugeVec
+	lastChecked *prometheus.GaugeVec
+
+	mu     sync.Mutex
+	models map[string]string
+}
+
+func newCollector() *collector {
+	labels := []string{modelUUIDLabel, modelNameLabel}
+	return &collector{
+		drifted: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "drifted",
+			Help:      "Whether the model differs from its desired bundle (1) or not (0).",
+		}, labels),
+		differences: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "differences",
+			Help:      "The number of applications, machines and relations differing from the desired bundle.",
+		}, labels),
+		failed: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "check_failed",
+			Help:      "Whether the model couldn't be compared with its desired bundle (1) or could (0).",
+		}, labels),
+		lastChecked: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "last_check_timestamp_seconds",
+			Help:      "The time the model was last compared with its desired bundle.",
+		}, labels),
+		models: make(map[string]string),
+	}
+}
+
+// Describe is part of the prometheus.Collector interface.
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	c.drifted.Describe(ch)
+	c.differences.Describe(ch)
+	c.failed.Describe(ch)
+	c.lastChecked.Describe(ch)
+}
+
+// Collect is part of the prometheus.Collector interface.
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	c.drifted.Collect(ch)
+	c.differences.Collect(ch)
+	c.failed.Collect(ch)
+	c.lastChecked.Collect(ch)
+}
+
+// record sets the metrics of the model from its drift.
+func (c *collector) record(bundle DesiredBundle, drift state.BundleDrift, count int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if name, ok := c.models[bundle.ModelUUID]; ok && name != bundle.ModelName {
+		c.delete(bundle.ModelUUID, name)
+	}
+	c.models[bundle.ModelUUID] = bundle.ModelName
+
+	labels := prometheus.Labels{
+		modelUUIDLabel: bundle.ModelUUID,
+		modelNameLabel: bundle.ModelName,
+	}
+	c.drifted.With(labels).Set(boolValue(drift.Drifted()))
+	c.differences.With(labels).Set(float64(count))
+	c.failed.With(labels).Set(boolValue(drift.Error != ""))
+	c.lastChecked.With(labels).Set(float64(drift.Checked.UnixNano()) / 1e9)
+}
+
+// prune removes the metrics of the models which weren't checked, as
+// they no longer exist or no longer have a desired bundle.
+func (c *collector) prune(checked map[string]bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for uuid, name := range c.models {
+		if !checked[uuid] {
+			c.delete(uuid, name)
+			delete(c.models, uuid)
+		}
+	}
+}
+
+func (c *collector) delete(uuid, name string) {
+	c.drifted.DeleteLabelValues(uuid, name)
+	c.differences.DeleteLabelValues(uuid, name)
+	c.failed.DeleteLabelValues(uuid, name)
+	c.lastChecked.DeleteLabelValues(uuid, name)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
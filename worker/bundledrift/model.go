@@ -0,0 +1,229 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundledrift
+
+import (
+	"github.com/juju/bundlechanges/v4"
+	"github.com/juju/charm/v8"
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	appbundle "github.com/juju/juju/cmd/juju/application/bundle"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/state"
+)
+
+// modelRepresentation reads the deployment of the model from state and
+// builds its representation with the same builder diff-bundle uses, so
+// that drift is reported for exactly the differences diff-bundle would
+// show. The model is read in the same terms as the status and
+// application facades serve it to diff-bundle.
+func modelRepresentation(st *state.State) (*bundlechanges.Model, error) {
+	status, err := modelStatus(st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return appbundle.BuildModelRepresentation(status, stateExtractor{st: st}, true, nil)
+}
+
+// modelStatus returns the parts of the model's full status read by
+// appbundle.BuildModelRepresentation.
+func modelStatus(st *state.State) (*params.FullStatus, error) {
+	m, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	spaces, err := st.AllSpaceInfos()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	status := &params.FullStatus{
+		Machines:     make(map[string]params.MachineStatus),
+		Applications: make(map[string]params.ApplicationStatus),
+	}
+
+	machines, err := st.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, machine := range machines {
+		// As in status, containers are nested in their host machines,
+		// which is all diff-bundle sees of them.
+		if machine.ContainerType() != "" {
+			continue
+		}
+		status.Machines[machine.Id()] = params.MachineStatus{
+			Id:     machine.Id(),
+			Series: machine.Series(),
+		}
+	}
+
+	relations, err := st.AllRelations()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, relation := range relations {
+		var endpoints []params.EndpointStatus
+		for _, ep := range relation.Endpoints() {
+			endpoints = append(endpoints, params.EndpointStatus{
+				ApplicationName: ep.ApplicationName,
+				Name:            ep.Name,
+			})
+		}
+		status.Relations = append(status.Relations, params.RelationStatus{
+			Id:        relation.Id(),
+			Endpoints: endpoints,
+		})
+	}
+
+	applications, err := st.AllApplications()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, app := range applications {
+		appStatus := params.ApplicationStatus{
+			Exposed: app.IsExposed(),
+			Series:  app.Series(),
+		}
+		if curl, _ := app.CharmURL(); curl != nil {
+			appStatus.Charm = curl.String()
+		}
+		if m.Type() == state.ModelTypeCAAS {
+			appStatus.Scale = app.GetScale()
+		}
+		for endpoint, exposed := range app.ExposedEndpoints() {
+			if appStatus.ExposedEndpoints == nil {
+				appStatus.ExposedEndpoints = make(map[string]params.ExposedEndpoint)
+			}
+			var spaceNames []string
+			for _, id := range exposed.ExposeToSpaceIDs {
+				if space := spaces.GetByID(id); space != nil {
+					spaceNames = append(spaceNames, string(space.Name))
+				}
+			}
+			appStatus.ExposedEndpoints[endpoint] = params.ExposedEndpoint{
+				ExposeToSpaces: spaceNames,
+				ExposeToCIDRs:  exposed.ExposeToCIDRs,
+			}
+		}
+
+		if app.IsPrincipal() {
+			units, err := app.AllUnits()
+			if err != nil {
+				return nil, errors.Annotatef(err, "reading units of application %q", app.Name())
+			}
+			appStatus.Units = make(map[string]params.UnitStatus)
+			for _, unit := range units {
+				machine, err := unit.AssignedMachineId()
+				if err != nil && !errors.IsNotAssigned(err) {
+					return nil, errors.Trace(err)
+				}
+				appStatus.Units[unit.Name()] = params.UnitStatus{Machine: machine}
+			}
+		}
+
+		// Subordinates are subordinate to the applications they have
+		// container scoped relations with.
+		subordinateTo := set.NewStrings()
+		if !app.IsPrincipal() {
+			for _, relation := range relations {
+				ep, err := relation.Endpoint(app.Name())
+				if errors.IsNotFound(err) {
+					continue
+				} else if err != nil {
+					return nil, errors.Trace(err)
+				}
+				if ep.Scope != charm.ScopeContainer {
+					continue
+				}
+				related, err := relation.RelatedEndpoints(app.Name())
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				for _, other := range related {
+					subordinateTo.Add(other.ApplicationName)
+				}
+			}
+		}
+		appStatus.SubordinateTo = subordinateTo.SortedValues()
+		status.Applications[app.Name()] = appStatus
+	}
+	return status, nil
+}
+
+// stateExtractor implements appbundle.ModelExtractor by reading the
+// model from state, as the application and annotations facades do for
+// diff-bundle.
+type stateExtractor struct {
+	st *state.State
+}
+
+// GetAnnotations is part of appbundle.ModelExtractor. Annotations are
+// not compared, so none are read.
+func (stateExtractor) GetAnnotations([]string) ([]params.AnnotationsGetResult, error) {
+	return nil, nil
+}
+
+// GetConstraints is part of appbundle.ModelExtractor.
+func (e stateExtractor) GetConstraints(applications ...string) ([]constraints.Value, error) {
+	values := make([]constraints.Value, len(applications))
+	for i, name := range applications {
+		app, err := e.st.Application(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if values[i], err = app.Constraints(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return values, nil
+}
+
+// GetConfig is part of appbundle.ModelExtractor.
+func (e stateExtractor) GetConfig(branchName string, applications ...string) ([]map[string]interface{}, error) {
+	values := make([]map[string]interface{}, len(applications))
+	for i, name := range applications {
+		app, err := e.st.Application(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ch, _, err := app.Charm()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		settings, err := app.CharmConfig(branchName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		values[i] = describeCharmConfig(settings, ch.Config())
+	}
+	return values, nil
+}
+
+// Sequences is part of appbundle.ModelExtractor.
+func (e stateExtractor) Sequences() (map[string]int, error) {
+	return e.st.Sequences()
+}
+
+// describeCharmConfig describes the charm config of an application as
+// the application facade does, saying whether each value was set by a
+// user or is the charm's default.
+func describeCharmConfig(settings charm.Settings, config *charm.Config) map[string]interface{} {
+	results := make(map[string]interface{})
+	for name, option := range config.Options {
+		info := map[string]interface{}{
+			"source": "unset",
+		}
+		if value := settings[name]; value != nil && option.Default != value {
+			info["value"] = value
+			info["source"] = "user"
+		} else if option.Default != nil {
+			info["value"] = option.Default
+			info["source"] = "default"
+		}
+		results[name] = info
+	}
+	return results
+}
@@ -0,0 +1,37 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundledrift
+
+import (
+	"github.com/juju/charm/v8"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type modelSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&modelSuite{})
+
+func (s *modelSuite) TestDescribeCharmConfig(c *gc.C) {
+	config := &charm.Config{Options: map[string]charm.Option{
+		"port":    {Type: "int", Default: 3306},
+		"name":    {Type: "string", Default: "db"},
+		"comment": {Type: "string"},
+		"tuning":  {Type: "string"},
+	}}
+	settings := charm.Settings{
+		"port":   4406,
+		"name":   "db",
+		"tuning": "fast",
+	}
+	c.Assert(describeCharmConfig(settings, config), jc.DeepEquals, map[string]interface{}{
+		"port":    map[string]interface{}{"value": 4406, "source": "user"},
+		"name":    map[string]interface{}{"value": "db", "source": "default"},
+		"comment": map[string]interface{}{"source": "unset"},
+		"tuning":  map[string]interface{}{"value": "fast", "source": "user"},
+	})
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundledrift
+
+import (
+	"github.com/juju/bundlechanges/v4"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/state"
+)
+
+// This file contains untested shims to let us wrap state in a sensible
+// interface and avoid writing tests that depend on mongodb. If you were
+// to change any part of it so that it were no longer *obviously* and
+// *trivially* correct, you would be Doing It Wrong.
+
+// statePoolShim gets the desired bundles of the models in the state
+// pool, and the models to compare with them.
+type statePoolShim struct {
+	pool *state.StatePool
+}
+
+// DesiredBundles is part of the Backend interface.
+func (s statePoolShim) DesiredBundles() ([]DesiredBundle, error) {
+	uuids, err := s.pool.SystemState().AllModelUUIDs()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []DesiredBundle
+	for _, uuid := range uuids {
+		bundle, err := s.desiredBundle(uuid)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		result = append(result, bundle)
+	}
+	return result, nil
+}
+
+func (s statePoolShim) desiredBundle(modelUUID string) (DesiredBundle, error) {
+	model, ph, err := s.pool.GetModel(modelUUID)
+	if err != nil {
+		return DesiredBundle{}, errors.Trace(err)
+	}
+	defer ph.Release()
+
+	desired, err := model.DesiredBundle()
+	if err != nil {
+		return DesiredBundle{}, errors.Trace(err)
+	}
+	return DesiredBundle{
+		ModelUUID: modelUUID,
+		ModelName: model.Name(),
+		Bundle:    desired.Bundle,
+		Overlays:  desired.Overlays,
+	}, nil
+}
+
+// Model is part of the Backend interface.
+func (s statePoolShim) Model(modelUUID string) (*bundlechanges.Model, error) {
+	st, err := s.pool.Get(modelUUID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer st.Release()
+	return modelRepresentation(st.State)
+}
+
+// SetBundleDrift is part of the Backend interface.
+func (s statePoolShim) SetBundleDrift(modelUUID string, drift state.BundleDrift) error {
+	model, ph, err := s.pool.GetModel(modelUUID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer ph.Release()
+	return model.SetBundleDrift(drift)
+}
@@ -0,0 +1,198 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package bundledrift provides a worker that periodically compares each
+// model on the controller which has a desired bundle with that bundle,
+// and records how the model has drifted from it.
+package bundledrift
+
+import (
+	"time"
+
+	"github.com/juju/bundlechanges/v4"
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/worker/v2"
+	"github.com/juju/worker/v2/catacomb"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/juju/juju/core/bundledrift"
+	"github.com/juju/juju/state"
+)
+
+// DefaultInterval is how often the models are compared with their
+// desired bundles.
+const DefaultInterval = 5 * time.Minute
+
+// Logger defines the methods needed for the worker to log messages.
+type Logger interface {
+	Tracef(string, ...interface{})
+	Debugf(string, ...interface{})
+	Warningf(string, ...interface{})
+}
+
+// DesiredBundle is the bundle a model is expected to match.
+type DesiredBundle struct {
+	ModelUUID string
+	ModelName string
+	Bundle    string
+	Overlays  []string
+}
+
+// Backend provides the desired bundles of the controller's models, and
+// the models to compare with them.
+type Backend interface {
+	// DesiredBundles returns the desired bundles of the models which
+	// have one.
+	DesiredBundles() ([]DesiredBundle, error)
+
+	// Model returns the deployment of the model, to be compared with
+	// its desired bundle.
+	Model(modelUUID string) (*bundlechanges.Model, error)
+
+	// SetBundleDrift records the drift of the model from its desired
+	// bundle.
+	SetBundleDrift(modelUUID string, drift state.BundleDrift) error
+}
+
+// Config defines the resources the worker needs to run.
+type Config struct {
+	Backend              Backend
+	Clock                clock.Clock
+	Logger               Logger
+	PrometheusRegisterer prometheus.Registerer
+
+	// Interval is how often the models are compared with their desired
+	// bundles.
+	Interval time.Duration
+}
+
+// Validate checks that this config can be used.
+func (config Config) Validate() error {
+	if config.Backend == nil {
+		return errors.NotValidf("nil Backend")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Logger == nil {
+		return errors.NotValidf("nil Logger")
+	}
+	if config.PrometheusRegisterer == nil {
+		return errors.NotValidf("nil PrometheusRegisterer")
+	}
+	if config.Interval <= 0 {
+		return errors.NotValidf("non-positive Interval")
+	}
+	return nil
+}
+
+// NewWorker returns a worker that compares the controller's models with
+// their desired bundles.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &driftWorker{
+		config:  config,
+		metrics: newCollector(),
+	}
+	if err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type driftWorker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+	metrics  *collector
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *driftWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *driftWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *driftWorker) loop() error {
+	_ = w.config.PrometheusRegisterer.Register(w.metrics)
+	defer w.config.PrometheusRegisterer.Unregister(w.metrics)
+
+	// The models are checked as soon as the worker starts.
+	timer := w.config.Clock.After(0)
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case <-timer:
+			if err := w.checkModels(); err != nil {
+				return errors.Trace(err)
+			}
+			timer = w.config.Clock.After(w.config.Interval)
+		}
+	}
+}
+
+// checkModels compares each model having a desired bundle with it, and
+// records the drift.
+func (w *driftWorker) checkModels() error {
+	desired, err := w.config.Backend.DesiredBundles()
+	if err != nil {
+		return errors.Annotate(err, "getting desired bundles")
+	}
+	checked := make(map[string]bool)
+	for _, bundle := range desired {
+		drift, count := w.checkModel(bundle)
+		err := w.config.Backend.SetBundleDrift(bundle.ModelUUID, drift)
+		if errors.IsNotFound(err) {
+			// The desired bundle or the model was removed while
+			// the model was being checked.
+			continue
+		} else if err != nil {
+			w.config.Logger.Warningf("cannot record bundle drift of model %q: %v", bundle.ModelName, err)
+			continue
+		}
+		checked[bundle.ModelUUID] = true
+		w.metrics.record(bundle, drift, count)
+	}
+	w.metrics.prune(checked)
+	return nil
+}
+
+// checkModel compares the model with its desired bundle, returning the
+// drift and the number of differences.
+func (w *driftWorker) checkModel(bundle DesiredBundle) (state.BundleDrift, int) {
+	drift := state.BundleDrift{Checked: w.config.Clock.Now().UTC()}
+	diff, err := w.diff(bundle)
+	if err != nil {
+		w.config.Logger.Debugf("cannot compare model %q with its desired bundle: %v", bundle.ModelName, err)
+		drift.Error = err.Error()
+		return drift, 0
+	}
+	if drift.Diff, err = bundledrift.Format(diff); err != nil {
+		drift.Error = err.Error()
+		return drift, 0
+	}
+	count := bundledrift.Count(diff)
+	if count > 0 {
+		w.config.Logger.Debugf("model %q has drifted from its desired bundle: %d differences", bundle.ModelName, count)
+	}
+	return drift, count
+}
+
+func (w *driftWorker) diff(bundle DesiredBundle) (*bundlechanges.BundleDiff, error) {
+	model, err := w.config.Backend.Model(bundle.ModelUUID)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading model")
+	}
+	diff, err := bundledrift.Diff(bundle.Bundle, bundle.Overlays, model, w.config.Logger)
+	return diff, errors.Trace(err)
+}
@@ -0,0 +1,305 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package bundledrift_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/bundlechanges/v4"
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v2"
+	"github.com/juju/worker/v2/workertest"
+	"github.com/prometheus/client_golang/prometheus"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/bundledrift"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+
+	clock    *testclock.Clock
+	backend  *fakeBackend
+	registry *prometheus.Registry
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+var startTime = time.Date(2021, 5, 6, 7, 0, 0, 0, time.UTC)
+
+const (
+	uuid1 = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+	uuid2 = "deadbeef-0bad-400d-8000-4b1d0d06f00e"
+)
+
+const bundle = `
+applications:
+  mysql:
+    charm: cs:mysql-58
+    num_units: 1
+    to: ["0"]
+machines:
+  "0": {}
+`
+
+func matchingModel() *bundlechanges.Model {
+	return &bundlechanges.Model{
+		Applications: map[string]*bundlechanges.Application{
+			"mysql": {
+				Name:  "mysql",
+				Charm: "cs:mysql-58",
+				Units: []bundlechanges.Unit{{Name: "mysql/0", Machine: "0"}},
+			},
+		},
+		Machines: map[string]*bundlechanges.Machine{
+			"0": {ID: "0"},
+		},
+	}
+}
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(startTime)
+	s.backend = &fakeBackend{
+		desired: []bundledrift.DesiredBundle{{
+			ModelUUID: uuid1,
+			ModelName: "prod",
+			Bundle:    bundle,
+		}},
+		models: map[string]*bundlechanges.Model{
+			uuid1: matchingModel(),
+		},
+		drifts: make(chan recordedDrift, 10),
+	}
+	s.registry = prometheus.NewRegistry()
+}
+
+func (s *WorkerSuite) newWorker(c *gc.C) worker.Worker {
+	w, err := bundledrift.NewWorker(bundledrift.Config{
+		Backend:              s.backend,
+		Clock:                s.clock,
+		Logger:               loggo.GetLogger("test"),
+		PrometheusRegisterer: s.registry,
+		Interval:             time.Minute,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) { workertest.DirtyKill(c, w) })
+	return w
+}
+
+func (s *WorkerSuite) nextDrift(c *gc.C) recordedDrift {
+	select {
+	case drift := <-s.backend.drifts:
+		return drift
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for drift")
+	}
+	panic("unreachable")
+}
+
+// waitChecked waits for the worker to finish checking the models, and
+// schedule the next check.
+func (s *WorkerSuite) waitChecked(c *gc.C) {
+	err := s.clock.WaitAdvance(0, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// gauges returns the values of the named gauge, by model name.
+func (s *WorkerSuite) gauges(c *gc.C, name string) map[string]float64 {
+	families, err := s.registry.Gather()
+	c.Assert(err, jc.ErrorIsNil)
+	result := make(map[string]float64)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "model_name" {
+					result[label.GetValue()] = m.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+	return result
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	_, err := bundledrift.NewWorker(bundledrift.Config{})
+	c.Assert(err, gc.ErrorMatches, "nil Backend not valid")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+
+	_, err = bundledrift.NewWorker(bundledrift.Config{
+		Backend:              s.backend,
+		Clock:                s.clock,
+		Logger:               loggo.GetLogger("test"),
+		PrometheusRegisterer: s.registry,
+	})
+	c.Assert(err, gc.ErrorMatches, "non-positive Interval not valid")
+}
+
+func (s *WorkerSuite) TestNoDrift(c *gc.C) {
+	w := s.newWorker(c)
+	drift := s.nextDrift(c)
+	c.Check(drift.modelUUID, gc.Equals, uuid1)
+	c.Check(drift.drift, jc.DeepEquals, state.BundleDrift{Checked: startTime})
+	s.waitChecked(c)
+	c.Check(s.gauges(c, "juju_bundledrift_drifted"), jc.DeepEquals, map[string]float64{"prod": 0})
+	c.Check(s.gauges(c, "juju_bundledrift_differences"), jc.DeepEquals, map[string]float64{"prod": 0})
+	workertest.CleanKill(c, w)
+}
+
+func (s *WorkerSuite) TestDrift(c *gc.C) {
+	s.backend.setModel(uuid1, &bundlechanges.Model{
+		Applications: map[string]*bundlechanges.Application{
+			"mysql": {
+				Name:    "mysql",
+				Charm:   "cs:mysql-58",
+				Options: map[string]interface{}{"max-connections": 500},
+				Units:   []bundlechanges.Unit{{Name: "mysql/0", Machine: "0"}},
+			},
+		},
+		Machines: map[string]*bundlechanges.Machine{
+			"0": {ID: "0"},
+		},
+	})
+	s.newWorker(c)
+	drift := s.nextDrift(c)
+	c.Check(drift.drift.Drifted(), jc.IsTrue)
+	c.Check(drift.drift.Diff, gc.Equals, `
+applications:
+  mysql:
+    options:
+      max-connections:
+        bundle: null
+        model: 500
+`[1:])
+	c.Check(drift.drift.Error, gc.Equals, "")
+	s.waitChecked(c)
+	c.Check(s.gauges(c, "juju_bundledrift_drifted"), jc.DeepEquals, map[string]float64{"prod": 1})
+	c.Check(s.gauges(c, "juju_bundledrift_differences"), jc.DeepEquals, map[string]float64{"prod": 1})
+	c.Check(s.gauges(c, "juju_bundledrift_check_failed"), jc.DeepEquals, map[string]float64{"prod": 0})
+}
+
+func (s *WorkerSuite) TestChecksPeriodically(c *gc.C) {
+	s.newWorker(c)
+	s.nextDrift(c)
+
+	// The model is hand edited.
+	model := matchingModel()
+	model.Applications["mysql"].Units = append(model.Applications["mysql"].Units, bundlechanges.Unit{
+		Name: "mysql/1", Machine: "0",
+	})
+	s.backend.setModel(uuid1, model)
+
+	err := s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	drift := s.nextDrift(c)
+	c.Check(drift.drift.Checked, gc.Equals, startTime.Add(time.Minute))
+	c.Check(drift.drift.Drifted(), jc.IsTrue)
+	c.Check(drift.drift.Diff, gc.Equals, `
+applications:
+  mysql:
+    num_units:
+      bundle: 1
+      model: 2
+`[1:])
+}
+
+func (s *WorkerSuite) TestCompareError(c *gc.C) {
+	s.backend.desired[0].Bundle = "applications: ["
+	s.newWorker(c)
+	drift := s.nextDrift(c)
+	c.Check(drift.drift.Drifted(), jc.IsFalse)
+	c.Check(drift.drift.Error, gc.Matches, "reading bundle: .*")
+	s.waitChecked(c)
+	c.Check(s.gauges(c, "juju_bundledrift_check_failed"), jc.DeepEquals, map[string]float64{"prod": 1})
+}
+
+func (s *WorkerSuite) TestRemovedModelMetricsPruned(c *gc.C) {
+	s.backend.desired = append(s.backend.desired, bundledrift.DesiredBundle{
+		ModelUUID: uuid2,
+		ModelName: "staging",
+		Bundle:    bundle,
+	})
+	s.backend.setModel(uuid2, matchingModel())
+	s.newWorker(c)
+	s.nextDrift(c)
+	s.nextDrift(c)
+	s.waitChecked(c)
+	c.Check(s.gauges(c, "juju_bundledrift_drifted"), jc.DeepEquals, map[string]float64{"prod": 0, "staging": 0})
+
+	s.backend.mu.Lock()
+	s.backend.desired = s.backend.desired[:1]
+	s.backend.mu.Unlock()
+	err := s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.nextDrift(c)
+	s.waitChecked(c)
+	c.Check(s.gauges(c, "juju_bundledrift_drifted"), jc.DeepEquals, map[string]float64{"prod": 0})
+}
+
+func (s *WorkerSuite) TestDesiredBundleRemovedWhileChecking(c *gc.C) {
+	s.backend.setErr = errors.NotFoundf("desired bundle")
+	w := s.newWorker(c)
+	s.nextDrift(c)
+	s.waitChecked(c)
+	c.Check(s.gauges(c, "juju_bundledrift_drifted"), gc.HasLen, 0)
+	workertest.CheckAlive(c, w)
+}
+
+func (s *WorkerSuite) TestDesiredBundlesError(c *gc.C) {
+	s.backend.desiredErr = errors.New("boom")
+	w := s.newWorker(c)
+	err := workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "getting desired bundles: boom")
+}
+
+type recordedDrift struct {
+	modelUUID string
+	drift     state.BundleDrift
+}
+
+type fakeBackend struct {
+	mu         sync.Mutex
+	desired    []bundledrift.DesiredBundle
+	desiredErr error
+	models     map[string]*bundlechanges.Model
+	setErr     error
+	drifts     chan recordedDrift
+}
+
+func (b *fakeBackend) setModel(uuid string, model *bundlechanges.Model) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.models[uuid] = model
+}
+
+func (b *fakeBackend) DesiredBundles() ([]bundledrift.DesiredBundle, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]bundledrift.DesiredBundle(nil), b.desired...), b.desiredErr
+}
+
+func (b *fakeBackend) Model(modelUUID string) (*bundlechanges.Model, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	model, ok := b.models[modelUUID]
+	if !ok {
+		return nil, errors.NotFoundf("model %q", modelUUID)
+	}
+	return model, nil
+}
+
+func (b *fakeBackend) SetBundleDrift(modelUUID string, drift state.BundleDrift) error {
+	b.drifts <- recordedDrift{modelUUID: modelUUID, drift: drift}
+	return b.setErr
+}
@@ -0,0 +1,70 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package duework
+
+import (
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/worker/v2"
+	"github.com/juju/worker/v2/dependency"
+
+	"github.com/juju/juju/api/base"
+)
+
+// Logger represents the methods used by the worker to log information.
+type Logger interface {
+	Errorf(string, ...interface{})
+}
+
+// ManifoldConfig describes the resources used by a due work worker.
+type ManifoldConfig struct {
+	APICallerName string
+	Clock         clock.Clock
+	Logger        Logger
+
+	// NewRunner returns the client of the facade whose due work
+	// the worker runs.
+	NewRunner func(base.APICaller) Runner
+}
+
+// Validate is called by start to check for bad configuration.
+func (config ManifoldConfig) Validate() error {
+	if config.APICallerName == "" {
+		return errors.NotValidf("empty APICallerName")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Logger == nil {
+		return errors.NotValidf("nil Logger")
+	}
+	if config.NewRunner == nil {
+		return errors.NotValidf("nil NewRunner")
+	}
+	return nil
+}
+
+// Manifold returns a Manifold that encapsulates a due work worker.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{config.APICallerName},
+		Start:  config.start,
+	}
+}
+
+// start is a StartFunc for a Worker manifold.
+func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var apiCaller base.APICaller
+	if err := context.Get(config.APICallerName, &apiCaller); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w, err := NewWorker(config.NewRunner(apiCaller), config.Clock, config.Logger)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package duework
+
+import (
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/worker/v2"
+	"github.com/juju/worker/v2/catacomb"
+
+	"github.com/juju/juju/core/watcher"
+)
+
+// period is the longest the worker waits before running due work
+// again. Changes to the work trigger it sooner, but running it may
+// fail transiently, and nothing else would trigger a retry.
+const period = time.Minute
+
+// Runner provides the means to run work that falls due over time, such
+// as the next batch of a rolling operation or a scheduled action.
+type Runner interface {
+	// RunDueWork runs whatever work is due now, and returns the
+	// earliest time more work falls due, or the zero time if
+	// none is waiting.
+	RunDueWork() (time.Time, error)
+
+	// WatchDueWork returns a watcher that notifies when the work
+	// to be run changes.
+	WatchDueWork() (watcher.NotifyWatcher, error)
+}
+
+// Worker runs due work whenever the work changes, and once
+// waiting work falls due.
+type Worker struct {
+	catacomb catacomb.Catacomb
+	runner   Runner
+	watcher  watcher.NotifyWatcher
+	clock    clock.Clock
+	logger   Logger
+}
+
+// NewWorker returns a worker.Worker that runs the runner's due work
+// whenever it changes, and once waiting work falls due.
+func NewWorker(runner Runner, clock clock.Clock, logger Logger) (worker.Worker, error) {
+	watcher, err := runner.WatchDueWork()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &Worker{
+		runner:  runner,
+		watcher: watcher,
+		clock:   clock,
+		logger:  logger,
+	}
+	if err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+		Init: []worker.Worker{watcher},
+	}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+func (w *Worker) loop() error {
+	timer := w.clock.NewTimer(period)
+	defer timer.Stop()
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case _, ok := <-w.watcher.Changes():
+			if !ok {
+				return errors.New("change channel closed")
+			}
+		case <-timer.Chan():
+		}
+		wait := period
+		next, err := w.runner.RunDueWork()
+		if err != nil {
+			// Don't exit; try again when the timer fires.
+			w.logger.Errorf("cannot run due work: %v", err)
+		} else if !next.IsZero() {
+			if due := next.Sub(w.clock.Now()); due < wait {
+				wait = due
+			}
+			if wait < 0 {
+				wait = 0
+			}
+		}
+		timer.Reset(wait)
+	}
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *Worker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *Worker) Wait() error {
+	return w.catacomb.Wait()
+}
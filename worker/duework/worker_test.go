@@ -0,0 +1,180 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package duework_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v2"
+	"github.com/juju/worker/v2/workertest"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/tomb.v2"
+
+	"github.com/juju/juju/core/watcher"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/duework"
+)
+
+type WorkerSuite struct {
+	coretesting.BaseSuite
+	api    *mockAPI
+	clock  *testclock.Clock
+	logger loggo.Logger
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC))
+	s.api = &mockAPI{
+		calls:   make(chan string, 1),
+		watcher: s.newMockNotifyWatcher(),
+	}
+	s.logger = loggo.GetLogger("test")
+}
+
+func (s *WorkerSuite) assertReceived(c *gc.C, expect string) {
+	select {
+	case call := <-s.api.calls:
+		c.Assert(call, gc.Equals, expect)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for %s", expect)
+	}
+}
+
+func (s *WorkerSuite) assertEmpty(c *gc.C) {
+	select {
+	case call := <-s.api.calls:
+		c.Fatalf("unexpected %s", call)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *WorkerSuite) startWorker(c *gc.C) worker.Worker {
+	w, err := duework.NewWorker(s.api, s.clock, s.logger)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) { workertest.CleanKill(c, w) })
+	s.assertReceived(c, "WatchDueWork")
+	return w
+}
+
+func (s *WorkerSuite) TestRunsOnChange(c *gc.C) {
+	s.startWorker(c)
+	s.assertReceived(c, "RunDueWork")
+	s.assertEmpty(c)
+
+	s.api.watcher.Change()
+	s.assertReceived(c, "RunDueWork")
+	s.assertEmpty(c)
+}
+
+func (s *WorkerSuite) TestRunsWhenWorkDue(c *gc.C) {
+	s.api.next = []time.Time{s.clock.Now().Add(10 * time.Second)}
+	s.startWorker(c)
+	s.assertReceived(c, "RunDueWork")
+
+	err := s.clock.WaitAdvance(10*time.Second, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertReceived(c, "RunDueWork")
+}
+
+func (s *WorkerSuite) TestRunsPeriodically(c *gc.C) {
+	s.startWorker(c)
+	s.assertReceived(c, "RunDueWork")
+
+	err := s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertReceived(c, "RunDueWork")
+}
+
+func (s *WorkerSuite) TestRunError(c *gc.C) {
+	s.api.err = []error{nil, errors.New("hello")}
+	s.startWorker(c)
+	s.assertReceived(c, "RunDueWork")
+	s.assertEmpty(c)
+	c.Assert(c.GetTestLog(), jc.Contains, "ERROR test cannot run due work: hello")
+}
+
+func (s *WorkerSuite) TestWatchError(c *gc.C) {
+	s.api.err = []error{errors.New("hello")}
+	w, err := duework.NewWorker(s.api, s.clock, s.logger)
+	c.Assert(err, gc.ErrorMatches, "hello")
+	c.Assert(w, gc.IsNil)
+}
+
+func (s *WorkerSuite) newMockNotifyWatcher() *mockNotifyWatcher {
+	m := &mockNotifyWatcher{
+		changes: make(chan struct{}, 1),
+	}
+	m.tomb.Go(func() error {
+		<-m.tomb.Dying()
+		return nil
+	})
+	s.AddCleanup(func(c *gc.C) {
+		err := worker.Stop(m)
+		c.Check(err, jc.ErrorIsNil)
+	})
+	m.Change()
+	return m
+}
+
+type mockNotifyWatcher struct {
+	watcher.NotifyWatcher
+
+	tomb    tomb.Tomb
+	changes chan struct{}
+}
+
+func (m *mockNotifyWatcher) Kill() {
+	m.tomb.Kill(nil)
+}
+
+func (m *mockNotifyWatcher) Wait() error {
+	return m.tomb.Wait()
+}
+
+func (m *mockNotifyWatcher) Changes() watcher.NotifyChannel {
+	return m.changes
+}
+
+func (m *mockNotifyWatcher) Change() {
+	m.changes <- struct{}{}
+}
+
+// mockAPI records calls to RunDueWork and WatchDueWork.
+type mockAPI struct {
+	watcher *mockNotifyWatcher
+	calls   chan string
+	next    []time.Time
+	err     []error
+}
+
+func (m *mockAPI) getError() (e error) {
+	if len(m.err) > 0 {
+		e = m.err[0]
+		m.err = m.err[1:]
+	}
+	return
+}
+
+func (m *mockAPI) RunDueWork() (time.Time, error) {
+	var next time.Time
+	if len(m.next) > 0 {
+		next = m.next[0]
+		m.next = m.next[1:]
+	}
+	err := m.getError()
+	m.calls <- "RunDueWork"
+	return next, err
+}
+
+func (m *mockAPI) WatchDueWork() (watcher.NotifyWatcher, error) {
+	m.calls <- "WatchDueWork"
+	return m.watcher, m.getError()
+}
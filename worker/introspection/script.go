@@ -117,6 +117,10 @@ juju_machine_lock () {
   done
 }
 
+juju_wrenches () {
+  juju_agent wrench
+}
+
 juju_unit_status () {
   juju_agent units?action=status
 }
@@ -229,6 +233,7 @@ if [ "$shell" = "bash" ]; then
   export -f juju_pubsub_report
   export -f juju_presence_report
   export -f juju_machine_lock
+  export -f juju_wrenches
   export -f juju_unit_status
   export -f juju_start_unit
   export -f juju_stop_unit
@@ -25,6 +25,7 @@ import (
 	"github.com/juju/juju/core/presence"
 	"github.com/juju/juju/pubsub/agent"
 	"github.com/juju/juju/worker/introspection/pprof"
+	"github.com/juju/juju/wrench"
 )
 
 var logger = loggo.GetLogger("juju.worker.introspection")
@@ -213,6 +214,7 @@ func (w *socketListener) RegisterHTTPHandlers(
 		handle("/presence", presenceHandler{w.presence})
 	}
 	handle("/machinelock", machineLockHandler{w.machineLock})
+	handle("/wrench", wrenchHandler{})
 	if w.localHub != nil {
 		handle("/units", unitsHandler{w.clock, w.localHub, w.done})
 	}
@@ -281,6 +283,34 @@ func (h machineLockHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprint(w, content)
 }
 
+type wrenchHandler struct{}
+
+// ServeHTTP is part of the http.Handler interface.
+func (h wrenchHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if !wrench.IsEnabled() {
+		fmt.Fprintln(w, "wrenches are disabled")
+		return
+	}
+	active := wrench.Active()
+	if len(active) == 0 {
+		fmt.Fprintln(w, "no active wrenches")
+		return
+	}
+
+	tw := output.TabWriter(w)
+	wrapper := output.Wrapper{tw}
+	wrapper.Println("CATEGORY", "FEATURE", "EXPIRES", "SOURCE")
+	for _, aw := range active {
+		expires := "-"
+		if !aw.Expires.IsZero() {
+			expires = aw.Expires.UTC().Format(time.RFC3339)
+		}
+		wrapper.Println(aw.Category, aw.Feature, expires, aw.Source)
+	}
+	tw.Flush()
+}
+
 type introspectionReporterHandler struct {
 	name     string
 	reporter Reporter
@@ -33,6 +33,7 @@ import (
 	"github.com/juju/juju/pubsub/agent"
 	_ "github.com/juju/juju/state"
 	"github.com/juju/juju/worker/introspection"
+	"github.com/juju/juju/wrench"
 )
 
 type suite struct {
@@ -194,6 +195,30 @@ func (s *introspectionSuite) TestMissingMachineLock(c *gc.C) {
 	s.assertBody(c, response, "missing machine lock reporter")
 }
 
+func (s *introspectionSuite) TestWrenchesDisabled(c *gc.C) {
+	enabled := wrench.SetEnabled(false)
+	s.AddCleanup(func(*gc.C) { wrench.SetEnabled(enabled) })
+	response := s.call(c, "/wrench")
+	c.Assert(response.StatusCode, gc.Equals, http.StatusOK)
+	s.assertBody(c, response, "wrenches are disabled")
+}
+
+func (s *introspectionSuite) TestWrenches(c *gc.C) {
+	enabled := wrench.SetEnabled(true)
+	s.AddCleanup(func(*gc.C) { wrench.SetEnabled(enabled) })
+	wrenches, err := wrench.Parse("machine-agent/slow, uniter/fail@2100-01-02T03:04:05Z")
+	c.Assert(err, jc.ErrorIsNil)
+	wrench.SetRemote(wrenches)
+	s.AddCleanup(func(*gc.C) { wrench.SetRemote(nil) })
+
+	response := s.call(c, "/wrench")
+	c.Assert(response.StatusCode, gc.Equals, http.StatusOK)
+	s.assertBody(c, response, `
+CATEGORY       FEATURE  EXPIRES               SOURCE
+machine-agent  slow     -                     model-config
+uniter         fail     2100-01-02T03:04:05Z  model-config`[1:])
+}
+
 func (s *introspectionSuite) TestStateTrackerReporter(c *gc.C) {
 	response := s.call(c, "/debug/pprof/juju/state/tracker?debug=1")
 	c.Assert(response.StatusCode, gc.Equals, http.StatusOK)
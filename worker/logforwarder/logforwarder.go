@@ -6,7 +6,9 @@ package logforwarder
 import (
 	"io"
 	"sync"
+	"time"
 
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/worker/v2/catacomb"
 	"gopkg.in/tomb.v2"
@@ -76,11 +78,15 @@ type OpenLogForwarderArgs struct {
 	// log stream.
 	OpenLogStream LogStreamFn
 
+	// Clock is used to time the flushing of batched records. If it is
+	// nil, the wall clock is used.
+	Clock clock.Clock
+
 	Logger Logger
 }
 
-// processNewConfig acts on a new syslog forward config change.
-func (lf *LogForwarder) processNewConfig(currentSender SendCloser) (SendCloser, error) {
+// processNewConfig acts on a new log forward config change.
+func (lf *LogForwarder) processNewConfig(currentSender *LogSink) (*LogSink, error) {
 	lf.mu.Lock()
 	defer lf.mu.Unlock()
 
@@ -99,7 +105,7 @@ func (lf *LogForwarder) processNewConfig(currentSender SendCloser) (SendCloser,
 		closeExisting()
 		return nil, errors.Trace(err)
 	}
-	if !ok || !cfg.Enabled {
+	if !ok || !cfg.Enabled() {
 		lf.args.Logger.Infof("config change - log forwarding not enabled")
 		return nil, closeExisting()
 	}
@@ -148,7 +154,7 @@ func (lf *LogForwarder) waitForEnabled() (bool, error) {
 	defer lf.mu.Unlock()
 
 	if !lf.enabled && enabled {
-		lf.args.Logger.Infof("log forward enabled, starting to stream logs to syslog sink")
+		lf.args.Logger.Infof("log forward enabled, starting to stream logs")
 	}
 	lf.enabled = enabled
 	return enabled, nil
@@ -157,6 +163,9 @@ func (lf *LogForwarder) waitForEnabled() (bool, error) {
 // NewLogForwarder returns a worker that forwards logs received from
 // the stream to the sender.
 func NewLogForwarder(args OpenLogForwarderArgs) (*LogForwarder, error) {
+	if args.Clock == nil {
+		args.Clock = clock.WallClock
+	}
 	lf := &LogForwarder{
 		args:      args,
 		enabledCh: make(chan bool, 1),
@@ -220,20 +229,49 @@ func (lf *LogForwarder) loop() error {
 		}
 	}()
 
-	var sender SendCloser
+	var (
+		sender     *LogSink
+		pending    []logfwd.Record
+		flushTimer clock.Timer
+		flush      <-chan time.Time
+	)
 	defer func() {
+		if flushTimer != nil {
+			flushTimer.Stop()
+		}
 		if sender != nil {
 			sender.Close()
 		}
 	}()
 
+	// send sends any pending records to the current sink. Records that
+	// are still pending when the worker stops haven't been tracked as
+	// sent, so they will be streamed again when it restarts.
+	send := func() error {
+		if flushTimer != nil {
+			flushTimer.Stop()
+			flushTimer, flush = nil, nil
+		}
+		records := pending
+		pending = nil
+		if sender == nil || len(records) == 0 {
+			return nil
+		}
+		return errors.Trace(sender.Send(records))
+	}
+
 	for {
 		select {
 		case <-lf.catacomb.Dying():
 			return lf.catacomb.ErrDying()
 		case _, ok := <-configWatcher.Changes():
 			if !ok {
-				return errors.New("syslog configuration watcher closed")
+				return errors.New("log forward configuration watcher closed")
+			}
+			// Records batched for the current sink are sent before
+			// it is replaced.
+			if err := send(); err != nil {
+				return errors.Trace(err)
 			}
 			if sender, err = lf.processNewConfig(sender); err != nil {
 				return errors.Trace(err)
@@ -242,7 +280,17 @@ func (lf *LogForwarder) loop() error {
 			if sender == nil {
 				continue
 			}
-			if err := sender.Send(rec); err != nil {
+			pending = append(pending, rec...)
+			if len(pending) >= sender.BatchSize {
+				if err := send(); err != nil {
+					return errors.Trace(err)
+				}
+			} else if flushTimer == nil {
+				flushTimer = lf.args.Clock.NewTimer(sender.FlushInterval)
+				flush = flushTimer.Chan()
+			}
+		case <-flush:
+			if err := send(); err != nil {
 				return errors.Trace(err)
 			}
 		}
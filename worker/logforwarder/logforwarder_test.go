@@ -6,6 +6,7 @@ package logforwarder_test
 import (
 	"time"
 
+	"github.com/juju/clock/testclock"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/testing"
@@ -16,6 +17,7 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/logfwd"
 	"github.com/juju/juju/logfwd/syslog"
 	coretesting "github.com/juju/juju/testing"
@@ -26,9 +28,11 @@ import (
 type LogForwarderSuite struct {
 	testing.IsolationSuite
 
-	stream *stubStream
-	sender *stubSender
-	rec    logfwd.Record
+	stream    *stubStream
+	sender    *stubSender
+	rec       logfwd.Record
+	clock     *testclock.Clock
+	batchSize int
 }
 
 var _ = gc.Suite(&LogForwarderSuite{})
@@ -38,6 +42,8 @@ func (s *LogForwarderSuite) SetUpTest(c *gc.C) {
 
 	s.stream = newStubStream()
 	s.sender = newStubSender()
+	s.clock = testclock.NewClock(time.Now())
+	s.batchSize = 0
 	s.rec = logfwd.Record{
 		Origin: logfwd.Origin{
 			ControllerUUID: "feebdaed-2f18-4fd2-967d-db9663db7bea",
@@ -85,10 +91,12 @@ func (s *LogForwarderSuite) newLogForwarderArgsWithAPI(
 		Caller:           &mockCaller{},
 		LogForwardConfig: configAPI,
 		ControllerUUID:   "feebdaed-2f18-4fd2-967d-db9663db7bea",
-		OpenSink: func(cfg *syslog.RawConfig) (*logforwarder.LogSink, error) {
-			sender.host = cfg.Host
+		OpenSink: func(cfg *config.LogFwdConfig) (*logforwarder.LogSink, error) {
+			sender.host = cfg.Syslog.Host
 			sink := &logforwarder.LogSink{
-				sender,
+				SendCloser:    sender,
+				BatchSize:     s.batchSize,
+				FlushInterval: time.Second,
 			}
 			return sink, nil
 		},
@@ -96,6 +104,7 @@ func (s *LogForwarderSuite) newLogForwarderArgsWithAPI(
 			c.Assert(controllerUUID, gc.Equals, "feebdaed-2f18-4fd2-967d-db9663db7bea")
 			return stream, nil
 		},
+		Clock:  s.clock,
 		Logger: loggo.GetLogger("test"),
 	}
 }
@@ -153,6 +162,36 @@ func (s *LogForwarderSuite) TestConfigChange(c *gc.C) {
 	})
 }
 
+func (s *LogForwarderSuite) TestBatching(c *gc.C) {
+	s.batchSize = 2
+	rec0 := s.rec
+	rec1 := s.rec
+	rec1.ID = 11
+	rec2 := s.rec
+	rec2.ID = 12
+
+	lf, err := logforwarder.NewLogForwarder(s.newLogForwarderArgs(c, s.stream, s.sender))
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, lf)
+
+	// A full batch is sent straight away.
+	s.stream.addRecords(c, rec0, rec1)
+	s.sender.waitForSend(c)
+
+	// A partial batch is sent once the flush interval passes.
+	s.stream.addRecords(c, rec2)
+	err = s.clock.WaitAdvance(time.Second, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.sender.waitForSend(c)
+
+	workertest.CleanKill(c, lf)
+	s.sender.stub.CheckCalls(c, []testing.StubCall{
+		{"Send", []interface{}{[]logfwd.Record{rec0, rec1}}},
+		{"Send", []interface{}{[]logfwd.Record{rec2}}},
+		{"Close", nil},
+	})
+}
+
 func (s *LogForwarderSuite) TestNotEnabled(c *gc.C) {
 	lf, err := logforwarder.NewLogForwarder(s.newLogForwarderArgs(c, nil, s.sender))
 	c.Assert(err, jc.ErrorIsNil)
@@ -249,13 +288,16 @@ func (c *mockLogForwardConfig) WatchForLogForwardConfigChanges() (watcher.Notify
 	}, nil
 }
 
-func (c *mockLogForwardConfig) LogForwardConfig() (*syslog.RawConfig, bool, error) {
-	return &syslog.RawConfig{
-		Enabled:    c.enabled,
-		Host:       c.host,
-		CACert:     coretesting.CACert,
-		ClientCert: coretesting.ServerCert,
-		ClientKey:  coretesting.ServerKey,
+func (c *mockLogForwardConfig) LogForwardConfig() (*config.LogFwdConfig, bool, error) {
+	return &config.LogFwdConfig{
+		Type: config.LogFwdTypeSyslog,
+		Syslog: &syslog.RawConfig{
+			Enabled:    c.enabled,
+			Host:       c.host,
+			CACert:     coretesting.CACert,
+			ClientCert: coretesting.ServerCert,
+			ClientKey:  coretesting.ServerKey,
+		},
 	}, true, nil
 }
 
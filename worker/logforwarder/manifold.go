@@ -4,6 +4,7 @@
 package logforwarder
 
 import (
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/worker/v2"
 	"github.com/juju/worker/v2/dependency"
@@ -37,6 +38,9 @@ type ManifoldConfig struct {
 	// OpenLogForwarder opens each log forwarder that will be used.
 	OpenLogForwarder func(OpenLogForwarderArgs) (*LogForwarder, error)
 
+	// Clock is used to time the batching of records.
+	Clock clock.Clock
+
 	Logger Logger
 }
 
@@ -81,6 +85,7 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 				Sinks:            config.Sinks,
 				OpenLogStream:    openLogStream,
 				OpenLogForwarder: openForwarder,
+				Clock:            config.Clock,
 				Logger:           config.Logger,
 			})
 			return orchestrator, errors.Annotate(err, "creating log forwarding orchestrator")
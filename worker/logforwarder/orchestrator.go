@@ -4,6 +4,7 @@
 package logforwarder
 
 import (
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 
 	"github.com/juju/juju/api/base"
@@ -36,6 +37,9 @@ type OrchestratorArgs struct {
 	// OpenLogForwarder opens each log forwarder that will be used.
 	OpenLogForwarder func(OpenLogForwarderArgs) (*LogForwarder, error)
 
+	// Clock is used by the log forwarders to time batches.
+	Clock clock.Clock
+
 	Logger Logger
 }
 
@@ -55,6 +59,7 @@ func newOrchestratorForController(args OrchestratorArgs) (*orchestrator, error)
 		Name:             args.Sinks[0].Name,
 		OpenSink:         args.Sinks[0].OpenFn,
 		OpenLogStream:    args.OpenLogStream,
+		Clock:            args.Clock,
 		Logger:           args.Logger,
 	})
 	return &orchestrator{lf}, errors.Annotate(err, "opening log forwarder")
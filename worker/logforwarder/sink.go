@@ -4,8 +4,10 @@
 package logforwarder
 
 import (
+	"time"
+
 	"github.com/juju/juju/core/watcher"
-	"github.com/juju/juju/logfwd/syslog"
+	"github.com/juju/juju/environs/config"
 )
 
 // LogForwardConfig provides access to the log forwarding config for a model.
@@ -15,7 +17,7 @@ type LogForwardConfig interface {
 	WatchForLogForwardConfigChanges() (watcher.NotifyWatcher, error)
 
 	// LogForwardConfig returns the current log forward configuration.
-	LogForwardConfig() (*syslog.RawConfig, bool, error)
+	LogForwardConfig() (*config.LogFwdConfig, bool, error)
 }
 
 type LogSinkSpec struct {
@@ -27,9 +29,17 @@ type LogSinkSpec struct {
 }
 
 // LogSinkFn is a function that opens a log sink.
-type LogSinkFn func(cfg *syslog.RawConfig) (*LogSink, error)
+type LogSinkFn func(cfg *config.LogFwdConfig) (*LogSink, error)
 
 // LogSink is a single log sink, to which log records may be sent.
 type LogSink struct {
 	SendCloser
+
+	// BatchSize is the number of records to collect before sending
+	// them to the sink. If it is zero, records are sent as they arrive.
+	BatchSize int
+
+	// FlushInterval is the longest time a record is held waiting for
+	// a batch to fill before it is sent.
+	FlushInterval time.Duration
 }
@@ -0,0 +1,42 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sinks
+
+import (
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/logfwd/httpfwd"
+	"github.com/juju/juju/worker/logforwarder"
+)
+
+const (
+	// httpBatchSize is the number of records sent in each request to
+	// an HTTP based sink.
+	httpBatchSize = 500
+
+	// httpFlushInterval is the longest a record waits for a batch to
+	// fill before it is sent.
+	httpFlushInterval = 2 * time.Second
+)
+
+// OpenHTTP returns a sink that posts log records to an HTTP endpoint,
+// encoded as plain JSON or for a Loki or Elasticsearch endpoint.
+func OpenHTTP(cfg *config.LogFwdConfig) (*logforwarder.LogSink, error) {
+	if cfg.HTTP == nil || !cfg.HTTP.Enabled {
+		return nil, errors.New("log forwarding not enabled")
+	}
+	client, err := httpfwd.Open(*cfg.HTTP, clock.WallClock)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &logforwarder.LogSink{
+		SendCloser:    client,
+		BatchSize:     httpBatchSize,
+		FlushInterval: httpFlushInterval,
+	}, nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sinks
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/worker/logforwarder"
+)
+
+var (
+	mu        sync.Mutex
+	sinkTypes = map[string]logforwarder.LogSinkFn{
+		config.LogFwdTypeSyslog:        OpenSyslog,
+		config.LogFwdTypeHTTP:          OpenHTTP,
+		config.LogFwdTypeLoki:          OpenHTTP,
+		config.LogFwdTypeElasticsearch: OpenHTTP,
+	}
+)
+
+// Register sets the function used to open sinks for the given
+// logforward-type, replacing any existing one.
+func Register(sinkType string, open logforwarder.LogSinkFn) {
+	mu.Lock()
+	defer mu.Unlock()
+	sinkTypes[sinkType] = open
+}
+
+// Open returns a sink of the type selected by the config's
+// logforward-type.
+func Open(cfg *config.LogFwdConfig) (*logforwarder.LogSink, error) {
+	mu.Lock()
+	open, ok := sinkTypes[cfg.Type]
+	mu.Unlock()
+	if !ok {
+		return nil, errors.NotSupportedf("log forwarding type %q", cfg.Type)
+	}
+	sink, err := open(cfg)
+	return sink, errors.Annotatef(err, "opening %s log sink", cfg.Type)
+}
@@ -0,0 +1,76 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sinks_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/logfwd/httpfwd"
+	"github.com/juju/juju/worker/logforwarder"
+	"github.com/juju/juju/worker/logforwarder/sinks"
+)
+
+type sinksSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&sinksSuite{})
+
+func (s *sinksSuite) TestOpenUnknownType(c *gc.C) {
+	_, err := sinks.Open(&config.LogFwdConfig{Type: "gelf"})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, `log forwarding type "gelf" not supported`)
+}
+
+func (s *sinksSuite) TestOpenHTTPTypes(c *gc.C) {
+	for _, sinkType := range []string{
+		config.LogFwdTypeHTTP,
+		config.LogFwdTypeLoki,
+		config.LogFwdTypeElasticsearch,
+	} {
+		c.Logf("type %s", sinkType)
+		sink, err := sinks.Open(&config.LogFwdConfig{
+			Type: sinkType,
+			HTTP: &httpfwd.RawConfig{
+				Enabled: true,
+				Format:  httpfwd.FormatJSON,
+				URL:     "https://logs.example.com/",
+			},
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(sink.SendCloser, gc.FitsTypeOf, &httpfwd.Client{})
+		c.Check(sink.BatchSize, gc.Equals, 500)
+		c.Check(sink.Close(), jc.ErrorIsNil)
+	}
+}
+
+func (s *sinksSuite) TestOpenHTTPNotEnabled(c *gc.C) {
+	_, err := sinks.Open(&config.LogFwdConfig{
+		Type: config.LogFwdTypeLoki,
+		HTTP: &httpfwd.RawConfig{
+			Format: httpfwd.FormatLoki,
+			URL:    "https://logs.example.com/",
+		},
+	})
+	c.Assert(err, gc.ErrorMatches, `opening loki log sink: log forwarding not enabled`)
+}
+
+func (s *sinksSuite) TestOpenSyslogNotEnabled(c *gc.C) {
+	_, err := sinks.Open(&config.LogFwdConfig{Type: config.LogFwdTypeSyslog})
+	c.Assert(err, gc.ErrorMatches, `opening syslog log sink: log forwarding not enabled`)
+}
+
+func (s *sinksSuite) TestRegister(c *gc.C) {
+	expected := &logforwarder.LogSink{}
+	sinks.Register("test", func(cfg *config.LogFwdConfig) (*logforwarder.LogSink, error) {
+		return expected, nil
+	})
+	sink, err := sinks.Open(&config.LogFwdConfig{Type: "test"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(sink, gc.Equals, expected)
+}
@@ -9,14 +9,14 @@ import (
 
 	"github.com/juju/juju/api/base"
 	logfwdapi "github.com/juju/juju/api/logfwd"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/logfwd"
-	"github.com/juju/juju/logfwd/syslog"
 )
 
 // TrackingSinkArgs holds the args to OpenTrackingSender.
 type TrackingSinkArgs struct {
 	// Config is the logging config that will be used.
-	Config *syslog.RawConfig
+	Config *config.LogFwdConfig
 
 	// Caller is the API caller that will be used.
 	Caller base.APICaller
@@ -38,10 +38,12 @@ func OpenTrackingSink(args TrackingSinkArgs) (*LogSink, error) {
 	}
 
 	return &LogSink{
-		&trackingSender{
+		SendCloser: &trackingSender{
 			SendCloser: sink,
 			tracker:    newLastSentTracker(args.Name, args.Caller),
 		},
+		BatchSize:     sink.BatchSize,
+		FlushInterval: sink.FlushInterval,
 	}, nil
 }
 
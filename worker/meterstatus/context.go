@@ -12,6 +12,8 @@ import (
 	"github.com/juju/loggo"
 
 	"github.com/juju/juju/caas"
+	"github.com/juju/juju/core/application"
+	"github.com/juju/juju/core/hooktrace"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/worker/uniter/runner/context"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
@@ -82,6 +84,18 @@ func (ctx *limitedContext) UnitName() string {
 	return ctx.config.unitName
 }
 
+// HookLimits implements runner.Context. The meter-status-changed hook
+// is run outside the uniter, so no limits are applied.
+func (ctx *limitedContext) HookLimits() application.HookLimits {
+	return application.HookLimits{}
+}
+
+// HookTracer implements runner.Context. The meter-status-changed hook
+// is run outside the uniter, so it is not traced.
+func (ctx *limitedContext) HookTracer() *hooktrace.Recorder {
+	return nil
+}
+
 // ModelType implements runner.Context
 func (ctx *limitedContext) ModelType() model.ModelType {
 	// Can return IAAS constant because meter status is only used in Uniter.
@@ -13,6 +13,8 @@ import (
 	"github.com/juju/loggo"
 
 	"github.com/juju/juju/caas"
+	"github.com/juju/juju/core/application"
+	"github.com/juju/juju/core/hooktrace"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/worker/metrics/spool"
 	"github.com/juju/juju/worker/uniter/runner/context"
@@ -67,6 +69,18 @@ func (ctx *hookContext) UnitName() string {
 	return ctx.config.unitName
 }
 
+// HookLimits implements runner.Context. The collect-metrics hook
+// is run outside the uniter, so no limits are applied.
+func (ctx *hookContext) HookLimits() application.HookLimits {
+	return application.HookLimits{}
+}
+
+// HookTracer implements runner.Context. The collect-metrics hook
+// is run outside the uniter, so it is not traced.
+func (ctx *hookContext) HookTracer() *hooktrace.Recorder {
+	return nil
+}
+
 // ModelType implements runner.Context
 func (ctx *hookContext) ModelType() model.ModelType {
 	// Can return IAAS constant because collect-metrics is only used in Uniter.
@@ -387,6 +387,9 @@ func (c *cacheWorker) translate(d multiwatcher.Delta) interface{} {
 		// Generation deltas are processed as cache branch changes,
 		// as only "in-flight" branches should ever be in the cache.
 		return c.translateBranch(d)
+	case multiwatcher.ActionKind:
+		// Only pending and running actions are held in the cache.
+		return c.translateAction(d)
 	default:
 		return nil
 	}
@@ -420,6 +423,7 @@ func (c *cacheWorker) translateModel(d multiwatcher.Delta) interface{} {
 		Annotations:     value.Annotations,
 		Config:          value.Config,
 		Status:          coreStatus(value.Status),
+		MigrationMode:   value.MigrationMode,
 		// TODO: constraints, sla
 		UserPermissions: value.UserPermissions,
 	}
@@ -649,6 +653,45 @@ func (c *cacheWorker) translateBranch(d multiwatcher.Delta) interface{} {
 	}
 }
 
+func (c *cacheWorker) translateAction(d multiwatcher.Delta) interface{} {
+	e := d.Entity
+	id := e.EntityID()
+
+	if d.Removed {
+		return cache.RemoveAction{
+			ModelUUID: id.ModelUUID,
+			Id:        id.ID,
+		}
+	}
+
+	value, ok := e.(*multiwatcher.ActionInfo)
+	if !ok {
+		c.config.Logger.Errorf("unexpected type %T", e)
+		return nil
+	}
+
+	// As with branches, actions which have finished are no longer
+	// of interest and are removed from the cache.
+	switch value.Status {
+	case string(state.ActionPending), string(state.ActionRunning):
+	default:
+		return cache.RemoveAction{
+			ModelUUID: id.ModelUUID,
+			Id:        id.ID,
+		}
+	}
+
+	return cache.ActionChange{
+		ModelUUID: value.ModelUUID,
+		Id:        value.ID,
+		Receiver:  value.Receiver,
+		Name:      value.Name,
+		Status:    value.Status,
+		Enqueued:  value.Enqueued,
+		Started:   value.Started,
+	}
+}
+
 // Kill is part of the worker.Worker interface.
 func (c *cacheWorker) Kill() {
 	c.catacomb.Kill(nil)
@@ -661,6 +661,65 @@ func (s *WorkerSuite) TestRemoveBranch(c *gc.C) {
 	}
 }
 
+func (s *WorkerSuite) addAction(c *gc.C) state.Action {
+	ch := s.Factory.MakeCharm(c, &factory.CharmParams{Name: "dummy"})
+	app := s.Factory.MakeApplication(c, &factory.ApplicationParams{Charm: ch})
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: app})
+
+	operationID, err := s.Model.EnqueueOperation("a test")
+	c.Assert(err, jc.ErrorIsNil)
+	action, err := unit.AddAction(operationID, "snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	return action
+}
+
+func (s *WorkerSuite) TestAddAction(c *gc.C) {
+	changes := s.captureEvents(c, cachetest.ActionEvents)
+	w := s.start(c)
+
+	action := s.addAction(c)
+	s.State.StartSync()
+
+	change := s.nextChange(c, changes)
+	obtained, ok := change.(cache.ActionChange)
+	c.Assert(ok, jc.IsTrue)
+	c.Check(obtained.Id, gc.Equals, action.Id())
+	c.Check(obtained.Name, gc.Equals, "snapshot")
+	c.Check(obtained.Status, gc.Equals, "pending")
+
+	controller := s.getController(c, w)
+	mod, err := controller.Model(s.Model.UUID())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(mod.Actions(), gc.HasLen, 1)
+}
+
+func (s *WorkerSuite) TestRemoveCompletedAction(c *gc.C) {
+	changes := s.captureEvents(c, cachetest.ActionEvents)
+	w := s.start(c)
+
+	action := s.addAction(c)
+	s.State.StartSync()
+	_ = s.nextChange(c, changes)
+
+	controller := s.getController(c, w)
+
+	// Finished actions are not deleted from the DB, but are no longer
+	// held in the cache.
+	_, err := action.Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	s.State.StartSync()
+
+	for {
+		change := s.nextChange(c, changes)
+		if _, ok := change.(cache.RemoveAction); ok {
+			mod, err := controller.Model(s.Model.UUID())
+			c.Assert(err, jc.ErrorIsNil)
+			c.Check(mod.Actions(), gc.HasLen, 0)
+			return
+		}
+	}
+}
+
 func (s *WorkerSuite) TestWatcherErrorCacheMarkSweep(c *gc.C) {
 	// Some state to close over.
 	fakeModelSent := false
@@ -0,0 +1,108 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package restoreflag
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/pubsub"
+	"github.com/juju/worker/v2"
+	"github.com/juju/worker/v2/dependency"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/cmd/jujud/agent/engine"
+	"github.com/juju/juju/worker/fortress"
+	"github.com/juju/juju/worker/gate"
+)
+
+// ManifoldConfig holds the dependencies and configuration for a
+// Worker manifold.
+type ManifoldConfig struct {
+	AgentName      string
+	CentralHubName string
+	FortressName   string
+
+	Started   gate.Lock
+	Logger    Logger
+	NewWorker func(Config) (worker.Worker, error)
+}
+
+// Validate is called by start to check for bad configuration.
+func (config ManifoldConfig) Validate() error {
+	if config.AgentName == "" {
+		return errors.NotValidf("empty AgentName")
+	}
+	if config.CentralHubName == "" {
+		return errors.NotValidf("empty CentralHubName")
+	}
+	if config.FortressName == "" {
+		return errors.NotValidf("empty FortressName")
+	}
+	if config.Started == nil {
+		return errors.NotValidf("nil Started")
+	}
+	if config.Logger == nil {
+		return errors.NotValidf("nil Logger")
+	}
+	if config.NewWorker == nil {
+		return errors.NotValidf("nil NewWorker")
+	}
+	return nil
+}
+
+// start is a StartFunc for a Worker manifold.
+func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var a agent.Agent
+	if err := context.Get(config.AgentName, &a); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var hub *pubsub.StructuredHub
+	if err := context.Get(config.CentralHubName, &hub); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var guard fortress.Guard
+	if err := context.Get(config.FortressName, &guard); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w, err := config.NewWorker(Config{
+		Hub:       hub,
+		Guard:     guard,
+		MachineID: a.CurrentConfig().Tag().Id(),
+		Logger:    config.Logger,
+		Started:   config.Started,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// Manifold packages a Worker for use in a dependency.Engine.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{
+			config.AgentName,
+			config.CentralHubName,
+			config.FortressName,
+		},
+		Start:  config.start,
+		Output: engine.FlagOutput,
+		Filter: bounceErrRestoring,
+	}
+}
+
+// NewManifoldWorker wraps NewWorker for use in a ManifoldConfig.
+func NewManifoldWorker(config Config) (worker.Worker, error) {
+	return NewWorker(config)
+}
+
+// bounceErrRestoring converts ErrRestoring to dependency.ErrBounce.
+func bounceErrRestoring(err error) error {
+	if errors.Cause(err) == ErrRestoring {
+		return dependency.ErrBounce
+	}
+	return err
+}
@@ -0,0 +1,180 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package restoreflag_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names/v4"
+	"github.com/juju/pubsub"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v2"
+	"github.com/juju/worker/v2/dependency"
+	dt "github.com/juju/worker/v2/dependency/testing"
+	"github.com/juju/worker/v2/workertest"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/cmd/jujud/agent/engine"
+	"github.com/juju/juju/worker/fortress"
+	"github.com/juju/juju/worker/gate"
+	"github.com/juju/juju/worker/restoreflag"
+)
+
+type ManifoldSuite struct {
+	testing.IsolationSuite
+
+	config   restoreflag.ManifoldConfig
+	manifold dependency.Manifold
+	context  dependency.Context
+	hub      *pubsub.StructuredHub
+	guard    *fakeGuard
+	started  gate.Lock
+	logger   loggo.Logger
+	worker   worker.Worker
+
+	stub testing.Stub
+}
+
+var _ = gc.Suite(&ManifoldSuite{})
+
+func (s *ManifoldSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.hub = pubsub.NewStructuredHub(nil)
+	s.guard = &fakeGuard{}
+	s.started = gate.NewLock()
+	s.logger = loggo.GetLogger("restoreflag_manifold")
+	s.worker = &struct{ worker.Worker }{}
+	s.stub.ResetCalls()
+
+	s.context = dt.StubContext(nil, map[string]interface{}{
+		"agent":    &fakeAgent{tag: names.NewMachineTag("3")},
+		"hub":      s.hub,
+		"fortress": s.guard,
+	})
+	s.config = restoreflag.ManifoldConfig{
+		AgentName:      "agent",
+		CentralHubName: "hub",
+		FortressName:   "fortress",
+		Started:        s.started,
+		Logger:         s.logger,
+		NewWorker:      s.newWorker,
+	}
+	s.manifold = restoreflag.Manifold(s.config)
+}
+
+func (s *ManifoldSuite) newWorker(config restoreflag.Config) (worker.Worker, error) {
+	s.stub.MethodCall(s, "NewWorker", config)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return s.worker, nil
+}
+
+func (s *ManifoldSuite) TestInputs(c *gc.C) {
+	c.Assert(s.manifold.Inputs, jc.SameContents, []string{"agent", "hub", "fortress"})
+}
+
+func (s *ManifoldSuite) TestMissingFortress(c *gc.C) {
+	context := dt.StubContext(nil, map[string]interface{}{
+		"agent":    &fakeAgent{tag: names.NewMachineTag("3")},
+		"hub":      s.hub,
+		"fortress": dependency.ErrMissing,
+	})
+	_, err := s.manifold.Start(context)
+	c.Assert(errors.Cause(err), gc.Equals, dependency.ErrMissing)
+}
+
+func (s *ManifoldSuite) TestValidate(c *gc.C) {
+	type test struct {
+		f      func(*restoreflag.ManifoldConfig)
+		expect string
+	}
+	tests := []test{{
+		func(cfg *restoreflag.ManifoldConfig) { cfg.AgentName = "" },
+		"empty AgentName not valid",
+	}, {
+		func(cfg *restoreflag.ManifoldConfig) { cfg.CentralHubName = "" },
+		"empty CentralHubName not valid",
+	}, {
+		func(cfg *restoreflag.ManifoldConfig) { cfg.FortressName = "" },
+		"empty FortressName not valid",
+	}, {
+		func(cfg *restoreflag.ManifoldConfig) { cfg.Started = nil },
+		"nil Started not valid",
+	}, {
+		func(cfg *restoreflag.ManifoldConfig) { cfg.Logger = nil },
+		"nil Logger not valid",
+	}, {
+		func(cfg *restoreflag.ManifoldConfig) { cfg.NewWorker = nil },
+		"nil NewWorker not valid",
+	}}
+	for i, test := range tests {
+		c.Logf("test #%d (%s)", i, test.expect)
+		config := s.config
+		test.f(&config)
+		manifold := restoreflag.Manifold(config)
+		w, err := manifold.Start(s.context)
+		workertest.CheckNilOrKill(c, w)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
+
+func (s *ManifoldSuite) TestStart(c *gc.C) {
+	w, err := s.manifold.Start(s.context)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w, gc.Equals, s.worker)
+
+	s.stub.CheckCallNames(c, "NewWorker")
+	c.Assert(s.stub.Calls()[0].Args, jc.DeepEquals, []interface{}{
+		restoreflag.Config{
+			Hub:       s.hub,
+			Guard:     s.guard,
+			MachineID: "3",
+			Logger:    s.logger,
+			Started:   s.started,
+		},
+	})
+}
+
+func (s *ManifoldSuite) TestOutput(c *gc.C) {
+	s.config.NewWorker = restoreflag.NewManifoldWorker
+	manifold := restoreflag.Manifold(s.config)
+	w, err := manifold.Start(s.context)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	var flag engine.Flag
+	err = manifold.Output(w, &flag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(flag.Check(), jc.IsTrue)
+}
+
+func (s *ManifoldSuite) TestFilter(c *gc.C) {
+	c.Check(s.manifold.Filter(restoreflag.ErrRestoring), gc.Equals, dependency.ErrBounce)
+	err := errors.New("boom")
+	c.Check(s.manifold.Filter(err), gc.Equals, err)
+}
+
+type fakeAgent struct {
+	agent.Agent
+	tag names.Tag
+}
+
+func (a *fakeAgent) CurrentConfig() agent.Config {
+	return fakeConfig{tag: a.tag}
+}
+
+type fakeConfig struct {
+	agent.Config
+	tag names.Tag
+}
+
+func (c fakeConfig) Tag() names.Tag {
+	return c.tag
+}
+
+var _ fortress.Guard = (*fakeGuard)(nil)
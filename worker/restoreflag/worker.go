@@ -0,0 +1,167 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package restoreflag provides a flag worker that is set on a
+// controller for as long as no backup is being restored onto it.
+//
+// When the API server restoring a backup publishes a lockdown request
+// for its machine, the flag is cleared, so that the workers depending
+// on it are stopped, and the restore fortress is locked down. The
+// response is only published once every worker occupying the fortress
+// has stopped, so the restore can safely replace the database. The
+// flag stays cleared until the agent is restarted.
+package restoreflag
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/pubsub"
+	"github.com/juju/worker/v2/catacomb"
+
+	pubsubbackups "github.com/juju/juju/pubsub/backups"
+	"github.com/juju/juju/worker/fortress"
+	"github.com/juju/juju/worker/gate"
+)
+
+// ErrRestoring indicates that a Worker has stopped because a restore
+// has started and its Check result is no longer valid.
+var ErrRestoring = errors.New("restore started")
+
+// Logger defines the methods needed for the worker to log messages.
+type Logger interface {
+	Infof(string, ...interface{})
+	Errorf(string, ...interface{})
+}
+
+// Config holds the dependencies and configuration for a Worker.
+type Config struct {
+	Hub       *pubsub.StructuredHub
+	Guard     fortress.Guard
+	MachineID string
+	Logger    Logger
+
+	// Started is unlocked when a restore starts. It outlives the
+	// worker so that the flag stays cleared when it is restarted.
+	Started gate.Lock
+}
+
+// Validate returns an error if the config cannot be expected to
+// drive a functional Worker.
+func (config Config) Validate() error {
+	if config.Hub == nil {
+		return errors.NotValidf("nil Hub")
+	}
+	if config.Guard == nil {
+		return errors.NotValidf("nil Guard")
+	}
+	if config.MachineID == "" {
+		return errors.NotValidf("empty MachineID")
+	}
+	if config.Logger == nil {
+		return errors.NotValidf("nil Logger")
+	}
+	if config.Started == nil {
+		return errors.NotValidf("nil Started")
+	}
+	return nil
+}
+
+// NewWorker returns a Worker that tracks whether a restore has started
+// on the configured machine.
+func NewWorker(config Config) (*Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &Worker{
+		config:    config,
+		restoring: config.Started.IsUnlocked(),
+	}
+	if !w.restoring {
+		if err := config.Guard.Unlock(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	unsubscribe, err := config.Hub.Subscribe(pubsubbackups.RestoreLockdownTopic, w.handleLockdown)
+	if err != nil {
+		return nil, errors.Annotatef(err, "subscribing to %q", pubsubbackups.RestoreLockdownTopic)
+	}
+	w.unsubscribe = unsubscribe
+	if err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	}); err != nil {
+		unsubscribe()
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// Worker implements worker.Worker and engine.Flag, and exits with
+// ErrRestoring once a restore starts.
+type Worker struct {
+	catacomb    catacomb.Catacomb
+	config      Config
+	restoring   bool
+	unsubscribe func()
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *Worker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *Worker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+// Check is part of the engine.Flag interface.
+//
+// Check returns true unless a restore had started when the worker
+// was created.
+func (w *Worker) Check() bool {
+	return !w.restoring
+}
+
+func (w *Worker) loop() error {
+	defer w.unsubscribe()
+	var started <-chan struct{}
+	if !w.restoring {
+		started = w.config.Started.Unlocked()
+	}
+	select {
+	case <-w.catacomb.Dying():
+		return w.catacomb.ErrDying()
+	case <-started:
+		return ErrRestoring
+	}
+}
+
+func (w *Worker) handleLockdown(_ string, req pubsubbackups.RestoreLockdownRequest, err error) {
+	if err != nil {
+		w.config.Logger.Errorf("restore lockdown callback error: %v", err)
+		return
+	}
+	if req.MachineID != w.config.MachineID {
+		return
+	}
+	if req.ResponseTopic == "" {
+		w.config.Logger.Errorf("restore lockdown request without response topic")
+		return
+	}
+	w.config.Logger.Infof("stopping controller workers for restore")
+	w.config.Started.Unlock()
+	// Waiting for the workers to stop must not hold up the other
+	// subscribers to the hub, nor be cut short when this worker is
+	// bounced by the flag change.
+	go func() {
+		var response pubsubbackups.RestoreLockdownResponse
+		// The lockdown is never aborted; the agent is restarted once
+		// the restore has finished.
+		if err := w.config.Guard.Lockdown(nil); err != nil {
+			response.Error = err.Error()
+		}
+		if _, err := w.config.Hub.Publish(req.ResponseTopic, response); err != nil {
+			w.config.Logger.Errorf("publishing restore lockdown response: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,196 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package restoreflag_test
+
+import (
+	"time"
+
+	"github.com/juju/loggo"
+	"github.com/juju/names/v4"
+	"github.com/juju/pubsub"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v2/workertest"
+	gc "gopkg.in/check.v1"
+
+	pubsubbackups "github.com/juju/juju/pubsub/backups"
+	"github.com/juju/juju/pubsub/centralhub"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/fortress"
+	"github.com/juju/juju/worker/gate"
+	"github.com/juju/juju/worker/restoreflag"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+
+	hub    *pubsub.StructuredHub
+	guard  *fakeGuard
+	config restoreflag.Config
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.hub = centralhub.New(names.NewMachineTag("0"))
+	s.guard = &fakeGuard{lockedDown: make(chan struct{})}
+	s.config = restoreflag.Config{
+		Hub:       s.hub,
+		Guard:     s.guard,
+		MachineID: "0",
+		Logger:    loggo.GetLogger("test"),
+		Started:   gate.NewLock(),
+	}
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	type test struct {
+		f      func(*restoreflag.Config)
+		expect string
+	}
+	tests := []test{{
+		func(cfg *restoreflag.Config) { cfg.Hub = nil },
+		"nil Hub not valid",
+	}, {
+		func(cfg *restoreflag.Config) { cfg.Guard = nil },
+		"nil Guard not valid",
+	}, {
+		func(cfg *restoreflag.Config) { cfg.MachineID = "" },
+		"empty MachineID not valid",
+	}, {
+		func(cfg *restoreflag.Config) { cfg.Logger = nil },
+		"nil Logger not valid",
+	}, {
+		func(cfg *restoreflag.Config) { cfg.Started = nil },
+		"nil Started not valid",
+	}}
+	for i, test := range tests {
+		c.Logf("test #%d (%s)", i, test.expect)
+		config := s.config
+		test.f(&config)
+		w, err := restoreflag.NewWorker(config)
+		c.Check(w, gc.IsNil)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
+
+func (s *WorkerSuite) TestNotRestoring(c *gc.C) {
+	w, err := restoreflag.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	c.Check(w.Check(), jc.IsTrue)
+	s.guard.CheckCallNames(c, "Unlock")
+}
+
+func (s *WorkerSuite) TestRestoring(c *gc.C) {
+	s.config.Started.Unlock()
+	w, err := restoreflag.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	c.Check(w.Check(), jc.IsFalse)
+	s.guard.CheckNoCalls(c)
+}
+
+func (s *WorkerSuite) requestLockdown(c *gc.C, machineID string) <-chan pubsubbackups.RestoreLockdownResponse {
+	responses := make(chan pubsubbackups.RestoreLockdownResponse, 1)
+	unsubscribe, err := s.hub.Subscribe("test.response", func(_ string, resp pubsubbackups.RestoreLockdownResponse, err error) {
+		c.Check(err, jc.ErrorIsNil)
+		responses <- resp
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { unsubscribe() })
+
+	_, err = s.hub.Publish(pubsubbackups.RestoreLockdownTopic, pubsubbackups.RestoreLockdownRequest{
+		MachineID:     machineID,
+		ResponseTopic: "test.response",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return responses
+}
+
+func (s *WorkerSuite) TestLockdown(c *gc.C) {
+	w, err := restoreflag.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, w)
+
+	responses := s.requestLockdown(c, "0")
+
+	// The flag is bounced as soon as the restore starts.
+	err = workertest.CheckKilled(c, w)
+	c.Assert(err, gc.Equals, restoreflag.ErrRestoring)
+
+	// The response waits for the workers in the fortress to stop.
+	select {
+	case resp := <-responses:
+		c.Fatalf("unexpected response %#v", resp)
+	case <-time.After(coretesting.ShortWait):
+	}
+	close(s.guard.lockedDown)
+	select {
+	case resp := <-responses:
+		c.Check(resp, jc.DeepEquals, pubsubbackups.RestoreLockdownResponse{})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for response")
+	}
+	s.guard.CheckCallNames(c, "Unlock", "Lockdown")
+
+	// A restarted flag stays cleared.
+	w2, err := restoreflag.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w2)
+	c.Check(w2.Check(), jc.IsFalse)
+}
+
+func (s *WorkerSuite) TestLockdownError(c *gc.C) {
+	s.guard.SetErrors(nil, fortress.ErrShutdown)
+	close(s.guard.lockedDown)
+	w, err := restoreflag.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, w)
+
+	responses := s.requestLockdown(c, "0")
+	select {
+	case resp := <-responses:
+		c.Check(resp.Error, gc.Equals, "fortress worker shutting down")
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for response")
+	}
+}
+
+func (s *WorkerSuite) TestLockdownOtherMachine(c *gc.C) {
+	w, err := restoreflag.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	responses := s.requestLockdown(c, "1")
+	select {
+	case resp := <-responses:
+		c.Fatalf("unexpected response %#v", resp)
+	case <-time.After(coretesting.ShortWait):
+	}
+	workertest.CheckAlive(c, w)
+	c.Check(s.config.Started.IsUnlocked(), jc.IsFalse)
+	s.guard.CheckCallNames(c, "Unlock")
+}
+
+type fakeGuard struct {
+	testing.Stub
+	lockedDown chan struct{}
+}
+
+// Unlock is part of the fortress.Guard interface.
+func (g *fakeGuard) Unlock() error {
+	g.AddCall("Unlock")
+	return g.NextErr()
+}
+
+// Lockdown is part of the fortress.Guard interface.
+func (g *fakeGuard) Lockdown(abort fortress.Abort) error {
+	g.AddCall("Lockdown")
+	<-g.lockedDown
+	return g.NextErr()
+}
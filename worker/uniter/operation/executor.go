@@ -8,12 +8,16 @@ import (
 
 	"github.com/juju/errors"
 
+	"github.com/juju/juju/core/hooktrace"
 	"github.com/juju/juju/worker/uniter/remotestate"
 )
 
 type executorStep struct {
 	verb string
 	run  func(op Operation, state State) (*State, error)
+
+	// last is true for the final step of an operation.
+	last bool
 }
 
 func (step executorStep) message(op Operation, unitName string) string {
@@ -21,9 +25,9 @@ func (step executorStep) message(op Operation, unitName string) string {
 }
 
 var (
-	stepPrepare = executorStep{"preparing", Operation.Prepare}
-	stepExecute = executorStep{"executing", Operation.Execute}
-	stepCommit  = executorStep{"committing", Operation.Commit}
+	stepPrepare = executorStep{"preparing", Operation.Prepare, false}
+	stepExecute = executorStep{"executing", Operation.Execute, false}
+	stepCommit  = executorStep{"committing", Operation.Commit, true}
 )
 
 type executor struct {
@@ -31,7 +35,12 @@ type executor struct {
 	stateOps           *StateOps
 	state              *State
 	acquireMachineLock func(string) (func(), error)
+	hookTracer         *hooktrace.Recorder
 	logger             Logger
+
+	// savedHookTraces holds the encoded hook traces last saved with
+	// the unit's state.
+	savedHookTraces string
 }
 
 // ExecutorConfig defines configuration for an Executor.
@@ -40,6 +49,11 @@ type ExecutorConfig struct {
 	InitialState    State
 	AcquireLock     func(string) (func(), error)
 	Logger          Logger
+
+	// HookTracer, if set, records a trace of each operation run that
+	// needs the machine lock. The recent traces are saved along with
+	// the uniter state, when it's next written.
+	HookTracer *hooktrace.Recorder
 }
 
 func (e ExecutorConfig) validate() error {
@@ -66,12 +80,26 @@ func NewExecutor(unitName string, cfg ExecutorConfig) (Executor, error) {
 	} else if err != nil {
 		return nil, err
 	}
+	var savedHookTraces string
+	if cfg.HookTracer != nil {
+		// Losing earlier traces doesn't stop the unit running hooks.
+		if data, err := stateOps.ReadHookTraces(); err != nil {
+			cfg.Logger.Warningf("cannot read hook traces for %s: %v", unitName, err)
+		} else if traces, err := hooktrace.Decode(data); err != nil {
+			cfg.Logger.Warningf("cannot read hook traces for %s: %v", unitName, err)
+		} else {
+			cfg.HookTracer.Restore(traces)
+			savedHookTraces = data
+		}
+	}
 	return &executor{
 		unitName:           unitName,
 		stateOps:           stateOps,
 		state:              state,
 		acquireMachineLock: cfg.AcquireLock,
+		hookTracer:         cfg.HookTracer,
 		logger:             cfg.Logger,
+		savedHookTraces:    savedHookTraces,
 	}, nil
 }
 
@@ -81,14 +109,19 @@ func (x *executor) State() State {
 }
 
 // Run is part of the Executor interface.
-func (x *executor) Run(op Operation, remoteStateChange <-chan remotestate.Snapshot) error {
+func (x *executor) Run(op Operation, remoteStateChange <-chan remotestate.Snapshot) (err error) {
 	x.logger.Debugf("running operation %v for %s", op, x.unitName)
 
 	if op.NeedsGlobalMachineLock() {
+		x.hookTracer.Start(op.String())
+		// The trace is normally finished by the commit step; this
+		// finishes it if the operation failed before then.
+		defer func() { x.hookTracer.Finish(err) }()
 		releaser, err := x.acquireMachineLock(op.String())
 		if err != nil {
 			return errors.Annotatef(err, "could not acquire %q lock for %s", op, x.unitName)
 		}
+		x.hookTracer.LockAcquired()
 		defer x.logger.Debugf("lock released for %s", x.unitName)
 		defer releaser()
 	}
@@ -131,6 +164,11 @@ func (x *executor) do(op Operation, step executorStep) (err error) {
 	message := step.message(op, x.unitName)
 	x.logger.Debugf(message)
 	newState, firstErr := step.run(op, *x.state)
+	if step.last {
+		// The trace is finished before the new state is written, so
+		// that it's saved along with it.
+		x.hookTracer.Finish(errors.Annotatef(firstErr, message))
+	}
 	if newState != nil {
 		writeErr := x.writeState(*newState)
 		if firstErr == nil {
@@ -149,9 +187,31 @@ func (x *executor) writeState(newState State) error {
 	if x.state != nil && x.state.match(newState) {
 		return nil
 	}
-	if err := x.stateOps.Write(&newState); err != nil {
+	hookTraces := x.unsavedHookTraces()
+	if err := x.stateOps.WriteWithHookTraces(&newState, hookTraces); err != nil {
 		return errors.Annotatef(err, "writing state")
 	}
 	x.state = &newState
+	if hookTraces != nil {
+		x.savedHookTraces = *hookTraces
+	}
 	return nil
 }
+
+// unsavedHookTraces returns the encoded recent hook traces if they have
+// changed since they were last saved, or nil. Failing to encode them
+// doesn't stop the state being written.
+func (x *executor) unsavedHookTraces() *string {
+	if x.hookTracer == nil {
+		return nil
+	}
+	data, err := hooktrace.Encode(x.hookTracer.Traces())
+	if err != nil {
+		x.logger.Errorf("saving hook traces for %s: %v", x.unitName, err)
+		return nil
+	}
+	if data == x.savedHookTraces {
+		return nil
+	}
+	return &data
+}
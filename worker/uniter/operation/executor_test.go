@@ -8,6 +8,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/juju/charm/v8/hooks"
+	"github.com/juju/clock/testclock"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/testing"
@@ -16,6 +17,7 @@ import (
 	"gopkg.in/yaml.v2"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/hooktrace"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/operation"
 	"github.com/juju/juju/worker/uniter/operation/mocks"
@@ -595,6 +597,95 @@ func (s *ExecutorSuite) TestLockUnlocksOnError_Commit(c *gc.C) {
 	c.Assert(mockLock.stepsCalledOnUnlock, gc.DeepEquals, expectedStepsOnUnlock)
 }
 
+func (s *ExecutorSuite) TestRunRecordsHookTrace(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+	clock := testclock.NewClock(time.Date(2021, 4, 1, 12, 0, 0, 0, time.UTC))
+	previous, err := hooktrace.Encode([]hooktrace.Trace{{Operation: "previous operation"}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	initialState := justInstalledState()
+	s.expectState(c, initialState)
+	s.mockStateRW.EXPECT().State().Return(params.UnitStateResult{HookTraces: previous}, nil)
+	// The traces are saved along with the operation's final state,
+	// rather than written separately.
+	commitState := operation.State{Kind: operation.Continue, Step: operation.Done}
+	var saved string
+	s.mockStateRW.EXPECT().SetState(gomock.Any()).DoAndReturn(func(arg params.SetUnitStateArg) error {
+		c.Assert(arg.UniterState, gc.NotNil)
+		c.Assert(arg.HookTraces, gc.NotNil)
+		saved = *arg.HookTraces
+		return nil
+	})
+
+	tracer := hooktrace.NewRecorder(clock)
+	executor, err := operation.NewExecutor("test", operation.ExecutorConfig{
+		StateReadWriter: s.mockStateRW,
+		InitialState:    operation.State{Step: operation.Queued},
+		AcquireLock: func(string) (func(), error) {
+			clock.Advance(time.Second)
+			return func() {}, nil
+		},
+		Logger:     loggo.GetLogger("test"),
+		HookTracer: tracer,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	op := &mockOperation{
+		needsLock: true,
+		prepare:   newStep(nil, nil),
+		execute: mockStepFunc(func(operation.State) (*operation.State, error) {
+			clock.Advance(2 * time.Second)
+			return nil, nil
+		}),
+		commit: newStep(&commitState, nil),
+	}
+	err = executor.Run(op, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	traces, err := hooktrace.Decode(saved)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(traces, jc.DeepEquals, tracer.Traces())
+	c.Assert(traces, gc.HasLen, 2)
+	c.Check(traces[0].Operation, gc.Equals, "previous operation")
+	c.Check(traces[1].Operation, gc.Equals, "mock operation")
+	c.Check(traces[1].LockWait, gc.Equals, time.Second)
+	c.Check(traces[1].Duration, gc.Equals, 3*time.Second)
+	c.Check(traces[1].Error, gc.Equals, "")
+}
+
+func (s *ExecutorSuite) TestRunNoLockNotTraced(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+	initialState := justInstalledState()
+	s.expectState(c, initialState)
+	s.mockStateRW.EXPECT().State().Return(params.UnitStateResult{}, nil)
+	// Unchanged traces aren't sent with the state.
+	commitState := operation.State{Kind: operation.Continue, Step: operation.Done}
+	s.mockStateRW.EXPECT().SetState(gomock.Any()).DoAndReturn(func(arg params.SetUnitStateArg) error {
+		c.Assert(arg.UniterState, gc.NotNil)
+		c.Assert(arg.HookTraces, gc.IsNil)
+		return nil
+	})
+
+	tracer := hooktrace.NewRecorder(testclock.NewClock(time.Time{}))
+	executor, err := operation.NewExecutor("test", operation.ExecutorConfig{
+		StateReadWriter: s.mockStateRW,
+		InitialState:    operation.State{Step: operation.Queued},
+		AcquireLock:     failAcquireLock,
+		Logger:          loggo.GetLogger("test"),
+		HookTracer:      tracer,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	op := &mockOperation{
+		prepare: newStep(nil, nil),
+		execute: newStep(nil, nil),
+		commit:  newStep(&commitState, nil),
+	}
+	err = executor.Run(op, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tracer.Traces(), gc.HasLen, 0)
+}
+
 type mockLockFunc struct {
 	noStepsCalledOnLock bool
 	stepsCalledOnUnlock []bool
@@ -135,6 +135,12 @@ func (rh *runHook) Execute(state State) (*State, error) {
 	default:
 		rh.logger.Errorf("hook %q (via %s) failed: %v", rh.name, handlerType, err)
 		rh.callbacks.NotifyHookFailed(rh.name, rh.runner.Context())
+		if runner.IsTimeoutError(cause) {
+			// Record the timeout so the hook error can be reported
+			// as such.
+			state.HookTimedOut = true
+			return &state, ErrHookFailed
+		}
 		return nil, ErrHookFailed
 	}
 
@@ -91,6 +91,10 @@ type State struct {
 	// upgrade is complete (instead of running an upgrade-charm hook).
 	Hook *hook.Info `yaml:"hook,omitempty"`
 
+	// HookTimedOut indicates that the hook held in Hook failed because
+	// it ran for longer than the application allows.
+	HookTimedOut bool `yaml:"hook-timed-out,omitempty"`
+
 	// ActionId holds action information relevant to the current operation. If
 	// Kind is Continue, it holds the last action that was executed; if Kind is
 	// RunAction, it holds the running action.
@@ -196,6 +200,7 @@ func (change stateChange) apply(state State) *State {
 	state.Kind = change.Kind
 	state.Step = change.Step
 	state.Hook = change.Hook
+	state.HookTimedOut = false
 	state.ActionId = change.ActionId
 	state.CharmURL = change.CharmURL
 	state.StatusSet = state.StatusSet || change.HasRunStatusSet
@@ -242,6 +247,12 @@ func (f *StateOps) Read() (*State, error) {
 
 // Write stores the supplied state on the controller.
 func (f *StateOps) Write(st *State) error {
+	return f.WriteWithHookTraces(st, nil)
+}
+
+// WriteWithHookTraces stores the supplied state on the controller,
+// along with the encoded hook traces if they're not nil.
+func (f *StateOps) WriteWithHookTraces(st *State, hookTraces *string) error {
 	if err := st.Validate(); err != nil {
 		return errors.Trace(err)
 	}
@@ -250,5 +261,18 @@ func (f *StateOps) Write(st *State) error {
 		return errors.Trace(err)
 	}
 	s := string(data)
-	return f.unitStateRW.SetState(params.SetUnitStateArg{UniterState: &s})
+	return f.unitStateRW.SetState(params.SetUnitStateArg{
+		UniterState: &s,
+		HookTraces:  hookTraces,
+	})
+}
+
+// ReadHookTraces returns the encoded recent hook traces saved on the
+// controller.
+func (f *StateOps) ReadHookTraces() (string, error) {
+	unitState, err := f.unitStateRW.State()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return unitState.HookTraces, nil
 }
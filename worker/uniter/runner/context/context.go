@@ -25,9 +25,11 @@ import (
 	"github.com/juju/juju/caas"
 	k8sspecs "github.com/juju/juju/caas/kubernetes/provider/specs"
 	"github.com/juju/juju/core/application"
+	"github.com/juju/juju/core/hooktrace"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/quota"
+	"github.com/juju/juju/core/secrets"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/juju/sockets"
 	"github.com/juju/juju/version"
@@ -233,6 +235,14 @@ type HookContext struct {
 	// meterStatus is the status of the unit's metering.
 	meterStatus *meterStatus
 
+	// hookLimits holds the limits placed on the hooks and actions run
+	// in this context.
+	hookLimits application.HookLimits
+
+	// hookTracer records the hook tools run and the time spent
+	// flushing this context.
+	hookTracer *hooktrace.Recorder
+
 	// a helper for recording requests to open/close port ranges for this unit.
 	portRangeChanges *portRangeChangeRecorder
 
@@ -251,6 +261,9 @@ type HookContext struct {
 	// storage provides access to the information about storage attached to the unit.
 	storage StorageContextAccessor
 
+	// secrets provides access to the secrets the unit manages and consumes.
+	secrets SecretsAccessor
+
 	// storageId is the tag of the storage instance associated with the running hook.
 	storageTag names.StorageTag
 
@@ -411,6 +424,96 @@ func (ctx *HookContext) ensureCharmStateLoaded() error {
 	return nil
 }
 
+func (ctx *HookContext) secretsAccessor() (SecretsAccessor, error) {
+	if ctx.secrets == nil {
+		return nil, errors.NotSupportedf("secrets")
+	}
+	return ctx.secrets, nil
+}
+
+// CreateSecret creates a secret with the specified data, returning its URI.
+// Implements jujuc.HookContext.ContextSecrets, part of runner.Context.
+func (ctx *HookContext) CreateSecret(args *jujuc.SecretCreateArgs) (string, error) {
+	accessor, err := ctx.secretsAccessor()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	arg := params.CreateSecretArg{
+		Data:       args.Value,
+		ExpireTime: args.ExpireTime,
+	}
+	if args.Description != nil {
+		arg.Description = *args.Description
+	}
+	if args.RotatePolicy != nil {
+		arg.RotatePolicy = string(*args.RotatePolicy)
+	}
+	return accessor.CreateSecret(args.OwnerTag, arg)
+}
+
+// UpdateSecret updates the attributes of the secret with the specified
+// URI, adding a new revision if its value is set.
+// Implements jujuc.HookContext.ContextSecrets, part of runner.Context.
+func (ctx *HookContext) UpdateSecret(uri string, args *jujuc.SecretUpdateArgs) error {
+	accessor, err := ctx.secretsAccessor()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	arg := params.UpdateSecretArg{
+		URI:         uri,
+		Description: args.Description,
+		ExpireTime:  args.ExpireTime,
+		Data:        args.Value,
+	}
+	if args.RotatePolicy != nil {
+		policy := string(*args.RotatePolicy)
+		arg.RotatePolicy = &policy
+	}
+	return accessor.UpdateSecret(arg)
+}
+
+// GetSecret returns the value of the secret with the specified URI.
+// Implements jujuc.HookContext.ContextSecrets, part of runner.Context.
+func (ctx *HookContext) GetSecret(uri string, peek, refresh bool) (secrets.SecretData, error) {
+	accessor, err := ctx.secretsAccessor()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return accessor.GetSecretValue(uri, peek, refresh)
+}
+
+// GrantSecret grants access to the secret with the specified URI.
+// Implements jujuc.HookContext.ContextSecrets, part of runner.Context.
+func (ctx *HookContext) GrantSecret(uri string, args *jujuc.SecretGrantRevokeArgs) error {
+	accessor, err := ctx.secretsAccessor()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return accessor.GrantSecret(uri, secretSubjects(args))
+}
+
+// RevokeSecret revokes access to the secret with the specified URI.
+// Implements jujuc.HookContext.ContextSecrets, part of runner.Context.
+func (ctx *HookContext) RevokeSecret(uri string, args *jujuc.SecretGrantRevokeArgs) error {
+	accessor, err := ctx.secretsAccessor()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return accessor.RevokeSecret(uri, secretSubjects(args))
+}
+
+// secretSubjects returns the unit to grant or revoke access to a secret
+// if one was specified, and otherwise the application.
+func secretSubjects(args *jujuc.SecretGrantRevokeArgs) []names.Tag {
+	if args.UnitName != nil {
+		return []names.Tag{names.NewUnitTag(*args.UnitName)}
+	}
+	if args.ApplicationName != nil {
+		return []names.Tag{names.NewApplicationTag(*args.ApplicationName)}
+	}
+	return nil
+}
+
 // Component returns the ContextComponent with the supplied name if
 // it was found.
 // Implements jujuc.HookContext.ContextComponents, part of runner.Context.
@@ -499,6 +602,20 @@ func (ctx *HookContext) ModelType() model.ModelType {
 	return ctx.modelType
 }
 
+// HookLimits returns the limits placed on the hooks and actions run in
+// this context.
+// Implements runner.Context.
+func (ctx *HookContext) HookLimits() application.HookLimits {
+	return ctx.hookLimits
+}
+
+// HookTracer returns the recorder tracing the hooks and actions run in
+// this context.
+// Implements runner.Context.
+func (ctx *HookContext) HookTracer() *hooktrace.Recorder {
+	return ctx.hookTracer
+}
+
 // UnitStatus will return the status for the current Unit.
 // Implements jujuc.HookContext.ContextStatus, part of runner.Context.
 func (ctx *HookContext) UnitStatus() (*jujuc.StatusInfo, error) {
@@ -22,6 +22,7 @@ import (
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/quota"
+	"github.com/juju/juju/core/secrets"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/worker/common/charmrunner"
 	"github.com/juju/juju/worker/uniter/runner"
@@ -845,3 +846,117 @@ func (s *mockHookContextSuite) TestMissingAction(c *gc.C) {
 	err := hookContext.Flush("action", charmrunner.NewMissingHookError("noaction"))
 	c.Assert(err, jc.ErrorIsNil)
 }
+
+type secretsHookContextSuite struct {
+	testing.IsolationSuite
+	accessor *stubSecretsAccessor
+}
+
+var _ = gc.Suite(&secretsHookContextSuite{})
+
+const secretURI = "secret:4a2b3f5c-3e0c-4c5d-8a8e-2d1f6c5e9b7a"
+
+func (s *secretsHookContextSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.accessor = &stubSecretsAccessor{}
+}
+
+func (s *secretsHookContextSuite) TestNotSupported(c *gc.C) {
+	hookContext := context.NewSecretsHookContext("mysql/0", nil)
+	_, err := hookContext.GetSecret(secretURI, false, false)
+	c.Assert(err, gc.ErrorMatches, "secrets not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *secretsHookContextSuite) TestCreateSecret(c *gc.C) {
+	hookContext := context.NewSecretsHookContext("mysql/0", s.accessor)
+	description := "db password"
+	daily := secrets.RotateDaily
+	uri, err := hookContext.CreateSecret(&jujuc.SecretCreateArgs{
+		SecretUpdateArgs: jujuc.SecretUpdateArgs{
+			Description:  &description,
+			RotatePolicy: &daily,
+			Value:        secrets.SecretData{"password": "s3cret"},
+		},
+		OwnerTag: names.NewApplicationTag("mysql"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(uri, gc.Equals, secretURI)
+	s.accessor.CheckCalls(c, []testing.StubCall{{"CreateSecret", []interface{}{
+		names.NewApplicationTag("mysql"),
+		params.CreateSecretArg{
+			Description:  "db password",
+			RotatePolicy: "daily",
+			Data:         map[string]string{"password": "s3cret"},
+		},
+	}}})
+}
+
+func (s *secretsHookContextSuite) TestUpdateSecret(c *gc.C) {
+	hookContext := context.NewSecretsHookContext("mysql/0", s.accessor)
+	hourly := secrets.RotateHourly
+	err := hookContext.UpdateSecret(secretURI, &jujuc.SecretUpdateArgs{
+		RotatePolicy: &hourly,
+		Value:        secrets.SecretData{"password": "n3w"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	policy := "hourly"
+	s.accessor.CheckCalls(c, []testing.StubCall{{"UpdateSecret", []interface{}{
+		params.UpdateSecretArg{
+			URI:          secretURI,
+			RotatePolicy: &policy,
+			Data:         map[string]string{"password": "n3w"},
+		},
+	}}})
+}
+
+func (s *secretsHookContextSuite) TestGetSecret(c *gc.C) {
+	hookContext := context.NewSecretsHookContext("mysql/0", s.accessor)
+	value, err := hookContext.GetSecret(secretURI, false, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(value, jc.DeepEquals, secrets.SecretData{"password": "s3cret"})
+	s.accessor.CheckCall(c, 0, "GetSecretValue", secretURI, false, true)
+}
+
+func (s *secretsHookContextSuite) TestGrantRevokeSecret(c *gc.C) {
+	hookContext := context.NewSecretsHookContext("mysql/0", s.accessor)
+	app := "wordpress"
+	unit := "wordpress/0"
+	err := hookContext.GrantSecret(secretURI, &jujuc.SecretGrantRevokeArgs{ApplicationName: &app})
+	c.Assert(err, jc.ErrorIsNil)
+	err = hookContext.RevokeSecret(secretURI, &jujuc.SecretGrantRevokeArgs{ApplicationName: &app, UnitName: &unit})
+	c.Assert(err, jc.ErrorIsNil)
+	s.accessor.CheckCalls(c, []testing.StubCall{
+		{"GrantSecret", []interface{}{secretURI, []names.Tag{names.NewApplicationTag("wordpress")}}},
+		{"RevokeSecret", []interface{}{secretURI, []names.Tag{names.NewUnitTag("wordpress/0")}}},
+	})
+}
+
+type stubSecretsAccessor struct {
+	testing.Stub
+}
+
+func (s *stubSecretsAccessor) CreateSecret(owner names.Tag, arg params.CreateSecretArg) (string, error) {
+	s.MethodCall(s, "CreateSecret", owner, arg)
+	return secretURI, s.NextErr()
+}
+
+func (s *stubSecretsAccessor) UpdateSecret(arg params.UpdateSecretArg) error {
+	s.MethodCall(s, "UpdateSecret", arg)
+	return s.NextErr()
+}
+
+func (s *stubSecretsAccessor) GetSecretValue(uri string, peek, refresh bool) (secrets.SecretData, error) {
+	s.MethodCall(s, "GetSecretValue", uri, peek, refresh)
+	return secrets.SecretData{"password": "s3cret"}, s.NextErr()
+}
+
+func (s *stubSecretsAccessor) GrantSecret(uri string, subjects []names.Tag) error {
+	s.MethodCall(s, "GrantSecret", uri, subjects)
+	return s.NextErr()
+}
+
+func (s *stubSecretsAccessor) RevokeSecret(uri string, subjects []names.Tag) error {
+	s.MethodCall(s, "RevokeSecret", uri, subjects)
+	return s.NextErr()
+}
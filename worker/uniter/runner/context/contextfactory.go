@@ -15,9 +15,11 @@ import (
 
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/hooktrace"
 	"github.com/juju/juju/core/leadership"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/secrets"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
 )
@@ -59,6 +61,28 @@ type StorageContextAccessor interface {
 	Storage(names.StorageTag) (jujuc.ContextStorageAttachment, error)
 }
 
+// SecretsAccessor is an interface providing access to the secrets
+// managed and consumed by a unit.
+type SecretsAccessor interface {
+	// CreateSecret creates a secret owned by the given application or
+	// unit, returning its URI.
+	CreateSecret(owner names.Tag, arg params.CreateSecretArg) (string, error)
+
+	// UpdateSecret updates the attributes of a secret.
+	UpdateSecret(arg params.UpdateSecretArg) error
+
+	// GetSecretValue returns the value of the secret with the given URI.
+	GetSecretValue(uri string, peek, refresh bool) (secrets.SecretData, error)
+
+	// GrantSecret grants the given applications and units access to the
+	// secret with the given URI.
+	GrantSecret(uri string, subjects []names.Tag) error
+
+	// RevokeSecret revokes the access of the given applications and
+	// units to the secret with the given URI.
+	RevokeSecret(uri string, subjects []names.Tag) error
+}
+
 // RelationsFunc is used to get snapshots of relation membership at context
 // creation time.
 type RelationsFunc func() map[int]*RelationInfo
@@ -78,9 +102,11 @@ type contextFactory struct {
 	modelType  model.ModelType
 	machineTag names.MachineTag
 	storage    StorageContextAccessor
+	secrets    SecretsAccessor
 	clock      Clock
 	zone       string
 	principal  string
+	hookTracer *hooktrace.Recorder
 
 	// Callback to get relation state snapshot.
 	getRelationInfos RelationsFunc
@@ -98,9 +124,11 @@ type FactoryConfig struct {
 	Tracker          leadership.Tracker
 	GetRelationInfos RelationsFunc
 	Storage          StorageContextAccessor
+	SecretsAccessor  SecretsAccessor
 	Paths            Paths
 	Clock            Clock
 	Logger           loggo.Logger
+	HookTracer       *hooktrace.Recorder
 }
 
 // NewContextFactory returns a ContextFactory capable of creating execution contexts backed
@@ -144,11 +172,13 @@ func NewContextFactory(config FactoryConfig) (ContextFactory, error) {
 		getRelationInfos: config.GetRelationInfos,
 		relationCaches:   map[int]*RelationCache{},
 		storage:          config.Storage,
+		secrets:          config.SecretsAccessor,
 		rand:             rand.New(rand.NewSource(time.Now().Unix())),
 		clock:            config.Clock,
 		zone:             zone,
 		principal:        principal,
 		modelType:        m.ModelType,
+		hookTracer:       config.HookTracer,
 	}
 	return f, nil
 }
@@ -178,12 +208,14 @@ func (f *contextFactory) coreContext() (*HookContext, error) {
 		relations:          f.getContextRelations(),
 		relationId:         -1,
 		storage:            f.storage,
+		secrets:            f.secrets,
 		clock:              f.clock,
 		logger:             f.logger,
 		componentDir:       f.paths.ComponentDir,
 		componentFuncs:     registeredComponentFuncs,
 		availabilityzone:   f.zone,
 		principal:          f.principal,
+		hookTracer:         f.hookTracer,
 	}
 	if err := f.updateContext(ctx); err != nil {
 		return nil, err
@@ -336,6 +368,11 @@ func (f *contextFactory) updateContext(ctx *HookContext) (err error) {
 		info: statusInfo,
 	}
 
+	ctx.hookLimits, err = f.unit.HookLimits()
+	if err != nil {
+		return errors.Annotate(err, "could not retrieve hook limits for unit")
+	}
+
 	var machPortRanges map[names.UnitTag]network.GroupedPortRanges
 	if f.modelType == model.IAAS {
 		if machPortRanges, err = f.state.OpenedMachinePortRangesByEndpoint(f.machineTag); err != nil {
@@ -105,6 +105,14 @@ func NewMockUnitHookContextWithState(unitName string, mockUnit *mocks.MockHookUn
 	}
 }
 
+func NewSecretsHookContext(unitName string, accessor SecretsAccessor) *HookContext {
+	return &HookContext{
+		unitName: unitName,
+		secrets:  accessor,
+		logger:   loggo.GetLogger("test"),
+	}
+}
+
 // SetEnvironmentHookContextRelation exists purely to set the fields used in hookVars.
 // It makes no assumptions about the validity of context.
 func SetEnvironmentHookContextRelation(context *HookContext, relationId int, endpointName, remoteUnitName, remoteAppName, departingUnitName string) {
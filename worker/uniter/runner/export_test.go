@@ -12,6 +12,9 @@ var (
 	SearchHook              = discoverHookScript
 	HookCommand             = hookCommand
 	LookPath                = lookPath
+	LimitHookCommand        = limitHookCommand
+	HookKillGrace           = &hookKillGrace
+	ExecLookPath            = &execLookPath
 )
 
 func RunnerPaths(rnr Runner) context.Paths {
@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/core/life"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/relation"
+	"github.com/juju/juju/core/secrets"
 	"github.com/juju/juju/storage"
 )
 
@@ -44,6 +45,7 @@ type HookContext interface {
 	ContextComponents
 	ContextRelations
 	ContextVersion
+	ContextSecrets
 }
 
 // UnitHookContext is the context for a unit hook.
@@ -353,6 +355,55 @@ type ContextVersion interface {
 	SetUnitWorkloadVersion(string) error
 }
 
+// ContextSecrets is the part of a hook context related to secrets.
+type ContextSecrets interface {
+	// CreateSecret creates a secret with the specified data,
+	// returning its URI.
+	CreateSecret(*SecretCreateArgs) (string, error)
+
+	// UpdateSecret updates the attributes of the secret with the
+	// specified URI, adding a new revision if its value is set.
+	UpdateSecret(string, *SecretUpdateArgs) error
+
+	// GetSecret returns the value of the secret with the specified
+	// URI. With peek, the latest revision is returned without the unit
+	// then using it; with refresh, the unit uses the latest revision
+	// from then on.
+	GetSecret(uri string, peek, refresh bool) (secrets.SecretData, error)
+
+	// GrantSecret grants access to the secret with the specified URI.
+	GrantSecret(string, *SecretGrantRevokeArgs) error
+
+	// RevokeSecret revokes access to the secret with the specified URI.
+	RevokeSecret(string, *SecretGrantRevokeArgs) error
+}
+
+// SecretUpdateArgs holds the attributes of a secret to update; nil
+// attributes are left unchanged.
+type SecretUpdateArgs struct {
+	Description  *string
+	RotatePolicy *secrets.RotatePolicy
+	ExpireTime   *time.Time
+
+	// Value, if set, is the value of a new revision of the secret.
+	Value secrets.SecretData
+}
+
+// SecretCreateArgs holds the attributes of a secret to create.
+type SecretCreateArgs struct {
+	SecretUpdateArgs
+
+	// OwnerTag is the application or unit that owns the secret.
+	OwnerTag names.Tag
+}
+
+// SecretGrantRevokeArgs holds the application, and optionally the
+// unit of it, to grant or revoke access to a secret.
+type SecretGrantRevokeArgs struct {
+	ApplicationName *string
+	UnitName        *string
+}
+
 // Settings is implemented by types that manipulate unit settings.
 type Settings interface {
 	Map() params.Settings
@@ -0,0 +1,76 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuctesting
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/secrets"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+// Secrets holds the values for the hook context.
+type Secrets struct {
+	SecretValues map[string]secrets.SecretData
+}
+
+// ContextSecrets is a test double for jujuc.ContextSecrets.
+type ContextSecrets struct {
+	contextBase
+	info *Secrets
+}
+
+// CreateSecret implements jujuc.ContextSecrets.
+func (c *ContextSecrets) CreateSecret(args *jujuc.SecretCreateArgs) (string, error) {
+	c.stub.AddCall("CreateSecret", args)
+	if err := c.stub.NextErr(); err != nil {
+		return "", errors.Trace(err)
+	}
+	uri := secrets.NewURI().String()
+	if c.info.SecretValues == nil {
+		c.info.SecretValues = make(map[string]secrets.SecretData)
+	}
+	c.info.SecretValues[uri] = args.Value
+	return uri, nil
+}
+
+// UpdateSecret implements jujuc.ContextSecrets.
+func (c *ContextSecrets) UpdateSecret(uri string, args *jujuc.SecretUpdateArgs) error {
+	c.stub.AddCall("UpdateSecret", uri, args)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := c.info.SecretValues[uri]; !ok {
+		return errors.NotFoundf("secret %q", uri)
+	}
+	if args.Value != nil {
+		c.info.SecretValues[uri] = args.Value
+	}
+	return nil
+}
+
+// GetSecret implements jujuc.ContextSecrets.
+func (c *ContextSecrets) GetSecret(uri string, peek, refresh bool) (secrets.SecretData, error) {
+	c.stub.AddCall("GetSecret", uri, peek, refresh)
+	if err := c.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	value, ok := c.info.SecretValues[uri]
+	if !ok {
+		return nil, errors.NotFoundf("secret %q", uri)
+	}
+	return value, nil
+}
+
+// GrantSecret implements jujuc.ContextSecrets.
+func (c *ContextSecrets) GrantSecret(uri string, args *jujuc.SecretGrantRevokeArgs) error {
+	c.stub.AddCall("GrantSecret", uri, args)
+	return c.stub.NextErr()
+}
+
+// RevokeSecret implements jujuc.ContextSecrets.
+func (c *ContextSecrets) RevokeSecret(uri string, args *jujuc.SecretGrantRevokeArgs) error {
+	c.stub.AddCall("RevokeSecret", uri, args)
+	return c.stub.NextErr()
+}
@@ -0,0 +1,240 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+	"github.com/juju/utils/v2/keyvalues"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/core/secrets"
+)
+
+// secretUpsertCommand holds the flags and arguments shared by the
+// secret-add and secret-set commands.
+type secretUpsertCommand struct {
+	cmd.CommandBase
+	ctx Context
+
+	description  optionalString
+	rotatePolicy string
+	expireSpec   string
+	valueFile    cmd.FileVar
+
+	data       secrets.SecretData
+	expireTime time.Time
+}
+
+func (c *secretUpsertCommand) setFlags(f *gnuflag.FlagSet) {
+	f.Var(&c.description, "description", "the secret description")
+	f.StringVar(&c.rotatePolicy, "rotate", "", "the secret rotation policy")
+	f.StringVar(&c.expireSpec, "expire", "", "either a duration or time when the secret should expire")
+	c.valueFile.SetStdin()
+	f.Var(&c.valueFile, "file", "a YAML file containing secret key values")
+}
+
+func (c *secretUpsertCommand) init(args []string) error {
+	if !secrets.RotatePolicy(c.rotatePolicy).IsValid() {
+		return errors.NotValidf("rotate policy %q", c.rotatePolicy)
+	}
+	if c.expireSpec != "" {
+		expireTime, err := parseExpireSpec(c.expireSpec, time.Now())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		c.expireTime = expireTime
+	}
+	// The values in the file, if any, are overridden in Run.
+	data, err := keyvalues.Parse(args, true)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.data = data
+	return nil
+}
+
+// parseExpireSpec parses the --expire value, which is either a duration
+// from now or an RFC3339 time.
+func parseExpireSpec(spec string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(spec); err == nil {
+		if d <= 0 {
+			return time.Time{}, errors.NotValidf("negative expire duration %q", spec)
+		}
+		return now.Add(d).UTC(), nil
+	}
+	t, err := time.Parse(time.RFC3339, spec)
+	if err != nil {
+		return time.Time{}, errors.NotValidf("expire time or duration %q", spec)
+	}
+	return t.UTC(), nil
+}
+
+// readValue reads the secret value from the --file, if given, with the
+// key=value arguments taking precedence.
+func (c *secretUpsertCommand) readValue(ctx *cmd.Context) (secrets.SecretData, error) {
+	if c.valueFile.Path == "" {
+		return c.data, nil
+	}
+	file, err := c.valueFile.Open(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer func() { _ = file.Close() }()
+
+	data, err := readSettings(file)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for k, v := range c.data {
+		data[k] = v
+	}
+	return data, nil
+}
+
+func (c *secretUpsertCommand) updateArgs(value secrets.SecretData) SecretUpdateArgs {
+	args := SecretUpdateArgs{
+		Description: c.description.value,
+	}
+	if len(value) > 0 {
+		args.Value = value
+	}
+	if c.rotatePolicy != "" {
+		policy := secrets.RotatePolicy(c.rotatePolicy)
+		args.RotatePolicy = &policy
+	}
+	if !c.expireTime.IsZero() {
+		expireTime := c.expireTime
+		args.ExpireTime = &expireTime
+	}
+	return args
+}
+
+// optionalString is a gnuflag.Value recording whether a string flag
+// was set at all, so that an empty value can be told apart from none.
+type optionalString struct {
+	value *string
+}
+
+// Set implements gnuflag.Value.
+func (v *optionalString) Set(s string) error {
+	v.value = &s
+	return nil
+}
+
+// String implements gnuflag.Value.
+func (v *optionalString) String() string {
+	if v.value == nil {
+		return ""
+	}
+	return *v.value
+}
+
+// SecretAddCommand implements the secret-add command.
+type SecretAddCommand struct {
+	secretUpsertCommand
+	owner string
+}
+
+// NewSecretAddCommand returns a secret-add command.
+func NewSecretAddCommand(ctx Context) (cmd.Command, error) {
+	return &SecretAddCommand{secretUpsertCommand: secretUpsertCommand{ctx: ctx}}, nil
+}
+
+// Info returns information about the Command.
+// Info implements part of the cmd.Command interface.
+func (c *SecretAddCommand) Info() *cmd.Info {
+	doc := `
+secret-add creates a secret with the specified key values, and prints
+its URI. The secret is encrypted at rest, and may only be read by its
+owner and the applications and units it is granted to with secret-grant.
+
+By default the secret is owned by the application, in which case only
+the leader unit may add it; with --owner unit it's owned by the unit.
+
+The --file option should be used when one or more key values are too
+long to fit within the command length limit of the shell or operating
+system. The file will contain a YAML map of the key values, which are
+overridden by any duplicate key=value arguments. A value of "-" for the
+filename means <stdin>.
+
+The rotation policy, if any, is one of never, hourly, daily, weekly,
+monthly, quarterly or yearly. The expiry is either a duration from now,
+such as 24h, or an RFC3339 time.
+
+Examples:
+    secret-add password=s3cret
+    secret-add --description "db credentials" --rotate monthly \
+        username=admin password=s3cret
+    secret-add --owner unit --expire 24h token=abc123
+
+See also:
+    secret-get
+    secret-grant
+    secret-revoke
+    secret-set
+`
+	return jujucmd.Info(&cmd.Info{
+		Name:    "secret-add",
+		Args:    "[key=value ...]",
+		Purpose: "add a new secret",
+		Doc:     doc,
+	})
+}
+
+// SetFlags adds command specific flags to the flag set.
+// SetFlags implements part of the cmd.Command interface.
+func (c *SecretAddCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.setFlags(f)
+	f.StringVar(&c.owner, "owner", "application", "the owner of the secret, either the application or unit")
+}
+
+// Init initializes the Command before running.
+// Init implements part of the cmd.Command interface.
+func (c *SecretAddCommand) Init(args []string) error {
+	if c.owner != "application" && c.owner != "unit" {
+		return errors.NotValidf("secret owner %q", c.owner)
+	}
+	return c.init(args)
+}
+
+// Run will execute the Command as directed by the options and positional
+// arguments passed to Init.
+// Run implements part of the cmd.Command interface.
+func (c *SecretAddCommand) Run(ctx *cmd.Context) error {
+	value, err := c.readValue(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(value) == 0 {
+		return errors.New("missing secret value")
+	}
+	if err := value.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+
+	unitName := c.ctx.UnitName()
+	var owner names.Tag = names.NewUnitTag(unitName)
+	if c.owner == "application" {
+		appName, err := names.UnitApplication(unitName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		owner = names.NewApplicationTag(appName)
+	}
+	uri, err := c.ctx.CreateSecret(&SecretCreateArgs{
+		SecretUpdateArgs: c.updateArgs(value),
+		OwnerTag:         owner,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintln(ctx.Stdout, uri)
+	return nil
+}
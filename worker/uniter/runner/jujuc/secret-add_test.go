@@ -0,0 +1,138 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/names/v4"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/secrets"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type SecretAddSuite struct {
+	secretsSuite
+}
+
+var _ = gc.Suite(&SecretAddSuite{})
+
+func (s *SecretAddSuite) TestHelp(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+	out := s.help(c, "secret-add")
+	c.Assert(strings.HasPrefix(out, `
+Usage: secret-add [options] [key=value ...]
+
+Summary:
+add a new secret
+
+Options:
+--description  (= )
+    the secret description
+--expire (= "")
+    either a duration or time when the secret should expire
+--file  (= )
+    a YAML file containing secret key values
+--owner (= "application")
+    the owner of the secret, either the application or unit
+--rotate (= "")
+    the secret rotation policy
+`[1:]), jc.IsTrue, gc.Commentf("%s", out))
+}
+
+func (s *SecretAddSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"--owner", "model", "password=s3cret"},
+		err:  `secret owner "model" not valid`,
+	}, {
+		args: []string{"--rotate", "fortnightly", "password=s3cret"},
+		err:  `rotate policy "fortnightly" not valid`,
+	}, {
+		args: []string{"--expire", "tomorrow", "password=s3cret"},
+		err:  `expire time or duration "tomorrow" not valid`,
+	}, {
+		args: []string{"password"},
+		err:  `expected "key=value", got "password"`,
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		ctrl := s.setupMocks(c)
+		code, ctx := s.run(c, "secret-add", t.args...)
+		c.Check(code, gc.Equals, 2)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, "ERROR "+t.err+"\n")
+		ctrl.Finish()
+	}
+}
+
+func (s *SecretAddSuite) TestRunErrors(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+	code, ctx := s.run(c, "secret-add")
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "ERROR missing secret value\n")
+
+	code, ctx = s.run(c, "secret-add", "Password=s3cret")
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "ERROR secret key \"Password\" not valid\n")
+}
+
+func (s *SecretAddSuite) TestAddApplicationSecret(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+	description := "db credentials"
+	monthly := secrets.RotateMonthly
+	expire := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	s.mockContext.EXPECT().UnitName().Return("mysql/0")
+	s.mockContext.EXPECT().CreateSecret(&jujuc.SecretCreateArgs{
+		SecretUpdateArgs: jujuc.SecretUpdateArgs{
+			Description:  &description,
+			RotatePolicy: &monthly,
+			ExpireTime:   &expire,
+			Value:        secrets.SecretData{"username": "admin", "password": "s3cret"},
+		},
+		OwnerTag: names.NewApplicationTag("mysql"),
+	}).Return(secretURI, nil)
+
+	code, ctx := s.run(c, "secret-add",
+		"--description", "db credentials", "--rotate", "monthly", "--expire", "2021-06-01T00:00:00Z",
+		"username=admin", "password=s3cret")
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stdout), gc.Equals, secretURI+"\n")
+}
+
+func (s *SecretAddSuite) TestAddUnitSecret(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+	s.mockContext.EXPECT().UnitName().Return("mysql/0")
+	s.mockContext.EXPECT().CreateSecret(&jujuc.SecretCreateArgs{
+		SecretUpdateArgs: jujuc.SecretUpdateArgs{
+			Value: secrets.SecretData{"token": "abc123"},
+		},
+		OwnerTag: names.NewUnitTag("mysql/0"),
+	}).Return(secretURI, nil)
+
+	code, ctx := s.run(c, "secret-add", "--owner", "unit", "token=abc123")
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stdout), gc.Equals, secretURI+"\n")
+}
+
+func (s *SecretAddSuite) TestAddFromFile(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+	s.mockContext.EXPECT().UnitName().Return("mysql/0")
+	s.mockContext.EXPECT().CreateSecret(&jujuc.SecretCreateArgs{
+		SecretUpdateArgs: jujuc.SecretUpdateArgs{
+			Value: secrets.SecretData{"username": "admin", "password": "override"},
+		},
+		OwnerTag: names.NewApplicationTag("mysql"),
+	}).Return(secretURI, nil)
+
+	code, ctx := s.runWithStdin(c, "username: admin\npassword: s3cret\n",
+		"secret-add", "--file", "-", "password=override")
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(code, gc.Equals, 0)
+}
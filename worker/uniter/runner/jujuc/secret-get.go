@@ -0,0 +1,107 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/core/secrets"
+)
+
+// SecretGetCommand implements the secret-get command.
+type SecretGetCommand struct {
+	cmd.CommandBase
+	ctx Context
+	out cmd.Output
+
+	uri     string
+	key     string
+	peek    bool
+	refresh bool
+}
+
+// NewSecretGetCommand returns a secret-get command.
+func NewSecretGetCommand(ctx Context) (cmd.Command, error) {
+	return &SecretGetCommand{ctx: ctx}, nil
+}
+
+// Info returns information about the Command.
+// Info implements part of the cmd.Command interface.
+func (c *SecretGetCommand) Info() *cmd.Info {
+	doc := `
+secret-get prints the value of the secret with the given URI, or of the
+given key in it. The unit must own the secret, or have been granted
+access to it.
+
+Units other than the owner keep seeing the revision of the secret they
+first read, until they run secret-get with --refresh to use the latest
+revision from then on. With --peek, the latest revision is printed
+without the unit then using it.
+
+Examples:
+    secret-get secret:4a2b3f5c-3e0c-4c5d-8a8e-2d1f6c5e9b7a
+    secret-get secret:4a2b3f5c-3e0c-4c5d-8a8e-2d1f6c5e9b7a password
+    secret-get secret:4a2b3f5c-3e0c-4c5d-8a8e-2d1f6c5e9b7a --refresh
+
+See also:
+    secret-add
+    secret-set
+`
+	return jujucmd.Info(&cmd.Info{
+		Name:    "secret-get",
+		Args:    "<URI> [<key>]",
+		Purpose: "print secret values",
+		Doc:     doc,
+	})
+}
+
+// SetFlags adds command specific flags to the flag set.
+// SetFlags implements part of the cmd.Command interface.
+func (c *SecretGetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters.Formatters())
+	f.BoolVar(&c.peek, "peek", false, "get the latest revision without using it")
+	f.BoolVar(&c.refresh, "refresh", false, "get and use the latest revision")
+}
+
+// Init initializes the Command before running.
+// Init implements part of the cmd.Command interface.
+func (c *SecretGetCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("missing secret URI")
+	}
+	if _, err := secrets.ParseURI(args[0]); err != nil {
+		return errors.Trace(err)
+	}
+	c.uri = args[0]
+	args = args[1:]
+	if len(args) > 0 {
+		c.key = args[0]
+		args = args[1:]
+	}
+	if c.peek && c.refresh {
+		return errors.New("specify one of --peek or --refresh but not both")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run will execute the Command as directed by the options and positional
+// arguments passed to Init.
+// Run implements part of the cmd.Command interface.
+func (c *SecretGetCommand) Run(ctx *cmd.Context) error {
+	value, err := c.ctx.GetSecret(c.uri, c.peek, c.refresh)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if c.key == "" {
+		return c.out.Write(ctx, value)
+	}
+	v, ok := value[c.key]
+	if !ok {
+		return errors.NotFoundf("secret key %q", c.key)
+	}
+	return c.out.Write(ctx, v)
+}
@@ -0,0 +1,107 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/secrets"
+)
+
+type SecretGetSuite struct {
+	secretsSuite
+}
+
+var _ = gc.Suite(&SecretGetSuite{})
+
+func (s *SecretGetSuite) TestHelp(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+	out := s.help(c, "secret-get")
+	c.Assert(strings.HasPrefix(out, `
+Usage: secret-get [options] <URI> [<key>]
+
+Summary:
+print secret values
+
+Options:
+--format  (= smart)
+    Specify output format (json|smart|yaml)
+-o, --output (= "")
+    Specify an output file
+--peek  (= false)
+    get the latest revision without using it
+--refresh  (= false)
+    get and use the latest revision
+`[1:]), jc.IsTrue, gc.Commentf("%s", out))
+}
+
+func (s *SecretGetSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "missing secret URI",
+	}, {
+		args: []string{"foo"},
+		err:  `secret URI "foo" not valid`,
+	}, {
+		args: []string{secretURI, "--peek", "--refresh"},
+		err:  "specify one of --peek or --refresh but not both",
+	}, {
+		args: []string{secretURI, "password", "username"},
+		err:  `unrecognized args: \["username"\]`,
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		ctrl := s.setupMocks(c)
+		code, ctx := s.run(c, "secret-get", t.args...)
+		c.Check(code, gc.Equals, 2)
+		c.Check(bufferString(ctx.Stderr), gc.Matches, "ERROR "+t.err+"\n")
+		ctrl.Finish()
+	}
+}
+
+func (s *SecretGetSuite) TestGetAll(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+	s.mockContext.EXPECT().GetSecret(secretURI, false, false).Return(
+		secrets.SecretData{"username": "admin", "password": "s3cret"}, nil)
+
+	code, ctx := s.run(c, "secret-get", secretURI)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stdout), gc.Equals, "password: s3cret\nusername: admin\n")
+}
+
+func (s *SecretGetSuite) TestGetKey(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+	s.mockContext.EXPECT().GetSecret(secretURI, false, true).Return(
+		secrets.SecretData{"username": "admin", "password": "s3cret"}, nil)
+
+	code, ctx := s.run(c, "secret-get", secretURI, "password", "--refresh")
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(ctx.Stdout), gc.Equals, "s3cret\n")
+}
+
+func (s *SecretGetSuite) TestGetMissingKey(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+	s.mockContext.EXPECT().GetSecret(secretURI, true, false).Return(
+		secrets.SecretData{"password": "s3cret"}, nil)
+
+	code, ctx := s.run(c, "secret-get", secretURI, "token", "--peek")
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "ERROR secret key \"token\" not found\n")
+}
+
+func (s *SecretGetSuite) TestGetError(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+	s.mockContext.EXPECT().GetSecret(secretURI, false, false).Return(nil, errors.New("permission denied"))
+
+	code, ctx := s.run(c, "secret-get", secretURI)
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "ERROR permission denied\n")
+}
@@ -0,0 +1,135 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/names/v4"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/core/secrets"
+)
+
+// secretGrantRevokeCommand holds the flags and arguments shared by the
+// secret-grant and secret-revoke commands.
+type secretGrantRevokeCommand struct {
+	cmd.CommandBase
+	ctx Context
+
+	uri             string
+	relationId      int
+	relationIdProxy gnuflag.Value
+	unitName        string
+}
+
+func newSecretGrantRevokeCommand(ctx Context) (*secretGrantRevokeCommand, error) {
+	c := &secretGrantRevokeCommand{ctx: ctx}
+	rV, err := NewRelationIdValue(ctx, &c.relationId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	c.relationIdProxy = rV
+	return c, nil
+}
+
+// SetFlags adds command specific flags to the flag set.
+// SetFlags implements part of the cmd.Command interface.
+func (c *secretGrantRevokeCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.Var(c.relationIdProxy, "r", "the relation with which to associate the grant")
+	f.Var(c.relationIdProxy, "relation", "")
+	f.StringVar(&c.unitName, "unit", "", "the unit, of the related application, to restrict the grant to")
+}
+
+// Init initializes the Command before running.
+// Init implements part of the cmd.Command interface.
+func (c *secretGrantRevokeCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("missing secret URI")
+	}
+	if _, err := secrets.ParseURI(args[0]); err != nil {
+		return errors.Trace(err)
+	}
+	c.uri = args[0]
+	if c.relationId == -1 {
+		return errors.Errorf("no relation id specified")
+	}
+	if c.unitName != "" && !names.IsValidUnit(c.unitName) {
+		return errors.NotValidf("unit %q", c.unitName)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// args returns the application on the other end of the relation, and
+// the unit of it if one was given.
+func (c *secretGrantRevokeCommand) args() (*SecretGrantRevokeArgs, error) {
+	r, err := c.ctx.Relation(c.relationId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	appName := r.RemoteApplicationName()
+	args := &SecretGrantRevokeArgs{ApplicationName: &appName}
+	if c.unitName != "" {
+		unitApp, _ := names.UnitApplication(c.unitName)
+		if unitApp != appName {
+			return nil, errors.NotValidf("unit %q in relation %q", c.unitName, r.FakeId())
+		}
+		unitName := c.unitName
+		args.UnitName = &unitName
+	}
+	return args, nil
+}
+
+// SecretGrantCommand implements the secret-grant command.
+type SecretGrantCommand struct {
+	*secretGrantRevokeCommand
+}
+
+// NewSecretGrantCommand returns a secret-grant command.
+func NewSecretGrantCommand(ctx Context) (cmd.Command, error) {
+	c, err := newSecretGrantRevokeCommand(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &SecretGrantCommand{c}, nil
+}
+
+// Info returns information about the Command.
+// Info implements part of the cmd.Command interface.
+func (c *SecretGrantCommand) Info() *cmd.Info {
+	doc := `
+secret-grant grants the application on the other end of the specified
+relation access to the secret with the given URI, or only the specified
+unit of it. The grant is removed with secret-revoke.
+
+Only the owner of the secret may grant access to it; if it's owned by
+the application, only the leader unit may.
+
+Examples:
+    secret-grant secret:4a2b3f5c-3e0c-4c5d-8a8e-2d1f6c5e9b7a -r 0
+    secret-grant secret:4a2b3f5c-3e0c-4c5d-8a8e-2d1f6c5e9b7a -r db:2 --unit mediawiki/6
+
+See also:
+    secret-add
+    secret-revoke
+`
+	return jujucmd.Info(&cmd.Info{
+		Name:    "secret-grant",
+		Args:    "<URI>",
+		Purpose: "grant access to a secret",
+		Doc:     doc,
+	})
+}
+
+// Run will execute the Command as directed by the options and positional
+// arguments passed to Init.
+// Run implements part of the cmd.Command interface.
+func (c *SecretGrantCommand) Run(_ *cmd.Context) error {
+	args, err := c.args()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(c.ctx.GrantSecret(c.uri, args))
+}
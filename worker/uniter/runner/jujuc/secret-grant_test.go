@@ -0,0 +1,107 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type SecretGrantSuite struct {
+	secretsSuite
+}
+
+var _ = gc.Suite(&SecretGrantSuite{})
+
+func (s *SecretGrantSuite) TestHelp(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+	s.expectNoHookRelation()
+	out := s.help(c, "secret-grant")
+	c.Assert(strings.HasPrefix(out, `
+Usage: secret-grant [options] <URI>
+
+Summary:
+grant access to a secret
+
+Options:
+-r, --relation  (= )
+    the relation with which to associate the grant
+--unit (= "")
+    the unit, of the related application, to restrict the grant to
+`[1:]), jc.IsTrue, gc.Commentf("%s", out))
+}
+
+func (s *SecretGrantSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "missing secret URI",
+	}, {
+		args: []string{"foo", "-r", "1"},
+		err:  `secret URI "foo" not valid`,
+	}, {
+		args: []string{secretURI},
+		err:  "no relation id specified",
+	}, {
+		args: []string{secretURI, "-r", "1", "--unit", "foo"},
+		err:  `unit "foo" not valid`,
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		ctrl := s.setupMocks(c)
+		s.expectNoHookRelation()
+		s.expectRelation(ctrl, "wordpress")
+		code, ctx := s.run(c, "secret-grant", t.args...)
+		c.Check(code, gc.Equals, 2)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, "ERROR "+t.err+"\n")
+		ctrl.Finish()
+	}
+}
+
+func (s *SecretGrantSuite) TestGrantApplication(c *gc.C) {
+	ctrl := s.setupMocks(c)
+	defer ctrl.Finish()
+	s.expectNoHookRelation()
+	s.expectRelation(ctrl, "wordpress")
+	app := "wordpress"
+	s.mockContext.EXPECT().GrantSecret(secretURI, &jujuc.SecretGrantRevokeArgs{
+		ApplicationName: &app,
+	}).Return(nil)
+
+	code, ctx := s.run(c, "secret-grant", secretURI, "-r", "db:1")
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(code, gc.Equals, 0)
+}
+
+func (s *SecretGrantSuite) TestGrantUnit(c *gc.C) {
+	ctrl := s.setupMocks(c)
+	defer ctrl.Finish()
+	s.expectNoHookRelation()
+	s.expectRelation(ctrl, "wordpress")
+	app := "wordpress"
+	unit := "wordpress/0"
+	s.mockContext.EXPECT().GrantSecret(secretURI, &jujuc.SecretGrantRevokeArgs{
+		ApplicationName: &app,
+		UnitName:        &unit,
+	}).Return(nil)
+
+	code, ctx := s.run(c, "secret-grant", secretURI, "-r", "1", "--unit", "wordpress/0")
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(code, gc.Equals, 0)
+}
+
+func (s *SecretGrantSuite) TestGrantUnitNotInRelation(c *gc.C) {
+	ctrl := s.setupMocks(c)
+	defer ctrl.Finish()
+	s.expectNoHookRelation()
+	s.expectRelation(ctrl, "wordpress")
+
+	code, ctx := s.run(c, "secret-grant", secretURI, "-r", "1", "--unit", "mediawiki/0")
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "ERROR unit \"mediawiki/0\" in relation \"db:1\" not valid\n")
+}
@@ -0,0 +1,63 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	jujucmd "github.com/juju/juju/cmd"
+)
+
+// SecretRevokeCommand implements the secret-revoke command.
+type SecretRevokeCommand struct {
+	*secretGrantRevokeCommand
+}
+
+// NewSecretRevokeCommand returns a secret-revoke command.
+func NewSecretRevokeCommand(ctx Context) (cmd.Command, error) {
+	c, err := newSecretGrantRevokeCommand(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &SecretRevokeCommand{c}, nil
+}
+
+// Info returns information about the Command.
+// Info implements part of the cmd.Command interface.
+func (c *SecretRevokeCommand) Info() *cmd.Info {
+	doc := `
+secret-revoke revokes the access of the application on the other end of
+the specified relation, or of the specified unit of it, to the secret
+with the given URI.
+
+Only the owner of the secret may revoke access to it; if it's owned by
+the application, only the leader unit may.
+
+Examples:
+    secret-revoke secret:4a2b3f5c-3e0c-4c5d-8a8e-2d1f6c5e9b7a -r 0
+    secret-revoke secret:4a2b3f5c-3e0c-4c5d-8a8e-2d1f6c5e9b7a -r db:2 --unit mediawiki/6
+
+See also:
+    secret-add
+    secret-grant
+`
+	return jujucmd.Info(&cmd.Info{
+		Name:    "secret-revoke",
+		Args:    "<URI>",
+		Purpose: "revoke access to a secret",
+		Doc:     doc,
+	})
+}
+
+// Run will execute the Command as directed by the options and positional
+// arguments passed to Init.
+// Run implements part of the cmd.Command interface.
+func (c *SecretRevokeCommand) Run(_ *cmd.Context) error {
+	args, err := c.args()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(c.ctx.RevokeSecret(c.uri, args))
+}
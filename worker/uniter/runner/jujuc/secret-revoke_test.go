@@ -0,0 +1,63 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type SecretRevokeSuite struct {
+	secretsSuite
+}
+
+var _ = gc.Suite(&SecretRevokeSuite{})
+
+func (s *SecretRevokeSuite) TestHelp(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+	s.expectNoHookRelation()
+	out := s.help(c, "secret-revoke")
+	c.Assert(strings.HasPrefix(out, `
+Usage: secret-revoke [options] <URI>
+
+Summary:
+revoke access to a secret
+`[1:]), jc.IsTrue, gc.Commentf("%s", out))
+}
+
+func (s *SecretRevokeSuite) TestRevokeApplication(c *gc.C) {
+	ctrl := s.setupMocks(c)
+	defer ctrl.Finish()
+	s.expectNoHookRelation()
+	s.expectRelation(ctrl, "wordpress")
+	app := "wordpress"
+	s.mockContext.EXPECT().RevokeSecret(secretURI, &jujuc.SecretGrantRevokeArgs{
+		ApplicationName: &app,
+	}).Return(nil)
+
+	code, ctx := s.run(c, "secret-revoke", secretURI, "-r", "1")
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(code, gc.Equals, 0)
+}
+
+func (s *SecretRevokeSuite) TestRevokeUnit(c *gc.C) {
+	ctrl := s.setupMocks(c)
+	defer ctrl.Finish()
+	s.expectNoHookRelation()
+	s.expectRelation(ctrl, "wordpress")
+	app := "wordpress"
+	unit := "wordpress/1"
+	s.mockContext.EXPECT().RevokeSecret(secretURI, &jujuc.SecretGrantRevokeArgs{
+		ApplicationName: &app,
+		UnitName:        &unit,
+	}).Return(nil)
+
+	code, ctx := s.run(c, "secret-revoke", secretURI, "--relation", "db:1", "--unit", "wordpress/1")
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(code, gc.Equals, 0)
+}
@@ -0,0 +1,90 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/core/secrets"
+)
+
+// SecretSetCommand implements the secret-set command.
+type SecretSetCommand struct {
+	secretUpsertCommand
+	uri string
+}
+
+// NewSecretSetCommand returns a secret-set command.
+func NewSecretSetCommand(ctx Context) (cmd.Command, error) {
+	return &SecretSetCommand{secretUpsertCommand: secretUpsertCommand{ctx: ctx}}, nil
+}
+
+// Info returns information about the Command.
+// Info implements part of the cmd.Command interface.
+func (c *SecretSetCommand) Info() *cmd.Info {
+	doc := `
+secret-set updates the attributes of an existing secret. If key values
+are given, they replace the value of the secret as a new revision,
+which consumers see when they next run secret-get with --refresh.
+
+Only the owner of the secret may update it; if it's owned by the
+application, only the leader unit may.
+
+Examples:
+    secret-set secret:4a2b3f5c-3e0c-4c5d-8a8e-2d1f6c5e9b7a password=n3w
+    secret-set secret:4a2b3f5c-3e0c-4c5d-8a8e-2d1f6c5e9b7a --rotate daily
+
+See also:
+    secret-add
+    secret-get
+`
+	return jujucmd.Info(&cmd.Info{
+		Name:    "secret-set",
+		Args:    "<URI> [key=value ...]",
+		Purpose: "update an existing secret",
+		Doc:     doc,
+	})
+}
+
+// SetFlags adds command specific flags to the flag set.
+// SetFlags implements part of the cmd.Command interface.
+func (c *SecretSetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.setFlags(f)
+}
+
+// Init initializes the Command before running.
+// Init implements part of the cmd.Command interface.
+func (c *SecretSetCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("missing secret URI")
+	}
+	if _, err := secrets.ParseURI(args[0]); err != nil {
+		return errors.Trace(err)
+	}
+	c.uri = args[0]
+	return c.init(args[1:])
+}
+
+// Run will execute the Command as directed by the options and positional
+// arguments passed to Init.
+// Run implements part of the cmd.Command interface.
+func (c *SecretSetCommand) Run(ctx *cmd.Context) error {
+	value, err := c.readValue(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(value) > 0 {
+		if err := value.Validate(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	args := c.updateArgs(value)
+	if args.Description == nil && args.RotatePolicy == nil && args.ExpireTime == nil && len(args.Value) == 0 {
+		return errors.New("nothing to update")
+	}
+	return errors.Trace(c.ctx.UpdateSecret(c.uri, &args))
+}
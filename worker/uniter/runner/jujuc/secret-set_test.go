@@ -0,0 +1,85 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/secrets"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type SecretSetSuite struct {
+	secretsSuite
+}
+
+var _ = gc.Suite(&SecretSetSuite{})
+
+func (s *SecretSetSuite) TestHelp(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+	out := s.help(c, "secret-set")
+	c.Assert(strings.HasPrefix(out, `
+Usage: secret-set [options] <URI> [key=value ...]
+
+Summary:
+update an existing secret
+`[1:]), jc.IsTrue, gc.Commentf("%s", out))
+}
+
+func (s *SecretSetSuite) TestInitErrors(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "missing secret URI",
+	}, {
+		args: []string{"password=s3cret"},
+		err:  `secret URI "password=s3cret" not valid`,
+	}, {
+		args: []string{secretURI, "--rotate", "fortnightly"},
+		err:  `rotate policy "fortnightly" not valid`,
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		ctrl := s.setupMocks(c)
+		code, ctx := s.run(c, "secret-set", t.args...)
+		c.Check(code, gc.Equals, 2)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, "ERROR "+t.err+"\n")
+		ctrl.Finish()
+	}
+}
+
+func (s *SecretSetSuite) TestNothingToUpdate(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+	code, ctx := s.run(c, "secret-set", secretURI)
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "ERROR nothing to update\n")
+}
+
+func (s *SecretSetSuite) TestSetValue(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+	s.mockContext.EXPECT().UpdateSecret(secretURI, &jujuc.SecretUpdateArgs{
+		Value: secrets.SecretData{"password": "n3w"},
+	}).Return(nil)
+
+	code, ctx := s.run(c, "secret-set", secretURI, "password=n3w")
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(code, gc.Equals, 0)
+}
+
+func (s *SecretSetSuite) TestSetAttributes(c *gc.C) {
+	defer s.setupMocks(c).Finish()
+	description := ""
+	daily := secrets.RotateDaily
+	s.mockContext.EXPECT().UpdateSecret(secretURI, &jujuc.SecretUpdateArgs{
+		Description:  &description,
+		RotatePolicy: &daily,
+	}).Return(nil)
+
+	code, ctx := s.run(c, "secret-set", secretURI, "--description", "", "--rotate", "daily")
+	c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+	c.Check(code, gc.Equals, 0)
+}
@@ -0,0 +1,71 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"bytes"
+
+	"github.com/golang/mock/gomock"
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+	"github.com/juju/juju/worker/uniter/runner/jujuc/mocks"
+)
+
+const secretURI = "secret:4a2b3f5c-3e0c-4c5d-8a8e-2d1f6c5e9b7a"
+
+type secretsSuite struct {
+	mockContext *mocks.MockContext
+}
+
+func (s *secretsSuite) setupMocks(c *gc.C) *gomock.Controller {
+	ctrl := gomock.NewController(c)
+	s.mockContext = mocks.NewMockContext(ctrl)
+	return ctrl
+}
+
+func (s *secretsSuite) expectNoHookRelation() {
+	s.mockContext.EXPECT().HookRelation().Return(nil, errors.NotFoundf("hook relation"))
+}
+
+// expectRelation sets up the relation with id 1 to the given remote
+// application.
+func (s *secretsSuite) expectRelation(ctrl *gomock.Controller, remoteApp string) {
+	relation := jujuc.NewMockContextRelation(ctrl)
+	relation.EXPECT().RemoteApplicationName().Return(remoteApp).AnyTimes()
+	relation.EXPECT().FakeId().Return("db:1").AnyTimes()
+	s.mockContext.EXPECT().Relation(1).Return(relation, nil).AnyTimes()
+}
+
+// run runs the named hook tool with the mock context, returning its exit
+// code and the context of the run.
+func (s *secretsSuite) run(c *gc.C, name string, args ...string) (int, *cmd.Context) {
+	return s.runWithStdin(c, "", name, args...)
+}
+
+// runWithStdin runs the named hook tool like run, reading stdin from
+// the given string.
+func (s *secretsSuite) runWithStdin(c *gc.C, stdin, name string, args ...string) (int, *cmd.Context) {
+	toolCmd, err := jujuc.NewCommand(s.mockContext, name)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := cmdtesting.Context(c)
+	ctx.Stdin = bytes.NewBufferString(stdin)
+	code := cmd.Main(jujuc.NewJujucCommandWrappedForTest(toolCmd), ctx, args)
+	return code, ctx
+}
+
+// help returns the help output of the named hook tool.
+func (s *secretsSuite) help(c *gc.C, name string) string {
+	toolCmd, err := jujuc.NewCommand(s.mockContext, name)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx := cmdtesting.Context(c)
+	code := cmd.Main(jujuc.NewJujucCommandWrappedForTest(toolCmd), ctx, []string{"--help"})
+	c.Assert(code, gc.Equals, 0)
+	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+	return bufferString(ctx.Stdout)
+}
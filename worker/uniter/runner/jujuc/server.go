@@ -16,6 +16,7 @@ import (
 	"path/filepath"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -82,6 +83,12 @@ var baseCommands = map[string]creator{
 	"state-get" + cmdSuffix:    NewStateGetCommand,
 	"state-delete" + cmdSuffix: NewStateDeleteCommand,
 	"state-set" + cmdSuffix:    NewStateSetCommand,
+
+	"secret-add" + cmdSuffix:    NewSecretAddCommand,
+	"secret-get" + cmdSuffix:    NewSecretGetCommand,
+	"secret-grant" + cmdSuffix:  NewSecretGrantCommand,
+	"secret-revoke" + cmdSuffix: NewSecretRevokeCommand,
+	"secret-set" + cmdSuffix:    NewSecretSetCommand,
 }
 
 type functionCmdCreator func(Context, string) (cmd.Command, error)
@@ -160,11 +167,17 @@ type Request struct {
 // CmdGetter looks up a Command implementation connected to a particular Context.
 type CmdGetter func(contextId, cmdName string) (cmd.Command, error)
 
+// ToolCallFunc is called after each hook tool has been run with the
+// name of the tool, its arguments, when it started and the error it
+// failed with, if any.
+type ToolCallFunc func(name string, args []string, started time.Time, err error)
+
 // Jujuc implements the jujuc command in the form required by net/rpc.
 type Jujuc struct {
-	mu     sync.Mutex
-	getCmd CmdGetter
-	token  string
+	mu       sync.Mutex
+	getCmd   CmdGetter
+	token    string
+	toolCall ToolCallFunc
 }
 
 // badReqErrorf returns an error indicating a bad Request.
@@ -211,7 +224,15 @@ func (j *Jujuc) Main(req Request, resp *exec.ExecResponse) error {
 	logger.Debugf("running hook tool %q for %s", req.CommandName, req.ContextId)
 	logger.Tracef("hook context id %q; dir %q", req.ContextId, req.Dir)
 	wrapper := &cmdWrapper{c, nil}
+	started := time.Now()
 	resp.Code = cmd.Main(wrapper, ctx, req.Args)
+	if j.toolCall != nil {
+		err := wrapper.err
+		if err == nil && resp.Code != 0 {
+			err = errors.Errorf("exit status %d", resp.Code)
+		}
+		j.toolCall(req.CommandName, req.Args, started, err)
+	}
 	if errors.Cause(wrapper.err) == ErrNoStdin {
 		return ErrNoStdin
 	}
@@ -233,10 +254,11 @@ type Server struct {
 
 // NewServer creates an RPC server bound to socketPath, which can execute
 // remote command invocations against an appropriate Context. It will not
-// actually do so until Run is called.
-func NewServer(getCmd CmdGetter, socket sockets.Socket, token string) (*Server, error) {
+// actually do so until Run is called. If toolCall is not nil, it is
+// called after each command invocation has been run.
+func NewServer(getCmd CmdGetter, socket sockets.Socket, token string, toolCall ToolCallFunc) (*Server, error) {
 	server := rpc.NewServer()
-	if err := server.Register(&Jujuc{getCmd: getCmd, token: token}); err != nil {
+	if err := server.Register(&Jujuc{getCmd: getCmd, token: token, toolCall: toolCall}); err != nil {
 		return nil, err
 	}
 	listener, err := sockets.Listen(socket)
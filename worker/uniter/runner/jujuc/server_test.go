@@ -80,11 +80,20 @@ func factory(contextId, cmdName string) (cmd.Command, error) {
 	return &RpcCommand{}, nil
 }
 
+type toolCall struct {
+	name string
+	args []string
+	err  error
+}
+
 type ServerSuite struct {
 	testing.BaseSuite
 	server *jujuc.Server
 	socket sockets.Socket
 	err    chan error
+
+	mu        sync.Mutex
+	toolCalls []toolCall
 }
 
 var _ = gc.Suite(&ServerSuite{})
@@ -103,7 +112,8 @@ func (s *ServerSuite) osDependentSockPath(c *gc.C) sockets.Socket {
 func (s *ServerSuite) SetUpTest(c *gc.C) {
 	s.BaseSuite.SetUpTest(c)
 	s.socket = s.osDependentSockPath(c)
-	srv, err := jujuc.NewServer(factory, s.socket, "")
+	s.toolCalls = nil
+	srv, err := jujuc.NewServer(factory, s.socket, "", s.recordToolCall)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(srv, gc.NotNil)
 	s.server = srv
@@ -111,6 +121,12 @@ func (s *ServerSuite) SetUpTest(c *gc.C) {
 	go func() { s.err <- s.server.Run() }()
 }
 
+func (s *ServerSuite) recordToolCall(name string, args []string, started time.Time, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.toolCalls = append(s.toolCalls, toolCall{name: name, args: args, err: err})
+}
+
 func (s *ServerSuite) TearDownTest(c *gc.C) {
 	s.server.Close()
 	c.Assert(<-s.err, gc.IsNil)
@@ -146,6 +162,33 @@ func (s *ServerSuite) TestHappyPath(c *gc.C) {
 	c.Assert(string(content), gc.Equals, "something")
 }
 
+func (s *ServerSuite) TestToolCallRecorded(c *gc.C) {
+	dir := c.MkDir()
+	_, err := s.Call(c, jujuc.Request{
+		ContextId:   "validCtx",
+		Dir:         dir,
+		CommandName: "remote",
+		Args:        []string{"--value", "something"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := s.Call(c, jujuc.Request{
+		ContextId:   "validCtx",
+		Dir:         dir,
+		CommandName: "remote",
+		Args:        []string{"--value", "error"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Code, gc.Equals, 1)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c.Assert(s.toolCalls, gc.HasLen, 2)
+	c.Check(s.toolCalls[0].name, gc.Equals, "remote")
+	c.Check(s.toolCalls[0].args, jc.DeepEquals, []string{"--value", "something"})
+	c.Check(s.toolCalls[0].err, jc.ErrorIsNil)
+	c.Check(s.toolCalls[1].err, gc.ErrorMatches, "blam")
+}
+
 func (s *ServerSuite) TestNoStdin(c *gc.C) {
 	dir := c.MkDir()
 	_, err := s.Call(c, jujuc.Request{
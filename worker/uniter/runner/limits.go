@@ -0,0 +1,94 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package runner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/application"
+)
+
+// hookKillGrace is how long a timed out hook or action is given to exit
+// after being sent SIGTERM, before it is killed.
+var hookKillGrace = 30 * time.Second
+
+// systemdRun is used to run hooks in a transient scope when CPU or memory
+// limits are set.
+const systemdRun = "systemd-run"
+
+// execLookPath is patched in tests.
+var execLookPath = exec.LookPath
+
+// timeoutError is returned when a hook or action runs for longer than
+// the application allows.
+type timeoutError struct {
+	kind    string
+	name    string
+	timeout time.Duration
+}
+
+// Error is part of the error interface.
+func (e *timeoutError) Error() string {
+	return fmt.Sprintf("%s %q timed out after %v", e.kind, e.name, e.timeout)
+}
+
+// NewTimeoutError returns an error indicating that the named hook or
+// action, of the given kind, ran for longer than the timeout.
+func NewTimeoutError(kind, name string, timeout time.Duration) error {
+	return &timeoutError{kind: kind, name: name, timeout: timeout}
+}
+
+// IsTimeoutError returns true if the error was caused by a hook or
+// action running for longer than its timeout.
+func IsTimeoutError(err error) bool {
+	_, ok := errors.Cause(err).(*timeoutError)
+	return ok
+}
+
+// limitHookCommand wraps the command used to run a hook so that it runs
+// in a transient systemd scope, with the CPU and memory limits applied
+// to the hook process and all of its children. The command is returned
+// unchanged if no limits are set, or if systemd-run is unavailable.
+func limitHookCommand(hookCmd []string, limits application.HookLimits) ([]string, error) {
+	if !limits.HasResourceLimits() || runtime.GOOS != "linux" {
+		return hookCmd, nil
+	}
+	systemdRunPath, err := execLookPath(systemdRun)
+	if err != nil {
+		return nil, errors.Annotate(err, "hook resource limits require systemd")
+	}
+	limited := []string{systemdRunPath, "--scope", "--quiet"}
+	if limits.CPUQuota > 0 {
+		limited = append(limited, "-p", fmt.Sprintf("CPUQuota=%d%%", limits.CPUQuota))
+	}
+	if limits.MemoryLimit > 0 {
+		limited = append(limited, "-p", fmt.Sprintf("MemoryMax=%dM", limits.MemoryLimit))
+	}
+	limited = append(limited, "--")
+	return append(limited, hookCmd...), nil
+}
+
+// terminateHook asks the hook process, and any processes it started,
+// to exit. Those still running once the grace period has passed, or
+// once the hook process has exited, are killed, as are all of them if
+// they can't be signalled.
+func terminateHook(p *os.Process, clock clock.Clock, done <-chan struct{}) {
+	if err := signalHook(p, syscall.SIGTERM); err != nil {
+		_ = signalHook(p, syscall.SIGKILL)
+		return
+	}
+	select {
+	case <-clock.After(hookKillGrace):
+	case <-done:
+	}
+	_ = signalHook(p, syscall.SIGKILL)
+}
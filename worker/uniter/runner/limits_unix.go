@@ -0,0 +1,25 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// +build !windows
+
+package runner
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setHookProcessGroup makes the hook process the leader of a new
+// process group, so that it can be signalled along with any processes
+// it starts.
+func setHookProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// signalHook sends the signal to every process in the hook process's
+// group.
+func signalHook(p *os.Process, sig syscall.Signal) error {
+	return syscall.Kill(-p.Pid, sig)
+}
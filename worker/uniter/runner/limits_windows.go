@@ -0,0 +1,22 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package runner
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setHookProcessGroup does nothing on windows, which has no process
+// groups to signal.
+func setHookProcessGroup(cmd *exec.Cmd) {}
+
+// signalHook sends the signal to the hook process.
+func signalHook(p *os.Process, sig syscall.Signal) error {
+	if sig == syscall.SIGKILL {
+		return p.Kill()
+	}
+	return p.Signal(sig)
+}
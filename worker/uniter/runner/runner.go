@@ -14,6 +14,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 	"unicode/utf8"
 
@@ -27,6 +28,8 @@ import (
 	"github.com/kballard/go-shellquote"
 
 	"github.com/juju/juju/core/actions"
+	"github.com/juju/juju/core/application"
+	"github.com/juju/juju/core/hooktrace"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/worker/common/charmrunner"
 	"github.com/juju/juju/worker/uniter/runner/context"
@@ -114,6 +117,8 @@ type Context interface {
 	HasExecutionSetUnitStatus() bool
 	ResetExecutionSetUnitStatus()
 	ModelType() model.ModelType
+	HookLimits() application.HookLimits
+	HookTracer() *hooktrace.Recorder
 
 	Prepare() error
 	Flush(badge string, failure error) error
@@ -210,6 +215,13 @@ func (runner *runner) runLocationToMode(runLocation RunLocation) (runMode, error
 	}
 }
 
+// flush flushes the runner's context, recording how long it took.
+func (runner *runner) flush(badge string, failure error) error {
+	started := clock.WallClock.Now()
+	defer runner.context.HookTracer().Flushed(started)
+	return runner.context.Flush(badge, failure)
+}
+
 // RunCommands exists to satisfy the Runner interface.
 func (runner *runner) RunCommands(commands string, runLocation RunLocation) (*utilexec.ExecResponse, error) {
 	rMode, err := runner.runLocationToMode(runLocation)
@@ -217,7 +229,7 @@ func (runner *runner) RunCommands(commands string, runLocation RunLocation) (*ut
 		return nil, errors.Trace(err)
 	}
 	result, err := runner.runCommandsWithTimeout(commands, 0, clock.WallClock, rMode, nil)
-	return result, runner.context.Flush("run commands", err)
+	return result, runner.flush("run commands", err)
 }
 
 // runCommandsWithTimeout is a helper to abstract common code between run commands and
@@ -315,10 +327,10 @@ func (runner *runner) runJujuRunAction() (err error) {
 	results, err := runner.runCommandsWithTimeout(command, time.Duration(timeout), clock.WallClock, rMode, data.Cancel)
 	if results != nil {
 		if err := runner.updateActionResults(results); err != nil {
-			return runner.context.Flush("juju-run", err)
+			return runner.flush("juju-run", err)
 		}
 	}
-	return runner.context.Flush("juju-run", err)
+	return runner.flush("juju-run", err)
 }
 
 func encodeBytes(input []byte) (value string, encoding string) {
@@ -436,7 +448,7 @@ func (runner *runner) runCharmHookWithLocation(hookName, charmLocation string, r
 	env = append(env, "JUJU_DISPATCH_PATH="+charmLocation+"/"+hookName)
 
 	defer func() {
-		err = runner.context.Flush(hookName, err)
+		err = runner.flush(hookName, err)
 	}()
 
 	logger := runner.logger()
@@ -589,10 +601,20 @@ func (runner *runner) runCharmProcessOnRemote(hook, hookName, charmDir string, e
 
 // Check still tested
 func (runner *runner) runCharmProcessOnLocal(hook, hookName, charmDir string, env []string) error {
+	limits := runner.context.HookLimits()
 	hookCmd := hookCommand(hook)
+	if runner.context.ModelType() == model.IAAS {
+		limitedCmd, err := limitHookCommand(hookCmd, limits)
+		if err != nil {
+			runner.logger().Warningf("%v, running %q without resource limits", err, hookName)
+		} else {
+			hookCmd = limitedCmd
+		}
+	}
 	ps := exec.Command(hookCmd[0], hookCmd[1:]...)
 	ps.Env = env
 	ps.Dir = charmDir
+	setHookProcessGroup(ps)
 	outReader, outWriter, err := os.Pipe()
 	if err != nil {
 		return errors.Errorf("cannot make logging pipe: %v", err)
@@ -622,6 +644,7 @@ func (runner *runner) runCharmProcessOnLocal(hook, hookName, charmDir string, en
 	var cancel <-chan struct{}
 	var actionOut *bufferAdaptor
 	var actionErr *bufferAdaptor
+	timeout := &timeoutError{kind: "hook", name: hookName, timeout: limits.HookTimeout}
 	actionData, err := runner.context.ActionData()
 	runningAction := err == nil && actionData != nil
 	if runningAction {
@@ -630,26 +653,43 @@ func (runner *runner) runCharmProcessOnLocal(hook, hookName, charmDir string, en
 		actionErr = &bufferAdaptor{ReadWriter: errWriter}
 		hookErrLogger.AddReceiver(actionErr)
 		cancel = actionData.Cancel
+		timeout = &timeoutError{kind: "action", name: hookName, timeout: limits.ActionTimeout}
 	}
 
 	err = ps.Start()
 	var exitErr error
 	if err == nil {
 		done := make(chan struct{})
-		if cancel != nil {
+		timedOut := make(chan struct{})
+		if cancel != nil || timeout.timeout > 0 {
+			var expired <-chan time.Time
+			if timeout.timeout > 0 {
+				expired = clock.WallClock.After(timeout.timeout)
+			}
 			go func() {
 				select {
 				case <-cancel:
-					ps.Process.Kill()
+					_ = signalHook(ps.Process, syscall.SIGKILL)
+				case <-expired:
+					close(timedOut)
+					runner.logger().Warningf("%v, terminating", timeout)
+					terminateHook(ps.Process, clock.WallClock, done)
 				case <-done:
 				}
 			}()
 		}
 		// Record the *os.Process of the hook
-		runner.context.SetProcess(hookProcess{ps.Process})
+		runner.context.SetProcess(hookProcessGroup{hookProcess{ps.Process}})
 		// Block until execution finishes
 		exitErr = ps.Wait()
 		close(done)
+		select {
+		case <-timedOut:
+			if exitErr != nil {
+				exitErr = timeout
+			}
+		default:
+		}
 	} else {
 		exitErr = err
 	}
@@ -702,7 +742,7 @@ func (runner *runner) startJujucServer(token string, rMode runMode) (*jujuc.Serv
 
 	socket := runner.paths.GetJujucServerSocket(rMode == runOnRemote)
 	runner.logger().Debugf("starting jujuc server %s %v", token, socket)
-	srv, err := jujuc.NewServer(getCmd, socket, token)
+	srv, err := jujuc.NewServer(getCmd, socket, token, runner.context.HookTracer().ToolCall)
 	if err != nil {
 		return nil, errors.Annotate(err, "starting jujuc server")
 	}
@@ -762,3 +802,13 @@ type hookProcess struct {
 func (p hookProcess) Pid() int {
 	return p.Process.Pid
 }
+
+// hookProcessGroup is a hookProcess whose Kill also kills any processes
+// the hook started.
+type hookProcessGroup struct {
+	hookProcess
+}
+
+func (p hookProcessGroup) Kill() error {
+	return signalHook(p.Process, syscall.SIGKILL)
+}
@@ -9,10 +9,12 @@ import (
 	"io/ioutil"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/juju/charm/v8/hooks"
+	"github.com/juju/clock"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/proxy"
@@ -21,7 +23,10 @@ import (
 	"github.com/juju/utils/v2/exec"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/core/application"
+	"github.com/juju/juju/core/hooktrace"
 	"github.com/juju/juju/core/model"
+	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/common/charmrunner"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/runner"
@@ -229,6 +234,8 @@ type MockContext struct {
 	flushFailure    error
 	flushResult     error
 	modelType       model.ModelType
+	hookLimits      application.HookLimits
+	hookTracer      *hooktrace.Recorder
 }
 
 func (ctx *MockContext) GetLogger(module string) loggo.Logger {
@@ -283,6 +290,14 @@ func (ctx *MockContext) UpdateActionResults(keys []string, value interface{}) er
 	return nil
 }
 
+func (ctx *MockContext) HookLimits() application.HookLimits {
+	return ctx.hookLimits
+}
+
+func (ctx *MockContext) HookTracer() *hooktrace.Recorder {
+	return ctx.hookTracer
+}
+
 func (ctx *MockContext) ModelType() model.ModelType {
 	if ctx.modelType == "" {
 		return model.IAAS
@@ -345,6 +360,174 @@ func (s *RunMockContextSuite) TestRunHookFlushFailure(c *gc.C) {
 	s.assertRecordedPid(c, ctx.expectPid)
 }
 
+func (s *RunMockContextSuite) TestRunHookTracesFlush(c *gc.C) {
+	tracer := hooktrace.NewRecorder(clock.WallClock)
+	ctx := &MockContext{hookTracer: tracer}
+	makeCharm(c, hookSpec{
+		dir:  "hooks",
+		name: hookName,
+		perm: 0700,
+	}, s.paths.GetCharmDir())
+	tracer.Start("run something-happened hook")
+	_, err := runner.NewRunner(ctx, s.paths, nil).RunHook("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	tracer.Finish(nil)
+
+	traces := tracer.Traces()
+	c.Assert(traces, gc.HasLen, 1)
+	c.Assert(traces[0].Flush > 0, jc.IsTrue)
+	c.Assert(traces[0].Flush <= traces[0].Duration, jc.IsTrue)
+}
+
+func (s *RunMockContextSuite) TestRunHookTimeout(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("test hooks can't sleep on windows")
+	}
+	ctx := &MockContext{
+		hookLimits: application.HookLimits{HookTimeout: 100 * time.Millisecond},
+	}
+	makeCharm(c, hookSpec{
+		dir:   "hooks",
+		name:  hookName,
+		perm:  0700,
+		sleep: 10 * time.Second,
+	}, s.paths.GetCharmDir())
+	start := time.Now()
+	_, err := runner.NewRunner(ctx, s.paths, nil).RunHook("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(time.Since(start) < 5*time.Second, jc.IsTrue)
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, `hook "something-happened" timed out after 100ms`)
+	c.Assert(runner.IsTimeoutError(ctx.flushFailure), jc.IsTrue)
+}
+
+func (s *RunMockContextSuite) TestRunHookTimeoutKillsAfterGrace(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("hooks can't ignore SIGTERM on windows")
+	}
+	s.PatchValue(runner.HookKillGrace, 100*time.Millisecond)
+	ctx := &MockContext{
+		hookLimits: application.HookLimits{HookTimeout: 100 * time.Millisecond},
+	}
+	makeCharm(c, hookSpec{
+		dir:        "hooks",
+		name:       hookName,
+		perm:       0700,
+		sleep:      10 * time.Second,
+		ignoreTerm: true,
+	}, s.paths.GetCharmDir())
+	start := time.Now()
+	_, err := runner.NewRunner(ctx, s.paths, nil).RunHook("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(time.Since(start) < 5*time.Second, jc.IsTrue)
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, `hook "something-happened" timed out after 100ms`)
+}
+
+func (s *RunMockContextSuite) TestRunHookTimeoutKillsChildren(c *gc.C) {
+	if runtime.GOOS != "linux" {
+		c.Skip("child processes are checked in /proc")
+	}
+	s.PatchValue(runner.HookKillGrace, 100*time.Millisecond)
+	ctx := &MockContext{
+		hookLimits: application.HookLimits{HookTimeout: 100 * time.Millisecond},
+	}
+	pidFile := filepath.Join(c.MkDir(), "child.pid")
+	makeCharm(c, hookSpec{
+		dir:          "hooks",
+		name:         hookName,
+		perm:         0700,
+		sleep:        10 * time.Second,
+		ignoreTerm:   true,
+		childPidFile: pidFile,
+	}, s.paths.GetCharmDir())
+	_, err := runner.NewRunner(ctx, s.paths, nil).RunHook("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, `hook "something-happened" timed out after 100ms`)
+
+	data, err := ioutil.ReadFile(pidFile)
+	c.Assert(err, jc.ErrorIsNil)
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	running := true
+	for a := coretesting.LongAttempt.Start(); running && a.Next(); {
+		running = processRunning(pid)
+	}
+	c.Assert(running, jc.IsFalse)
+}
+
+func (s *RunMockContextSuite) TestRunHookWithinTimeout(c *gc.C) {
+	ctx := &MockContext{
+		hookLimits: application.HookLimits{HookTimeout: time.Minute},
+	}
+	makeCharm(c, hookSpec{
+		dir:  "hooks",
+		name: hookName,
+		perm: 0700,
+		code: 123,
+	}, s.paths.GetCharmDir())
+	_, err := runner.NewRunner(ctx, s.paths, nil).RunHook("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, "exit status 123")
+	c.Assert(runner.IsTimeoutError(ctx.flushFailure), jc.IsFalse)
+}
+
+func (s *RunMockContextSuite) TestRunActionTimeout(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("test hooks can't sleep on windows")
+	}
+	ctx := &MockContext{
+		actionData:    &context.ActionData{},
+		actionResults: map[string]interface{}{},
+		hookLimits: application.HookLimits{
+			HookTimeout:   time.Minute,
+			ActionTimeout: 100 * time.Millisecond,
+		},
+	}
+	makeCharm(c, hookSpec{
+		dir:   "actions",
+		name:  hookName,
+		perm:  0700,
+		sleep: 10 * time.Second,
+	}, s.paths.GetCharmDir())
+	_, err := runner.NewRunner(ctx, s.paths, nil).RunAction("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.flushFailure, gc.ErrorMatches, `action "something-happened" timed out after 100ms`)
+}
+
+func (s *RunMockContextSuite) TestLimitHookCommand(c *gc.C) {
+	if runtime.GOOS != "linux" {
+		c.Skip("hook resource limits are only applied on linux")
+	}
+	s.PatchValue(runner.ExecLookPath, func(file string) (string, error) {
+		c.Assert(file, gc.Equals, "systemd-run")
+		return "/usr/bin/systemd-run", nil
+	})
+	cmd, err := runner.LimitHookCommand([]string{"/path/to/hook"}, application.HookLimits{
+		CPUQuota:    50,
+		MemoryLimit: 512,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmd, jc.DeepEquals, []string{
+		"/usr/bin/systemd-run", "--scope", "--quiet",
+		"-p", "CPUQuota=50%", "-p", "MemoryMax=512M",
+		"--", "/path/to/hook",
+	})
+
+	cmd, err = runner.LimitHookCommand([]string{"/path/to/hook"}, application.HookLimits{HookTimeout: time.Minute})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmd, jc.DeepEquals, []string{"/path/to/hook"})
+}
+
+func (s *RunMockContextSuite) TestLimitHookCommandNoSystemd(c *gc.C) {
+	if runtime.GOOS != "linux" {
+		c.Skip("hook resource limits are only applied on linux")
+	}
+	s.PatchValue(runner.ExecLookPath, func(file string) (string, error) {
+		return "", errors.NotFoundf(file)
+	})
+	_, err := runner.LimitHookCommand([]string{"/path/to/hook"}, application.HookLimits{CPUQuota: 50})
+	c.Assert(err, gc.ErrorMatches, "hook resource limits require systemd: systemd-run not found")
+}
+
 func (s *RunHookSuite) TestRunActionDispatchingHookHandler(c *gc.C) {
 	ctx := &MockContext{
 		actionData:    &context.ActionData{},
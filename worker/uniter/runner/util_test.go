@@ -4,7 +4,9 @@
 package runner_test
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -209,6 +211,13 @@ type hookSpec struct {
 	background string
 	// missingShebang will omit the '#!/bin/bash' line
 	missingShebang bool
+	// sleep holds how long the hook sleeps before exiting.
+	sleep time.Duration
+	// ignoreTerm makes the hook ignore SIGTERM.
+	ignoreTerm bool
+	// childPidFile, if set, makes the hook start a long running child
+	// process and write its pid to the file.
+	childPidFile string
 }
 
 // makeCharm constructs a fake charm dir containing a single named hook
@@ -253,6 +262,17 @@ func makeCharm(c *gc.C, spec hookSpec, charmDir string) {
 		// expected.
 		printf("(sleep 0.2; echo %s; sleep 10) &", spec.background)
 	}
+	if spec.childPidFile != "" {
+		printf("/bin/sleep 30 &")
+		printf("echo $! > %s", spec.childPidFile)
+	}
+	if spec.ignoreTerm {
+		printf("trap '' TERM")
+	}
+	if spec.sleep > 0 {
+		// The test environment has no PATH.
+		printf("/bin/sleep %v", spec.sleep.Seconds())
+	}
 	printf("exit %d", spec.code)
 }
 
@@ -263,3 +283,15 @@ type relUnitShim struct {
 func (r *relUnitShim) Relation() context.Relation {
 	return r.RelationUnit.Relation()
 }
+
+// processRunning reports whether the process with the given pid is
+// running, rather than gone or waiting to be reaped.
+func processRunning(pid int) bool {
+	stat, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return false
+	}
+	// The process state follows its parenthesised command name.
+	fields := strings.Fields(string(stat[bytes.LastIndexByte(stat, ')')+1:]))
+	return len(fields) > 0 && fields[0] != "Z"
+}
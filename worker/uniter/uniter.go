@@ -20,6 +20,7 @@ import (
 	"github.com/juju/juju/agent/tools"
 	"github.com/juju/juju/api/uniter"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/hooktrace"
 	"github.com/juju/juju/core/leadership"
 	"github.com/juju/juju/core/life"
 	"github.com/juju/juju/core/lxdprofile"
@@ -84,6 +85,7 @@ type Uniter struct {
 	embedded                     bool
 	enforcedCharmModifiedVersion int
 	storage                      *storage.Attachments
+	secrets                      context.SecretsAccessor
 	clock                        clock.Clock
 
 	relationStateTracker relation.RelationStateTracker
@@ -107,6 +109,10 @@ type Uniter struct {
 
 	hookLock machinelock.Lock
 
+	// hookTracer records where the time goes when the unit runs its
+	// hooks, actions and commands.
+	hookTracer *hooktrace.Recorder
+
 	Probe Probe
 
 	// TODO(axw) move the runListener and run-command code outside of the
@@ -158,6 +164,7 @@ type Uniter struct {
 // UniterParams hold all the necessary parameters for a new Uniter.
 type UniterParams struct {
 	UniterFacade                  *uniter.State
+	SecretsClient                 context.SecretsAccessor
 	UnitTag                       names.UnitTag
 	ModelType                     model.ModelType
 	LeadershipTrackerFunc         func(names.UnitTag) leadership.TrackerWorker
@@ -228,6 +235,7 @@ func newUniter(uniterParams *UniterParams) func() (worker.Worker, error) {
 	startFunc := func() (worker.Worker, error) {
 		u := &Uniter{
 			st:                            uniterParams.UniterFacade,
+			secrets:                       uniterParams.SecretsClient,
 			paths:                         NewPaths(uniterParams.DataDir, uniterParams.UnitTag, uniterParams.SocketConfig),
 			modelType:                     uniterParams.ModelType,
 			hookLock:                      uniterParams.MachineLock,
@@ -253,6 +261,7 @@ func newUniter(uniterParams *UniterParams) func() (worker.Worker, error) {
 			logger:                        uniterParams.Logger,
 			embedded:                      uniterParams.Embedded,
 			enforcedCharmModifiedVersion:  uniterParams.EnforcedCharmModifiedVersion,
+			hookTracer:                    hooktrace.NewRecorder(uniterParams.Clock),
 		}
 		plan := catacomb.Plan{
 			Site: &u.catacomb,
@@ -764,9 +773,11 @@ func (u *Uniter) init(unitTag names.UnitTag) (err error) {
 		Tracker:          u.leadershipTracker,
 		GetRelationInfos: u.relationStateTracker.GetInfo,
 		Storage:          u.storage,
+		SecretsAccessor:  u.secrets,
 		Paths:            u.paths,
 		Clock:            u.clock,
 		Logger:           u.logger.Child("context"),
+		HookTracer:       u.hookTracer,
 	})
 	if err != nil {
 		return err
@@ -806,6 +817,7 @@ func (u *Uniter) init(unitTag names.UnitTag) (err error) {
 		InitialState:    initialState,
 		AcquireLock:     u.acquireExecutionLock,
 		Logger:          u.logger.Child("operation"),
+		HookTracer:      u.hookTracer,
 	})
 	if err != nil {
 		return errors.Trace(err)
@@ -850,6 +862,13 @@ func (u *Uniter) Wait() error {
 	return u.catacomb.Wait()
 }
 
+// Report provides information for the engine report.
+func (u *Uniter) Report() map[string]interface{} {
+	return map[string]interface{}{
+		"hook-traces": u.hookTracer.Traces(),
+	}
+}
+
 func (u *Uniter) getApplicationCharmURL() (*corecharm.URL, error) {
 	// TODO(fwereade): pretty sure there's no reason to make 2 API calls here.
 	app, err := u.st.Application(u.unit.ApplicationTag())
@@ -904,5 +923,9 @@ func (u *Uniter) reportHookError(hookInfo hook.Info) error {
 	}
 	statusData["hook"] = hookName
 	statusMessage := fmt.Sprintf("hook failed: %q", hookName)
+	if u.operationExecutor.State().HookTimedOut {
+		statusData["timed-out"] = true
+		statusMessage = fmt.Sprintf("hook timed out: %q", hookName)
+	}
 	return setAgentStatus(u, status.Error, statusMessage, statusData)
 }
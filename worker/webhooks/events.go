@@ -0,0 +1,139 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhooks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/juju/core/multiwatcher"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/core/webhook"
+)
+
+// actionFailed is the status of a failed action.
+const actionFailed = "failed"
+
+// unitState holds what is remembered about a unit between deltas.
+type unitState struct {
+	inError bool
+	blocked bool
+}
+
+// eventTracker remembers the last known state of the entities in the
+// controller's models, and turns the multiwatcher deltas into the
+// events that webhooks are notified of. Events are only raised on
+// transitions: a unit that stays in error raises a single event.
+type eventTracker struct {
+	models       map[string]string
+	units        map[multiwatcher.EntityID]unitState
+	machines     map[multiwatcher.EntityID]bool
+	applications map[multiwatcher.EntityID]bool
+	actions      map[multiwatcher.EntityID]bool
+}
+
+func newEventTracker() *eventTracker {
+	return &eventTracker{
+		models:       make(map[string]string),
+		units:        make(map[multiwatcher.EntityID]unitState),
+		machines:     make(map[multiwatcher.EntityID]bool),
+		applications: make(map[multiwatcher.EntityID]bool),
+		actions:      make(map[multiwatcher.EntityID]bool),
+	}
+}
+
+// process updates the tracked state with the deltas, returning the
+// events they raise, stamped with the given time.
+func (t *eventTracker) process(deltas []multiwatcher.Delta, now time.Time) []webhook.Event {
+	// Model names are needed for the other entities' events, so deal
+	// with the models first.
+	for _, delta := range deltas {
+		if info, ok := delta.Entity.(*multiwatcher.ModelInfo); ok {
+			if delta.Removed {
+				delete(t.models, info.ModelUUID)
+			} else {
+				t.models[info.ModelUUID] = info.Owner + "/" + info.Name
+			}
+		}
+	}
+
+	var events []webhook.Event
+	add := func(kind webhook.EventKind, modelUUID, entity string, s status.Status, message string) {
+		events = append(events, webhook.Event{
+			Kind:      kind,
+			ModelUUID: modelUUID,
+			Model:     t.models[modelUUID],
+			Entity:    entity,
+			Status:    string(s),
+			Message:   message,
+			Timestamp: now,
+		})
+	}
+	for _, delta := range deltas {
+		id := delta.Entity.EntityID()
+		switch info := delta.Entity.(type) {
+		case *multiwatcher.UnitInfo:
+			if delta.Removed {
+				delete(t.units, id)
+				continue
+			}
+			last := t.units[id]
+			current := unitState{
+				inError: info.WorkloadStatus.Current == status.Error || info.AgentStatus.Current == status.Error,
+				blocked: info.WorkloadStatus.Current == status.Blocked,
+			}
+			if current.inError && !last.inError {
+				errStatus := info.WorkloadStatus
+				if errStatus.Current != status.Error {
+					errStatus = info.AgentStatus
+				}
+				add(webhook.UnitError, info.ModelUUID, info.Name, errStatus.Current, errStatus.Message)
+			}
+			if current.blocked && !last.blocked {
+				add(webhook.UnitBlocked, info.ModelUUID, info.Name, info.WorkloadStatus.Current, info.WorkloadStatus.Message)
+			}
+			t.units[id] = current
+
+		case *multiwatcher.MachineInfo:
+			if delta.Removed {
+				delete(t.machines, id)
+				continue
+			}
+			down := info.AgentStatus.Current == status.Down
+			if down && !t.machines[id] {
+				add(webhook.MachineDown, info.ModelUUID, info.ID, info.AgentStatus.Current, info.AgentStatus.Message)
+			}
+			t.machines[id] = down
+
+		case *multiwatcher.ActionInfo:
+			if delta.Removed {
+				delete(t.actions, id)
+				continue
+			}
+			failed := info.Status == actionFailed
+			if failed && !t.actions[id] {
+				message := fmt.Sprintf("action %q (%s) failed", info.Name, info.ID)
+				if info.Message != "" {
+					message += ": " + info.Message
+				}
+				add(webhook.ActionFailed, info.ModelUUID, info.Receiver, status.Status(info.Status), message)
+			}
+			t.actions[id] = failed
+
+		case *multiwatcher.ApplicationInfo:
+			if delta.Removed {
+				if t.applications[id] {
+					add(webhook.ApplicationRemoved, info.ModelUUID, info.Name, "", "")
+				}
+				delete(t.applications, id)
+				continue
+			}
+			if !t.applications[id] {
+				add(webhook.ApplicationDeployed, info.ModelUUID, info.Name, info.Status.Current, info.Status.Message)
+			}
+			t.applications[id] = true
+		}
+	}
+	return events
+}
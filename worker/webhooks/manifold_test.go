@@ -0,0 +1,75 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhooks_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v2"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/webhooks"
+)
+
+type ManifoldSuite struct {
+	testing.IsolationSuite
+	config webhooks.ManifoldConfig
+}
+
+var _ = gc.Suite(&ManifoldSuite{})
+
+func (s *ManifoldSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.config = webhooks.ManifoldConfig{
+		ClockName:        "clock",
+		StateName:        "state",
+		MultiwatcherName: "multiwatcher",
+		Logger:           loggo.GetLogger("test"),
+		NewWorker: func(webhooks.Config) (worker.Worker, error) {
+			return nil, errors.New("unexpected")
+		},
+	}
+}
+
+func (s *ManifoldSuite) TestValid(c *gc.C) {
+	c.Check(s.config.Validate(), jc.ErrorIsNil)
+}
+
+func (s *ManifoldSuite) TestMissingClockName(c *gc.C) {
+	s.config.ClockName = ""
+	s.checkNotValid(c, "empty ClockName not valid")
+}
+
+func (s *ManifoldSuite) TestMissingStateName(c *gc.C) {
+	s.config.StateName = ""
+	s.checkNotValid(c, "empty StateName not valid")
+}
+
+func (s *ManifoldSuite) TestMissingMultiwatcherName(c *gc.C) {
+	s.config.MultiwatcherName = ""
+	s.checkNotValid(c, "empty MultiwatcherName not valid")
+}
+
+func (s *ManifoldSuite) TestMissingLogger(c *gc.C) {
+	s.config.Logger = nil
+	s.checkNotValid(c, "nil Logger not valid")
+}
+
+func (s *ManifoldSuite) TestMissingNewWorker(c *gc.C) {
+	s.config.NewWorker = nil
+	s.checkNotValid(c, "nil NewWorker not valid")
+}
+
+func (s *ManifoldSuite) TestInputs(c *gc.C) {
+	manifold := webhooks.Manifold(s.config)
+	c.Check(manifold.Inputs, jc.SameContents, []string{"clock", "state", "multiwatcher"})
+}
+
+func (s *ManifoldSuite) checkNotValid(c *gc.C, expect string) {
+	err := s.config.Validate()
+	c.Check(err, gc.ErrorMatches, expect)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+}
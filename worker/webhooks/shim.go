@@ -0,0 +1,44 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhooks
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/webhook"
+	"github.com/juju/juju/state"
+)
+
+// This file contains untested shims to let us wrap state in a sensible
+// interface and avoid writing tests that depend on mongodb. If you were
+// to change any part of it so that it were no longer *obviously* and
+// *trivially* correct, you would be Doing It Wrong.
+
+// statePoolShim gets the webhooks of the models in the state pool.
+type statePoolShim struct {
+	pool *state.StatePool
+}
+
+// ModelWebhooks is part of the Backend interface.
+func (s statePoolShim) ModelWebhooks(modelUUID string) ([]webhook.Webhook, error) {
+	st, err := s.pool.Get(modelUUID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer st.Release()
+
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	hooks, err := model.Webhooks()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]webhook.Webhook, len(hooks))
+	for i, hook := range hooks {
+		result[i] = hook.Webhook
+	}
+	return result, nil
+}
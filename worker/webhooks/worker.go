@@ -0,0 +1,303 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package webhooks provides a worker that watches the models on the
+// controller, and notifies the webhooks registered in them when units
+// enter error or blocked status, machines go down, actions fail, or
+// applications are deployed or removed.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/retry"
+	"github.com/juju/worker/v2"
+	"github.com/juju/worker/v2/catacomb"
+
+	"github.com/juju/juju/core/multiwatcher"
+	"github.com/juju/juju/core/webhook"
+)
+
+const (
+	// queueSize is the number of events that can be waiting to be
+	// delivered to each webhook. Events raised while a webhook's queue
+	// is full aren't delivered to it.
+	queueSize = 1000
+
+	// deliveryAttempts is the number of times a payload is sent to a
+	// webhook before giving up.
+	deliveryAttempts = 5
+
+	// retryDelay is the delay before sending a payload again, which
+	// doubles with each attempt up to maxRetryDelay.
+	retryDelay    = time.Second
+	maxRetryDelay = time.Minute
+)
+
+// Logger defines the methods needed for the worker to log messages.
+type Logger interface {
+	Debugf(string, ...interface{})
+	Warningf(string, ...interface{})
+}
+
+// Backend provides the webhooks registered in the controller's models.
+type Backend interface {
+	// ModelWebhooks returns the webhooks registered in the model.
+	ModelWebhooks(modelUUID string) ([]webhook.Webhook, error)
+}
+
+// HTTPClient sends the payloads to the webhooks.
+type HTTPClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Config defines the resources the worker needs to run.
+type Config struct {
+	Backend    Backend
+	HTTPClient HTTPClient
+	Clock      clock.Clock
+	Logger     Logger
+
+	// NewWatcher returns a multiwatcher watching all the models on
+	// the controller.
+	NewWatcher func() multiwatcher.Watcher
+}
+
+// Validate checks that this config can be used.
+func (config Config) Validate() error {
+	if config.Backend == nil {
+		return errors.NotValidf("nil Backend")
+	}
+	if config.HTTPClient == nil {
+		return errors.NotValidf("nil HTTPClient")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Logger == nil {
+		return errors.NotValidf("nil Logger")
+	}
+	if config.NewWatcher == nil {
+		return errors.NotValidf("nil NewWatcher")
+	}
+	return nil
+}
+
+// NewWorker returns a worker that notifies webhooks of the events in
+// the controller's models.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &webhookWorker{
+		config: config,
+	}
+	if err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type webhookWorker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+}
+
+// delivery is an event waiting to be sent to a webhook.
+type delivery struct {
+	hook  webhook.Webhook
+	event webhook.Event
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *webhookWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *webhookWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *webhookWorker) loop() error {
+	watcher := w.config.NewWatcher()
+	deltas := make(chan []multiwatcher.Delta)
+	watchErr := make(chan error, 1)
+
+	// The goroutines reading the watcher and delivering the events
+	// are stopped, and waited for, before the loop returns.
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		_ = watcher.Stop()
+		wg.Wait()
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			d, err := watcher.Next()
+			if err != nil {
+				watchErr <- err
+				return
+			}
+			select {
+			case deltas <- d:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Each webhook has its own queue, which is delivered by a goroutine
+	// while it has events in it, so that a webhook that is slow to
+	// respond, or down, doesn't delay the others. The queues are keyed
+	// by model and webhook id.
+	queues := make(map[string]chan delivery)
+	drained := make(chan string)
+	startDelivering := func(key string, queue <-chan delivery) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.deliverQueued(ctx, queue)
+			select {
+			case drained <- key:
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	tracker := newEventTracker()
+	first := true
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case err := <-watchErr:
+			return errors.Annotate(err, "watching models")
+		case key := <-drained:
+			// Events may have been queued since the goroutine found
+			// the queue empty.
+			if queue := queues[key]; len(queue) > 0 {
+				startDelivering(key, queue)
+			} else {
+				delete(queues, key)
+			}
+		case d := <-deltas:
+			events := tracker.process(d, w.config.Clock.Now().UTC())
+			if first {
+				// The first deltas describe the entities as they are
+				// when the worker starts, which aren't events.
+				first = false
+				continue
+			}
+			for _, ev := range events {
+				hooks, err := w.config.Backend.ModelWebhooks(ev.ModelUUID)
+				if err != nil {
+					w.config.Logger.Warningf("cannot get webhooks for model %q: %v", ev.ModelUUID, err)
+					continue
+				}
+				for _, hook := range hooks {
+					if !hook.Wants(ev.Kind) {
+						continue
+					}
+					key := ev.ModelUUID + ":" + hook.Id
+					queue, ok := queues[key]
+					if !ok {
+						queue = make(chan delivery, queueSize)
+						queues[key] = queue
+					}
+					select {
+					case queue <- delivery{hook: hook, event: ev}:
+					default:
+						w.config.Logger.Warningf("too many events queued for webhook %s, dropping %s event for %q", hook.Id, ev.Kind, ev.Entity)
+					}
+					if !ok {
+						startDelivering(key, queue)
+					}
+				}
+			}
+		}
+	}
+}
+
+// deliverQueued sends the events in the queue to their webhook until
+// the queue is empty or the context is cancelled.
+func (w *webhookWorker) deliverQueued(ctx context.Context, queue <-chan delivery) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d := <-queue:
+			if err := w.deliver(ctx, d.hook, d.event); err != nil {
+				w.config.Logger.Warningf("cannot notify webhook %s of %s event for %q: %v", d.hook.Id, d.event.Kind, d.event.Entity, err)
+			}
+		default:
+			return
+		}
+	}
+}
+
+// deliver sends the event to the webhook, retrying with increasing
+// delays if it fails.
+func (w *webhookWorker) deliver(ctx context.Context, hook webhook.Webhook, ev webhook.Event) error {
+	payload, err := hook.Payload(ev)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = retry.Call(retry.CallArgs{
+		Func: func() error {
+			return w.post(ctx, hook, payload)
+		},
+		NotifyFunc: func(err error, attempt int) {
+			w.config.Logger.Debugf("attempt %d to notify webhook %s failed: %v", attempt, hook.Id, err)
+		},
+		Attempts:    deliveryAttempts,
+		Delay:       retryDelay,
+		MaxDelay:    maxRetryDelay,
+		BackoffFunc: retry.DoubleDelay,
+		Clock:       w.config.Clock,
+		Stop:        ctx.Done(),
+	})
+	if err != nil {
+		return errors.Trace(retry.LastError(err))
+	}
+	return nil
+}
+
+// post sends the payload to the webhook, signing it if the webhook has
+// a secret.
+func (w *webhookWorker) post(ctx context.Context, hook webhook.Webhook, payload []byte) error {
+	req, err := http.NewRequest("POST", hook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	if hook.Secret != "" {
+		req.Header.Set(webhook.SignatureHeader, webhook.Sign(hook.Secret, payload))
+	}
+	resp, err := w.config.HTTPClient.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.Errorf("%s responded with %s", hook.URL, resp.Status)
+	}
+	return nil
+}
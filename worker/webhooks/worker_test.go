@@ -0,0 +1,456 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package webhooks_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v2"
+	"github.com/juju/worker/v2/workertest"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/multiwatcher"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/core/webhook"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/webhooks"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+
+	clock   *testclock.Clock
+	watcher *fakeWatcher
+	backend *fakeBackend
+	client  *fakeHTTPClient
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+const modelUUID = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+
+var startTime = time.Date(2021, 5, 6, 7, 0, 0, 0, time.UTC)
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(startTime)
+	s.watcher = &fakeWatcher{
+		deltas: make(chan []multiwatcher.Delta),
+		stop:   make(chan struct{}),
+	}
+	s.backend = &fakeBackend{
+		webhooks: map[string][]webhook.Webhook{
+			modelUUID: {{
+				Id:  "1",
+				URL: "https://example.com/hook",
+			}},
+		},
+	}
+	s.client = &fakeHTTPClient{
+		requests: make(chan *http.Request, 10),
+	}
+}
+
+func (s *WorkerSuite) newWorker(c *gc.C) worker.Worker {
+	w, err := webhooks.NewWorker(webhooks.Config{
+		Backend:    s.backend,
+		HTTPClient: s.client,
+		Clock:      s.clock,
+		Logger:     loggo.GetLogger("test"),
+		NewWatcher: func() multiwatcher.Watcher { return s.watcher },
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(c *gc.C) { workertest.DirtyKill(c, w) })
+	return w
+}
+
+// start starts the worker with the given deltas as its baseline.
+func (s *WorkerSuite) start(c *gc.C, baseline ...multiwatcher.EntityInfo) worker.Worker {
+	w := s.newWorker(c)
+	s.send(c, append([]multiwatcher.EntityInfo{modelInfo()}, baseline...)...)
+	return w
+}
+
+func (s *WorkerSuite) send(c *gc.C, entities ...multiwatcher.EntityInfo) {
+	deltas := make([]multiwatcher.Delta, len(entities))
+	for i, entity := range entities {
+		deltas[i] = multiwatcher.Delta{Entity: entity}
+	}
+	s.sendDeltas(c, deltas...)
+}
+
+func (s *WorkerSuite) sendDeltas(c *gc.C, deltas ...multiwatcher.Delta) {
+	select {
+	case s.watcher.deltas <- deltas:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out sending deltas")
+	}
+}
+
+func (s *WorkerSuite) nextRequest(c *gc.C) (*http.Request, webhook.Event) {
+	select {
+	case req := <-s.client.requests:
+		var ev webhook.Event
+		err := json.Unmarshal(s.client.body(req), &ev)
+		c.Assert(err, jc.ErrorIsNil)
+		return req, ev
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for request")
+	}
+	panic("unreachable")
+}
+
+func (s *WorkerSuite) assertNoRequest(c *gc.C) {
+	select {
+	case req := <-s.client.requests:
+		c.Fatalf("unexpected request: %s", s.client.body(req))
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func modelInfo() *multiwatcher.ModelInfo {
+	return &multiwatcher.ModelInfo{
+		ModelUUID: modelUUID,
+		Name:      "mymodel",
+		Owner:     "admin",
+	}
+}
+
+func unitInfo(workload, agent status.Status, message string) *multiwatcher.UnitInfo {
+	return &multiwatcher.UnitInfo{
+		ModelUUID:      modelUUID,
+		Name:           "mysql/0",
+		Application:    "mysql",
+		WorkloadStatus: multiwatcher.StatusInfo{Current: workload, Message: message},
+		AgentStatus:    multiwatcher.StatusInfo{Current: agent},
+	}
+}
+
+func (s *WorkerSuite) TestUnitError(c *gc.C) {
+	s.start(c, unitInfo(status.Active, status.Idle, ""))
+	s.send(c, unitInfo(status.Error, status.Idle, `hook failed: "install"`))
+
+	req, ev := s.nextRequest(c)
+	c.Check(req.Method, gc.Equals, "POST")
+	c.Check(req.URL.String(), gc.Equals, "https://example.com/hook")
+	c.Check(req.Header.Get("Content-Type"), gc.Equals, "application/json")
+	c.Check(req.Header.Get(webhook.SignatureHeader), gc.Equals, "")
+	c.Check(ev, jc.DeepEquals, webhook.Event{
+		Kind:      webhook.UnitError,
+		ModelUUID: modelUUID,
+		Model:     "admin/mymodel",
+		Entity:    "mysql/0",
+		Status:    "error",
+		Message:   `hook failed: "install"`,
+		Timestamp: startTime,
+	})
+
+	// The unit staying in error doesn't raise another event.
+	s.send(c, unitInfo(status.Error, status.Idle, `hook failed: "config-changed"`))
+	s.assertNoRequest(c)
+}
+
+func (s *WorkerSuite) TestBaselineRaisesNoEvents(c *gc.C) {
+	s.start(c, unitInfo(status.Error, status.Idle, `hook failed: "install"`))
+	s.assertNoRequest(c)
+
+	// The unit is already known to be in error.
+	s.send(c, unitInfo(status.Error, status.Idle, `hook failed: "install"`))
+	s.assertNoRequest(c)
+}
+
+func (s *WorkerSuite) TestUnitBlocked(c *gc.C) {
+	s.start(c, unitInfo(status.Waiting, status.Idle, ""))
+	s.send(c, unitInfo(status.Blocked, status.Idle, "missing relation"))
+
+	_, ev := s.nextRequest(c)
+	c.Check(ev.Kind, gc.Equals, webhook.UnitBlocked)
+	c.Check(ev.Entity, gc.Equals, "mysql/0")
+	c.Check(ev.Message, gc.Equals, "missing relation")
+}
+
+func (s *WorkerSuite) TestMachineDown(c *gc.C) {
+	machine := &multiwatcher.MachineInfo{
+		ModelUUID:   modelUUID,
+		ID:          "0",
+		AgentStatus: multiwatcher.StatusInfo{Current: status.Started},
+	}
+	s.start(c, machine)
+	machine = machine.Clone().(*multiwatcher.MachineInfo)
+	machine.AgentStatus = multiwatcher.StatusInfo{Current: status.Down, Message: "agent is not communicating with the server"}
+	s.send(c, machine)
+
+	_, ev := s.nextRequest(c)
+	c.Check(ev.Kind, gc.Equals, webhook.MachineDown)
+	c.Check(ev.Entity, gc.Equals, "0")
+	c.Check(ev.Status, gc.Equals, "down")
+}
+
+func (s *WorkerSuite) TestActionFailed(c *gc.C) {
+	s.start(c)
+	s.send(c, &multiwatcher.ActionInfo{
+		ModelUUID: modelUUID,
+		ID:        "42",
+		Receiver:  "mysql/0",
+		Name:      "backup",
+		Status:    "running",
+	})
+	s.assertNoRequest(c)
+	s.send(c, &multiwatcher.ActionInfo{
+		ModelUUID: modelUUID,
+		ID:        "42",
+		Receiver:  "mysql/0",
+		Name:      "backup",
+		Status:    "failed",
+		Message:   "disk full",
+	})
+
+	_, ev := s.nextRequest(c)
+	c.Check(ev.Kind, gc.Equals, webhook.ActionFailed)
+	c.Check(ev.Entity, gc.Equals, "mysql/0")
+	c.Check(ev.Status, gc.Equals, "failed")
+	c.Check(ev.Message, gc.Equals, `action "backup" (42) failed: disk full`)
+}
+
+func (s *WorkerSuite) TestApplicationDeployedAndRemoved(c *gc.C) {
+	existing := &multiwatcher.ApplicationInfo{ModelUUID: modelUUID, Name: "mysql"}
+	s.start(c, existing)
+
+	app := &multiwatcher.ApplicationInfo{
+		ModelUUID: modelUUID,
+		Name:      "wordpress",
+		Status:    multiwatcher.StatusInfo{Current: status.Waiting, Message: "waiting for machine"},
+	}
+	s.send(c, app)
+	_, ev := s.nextRequest(c)
+	c.Check(ev.Kind, gc.Equals, webhook.ApplicationDeployed)
+	c.Check(ev.Entity, gc.Equals, "wordpress")
+	c.Check(ev.Status, gc.Equals, "waiting")
+
+	// Updates to the application don't raise events.
+	s.send(c, app)
+	s.assertNoRequest(c)
+
+	s.sendDeltas(c, multiwatcher.Delta{Removed: true, Entity: existing})
+	_, ev = s.nextRequest(c)
+	c.Check(ev.Kind, gc.Equals, webhook.ApplicationRemoved)
+	c.Check(ev.Entity, gc.Equals, "mysql")
+}
+
+func (s *WorkerSuite) TestEventsFiltered(c *gc.C) {
+	s.backend.webhooks[modelUUID] = []webhook.Webhook{{
+		Id:     "1",
+		URL:    "https://example.com/hook",
+		Events: []webhook.EventKind{webhook.MachineDown},
+	}}
+	s.start(c, unitInfo(status.Active, status.Idle, ""))
+	s.send(c, unitInfo(status.Error, status.Idle, `hook failed: "install"`))
+	s.assertNoRequest(c)
+
+	s.send(c, &multiwatcher.MachineInfo{
+		ModelUUID:   modelUUID,
+		ID:          "1",
+		AgentStatus: multiwatcher.StatusInfo{Current: status.Down},
+	})
+	_, ev := s.nextRequest(c)
+	c.Check(ev.Kind, gc.Equals, webhook.MachineDown)
+}
+
+func (s *WorkerSuite) TestSignedTemplatePayload(c *gc.C) {
+	s.backend.webhooks[modelUUID] = []webhook.Webhook{{
+		Id:       "1",
+		URL:      "https://example.com/chat",
+		Secret:   "s3cret",
+		Template: `{"text": {{printf "%s is %s" .Entity .Status | json}}}`,
+	}}
+	s.start(c, unitInfo(status.Active, status.Idle, ""))
+	s.send(c, unitInfo(status.Error, status.Idle, ""))
+
+	select {
+	case req := <-s.client.requests:
+		body := s.client.body(req)
+		c.Check(string(body), gc.Equals, `{"text": "mysql/0 is error"}`)
+		c.Check(req.Header.Get(webhook.SignatureHeader), gc.Equals, webhook.Sign("s3cret", body))
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for request")
+	}
+}
+
+func (s *WorkerSuite) TestSlowWebhookDoesNotDelayOthers(c *gc.C) {
+	s.backend.webhooks[modelUUID] = []webhook.Webhook{{
+		Id:  "1",
+		URL: "https://example.com/slow",
+	}, {
+		Id:  "2",
+		URL: "https://example.com/hook",
+	}}
+	unblock := make(chan struct{})
+	defer close(unblock)
+	s.client.blockURL = "https://example.com/slow"
+	s.client.unblock = unblock
+	machine := &multiwatcher.MachineInfo{
+		ModelUUID:   modelUUID,
+		ID:          "0",
+		AgentStatus: multiwatcher.StatusInfo{Current: status.Started},
+	}
+	s.start(c, unitInfo(status.Active, status.Idle, ""), machine)
+
+	s.send(c, unitInfo(status.Error, status.Idle, ""))
+	req, ev := s.nextRequest(c)
+	c.Check(req.URL.String(), gc.Equals, "https://example.com/hook")
+	c.Check(ev.Kind, gc.Equals, webhook.UnitError)
+
+	machine = machine.Clone().(*multiwatcher.MachineInfo)
+	machine.AgentStatus = multiwatcher.StatusInfo{Current: status.Down}
+	s.send(c, machine)
+	req, ev = s.nextRequest(c)
+	c.Check(req.URL.String(), gc.Equals, "https://example.com/hook")
+	c.Check(ev.Kind, gc.Equals, webhook.MachineDown)
+}
+
+func (s *WorkerSuite) TestRetry(c *gc.C) {
+	s.client.statuses = []int{http.StatusInternalServerError, http.StatusServiceUnavailable}
+	s.start(c, unitInfo(status.Active, status.Idle, ""))
+	s.send(c, unitInfo(status.Error, status.Idle, ""))
+
+	s.nextRequest(c)
+	c.Assert(s.clock.WaitAdvance(time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.nextRequest(c)
+	// The delay doubles with each attempt.
+	c.Assert(s.clock.WaitAdvance(2*time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+	_, ev := s.nextRequest(c)
+	c.Check(ev.Kind, gc.Equals, webhook.UnitError)
+	s.assertNoRequest(c)
+}
+
+func (s *WorkerSuite) TestModelWithoutWebhooks(c *gc.C) {
+	s.start(c)
+	s.send(c, &multiwatcher.UnitInfo{
+		ModelUUID:      "other-model",
+		Name:           "mysql/0",
+		WorkloadStatus: multiwatcher.StatusInfo{Current: status.Error},
+	})
+	s.assertNoRequest(c)
+	c.Check(s.backend.modelUUIDs(), jc.DeepEquals, []string{"other-model"})
+}
+
+func (s *WorkerSuite) TestWatcherError(c *gc.C) {
+	w := s.newWorker(c)
+	s.watcher.setErr(errors.New("boom"))
+	err := workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "watching models: boom")
+}
+
+type fakeWatcher struct {
+	mu      sync.Mutex
+	deltas  chan []multiwatcher.Delta
+	stop    chan struct{}
+	stopped bool
+	err     error
+}
+
+func (w *fakeWatcher) setErr(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.err = err
+	close(w.stop)
+	w.stopped = true
+}
+
+func (w *fakeWatcher) Next() ([]multiwatcher.Delta, error) {
+	select {
+	case d := <-w.deltas:
+		return d, nil
+	case <-w.stop:
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		if w.err != nil {
+			return nil, w.err
+		}
+		return nil, errors.New("watcher stopped")
+	}
+}
+
+func (w *fakeWatcher) Stop() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.stopped {
+		close(w.stop)
+		w.stopped = true
+	}
+	return nil
+}
+
+type fakeBackend struct {
+	mu       sync.Mutex
+	webhooks map[string][]webhook.Webhook
+	models   []string
+}
+
+func (b *fakeBackend) ModelWebhooks(modelUUID string) ([]webhook.Webhook, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.models = append(b.models, modelUUID)
+	return b.webhooks[modelUUID], nil
+}
+
+func (b *fakeBackend) modelUUIDs() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.models
+}
+
+// fakeHTTPClient records the requests sent, and responds with the
+// statuses in turn, then with 200 OK. Requests to blockURL don't get a
+// response until unblock is closed.
+type fakeHTTPClient struct {
+	mu       sync.Mutex
+	requests chan *http.Request
+	bodies   map[*http.Request][]byte
+	statuses []int
+	blockURL string
+	unblock  chan struct{}
+}
+
+func (f *fakeHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if f.blockURL != "" && req.URL.String() == f.blockURL {
+		<-f.unblock
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	if f.bodies == nil {
+		f.bodies = make(map[*http.Request][]byte)
+	}
+	f.bodies[req] = body
+	code := http.StatusOK
+	if len(f.statuses) > 0 {
+		code, f.statuses = f.statuses[0], f.statuses[1:]
+	}
+	f.mu.Unlock()
+	f.requests <- req
+	return &http.Response{
+		StatusCode: code,
+		Status:     http.StatusText(code),
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+	}, nil
+}
+
+func (f *fakeHTTPClient) body(req *http.Request) []byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.bodies[req]
+}
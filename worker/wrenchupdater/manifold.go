@@ -0,0 +1,68 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package wrenchupdater
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/featureflag"
+	"github.com/juju/worker/v2"
+	"github.com/juju/worker/v2/dependency"
+
+	apiagent "github.com/juju/juju/api/agent"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/feature"
+	"github.com/juju/juju/wrench"
+)
+
+// ManifoldConfig defines the names of the manifolds on which a
+// Manifold will depend.
+type ManifoldConfig struct {
+	APICallerName string
+	Logger        Logger
+}
+
+// Validate checks the manifold config.
+func (config ManifoldConfig) Validate() error {
+	if config.APICallerName == "" {
+		return errors.NotValidf("empty APICallerName")
+	}
+	if config.Logger == nil {
+		return errors.NotValidf("nil Logger")
+	}
+	return nil
+}
+
+// Manifold returns a dependency manifold that runs a wrench updater
+// worker, using the resource names defined in the supplied config.
+// The worker is uninstalled unless the agent was started with the
+// remote-wrenches developer feature flag, so that wrenches can't be
+// set on production agents through model config.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{
+			config.APICallerName,
+		},
+		Start: func(context dependency.Context) (worker.Worker, error) {
+			if err := config.Validate(); err != nil {
+				return nil, errors.Trace(err)
+			}
+			if !featureflag.Enabled(feature.RemoteWrenches) {
+				return nil, dependency.ErrUninstall
+			}
+			var apiCaller base.APICaller
+			if err := context.Get(config.APICallerName, &apiCaller); err != nil {
+				return nil, err
+			}
+			api, err := apiagent.NewState(apiCaller)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return NewWorker(WorkerConfig{
+				API:       api,
+				Logger:    config.Logger,
+				SetRemote: wrench.SetRemote,
+			})
+		},
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package wrenchupdater_test
+
+import (
+	"github.com/juju/loggo"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v2/dependency"
+	dt "github.com/juju/worker/v2/dependency/testing"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/feature"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/wrenchupdater"
+)
+
+type manifoldSuite struct {
+	coretesting.BaseSuite
+
+	manifold dependency.Manifold
+	context  dependency.Context
+}
+
+var _ = gc.Suite(&manifoldSuite{})
+
+func (s *manifoldSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.manifold = wrenchupdater.Manifold(wrenchupdater.ManifoldConfig{
+		APICallerName: "api-caller",
+		Logger:        loggo.GetLogger("test"),
+	})
+	s.context = dt.StubContext(nil, map[string]interface{}{
+		"api-caller": dependency.ErrMissing,
+	})
+}
+
+func (s *manifoldSuite) TestInputs(c *gc.C) {
+	c.Assert(s.manifold.Inputs, jc.SameContents, []string{"api-caller"})
+}
+
+func (s *manifoldSuite) TestUninstallsWithoutFeatureFlag(c *gc.C) {
+	_, err := s.manifold.Start(s.context)
+	c.Assert(err, gc.Equals, dependency.ErrUninstall)
+}
+
+func (s *manifoldSuite) TestStartsWithFeatureFlag(c *gc.C) {
+	s.SetFeatureFlags(feature.RemoteWrenches)
+	_, err := s.manifold.Start(s.context)
+	c.Assert(err, gc.Equals, dependency.ErrMissing)
+}
@@ -0,0 +1,106 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package wrenchupdater provides a worker that keeps the agent's
+// wrenches in line with the model's "wrenches" config, so that faults
+// can be injected on every agent in a model without writing wrench
+// files on each machine.
+package wrenchupdater
+
+import (
+	"reflect"
+
+	"github.com/juju/errors"
+	"github.com/juju/worker/v2"
+
+	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/wrench"
+)
+
+// Logger represents the logging methods used by the worker.
+type Logger interface {
+	Infof(string, ...interface{})
+	Debugf(string, ...interface{})
+}
+
+// ModelConfigAPI represents the API calls the worker makes.
+type ModelConfigAPI interface {
+	ModelConfig() (*config.Config, error)
+	WatchForModelConfigChanges() (watcher.NotifyWatcher, error)
+}
+
+// WorkerConfig contains the information required for the worker to
+// operate.
+type WorkerConfig struct {
+	API       ModelConfigAPI
+	Logger    Logger
+	SetRemote func([]wrench.Wrench)
+}
+
+// Validate ensures all the necessary fields have values.
+func (c WorkerConfig) Validate() error {
+	if c.API == nil {
+		return errors.NotValidf("nil API")
+	}
+	if c.Logger == nil {
+		return errors.NotValidf("nil Logger")
+	}
+	if c.SetRemote == nil {
+		return errors.NotValidf("nil SetRemote")
+	}
+	return nil
+}
+
+type updater struct {
+	config  WorkerConfig
+	current []wrench.Wrench
+}
+
+// NewWorker returns a worker that passes the wrenches in the model
+// config to SetRemote whenever they change. The wrenches are left in
+// place when the worker stops, so that faults which break the API
+// connection don't also remove the wrench that caused them; wrenches
+// set with an expiry time stop being active regardless.
+func NewWorker(config WorkerConfig) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w, err := watcher.NewNotifyWorker(watcher.NotifyConfig{
+		Handler: &updater{config: config},
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+// SetUp is part of the watcher.NotifyHandler interface.
+func (u *updater) SetUp() (watcher.NotifyWatcher, error) {
+	return u.config.API.WatchForModelConfigChanges()
+}
+
+// Handle is part of the watcher.NotifyHandler interface.
+func (u *updater) Handle(_ <-chan struct{}) error {
+	cfg, err := u.config.API.ModelConfig()
+	if err != nil {
+		return errors.Annotate(err, "getting model config")
+	}
+	wrenches := cfg.Wrenches()
+	if reflect.DeepEqual(wrenches, u.current) {
+		return nil
+	}
+	if len(wrenches) == 0 {
+		u.config.Logger.Infof("clearing model config wrenches")
+	} else {
+		u.config.Logger.Infof("setting model config wrenches: %v", wrenches)
+	}
+	u.config.SetRemote(wrenches)
+	u.current = wrenches
+	return nil
+}
+
+// TearDown is part of the watcher.NotifyHandler interface.
+func (u *updater) TearDown() error {
+	return nil
+}
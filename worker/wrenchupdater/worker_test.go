@@ -0,0 +1,129 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package wrenchupdater_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/worker/v2/workertest"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/core/watcher/watchertest"
+	"github.com/juju/juju/environs/config"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/wrenchupdater"
+	"github.com/juju/juju/wrench"
+)
+
+type workerSuite struct {
+	testing.IsolationSuite
+
+	api    *mockAPI
+	set    chan []wrench.Wrench
+	config wrenchupdater.WorkerConfig
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+func (s *workerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.api = &mockAPI{
+		changes: make(chan struct{}, 1),
+		config:  coretesting.ModelConfig(c),
+	}
+	s.set = make(chan []wrench.Wrench, 10)
+	s.config = wrenchupdater.WorkerConfig{
+		API:    s.api,
+		Logger: loggo.GetLogger("test"),
+		SetRemote: func(wrenches []wrench.Wrench) {
+			s.set <- wrenches
+		},
+	}
+}
+
+func (s *workerSuite) TestValidate(c *gc.C) {
+	s.config.API = nil
+	_, err := wrenchupdater.NewWorker(s.config)
+	c.Assert(err, gc.ErrorMatches, "nil API not valid")
+}
+
+func (s *workerSuite) TestSetsWrenchesOnChange(c *gc.C) {
+	s.api.setConfig(c, "provisioner/stop-instances, hooks/install-error@2021-06-01T12:00:00Z")
+	w, err := wrenchupdater.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.api.changes <- struct{}{}
+	c.Assert(s.waitForSet(c), jc.DeepEquals, []wrench.Wrench{{
+		Category: "provisioner",
+		Feature:  "stop-instances",
+	}, {
+		Category: "hooks",
+		Feature:  "install-error",
+		Expires:  time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC),
+	}})
+
+	// Unrelated config changes don't reset the wrenches.
+	s.api.changes <- struct{}{}
+	s.api.setConfig(c, "")
+	s.api.changes <- struct{}{}
+	c.Assert(s.waitForSet(c), gc.HasLen, 0)
+	select {
+	case wrenches := <-s.set:
+		c.Fatalf("unexpected wrenches set: %v", wrenches)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *workerSuite) TestModelConfigError(c *gc.C) {
+	s.api.err = errors.New("boom")
+	w, err := wrenchupdater.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, w)
+
+	s.api.changes <- struct{}{}
+	err = workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "getting model config: boom")
+}
+
+func (s *workerSuite) waitForSet(c *gc.C) []wrench.Wrench {
+	select {
+	case wrenches := <-s.set:
+		return wrenches
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for wrenches to be set")
+	}
+	return nil
+}
+
+type mockAPI struct {
+	mu      sync.Mutex
+	changes chan struct{}
+	config  *config.Config
+	err     error
+}
+
+func (m *mockAPI) setConfig(c *gc.C, wrenches string) {
+	cfg, err := m.config.Apply(map[string]interface{}{"wrenches": wrenches})
+	c.Assert(err, jc.ErrorIsNil)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config = cfg
+}
+
+func (m *mockAPI) ModelConfig() (*config.Config, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.config, m.err
+}
+
+func (m *mockAPI) WatchForModelConfigChanges() (watcher.NotifyWatcher, error) {
+	return watchertest.NewMockNotifyWatcher(m.changes), nil
+}
@@ -6,4 +6,5 @@ package wrench
 var (
 	WrenchDir = &wrenchDir
 	Stat      = &stat
+	Now       = &now
 )
@@ -0,0 +1,160 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package wrench
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+const (
+	// SourceFile identifies wrenches read from wrench files.
+	SourceFile = "file"
+
+	// SourceModelConfig identifies wrenches set in the model's
+	// "wrenches" config and distributed to the agent through the API.
+	SourceModelConfig = "model-config"
+)
+
+var (
+	remoteMu sync.Mutex
+	remote   []Wrench
+
+	now = time.Now // To support patching
+)
+
+// Wrench identifies a feature that is active for a category, in the
+// same way as a line in a wrench file.
+type Wrench struct {
+	Category string
+	Feature  string
+
+	// Expires is the time the wrench stops being active. If it is
+	// zero the wrench doesn't expire.
+	Expires time.Time
+}
+
+// String returns the wrench in the form accepted by Parse.
+func (w Wrench) String() string {
+	s := w.Category + "/" + w.Feature
+	if !w.Expires.IsZero() {
+		s += "@" + w.Expires.UTC().Format(time.RFC3339)
+	}
+	return s
+}
+
+func (w Wrench) expired(t time.Time) bool {
+	return !w.Expires.IsZero() && !t.Before(w.Expires)
+}
+
+// Parse parses a list of wrenches separated by commas or whitespace.
+// Each wrench is written as "category/feature", optionally followed by
+// "@" and the RFC3339 time it expires, for example:
+//
+//   hooks/mysql-install-error@2021-06-01T12:00:00Z, provisioner/stop-instances
+func Parse(value string) ([]Wrench, error) {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+	var wrenches []Wrench
+	for _, field := range fields {
+		spec, expires := field, ""
+		if i := strings.Index(field, "@"); i >= 0 {
+			spec, expires = field[:i], field[i+1:]
+		}
+		parts := strings.SplitN(spec, "/", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.NotValidf("wrench %q, expected category/feature", field)
+		}
+		w := Wrench{Category: parts[0], Feature: parts[1]}
+		if expires != "" {
+			t, err := time.Parse(time.RFC3339, expires)
+			if err != nil {
+				return nil, errors.NotValidf("wrench %q expiry time", field)
+			}
+			w.Expires = t
+		}
+		wrenches = append(wrenches, w)
+	}
+	return wrenches, nil
+}
+
+// SetRemote replaces the wrenches distributed to the agent through the
+// API. They are active, alongside any in wrench files, until they
+// expire or are replaced.
+func SetRemote(wrenches []Wrench) {
+	remoteMu.Lock()
+	defer remoteMu.Unlock()
+	remote = append([]Wrench(nil), wrenches...)
+}
+
+func isRemoteActive(category, feature string) bool {
+	remoteMu.Lock()
+	defer remoteMu.Unlock()
+	t := now()
+	for _, w := range remote {
+		if w.Category == category && w.Feature == feature && !w.expired(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveWrench is a wrench that is currently active, and where it was
+// set.
+type ActiveWrench struct {
+	Wrench
+	Source string
+}
+
+// Active returns all of the wrenches currently active, sorted by
+// category and feature. It returns nothing if the wrench feature is
+// turned off.
+func Active() []ActiveWrench {
+	if !IsEnabled() {
+		return nil
+	}
+	var active []ActiveWrench
+	remoteMu.Lock()
+	t := now()
+	for _, w := range remote {
+		if !w.expired(t) {
+			active = append(active, ActiveWrench{Wrench: w, Source: SourceModelConfig})
+		}
+	}
+	remoteMu.Unlock()
+
+	if checkWrenchDir(wrenchDir) {
+		infos, err := ioutil.ReadDir(wrenchDir)
+		if err != nil {
+			logger.Errorf("unable to list wrench files (ignored): %v", err)
+		}
+		for _, info := range infos {
+			if info.IsDir() {
+				continue
+			}
+			category := info.Name()
+			for _, feature := range fileFeatures(category, filepath.Join(wrenchDir, category)) {
+				active = append(active, ActiveWrench{
+					Wrench: Wrench{Category: category, Feature: feature},
+					Source: SourceFile,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(active, func(i, j int) bool {
+		if active[i].Category != active[j].Category {
+			return active[i].Category < active[j].Category
+		}
+		return active[i].Feature < active[j].Feature
+	})
+	return active
+}
@@ -0,0 +1,133 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package wrench_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/wrench"
+)
+
+type remoteSuite struct {
+	coretesting.BaseSuite
+	now time.Time
+}
+
+var _ = gc.Suite(&remoteSuite{})
+
+func (s *remoteSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	wrench.SetEnabled(true)
+	s.PatchValue(wrench.WrenchDir, c.MkDir())
+	s.now = time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.PatchValue(wrench.Now, func() time.Time { return s.now })
+	s.AddCleanup(func(*gc.C) {
+		wrench.SetRemote(nil)
+		wrench.SetEnabled(false)
+	})
+}
+
+func (s *remoteSuite) TestParse(c *gc.C) {
+	wrenches, err := wrench.Parse("hooks/mysql-install-error@2021-06-01T13:00:00Z, provisioner/stop-instances\n\tfoo/bar")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(wrenches, jc.DeepEquals, []wrench.Wrench{{
+		Category: "hooks",
+		Feature:  "mysql-install-error",
+		Expires:  time.Date(2021, 6, 1, 13, 0, 0, 0, time.UTC),
+	}, {
+		Category: "provisioner",
+		Feature:  "stop-instances",
+	}, {
+		Category: "foo",
+		Feature:  "bar",
+	}})
+	c.Assert(wrenches[0].String(), gc.Equals, "hooks/mysql-install-error@2021-06-01T13:00:00Z")
+	c.Assert(wrenches[1].String(), gc.Equals, "provisioner/stop-instances")
+}
+
+func (s *remoteSuite) TestParseEmpty(c *gc.C) {
+	wrenches, err := wrench.Parse(" , ")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(wrenches, gc.HasLen, 0)
+}
+
+func (s *remoteSuite) TestParseErrors(c *gc.C) {
+	for _, test := range []struct {
+		value  string
+		expect string
+	}{{
+		value:  "foo",
+		expect: `wrench "foo", expected category/feature not valid`,
+	}, {
+		value:  "foo/",
+		expect: `wrench "foo/", expected category/feature not valid`,
+	}, {
+		value:  "foo/bar@tomorrow",
+		expect: `wrench "foo/bar@tomorrow" expiry time not valid`,
+	}} {
+		c.Logf("parsing %q", test.value)
+		_, err := wrench.Parse(test.value)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
+
+func (s *remoteSuite) TestIsActive(c *gc.C) {
+	wrench.SetRemote([]wrench.Wrench{{Category: "foo", Feature: "bar"}})
+	c.Assert(wrench.IsActive("foo", "bar"), jc.IsTrue)
+	c.Assert(wrench.IsActive("foo", "baz"), jc.IsFalse)
+
+	wrench.SetRemote(nil)
+	c.Assert(wrench.IsActive("foo", "bar"), jc.IsFalse)
+}
+
+func (s *remoteSuite) TestExpiry(c *gc.C) {
+	wrench.SetRemote([]wrench.Wrench{{
+		Category: "foo",
+		Feature:  "bar",
+		Expires:  s.now.Add(time.Minute),
+	}})
+	c.Assert(wrench.IsActive("foo", "bar"), jc.IsTrue)
+
+	s.now = s.now.Add(time.Minute)
+	c.Assert(wrench.IsActive("foo", "bar"), jc.IsFalse)
+	c.Assert(wrench.Active(), gc.HasLen, 0)
+}
+
+func (s *remoteSuite) TestDisabled(c *gc.C) {
+	wrench.SetRemote([]wrench.Wrench{{Category: "foo", Feature: "bar"}})
+	wrench.SetEnabled(false)
+	c.Assert(wrench.IsActive("foo", "bar"), jc.IsFalse)
+	c.Assert(wrench.Active(), gc.HasLen, 0)
+}
+
+func (s *remoteSuite) TestActive(c *gc.C) {
+	dir := c.MkDir()
+	s.PatchValue(wrench.WrenchDir, dir)
+	err := ioutil.WriteFile(filepath.Join(dir, "provisioner"), []byte("stop-instances\n\n"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	expires := s.now.Add(time.Hour)
+	wrench.SetRemote([]wrench.Wrench{{
+		Category: "hooks",
+		Feature:  "mysql-install-error",
+		Expires:  expires,
+	}, {
+		Category: "old",
+		Feature:  "gone",
+		Expires:  s.now,
+	}})
+
+	c.Assert(wrench.Active(), jc.DeepEquals, []wrench.ActiveWrench{{
+		Wrench: wrench.Wrench{Category: "hooks", Feature: "mysql-install-error", Expires: expires},
+		Source: "model-config",
+	}, {
+		Wrench: wrench.Wrench{Category: "provisioner", Feature: "stop-instances"},
+		Source: "file",
+	}})
+}
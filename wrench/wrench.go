@@ -5,6 +5,7 @@ package wrench
 
 import (
 	"bufio"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -45,12 +46,20 @@ var logger = loggo.GetLogger("juju.wrench")
 //   refuse-upgrade
 //   fail-api-server-start
 //
+// Wrenches may also be set in the model's "wrenches" config, which
+// agents started with the remote-wrenches developer feature flag pass
+// to SetRemote. These are active until they expire.
+//
 // The caller need not worry about errors. Any errors that occur will
 // be logged and false will be returned.
 func IsActive(category, feature string) bool {
 	if !IsEnabled() {
 		return false
 	}
+	if isRemoteActive(category, feature) {
+		logger.Tracef("wrench for %s/%s is active", category, feature)
+		return true
+	}
 	if !checkWrenchDir(wrenchDir) {
 		return false
 	}
@@ -81,6 +90,26 @@ func IsActive(category, feature string) bool {
 	return false
 }
 
+// fileFeatures returns the features listed in the wrench file for the
+// category.
+func fileFeatures(category, fileName string) []string {
+	if !checkWrenchFile(category, "*", fileName) {
+		return nil
+	}
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		logger.Errorf("unable to read wrench data for %s (ignored): %v", category, err)
+		return nil
+	}
+	var features []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			features = append(features, line)
+		}
+	}
+	return features
+}
+
 // SetEnabled turns the wrench feature on or off globally.
 //
 // If false is given, all future IsActive calls will unconditionally